@@ -1,6 +1,10 @@
 package v1
 
-import "github.com/google/uuid"
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 const (
 	// AgentKind is the kind of the agent resource.
@@ -10,6 +14,11 @@ const (
 // Agent represents an agent which is defined OpAMP protocol.
 // It follows the Kubernetes-style resource structure with Metadata, Spec, and Status.
 type Agent struct {
+	// Kind is the type of the resource.
+	Kind string `json:"kind"`
+	// APIVersion is the version of the API.
+	APIVersion string `json:"apiVersion"`
+
 	// Metadata contains identifying information about the agent.
 	Metadata AgentMetadata `json:"metadata"`
 
@@ -42,6 +51,10 @@ type AgentMetadata struct {
 
 	// CustomCapabilities is a map of custom capabilities for the agent.
 	CustomCapabilities AgentCustomCapabilities `json:"customCapabilities,omitzero"`
+
+	// Labels are server-owned labels derived by agent enrichment, e.g. the agent's region
+	// or inventory owner. They are read-only through the API.
+	Labels map[string]string `json:"labels,omitempty"`
 } // @name AgentMetadata
 
 // AgentSpec contains the desired configuration for the agent.
@@ -61,8 +74,123 @@ type AgentSpec struct {
 	// RestartRequiredAt is the time when a restart was requested.
 	// If this time is after the agent's start time, the agent should be restarted.
 	RestartRequiredAt *Time `json:"restartRequiredAt,omitempty"`
+
+	// Quarantine is set while the server has stopped pushing new config to the agent.
+	// It is read-only here; use the quarantine endpoints to change it.
+	Quarantine *AgentQuarantine `json:"quarantine,omitempty"`
+
+	// ExpectedAttributes are the attributes the agent must keep reporting, with their values.
+	// The AttributeDrift condition is True while the agent does not report them. It is
+	// read-only here; use the expected attributes endpoint to change it.
+	ExpectedAttributes map[string]string `json:"expectedAttributes,omitempty"`
+
+	// PendingReports are the parts of its state the agent was asked to report again and
+	// has not reported yet. It is read-only here; use the report endpoints to add to it.
+	PendingReports []AgentReportKind `json:"pendingReports,omitempty"`
 } // @name AgentSpec
 
+// AgentReportKind is a part of the agent's state the server can ask the agent to report again.
+type AgentReportKind string // @name AgentReportKind
+
+const (
+	// AgentReportKindEffectiveConfig asks the agent to report its effective config.
+	AgentReportKindEffectiveConfig AgentReportKind = "EffectiveConfig"
+	// AgentReportKindHealth asks the agent to report its component health.
+	AgentReportKindHealth AgentReportKind = "Health"
+	// AgentReportKindAvailableComponents asks the agent to report its available components.
+	AgentReportKindAvailableComponents AgentReportKind = "AvailableComponents"
+)
+
+// AgentQuarantine describes why and since when an agent is quarantined.
+type AgentQuarantine struct {
+	// Automatic is true when the server quarantined the agent because it stayed
+	// unhealthy; such a quarantine is lifted once the agent reports healthy again.
+	Automatic bool `json:"automatic"`
+	// Reason explains why the agent was quarantined.
+	Reason string `json:"reason,omitempty"`
+	// QuarantinedBy is the user or system that quarantined the agent.
+	QuarantinedBy string `json:"quarantinedBy,omitempty"`
+	// QuarantinedAt is when the agent was quarantined.
+	QuarantinedAt Time `json:"quarantinedAt"`
+} // @name AgentQuarantine
+
+// AgentQuarantineRequest is the optional body of a manual quarantine request.
+type AgentQuarantineRequest struct {
+	// Reason explains why the agent is being quarantined.
+	Reason string `json:"reason,omitempty"`
+} // @name AgentQuarantineRequest
+
+// AgentExpectedAttributesRequest is the body of a request that replaces the attributes
+// an agent is expected to report.
+type AgentExpectedAttributesRequest struct {
+	// Attributes are the expected attributes with their values. Empty removes every expectation.
+	Attributes map[string]string `json:"attributes"`
+} // @name AgentExpectedAttributesRequest
+
+// AgentRevocation is a revoked agent instance UID. The server refuses and closes
+// connections from it until the revocation is removed.
+type AgentRevocation struct {
+	// InstanceUID is the revoked agent instance UID.
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// Reason explains why the agent was revoked.
+	Reason string `json:"reason,omitempty"`
+	// RevokedBy is the user who revoked the agent.
+	RevokedBy string `json:"revokedBy,omitempty"`
+	// RevokedAt is when the agent was revoked.
+	RevokedAt Time `json:"revokedAt"`
+} // @name AgentRevocation
+
+// AgentRevocationRequest is the optional body of a revoke request.
+type AgentRevocationRequest struct {
+	// Reason explains why the agent is being revoked.
+	Reason string `json:"reason,omitempty"`
+} // @name AgentRevocationRequest
+
+// AgentUptime reports an agent's connection statistics over time.
+type AgentUptime struct {
+	// InstanceUID is the agent the statistics belong to.
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// Connected reports whether the agent is currently connected.
+	Connected bool `json:"connected"`
+	// ConnectedSince is when the current connection started; omitted while disconnected.
+	ConnectedSince Time `json:"connectedSince,omitzero"`
+	// LastDisconnectedAt is when the agent last disconnected.
+	LastDisconnectedAt Time `json:"lastDisconnectedAt,omitzero"`
+	// TotalConnectedSeconds is the connected time since the server started tracking
+	// the agent, including the current connection.
+	TotalConnectedSeconds float64 `json:"totalConnectedSeconds"`
+	// DisconnectCount is the number of disconnects since tracking started.
+	DisconnectCount int64 `json:"disconnectCount"`
+	// WindowSeconds is the trailing period the window fields cover: the last 24
+	// hours, or less when tracking started more recently.
+	WindowSeconds float64 `json:"windowSeconds"`
+	// WindowConnectedSeconds is the connected time within the window.
+	WindowConnectedSeconds float64 `json:"windowConnectedSeconds"`
+	// WindowUptimePercentage is WindowConnectedSeconds / WindowSeconds as 0-100.
+	WindowUptimePercentage float64 `json:"windowUptimePercentage"`
+} // @name AgentUptime
+
+// AgentEffectiveConfigHistory lists the distinct effective configs an agent reported.
+type AgentEffectiveConfigHistory struct {
+	// InstanceUID is the agent the history belongs to.
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// Items are the snapshots, oldest first.
+	Items []AgentEffectiveConfigSnapshot `json:"items"`
+} // @name AgentEffectiveConfigHistory
+
+// AgentEffectiveConfigSnapshot is one effective config in an agent's history.
+type AgentEffectiveConfigSnapshot struct {
+	// ReportedAt is when the agent first reported this config.
+	ReportedAt Time `json:"reportedAt"`
+	// Hash is the hex-encoded SHA-256 of the config content.
+	Hash string `json:"hash"`
+	// Truncated is true when the config exceeded the server's history size limit and
+	// only its file names and content types were kept.
+	Truncated bool `json:"truncated,omitempty"`
+	// EffectiveConfig is the reported config.
+	EffectiveConfig AgentEffectiveConfig `json:"effectiveConfig"`
+} // @name AgentEffectiveConfigSnapshot
+
 // AgentSpecRemoteConfig represents the remote config specification for an agent.
 type AgentSpecRemoteConfig struct {
 	// RemoteConfigNames is a list of remote config names applied to this agent.
@@ -74,6 +202,9 @@ type AgentStatus struct {
 	// EffectiveConfig is the effective configuration of the agent.
 	EffectiveConfig AgentEffectiveConfig `json:"effectiveConfig,omitzero"`
 
+	// EffectiveConfigReportedAt is the timestamp when the agent last reported its effective config.
+	EffectiveConfigReportedAt string `json:"effectiveConfigReportedAt,omitempty"`
+
 	// PackageStatuses is a map of package statuses for the agent.
 	PackageStatuses AgentPackageStatuses `json:"packageStatuses,omitzero"`
 
@@ -86,48 +217,76 @@ type AgentStatus struct {
 	// Conditions is a list of conditions that apply to the agent.
 	Conditions []Condition `json:"conditions,omitempty"`
 
+	// ConfigInSync indicates the agent reports having applied the remote config the server
+	// offers it, by its hash. It is true when no remote config is offered.
+	ConfigInSync bool `json:"configInSync"`
+
 	// Connected indicates if the agent is currently connected.
 	Connected bool `json:"connected"`
 
-	// ConnectionType indicates the type of connection the agent is using.
+	// ConnectionState is one of "Connected", "Grace" or "Disconnected". Grace means the
+	// agent's connection was lost but the disconnect grace period has not elapsed yet;
+	// the agent still counts as connected meanwhile.
+	ConnectionState string `json:"connectionState,omitempty"`
+
+	// ConnectionLostAt is when the agent's connection was lost, set during the grace period.
+	ConnectionLostAt string `json:"connectionLostAt,omitempty"`
+
+	// ConnectionType indicates the type of connection the agent is using, one of the
+	// ConnectionType* names.
 	ConnectionType string `json:"connectionType,omitempty"`
 
 	// SequenceNum is the sequence number from the last AgentToServer message.
 	SequenceNum uint64 `json:"sequenceNum,omitempty"`
 
+	// FirstSeenAt is the timestamp when the agent first contacted a server.
+	FirstSeenAt string `json:"firstSeenAt,omitempty"`
+
 	// LastReportedAt is the timestamp when the agent last reported its status.
 	LastReportedAt string `json:"lastReportedAt,omitempty"`
 } // @name AgentStatus
 
+// Connection types reported in AgentStatus.ConnectionType.
+const (
+	// ConnectionTypeHTTP is an agent polling the server over plain HTTP.
+	ConnectionTypeHTTP = "HTTP"
+	// ConnectionTypeWebSocket is an agent holding a WebSocket connection to the server.
+	ConnectionTypeWebSocket = "WebSocket"
+	// ConnectionTypeUnknown is an agent whose connection type is not known, e.g. because
+	// it is not connected.
+	ConnectionTypeUnknown = "Unknown"
+)
+
 // AgentCapabilities is a bitmask representing the capabilities of the agent.
 type AgentCapabilities uint64
 
+// agentCapabilityNames maps each OpAMP agent capability bit to its flag name.
+var agentCapabilityNames = [...]struct {
+	bit  AgentCapabilities
+	name string
+}{
+	{1, "ReportsStatus"},
+	{2, "AcceptsRemoteConfig"},
+	{4, "ReportsEffectiveConfig"},
+	{8, "AcceptsPackages"},
+	{16, "ReportsPackageStatuses"},
+	{32, "ReportsOwnTraces"},
+	{64, "ReportsOwnMetrics"},
+	{128, "ReportsOwnLogs"},
+	{256, "AcceptsOpAMPConnectionSettings"},
+	{512, "AcceptsOtherConnectionSettings"},
+	{1024, "AcceptsRestartCommand"},
+	{2048, "ReportsHealth"},
+	{4096, "ReportsRemoteConfig"},
+	{8192, "ReportsHeartbeat"},
+	{16384, "ReportsAvailableComponents"},
+}
+
 // Names returns the list of capability flag names set in this bitmask.
 func (c AgentCapabilities) Names() []string {
-	table := [...]struct {
-		bit  AgentCapabilities
-		name string
-	}{
-		{1, "ReportsStatus"},
-		{2, "AcceptsRemoteConfig"},
-		{4, "ReportsEffectiveConfig"},
-		{8, "AcceptsPackages"},
-		{16, "ReportsPackageStatuses"},
-		{32, "ReportsOwnTraces"},
-		{64, "ReportsOwnMetrics"},
-		{128, "ReportsOwnLogs"},
-		{256, "AcceptsOpAMPConnectionSettings"},
-		{512, "AcceptsOtherConnectionSettings"},
-		{1024, "AcceptsRestartCommand"},
-		{2048, "ReportsHealth"},
-		{4096, "ReportsRemoteConfig"},
-		{8192, "ReportsHeartbeat"},
-		{16384, "ReportsAvailableComponents"},
-	}
-
 	var names []string
 
-	for _, entry := range table {
+	for _, entry := range agentCapabilityNames {
 		if c&entry.bit != 0 {
 			names = append(names, entry.name)
 		}
@@ -136,12 +295,33 @@ func (c AgentCapabilities) Names() []string {
 	return names
 }
 
+// ParseAgentCapability returns the bit of the capability flag with the given name,
+// ignoring case. It reports false for a name that is not a known flag.
+func ParseAgentCapability(name string) (AgentCapabilities, bool) {
+	for _, entry := range agentCapabilityNames {
+		if strings.EqualFold(entry.name, name) {
+			return entry.bit, true
+		}
+	}
+
+	return 0, false
+}
+
 // AgentDescription represents the description of the agent.
 type AgentDescription struct {
 	// IdentifyingAttributes are attributes that uniquely identify the agent.
 	IdentifyingAttributes map[string]string `json:"identifyingAttributes,omitempty"`
 	// NonIdentifyingAttributes are attributes that do not uniquely identify the agent.
 	NonIdentifyingAttributes map[string]string `json:"nonIdentifyingAttributes,omitempty"`
+	// RawIdentifyingAttributes are the identifying attributes as the agent reported them,
+	// present only when the server normalized aliased keys.
+	RawIdentifyingAttributes map[string]string `json:"rawIdentifyingAttributes,omitempty"`
+	// RawNonIdentifyingAttributes are the non-identifying attributes as the agent reported
+	// them, present only when the server normalized aliased keys.
+	RawNonIdentifyingAttributes map[string]string `json:"rawNonIdentifyingAttributes,omitempty"`
+	// AttributesTruncated is true when the agent reported more attributes than the
+	// server accepts and the excess was dropped.
+	AttributesTruncated bool `json:"attributesTruncated,omitempty"`
 } // @name AgentDescription
 
 // AgentEffectiveConfig represents the effective configuration of the agent.
@@ -163,6 +343,9 @@ type AgentConfigMap struct {
 type AgentConfigFile struct {
 	Body        string `json:"body"`
 	ContentType string `json:"contentType"`
+	// Primary marks the main file of an effective config with several files. At most one
+	// file is marked; none is when the main file cannot be told apart from the rest.
+	Primary bool `json:"primary,omitempty"`
 } // @name AgentConfigFile
 
 // AgentPackageStatuses represents the package statuses of the agent.
@@ -195,6 +378,9 @@ type AgentCustomCapabilities struct {
 // AgentAvailableComponents represents the available components of the agent.
 type AgentAvailableComponents struct {
 	Components map[string]AgentComponentDetails `json:"components,omitempty"`
+	// Truncated is true when the agent reported a larger components tree than the server
+	// stores and some components were dropped.
+	Truncated bool `json:"truncated,omitempty"`
 } // @name AgentAvailableComponents
 
 // AgentComponentDetails represents details of an available component.
@@ -0,0 +1,20 @@
+package v1
+
+// AgentAttributeCatalog lists the attribute keys agents reported across all namespaces,
+// e.g. to suggest keys and values while writing an agent group selector.
+type AgentAttributeCatalog struct {
+	// IdentifyingAttributes are the distinct identifying attribute keys, sorted.
+	IdentifyingAttributes []AgentAttribute `json:"identifyingAttributes"`
+	// NonIdentifyingAttributes are the distinct non-identifying attribute keys, sorted.
+	NonIdentifyingAttributes []AgentAttribute `json:"nonIdentifyingAttributes"`
+} // @name AgentAttributeCatalog
+
+// AgentAttribute is one attribute key of the catalog.
+type AgentAttribute struct {
+	Key string `json:"key"`
+	// Values are the distinct values reported for the key, sorted. They are only listed
+	// when requested, and at most as many as requested.
+	Values []string `json:"values,omitempty"`
+	// ValuesTruncated is set when more values were reported than are listed.
+	ValuesTruncated bool `json:"valuesTruncated,omitempty"`
+} // @name AgentAttribute
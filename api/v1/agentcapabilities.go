@@ -0,0 +1,22 @@
+package v1
+
+import "github.com/google/uuid"
+
+const (
+	// AgentCapabilitySummaryKind is the kind for agent capability summaries.
+	AgentCapabilitySummaryKind = "AgentCapabilitySummary"
+)
+
+// AgentCapabilitySummary is one row of the capability matrix: the OpAMP capabilities a
+// connected agent reported, decoded into flag names.
+type AgentCapabilitySummary struct {
+	// Namespace and InstanceUID identify the agent.
+	Namespace   string    `json:"namespace"`
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// Type is the agent type, as in AgentMetadata.Type.
+	Type string `json:"type,omitempty"`
+	// Capabilities is the raw capability bitmask the agent reported.
+	Capabilities AgentCapabilities `json:"capabilities"`
+	// CapabilityNames lists the flag names set in Capabilities, e.g. "AcceptsPackages".
+	CapabilityNames []string `json:"capabilityNames"`
+} // @name AgentCapabilitySummary
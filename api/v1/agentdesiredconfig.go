@@ -0,0 +1,46 @@
+package v1
+
+import "github.com/google/uuid"
+
+const (
+	// AgentDesiredConfigKind is the kind for an agent's desired config.
+	AgentDesiredConfigKind = "AgentDesiredConfig"
+)
+
+// AgentDesiredConfigSourceKind tells where an entry of an agent's desired config comes from.
+type AgentDesiredConfigSourceKind string // @name AgentDesiredConfigSourceKind
+
+const (
+	// AgentDesiredConfigSourceAgentGroup marks an entry contributed by a matching agent group.
+	AgentDesiredConfigSourceAgentGroup AgentDesiredConfigSourceKind = "AgentGroup"
+	// AgentDesiredConfigSourceAgent marks an entry set on the agent directly, or left over
+	// from an agent group that no longer matches.
+	AgentDesiredConfigSourceAgent AgentDesiredConfigSourceKind = "Agent"
+)
+
+// AgentDesiredConfig is the remote config the server intends to offer an agent, merged
+// from every source, with the provenance of each entry.
+type AgentDesiredConfig struct {
+	// Namespace and InstanceUID identify the agent.
+	Namespace   string    `json:"namespace"`
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// ConfigMap holds the desired config entries keyed by config name.
+	ConfigMap map[string]AgentDesiredConfigEntry `json:"configMap"`
+} // @name AgentDesiredConfig
+
+// AgentDesiredConfigEntry is one entry of an agent's desired config.
+type AgentDesiredConfigEntry struct {
+	Body        string `json:"body"`
+	ContentType string `json:"contentType"`
+	// Source is where the entry comes from.
+	Source AgentDesiredConfigSource `json:"source"`
+} // @name AgentDesiredConfigEntry
+
+// AgentDesiredConfigSource is the provenance of a desired config entry.
+type AgentDesiredConfigSource struct {
+	Kind AgentDesiredConfigSourceKind `json:"kind"`
+	// Namespace and Name identify the contributing agent group when Kind is AgentGroup.
+	// When several matching groups declare the entry, it is the one whose config wins.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+} // @name AgentDesiredConfigSource
@@ -1,5 +1,7 @@
 package v1
 
+import "github.com/google/uuid"
+
 const (
 	// AgentGroupKind is the kind of the agent group resource.
 	AgentGroupKind = "AgentGroup"
@@ -21,6 +23,7 @@ type Metadata struct {
 	Attributes Attributes `json:"attributes"`
 	CreatedAt  Time       `json:"createdAt"`
 	DeletedAt  *Time      `json:"deletedAt,omitempty"`
+	DeletedBy  string     `json:"deletedBy,omitempty"`
 } // @name AgentGroupMetadata
 
 // Spec represents the specification of an agent group.
@@ -28,6 +31,12 @@ type Spec struct {
 	Priority    int           `json:"priority"`
 	Selector    AgentSelector `json:"selector"`
 	AgentConfig *AgentConfig  `json:"agentConfig,omitempty"`
+	// Rollout limits the group's remote configs to a subset of its agents while a change
+	// is canaried. Without it every agent of the group receives them.
+	Rollout *AgentGroupRollout `json:"rollout,omitempty"`
+	// ExcludeBaseRemoteConfig keeps the server's base remote configs off the group's agents.
+	// An agent receives them only when none of its matching groups excludes them.
+	ExcludeBaseRemoteConfig bool `json:"excludeBaseRemoteConfig,omitempty"`
 } // @name AgentGroupSpec
 
 // Status represents the status of an agent group.
@@ -56,12 +65,43 @@ type Status struct {
 type Attributes map[string]string
 
 // AgentSelector defines the criteria for selecting agents to be included in the agent group.
+// Listed attributes must be present with the given value; keys in the Absent lists must
+// not be reported by the agent at all. Values match exactly unless CaseInsensitive is set,
+// in which case case and surrounding whitespace are ignored. Match expressions compare
+// attribute values beyond equality, e.g. numerically.
 // @name AgentGroupAgentSelector.
 type AgentSelector struct {
 	IdentifyingAttributes    map[string]string `json:"identifyingAttributes"`
 	NonIdentifyingAttributes map[string]string `json:"nonIdentifyingAttributes"`
+
+	AbsentIdentifyingAttributes    []string `json:"absentIdentifyingAttributes,omitempty"`
+	AbsentNonIdentifyingAttributes []string `json:"absentNonIdentifyingAttributes,omitempty"`
+
+	IdentifyingMatchExpressions    []AttributeMatchExpression `json:"identifyingMatchExpressions,omitempty"`
+	NonIdentifyingMatchExpressions []AttributeMatchExpression `json:"nonIdentifyingMatchExpressions,omitempty"`
+
+	CaseInsensitive bool `json:"caseInsensitive,omitempty"`
 }
 
+// AttributeMatchOperator is the comparison an AttributeMatchExpression applies.
+type AttributeMatchOperator string // @name AttributeMatchOperator
+
+const (
+	// AttributeMatchOperatorGreaterThan matches a numeric attribute value greater than the value.
+	AttributeMatchOperatorGreaterThan AttributeMatchOperator = "Gt"
+	// AttributeMatchOperatorLessThan matches a numeric attribute value less than the value.
+	AttributeMatchOperatorLessThan AttributeMatchOperator = "Lt"
+)
+
+// AttributeMatchExpression selects agents whose attribute Key compares with Value as
+// Operator says, e.g. {"key": "port", "operator": "Gt", "value": 1024}. Attribute values
+// are strings; a numeric comparison only matches one that parses as a number.
+type AttributeMatchExpression struct {
+	Key      string                 `json:"key"`
+	Operator AttributeMatchOperator `json:"operator"`
+	Value    float64                `json:"value"`
+} // @name AttributeMatchExpression
+
 // AgentConfig represents the remote configuration for agents in the group.
 // @name AgentGroupAgentConfig.
 type AgentConfig struct {
@@ -80,3 +120,73 @@ type AgentGroupRemoteConfig struct {
 	// AgentRemoteConfigRef is a reference to a standalone remote configuration resource.
 	AgentRemoteConfigRef *string `json:"agentRemoteConfigRef,omitempty"`
 }
+
+// AgentGroupRollout rolls a change of the group's remote configs out to a deterministic
+// subset of its agents, picked by a hash of their instance UID, before the rest.
+// @name AgentGroupRollout.
+type AgentGroupRollout struct {
+	// Percentage is the share of the group's agents, from 0 to 100, that receive the
+	// group's remote configs.
+	Percentage int `json:"percentage,omitempty"`
+	// Count is the number of the group's agents that receive the group's remote configs,
+	// instead of a percentage.
+	Count int `json:"count,omitempty"`
+	// PreviousAgentRemoteConfigs are the remote configs the rest of the group stays on. The
+	// server records them from the group's remote configs when the rollout starts.
+	PreviousAgentRemoteConfigs []AgentGroupRemoteConfig `json:"previousAgentRemoteConfigs,omitempty"`
+}
+
+// AgentGroupRolloutAdvance moves an agent group's rollout forward to a larger percentage
+// or count. Advancing to 100 percent completes the rollout.
+type AgentGroupRolloutAdvance struct {
+	Percentage int `json:"percentage,omitempty"`
+	Count      int `json:"count,omitempty"`
+} // @name AgentGroupRolloutAdvance
+
+// AgentSelectorPreview reports which agents an AgentSelector matches at the moment, so a
+// selector can be checked before it is saved on an AgentGroup.
+type AgentSelectorPreview struct {
+	// Count is the number of agents the selector matches.
+	Count int64 `json:"count"`
+	// Agents is a sample of the matching agents, at most as many as requested.
+	Agents []Agent `json:"agents"`
+} // @name AgentSelectorPreview
+
+// AgentGroupPropagationResult summarizes re-applying an agent group to its matching agents.
+type AgentGroupPropagationResult struct {
+	// Updated is the number of agents that were changed and saved.
+	Updated int `json:"updated"`
+	// Unchanged is the number of agents that already matched the group.
+	Unchanged int `json:"unchanged"`
+	// Failed lists the agents the group could not be applied to.
+	Failed []AgentGroupPropagationFailure `json:"failed"`
+} // @name AgentGroupPropagationResult
+
+// AgentGroupApplyRequest lists the agents to apply an agent group's remote configs to,
+// whether or not the group's selector matches them.
+type AgentGroupApplyRequest struct {
+	InstanceUIDs []uuid.UUID `json:"instanceUids"`
+} // @name AgentGroupApplyRequest
+
+// AgentGroupPropagationFailure describes an agent the agent group could not be applied to.
+type AgentGroupPropagationFailure struct {
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	Error       string    `json:"error"`
+} // @name AgentGroupPropagationFailure
+
+// AgentGroupFailures lists the agents of an agent group that failed to apply its remote configs.
+type AgentGroupFailures struct {
+	Items []AgentGroupFailure `json:"items"`
+} // @name AgentGroupFailures
+
+// AgentGroupFailure describes an agent that reported it failed to apply its remote config
+// while holding remote configs from the agent group.
+type AgentGroupFailure struct {
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// RemoteConfigNames are the agent group's remote configs the agent was offered.
+	RemoteConfigNames []string `json:"remoteConfigNames"`
+	// ErrorMessage is the error the agent reported.
+	ErrorMessage string `json:"errorMessage"`
+	// ReportedAt is when the agent reported the failure.
+	ReportedAt Time `json:"reportedAt,omitzero"`
+} // @name AgentGroupFailure
@@ -21,6 +21,7 @@ type AgentPackageMetadata struct {
 	Attributes Attributes `json:"attributes"`
 	CreatedAt  Time       `json:"createdAt"`
 	DeletedAt  *Time      `json:"deletedAt,omitempty"`
+	DeletedBy  string     `json:"deletedBy,omitempty"`
 } // @name AgentPackageMetadata
 
 // AgentPackageSpec represents the specification of an agent package.
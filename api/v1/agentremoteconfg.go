@@ -20,6 +20,8 @@ type AgentRemoteConfigMetadata struct {
 	Namespace  string     `json:"namespace"`
 	Attributes Attributes `json:"attributes"`
 	CreatedAt  Time       `json:"createdAt"`
+	DeletedAt  *Time      `json:"deletedAt,omitempty"`
+	DeletedBy  string     `json:"deletedBy,omitempty"`
 } // @name AgentRemoteConfigMetadata
 
 // AgentRemoteConfigSpec represents the specification of an agent remote config.
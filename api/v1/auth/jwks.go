@@ -0,0 +1,24 @@
+package auth
+
+// JSONWebKeySet is the RFC 7517 key set served at /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	// Keys are the public keys that verify the tokens the server signs.
+	// It is empty when tokens are signed with a shared secret (HS256).
+	Keys []JSONWebKey `json:"keys"`
+} // @name JSONWebKeySet
+
+// JSONWebKey is an RFC 7517 RSA public key.
+type JSONWebKey struct {
+	// KeyType is the key family, always "RSA".
+	KeyType string `json:"kty"`
+	// Use is the intended use of the key, always "sig".
+	Use string `json:"use"`
+	// Algorithm is the JWS algorithm the key is used with, e.g. "RS256".
+	Algorithm string `json:"alg"`
+	// KeyID matches the "kid" header of the tokens the key verifies.
+	KeyID string `json:"kid"`
+	// Modulus is the base64url-encoded RSA modulus.
+	Modulus string `json:"n"`
+	// Exponent is the base64url-encoded RSA public exponent.
+	Exponent string `json:"e"`
+} // @name JSONWebKey
@@ -0,0 +1,36 @@
+package v1
+
+const (
+	// BackupBundleKind is the kind of the backup bundle resource.
+	BackupBundleKind = "BackupBundle"
+)
+
+// BackupBundle is a point-in-time export of the managed resources, used to
+// restore a server for disaster recovery. Ephemeral state (agents, connections,
+// resource status) is not part of the bundle.
+type BackupBundle struct {
+	Kind               string               `json:"kind"`
+	APIVersion         string               `json:"apiVersion"`
+	Metadata           BackupBundleMetadata `json:"metadata"`
+	AgentGroups        []AgentGroup         `json:"agentGroups"`
+	Certificates       []Certificate        `json:"certificates"`
+	AgentPackages      []AgentPackage       `json:"agentPackages"`
+	AgentRemoteConfigs []AgentRemoteConfig  `json:"agentRemoteConfigs"`
+} // @name BackupBundle
+
+// BackupBundleMetadata represents the metadata of a backup bundle.
+type BackupBundleMetadata struct {
+	// ExportedAt is the time the bundle was exported.
+	ExportedAt Time `json:"exportedAt"`
+} // @name BackupBundleMetadata
+
+// BackupImportResult summarizes what an import changed. Importing the same
+// bundle twice reports every resource as unchanged the second time.
+type BackupImportResult struct {
+	// Created is the number of resources that did not exist and were created.
+	Created int `json:"created"`
+	// Updated is the number of existing resources whose spec was replaced.
+	Updated int `json:"updated"`
+	// Unchanged is the number of existing resources already matching the bundle.
+	Unchanged int `json:"unchanged"`
+} // @name BackupImportResult
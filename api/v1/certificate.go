@@ -1,5 +1,7 @@
 package v1
 
+import "github.com/google/uuid"
+
 const (
 	// CertificateKind is the kind for Certificate resources.
 	CertificateKind = "Certificate"
@@ -32,6 +34,8 @@ type CertificateMetadata struct {
 	// DeletedAt is the timestamp when the certificate was soft deleted.
 	// If nil, the certificate is not deleted.
 	DeletedAt *Time `json:"deletedAt,omitempty"`
+	// DeletedBy identifies the user or system that soft deleted the certificate.
+	DeletedBy string `json:"deletedBy,omitempty"`
 } // @name CertificateMetadata
 
 // CertificateSpec represents the specification of a certificate.
@@ -49,3 +53,28 @@ type CertificateStatus struct {
 	// Conditions contains the conditions of the certificate.
 	Conditions []Condition `json:"conditions,omitempty"`
 } // @name CertificateStatus
+
+// CertificateUsage lists the agent groups whose connection settings use a certificate and
+// whether the agents of those groups accepted the connection settings carrying it.
+type CertificateUsage struct {
+	// AgentGroups are the names of the agent groups referencing the certificate.
+	AgentGroups []string `json:"agentGroups"`
+	// Agents are the agents of those agent groups.
+	Agents []CertificateUsageAgent `json:"agents"`
+} // @name CertificateUsage
+
+// CertificateUsageAgent describes whether an agent accepted the connection settings that use
+// a certificate.
+type CertificateUsageAgent struct {
+	InstanceUID uuid.UUID `json:"instanceUid"`
+	// AgentGroups are the agent groups referencing the certificate that the agent belongs to.
+	AgentGroups []string `json:"agentGroups"`
+	// Status is the connection settings status the agent last reported:
+	// UNSET, APPLIED, APPLYING or FAILED.
+	Status string `json:"status"`
+	// ErrorMessage is the error the agent reported when applying the connection settings failed.
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Accepted is true when the agent reported it applied the connection settings currently
+	// offered to it, so it uses the current certificate.
+	Accepted bool `json:"accepted"`
+} // @name CertificateUsageAgent
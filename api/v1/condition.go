@@ -27,6 +27,12 @@ const (
 	ConditionTypeConfigured ConditionType = "Configured"
 	// ConditionTypeRegistered represents the condition when the agent has been registered.
 	ConditionTypeRegistered ConditionType = "Registered"
+	// ConditionTypeContentVerified represents whether an agent package's artifact was
+	// downloaded and matches its content hash.
+	ConditionTypeContentVerified ConditionType = "ContentVerified"
+	// ConditionTypeRemoteConfigApplied represents whether the remote config an agent group
+	// assigned to the agent can be delivered to it.
+	ConditionTypeRemoteConfigApplied ConditionType = "RemoteConfigApplied"
 )
 
 // ConditionStatus represents the status of an agent condition.
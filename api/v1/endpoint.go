@@ -20,6 +20,8 @@ type EndpointMetadata struct {
 	Namespace  string     `json:"namespace"`
 	Attributes Attributes `json:"attributes"`
 	CreatedAt  Time       `json:"createdAt"`
+	DeletedAt  *Time      `json:"deletedAt,omitempty"`
+	DeletedBy  string     `json:"deletedBy,omitempty"`
 } // @name EndpointMetadata
 
 // EndpointSpec represents the specification of an endpoint.
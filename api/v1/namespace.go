@@ -20,6 +20,7 @@ type NamespaceMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	CreatedAt   Time              `json:"createdAt"`
 	DeletedAt   *Time             `json:"deletedAt,omitempty"`
+	DeletedBy   string            `json:"deletedBy,omitempty"`
 } // @name NamespaceMetadata
 
 // NamespaceStatus represents the status of a namespace.
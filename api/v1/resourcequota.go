@@ -0,0 +1,29 @@
+package v1
+
+const (
+	// ResourceQuotaKind is the kind of the resource quota.
+	ResourceQuotaKind = "ResourceQuota"
+)
+
+// ResourceQuota reports how many resources of one kind exist against its quota.
+type ResourceQuota struct {
+	// Resource is the kind of resource the quota caps, e.g. AgentGroup or Certificate.
+	Resource string `json:"resource"`
+	// Used is the number of resources that exist across all namespaces.
+	Used int64 `json:"used"`
+	// Limit is the maximum number of resources that may exist. 0 means unlimited.
+	Limit int64 `json:"limit"`
+} // @name ResourceQuota
+
+// ResourceQuotaListResponse represents a list of resource quotas with metadata.
+type ResourceQuotaListResponse = ListResponse[ResourceQuota]
+
+// NewResourceQuotaListResponse creates a new ResourceQuotaListResponse with the given quotas and metadata.
+func NewResourceQuotaListResponse(quotas []ResourceQuota, metadata ListMeta) *ResourceQuotaListResponse {
+	return &ResourceQuotaListResponse{
+		Kind:       ResourceQuotaKind,
+		APIVersion: APIVersion,
+		Metadata:   metadata,
+		Items:      quotas,
+	}
+}
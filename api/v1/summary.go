@@ -0,0 +1,23 @@
+package v1
+
+const (
+	// SummaryKind is the kind of the fleet summary.
+	SummaryKind = "Summary"
+)
+
+// Summary is an overview of the health of the whole agent fleet, across all namespaces.
+type Summary struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	// TotalAgents is the number of agents.
+	TotalAgents int64 `json:"totalAgents"`
+	// ConnectedAgents is the number of agents that are connected.
+	ConnectedAgents int64 `json:"connectedAgents"`
+	// HealthyAgents is the number of connected agents that report being healthy.
+	HealthyAgents int64 `json:"healthyAgents"`
+	// UnhealthyAgents is the number of connected agents that do not report being healthy.
+	UnhealthyAgents int64 `json:"unhealthyAgents"`
+	// AgentsWithPendingCommands is the number of agents with a command they have not acted
+	// on yet, such as a requested restart or report.
+	AgentsWithPendingCommands int64 `json:"agentsWithPendingCommands"`
+} // @name Summary
@@ -20,3 +20,11 @@ type ListResponse[T any] struct {
 	Metadata   ListMeta `json:"metadata"`
 	Items      []T      `json:"items"`
 } // @name ListResponse
+
+// DeleteCollectionResponse reports the outcome of a selector-based bulk delete.
+type DeleteCollectionResponse struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	// DeletedCount is the number of resources the request deleted.
+	DeletedCount int `json:"deletedCount"`
+} // @name DeleteCollectionResponse
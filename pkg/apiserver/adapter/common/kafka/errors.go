@@ -9,3 +9,13 @@ type UnknownMessageTypeError struct {
 func (e *UnknownMessageTypeError) Error() string {
 	return "unknown message type: " + e.MessageType
 }
+
+// UnsupportedKeyStrategyError is returned when the configured Kafka key strategy is unknown.
+type UnsupportedKeyStrategyError struct {
+	KeyStrategy string
+}
+
+// Error implements the error interface.
+func (e *UnsupportedKeyStrategyError) Error() string {
+	return "unsupported kafka key strategy: " + e.KeyStrategy
+}
@@ -15,6 +15,12 @@ const (
 	SendToAgentEventType = "io.opampcommander.server.sendtosagent.v1"
 	// InvalidateAgentCacheEventType is the CloudEvent type for invalidating cached agents.
 	InvalidateAgentCacheEventType = "io.opampcommander.server.invalidateagentcache.v1"
+	// AgentGroupChangedEventType is the CloudEvent type for AgentGroup config diffs.
+	AgentGroupChangedEventType = "io.opampcommander.server.agentgroupchanged.v1"
+	// DisconnectAgentEventType is the CloudEvent type for closing an agent's connection.
+	DisconnectAgentEventType = "io.opampcommander.server.disconnectagent.v1"
+	// AgentEffectiveConfigChangedEventType is the CloudEvent type for an agent's changed effective config.
+	AgentEffectiveConfigChangedEventType = "io.opampcommander.server.agenteffectiveconfigchanged.v1"
 	// UnknownEventType is the CloudEvent type for unknown messages.
 	UnknownEventType = "io.opampcommander.server.unknown.v1"
 )
@@ -26,6 +32,12 @@ func EventTypeFromMessageType(messageType serverevent.MessageType) string {
 		return SendToAgentEventType
 	case serverevent.MessageTypeInvalidateAgentCache:
 		return InvalidateAgentCacheEventType
+	case serverevent.MessageTypeAgentGroupChanged:
+		return AgentGroupChangedEventType
+	case serverevent.MessageTypeDisconnectAgent:
+		return DisconnectAgentEventType
+	case serverevent.MessageTypeAgentEffectiveConfigChanged:
+		return AgentEffectiveConfigChangedEventType
 	default:
 		return UnknownEventType
 	}
@@ -38,6 +50,12 @@ func MessageTypeFromEventType(eventType string) (serverevent.MessageType, error)
 		return serverevent.MessageTypeSendServerToAgent, nil
 	case InvalidateAgentCacheEventType:
 		return serverevent.MessageTypeInvalidateAgentCache, nil
+	case AgentGroupChangedEventType:
+		return serverevent.MessageTypeAgentGroupChanged, nil
+	case DisconnectAgentEventType:
+		return serverevent.MessageTypeDisconnectAgent, nil
+	case AgentEffectiveConfigChangedEventType:
+		return serverevent.MessageTypeAgentEffectiveConfigChanged, nil
 	default:
 		return "", &UnknownMessageTypeError{MessageType: eventType}
 	}
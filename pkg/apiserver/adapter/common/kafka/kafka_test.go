@@ -23,6 +23,11 @@ func TestEventTypeFromMessageType(t *testing.T) {
 			messageType: serverevent.MessageTypeSendServerToAgent,
 			expected:    kafkamodel.SendToAgentEventType,
 		},
+		{
+			name:        "AgentGroupChanged type",
+			messageType: serverevent.MessageTypeAgentGroupChanged,
+			expected:    kafkamodel.AgentGroupChangedEventType,
+		},
 		{
 			name:        "Unknown type",
 			messageType: "unknown",
@@ -55,6 +60,12 @@ func TestMessageTypeFromEventType(t *testing.T) {
 			expected:    serverevent.MessageTypeSendServerToAgent,
 			expectError: false,
 		},
+		{
+			name:        "AgentGroupChanged event type",
+			eventType:   kafkamodel.AgentGroupChangedEventType,
+			expected:    serverevent.MessageTypeAgentGroupChanged,
+			expectError: false,
+		},
 		{
 			name:        "Unknown event type",
 			eventType:   "unknown",
@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/serverevent"
+)
+
+// PartitionKeyExtension is the CloudEvents extension the Kafka sender uses as the message key.
+const PartitionKeyExtension = "partitionkey"
+
+// KeyStrategy selects how server events are keyed, and so how they are spread over partitions.
+type KeyStrategy string
+
+const (
+	// KeyStrategyInstanceUID keys an event about a single agent by that agent's instance UID,
+	// so the events for one agent stay ordered within a partition. Events about several
+	// agents, or about none, are keyed by their target server instead.
+	KeyStrategyInstanceUID KeyStrategy = "instanceUID"
+	// KeyStrategyNone sends events without a key, letting the producer spread them freely.
+	KeyStrategyNone KeyStrategy = "none"
+)
+
+// ParseKeyStrategy parses a configured key strategy. An empty value selects KeyStrategyInstanceUID.
+func ParseKeyStrategy(value string) (KeyStrategy, error) {
+	switch strategy := KeyStrategy(value); strategy {
+	case "":
+		return KeyStrategyInstanceUID, nil
+	case KeyStrategyInstanceUID, KeyStrategyNone:
+		return strategy, nil
+	default:
+		return "", &UnsupportedKeyStrategyError{KeyStrategy: value}
+	}
+}
+
+// PartitionKey returns the key message is sent with under strategy, or "" for no key.
+func PartitionKey(strategy KeyStrategy, message serverevent.Message) string {
+	if strategy != KeyStrategyInstanceUID {
+		return ""
+	}
+
+	var instanceUIDs []uuid.UUID
+
+	switch {
+	case message.Payload.MessageForServerToAgent != nil:
+		instanceUIDs = message.Payload.TargetAgentInstanceUIDs
+	case message.Payload.MessageForInvalidateAgentCache != nil:
+		instanceUIDs = message.Payload.AgentInstanceUIDs
+	case message.Payload.MessageForDisconnectAgent != nil:
+		instanceUIDs = []uuid.UUID{message.Payload.AgentInstanceUID}
+	case message.Payload.MessageForAgentEffectiveConfigChanged != nil:
+		instanceUIDs = []uuid.UUID{message.Payload.ChangedAgentInstanceUID}
+	}
+
+	if len(instanceUIDs) == 1 {
+		return instanceUIDs[0].String()
+	}
+
+	return message.Target
+}
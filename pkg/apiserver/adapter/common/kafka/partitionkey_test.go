@@ -0,0 +1,82 @@
+package kafka_test
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	kafkamodel "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/common/kafka"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/serverevent"
+)
+
+func sendToAgentMessage(target string, instanceUIDs ...uuid.UUID) serverevent.Message {
+	return serverevent.Message{
+		Source: "server-a",
+		Target: target,
+		Type:   serverevent.MessageTypeSendServerToAgent,
+		Payload: serverevent.MessagePayload{
+			MessageForServerToAgent: &serverevent.MessageForServerToAgent{
+				TargetAgentInstanceUIDs: instanceUIDs,
+				TargetAgentSequenceNums: nil,
+			},
+			MessageForInvalidateAgentCache:        nil,
+			MessageForAgentGroupChanged:           nil,
+			MessageForDisconnectAgent:             nil,
+			MessageForAgentEffectiveConfigChanged: nil,
+		},
+	}
+}
+
+func TestPartitionKey_InstanceUID(t *testing.T) {
+	t.Parallel()
+
+	agentA := uuid.New()
+	agentB := uuid.New()
+
+	first := kafkamodel.PartitionKey(kafkamodel.KeyStrategyInstanceUID, sendToAgentMessage("server-b", agentA))
+	second := kafkamodel.PartitionKey(kafkamodel.KeyStrategyInstanceUID, sendToAgentMessage("server-c", agentA))
+	other := kafkamodel.PartitionKey(kafkamodel.KeyStrategyInstanceUID, sendToAgentMessage("server-b", agentB))
+
+	assert.Equal(t, agentA.String(), first)
+	assert.Equal(t, first, second, "events for the same agent share a key regardless of the target server")
+	assert.NotEqual(t, first, other)
+
+	// The producer's default partitioner hashes the key, so equal keys land on one partition.
+	partitioner := sarama.NewHashPartitioner("events")
+	partitionOf := func(key string) int32 {
+		partition, err := partitioner.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder(key)}, 12)
+		require.NoError(t, err)
+
+		return partition
+	}
+	assert.Equal(t, partitionOf(first), partitionOf(second))
+}
+
+func TestPartitionKey_FallsBackToTargetServer(t *testing.T) {
+	t.Parallel()
+
+	batch := sendToAgentMessage("server-b", uuid.New(), uuid.New())
+
+	assert.Equal(t, "server-b", kafkamodel.PartitionKey(kafkamodel.KeyStrategyInstanceUID, batch))
+	assert.Empty(t, kafkamodel.PartitionKey(kafkamodel.KeyStrategyNone, batch))
+}
+
+func TestParseKeyStrategy(t *testing.T) {
+	t.Parallel()
+
+	strategy, err := kafkamodel.ParseKeyStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, kafkamodel.KeyStrategyInstanceUID, strategy)
+
+	strategy, err = kafkamodel.ParseKeyStrategy("none")
+	require.NoError(t, err)
+	assert.Equal(t, kafkamodel.KeyStrategyNone, strategy)
+
+	var unsupported *kafkamodel.UnsupportedKeyStrategyError
+
+	_, err = kafkamodel.ParseKeyStrategy("random")
+	require.ErrorAs(t, err, &unsupported)
+}
@@ -92,7 +92,10 @@ func newService(t *testing.T, repo userport.UserPersistencePort, refreshExpirati
 		},
 	}
 
-	return security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg), repo)
+	svc, err := security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg), repo)
+	require.NoError(t, err)
+
+	return svc
 }
 
 // newRouter registers the controller routes without any authentication middleware.
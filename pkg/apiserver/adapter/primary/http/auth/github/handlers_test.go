@@ -130,7 +130,11 @@ func newService(t *testing.T, httpClient *http.Client, allowedHosts []string) *s
 		},
 	}
 
-	return security.New(slog.Default(), cfg, httpClient, security.NewPasswordHasher(cfg), inmemory.NewUserRepository())
+	svc, err := security.New(slog.Default(), cfg, httpClient, security.NewPasswordHasher(cfg),
+		inmemory.NewUserRepository())
+	require.NoError(t, err)
+
+	return svc
 }
 
 // newController builds a controller and returns it alongside the provisioning spy.
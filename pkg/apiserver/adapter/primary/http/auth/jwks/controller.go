@@ -0,0 +1,71 @@
+// Package jwks provides the controller that publishes the public keys verifying the
+// server's tokens, so other services can verify them without sharing a secret.
+package jwks
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	v1auth "github.com/minuk-dev/opampcommander/api/v1/auth"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+)
+
+// keySetProvider is the part of the security service the controller needs.
+type keySetProvider interface {
+	VerificationKeys() []security.VerificationKey
+}
+
+// Controller serves the JSON Web Key Set of the server.
+type Controller struct {
+	logger  *slog.Logger
+	service keySetProvider
+}
+
+// NewController creates a new instance of the Controller struct with the provided settings.
+func NewController(logger *slog.Logger, service *security.Service) *Controller {
+	return &Controller{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// RoutesInfo returns the routes information for the JWKS controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      "GET",
+			Path:        "/.well-known/jwks.json",
+			Handler:     "http.auth.JWKS",
+			HandlerFunc: c.JWKS,
+		},
+	}
+}
+
+// JWKS returns the public keys that verify the tokens the server signs.
+// The set is empty unless tokens are signed with RS256.
+//
+// @Summary  JSON Web Key Set
+// @Tags auth
+// @Description Return the public keys that verify the tokens the server signs (empty unless RS256 is used).
+// @Produce json
+// @Success 200 {object} v1auth.JSONWebKeySet
+// @Router /.well-known/jwks.json [get].
+func (c *Controller) JWKS(ctx *gin.Context) {
+	keys := lo.Map(c.service.VerificationKeys(), func(key security.VerificationKey, _ int) v1auth.JSONWebKey {
+		return v1auth.JSONWebKey{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+			KeyID:     key.KeyID,
+			Modulus:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}
+	})
+
+	ctx.JSON(http.StatusOK, v1auth.JSONWebKeySet{Keys: keys})
+}
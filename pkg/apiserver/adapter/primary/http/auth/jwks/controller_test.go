@@ -0,0 +1,119 @@
+package jwks_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1auth "github.com/minuk-dev/opampcommander/api/v1/auth"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/auth/jwks"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func newService(t *testing.T, settings security.JWTSettings) *security.Service {
+	t.Helper()
+
+	settings.Issuer = "test"
+	settings.Expiration = time.Minute
+
+	//exhaustruct:ignore
+	cfg := &security.Config{JWTSettings: settings}
+
+	svc, err := security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg),
+		inmemory.NewUserRepository())
+	require.NoError(t, err)
+
+	return svc
+}
+
+// getKeySet serves the controller behind the JWT middleware, as the server does, and
+// fetches the key set without credentials.
+func getKeySet(t *testing.T, service *security.Service) v1auth.JSONWebKeySet {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(security.NewAuthJWTMiddleware(service))
+
+	for _, route := range jwks.NewController(slog.Default(), service).RoutesInfo() {
+		router.Handle(route.Method, route.Path, route.HandlerFunc)
+	}
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/.well-known/jwks.json", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var keySet v1auth.JSONWebKeySet
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &keySet))
+
+	return keySet
+}
+
+func TestController_JWKS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RS256 publishes the public key", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "jwt.pem")
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		require.NoError(t, os.WriteFile(path, pemBytes, 0o600))
+
+		//exhaustruct:ignore
+		service := newService(t, security.JWTSettings{
+			SigningAlgorithm: security.SigningAlgorithmRS256,
+			PrivateKeyFile:   path,
+		})
+
+		keySet := getKeySet(t, service)
+		require.Len(t, keySet.Keys, 1)
+
+		jwk := keySet.Keys[0]
+		assert.Equal(t, "RSA", jwk.KeyType)
+		assert.Equal(t, "sig", jwk.Use)
+		assert.Equal(t, "RS256", jwk.Algorithm)
+		assert.Equal(t, service.VerificationKeys()[0].KeyID, jwk.KeyID)
+
+		modulus, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+		require.NoError(t, err)
+		assert.Equal(t, 0, key.N.Cmp(new(big.Int).SetBytes(modulus)))
+
+		exponent, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+		require.NoError(t, err)
+		assert.Equal(t, int64(key.E), new(big.Int).SetBytes(exponent).Int64())
+	})
+
+	t.Run("HS256 publishes no key", func(t *testing.T) {
+		t.Parallel()
+
+		//exhaustruct:ignore
+		keySet := getKeySet(t, newService(t, security.JWTSettings{SigningKey: "test-signing-key"}))
+		assert.NotNil(t, keySet.Keys)
+		assert.Empty(t, keySet.Keys)
+	})
+}
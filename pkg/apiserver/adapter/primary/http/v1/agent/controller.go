@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,11 +17,22 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
 )
 
-// ErrInvalidSelector is returned when a selector query parameter is malformed
-// (an entry without a "key=value" shape, or with an empty key). It wraps
-// ginutil.ErrInvalidFormat so the HTTP layer maps it to a 400 Bad Request.
-var ErrInvalidSelector = fmt.Errorf(
-	"invalid selector: expected comma-separated key=value pairs: %w", ginutil.ErrInvalidFormat)
+// ErrInvalidSelector is returned when a selector query parameter is malformed.
+// It aliases ginutil.ErrInvalidSelector, which the other list endpoints share.
+var ErrInvalidSelector = ginutil.ErrInvalidSelector
+
+const (
+	// DefaultStreamPageSize is how many agents a streaming list reads per page when the
+	// request does not set a limit.
+	DefaultStreamPageSize = 500
+	// EffectiveConfigEventName is the server-sent event name carrying an agent's effective
+	// config on the effective config watch.
+	EffectiveConfigEventName = "effectiveConfig"
+	// CommandQueueFullRetryAfter is the Retry-After sent when an agent's command queue is
+	// full. Agents act on queued commands in their next message, so it is about one
+	// heartbeat interval.
+	CommandQueueFullRetryAfter = 30 * time.Second
+)
 
 // Controller is a struct that implements the agent controller.
 type Controller struct {
@@ -58,29 +70,107 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.agent.Search",
 			HandlerFunc: c.Search,
 		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agents/by-package",
+			Handler:     "http.v1.agent.ListByPackage",
+			HandlerFunc: c.ListByPackage,
+		},
 		{
 			Method:      http.MethodGet,
 			Path:        "/api/v1/namespaces/:namespace/agents/:id",
 			Handler:     "http.v1.agent.Get",
-			HandlerFunc: c.Get,
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Get),
 		},
 		{
 			Method:      http.MethodGet,
 			Path:        "/api/v1/namespaces/:namespace/agents/:id/endpoints",
 			Handler:     "http.v1.agent.ListEndpoints",
-			HandlerFunc: c.ListEndpoints,
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.ListEndpoints),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/uptime",
+			Handler:     "http.v1.agent.GetUptime",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.GetUptime),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/effective-config/history",
+			Handler:     "http.v1.agent.GetEffectiveConfigHistory",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.GetEffectiveConfigHistory),
 		},
 		{
 			Method:      http.MethodPut,
 			Path:        "/api/v1/namespaces/:namespace/agents/:id",
 			Handler:     "http.v1.agent.Update",
-			HandlerFunc: c.Update,
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Update),
 		},
 		{
 			Method:      http.MethodDelete,
 			Path:        "/api/v1/namespaces/:namespace/agents/:id",
 			Handler:     "http.v1.agent.Delete",
-			HandlerFunc: c.Delete,
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Delete),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/report/effective-config",
+			Handler:     "http.v1.agent.RequestEffectiveConfigReport",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.RequestEffectiveConfigReport),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/report/health",
+			Handler:     "http.v1.agent.RequestHealthReport",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.RequestHealthReport),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/report/available-components",
+			Handler:     "http.v1.agent.RequestAvailableComponentsReport",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.RequestAvailableComponentsReport),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/resend-config",
+			Handler:     "http.v1.agent.ResendConfig",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.ResendConfig),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/reconnect",
+			Handler:     "http.v1.agent.Reconnect",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Reconnect),
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/config",
+			Handler:     "http.v1.agent.SetConfig",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.SetConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/desired-config",
+			Handler:     "http.v1.agent.GetDesiredConfig",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.GetDesiredConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/effective-config/watch",
+			Handler:     "http.v1.agent.WatchEffectiveConfig",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.WatchEffectiveConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/agents/capabilities",
+			Handler:     "http.v1.agent.ListCapabilities",
+			HandlerFunc: c.ListCapabilities,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/agents/attributes",
+			Handler:     "http.v1.agent.ListAttributes",
+			HandlerFunc: c.ListAttributes,
 		},
 	}
 }
@@ -90,15 +180,20 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 // @Summary  List Agents
 // @Tags agent
 // @Description Retrieve a list of agents in a namespace.
+// @Description With stream=ndjson or "Accept: application/x-ndjson", every matching agent is
+// @Description streamed as one JSON object per line instead; limit then sets the page size.
 // @Accept json
 // @Produce json
+// @Produce application/x-ndjson
 // @Success 200 {object} v1.ListResponse[v1.Agent]
 // @Param namespace path string true "Namespace"
 // @Param limit query int false "Maximum number of agents to return"
 // @Param continue query string false "Token to continue listing agents"
 // @Param connected query bool false "When true, return only currently-connected agents"
+// @Param connectionType query string false "Connection type filter" Enums(http, websocket, unknown)
 // @Param selector query []string false "Identifying attribute filter (key=value, repeatable)" collectionFormat(multi)
 // @Param nonIdentifyingSelector query []string false "Non-identifying attribute (key=value)" collectionFormat(multi)
+// @Param stream query string false "Set to ndjson to stream agents as newline-delimited JSON"
 // @Failure 400 {object} ErrorModel
 // @Failure 500 {object} ErrorModel
 // @Router /api/v1/namespaces/{namespace}/agents [get].
@@ -124,14 +219,21 @@ func (c *Controller) List(ctx *gin.Context) {
 		return
 	}
 
-	identifyingAttributes, err := parseSelector(ctx.QueryArray("selector"))
+	connectionType, err := parseConnectionType(ctx.Query("connectionType"))
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "connectionType", ctx.Query("connectionType"), err, false)
+
+		return
+	}
+
+	identifyingAttributes, err := ginutil.ParseSelector(ctx.QueryArray("selector"))
 	if err != nil {
 		ginutil.HandleValidationError(ctx, "selector", strings.Join(ctx.QueryArray("selector"), ","), err, false)
 
 		return
 	}
 
-	nonIdentifyingAttributes, err := parseSelector(ctx.QueryArray("nonIdentifyingSelector"))
+	nonIdentifyingAttributes, err := ginutil.ParseSelector(ctx.QueryArray("nonIdentifyingSelector"))
 	if err != nil {
 		ginutil.HandleValidationError(ctx, "nonIdentifyingSelector",
 			strings.Join(ctx.QueryArray("nonIdentifyingSelector"), ","), err, false)
@@ -139,15 +241,30 @@ func (c *Controller) List(ctx *gin.Context) {
 		return
 	}
 
-	continueToken := ctx.Query("continue")
+	stream, err := ginutil.WantsNDJSON(ctx)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "stream", ctx.Query("stream"), err, false)
 
-	response, err := c.agentUsecase.ListAgents(ctx.Request.Context(), namespace, &applicationport.ListOptions{
+		return
+	}
+
+	continueToken := ctx.Query("continue")
+	options := &applicationport.ListOptions{
 		Limit:                    limit,
 		Continue:                 continueToken,
 		ConnectedOnly:            connectedOnly,
 		IdentifyingAttributes:    identifyingAttributes,
 		NonIdentifyingAttributes: nonIdentifyingAttributes,
-	})
+		ConnectionType:           connectionType,
+	}
+
+	if stream {
+		c.streamList(ctx, namespace, options)
+
+		return
+	}
+
+	response, err := c.agentUsecase.ListAgents(ctx.Request.Context(), namespace, options)
 	if err != nil {
 		c.logger.Error("failed to list agents", "error", err.Error())
 		ginutil.HandleDomainError(ctx, err, "An error occurred while retrieving the list of agents.")
@@ -158,6 +275,68 @@ func (c *Controller) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// parseConnectionType maps the connectionType query value to the connection type name it
+// selects, ignoring case. An empty value selects every connection type and yields "".
+func parseConnectionType(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	for _, connectionType := range []string{
+		v1.ConnectionTypeHTTP, v1.ConnectionTypeWebSocket, v1.ConnectionTypeUnknown,
+	} {
+		if strings.EqualFold(value, connectionType) {
+			return connectionType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: unknown connection type %q", ginutil.ErrInvalidValue, value)
+}
+
+// streamList walks every page of the listing and writes each agent as its own NDJSON line,
+// so neither side holds more than one page at a time.
+// Errors before the first page are reported as usual; once streaming has started the status
+// is already sent, so a failure just ends the stream early.
+func (c *Controller) streamList(ctx *gin.Context, namespace string, options *applicationport.ListOptions) {
+	if options.Limit <= 0 {
+		options.Limit = DefaultStreamPageSize
+	}
+
+	var writer *ginutil.NDJSONWriter
+
+	for {
+		response, err := c.agentUsecase.ListAgents(ctx.Request.Context(), namespace, options)
+		if err != nil {
+			c.logger.Error("failed to stream agents", "error", err.Error())
+
+			if writer == nil {
+				ginutil.HandleDomainError(ctx, err, "An error occurred while retrieving the list of agents.")
+			}
+
+			return
+		}
+
+		if writer == nil {
+			writer = ginutil.NewNDJSONWriter(ctx)
+		}
+
+		for _, item := range response.Items {
+			err = writer.Write(item)
+			if err != nil {
+				c.logger.Warn("failed to write agent to stream", "error", err.Error())
+
+				return
+			}
+		}
+
+		if response.Metadata.Continue == "" {
+			return
+		}
+
+		options.Continue = response.Metadata.Continue
+	}
+}
+
 // Search searches agents by query string.
 //
 // @Summary  Search Agents
@@ -234,6 +413,167 @@ func (c *Controller) Search(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// ListByPackage lists the agents that reported a package, optionally at one version.
+//
+// @Summary  List Agents by Package
+// @Tags agent
+// @Description List the agents in a namespace whose package statuses report the named package,
+// @Description at the given version when one is set (e.g. the agents still on an old collector).
+// @Accept json
+// @Produce json
+// @Success 200 {object} v1.ListResponse[v1.Agent]
+// @Param namespace path string true "Namespace"
+// @Param name query string true "Package name"
+// @Param version query string false "Package version the agent has; any version when omitted"
+// @Param limit query int false "Maximum number of agents to return"
+// @Param continue query string false "Token to continue listing agents"
+// @Param connected query bool false "When true, return only currently-connected agents"
+// @Failure 400 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agents/by-package [get].
+func (c *Controller) ListByPackage(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	packageName := strings.TrimSpace(ctx.Query("name"))
+	if packageName == "" {
+		ginutil.HandleValidationError(ctx, "name", "", ginutil.ErrRequiredParam, false)
+
+		return
+	}
+
+	limit, err := ginutil.ParseInt64(ctx, "limit", 0)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "limit", ctx.Query("limit"), err, false)
+
+		return
+	}
+
+	connectedOnly, err := ginutil.ParseBool(ctx, "connected", false)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "connected", ctx.Query("connected"), err, false)
+
+		return
+	}
+
+	response, err := c.agentUsecase.ListAgentsByPackage(ctx.Request.Context(), namespace,
+		packageName, strings.TrimSpace(ctx.Query("version")), &applicationport.ListOptions{
+			Limit:         limit,
+			Continue:      ctx.Query("continue"),
+			ConnectedOnly: connectedOnly,
+		})
+	if err != nil {
+		c.logger.Error("failed to list agents by package", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while listing agents by package.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListCapabilities lists the decoded OpAMP capabilities of every connected agent.
+//
+// @Summary  List Agent Capabilities
+// @Tags agent
+// @Description List the OpAMP capabilities of every connected agent across all namespaces,
+// @Description decoded into flag names, for a capability matrix. With capability set, only the
+// @Description agents that reported every given capability are listed.
+// @Produce json
+// @Success 200 {object} v1.ListResponse[v1.AgentCapabilitySummary]
+// @Param capability query []string false "Capability flag, e.g. AcceptsPackages (repeatable)" collectionFormat(multi)
+// @Param limit query int false "Maximum number of agents to return"
+// @Param continue query string false "Token to continue listing agents"
+// @Failure 400 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/agents/capabilities [get].
+func (c *Controller) ListCapabilities(ctx *gin.Context) {
+	capabilities, err := parseCapabilities(ctx.QueryArray("capability"))
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "capability", strings.Join(ctx.QueryArray("capability"), ","), err, false)
+
+		return
+	}
+
+	limit, err := ginutil.ParseInt64(ctx, "limit", 0)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "limit", ctx.Query("limit"), err, false)
+
+		return
+	}
+
+	response, err := c.agentUsecase.ListAgentCapabilities(ctx.Request.Context(), &applicationport.ListOptions{
+		Limit:        limit,
+		Continue:     ctx.Query("continue"),
+		Capabilities: uint64(capabilities),
+	})
+	if err != nil {
+		c.logger.Error("failed to list agent capabilities", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while listing agent capabilities.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListAttributes lists the distinct attribute keys agents reported.
+//
+// @Summary  List Agent Attributes
+// @Tags agent
+// @Description List the distinct identifying and non-identifying attribute keys reported by
+// @Description agents across all namespaces, e.g. to help write agent group selectors. With
+// @Description values set, up to that many distinct values are listed per key, at most 100.
+// @Produce json
+// @Success 200 {object} v1.AgentAttributeCatalog
+// @Param values query int false "Maximum number of distinct values to list per key"
+// @Failure 400 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/agents/attributes [get].
+func (c *Controller) ListAttributes(ctx *gin.Context) {
+	valueLimit, err := ginutil.ParseInt64(ctx, "values", 0)
+	if err == nil && valueLimit < 0 {
+		err = fmt.Errorf("%w: must not be negative", ginutil.ErrInvalidValue)
+	}
+
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "values", ctx.Query("values"), err, false)
+
+		return
+	}
+
+	response, err := c.agentUsecase.ListAgentAttributes(ctx.Request.Context(), int(valueLimit))
+	if err != nil {
+		c.logger.Error("failed to list agent attributes", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while listing agent attributes.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// parseCapabilities combines the named capability flags into one bitmask, ignoring case.
+// No names yields zero, which selects every agent.
+func parseCapabilities(names []string) (v1.AgentCapabilities, error) {
+	var capabilities v1.AgentCapabilities
+
+	for _, name := range names {
+		capability, ok := v1.ParseAgentCapability(strings.TrimSpace(name))
+		if !ok {
+			return 0, fmt.Errorf("%w: unknown capability %q", ginutil.ErrInvalidValue, name)
+		}
+
+		capabilities |= capability
+	}
+
+	return capabilities, nil
+}
+
 // Get retrieves an agent by its instance UID.
 //
 // @Summary  Get Agent
@@ -243,6 +583,7 @@ func (c *Controller) Search(ctx *gin.Context) {
 // @Produce  json
 // @Param  namespace path string true "Namespace"
 // @Param  id path string true "Instance UID of the agent"
+// @Param  fields query string false "Comma-separated field paths to return, e.g. metadata,status.componentHealth"
 // @Success  200 {object} Agent
 // @Failure  400 {object} ErrorModel
 // @Failure  404 {object} ErrorModel
@@ -256,9 +597,11 @@ func (c *Controller) Get(ctx *gin.Context) {
 		return
 	}
 
-	instanceUID, err := ginutil.ParseUUID(ctx, "id")
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	fields, err := ginutil.ParseFieldSelector(ctx, "fields", v1.Agent{})
 	if err != nil {
-		ginutil.HandleValidationError(ctx, "id", ctx.Param("id"), err, true)
+		ginutil.HandleValidationError(ctx, "fields", ctx.Query("fields"), err, false)
 
 		return
 	}
@@ -270,7 +613,20 @@ func (c *Controller) Get(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, agent)
+	if fields == nil {
+		ctx.JSON(http.StatusOK, agent)
+
+		return
+	}
+
+	projected, err := fields.Project(agent)
+	if err != nil {
+		ginutil.InternalServerError(ctx, err, "An error occurred while selecting agent fields.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, projected)
 }
 
 // ListEndpoints retrieves the endpoints an agent currently exports to, extracted
@@ -296,21 +652,82 @@ func (c *Controller) ListEndpoints(ctx *gin.Context) {
 		return
 	}
 
-	instanceUID, err := ginutil.ParseUUID(ctx, "id")
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	endpoints, err := c.agentUsecase.ListAgentEndpoints(ctx.Request.Context(), namespace, instanceUID)
 	if err != nil {
-		ginutil.HandleValidationError(ctx, "id", ctx.Param("id"), err, true)
+		c.handleAgentError(ctx, err, "An error occurred while extracting the agent's endpoints.")
 
 		return
 	}
 
-	endpoints, err := c.agentUsecase.ListAgentEndpoints(ctx.Request.Context(), namespace, instanceUID)
+	ctx.JSON(http.StatusOK, endpoints)
+}
+
+// GetUptime retrieves an agent's connection statistics over time.
+//
+// @Summary  Get Agent Uptime
+// @Tags agent
+// @Description Retrieve an agent's total connected time, disconnect count and last-24h uptime.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  200 {object} v1.AgentUptime
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/uptime [get].
+func (c *Controller) GetUptime(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
-		c.handleAgentError(ctx, err, "An error occurred while extracting the agent's endpoints.")
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
 
 		return
 	}
 
-	ctx.JSON(http.StatusOK, endpoints)
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	uptime, err := c.agentUsecase.GetAgentUptime(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while retrieving the agent's uptime.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, uptime)
+}
+
+// GetEffectiveConfigHistory retrieves the effective configs an agent reported over time.
+//
+// @Summary  Get Agent Effective Config History
+// @Tags agent
+// @Description Retrieve the distinct effective configs the agent reported, oldest first.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  200 {object} v1.AgentEffectiveConfigHistory
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/effective-config/history [get].
+func (c *Controller) GetEffectiveConfigHistory(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	history, err := c.agentUsecase.GetAgentEffectiveConfigHistory(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while retrieving the agent's effective config history.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
 }
 
 // Update updates an agent's metadata & spec.
@@ -326,6 +743,9 @@ func (c *Controller) ListEndpoints(ctx *gin.Context) {
 // @Success  200 {object} v1.Agent
 // @Failure  400 {object} ErrorModel
 // @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  429 {object} ErrorModel
+// @Header   429 {string} Retry-After "Seconds to wait before retrying"
 // @Failure  500 {object} ErrorModel
 // @Router  /api/v1/namespaces/{namespace}/agents/{id} [put].
 func (c *Controller) Update(ctx *gin.Context) {
@@ -336,12 +756,7 @@ func (c *Controller) Update(ctx *gin.Context) {
 		return
 	}
 
-	instanceUID, err := ginutil.ParseUUID(ctx, "id")
-	if err != nil {
-		ginutil.HandleValidationError(ctx, "id", ctx.Param("id"), err, true)
-
-		return
-	}
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
 
 	var req v1.Agent
 
@@ -386,66 +801,321 @@ func (c *Controller) Delete(ctx *gin.Context) {
 		return
 	}
 
-	instanceUID, err := ginutil.ParseUUID(ctx, "id")
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	err = c.agentUsecase.DeleteAgent(ctx.Request.Context(), namespace, instanceUID)
 	if err != nil {
-		ginutil.HandleValidationError(ctx, "id", ctx.Param("id"), err, true)
+		c.handleAgentError(ctx, err, "An error occurred while deleting the agent.")
 
 		return
 	}
 
-	err = c.agentUsecase.DeleteAgent(ctx.Request.Context(), namespace, instanceUID)
+	ctx.Status(http.StatusNoContent)
+}
+
+// RequestEffectiveConfigReport asks an agent to report its effective config again.
+//
+// @Summary  Request Agent Effective Config Report
+// @Tags agent
+// @Description Ask the agent to re-send its effective config. OpAMP has no flag for the effective
+// @Description config alone, so the agent is sent ReportFullState until it reports the config.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  202 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  429 {object} ErrorModel
+// @Header   429 {string} Retry-After "Seconds to wait before retrying"
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/report/effective-config [post].
+func (c *Controller) RequestEffectiveConfigReport(ctx *gin.Context) {
+	c.requestReport(ctx, v1.AgentReportKindEffectiveConfig)
+}
+
+// RequestHealthReport asks an agent to report its component health again.
+//
+// @Summary  Request Agent Health Report
+// @Tags agent
+// @Description Ask the agent to re-send its component health. OpAMP has no flag for the health
+// @Description alone, so the agent is sent ReportFullState until it reports its health.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  202 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  429 {object} ErrorModel
+// @Header   429 {string} Retry-After "Seconds to wait before retrying"
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/report/health [post].
+func (c *Controller) RequestHealthReport(ctx *gin.Context) {
+	c.requestReport(ctx, v1.AgentReportKindHealth)
+}
+
+// RequestAvailableComponentsReport asks an agent to report its available components again.
+//
+// @Summary  Request Agent Available Components Report
+// @Tags agent
+// @Description Ask the agent to re-send its available components with the ReportAvailableComponents flag.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  202 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  429 {object} ErrorModel
+// @Header   429 {string} Retry-After "Seconds to wait before retrying"
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/report/available-components [post].
+func (c *Controller) RequestAvailableComponentsReport(ctx *gin.Context) {
+	c.requestReport(ctx, v1.AgentReportKindAvailableComponents)
+}
+
+// requestReport records the report request and answers 202, since the agent reports
+// asynchronously.
+func (c *Controller) requestReport(ctx *gin.Context, kind v1.AgentReportKind) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
-		c.handleAgentError(ctx, err, "An error occurred while deleting the agent.")
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
 
 		return
 	}
 
-	ctx.Status(http.StatusNoContent)
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	agent, err := c.agentUsecase.RequestAgentReport(ctx.Request.Context(), namespace, instanceUID, kind)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while requesting the agent report.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, agent)
 }
 
-// parseSelector parses identifying-attribute selector values into an exact-match
-// map. Each query-param value is exactly one "key=value" pair; repeat the
-// parameter (?selector=a=b&selector=c=d) to match multiple attributes. Commas are
-// NOT treated as delimiters, so attribute values may safely contain commas. The
-// value is split on the first "=" only, so it may also contain "=". Whitespace
-// around the whole entry and the key is trimmed; the attribute value is kept
-// verbatim. It returns an empty map (no filter) when no pairs are present, and
-// ErrInvalidSelector for a malformed entry.
-func parseSelector(values []string) (map[string]string, error) {
-	result := make(map[string]string)
-
-	for _, value := range values {
-		value = strings.TrimSpace(value)
-		if value == "" {
-			continue
-		}
+// ResendConfig offers an agent its current desired remote config again.
+//
+// @Summary  Resend Agent Remote Config
+// @Tags agent
+// @Description Re-deliver the agent's current desired remote config as an OpAMP offer, e.g. after
+// @Description the agent missed a push while disconnected. The agent group is left untouched.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  202 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/resend-config [post].
+func (c *Controller) ResendConfig(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
 
-		key, val, found := strings.Cut(value, "=")
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
 
-		key = strings.TrimSpace(key)
-		if !found || key == "" {
-			return nil, ErrInvalidSelector
-		}
+	agent, err := c.agentUsecase.ResendAgentRemoteConfig(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while resending the agent's remote config.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, agent)
+}
+
+// Reconnect closes an agent's connection so that it reconnects.
+//
+// @Summary  Reconnect Agent
+// @Tags agent
+// @Description Close the agent's OpAMP connection on the server holding it. The agent reconnects on its
+// @Description own and re-establishes its session, reporting its full state again.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  202 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/reconnect [post].
+func (c *Controller) Reconnect(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	agent, err := c.agentUsecase.ReconnectAgent(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while reconnecting the agent.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, agent)
+}
+
+// SetConfig sets remote configs on an agent directly, outside of any agent group.
+//
+// @Summary  Set Agent Config
+// @Tags agent
+// @Description Replace the remote configs set on the agent directly and offer the agent the result.
+// @Description They are delivered under keys prefixed with "@agent/", and agent group propagation
+// @Description keeps them. An empty config map removes them.
+// @Accept  json
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Param  config body v1.AgentConfigMap true "Remote configs keyed by name"
+// @Success  202 {object} v1.AgentDesiredConfig
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  409 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/config [put].
+func (c *Controller) SetConfig(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
 
-		result[key] = val
+		return
 	}
 
-	// A non-nil but empty map is treated as "no filter" by the persistence layer,
-	// so there is no need for a nil return here.
-	return result, nil
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	var req v1.AgentConfigMap
+
+	err = ginutil.BindJSON(ctx, &req)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	desiredConfig, err := c.agentUsecase.SetAgentDirectRemoteConfig(ctx.Request.Context(), namespace, instanceUID, &req)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while setting the agent's config.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, desiredConfig)
+}
+
+// GetDesiredConfig returns the remote config the server intends to offer an agent.
+//
+// @Summary  Get Agent Desired Config
+// @Tags agent
+// @Description Retrieve the merged remote config the server intends to offer the agent. Each
+// @Description entry names its source: the matching agent group it comes from, or the agent itself.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  200 {object} v1.AgentDesiredConfig
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/desired-config [get].
+func (c *Controller) GetDesiredConfig(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	desiredConfig, err := c.agentUsecase.GetAgentDesiredConfig(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while retrieving the agent's desired config.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, desiredConfig)
+}
+
+// WatchEffectiveConfig streams an agent's effective config as server-sent events.
+//
+// @Summary  Watch Agent Effective Config
+// @Tags agent
+// @Description Stream the agent's effective config as server-sent "effectiveConfig" events:
+// @Description first the current config, then the config each time the agent reports a
+// @Description different one. The stream stays open until the client disconnects.
+// @Produce  text/event-stream
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  200 {object} v1.AgentEffectiveConfig
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/effective-config/watch [get].
+func (c *Controller) WatchEffectiveConfig(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	configs, err := c.agentUsecase.WatchAgentEffectiveConfig(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleAgentError(ctx, err, "An error occurred while watching the agent's effective config.")
+
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+
+	// The channel closes once the client disconnects, as that cancels the request context.
+	for config := range configs {
+		ctx.SSEvent(EffectiveConfigEventName, config)
+		ctx.Writer.Flush()
+	}
 }
 
 // handleAgentError maps agent management errors to HTTP responses, centralising the
 // status mapping shared by Get/Update/Delete:
-//   - ErrAgentNamespaceMismatch -> 404 (the agent exists, but not in this namespace)
-//   - ErrAgentConnected          -> 409 (a connected agent cannot be deleted)
-//   - everything else            -> delegated to ginutil.HandleDomainError (404/500)
+//   - ErrAgentNamespaceMismatch    -> 404 (the agent exists, but not in this namespace)
+//   - ErrAgentConnected            -> 409 (a connected agent cannot be deleted)
+//   - ErrNewInstanceUIDInUse       -> 409 (another agent has or awaits the requested UID)
+//   - ErrUnsupportedAgentOperation -> 409 (the agent's capabilities rule the operation out)
+//   - ErrAgentHasNoRemoteConfig    -> 409 (there is no remote config to resend)
+//   - ErrAgentCommandQueueFull     -> 429 (the agent's pending command queue is at its cap)
+//   - everything else              -> delegated to ginutil.HandleDomainError (404/500)
 func (c *Controller) handleAgentError(ctx *gin.Context, err error, fallbackMessage string) {
 	switch {
 	case errors.Is(err, applicationport.ErrAgentNamespaceMismatch):
 		ginutil.ResourceNotFoundError(ctx, "agent", ctx.Param("id"))
 	case errors.Is(err, applicationport.ErrAgentConnected):
 		ginutil.ConflictError(ctx, err, "The agent is still connected and cannot be deleted.")
+	case errors.Is(err, applicationport.ErrNewInstanceUIDInUse):
+		ginutil.ConflictError(ctx, err, "The requested new instance UID is already in use by another agent.")
+	case errors.Is(err, applicationport.ErrUnsupportedAgentOperation):
+		ginutil.ConflictError(ctx, err, "The agent does not support the requested operation.")
+	case errors.Is(err, applicationport.ErrAgentHasNoRemoteConfig):
+		ginutil.ConflictError(ctx, err, "The agent has no remote config to resend.")
+	case errors.Is(err, applicationport.ErrAgentNotConnected):
+		ginutil.ConflictError(ctx, err, "The agent is not connected.")
+	case errors.Is(err, applicationport.ErrAgentCommandQueueFull):
+		ginutil.TooManyRequestsError(ctx, err,
+			"Too many commands are pending for the agent; retry once it has caught up.",
+			CommandQueueFullRetryAfter)
 	default:
 		c.logger.Error(fallbackMessage, "error", err.Error())
 		ginutil.HandleDomainError(ctx, err, fallbackMessage)
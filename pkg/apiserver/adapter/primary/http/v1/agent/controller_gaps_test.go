@@ -15,6 +15,7 @@ import (
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent/usecasemock"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
@@ -98,7 +99,7 @@ func TestAgentController_ListValidation(t *testing.T) {
 		usecase.On("ListAgents", mock.Anything, "default", mock.Anything).
 			Return(&v1.ListResponse[v1.Agent]{Items: []v1.Agent{}}, nil)
 
-		// The empty selector value exercises the "skip empty entry" branch of parseSelector.
+		// The empty selector value exercises the "skip empty entry" branch of ginutil.ParseSelector.
 		require.Equal(t, http.StatusOK,
 			gapReq(t, ctrlBase.Router, http.MethodGet, gapBase+"?selector=&selector=env=prod", "").Code)
 	})
@@ -270,6 +271,21 @@ func TestAgentController_Update(t *testing.T) {
 			gapReq(t, ctrlBase.Router, http.MethodPut, gapBase+"/"+uid.String(), `{not-json`).Code)
 	})
 
+	t.Run("malformed newInstanceUid", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase, usecase := gapSetup(t)
+		uid := uuid.New()
+		usecase.On("UpdateAgent", mock.Anything, "default", uid, mock.Anything).
+			Return(nil, &helper.ConversionError{Field: "spec.newInstanceUid", Value: "not-a-uuid", Err: errGapBoom})
+
+		// A conversion failure is a client error, not a 500 and not a silent zero value.
+		recorder := gapReq(t, ctrlBase.Router, http.MethodPut, gapBase+"/"+uid.String(),
+			`{"spec":{"newInstanceUid":"not-a-uuid"}}`)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "spec.newInstanceUid")
+	})
+
 	t.Run("usecase error", func(t *testing.T) {
 		t.Parallel()
 
@@ -1,10 +1,18 @@
 package agent_test
 
 import (
+	"bufio"
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,9 +23,13 @@ import (
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent/usecasemock"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
 func TestMain(m *testing.M) {
@@ -318,6 +330,464 @@ func TestAgentControllerListAgentSelectorThreading(t *testing.T) {
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
 
+func TestAgentControllerListAgentConnectionType(t *testing.T) {
+	t.Parallel()
+
+	t.Run("threads the canonical connection type", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		agentUsecase.EXPECT().
+			ListAgents(mock.Anything, "default", mock.MatchedBy(func(opts *applicationport.ListOptions) bool {
+				return opts != nil && opts.ConnectionType == v1.ConnectionTypeWebSocket
+			})).
+			Return(&v1.ListResponse[v1.Agent]{
+				APIVersion: "v1",
+				Kind:       v1.AgentKind,
+				Items:      []v1.Agent{},
+				Metadata:   v1.ListMeta{RemainingItemCount: 0, Continue: ""},
+			}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents?connectionType=websocket", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("rejects an unknown connection type", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents?connectionType=grpc", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "query.connectionType", gjson.Get(recorder.Body.String(), "errors.0.location").String())
+	})
+}
+
+func TestAgentControllerListCapabilities(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists agents with the requested capability", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		acceptsPackages, ok := v1.ParseAgentCapability("AcceptsPackages")
+		require.True(t, ok)
+
+		instanceUID := uuid.New()
+		capabilities := v1.AgentCapabilities(1) | acceptsPackages
+		agentUsecase.EXPECT().
+			ListAgentCapabilities(mock.Anything, mock.MatchedBy(func(opts *applicationport.ListOptions) bool {
+				return opts != nil && opts.Capabilities == uint64(acceptsPackages)
+			})).
+			Return(&v1.ListResponse[v1.AgentCapabilitySummary]{
+				APIVersion: "v1",
+				Kind:       v1.AgentCapabilitySummaryKind,
+				Items: []v1.AgentCapabilitySummary{{
+					Namespace:       "default",
+					InstanceUID:     instanceUID,
+					Type:            "otelcol",
+					Capabilities:    capabilities,
+					CapabilityNames: capabilities.Names(),
+				}},
+				Metadata: v1.ListMeta{RemainingItemCount: 0, Continue: ""},
+			}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/agents/capabilities?capability=acceptspackages", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, instanceUID.String(), gjson.Get(recorder.Body.String(), "items.0.instanceUid").String())
+		assert.Equal(t, `["ReportsStatus","AcceptsPackages"]`,
+			gjson.Get(recorder.Body.String(), "items.0.capabilityNames").Raw)
+	})
+
+	t.Run("rejects an unknown capability", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/agents/capabilities?capability=AcceptsEverything", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "query.capability", gjson.Get(recorder.Body.String(), "errors.0.location").String())
+	})
+}
+
+func TestAgentControllerListAttributes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists the attribute keys with the requested number of values", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		agentUsecase.EXPECT().
+			ListAgentAttributes(mock.Anything, 2).
+			Return(&v1.AgentAttributeCatalog{
+				IdentifyingAttributes: []v1.AgentAttribute{
+					{Key: "service.name", Values: []string{"db", "web"}, ValuesTruncated: true},
+				},
+				NonIdentifyingAttributes: []v1.AgentAttribute{},
+			}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/agents/attributes?values=2", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "service.name", gjson.Get(recorder.Body.String(), "identifyingAttributes.0.key").String())
+		assert.Equal(t, `["db","web"]`, gjson.Get(recorder.Body.String(), "identifyingAttributes.0.values").Raw)
+		assert.True(t, gjson.Get(recorder.Body.String(), "identifyingAttributes.0.valuesTruncated").Bool())
+	})
+
+	t.Run("rejects a negative number of values", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/agents/attributes?values=-1", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "query.values", gjson.Get(recorder.Body.String(), "errors.0.location").String())
+	})
+}
+
+func TestAgentControllerListAgentNDJSONStream(t *testing.T) {
+	t.Parallel()
+
+	const seeded = 5
+
+	agents := make([]v1.Agent, 0, seeded)
+	for range seeded {
+		//exhaustruct:ignore
+		agents = append(agents, v1.Agent{Metadata: v1.AgentMetadata{InstanceUID: uuid.New()}})
+	}
+
+	// pagedListAgents serves the seeded agents in pages of opts.Limit, resuming from the
+	// offset encoded in the continue token, the way the persistence cursor does.
+	pagedListAgents := func(
+		_ context.Context, _ string, opts *applicationport.ListOptions,
+	) (*v1.ListResponse[v1.Agent], error) {
+		start := 0
+		if opts.Continue != "" {
+			start, _ = strconv.Atoi(opts.Continue)
+		}
+
+		end := min(start+int(opts.Limit), len(agents))
+		next := ""
+
+		if end < len(agents) {
+			next = strconv.Itoa(end)
+		}
+
+		return &v1.ListResponse[v1.Agent]{
+			APIVersion: "v1",
+			Kind:       v1.AgentKind,
+			Items:      agents[start:end],
+			Metadata:   v1.ListMeta{Continue: next, RemainingItemCount: int64(len(agents) - end)},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+	}{
+		{name: "stream query parameter", query: "?stream=ndjson&limit=2", accept: ""},
+		{name: "Accept header", query: "?limit=2", accept: "application/x-ndjson"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			ctrlBase.SetupRouter(agent.NewController(agentUsecase, ctrlBase.Logger))
+
+			agentUsecase.EXPECT().
+				ListAgents(mock.Anything, "default", mock.Anything).
+				RunAndReturn(pagedListAgents).
+				Times(3)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents"+tt.query, nil,
+			)
+			require.NoError(t, err)
+
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			ctrlBase.Router.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+
+			lines := 0
+			scanner := bufio.NewScanner(recorder.Body)
+
+			for scanner.Scan() {
+				assert.Equal(t, agents[lines].Metadata.InstanceUID.String(),
+					gjson.Get(scanner.Text(), "metadata.instanceUid").String())
+
+				lines++
+			}
+
+			require.NoError(t, scanner.Err())
+			assert.Equal(t, seeded, lines)
+		})
+	}
+
+	t.Run("unsupported stream format is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		ctrlBase.SetupRouter(agent.NewController(usecasemock.NewMockManageUsecase(t), ctrlBase.Logger))
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents?stream=csv", nil,
+		)
+		require.NoError(t, err)
+
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentControllerRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	agentUsecase := usecasemock.NewMockManageUsecase(t)
+	controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+
+	router := gin.New()
+	router.Use(ginutil.NewRequestTimeoutMiddleware(20*time.Millisecond, nil))
+
+	for _, route := range controller.RoutesInfo() {
+		router.Handle(route.Method, route.Path, route.HandlerFunc)
+	}
+
+	// given: a usecase that only returns once the request context gives up, as a
+	// persistence call stuck on a slow database does.
+	agentUsecase.EXPECT().
+		GetAgent(mock.Anything, "default", mock.Anything).
+		RunAndReturn(func(ctx context.Context, _ string, _ uuid.UUID) (*v1.Agent, error) {
+			<-ctx.Done()
+
+			return nil, fmt.Errorf("find agent: %w", ctx.Err())
+		})
+
+	// when
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(), http.MethodGet,
+		"/api/v1/namespaces/default/agents/"+uuid.New().String(), nil,
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+
+	// then
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.Equal(t, int64(http.StatusGatewayTimeout), gjson.Get(recorder.Body.String(), "status").Int())
+	assert.Equal(t, "Gateway Timeout", gjson.Get(recorder.Body.String(), "title").String())
+}
+
+func TestAgentControllerUnsupportedAccept(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	// no expectations: the usecase must not be reached.
+	agentUsecase := usecasemock.NewMockManageUsecase(t)
+	controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+
+	router := gin.New()
+	router.Use(ginutil.NewContentNegotiationMiddleware(nil))
+
+	for _, route := range controller.RoutesInfo() {
+		router.Handle(route.Method, route.Path, route.HandlerFunc)
+	}
+
+	// when
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/xml")
+	router.ServeHTTP(recorder, req)
+
+	// then
+	body := recorder.Body.String()
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	assert.Equal(t, int64(http.StatusNotAcceptable), gjson.Get(body, "status").Int())
+	assert.Equal(t, "Not Acceptable", gjson.Get(body, "title").String())
+	assert.Equal(t, "header.Accept", gjson.Get(body, "errors.0.location").String())
+	assert.ElementsMatch(t,
+		[]any{"application/json", "application/problem+json", "application/x-ndjson"},
+		gjson.Get(body, "errors.0.value").Value(),
+	)
+}
+
+func TestAgentControllerLargeIntsAsStrings(t *testing.T) {
+	t.Parallel()
+
+	// 2^53+1 is the first integer a JavaScript number cannot hold.
+	const largeSequenceNum uint64 = 1<<53 + 1
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		quoted bool
+	}{
+		{name: "not requested", quoted: false},
+		{name: "query param", query: "?largeIntsAsStrings=true", quoted: true},
+		{name: "accept profile", accept: `application/json; profile="large-ints-as-strings"`, quoted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+
+			router := gin.New()
+			router.Use(ginutil.NewLargeIntsAsStringsMiddleware())
+
+			for _, route := range controller.RoutesInfo() {
+				router.Handle(route.Method, route.Path, route.HandlerFunc)
+			}
+
+			// given
+			instanceUID := uuid.New()
+			agentUsecase.EXPECT().
+				GetAgent(mock.Anything, "default", instanceUID).
+				Return(
+					//exhaustruct:ignore
+					&v1.Agent{
+						Metadata: v1.AgentMetadata{InstanceUID: instanceUID},
+						Status:   v1.AgentStatus{SequenceNum: largeSequenceNum},
+					}, nil)
+
+			// when
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodGet,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+test.query, nil,
+			)
+			require.NoError(t, err)
+
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			// then
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			sequenceNum := gjson.Get(recorder.Body.String(), "status.sequenceNum")
+			if test.quoted {
+				assert.Equal(t, gjson.String, sequenceNum.Type)
+				assert.Equal(t, strconv.FormatUint(largeSequenceNum, 10), sequenceNum.Str)
+			} else {
+				assert.Equal(t, gjson.Number, sequenceNum.Type)
+			}
+
+			assert.Equal(t, instanceUID.String(), gjson.Get(recorder.Body.String(), "metadata.instanceUid").String())
+		})
+	}
+}
+
+func TestAgentControllerGetAgentTypeMeta(t *testing.T) {
+	t.Parallel()
+
+	for name, fields := range map[string]string{
+		"full object":      "",
+		"projected object": "?fields=metadata.instanceUid",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+			ctrlBase.SetupRouter(controller)
+			router := ctrlBase.Router
+
+			instanceUID := uuid.New()
+			mapper := helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness)
+			agentUsecase.EXPECT().
+				GetAgent(mock.Anything, "default", instanceUID).
+				Return(mapper.MapAgentToAPI(agentmodel.NewAgent(instanceUID)), nil)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodGet,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+fields, nil,
+			)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, v1.AgentKind, gjson.Get(recorder.Body.String(), "kind").String())
+			assert.Equal(t, v1.APIVersion, gjson.Get(recorder.Body.String(), "apiVersion").String())
+		})
+	}
+}
+
 func TestAgentControllerGetAgent(t *testing.T) {
 	t.Parallel()
 	t.Run("Get Agent - happycase", func(t *testing.T) {
@@ -451,6 +921,81 @@ func TestAgentControllerGetAgent(t *testing.T) {
 		router.ServeHTTP(recorder, req)
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 	})
+
+	t.Run("Get Agent - fields returns only the selected paths", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// given
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			GetAgent(mock.Anything, "default", instanceUID).
+			Return(
+				//exhaustruct:ignore
+				&v1.Agent{
+					Metadata: v1.AgentMetadata{
+						InstanceUID: instanceUID,
+					},
+					Status: v1.AgentStatus{
+						EffectiveConfig: v1.AgentEffectiveConfig{
+							ConfigMap: v1.AgentConfigMap{
+								ConfigMap: map[string]v1.AgentConfigFile{
+									"config.yaml": {Body: "receivers: {}", ContentType: "text/yaml"},
+								},
+							},
+						},
+						ComponentHealth: v1.AgentComponentHealth{Healthy: true},
+						Connected:       true,
+					},
+				}, nil)
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String()+"?fields=metadata,status.componentHealth", nil,
+		)
+		require.NoError(t, err)
+		// then
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		body := recorder.Body.String()
+		assert.Equal(t, instanceUID.String(), gjson.Get(body, "metadata.instanceUid").String())
+		assert.True(t, gjson.Get(body, "status.componentHealth.healthy").Bool())
+		assert.False(t, gjson.Get(body, "spec").Exists())
+		assert.False(t, gjson.Get(body, "status.effectiveConfig").Exists())
+		assert.False(t, gjson.Get(body, "status.connected").Exists())
+	})
+
+	t.Run("Get Agent - unknown field returns 400", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/"+uuid.NewString()+"?fields=metadata,status.nope", nil,
+		)
+		require.NoError(t, err)
+		// then
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body := recorder.Body.String()
+		assert.Contains(t, body, "invalid value")
+		assert.Contains(t, body, "query.fields")
+	})
 }
 
 func TestAgentControllerDeleteAgent(t *testing.T) {
@@ -514,7 +1059,7 @@ func TestAgentControllerDeleteAgent(t *testing.T) {
 		assert.Contains(t, body, "Conflict")
 	})
 
-	t.Run("Delete Agent - namespace mismatch returns 404", func(t *testing.T) {
+	t.Run("Update Agent - new instance UID in use returns 409", func(t *testing.T) {
 		t.Parallel()
 
 		ctrlBase := testutil.NewBase(t).ForController()
@@ -527,21 +1072,23 @@ func TestAgentControllerDeleteAgent(t *testing.T) {
 		instanceUID := uuid.New()
 
 		agentUsecase.EXPECT().
-			DeleteAgent(mock.Anything, "default", mock.Anything).
-			Return(applicationport.ErrAgentNamespaceMismatch)
+			UpdateAgent(mock.Anything, "default", instanceUID, mock.Anything).
+			Return(nil, applicationport.ErrNewInstanceUIDInUse)
 		// when
 		recorder := httptest.NewRecorder()
 		req, err := http.NewRequestWithContext(
-			t.Context(), http.MethodDelete,
-			"/api/v1/namespaces/default/agents/"+instanceUID.String(), nil,
+			t.Context(), http.MethodPut,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String(),
+			strings.NewReader(`{"spec":{"newInstanceUid":"`+uuid.New().String()+`"}}`),
 		)
 		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
 		// then
 		router.ServeHTTP(recorder, req)
-		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
 	})
 
-	t.Run("Delete Agent - not found returns 404", func(t *testing.T) {
+	t.Run("Delete Agent - namespace mismatch returns 404", func(t *testing.T) {
 		t.Parallel()
 
 		ctrlBase := testutil.NewBase(t).ForController()
@@ -555,7 +1102,34 @@ func TestAgentControllerDeleteAgent(t *testing.T) {
 
 		agentUsecase.EXPECT().
 			DeleteAgent(mock.Anything, "default", mock.Anything).
-			Return(model.ErrResourceNotExist)
+			Return(applicationport.ErrAgentNamespaceMismatch)
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodDelete,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String(), nil,
+		)
+		require.NoError(t, err)
+		// then
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Delete Agent - not found returns 404", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// given
+		instanceUID := uuid.New()
+
+		agentUsecase.EXPECT().
+			DeleteAgent(mock.Anything, "default", mock.Anything).
+			Return(model.ErrResourceNotExist)
 		// when
 		recorder := httptest.NewRecorder()
 		req, err := http.NewRequestWithContext(
@@ -695,3 +1269,646 @@ func TestAgentControllerSearchAgent(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, recorder.Code)
 	})
 }
+
+func TestAgentControllerListAgentsByPackage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("List Agents by Package - happy case", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// given
+		instanceUID := uuid.MustParse("12345678-1234-1234-1234-123456789012")
+		//exhaustruct:ignore
+		agents := []v1.Agent{
+			{
+				Metadata: v1.AgentMetadata{
+					InstanceUID: instanceUID,
+				},
+			},
+		}
+		agentUsecase.EXPECT().
+			ListAgentsByPackage(mock.Anything, "default", "otelcol", "0.114.0",
+				mock.MatchedBy(func(options *applicationport.ListOptions) bool {
+					return options.Limit == 10 && options.ConnectedOnly
+				})).
+			Return(&v1.ListResponse[v1.Agent]{
+				APIVersion: "v1",
+				Kind:       v1.AgentKind,
+				Items:      agents,
+				Metadata: v1.ListMeta{
+					RemainingItemCount: 0,
+					Continue:           "",
+				},
+			}, nil)
+
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/by-package?name=otelcol&version=0.114.0&limit=10&connected=true", nil,
+		)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		result := gjson.Parse(recorder.Body.String())
+		assert.Equal(t, int64(1), result.Get("items.#").Int())
+		assert.Equal(t, instanceUID.String(), result.Get("items.0.metadata.instanceUid").String())
+	})
+
+	t.Run("List Agents by Package - version is optional", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// given
+		agentUsecase.EXPECT().
+			ListAgentsByPackage(mock.Anything, "default", "otelcol", "", mock.Anything).
+			Return(&v1.ListResponse[v1.Agent]{
+				APIVersion: "v1",
+				Kind:       v1.AgentKind,
+				Items:      []v1.Agent{},
+				Metadata:   v1.ListMeta{RemainingItemCount: 0, Continue: ""},
+			}, nil)
+
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/by-package?name=otelcol", nil,
+		)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("List Agents by Package - missing package name", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		// when
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/by-package?version=0.114.0", nil,
+		)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentController_RequestReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("each endpoint requests its kind", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		endpoints := map[string]v1.AgentReportKind{
+			"effective-config":     v1.AgentReportKindEffectiveConfig,
+			"health":               v1.AgentReportKindHealth,
+			"available-components": v1.AgentReportKindAvailableComponents,
+		}
+
+		for path, kind := range endpoints {
+			//exhaustruct:ignore
+			agentUsecase.EXPECT().
+				RequestAgentReport(mock.Anything, "default", instanceUID, kind).
+				Return(&v1.Agent{Spec: v1.AgentSpec{PendingReports: []v1.AgentReportKind{kind}}}, nil).
+				Once()
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodPost,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/report/"+path,
+				nil,
+			)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusAccepted, recorder.Code, path)
+		}
+	})
+
+	t.Run("unsupported by the agent returns 409", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+
+		agentUsecase.EXPECT().
+			RequestAgentReport(mock.Anything, "default", instanceUID, v1.AgentReportKindHealth).
+			Return(nil, applicationport.ErrUnsupportedAgentOperation)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/report/health",
+			nil,
+		)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("full command queue returns 429", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+
+		agentUsecase.EXPECT().
+			RequestAgentReport(mock.Anything, "default", instanceUID, v1.AgentReportKindHealth).
+			Return(nil, applicationport.ErrAgentCommandQueueFull)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/report/health",
+			nil,
+		)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	})
+}
+
+func TestAgentController_ResendConfig(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		err      error
+		expected int
+	}{
+		"accepted":             {err: nil, expected: http.StatusAccepted},
+		"no remote config":     {err: applicationport.ErrAgentHasNoRemoteConfig, expected: http.StatusConflict},
+		"agent does not exist": {err: model.ErrResourceNotExist, expected: http.StatusNotFound},
+		"other namespace":      {err: applicationport.ErrAgentNamespaceMismatch, expected: http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+			ctrlBase.SetupRouter(controller)
+			router := ctrlBase.Router
+
+			instanceUID := uuid.New()
+
+			var result *v1.Agent
+			if tc.err == nil {
+				//exhaustruct:ignore
+				result = &v1.Agent{}
+			}
+
+			agentUsecase.EXPECT().
+				ResendAgentRemoteConfig(mock.Anything, "default", instanceUID).
+				Return(result, tc.err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodPost,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/resend-config",
+				nil,
+			)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expected, recorder.Code)
+		})
+	}
+}
+
+func TestAgentController_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		err      error
+		expected int
+	}{
+		"accepted":             {err: nil, expected: http.StatusAccepted},
+		"not connected":        {err: applicationport.ErrAgentNotConnected, expected: http.StatusConflict},
+		"agent does not exist": {err: model.ErrResourceNotExist, expected: http.StatusNotFound},
+		"other namespace":      {err: applicationport.ErrAgentNamespaceMismatch, expected: http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+			ctrlBase.SetupRouter(controller)
+			router := ctrlBase.Router
+
+			instanceUID := uuid.New()
+
+			var result *v1.Agent
+			if tc.err == nil {
+				//exhaustruct:ignore
+				result = &v1.Agent{}
+			}
+
+			agentUsecase.EXPECT().
+				ReconnectAgent(mock.Anything, "default", instanceUID).
+				Return(result, tc.err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodPost,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/reconnect",
+				nil,
+			)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expected, recorder.Code)
+		})
+	}
+}
+
+func TestAgentController_GetDesiredConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Entries carry their source", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			GetAgentDesiredConfig(mock.Anything, "default", instanceUID).
+			Return(&v1.AgentDesiredConfig{
+				Namespace:   "default",
+				InstanceUID: instanceUID,
+				ConfigMap: map[string]v1.AgentDesiredConfigEntry{
+					"collector.yaml": {
+						Body:        "receivers: {}",
+						ContentType: "text/yaml",
+						Source: v1.AgentDesiredConfigSource{
+							Kind:      v1.AgentDesiredConfigSourceAgentGroup,
+							Namespace: "default",
+							Name:      "production",
+						},
+					},
+					"debug.yaml": {
+						Body:        "exporters: {}",
+						ContentType: "text/yaml",
+						Source: v1.AgentDesiredConfigSource{
+							Kind:      v1.AgentDesiredConfigSourceAgent,
+							Namespace: "",
+							Name:      "",
+						},
+					},
+				},
+			}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents/"+instanceUID.String()+"/desired-config", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		body := recorder.Body.String()
+		assert.Equal(t, instanceUID.String(), gjson.Get(body, "instanceUid").String())
+		assert.Equal(t, "receivers: {}", gjson.Get(body, "configMap.collector\\.yaml.body").String())
+		assert.Equal(t, "AgentGroup", gjson.Get(body, "configMap.collector\\.yaml.source.kind").String())
+		assert.Equal(t, "production", gjson.Get(body, "configMap.collector\\.yaml.source.name").String())
+		assert.Equal(t, "Agent", gjson.Get(body, "configMap.debug\\.yaml.source.kind").String())
+		assert.False(t, gjson.Get(body, "configMap.debug\\.yaml.source.name").Exists())
+	})
+
+	t.Run("Agent does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			GetAgentDesiredConfig(mock.Anything, "default", instanceUID).
+			Return(nil, model.ErrResourceNotExist)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/default/agents/"+instanceUID.String()+"/desired-config", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			GetAgentDesiredConfig(mock.Anything, "other", instanceUID).
+			Return(nil, applicationport.ErrAgentNamespaceMismatch)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(
+			t.Context(), http.MethodGet, "/api/v1/namespaces/other/agents/"+instanceUID.String()+"/desired-config", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAgentController_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "Accepted", err: nil, expected: http.StatusAccepted},
+		{name: "Agent does not exist", err: model.ErrResourceNotExist, expected: http.StatusNotFound},
+		{name: "Agent in another namespace", err: applicationport.ErrAgentNamespaceMismatch, expected: http.StatusNotFound},
+		{
+			name:     "Agent does not accept remote config",
+			err:      fmt.Errorf("wrapped: %w", applicationport.ErrUnsupportedAgentOperation),
+			expected: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			agentUsecase := usecasemock.NewMockManageUsecase(t)
+			controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+			ctrlBase.SetupRouter(controller)
+			router := ctrlBase.Router
+
+			instanceUID := uuid.New()
+			expectedConfig := &v1.AgentConfigMap{
+				ConfigMap: map[string]v1.AgentConfigFile{
+					"debug": {Body: "log_level: debug", ContentType: "text/yaml", Primary: false},
+				},
+			}
+
+			var result *v1.AgentDesiredConfig
+			if tc.err == nil {
+				result = &v1.AgentDesiredConfig{
+					Namespace:   "default",
+					InstanceUID: instanceUID,
+					ConfigMap: map[string]v1.AgentDesiredConfigEntry{
+						"@agent/debug": {
+							Body:        "log_level: debug",
+							ContentType: "text/yaml",
+							Source: v1.AgentDesiredConfigSource{
+								Kind:      v1.AgentDesiredConfigSourceAgent,
+								Namespace: "",
+								Name:      "",
+							},
+						},
+					},
+				}
+			}
+
+			agentUsecase.EXPECT().
+				SetAgentDirectRemoteConfig(mock.Anything, "default", instanceUID, expectedConfig).
+				Return(result, tc.err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(
+				t.Context(), http.MethodPut,
+				"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/config",
+				strings.NewReader(`{"configMap":{"debug":{"body":"log_level: debug","contentType":"text/yaml"}}}`),
+			)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expected, recorder.Code)
+
+			if tc.err == nil {
+				assert.Equal(t, "Agent",
+					gjson.Get(recorder.Body.String(), "configMap.@agent/debug.source.kind").String())
+			}
+		})
+	}
+}
+
+func TestAgentController_WatchEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Streams every config as an event", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		configWithBody := func(body string) *v1.AgentEffectiveConfig {
+			return &v1.AgentEffectiveConfig{
+				ConfigMap: v1.AgentConfigMap{
+					ConfigMap: map[string]v1.AgentConfigFile{
+						"collector.yaml": {Body: body, ContentType: "text/yaml", Primary: false},
+					},
+				},
+			}
+		}
+
+		// The usecase emits the snapshot and one change, then ends the watch.
+		configs := make(chan *v1.AgentEffectiveConfig, 2)
+		configs <- configWithBody("a: 1")
+		configs <- configWithBody("a: 2")
+		close(configs)
+
+		agentUsecase.EXPECT().
+			WatchAgentEffectiveConfig(mock.Anything, "default", instanceUID).
+			Return(configs, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/effective-config/watch", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/event-stream")
+
+		body := recorder.Body.String()
+		assert.Equal(t, 2, strings.Count(body, "event:"+agent.EffectiveConfigEventName+"\n"))
+
+		var data []string
+
+		scanner := bufio.NewScanner(strings.NewReader(body))
+		for scanner.Scan() {
+			if payload, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				data = append(data, payload)
+			}
+		}
+
+		require.Len(t, data, 2)
+		assert.Equal(t, "a: 1", gjson.Get(data[0], "configMap.configMap.collector\\.yaml.body").String())
+		assert.Equal(t, "a: 2", gjson.Get(data[1], "configMap.configMap.collector\\.yaml.body").String())
+	})
+
+	t.Run("Agent does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			WatchAgentEffectiveConfig(mock.Anything, "default", instanceUID).
+			Return(nil, model.ErrResourceNotExist)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/effective-config/watch", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		agentUsecase := usecasemock.NewMockManageUsecase(t)
+		controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		agentUsecase.EXPECT().
+			WatchAgentEffectiveConfig(mock.Anything, "other", instanceUID).
+			Return(nil, applicationport.ErrAgentNamespaceMismatch)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"/api/v1/namespaces/other/agents/"+instanceUID.String()+"/effective-config/watch", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAgentController_MalformedInstanceUIDIsRejectedOnEveryRoute(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	// No usecase expectation: a malformed ID must be rejected before the handler runs.
+	agentUsecase := usecasemock.NewMockManageUsecase(t)
+	controller := agent.NewController(agentUsecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s"},
+		{http.MethodPut, "/api/v1/namespaces/default/agents/%s"},
+		{http.MethodDelete, "/api/v1/namespaces/default/agents/%s"},
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s/endpoints"},
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s/uptime"},
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s/effective-config/history"},
+		{http.MethodPost, "/api/v1/namespaces/default/agents/%s/report/health"},
+		{http.MethodPost, "/api/v1/namespaces/default/agents/%s/resend-config"},
+		{http.MethodPost, "/api/v1/namespaces/default/agents/%s/reconnect"},
+		{http.MethodPut, "/api/v1/namespaces/default/agents/%s/config"},
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s/desired-config"},
+		{http.MethodGet, "/api/v1/namespaces/default/agents/%s/effective-config/watch"},
+	}
+	malformedIDs := []string{
+		"not-a-uuid",
+		"550e8400-e29b-41d4-a716-44665544000g",
+		"{550e8400-e29b-41d4-a716-446655440000}",
+		"urn:uuid:550e8400-e29b-41d4-a716-446655440000",
+	}
+
+	for _, route := range routes {
+		for _, id := range malformedIDs {
+			t.Run(route.method+" "+fmt.Sprintf(route.path, id), func(t *testing.T) {
+				t.Parallel()
+
+				req, err := http.NewRequestWithContext(t.Context(), route.method,
+					fmt.Sprintf(route.path, url.PathEscape(id)), strings.NewReader(`{}`))
+				require.NoError(t, err)
+
+				recorder := httptest.NewRecorder()
+				ctrlBase.Router.ServeHTTP(recorder, req)
+
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				body := recorder.Body.String()
+				assert.Equal(t, "path.id", gjson.Get(body, "errors.0.location").String())
+				assert.Equal(t, id, gjson.Get(body, "errors.0.value").String())
+			})
+		}
+	}
+}
@@ -177,24 +177,616 @@ func (_c *MockManageUsecase_GetAgent_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// GetAgentDesiredConfig provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) GetAgentDesiredConfig(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentDesiredConfig, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentDesiredConfig")
+	}
+
+	var r0 *v1.AgentDesiredConfig
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.AgentDesiredConfig, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.AgentDesiredConfig); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentDesiredConfig)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_GetAgentDesiredConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAgentDesiredConfig'
+type MockManageUsecase_GetAgentDesiredConfig_Call struct {
+	*mock.Call
+}
+
+// GetAgentDesiredConfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) GetAgentDesiredConfig(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_GetAgentDesiredConfig_Call {
+	return &MockManageUsecase_GetAgentDesiredConfig_Call{Call: _e.mock.On("GetAgentDesiredConfig", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_GetAgentDesiredConfig_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_GetAgentDesiredConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentDesiredConfig_Call) Return(agentDesiredConfig *v1.AgentDesiredConfig, err error) *MockManageUsecase_GetAgentDesiredConfig_Call {
+	_c.Call.Return(agentDesiredConfig, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentDesiredConfig_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentDesiredConfig, error)) *MockManageUsecase_GetAgentDesiredConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAgentEffectiveConfigHistory provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) GetAgentEffectiveConfigHistory(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentEffectiveConfigHistory, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentEffectiveConfigHistory")
+	}
+
+	var r0 *v1.AgentEffectiveConfigHistory
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.AgentEffectiveConfigHistory, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.AgentEffectiveConfigHistory); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentEffectiveConfigHistory)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_GetAgentEffectiveConfigHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAgentEffectiveConfigHistory'
+type MockManageUsecase_GetAgentEffectiveConfigHistory_Call struct {
+	*mock.Call
+}
+
+// GetAgentEffectiveConfigHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) GetAgentEffectiveConfigHistory(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_GetAgentEffectiveConfigHistory_Call {
+	return &MockManageUsecase_GetAgentEffectiveConfigHistory_Call{Call: _e.mock.On("GetAgentEffectiveConfigHistory", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_GetAgentEffectiveConfigHistory_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_GetAgentEffectiveConfigHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentEffectiveConfigHistory_Call) Return(agentEffectiveConfigHistory *v1.AgentEffectiveConfigHistory, err error) *MockManageUsecase_GetAgentEffectiveConfigHistory_Call {
+	_c.Call.Return(agentEffectiveConfigHistory, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentEffectiveConfigHistory_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentEffectiveConfigHistory, error)) *MockManageUsecase_GetAgentEffectiveConfigHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAgentUptime provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) GetAgentUptime(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentUptime, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentUptime")
+	}
+
+	var r0 *v1.AgentUptime
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.AgentUptime, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.AgentUptime); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentUptime)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_GetAgentUptime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAgentUptime'
+type MockManageUsecase_GetAgentUptime_Call struct {
+	*mock.Call
+}
+
+// GetAgentUptime is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) GetAgentUptime(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_GetAgentUptime_Call {
+	return &MockManageUsecase_GetAgentUptime_Call{Call: _e.mock.On("GetAgentUptime", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_GetAgentUptime_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_GetAgentUptime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentUptime_Call) Return(agentUptime *v1.AgentUptime, err error) *MockManageUsecase_GetAgentUptime_Call {
+	_c.Call.Return(agentUptime, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_GetAgentUptime_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentUptime, error)) *MockManageUsecase_GetAgentUptime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAgentAttributes provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ListAgentAttributes(ctx context.Context, valueLimit int) (*v1.AgentAttributeCatalog, error) {
+	ret := _mock.Called(ctx, valueLimit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgentAttributes")
+	}
+
+	var r0 *v1.AgentAttributeCatalog
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*v1.AgentAttributeCatalog, error)); ok {
+		return returnFunc(ctx, valueLimit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *v1.AgentAttributeCatalog); ok {
+		r0 = returnFunc(ctx, valueLimit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentAttributeCatalog)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, valueLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ListAgentAttributes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentAttributes'
+type MockManageUsecase_ListAgentAttributes_Call struct {
+	*mock.Call
+}
+
+// ListAgentAttributes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - valueLimit int
+func (_e *MockManageUsecase_Expecter) ListAgentAttributes(ctx interface{}, valueLimit interface{}) *MockManageUsecase_ListAgentAttributes_Call {
+	return &MockManageUsecase_ListAgentAttributes_Call{Call: _e.mock.On("ListAgentAttributes", ctx, valueLimit)}
+}
+
+func (_c *MockManageUsecase_ListAgentAttributes_Call) Run(run func(ctx context.Context, valueLimit int)) *MockManageUsecase_ListAgentAttributes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentAttributes_Call) Return(agentAttributeCatalog *v1.AgentAttributeCatalog, err error) *MockManageUsecase_ListAgentAttributes_Call {
+	_c.Call.Return(agentAttributeCatalog, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentAttributes_Call) RunAndReturn(run func(ctx context.Context, valueLimit int) (*v1.AgentAttributeCatalog, error)) *MockManageUsecase_ListAgentAttributes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAgentCapabilities provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ListAgentCapabilities(ctx context.Context, options *port.ListOptions) (*v1.ListResponse[v1.AgentCapabilitySummary], error) {
+	ret := _mock.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgentCapabilities")
+	}
+
+	var r0 *v1.ListResponse[v1.AgentCapabilitySummary]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *port.ListOptions) (*v1.ListResponse[v1.AgentCapabilitySummary], error)); ok {
+		return returnFunc(ctx, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *port.ListOptions) *v1.ListResponse[v1.AgentCapabilitySummary]); ok {
+		r0 = returnFunc(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ListResponse[v1.AgentCapabilitySummary])
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *port.ListOptions) error); ok {
+		r1 = returnFunc(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ListAgentCapabilities_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentCapabilities'
+type MockManageUsecase_ListAgentCapabilities_Call struct {
+	*mock.Call
+}
+
+// ListAgentCapabilities is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options *port.ListOptions
+func (_e *MockManageUsecase_Expecter) ListAgentCapabilities(ctx interface{}, options interface{}) *MockManageUsecase_ListAgentCapabilities_Call {
+	return &MockManageUsecase_ListAgentCapabilities_Call{Call: _e.mock.On("ListAgentCapabilities", ctx, options)}
+}
+
+func (_c *MockManageUsecase_ListAgentCapabilities_Call) Run(run func(ctx context.Context, options *port.ListOptions)) *MockManageUsecase_ListAgentCapabilities_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *port.ListOptions
+		if args[1] != nil {
+			arg1 = args[1].(*port.ListOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentCapabilities_Call) Return(listResponse *v1.ListResponse[v1.AgentCapabilitySummary], err error) *MockManageUsecase_ListAgentCapabilities_Call {
+	_c.Call.Return(listResponse, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentCapabilities_Call) RunAndReturn(run func(ctx context.Context, options *port.ListOptions) (*v1.ListResponse[v1.AgentCapabilitySummary], error)) *MockManageUsecase_ListAgentCapabilities_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAgentEndpoints provides a mock function for the type MockManageUsecase
 func (_mock *MockManageUsecase) ListAgentEndpoints(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.ListResponse[v1.Endpoint], error) {
 	ret := _mock.Called(ctx, namespace, instanceUID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAgentEndpoints")
+		panic("no return value specified for ListAgentEndpoints")
+	}
+
+	var r0 *v1.ListResponse[v1.Endpoint]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.ListResponse[v1.Endpoint], error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.ListResponse[v1.Endpoint]); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ListResponse[v1.Endpoint])
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ListAgentEndpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentEndpoints'
+type MockManageUsecase_ListAgentEndpoints_Call struct {
+	*mock.Call
+}
+
+// ListAgentEndpoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) ListAgentEndpoints(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_ListAgentEndpoints_Call {
+	return &MockManageUsecase_ListAgentEndpoints_Call{Call: _e.mock.On("ListAgentEndpoints", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_ListAgentEndpoints_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_ListAgentEndpoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentEndpoints_Call) Return(listResponse *v1.ListResponse[v1.Endpoint], err error) *MockManageUsecase_ListAgentEndpoints_Call {
+	_c.Call.Return(listResponse, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentEndpoints_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.ListResponse[v1.Endpoint], error)) *MockManageUsecase_ListAgentEndpoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAgents provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ListAgents(ctx context.Context, namespace string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error) {
+	ret := _mock.Called(ctx, namespace, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgents")
+	}
+
+	var r0 *v1.ListResponse[v1.Agent]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *port.ListOptions) (*v1.ListResponse[v1.Agent], error)); ok {
+		return returnFunc(ctx, namespace, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *port.ListOptions) *v1.ListResponse[v1.Agent]); ok {
+		r0 = returnFunc(ctx, namespace, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ListResponse[v1.Agent])
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *port.ListOptions) error); ok {
+		r1 = returnFunc(ctx, namespace, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ListAgents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgents'
+type MockManageUsecase_ListAgents_Call struct {
+	*mock.Call
+}
+
+// ListAgents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - options *port.ListOptions
+func (_e *MockManageUsecase_Expecter) ListAgents(ctx interface{}, namespace interface{}, options interface{}) *MockManageUsecase_ListAgents_Call {
+	return &MockManageUsecase_ListAgents_Call{Call: _e.mock.On("ListAgents", ctx, namespace, options)}
+}
+
+func (_c *MockManageUsecase_ListAgents_Call) Run(run func(ctx context.Context, namespace string, options *port.ListOptions)) *MockManageUsecase_ListAgents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *port.ListOptions
+		if args[2] != nil {
+			arg2 = args[2].(*port.ListOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgents_Call) Return(listResponse *v1.ListResponse[v1.Agent], err error) *MockManageUsecase_ListAgents_Call {
+	_c.Call.Return(listResponse, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgents_Call) RunAndReturn(run func(ctx context.Context, namespace string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error)) *MockManageUsecase_ListAgents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAgentsByPackage provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ListAgentsByPackage(ctx context.Context, namespace string, packageName string, packageVersion string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error) {
+	ret := _mock.Called(ctx, namespace, packageName, packageVersion, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgentsByPackage")
+	}
+
+	var r0 *v1.ListResponse[v1.Agent]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *port.ListOptions) (*v1.ListResponse[v1.Agent], error)); ok {
+		return returnFunc(ctx, namespace, packageName, packageVersion, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *port.ListOptions) *v1.ListResponse[v1.Agent]); ok {
+		r0 = returnFunc(ctx, namespace, packageName, packageVersion, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ListResponse[v1.Agent])
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, *port.ListOptions) error); ok {
+		r1 = returnFunc(ctx, namespace, packageName, packageVersion, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ListAgentsByPackage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentsByPackage'
+type MockManageUsecase_ListAgentsByPackage_Call struct {
+	*mock.Call
+}
+
+// ListAgentsByPackage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - packageName string
+//   - packageVersion string
+//   - options *port.ListOptions
+func (_e *MockManageUsecase_Expecter) ListAgentsByPackage(ctx interface{}, namespace interface{}, packageName interface{}, packageVersion interface{}, options interface{}) *MockManageUsecase_ListAgentsByPackage_Call {
+	return &MockManageUsecase_ListAgentsByPackage_Call{Call: _e.mock.On("ListAgentsByPackage", ctx, namespace, packageName, packageVersion, options)}
+}
+
+func (_c *MockManageUsecase_ListAgentsByPackage_Call) Run(run func(ctx context.Context, namespace string, packageName string, packageVersion string, options *port.ListOptions)) *MockManageUsecase_ListAgentsByPackage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 *port.ListOptions
+		if args[4] != nil {
+			arg4 = args[4].(*port.ListOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentsByPackage_Call) Return(listResponse *v1.ListResponse[v1.Agent], err error) *MockManageUsecase_ListAgentsByPackage_Call {
+	_c.Call.Return(listResponse, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ListAgentsByPackage_Call) RunAndReturn(run func(ctx context.Context, namespace string, packageName string, packageVersion string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error)) *MockManageUsecase_ListAgentsByPackage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReconnectAgent provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ReconnectAgent(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconnectAgent")
 	}
 
-	var r0 *v1.ListResponse[v1.Endpoint]
+	var r0 *v1.Agent
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.ListResponse[v1.Endpoint], error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.Agent, error)); ok {
 		return returnFunc(ctx, namespace, instanceUID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.ListResponse[v1.Endpoint]); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.Agent); ok {
 		r0 = returnFunc(ctx, namespace, instanceUID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v1.ListResponse[v1.Endpoint])
+			r0 = ret.Get(0).(*v1.Agent)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
@@ -205,20 +797,20 @@ func (_mock *MockManageUsecase) ListAgentEndpoints(ctx context.Context, namespac
 	return r0, r1
 }
 
-// MockManageUsecase_ListAgentEndpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentEndpoints'
-type MockManageUsecase_ListAgentEndpoints_Call struct {
+// MockManageUsecase_ReconnectAgent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconnectAgent'
+type MockManageUsecase_ReconnectAgent_Call struct {
 	*mock.Call
 }
 
-// ListAgentEndpoints is a helper method to define mock.On call
+// ReconnectAgent is a helper method to define mock.On call
 //   - ctx context.Context
 //   - namespace string
 //   - instanceUID uuid.UUID
-func (_e *MockManageUsecase_Expecter) ListAgentEndpoints(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_ListAgentEndpoints_Call {
-	return &MockManageUsecase_ListAgentEndpoints_Call{Call: _e.mock.On("ListAgentEndpoints", ctx, namespace, instanceUID)}
+func (_e *MockManageUsecase_Expecter) ReconnectAgent(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_ReconnectAgent_Call {
+	return &MockManageUsecase_ReconnectAgent_Call{Call: _e.mock.On("ReconnectAgent", ctx, namespace, instanceUID)}
 }
 
-func (_c *MockManageUsecase_ListAgentEndpoints_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_ListAgentEndpoints_Call {
+func (_c *MockManageUsecase_ReconnectAgent_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_ReconnectAgent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -241,58 +833,59 @@ func (_c *MockManageUsecase_ListAgentEndpoints_Call) Run(run func(ctx context.Co
 	return _c
 }
 
-func (_c *MockManageUsecase_ListAgentEndpoints_Call) Return(listResponse *v1.ListResponse[v1.Endpoint], err error) *MockManageUsecase_ListAgentEndpoints_Call {
-	_c.Call.Return(listResponse, err)
+func (_c *MockManageUsecase_ReconnectAgent_Call) Return(agent *v1.Agent, err error) *MockManageUsecase_ReconnectAgent_Call {
+	_c.Call.Return(agent, err)
 	return _c
 }
 
-func (_c *MockManageUsecase_ListAgentEndpoints_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.ListResponse[v1.Endpoint], error)) *MockManageUsecase_ListAgentEndpoints_Call {
+func (_c *MockManageUsecase_ReconnectAgent_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error)) *MockManageUsecase_ReconnectAgent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListAgents provides a mock function for the type MockManageUsecase
-func (_mock *MockManageUsecase) ListAgents(ctx context.Context, namespace string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error) {
-	ret := _mock.Called(ctx, namespace, options)
+// RequestAgentReport provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) RequestAgentReport(ctx context.Context, namespace string, instanceUID uuid.UUID, kind v1.AgentReportKind) (*v1.Agent, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID, kind)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAgents")
+		panic("no return value specified for RequestAgentReport")
 	}
 
-	var r0 *v1.ListResponse[v1.Agent]
+	var r0 *v1.Agent
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *port.ListOptions) (*v1.ListResponse[v1.Agent], error)); ok {
-		return returnFunc(ctx, namespace, options)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, v1.AgentReportKind) (*v1.Agent, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID, kind)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *port.ListOptions) *v1.ListResponse[v1.Agent]); ok {
-		r0 = returnFunc(ctx, namespace, options)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, v1.AgentReportKind) *v1.Agent); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID, kind)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v1.ListResponse[v1.Agent])
+			r0 = ret.Get(0).(*v1.Agent)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *port.ListOptions) error); ok {
-		r1 = returnFunc(ctx, namespace, options)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, v1.AgentReportKind) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID, kind)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockManageUsecase_ListAgents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgents'
-type MockManageUsecase_ListAgents_Call struct {
+// MockManageUsecase_RequestAgentReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestAgentReport'
+type MockManageUsecase_RequestAgentReport_Call struct {
 	*mock.Call
 }
 
-// ListAgents is a helper method to define mock.On call
+// RequestAgentReport is a helper method to define mock.On call
 //   - ctx context.Context
 //   - namespace string
-//   - options *port.ListOptions
-func (_e *MockManageUsecase_Expecter) ListAgents(ctx interface{}, namespace interface{}, options interface{}) *MockManageUsecase_ListAgents_Call {
-	return &MockManageUsecase_ListAgents_Call{Call: _e.mock.On("ListAgents", ctx, namespace, options)}
+//   - instanceUID uuid.UUID
+//   - kind v1.AgentReportKind
+func (_e *MockManageUsecase_Expecter) RequestAgentReport(ctx interface{}, namespace interface{}, instanceUID interface{}, kind interface{}) *MockManageUsecase_RequestAgentReport_Call {
+	return &MockManageUsecase_RequestAgentReport_Call{Call: _e.mock.On("RequestAgentReport", ctx, namespace, instanceUID, kind)}
 }
 
-func (_c *MockManageUsecase_ListAgents_Call) Run(run func(ctx context.Context, namespace string, options *port.ListOptions)) *MockManageUsecase_ListAgents_Call {
+func (_c *MockManageUsecase_RequestAgentReport_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID, kind v1.AgentReportKind)) *MockManageUsecase_RequestAgentReport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -302,25 +895,104 @@ func (_c *MockManageUsecase_ListAgents_Call) Run(run func(ctx context.Context, n
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 *port.ListOptions
+		var arg2 uuid.UUID
 		if args[2] != nil {
-			arg2 = args[2].(*port.ListOptions)
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 v1.AgentReportKind
+		if args[3] != nil {
+			arg3 = args[3].(v1.AgentReportKind)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockManageUsecase_ListAgents_Call) Return(listResponse *v1.ListResponse[v1.Agent], err error) *MockManageUsecase_ListAgents_Call {
-	_c.Call.Return(listResponse, err)
+func (_c *MockManageUsecase_RequestAgentReport_Call) Return(agent *v1.Agent, err error) *MockManageUsecase_RequestAgentReport_Call {
+	_c.Call.Return(agent, err)
 	return _c
 }
 
-func (_c *MockManageUsecase_ListAgents_Call) RunAndReturn(run func(ctx context.Context, namespace string, options *port.ListOptions) (*v1.ListResponse[v1.Agent], error)) *MockManageUsecase_ListAgents_Call {
+func (_c *MockManageUsecase_RequestAgentReport_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID, kind v1.AgentReportKind) (*v1.Agent, error)) *MockManageUsecase_RequestAgentReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResendAgentRemoteConfig provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) ResendAgentRemoteConfig(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendAgentRemoteConfig")
+	}
+
+	var r0 *v1.Agent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (*v1.Agent, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) *v1.Agent); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Agent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_ResendAgentRemoteConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendAgentRemoteConfig'
+type MockManageUsecase_ResendAgentRemoteConfig_Call struct {
+	*mock.Call
+}
+
+// ResendAgentRemoteConfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) ResendAgentRemoteConfig(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_ResendAgentRemoteConfig_Call {
+	return &MockManageUsecase_ResendAgentRemoteConfig_Call{Call: _e.mock.On("ResendAgentRemoteConfig", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_ResendAgentRemoteConfig_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_ResendAgentRemoteConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_ResendAgentRemoteConfig_Call) Return(agent *v1.Agent, err error) *MockManageUsecase_ResendAgentRemoteConfig_Call {
+	_c.Call.Return(agent, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_ResendAgentRemoteConfig_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error)) *MockManageUsecase_ResendAgentRemoteConfig_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -405,6 +1077,86 @@ func (_c *MockManageUsecase_SearchAgents_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// SetAgentDirectRemoteConfig provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) SetAgentDirectRemoteConfig(ctx context.Context, namespace string, instanceUID uuid.UUID, configMap *v1.AgentConfigMap) (*v1.AgentDesiredConfig, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID, configMap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAgentDirectRemoteConfig")
+	}
+
+	var r0 *v1.AgentDesiredConfig
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, *v1.AgentConfigMap) (*v1.AgentDesiredConfig, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID, configMap)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, *v1.AgentConfigMap) *v1.AgentDesiredConfig); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID, configMap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentDesiredConfig)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, *v1.AgentConfigMap) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID, configMap)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_SetAgentDirectRemoteConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAgentDirectRemoteConfig'
+type MockManageUsecase_SetAgentDirectRemoteConfig_Call struct {
+	*mock.Call
+}
+
+// SetAgentDirectRemoteConfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+//   - configMap *v1.AgentConfigMap
+func (_e *MockManageUsecase_Expecter) SetAgentDirectRemoteConfig(ctx interface{}, namespace interface{}, instanceUID interface{}, configMap interface{}) *MockManageUsecase_SetAgentDirectRemoteConfig_Call {
+	return &MockManageUsecase_SetAgentDirectRemoteConfig_Call{Call: _e.mock.On("SetAgentDirectRemoteConfig", ctx, namespace, instanceUID, configMap)}
+}
+
+func (_c *MockManageUsecase_SetAgentDirectRemoteConfig_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID, configMap *v1.AgentConfigMap)) *MockManageUsecase_SetAgentDirectRemoteConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 *v1.AgentConfigMap
+		if args[3] != nil {
+			arg3 = args[3].(*v1.AgentConfigMap)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_SetAgentDirectRemoteConfig_Call) Return(agentDesiredConfig *v1.AgentDesiredConfig, err error) *MockManageUsecase_SetAgentDirectRemoteConfig_Call {
+	_c.Call.Return(agentDesiredConfig, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_SetAgentDirectRemoteConfig_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID, configMap *v1.AgentConfigMap) (*v1.AgentDesiredConfig, error)) *MockManageUsecase_SetAgentDirectRemoteConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateAgent provides a mock function for the type MockManageUsecase
 func (_mock *MockManageUsecase) UpdateAgent(ctx context.Context, namespace string, instanceUID uuid.UUID, agent *v1.Agent) (*v1.Agent, error) {
 	ret := _mock.Called(ctx, namespace, instanceUID, agent)
@@ -484,3 +1236,77 @@ func (_c *MockManageUsecase_UpdateAgent_Call) RunAndReturn(run func(ctx context.
 	_c.Call.Return(run)
 	return _c
 }
+
+// WatchAgentEffectiveConfig provides a mock function for the type MockManageUsecase
+func (_mock *MockManageUsecase) WatchAgentEffectiveConfig(ctx context.Context, namespace string, instanceUID uuid.UUID) (<-chan *v1.AgentEffectiveConfig, error) {
+	ret := _mock.Called(ctx, namespace, instanceUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchAgentEffectiveConfig")
+	}
+
+	var r0 <-chan *v1.AgentEffectiveConfig
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (<-chan *v1.AgentEffectiveConfig, error)); ok {
+		return returnFunc(ctx, namespace, instanceUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) <-chan *v1.AgentEffectiveConfig); ok {
+		r0 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *v1.AgentEffectiveConfig)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, namespace, instanceUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockManageUsecase_WatchAgentEffectiveConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchAgentEffectiveConfig'
+type MockManageUsecase_WatchAgentEffectiveConfig_Call struct {
+	*mock.Call
+}
+
+// WatchAgentEffectiveConfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - instanceUID uuid.UUID
+func (_e *MockManageUsecase_Expecter) WatchAgentEffectiveConfig(ctx interface{}, namespace interface{}, instanceUID interface{}) *MockManageUsecase_WatchAgentEffectiveConfig_Call {
+	return &MockManageUsecase_WatchAgentEffectiveConfig_Call{Call: _e.mock.On("WatchAgentEffectiveConfig", ctx, namespace, instanceUID)}
+}
+
+func (_c *MockManageUsecase_WatchAgentEffectiveConfig_Call) Run(run func(ctx context.Context, namespace string, instanceUID uuid.UUID)) *MockManageUsecase_WatchAgentEffectiveConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockManageUsecase_WatchAgentEffectiveConfig_Call) Return(vCh <-chan *v1.AgentEffectiveConfig, err error) *MockManageUsecase_WatchAgentEffectiveConfig_Call {
+	_c.Call.Return(vCh, err)
+	return _c
+}
+
+func (_c *MockManageUsecase_WatchAgentEffectiveConfig_Call) RunAndReturn(run func(ctx context.Context, namespace string, instanceUID uuid.UUID) (<-chan *v1.AgentEffectiveConfig, error)) *MockManageUsecase_WatchAgentEffectiveConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,99 @@
+// Package agentexpectedattributes contains the controller for the attributes an agent is
+// expected to keep reporting.
+package agentexpectedattributes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// Controller exposes the agent expected attributes sub-resource.
+type Controller struct {
+	logger *slog.Logger
+
+	expectedAttributesUsecase usecase.AgentExpectedAttributesManageUsecase
+}
+
+// NewController creates a new agent expected attributes Controller.
+func NewController(
+	usecase usecase.AgentExpectedAttributesManageUsecase,
+	logger *slog.Logger,
+) *Controller {
+	return &Controller{
+		logger:                    logger,
+		expectedAttributesUsecase: usecase,
+	}
+}
+
+// RoutesInfo returns the routes for the agent expected attributes controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      http.MethodPut,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/expectedattributes",
+			Handler:     "http.v1.agentexpectedattributes.Set",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Set),
+		},
+	}
+}
+
+// Set replaces the attributes the agent is expected to keep reporting.
+//
+// @Summary  Set Agent Expected Attributes
+// @Tags agent
+// @Description Replace the attributes the agent must keep reporting. The agent's AttributeDrift
+// @Description condition is True while it does not report them. Empty attributes remove every expectation.
+// @Accept  json
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Param  request body v1.AgentExpectedAttributesRequest true "Expected attributes"
+// @Success  200 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/expectedattributes [put].
+func (c *Controller) Set(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	var req v1.AgentExpectedAttributesRequest
+
+	err = ginutil.BindJSON(ctx, &req)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	agent, err := c.expectedAttributesUsecase.SetAgentExpectedAttributes(
+		ctx.Request.Context(), namespace, instanceUID, req.Attributes)
+	if err != nil {
+		if errors.Is(err, applicationport.ErrAgentNamespaceMismatch) {
+			ginutil.ResourceNotFoundError(ctx, "agent", ctx.Param("id"))
+
+			return
+		}
+
+		c.logger.Error("failed to set expected attributes", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while setting the agent's expected attributes.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agent)
+}
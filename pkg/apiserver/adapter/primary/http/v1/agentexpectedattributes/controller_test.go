@@ -0,0 +1,121 @@
+package agentexpectedattributes_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentexpectedattributes"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	goleak.VerifyTestMain(m)
+}
+
+// mockExpectedAttributesUsecase is a testify mock of usecase.AgentExpectedAttributesManageUsecase.
+type mockExpectedAttributesUsecase struct {
+	mock.Mock
+}
+
+func newMockExpectedAttributesUsecase(t *testing.T) *mockExpectedAttributesUsecase {
+	t.Helper()
+
+	m := &mockExpectedAttributesUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockExpectedAttributesUsecase) SetAgentExpectedAttributes(
+	ctx context.Context, namespace string, instanceUID uuid.UUID, attributes map[string]string,
+) (*v1.Agent, error) {
+	args := m.Called(ctx, namespace, instanceUID, attributes)
+
+	res, _ := args.Get(0).(*v1.Agent)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func expectedAttributesPath(instanceUID string) string {
+	return "/api/v1/namespaces/default/agents/" + instanceUID + "/expectedattributes"
+}
+
+func TestAgentExpectedAttributesController_Set(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+
+	t.Run("returns the agent with its expected attributes", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockExpectedAttributesUsecase(t)
+		ctrlBase.SetupRouter(agentexpectedattributes.NewController(usecase, slog.Default()))
+
+		expected := map[string]string{"env": "prod"}
+		usecase.On("SetAgentExpectedAttributes", mock.Anything, "default", instanceUID, expected).
+			Return(&v1.Agent{Spec: v1.AgentSpec{ExpectedAttributes: expected}}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPut,
+			expectedAttributesPath(instanceUID.String()), strings.NewReader(`{"attributes":{"env":"prod"}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "prod", gjson.Get(recorder.Body.String(), "spec.expectedAttributes.env").String())
+	})
+
+	t.Run("returns 400 on a malformed body", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		ctrlBase.SetupRouter(agentexpectedattributes.NewController(newMockExpectedAttributesUsecase(t), slog.Default()))
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPut,
+			expectedAttributesPath(instanceUID.String()), strings.NewReader(`{"attributes":["env"]}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("returns 404 for an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockExpectedAttributesUsecase(t)
+		ctrlBase.SetupRouter(agentexpectedattributes.NewController(usecase, slog.Default()))
+
+		usecase.On("SetAgentExpectedAttributes", mock.Anything, "default", instanceUID, mock.Anything).
+			Return(nil, applicationport.ErrAgentNamespaceMismatch)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPut,
+			expectedAttributesPath(instanceUID.String()), strings.NewReader(`{"attributes":{}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
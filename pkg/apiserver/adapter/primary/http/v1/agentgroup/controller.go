@@ -3,6 +3,7 @@ package agentgroup
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -13,6 +14,14 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
 )
 
+const (
+	// DefaultSelectorPreviewSampleSize is how many matching agents a selector preview
+	// returns when the request does not say.
+	DefaultSelectorPreviewSampleSize = 10
+	// MaxSelectorPreviewSampleSize caps how many matching agents a selector preview returns.
+	MaxSelectorPreviewSampleSize = 100
+)
+
 // Controller is a struct that implements the agent group controller.
 type Controller struct {
 	logger *slog.Logger
@@ -47,11 +56,17 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.agentgroup.GetAgentByAgentGroup",
 			HandlerFunc: c.ListAgentsByAgentGroup,
 		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/agentgroups/:name/failures",
+			Handler:     "http.v1.agentgroup.ListFailures",
+			HandlerFunc: c.ListFailures,
+		},
 		{
 			Method:      http.MethodGet,
 			Path:        "/api/v1/namespaces/:namespace/agents/:id/agentgroups",
 			Handler:     "http.v1.agentgroup.ListAgentGroupsByAgent",
-			HandlerFunc: c.ListAgentGroupsByAgent,
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.ListAgentGroupsByAgent),
 		},
 		{
 			Method:      http.MethodGet,
@@ -77,6 +92,36 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.agentgroup.Delete",
 			HandlerFunc: c.Delete,
 		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agentgroups/:name/propagate",
+			Handler:     "http.v1.agentgroup.Propagate",
+			HandlerFunc: c.Propagate,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agentgroups/:name/apply",
+			Handler:     "http.v1.agentgroup.Apply",
+			HandlerFunc: c.Apply,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agentgroups/:name/rollout",
+			Handler:     "http.v1.agentgroup.AdvanceRollout",
+			HandlerFunc: c.AdvanceRollout,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agentgroups/:name/rollback",
+			Handler:     "http.v1.agentgroup.Rollback",
+			HandlerFunc: c.Rollback,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/selectors/preview",
+			Handler:     "http.v1.agentgroup.PreviewSelector",
+			HandlerFunc: c.PreviewSelector,
+		},
 	}
 }
 
@@ -185,7 +230,13 @@ func (c *Controller) Get(ctx *gin.Context) {
 // @Success 200 {object} v1.ListResponse[v1.Agent]
 // @Param namespace path string true "Namespace"
 // @Param name path string true "Agent Group Name"
-// @Param connected query bool false "When true, return only currently-connected agents".
+// @Param limit query int false "Maximum number of agents to return"
+// @Param continue query string false "Token to continue listing agents"
+// @Param connected query bool false "When true, return only currently-connected agents"
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/agents [get].
 func (c *Controller) ListAgentsByAgentGroup(ctx *gin.Context) {
 	limit, err := ginutil.ParseInt64(ctx, "limit", 0)
 	if err != nil {
@@ -255,12 +306,7 @@ func (c *Controller) ListAgentGroupsByAgent(ctx *gin.Context) {
 		return
 	}
 
-	instanceUID, err := ginutil.ParseUUID(ctx, "id")
-	if err != nil {
-		ginutil.HandleValidationError(ctx, "id", ctx.Param("id"), err, true)
-
-		return
-	}
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
 
 	agentGroups, err := c.agentGroupUsecase.ListAgentGroupsByAgent(ctx.Request.Context(), namespace, instanceUID)
 	if err != nil {
@@ -290,6 +336,8 @@ func (c *Controller) ListAgentGroupsByAgent(ctx *gin.Context) {
 // @Param agentGroup body v1.AgentGroup true "Agent Group to create"
 // @Success 201 {object} v1.AgentGroup
 // @Failure 400 {object} ErrorModel
+// @Failure 403 {object} ErrorModel
+// @Failure 422 {object} ErrorModel
 // @Failure 500 {object} ErrorModel
 // @Router /api/v1/namespaces/{namespace}/agentgroups [post].
 func (c *Controller) Create(ctx *gin.Context) {
@@ -314,7 +362,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 	created, err := c.agentGroupUsecase.CreateAgentGroup(ctx.Request.Context(), &req)
 	if err != nil {
 		c.logger.Error("failed to create agent group", "error", err.Error())
-		ginutil.InternalServerError(ctx, err, "An error occurred while creating the agent group.")
+		ginutil.HandleDomainError(ctx, err, "An error occurred while creating the agent group.")
 
 		return
 	}
@@ -324,10 +372,11 @@ func (c *Controller) Create(ctx *gin.Context) {
 }
 
 // Update updates an existing agent group.
+// It does not create the group: updating a group that does not exist, or was deleted, is a 404.
 //
 // @Summary Update Agent Group
 // @Tags agentgroup
-// @Description Update an existing agent group.
+// @Description Update an existing agent group. A group that does not exist or was deleted is not created.
 // @Accept json
 // @Produce json
 // @Param namespace path string true "Namespace"
@@ -336,6 +385,8 @@ func (c *Controller) Create(ctx *gin.Context) {
 // @Success 200 {object} v1.AgentGroup
 // @Failure 400 {object} ErrorModel
 // @Failure 404 {object} ErrorModel
+// @Failure 409 {object} ErrorModel
+// @Failure 422 {object} ErrorModel
 // @Failure 500 {object} ErrorModel
 // @Router /api/v1/namespaces/{namespace}/agentgroups/{name} [put].
 func (c *Controller) Update(ctx *gin.Context) {
@@ -364,6 +415,12 @@ func (c *Controller) Update(ctx *gin.Context) {
 
 	updated, err := c.agentGroupUsecase.UpdateAgentGroup(ctx.Request.Context(), namespace, name, &req)
 	if err != nil {
+		if errors.Is(err, applicationport.ErrResourceNotExist) {
+			ginutil.ResourceNotFoundError(ctx, "agent group", name)
+
+			return
+		}
+
 		c.logger.Error("failed to update agent group", "error", err.Error())
 		ginutil.HandleDomainError(ctx, err, "An error occurred while updating the agent group.")
 
@@ -410,3 +467,279 @@ func (c *Controller) Delete(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListFailures lists the agents that failed to apply an agent group's remote configs.
+//
+// @Summary List Agent Group Failures
+// @Tags agentgroup
+// @Description List the agents matched by an agent group that reported failing to apply a remote
+// @Description config holding the group's configs, with the errors they reported, e.g. after a rollout.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Agent Group Name"
+// @Success 200 {object} v1.AgentGroupFailures
+// @Failure 400 {object} ErrorModel
+// @Failure 404 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/failures [get].
+func (c *Controller) ListFailures(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	failures, err := c.agentGroupUsecase.ListAgentGroupFailures(ctx.Request.Context(), namespace, name)
+	if err != nil {
+		c.logger.Error("failed to list agent group failures", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while listing the agent group failures.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, failures)
+}
+
+// Propagate re-applies an agent group to its matching agents.
+//
+// @Summary Propagate Agent Group
+// @Tags agentgroup
+// @Description Re-apply an agent group to its matching agents without modifying it, e.g. to retry a
+// @Description partially failed propagation. Agents that fail are listed in the result.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Agent Group Name"
+// @Success 200 {object} v1.AgentGroupPropagationResult
+// @Failure 400 {object} ErrorModel
+// @Failure 404 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/propagate [post].
+func (c *Controller) Propagate(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	result, err := c.agentGroupUsecase.PropagateAgentGroup(ctx.Request.Context(), namespace, name)
+	if err != nil {
+		c.logger.Error("failed to propagate agent group", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while propagating the agent group.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Apply applies an agent group's remote configs to the given agents.
+//
+// @Summary Apply Agent Group To Agents
+// @Tags agentgroup
+// @Description Apply the agent group's remote configs to exactly the listed agents, whether or not its
+// @Description selector matches them. Later changes to the group do not reach them; apply it again for that.
+// @Description Agents that fail are listed in the result.
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Agent Group Name"
+// @Param request body v1.AgentGroupApplyRequest true "Agents to apply the group to"
+// @Success 200 {object} v1.AgentGroupPropagationResult
+// @Failure 400 {object} ErrorModel
+// @Failure 404 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/apply [post].
+func (c *Controller) Apply(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	var req v1.AgentGroupApplyRequest
+
+	err = ginutil.BindJSON(ctx, &req)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	result, err := c.agentGroupUsecase.ApplyAgentGroupToAgents(ctx.Request.Context(), namespace, name, &req)
+	if err != nil {
+		c.logger.Error("failed to apply agent group to agents", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while applying the agent group to agents.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// AdvanceRollout moves an agent group's rollout forward.
+//
+// @Summary Advance Agent Group Rollout
+// @Tags agentgroup
+// @Description Raise the percentage or count of the agent group's agents that receive its remote
+// @Description configs. The rest stay on the configs from before the rollout; 100 percent completes it.
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Agent Group Name"
+// @Param advance body v1.AgentGroupRolloutAdvance true "Rollout target"
+// @Success 200 {object} v1.AgentGroup
+// @Failure 400 {object} ErrorModel
+// @Failure 404 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/rollout [post].
+func (c *Controller) AdvanceRollout(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	var req v1.AgentGroupRolloutAdvance
+
+	err = ginutil.BindJSON(ctx, &req)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	updated, err := c.agentGroupUsecase.AdvanceAgentGroupRollout(ctx.Request.Context(), namespace, name, &req)
+	if err != nil {
+		c.logger.Error("failed to advance agent group rollout", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while advancing the agent group rollout.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Rollback puts an agent group back on its previous remote configs.
+//
+// @Summary Roll Back Agent Group
+// @Tags agentgroup
+// @Description Restore the remote configs the agent group had before they last changed and
+// @Description re-apply them to all of its agents, ending any rollout. Rolling back again undoes it.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Agent Group Name"
+// @Success 200 {object} v1.AgentGroup
+// @Failure 400 {object} ErrorModel
+// @Failure 404 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/namespaces/{namespace}/agentgroups/{name}/rollback [post].
+func (c *Controller) Rollback(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	updated, err := c.agentGroupUsecase.RollbackAgentGroup(ctx.Request.Context(), namespace, name)
+	if err != nil {
+		c.logger.Error("failed to roll back agent group", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while rolling back the agent group.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// PreviewSelector reports which agents a selector matches, without saving anything.
+//
+// @Summary Preview Agent Selector
+// @Tags agentgroup
+// @Description Count the agents across all namespaces that an agent group selector matches
+// @Description and return a sample of them, e.g. to check a selector before saving an agent
+// @Description group. The sample holds limit agents, 10 by default and at most 100.
+// @Accept json
+// @Produce json
+// @Param selector body v1.AgentSelector true "Selector to preview"
+// @Param limit query int false "Maximum number of matching agents to return"
+// @Success 200 {object} v1.AgentSelectorPreview
+// @Failure 400 {object} ErrorModel
+// @Failure 500 {object} ErrorModel
+// @Router /api/v1/selectors/preview [post].
+func (c *Controller) PreviewSelector(ctx *gin.Context) {
+	sampleSize, err := ginutil.ParseInt64(ctx, "limit", DefaultSelectorPreviewSampleSize)
+	if err == nil && sampleSize < 0 {
+		err = fmt.Errorf("%w: must not be negative", ginutil.ErrInvalidValue)
+	}
+
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "limit", ctx.Query("limit"), err, false)
+
+		return
+	}
+
+	if sampleSize == 0 {
+		sampleSize = DefaultSelectorPreviewSampleSize
+	}
+
+	sampleSize = min(sampleSize, MaxSelectorPreviewSampleSize)
+
+	var req v1.AgentSelector
+
+	err = ginutil.BindJSON(ctx, &req)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	preview, err := c.agentGroupUsecase.PreviewAgentSelector(ctx.Request.Context(), &req, sampleSize)
+	if err != nil {
+		c.logger.Error("failed to preview agent selector", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while previewing the agent selector.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preview)
+}
@@ -2,6 +2,7 @@ package agentgroup_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -18,9 +19,12 @@ import (
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentgroup"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentgroup/usecasemock"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
 func TestMain(m *testing.M) { goleak.VerifyTestMain(m) }
@@ -188,6 +192,28 @@ func TestAgentGroupController_Get(t *testing.T) {
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
 
+func TestAgentGroupController_Get_TypeMeta(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	mapper := helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness)
+	domainGroup := agentmodel.NewAgentGroup("default", "g1", agentmodel.Attributes{}, time.Now(), "tester")
+	usecase.EXPECT().GetAgentGroup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(mapper.MapAgentGroupToAPI(domainGroup), nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/namespaces/default/agentgroups/g1", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, v1.AgentGroupKind, gjson.Get(recorder.Body.String(), "kind").String())
+	assert.Equal(t, v1.APIVersion, gjson.Get(recorder.Body.String(), "apiVersion").String())
+}
+
 func TestAgentGroupController_Get_NotFound(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -431,6 +457,92 @@ func TestAgentGroupController_Create_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestAgentGroupController_InvalidRemoteConfigRef(t *testing.T) {
+	t.Parallel()
+
+	const field = "spec.agentConfig.agentRemoteConfigs[0].agentRemoteConfigRef"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		reason string
+	}{
+		{
+			name:   "create with a dangling ref",
+			method: http.MethodPost,
+			path:   "/api/v1/namespaces/default/agentgroups",
+			reason: `agent remote config "missing" does not exist in namespace "default"`,
+		},
+		{
+			name:   "update with a ref to its own entry",
+			method: http.MethodPut,
+			path:   "/api/v1/namespaces/default/agentgroups/g1",
+			reason: "agent remote config ref refers to its own entry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			usecase := usecasemock.NewMockUsecase(t)
+			ctrlBase.SetupRouter(agentgroup.NewController(usecase, ctrlBase.Logger))
+
+			fieldErr := &model.FieldError{Field: field, Value: "missing", Reason: tt.reason}
+			if tt.method == http.MethodPost {
+				usecase.EXPECT().CreateAgentGroup(mock.Anything, mock.Anything).Return(nil, fieldErr)
+			} else {
+				usecase.EXPECT().
+					UpdateAgentGroup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, fieldErr)
+			}
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), tt.method, tt.path,
+				strings.NewReader(`{"metadata":{"name":"g1"},"spec":{"selector":{}}}`))
+			require.NoError(t, err)
+			ctrlBase.Router.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, "body."+field, gjson.Get(recorder.Body.String(), "errors.0.location").String())
+			assert.Equal(t, tt.reason, gjson.Get(recorder.Body.String(), "errors.0.message").String())
+		})
+	}
+}
+
+func TestAgentGroupController_PriorityOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	ctrlBase.SetupRouter(agentgroup.NewController(usecase, ctrlBase.Logger))
+
+	group := agentmodel.NewAgentGroup("default", "g1", nil, time.Now(), "tester")
+	group.Spec.Priority = agentmodel.MaxAgentGroupPriority + 1
+	validationErr := group.ValidatePriority()
+	require.Error(t, validationErr)
+
+	usecase.EXPECT().CreateAgentGroup(mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("create agent group: %w", validationErr))
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/namespaces/default/agentgroups",
+		strings.NewReader(`{"metadata":{"name":"g1"},"spec":{"priority":1001,"selector":{}}}`))
+	require.NoError(t, err)
+	ctrlBase.Router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Equal(t, "Unprocessable Entity", gjson.Get(body, "title").String())
+	assert.Equal(t, int64(http.StatusUnprocessableEntity), gjson.Get(body, "status").Int())
+	assert.Equal(t, "body.spec.priority", gjson.Get(body, "errors.0.location").String())
+	assert.Equal(t, int64(1001), gjson.Get(body, "errors.0.value").Int())
+	assert.Equal(t, "must be between 0 and 1000", gjson.Get(body, "errors.0.message").String())
+}
+
 func TestAgentGroupController_Update(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -555,6 +667,105 @@ func TestAgentGroupController_Update_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestAgentGroupController_Update_ExistingGroup(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+	group := &v1.AgentGroup{
+		Metadata: v1.Metadata{Name: "g1", Namespace: "default", Attributes: v1.Attributes{}},
+		Spec: v1.Spec{
+			Priority: 5,
+			Selector: v1.AgentSelector{
+				IdentifyingAttributes:    map[string]string{"service.name": "collector"},
+				NonIdentifyingAttributes: map[string]string{},
+			},
+		},
+	}
+
+	usecase.EXPECT().UpdateAgentGroup(mock.Anything, "default", "g1", mock.Anything).Return(group, nil)
+
+	jsonBody, err := json.Marshal(group)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPut,
+		"/api/v1/namespaces/default/agentgroups/g1",
+		strings.NewReader(string(jsonBody)),
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, "g1", gjson.Get(recorder.Body.String(), "metadata.name").String())
+	assert.Equal(t, int64(5), gjson.Get(recorder.Body.String(), "spec.priority").Int())
+}
+
+func TestAgentGroupController_Update_MissingGroup(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+	group := &v1.AgentGroup{Metadata: v1.Metadata{Name: "missing", Attributes: v1.Attributes{}}}
+
+	usecase.EXPECT().
+		UpdateAgentGroup(mock.Anything, "default", "missing", mock.Anything).
+		Return(nil, fmt.Errorf("get agent group for update: %w", model.ErrResourceNotExist))
+
+	jsonBody, err := json.Marshal(group)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPut,
+		"/api/v1/namespaces/default/agentgroups/missing",
+		strings.NewReader(string(jsonBody)),
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Equal(t, "The requested agent group does not exist.", gjson.Get(body, "detail").String())
+	assert.Equal(t, "path.name", gjson.Get(body, "errors.0.location").String())
+	assert.Equal(t, "missing", gjson.Get(body, "errors.0.value").String())
+}
+
+func TestAgentGroupController_Update_Conflict(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+	group := &v1.AgentGroup{Metadata: v1.Metadata{Name: "g1", Attributes: v1.Attributes{}}}
+
+	usecase.EXPECT().
+		UpdateAgentGroup(mock.Anything, "default", "g1", mock.Anything).
+		Return(nil, fmt.Errorf("save agent group: %w", model.ErrConflict))
+
+	jsonBody, err := json.Marshal(group)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPut,
+		"/api/v1/namespaces/default/agentgroups/g1",
+		strings.NewReader(string(jsonBody)),
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
 func TestAgentGroupController_Delete(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -619,3 +830,267 @@ func TestAgentGroupController_Delete_InternalError(t *testing.T) {
 	router.ServeHTTP(recorder, req)
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
+
+func TestAgentGroupController_Propagate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the propagation summary", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		failedUID := uuid.New()
+		usecase.EXPECT().PropagateAgentGroup(mock.Anything, "default", "web").Return(&v1.AgentGroupPropagationResult{
+			Updated:   2,
+			Unchanged: 1,
+			Failed: []v1.AgentGroupPropagationFailure{
+				{InstanceUID: failedUID, Error: "save updated agent: conflict"},
+			},
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/web/propagate", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		assert.Equal(t, int64(2), gjson.Get(body, "updated").Int())
+		assert.Equal(t, int64(1), gjson.Get(body, "unchanged").Int())
+		assert.Equal(t, failedUID.String(), gjson.Get(body, "failed.0.instanceUid").String())
+		assert.Equal(t, "save updated agent: conflict", gjson.Get(body, "failed.0.error").String())
+	})
+
+	t.Run("unknown agent group is not found", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		usecase.EXPECT().PropagateAgentGroup(mock.Anything, "default", "missing").
+			Return(nil, model.ErrResourceNotExist)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/missing/propagate", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAgentGroupController_AdvanceRollout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the updated agent group", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		usecase.EXPECT().AdvanceAgentGroupRollout(mock.Anything, "default", "web",
+			&v1.AgentGroupRolloutAdvance{Percentage: 50}).Return(&v1.AgentGroup{
+			Metadata: v1.Metadata{Namespace: "default", Name: "web"},
+			Spec:     v1.Spec{Rollout: &v1.AgentGroupRollout{Percentage: 50}},
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/web/rollout", strings.NewReader(`{"percentage":50}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(50), gjson.Get(recorder.Body.String(), "spec.rollout.percentage").Int())
+	})
+
+	t.Run("agent group without a rollout is a bad request", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		usecase.EXPECT().AdvanceAgentGroupRollout(mock.Anything, "default", "web", mock.Anything).
+			Return(nil, agentmodel.ErrNoRolloutInProgress)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/web/rollout", strings.NewReader(`{"percentage":50}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentGroupController_Rollback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the rolled back agent group", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		configName := "collector"
+		usecase.EXPECT().RollbackAgentGroup(mock.Anything, "default", "web").Return(&v1.AgentGroup{
+			Metadata: v1.Metadata{Namespace: "default", Name: "web"},
+			Spec: v1.Spec{AgentConfig: &v1.AgentConfig{
+				AgentRemoteConfigs: []v1.AgentGroupRemoteConfig{{AgentRemoteConfigRef: &configName}},
+			}},
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/web/rollback", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "collector",
+			gjson.Get(recorder.Body.String(), "spec.agentConfig.agentRemoteConfigs.0.agentRemoteConfigRef").String())
+	})
+
+	t.Run("agent group without previous configs is a bad request", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		usecase.EXPECT().RollbackAgentGroup(mock.Anything, "default", "web").
+			Return(nil, agentmodel.ErrNoPreviousRemoteConfigs)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/namespaces/default/agentgroups/web/rollback", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentGroupController_PreviewSelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the count and a sample of matching agents", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		instanceUID := uuid.New()
+		usecase.EXPECT().PreviewAgentSelector(mock.Anything, &v1.AgentSelector{
+			IdentifyingAttributes: map[string]string{"service.name": "web"},
+		}, int64(agentgroup.DefaultSelectorPreviewSampleSize)).Return(&v1.AgentSelectorPreview{
+			Count: 3,
+			Agents: []v1.Agent{
+				{Metadata: v1.AgentMetadata{InstanceUID: instanceUID}},
+			},
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/selectors/preview", strings.NewReader(`{"identifyingAttributes":{"service.name":"web"}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		assert.Equal(t, int64(3), gjson.Get(body, "count").Int())
+		assert.Equal(t, int64(1), gjson.Get(body, "agents.#").Int())
+		assert.Equal(t, instanceUID.String(), gjson.Get(body, "agents.0.metadata.instanceUid").String())
+	})
+
+	t.Run("caps the sample size", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		usecase.EXPECT().PreviewAgentSelector(mock.Anything, mock.Anything,
+			int64(agentgroup.MaxSelectorPreviewSampleSize)).Return(&v1.AgentSelectorPreview{}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/selectors/preview?limit=1000", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("negative limit is a bad request", func(t *testing.T) {
+		t.Parallel()
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := usecasemock.NewMockUsecase(t)
+		controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+		ctrlBase.SetupRouter(controller)
+		router := ctrlBase.Router
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+			"/api/v1/selectors/preview?limit=-1", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentGroupController_ListFailures(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentgroup.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	failedUID := uuid.New()
+	usecase.EXPECT().ListAgentGroupFailures(mock.Anything, "default", "web").Return(&v1.AgentGroupFailures{
+		Items: []v1.AgentGroupFailure{
+			{
+				InstanceUID:       failedUID,
+				RemoteConfigNames: []string{"web/pipeline"},
+				ErrorMessage:      "invalid pipeline",
+			},
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/api/v1/namespaces/default/agentgroups/web/failures", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	assert.Equal(t, failedUID.String(), gjson.Get(body, "items.0.instanceUid").String())
+	assert.Equal(t, "web/pipeline", gjson.Get(body, "items.0.remoteConfigNames.0").String())
+	assert.Equal(t, "invalid pipeline", gjson.Get(body, "items.0.errorMessage").String())
+}
@@ -40,6 +40,166 @@ func (_m *MockUsecase) EXPECT() *MockUsecase_Expecter {
 	return &MockUsecase_Expecter{mock: &_m.Mock}
 }
 
+// AdvanceAgentGroupRollout provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) AdvanceAgentGroupRollout(ctx context.Context, namespace string, name string, advance *v1.AgentGroupRolloutAdvance) (*v1.AgentGroup, error) {
+	ret := _mock.Called(ctx, namespace, name, advance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceAgentGroupRollout")
+	}
+
+	var r0 *v1.AgentGroup
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *v1.AgentGroupRolloutAdvance) (*v1.AgentGroup, error)); ok {
+		return returnFunc(ctx, namespace, name, advance)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *v1.AgentGroupRolloutAdvance) *v1.AgentGroup); ok {
+		r0 = returnFunc(ctx, namespace, name, advance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentGroup)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *v1.AgentGroupRolloutAdvance) error); ok {
+		r1 = returnFunc(ctx, namespace, name, advance)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_AdvanceAgentGroupRollout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdvanceAgentGroupRollout'
+type MockUsecase_AdvanceAgentGroupRollout_Call struct {
+	*mock.Call
+}
+
+// AdvanceAgentGroupRollout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+//   - advance *v1.AgentGroupRolloutAdvance
+func (_e *MockUsecase_Expecter) AdvanceAgentGroupRollout(ctx interface{}, namespace interface{}, name interface{}, advance interface{}) *MockUsecase_AdvanceAgentGroupRollout_Call {
+	return &MockUsecase_AdvanceAgentGroupRollout_Call{Call: _e.mock.On("AdvanceAgentGroupRollout", ctx, namespace, name, advance)}
+}
+
+func (_c *MockUsecase_AdvanceAgentGroupRollout_Call) Run(run func(ctx context.Context, namespace string, name string, advance *v1.AgentGroupRolloutAdvance)) *MockUsecase_AdvanceAgentGroupRollout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 *v1.AgentGroupRolloutAdvance
+		if args[3] != nil {
+			arg3 = args[3].(*v1.AgentGroupRolloutAdvance)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_AdvanceAgentGroupRollout_Call) Return(agentGroup *v1.AgentGroup, err error) *MockUsecase_AdvanceAgentGroupRollout_Call {
+	_c.Call.Return(agentGroup, err)
+	return _c
+}
+
+func (_c *MockUsecase_AdvanceAgentGroupRollout_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string, advance *v1.AgentGroupRolloutAdvance) (*v1.AgentGroup, error)) *MockUsecase_AdvanceAgentGroupRollout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ApplyAgentGroupToAgents provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) ApplyAgentGroupToAgents(ctx context.Context, namespace string, name string, request *v1.AgentGroupApplyRequest) (*v1.AgentGroupPropagationResult, error) {
+	ret := _mock.Called(ctx, namespace, name, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyAgentGroupToAgents")
+	}
+
+	var r0 *v1.AgentGroupPropagationResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *v1.AgentGroupApplyRequest) (*v1.AgentGroupPropagationResult, error)); ok {
+		return returnFunc(ctx, namespace, name, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *v1.AgentGroupApplyRequest) *v1.AgentGroupPropagationResult); ok {
+		r0 = returnFunc(ctx, namespace, name, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentGroupPropagationResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *v1.AgentGroupApplyRequest) error); ok {
+		r1 = returnFunc(ctx, namespace, name, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_ApplyAgentGroupToAgents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyAgentGroupToAgents'
+type MockUsecase_ApplyAgentGroupToAgents_Call struct {
+	*mock.Call
+}
+
+// ApplyAgentGroupToAgents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+//   - request *v1.AgentGroupApplyRequest
+func (_e *MockUsecase_Expecter) ApplyAgentGroupToAgents(ctx interface{}, namespace interface{}, name interface{}, request interface{}) *MockUsecase_ApplyAgentGroupToAgents_Call {
+	return &MockUsecase_ApplyAgentGroupToAgents_Call{Call: _e.mock.On("ApplyAgentGroupToAgents", ctx, namespace, name, request)}
+}
+
+func (_c *MockUsecase_ApplyAgentGroupToAgents_Call) Run(run func(ctx context.Context, namespace string, name string, request *v1.AgentGroupApplyRequest)) *MockUsecase_ApplyAgentGroupToAgents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 *v1.AgentGroupApplyRequest
+		if args[3] != nil {
+			arg3 = args[3].(*v1.AgentGroupApplyRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_ApplyAgentGroupToAgents_Call) Return(agentGroupPropagationResult *v1.AgentGroupPropagationResult, err error) *MockUsecase_ApplyAgentGroupToAgents_Call {
+	_c.Call.Return(agentGroupPropagationResult, err)
+	return _c
+}
+
+func (_c *MockUsecase_ApplyAgentGroupToAgents_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string, request *v1.AgentGroupApplyRequest) (*v1.AgentGroupPropagationResult, error)) *MockUsecase_ApplyAgentGroupToAgents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAgentGroup provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) CreateAgentGroup(ctx context.Context, agentGroup *v1.AgentGroup) (*v1.AgentGroup, error) {
 	ret := _mock.Called(ctx, agentGroup)
@@ -251,6 +411,80 @@ func (_c *MockUsecase_GetAgentGroup_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
+// ListAgentGroupFailures provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) ListAgentGroupFailures(ctx context.Context, namespace string, name string) (*v1.AgentGroupFailures, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgentGroupFailures")
+	}
+
+	var r0 *v1.AgentGroupFailures
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*v1.AgentGroupFailures, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *v1.AgentGroupFailures); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentGroupFailures)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_ListAgentGroupFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAgentGroupFailures'
+type MockUsecase_ListAgentGroupFailures_Call struct {
+	*mock.Call
+}
+
+// ListAgentGroupFailures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockUsecase_Expecter) ListAgentGroupFailures(ctx interface{}, namespace interface{}, name interface{}) *MockUsecase_ListAgentGroupFailures_Call {
+	return &MockUsecase_ListAgentGroupFailures_Call{Call: _e.mock.On("ListAgentGroupFailures", ctx, namespace, name)}
+}
+
+func (_c *MockUsecase_ListAgentGroupFailures_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockUsecase_ListAgentGroupFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_ListAgentGroupFailures_Call) Return(agentGroupPropagationResult *v1.AgentGroupFailures, err error) *MockUsecase_ListAgentGroupFailures_Call {
+	_c.Call.Return(agentGroupPropagationResult, err)
+	return _c
+}
+
+func (_c *MockUsecase_ListAgentGroupFailures_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*v1.AgentGroupFailures, error)) *MockUsecase_ListAgentGroupFailures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAgentGroups provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) ListAgentGroups(ctx context.Context, options *port.ListOptions) (*v1.ListResponse[v1.AgentGroup], error) {
 	ret := _mock.Called(ctx, options)
@@ -473,6 +707,228 @@ func (_c *MockUsecase_ListAgentsByAgentGroup_Call) RunAndReturn(run func(ctx con
 	return _c
 }
 
+// PreviewAgentSelector provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) PreviewAgentSelector(ctx context.Context, selector *v1.AgentSelector, sampleSize int64) (*v1.AgentSelectorPreview, error) {
+	ret := _mock.Called(ctx, selector, sampleSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewAgentSelector")
+	}
+
+	var r0 *v1.AgentSelectorPreview
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.AgentSelector, int64) (*v1.AgentSelectorPreview, error)); ok {
+		return returnFunc(ctx, selector, sampleSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v1.AgentSelector, int64) *v1.AgentSelectorPreview); ok {
+		r0 = returnFunc(ctx, selector, sampleSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentSelectorPreview)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v1.AgentSelector, int64) error); ok {
+		r1 = returnFunc(ctx, selector, sampleSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_PreviewAgentSelector_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreviewAgentSelector'
+type MockUsecase_PreviewAgentSelector_Call struct {
+	*mock.Call
+}
+
+// PreviewAgentSelector is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selector *v1.AgentSelector
+//   - sampleSize int64
+func (_e *MockUsecase_Expecter) PreviewAgentSelector(ctx interface{}, selector interface{}, sampleSize interface{}) *MockUsecase_PreviewAgentSelector_Call {
+	return &MockUsecase_PreviewAgentSelector_Call{Call: _e.mock.On("PreviewAgentSelector", ctx, selector, sampleSize)}
+}
+
+func (_c *MockUsecase_PreviewAgentSelector_Call) Run(run func(ctx context.Context, selector *v1.AgentSelector, sampleSize int64)) *MockUsecase_PreviewAgentSelector_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v1.AgentSelector
+		if args[1] != nil {
+			arg1 = args[1].(*v1.AgentSelector)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_PreviewAgentSelector_Call) Return(agentSelectorPreview *v1.AgentSelectorPreview, err error) *MockUsecase_PreviewAgentSelector_Call {
+	_c.Call.Return(agentSelectorPreview, err)
+	return _c
+}
+
+func (_c *MockUsecase_PreviewAgentSelector_Call) RunAndReturn(run func(ctx context.Context, selector *v1.AgentSelector, sampleSize int64) (*v1.AgentSelectorPreview, error)) *MockUsecase_PreviewAgentSelector_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PropagateAgentGroup provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) PropagateAgentGroup(ctx context.Context, namespace string, name string) (*v1.AgentGroupPropagationResult, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PropagateAgentGroup")
+	}
+
+	var r0 *v1.AgentGroupPropagationResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*v1.AgentGroupPropagationResult, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *v1.AgentGroupPropagationResult); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentGroupPropagationResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_PropagateAgentGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PropagateAgentGroup'
+type MockUsecase_PropagateAgentGroup_Call struct {
+	*mock.Call
+}
+
+// PropagateAgentGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockUsecase_Expecter) PropagateAgentGroup(ctx interface{}, namespace interface{}, name interface{}) *MockUsecase_PropagateAgentGroup_Call {
+	return &MockUsecase_PropagateAgentGroup_Call{Call: _e.mock.On("PropagateAgentGroup", ctx, namespace, name)}
+}
+
+func (_c *MockUsecase_PropagateAgentGroup_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockUsecase_PropagateAgentGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_PropagateAgentGroup_Call) Return(agentGroupPropagationResult *v1.AgentGroupPropagationResult, err error) *MockUsecase_PropagateAgentGroup_Call {
+	_c.Call.Return(agentGroupPropagationResult, err)
+	return _c
+}
+
+func (_c *MockUsecase_PropagateAgentGroup_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*v1.AgentGroupPropagationResult, error)) *MockUsecase_PropagateAgentGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RollbackAgentGroup provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) RollbackAgentGroup(ctx context.Context, namespace string, name string) (*v1.AgentGroup, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackAgentGroup")
+	}
+
+	var r0 *v1.AgentGroup
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*v1.AgentGroup, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *v1.AgentGroup); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentGroup)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_RollbackAgentGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackAgentGroup'
+type MockUsecase_RollbackAgentGroup_Call struct {
+	*mock.Call
+}
+
+// RollbackAgentGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockUsecase_Expecter) RollbackAgentGroup(ctx interface{}, namespace interface{}, name interface{}) *MockUsecase_RollbackAgentGroup_Call {
+	return &MockUsecase_RollbackAgentGroup_Call{Call: _e.mock.On("RollbackAgentGroup", ctx, namespace, name)}
+}
+
+func (_c *MockUsecase_RollbackAgentGroup_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockUsecase_RollbackAgentGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_RollbackAgentGroup_Call) Return(agentGroup *v1.AgentGroup, err error) *MockUsecase_RollbackAgentGroup_Call {
+	_c.Call.Return(agentGroup, err)
+	return _c
+}
+
+func (_c *MockUsecase_RollbackAgentGroup_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*v1.AgentGroup, error)) *MockUsecase_RollbackAgentGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateAgentGroup provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) UpdateAgentGroup(ctx context.Context, namespace string, name string, agentGroup *v1.AgentGroup) (*v1.AgentGroup, error) {
 	ret := _mock.Called(ctx, namespace, name, agentGroup)
@@ -4,6 +4,7 @@ package agentpackage
 import (
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -60,12 +61,24 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.agentpackage.Update",
 			HandlerFunc: c.Update,
 		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/api/v1/namespaces/:namespace/agentpackages",
+			Handler:     "http.v1.agentpackage.DeleteCollection",
+			HandlerFunc: c.DeleteCollection,
+		},
 		{
 			Method:      http.MethodDelete,
 			Path:        "/api/v1/namespaces/:namespace/agentpackages/:name",
 			Handler:     "http.v1.agentpackage.Delete",
 			HandlerFunc: c.Delete,
 		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agentpackages/:name/verify",
+			Handler:     "http.v1.agentpackage.Verify",
+			HandlerFunc: c.Verify,
+		},
 	}
 }
 
@@ -197,7 +210,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 	created, err := c.agentpackageUsecase.CreateAgentPackage(ctx.Request.Context(), &req)
 	if err != nil {
 		c.logger.Error("failed to create agent package", "error", err.Error())
-		ginutil.InternalServerError(ctx, err, "An error occurred while creating the agent package.")
+		ginutil.HandleDomainError(ctx, err, "An error occurred while creating the agent package.")
 
 		return
 	}
@@ -295,3 +308,88 @@ func (c *Controller) Delete(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// Verify downloads an agent package's artifact and checks it against its content hash.
+//
+// @Summary  Verify Agent Package
+// @Tags agentpackage
+// @Description Download the agent package's artifact and record whether it matches the content hash
+// @Description as the ContentVerified condition. Downloads are retried, and an origin that keeps
+// @Description failing is not contacted for a while; either way the failure is reported on the
+// @Description condition rather than as an error status.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name of the agent package"
+// @Success 200 {object} v1.AgentPackage
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentpackages/{name}/verify [post].
+func (c *Controller) Verify(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	verified, err := c.agentpackageUsecase.VerifyAgentPackage(ctx.Request.Context(), namespace, name)
+	if err != nil {
+		c.logger.Error("failed to verify agent package", "name", name, "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while verifying the agent package.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verified)
+}
+
+// DeleteCollection soft-deletes the agent packages whose attributes match a selector.
+//
+// @Summary  Delete AgentPackages by Selector
+// @Tags agentpackage
+// @Description Soft-delete every agent package in the namespace whose attributes match the selector.
+// @Description The selector is required, so an empty request cannot delete the whole namespace.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param selector query []string true "Attribute to match (key=value)" collectionFormat(multi)
+// @Success 200 {object} v1.DeleteCollectionResponse
+// @Failure 400 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentpackages [delete].
+func (c *Controller) DeleteCollection(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	selector, err := ginutil.ParseSelector(ctx.QueryArray("selector"))
+	if err == nil && len(selector) == 0 {
+		err = ginutil.ErrRequiredParam
+	}
+
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "selector", strings.Join(ctx.QueryArray("selector"), ","), err, false)
+
+		return
+	}
+
+	response, err := c.agentpackageUsecase.DeleteAgentPackagesBySelector(ctx.Request.Context(), namespace, selector)
+	if err != nil {
+		c.logger.Error("failed to delete agent packages by selector", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while deleting the agent packages.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
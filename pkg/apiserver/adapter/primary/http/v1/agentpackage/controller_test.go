@@ -2,6 +2,7 @@ package agentpackage_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -17,8 +18,11 @@ import (
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentpackage"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentpackage/usecasemock"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
 const (
@@ -187,6 +191,31 @@ func TestAgentPackageController_Get(t *testing.T) {
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
 
+func TestAgentPackageController_Get_TypeMeta(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentpackage.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	mapper := helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness)
+	//exhaustruct:ignore
+	domainPackage := &agentmodel.AgentPackage{
+		Metadata: agentmodel.AgentPackageMetadata{Namespace: testNamespace, Name: testPackageName},
+	}
+	usecase.EXPECT().GetAgentPackage(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(mapper.MapAgentPackageToAPI(domainPackage), nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, testBaseURL+"/"+testPackageName, nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, v1.AgentPackageKind, gjson.Get(recorder.Body.String(), "kind").String())
+	assert.Equal(t, v1.APIVersion, gjson.Get(recorder.Body.String(), "apiVersion").String())
+}
+
 func TestAgentPackageController_Get_NotFound(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -312,6 +341,62 @@ func TestAgentPackageController_Create_InvalidBody(t *testing.T) {
 	assert.Contains(t, body, "instance")
 }
 
+func TestAgentPackageController_Create_MultipleInvalidFields(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentpackage.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+	payload := v1.AgentPackage{
+		Metadata: v1.AgentPackageMetadata{
+			Attributes: v1.Attributes{},
+		},
+		Spec: v1.AgentPackageSpec{
+			PackageType: "TopLevelPackageName",
+			Version:     "1.0.0",
+			DownloadURL: "not a url",
+			ContentHash: []byte("short"),
+		},
+	}
+
+	usecase.EXPECT().CreateAgentPackage(mock.Anything, mock.Anything).Return(nil, fmt.Errorf(
+		"failed to create agent package: %w", model.FieldErrors{
+			{Field: "metadata.name", Value: "", Reason: "must not be empty"},
+			{Field: "spec.downloadUrl", Value: "not a url", Reason: "must be an absolute http or https URL"},
+			{Field: "spec.contentHash", Value: []byte("short"), Reason: "must be a SHA-256 digest of 32 bytes"},
+		}))
+
+	jsonBody, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPost,
+		testBaseURL,
+		strings.NewReader(string(jsonBody)),
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Equal(t, []string{"body.metadata.name", "body.spec.downloadUrl", "body.spec.contentHash"},
+		stringsOf(gjson.Get(body, "errors.#.location").Array()))
+	assert.Equal(t, "must be an absolute http or https URL", gjson.Get(body, "errors.1.message").String())
+	assert.Equal(t, "not a url", gjson.Get(body, "errors.1.value").String())
+}
+
+func stringsOf(results []gjson.Result) []string {
+	values := make([]string, 0, len(results))
+	for _, result := range results {
+		values = append(values, result.String())
+	}
+
+	return values
+}
+
 func TestAgentPackageController_Create_InternalError(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -519,3 +604,38 @@ func TestAgentPackageController_Delete_InternalError(t *testing.T) {
 	router.ServeHTTP(recorder, req)
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
+
+func TestAgentPackageController_Verify(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := agentpackage.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	//exhaustruct:ignore
+	verified := &v1.AgentPackage{
+		Metadata: v1.AgentPackageMetadata{Name: testPackageName},
+		Status: v1.AgentPackageStatus{
+			Conditions: []v1.Condition{
+				{
+					Type:               v1.ConditionTypeContentVerified,
+					LastTransitionTime: v1.NewTime(time.Now()),
+					Status:             v1.ConditionStatusFalse,
+					Reason:             "tester",
+					Message:            "download failed: agent package origin is unavailable",
+				},
+			},
+		},
+	}
+	usecase.EXPECT().VerifyAgentPackage(mock.Anything, "default", testPackageName).Return(verified, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+		testBaseURL+"/"+testPackageName+"/verify", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "a failed download is reported on the condition")
+	assert.Contains(t, recorder.Body.String(), "origin is unavailable")
+}
@@ -170,6 +170,80 @@ func (_c *MockUsecase_DeleteAgentPackage_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// DeleteAgentPackagesBySelector provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) DeleteAgentPackagesBySelector(ctx context.Context, namespace string, selector map[string]string) (*v1.DeleteCollectionResponse, error) {
+	ret := _mock.Called(ctx, namespace, selector)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAgentPackagesBySelector")
+	}
+
+	var r0 *v1.DeleteCollectionResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) (*v1.DeleteCollectionResponse, error)); ok {
+		return returnFunc(ctx, namespace, selector)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) *v1.DeleteCollectionResponse); ok {
+		r0 = returnFunc(ctx, namespace, selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.DeleteCollectionResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, map[string]string) error); ok {
+		r1 = returnFunc(ctx, namespace, selector)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_DeleteAgentPackagesBySelector_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAgentPackagesBySelector'
+type MockUsecase_DeleteAgentPackagesBySelector_Call struct {
+	*mock.Call
+}
+
+// DeleteAgentPackagesBySelector is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - selector map[string]string
+func (_e *MockUsecase_Expecter) DeleteAgentPackagesBySelector(ctx interface{}, namespace interface{}, selector interface{}) *MockUsecase_DeleteAgentPackagesBySelector_Call {
+	return &MockUsecase_DeleteAgentPackagesBySelector_Call{Call: _e.mock.On("DeleteAgentPackagesBySelector", ctx, namespace, selector)}
+}
+
+func (_c *MockUsecase_DeleteAgentPackagesBySelector_Call) Run(run func(ctx context.Context, namespace string, selector map[string]string)) *MockUsecase_DeleteAgentPackagesBySelector_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_DeleteAgentPackagesBySelector_Call) Return(deleteCollectionResponse *v1.DeleteCollectionResponse, err error) *MockUsecase_DeleteAgentPackagesBySelector_Call {
+	_c.Call.Return(deleteCollectionResponse, err)
+	return _c
+}
+
+func (_c *MockUsecase_DeleteAgentPackagesBySelector_Call) RunAndReturn(run func(ctx context.Context, namespace string, selector map[string]string) (*v1.DeleteCollectionResponse, error)) *MockUsecase_DeleteAgentPackagesBySelector_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAgentPackage provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) GetAgentPackage(ctx context.Context, namespace string, name string, options *port.GetOptions) (*v1.AgentPackage, error) {
 	ret := _mock.Called(ctx, namespace, name, options)
@@ -397,3 +471,77 @@ func (_c *MockUsecase_UpdateAgentPackage_Call) RunAndReturn(run func(ctx context
 	_c.Call.Return(run)
 	return _c
 }
+
+// VerifyAgentPackage provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) VerifyAgentPackage(ctx context.Context, namespace string, name string) (*v1.AgentPackage, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAgentPackage")
+	}
+
+	var r0 *v1.AgentPackage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*v1.AgentPackage, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *v1.AgentPackage); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.AgentPackage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_VerifyAgentPackage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyAgentPackage'
+type MockUsecase_VerifyAgentPackage_Call struct {
+	*mock.Call
+}
+
+// VerifyAgentPackage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockUsecase_Expecter) VerifyAgentPackage(ctx interface{}, namespace interface{}, name interface{}) *MockUsecase_VerifyAgentPackage_Call {
+	return &MockUsecase_VerifyAgentPackage_Call{Call: _e.mock.On("VerifyAgentPackage", ctx, namespace, name)}
+}
+
+func (_c *MockUsecase_VerifyAgentPackage_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockUsecase_VerifyAgentPackage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_VerifyAgentPackage_Call) Return(agentPackage *v1.AgentPackage, err error) *MockUsecase_VerifyAgentPackage_Call {
+	_c.Call.Return(agentPackage, err)
+	return _c
+}
+
+func (_c *MockUsecase_VerifyAgentPackage_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*v1.AgentPackage, error)) *MockUsecase_VerifyAgentPackage_Call {
+	_c.Call.Return(run)
+	return _c
+}
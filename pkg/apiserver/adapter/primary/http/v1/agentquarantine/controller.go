@@ -0,0 +1,144 @@
+// Package agentquarantine contains the controller for manually quarantining agents.
+package agentquarantine
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// Controller exposes the agent quarantine sub-resource.
+type Controller struct {
+	logger *slog.Logger
+
+	quarantineUsecase usecase.AgentQuarantineManageUsecase
+}
+
+// NewController creates a new agent quarantine Controller.
+func NewController(
+	usecase usecase.AgentQuarantineManageUsecase,
+	logger *slog.Logger,
+) *Controller {
+	return &Controller{
+		logger:            logger,
+		quarantineUsecase: usecase,
+	}
+}
+
+// RoutesInfo returns the routes for the agent quarantine controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/quarantine",
+			Handler:     "http.v1.agentquarantine.Quarantine",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Quarantine),
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/api/v1/namespaces/:namespace/agents/:id/quarantine",
+			Handler:     "http.v1.agentquarantine.Unquarantine",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Unquarantine),
+		},
+	}
+}
+
+// Quarantine stops agent group propagation from pushing new config to the agent.
+//
+// @Summary  Quarantine Agent
+// @Tags agent
+// @Description Manually quarantine an agent. A manual quarantine stays until it is lifted.
+// @Accept  json
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Param  request body v1.AgentQuarantineRequest false "Optional quarantine reason"
+// @Success  200 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/quarantine [post].
+func (c *Controller) Quarantine(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	var req v1.AgentQuarantineRequest
+
+	// The body is optional: an empty request quarantines with the default reason.
+	if ctx.Request.ContentLength != 0 {
+		err = ginutil.BindJSON(ctx, &req)
+		if err != nil {
+			ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+			return
+		}
+	}
+
+	agent, err := c.quarantineUsecase.QuarantineAgent(ctx.Request.Context(), namespace, instanceUID, req.Reason)
+	if err != nil {
+		c.handleError(ctx, err, "An error occurred while quarantining the agent.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agent)
+}
+
+// Unquarantine lifts the agent's quarantine and re-applies its agent groups.
+//
+// @Summary  Unquarantine Agent
+// @Tags agent
+// @Description Lift a manual or automatic quarantine of an agent.
+// @Produce  json
+// @Param  namespace path string true "Namespace"
+// @Param  id path string true "Instance UID of the agent"
+// @Success  200 {object} v1.Agent
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/namespaces/{namespace}/agents/{id}/quarantine [delete].
+func (c *Controller) Unquarantine(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	agent, err := c.quarantineUsecase.UnquarantineAgent(ctx.Request.Context(), namespace, instanceUID)
+	if err != nil {
+		c.handleError(ctx, err, "An error occurred while lifting the agent's quarantine.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agent)
+}
+
+// handleError reports an agent in another namespace as not found and delegates the
+// rest to ginutil.HandleDomainError.
+func (c *Controller) handleError(ctx *gin.Context, err error, fallbackMessage string) {
+	if errors.Is(err, applicationport.ErrAgentNamespaceMismatch) {
+		ginutil.ResourceNotFoundError(ctx, "agent", ctx.Param("id"))
+
+		return
+	}
+
+	c.logger.Error(fallbackMessage, "error", err.Error())
+	ginutil.HandleDomainError(ctx, err, fallbackMessage)
+}
@@ -0,0 +1,161 @@
+package agentquarantine_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentquarantine"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	goleak.VerifyTestMain(m)
+}
+
+// mockQuarantineUsecase is a testify mock of usecase.AgentQuarantineManageUsecase.
+type mockQuarantineUsecase struct {
+	mock.Mock
+}
+
+func newMockQuarantineUsecase(t *testing.T) *mockQuarantineUsecase {
+	t.Helper()
+
+	m := &mockQuarantineUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockQuarantineUsecase) QuarantineAgent(
+	ctx context.Context, namespace string, instanceUID uuid.UUID, reason string,
+) (*v1.Agent, error) {
+	args := m.Called(ctx, namespace, instanceUID, reason)
+
+	res, _ := args.Get(0).(*v1.Agent)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockQuarantineUsecase) UnquarantineAgent(
+	ctx context.Context, namespace string, instanceUID uuid.UUID,
+) (*v1.Agent, error) {
+	args := m.Called(ctx, namespace, instanceUID)
+
+	res, _ := args.Get(0).(*v1.Agent)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func quarantinePath(instanceUID string) string {
+	return "/api/v1/namespaces/default/agents/" + instanceUID + "/quarantine"
+}
+
+func TestAgentQuarantineController_Quarantine(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "with a reason", body: `{"reason":"rollout canary"}`, reason: "rollout canary"},
+		{name: "without a body", body: "", reason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			usecase := newMockQuarantineUsecase(t)
+			ctrlBase.SetupRouter(agentquarantine.NewController(usecase, slog.Default()))
+
+			usecase.On("QuarantineAgent", mock.Anything, "default", instanceUID, tt.reason).
+				Return(&v1.Agent{Spec: v1.AgentSpec{Quarantine: &v1.AgentQuarantine{Reason: "rollout canary"}}}, nil)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+				quarantinePath(instanceUID.String()), strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			ctrlBase.Router.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "rollout canary", gjson.Get(recorder.Body.String(), "spec.quarantine.reason").String())
+		})
+	}
+
+	t.Run("returns 400 on an invalid instance UID", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		ctrlBase.SetupRouter(agentquarantine.NewController(newMockQuarantineUsecase(t), slog.Default()))
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, quarantinePath("not-a-uuid"), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentQuarantineController_Unquarantine(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+
+	t.Run("returns the agent", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockQuarantineUsecase(t)
+		ctrlBase.SetupRouter(agentquarantine.NewController(usecase, slog.Default()))
+
+		usecase.On("UnquarantineAgent", mock.Anything, "default", instanceUID).Return(&v1.Agent{}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, quarantinePath(instanceUID.String()), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.False(t, gjson.Get(recorder.Body.String(), "spec.quarantine").Exists())
+	})
+
+	t.Run("returns 404 for an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockQuarantineUsecase(t)
+		ctrlBase.SetupRouter(agentquarantine.NewController(usecase, slog.Default()))
+
+		usecase.On("UnquarantineAgent", mock.Anything, "default", instanceUID).
+			Return(nil, applicationport.ErrAgentNamespaceMismatch)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, quarantinePath(instanceUID.String()), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -70,6 +70,19 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 }
 
 // List retrieves a list of agent remote configs.
+//
+// @Summary  List Agent Remote Configs
+// @Tags agentremoteconfig
+// @Description Retrieve a list of agent remote configs.
+// @Produce json
+// @Success 200 {object} v1.ListResponse[v1.AgentRemoteConfig]
+// @Param namespace path string true "Namespace"
+// @Param limit query int false "Maximum number of agent remote configs to return"
+// @Param continue query string false "Token to continue listing agent remote configs"
+// @Param includeDeleted query bool false "Include soft-deleted agent remote configs"
+// @Failure 400 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentremoteconfigs [get].
 func (c *Controller) List(ctx *gin.Context) {
 	limit, err := ginutil.ParseInt64(ctx, "limit", 0)
 	if err != nil {
@@ -102,7 +115,7 @@ func (c *Controller) List(ctx *gin.Context) {
 		c.logger.Error(
 			"failed to list agent remote configs", "error", err.Error(),
 		)
-		ginutil.InternalServerError(
+		ginutil.HandleDomainError(
 			ctx, err,
 			"An error occurred while retrieving agent remote configs.",
 		)
@@ -114,6 +127,19 @@ func (c *Controller) List(ctx *gin.Context) {
 }
 
 // Get retrieves an agent remote config by its name.
+//
+// @Summary  Get Agent Remote Config
+// @Tags agentremoteconfig
+// @Description Retrieve an agent remote config by its name.
+// @Produce json
+// @Success 200 {object} v1.AgentRemoteConfig
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name of the agent remote config"
+// @Param includeDeleted query bool false "Include a soft-deleted agent remote config"
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentremoteconfigs/{name} [get].
 func (c *Controller) Get(ctx *gin.Context) {
 	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
@@ -164,6 +190,19 @@ func (c *Controller) Get(ctx *gin.Context) {
 }
 
 // Create creates a new agent remote config.
+//
+// @Summary  Create Agent Remote Config
+// @Tags agentremoteconfig
+// @Description Create a new agent remote config.
+// @Accept json
+// @Produce json
+// @Success 201 {object} v1.AgentRemoteConfig
+// @Param namespace path string true "Namespace"
+// @Param agentRemoteConfig body v1.AgentRemoteConfig true "Agent remote config to create"
+// @Failure 400 {object} map[string]any
+// @Failure 409 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentremoteconfigs [post].
 func (c *Controller) Create(ctx *gin.Context) {
 	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
@@ -192,7 +231,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 		c.logger.Error(
 			"failed to create agent remote config", "error", err.Error(),
 		)
-		ginutil.InternalServerError(
+		ginutil.HandleDomainError(
 			ctx, err,
 			"An error occurred while creating the agent remote config.",
 		)
@@ -209,6 +248,20 @@ func (c *Controller) Create(ctx *gin.Context) {
 }
 
 // Update updates an existing agent remote config.
+//
+// @Summary  Update Agent Remote Config
+// @Tags agentremoteconfig
+// @Description Update an existing agent remote config.
+// @Accept json
+// @Produce json
+// @Success 200 {object} v1.AgentRemoteConfig
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name of the agent remote config"
+// @Param agentRemoteConfig body v1.AgentRemoteConfig true "Updated agent remote config"
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentremoteconfigs/{name} [put].
 func (c *Controller) Update(ctx *gin.Context) {
 	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
@@ -257,6 +310,17 @@ func (c *Controller) Update(ctx *gin.Context) {
 }
 
 // Delete deletes an agent remote config by its name.
+//
+// @Summary  Delete Agent Remote Config
+// @Tags agentremoteconfig
+// @Description Delete an agent remote config by its name.
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name of the agent remote config"
+// @Success 204
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/agentremoteconfigs/{name} [delete].
 func (c *Controller) Delete(ctx *gin.Context) {
 	namespace, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
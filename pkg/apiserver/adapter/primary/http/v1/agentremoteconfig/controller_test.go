@@ -3,6 +3,7 @@ package agentremoteconfig_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -268,6 +269,18 @@ func TestController_Create(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
 
+	t.Run("returns 409 when the config already exists", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase, usecase := setup(t)
+		usecase.On("CreateAgentRemoteConfig", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("create agent remote config: %w", model.ErrResourceAlreadyExist))
+
+		recorder := doReq(t, ctrlBase.Router, http.MethodPost, base, `{"metadata":{"name":"cfg"}}`)
+
+		require.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
 	t.Run("returns 500 when the usecase fails", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,114 @@
+// Package agentrevocation contains the controller for revoking agent instance UIDs.
+package agentrevocation
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// Controller exposes the agent revocation sub-resource. Revocation is an admin operation
+// on the cluster-wide blacklist, so its routes are not namespaced.
+type Controller struct {
+	logger *slog.Logger
+
+	revocationUsecase usecase.AgentRevocationManageUsecase
+}
+
+// NewController creates a new agent revocation Controller.
+func NewController(
+	usecase usecase.AgentRevocationManageUsecase,
+	logger *slog.Logger,
+) *Controller {
+	return &Controller{
+		logger:            logger,
+		revocationUsecase: usecase,
+	}
+}
+
+// RoutesInfo returns the routes for the agent revocation controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/agents/:id/revoke",
+			Handler:     "http.v1.agentrevocation.Revoke",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Revoke),
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/api/v1/agents/:id/revoke",
+			Handler:     "http.v1.agentrevocation.Unrevoke",
+			HandlerFunc: ginutil.WithUUIDPathParam("id", c.Unrevoke),
+		},
+	}
+}
+
+// Revoke blacklists an agent instance UID and closes its connection.
+//
+// @Summary  Revoke Agent
+// @Tags agent
+// @Description Revoke an agent instance UID. The server refuses and closes its connections until it is unrevoked.
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "Instance UID of the agent"
+// @Param  request body v1.AgentRevocationRequest false "Optional revocation reason"
+// @Success  200 {object} v1.AgentRevocation
+// @Failure  400 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/agents/{id}/revoke [post].
+func (c *Controller) Revoke(ctx *gin.Context) {
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	var req v1.AgentRevocationRequest
+
+	// The body is optional: an empty request revokes with the default reason.
+	if ctx.Request.ContentLength != 0 {
+		err := ginutil.BindJSON(ctx, &req)
+		if err != nil {
+			ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+			return
+		}
+	}
+
+	revocation, err := c.revocationUsecase.RevokeAgent(ctx.Request.Context(), instanceUID, req.Reason)
+	if err != nil {
+		c.logger.Error("failed to revoke agent", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while revoking the agent.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revocation)
+}
+
+// Unrevoke removes an agent instance UID from the blacklist.
+//
+// @Summary  Unrevoke Agent
+// @Tags agent
+// @Description Remove the revocation of an agent instance UID so it may connect again.
+// @Param  id path string true "Instance UID of the agent"
+// @Success  204
+// @Failure  400 {object} ErrorModel
+// @Failure  404 {object} ErrorModel
+// @Failure  500 {object} ErrorModel
+// @Router  /api/v1/agents/{id}/revoke [delete].
+func (c *Controller) Unrevoke(ctx *gin.Context) {
+	instanceUID := ginutil.UUIDPathParam(ctx, "id")
+
+	err := c.revocationUsecase.UnrevokeAgent(ctx.Request.Context(), instanceUID)
+	if err != nil {
+		c.logger.Error("failed to unrevoke agent", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while unrevoking the agent.")
+
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,156 @@
+package agentrevocation_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentrevocation"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	goleak.VerifyTestMain(m)
+}
+
+// mockRevocationUsecase is a testify mock of usecase.AgentRevocationManageUsecase.
+type mockRevocationUsecase struct {
+	mock.Mock
+}
+
+func newMockRevocationUsecase(t *testing.T) *mockRevocationUsecase {
+	t.Helper()
+
+	m := &mockRevocationUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockRevocationUsecase) RevokeAgent(
+	ctx context.Context, instanceUID uuid.UUID, reason string,
+) (*v1.AgentRevocation, error) {
+	args := m.Called(ctx, instanceUID, reason)
+
+	res, _ := args.Get(0).(*v1.AgentRevocation)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockRevocationUsecase) UnrevokeAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	args := m.Called(ctx, instanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
+func revokePath(instanceUID string) string {
+	return "/api/v1/agents/" + instanceUID + "/revoke"
+}
+
+func TestAgentRevocationController_Revoke(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "with a reason", body: `{"reason":"leaked credentials"}`, reason: "leaked credentials"},
+		{name: "without a body", body: "", reason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlBase := testutil.NewBase(t).ForController()
+			usecase := newMockRevocationUsecase(t)
+			ctrlBase.SetupRouter(agentrevocation.NewController(usecase, slog.Default()))
+
+			usecase.On("RevokeAgent", mock.Anything, instanceUID, tt.reason).
+				Return(&v1.AgentRevocation{InstanceUID: instanceUID, Reason: "leaked credentials"}, nil)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+				revokePath(instanceUID.String()), strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			ctrlBase.Router.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, instanceUID.String(), gjson.Get(recorder.Body.String(), "instanceUid").String())
+			assert.Equal(t, "leaked credentials", gjson.Get(recorder.Body.String(), "reason").String())
+		})
+	}
+
+	t.Run("returns 400 on an invalid instance UID", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		ctrlBase.SetupRouter(agentrevocation.NewController(newMockRevocationUsecase(t), slog.Default()))
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, revokePath("not-a-uuid"), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAgentRevocationController_Unrevoke(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+
+	t.Run("returns 204", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockRevocationUsecase(t)
+		ctrlBase.SetupRouter(agentrevocation.NewController(usecase, slog.Default()))
+
+		usecase.On("UnrevokeAgent", mock.Anything, instanceUID).Return(nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, revokePath(instanceUID.String()), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("returns 404 for an instance UID that is not revoked", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockRevocationUsecase(t)
+		ctrlBase.SetupRouter(agentrevocation.NewController(usecase, slog.Default()))
+
+		usecase.On("UnrevokeAgent", mock.Anything, instanceUID).Return(model.ErrResourceNotExist)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, revokePath(instanceUID.String()), nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
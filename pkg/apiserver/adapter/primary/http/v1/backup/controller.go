@@ -0,0 +1,110 @@
+// Package backup contains the controller for exporting the managed resources as a
+// backup bundle and importing one back, for disaster recovery.
+package backup
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// exportFilename is the download name suggested for an exported bundle.
+const exportFilename = "opampcommander-backup.json"
+
+// Controller exposes the export and import endpoints.
+type Controller struct {
+	logger *slog.Logger
+
+	backupUsecase usecase.BackupUsecase
+}
+
+// NewController creates a new backup Controller.
+func NewController(
+	usecase usecase.BackupUsecase,
+	logger *slog.Logger,
+) *Controller {
+	return &Controller{
+		logger:        logger,
+		backupUsecase: usecase,
+	}
+}
+
+// RoutesInfo returns the routes for the backup controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/export",
+			Handler:     "http.v1.backup.Export",
+			HandlerFunc: c.Export,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api/v1/import",
+			Handler:     "http.v1.backup.Import",
+			HandlerFunc: c.Import,
+		},
+	}
+}
+
+// Export returns every agent group, agent remote config, certificate and agent package as a single bundle.
+//
+// @Summary  Export managed resources
+// @Tags  backup
+// @Description Export all AgentGroups, AgentRemoteConfigs, Certificates and AgentPackages as a backup bundle.
+// @Description The bundle contains certificate private keys; store it accordingly.
+// @Produce  json
+// @Success  200 {object} v1.BackupBundle
+// @Failure  500 {object} map[string]any
+// @Router  /api/v1/export [get].
+func (c *Controller) Export(ctx *gin.Context) {
+	bundle, err := c.backupUsecase.Export(ctx.Request.Context())
+	if err != nil {
+		c.logger.Error("failed to export resources", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while exporting resources.")
+
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+exportFilename+`"`)
+	ctx.JSON(http.StatusOK, bundle)
+}
+
+// Import restores the resources of a backup bundle. Missing resources are created,
+// differing ones updated and matching ones left as is, so the import can be re-run.
+//
+// @Summary  Import managed resources
+// @Tags  backup
+// @Description Recreate the AgentGroups, AgentRemoteConfigs, Certificates and AgentPackages of a backup bundle.
+// @Accept  json
+// @Produce  json
+// @Param  bundle body v1.BackupBundle true "Backup bundle produced by the export endpoint"
+// @Success  200 {object} v1.BackupImportResult
+// @Failure  400 {object} map[string]any
+// @Failure  500 {object} map[string]any
+// @Router  /api/v1/import [post].
+func (c *Controller) Import(ctx *gin.Context) {
+	var bundle v1.BackupBundle
+
+	err := ginutil.BindJSON(ctx, &bundle)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "body", "", err, false)
+
+		return
+	}
+
+	result, err := c.backupUsecase.Import(ctx.Request.Context(), &bundle)
+	if err != nil {
+		c.logger.Error("failed to import resources", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while importing resources.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,150 @@
+package backup_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/backup"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	goleak.VerifyTestMain(m)
+}
+
+// mockBackupUsecase is a testify mock of usecase.BackupUsecase.
+type mockBackupUsecase struct {
+	mock.Mock
+}
+
+func newMockBackupUsecase(t *testing.T) *mockBackupUsecase {
+	t.Helper()
+
+	m := &mockBackupUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockBackupUsecase) Export(ctx context.Context) (*v1.BackupBundle, error) {
+	args := m.Called(ctx)
+
+	res, _ := args.Get(0).(*v1.BackupBundle)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockBackupUsecase) Import(ctx context.Context, bundle *v1.BackupBundle) (*v1.BackupImportResult, error) {
+	args := m.Called(ctx, bundle)
+
+	res, _ := args.Get(0).(*v1.BackupImportResult)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func TestBackupController_Export(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := newMockBackupUsecase(t)
+	controller := backup.NewController(usecase, slog.Default())
+	ctrlBase.SetupRouter(controller)
+
+	usecase.On("Export", mock.Anything).Return(&v1.BackupBundle{
+		Kind:       v1.BackupBundleKind,
+		APIVersion: v1.APIVersion,
+		Certificates: []v1.Certificate{
+			{Metadata: v1.CertificateMetadata{Namespace: "default", Name: "tls"}},
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/export", nil)
+	require.NoError(t, err)
+	ctrlBase.Router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, v1.BackupBundleKind, gjson.Get(recorder.Body.String(), "kind").String())
+	assert.Equal(t, "tls", gjson.Get(recorder.Body.String(), "certificates.0.metadata.name").String())
+}
+
+func TestBackupController_Import(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the import result", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockBackupUsecase(t)
+		controller := backup.NewController(usecase, slog.Default())
+		ctrlBase.SetupRouter(controller)
+
+		usecase.On("Import", mock.Anything, mock.MatchedBy(func(bundle *v1.BackupBundle) bool {
+			return len(bundle.AgentGroups) == 1 && bundle.AgentGroups[0].Metadata.Name == "linux"
+		})).Return(&v1.BackupImportResult{Created: 1}, nil)
+
+		body := `{"kind":"BackupBundle","agentGroups":[{"metadata":{"namespace":"default","name":"linux"}}]}`
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/import",
+			strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(1), gjson.Get(recorder.Body.String(), "created").Int())
+	})
+
+	t.Run("returns 400 on a malformed bundle", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockBackupUsecase(t)
+		controller := backup.NewController(usecase, slog.Default())
+		ctrlBase.SetupRouter(controller)
+
+		usecase.On("Import", mock.Anything, mock.Anything).Return(nil, model.ErrInvalidArgument)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/import",
+			strings.NewReader(`{"kind":"AgentGroup"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("returns 400 on an unparsable body", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		controller := backup.NewController(newMockBackupUsecase(t), slog.Default())
+		ctrlBase.SetupRouter(controller)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/import",
+			strings.NewReader(`{not json`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
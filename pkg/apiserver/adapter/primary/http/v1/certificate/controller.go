@@ -4,6 +4,7 @@ package certificate
 import (
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -48,6 +49,12 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.certificate.Get",
 			HandlerFunc: c.Get,
 		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/namespaces/:namespace/certificates/:name/usage",
+			Handler:     "http.v1.certificate.GetUsage",
+			HandlerFunc: c.GetUsage,
+		},
 		{
 			Method:      http.MethodPost,
 			Path:        "/api/v1/namespaces/:namespace/certificates",
@@ -60,6 +67,12 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 			Handler:     "http.v1.certificate.Update",
 			HandlerFunc: c.Update,
 		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/api/v1/namespaces/:namespace/certificates",
+			Handler:     "http.v1.certificate.DeleteCollection",
+			HandlerFunc: c.DeleteCollection,
+		},
 		{
 			Method:      http.MethodDelete,
 			Path:        "/api/v1/namespaces/:namespace/certificates/:name",
@@ -162,6 +175,48 @@ func (c *Controller) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, certificate)
 }
 
+// GetUsage reports the agent groups and agents using a certificate.
+//
+// @Summary  Get Certificate Usage
+// @Tags certificate
+// @Description List the agent groups whose connection settings reference a certificate by name, and
+// @Description their agents with the connection settings status they reported. An agent is accepted
+// @Description when it reported applying the connection settings currently offered to it, e.g. after
+// @Description the certificate was rotated.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name of the certificate"
+// @Success 200 {object} v1.CertificateUsage
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/certificates/{name}/usage [get].
+func (c *Controller) GetUsage(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	name, err := ginutil.ParseString(ctx, "name", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "name", ctx.Param("name"), err, true)
+
+		return
+	}
+
+	usage, err := c.certificateUsecase.GetCertificateUsage(ctx.Request.Context(), namespace, name)
+	if err != nil {
+		c.logger.Error("failed to get certificate usage", "name", name, "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while retrieving the certificate usage.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
+
 // Create creates a new certificate.
 //
 // @Summary  Create Certificate
@@ -173,6 +228,7 @@ func (c *Controller) Get(ctx *gin.Context) {
 // @Param namespace path string true "Namespace"
 // @Param certificate body v1.Certificate true "Certificate to create"
 // @Failure 400 {object} map[string]any
+// @Failure 403 {object} map[string]any
 // @Failure 500 {object} map[string]any
 // @Router /api/v1/namespaces/{namespace}/certificates [post].
 func (c *Controller) Create(ctx *gin.Context) {
@@ -197,7 +253,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 	created, err := c.certificateUsecase.CreateCertificate(ctx.Request.Context(), &req)
 	if err != nil {
 		c.logger.Error("failed to create certificate", "error", err.Error())
-		ginutil.InternalServerError(ctx, err, "An error occurred while creating the certificate.")
+		ginutil.HandleDomainError(ctx, err, "An error occurred while creating the certificate.")
 
 		return
 	}
@@ -295,3 +351,46 @@ func (c *Controller) Delete(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// DeleteCollection soft-deletes the certificates whose attributes match a selector.
+//
+// @Summary  Delete Certificates by Selector
+// @Tags certificate
+// @Description Soft-delete every certificate in the namespace whose attributes match the selector.
+// @Description The selector is required, so an empty request cannot delete the whole namespace.
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param selector query []string true "Attribute to match (key=value)" collectionFormat(multi)
+// @Success 200 {object} v1.DeleteCollectionResponse
+// @Failure 400 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace}/certificates [delete].
+func (c *Controller) DeleteCollection(ctx *gin.Context) {
+	namespace, err := ginutil.ParseString(ctx, "namespace", true)
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "namespace", ctx.Param("namespace"), err, true)
+
+		return
+	}
+
+	selector, err := ginutil.ParseSelector(ctx.QueryArray("selector"))
+	if err == nil && len(selector) == 0 {
+		err = ginutil.ErrRequiredParam
+	}
+
+	if err != nil {
+		ginutil.HandleValidationError(ctx, "selector", strings.Join(ctx.QueryArray("selector"), ","), err, false)
+
+		return
+	}
+
+	response, err := c.certificateUsecase.DeleteCertificatesBySelector(ctx.Request.Context(), namespace, selector)
+	if err != nil {
+		c.logger.Error("failed to delete certificates by selector", "error", err.Error())
+		ginutil.HandleDomainError(ctx, err, "An error occurred while deleting the certificates.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
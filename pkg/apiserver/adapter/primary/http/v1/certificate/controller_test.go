@@ -2,12 +2,14 @@ package certificate_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -214,6 +216,59 @@ func TestCertificateController_Get_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestCertificateController_GetUsage(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := certificate.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	instanceUID := uuid.New()
+	usecase.EXPECT().GetCertificateUsage(mock.Anything, "default", testCertName).Return(&v1.CertificateUsage{
+		AgentGroups: []string{"collectors"},
+		Agents: []v1.CertificateUsageAgent{
+			{
+				InstanceUID:  instanceUID,
+				AgentGroups:  []string{"collectors"},
+				Status:       "FAILED",
+				ErrorMessage: "x509: certificate signed by unknown authority",
+				Accepted:     false,
+			},
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, testBasePath+"/"+testCertName+"/usage", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Equal(t, "collectors", gjson.Get(body, "agentGroups.0").String())
+	assert.Equal(t, instanceUID.String(), gjson.Get(body, "agents.0.instanceUid").String())
+	assert.Equal(t, "FAILED", gjson.Get(body, "agents.0.status").String())
+	assert.False(t, gjson.Get(body, "agents.0.accepted").Bool())
+}
+
+func TestCertificateController_GetUsage_NotFound(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := certificate.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	usecase.EXPECT().GetCertificateUsage(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, model.ErrResourceNotExist)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, testBasePath+"/notfound/usage", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
 func TestCertificateController_Create(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -333,6 +388,39 @@ func TestCertificateController_Create_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestCertificateController_Create_QuotaExceeded(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := certificate.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+	payload := v1.Certificate{
+		Metadata: v1.CertificateMetadata{
+			Name:       testCertName,
+			Attributes: v1.Attributes{},
+		},
+	}
+
+	usecase.EXPECT().CreateCertificate(mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("create certificate: %w", model.ErrQuotaExceeded))
+
+	jsonBody, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPost,
+		testBasePath,
+		strings.NewReader(string(jsonBody)),
+	)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, int64(http.StatusForbidden), gjson.Get(recorder.Body.String(), "status").Int())
+}
+
 func TestCertificateController_Update(t *testing.T) {
 	t.Parallel()
 	ctrlBase := testutil.NewBase(t).ForController()
@@ -502,3 +590,43 @@ func TestCertificateController_Delete_InternalError(t *testing.T) {
 	router.ServeHTTP(recorder, req)
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
+
+func TestCertificateController_DeleteCollection(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := certificate.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	usecase.EXPECT().
+		DeleteCertificatesBySelector(mock.Anything, "default", map[string]string{"environment": "test"}).
+		Return(&v1.DeleteCollectionResponse{
+			Kind:         v1.CertificateKind,
+			APIVersion:   v1.APIVersion,
+			DeletedCount: 2,
+		}, nil)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete,
+		testBasePath+"?selector=environment=test", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, int64(2), gjson.Get(recorder.Body.String(), "deletedCount").Int())
+}
+
+func TestCertificateController_DeleteCollection_MissingSelector(t *testing.T) {
+	t.Parallel()
+	ctrlBase := testutil.NewBase(t).ForController()
+	usecase := usecasemock.NewMockUsecase(t)
+	controller := certificate.NewController(usecase, ctrlBase.Logger)
+	ctrlBase.SetupRouter(controller)
+	router := ctrlBase.Router
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, testBasePath, nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
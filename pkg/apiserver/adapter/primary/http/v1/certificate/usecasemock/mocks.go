@@ -170,6 +170,80 @@ func (_c *MockUsecase_DeleteCertificate_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// DeleteCertificatesBySelector provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) DeleteCertificatesBySelector(ctx context.Context, namespace string, selector map[string]string) (*v1.DeleteCollectionResponse, error) {
+	ret := _mock.Called(ctx, namespace, selector)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCertificatesBySelector")
+	}
+
+	var r0 *v1.DeleteCollectionResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) (*v1.DeleteCollectionResponse, error)); ok {
+		return returnFunc(ctx, namespace, selector)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) *v1.DeleteCollectionResponse); ok {
+		r0 = returnFunc(ctx, namespace, selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.DeleteCollectionResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, map[string]string) error); ok {
+		r1 = returnFunc(ctx, namespace, selector)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_DeleteCertificatesBySelector_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCertificatesBySelector'
+type MockUsecase_DeleteCertificatesBySelector_Call struct {
+	*mock.Call
+}
+
+// DeleteCertificatesBySelector is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - selector map[string]string
+func (_e *MockUsecase_Expecter) DeleteCertificatesBySelector(ctx interface{}, namespace interface{}, selector interface{}) *MockUsecase_DeleteCertificatesBySelector_Call {
+	return &MockUsecase_DeleteCertificatesBySelector_Call{Call: _e.mock.On("DeleteCertificatesBySelector", ctx, namespace, selector)}
+}
+
+func (_c *MockUsecase_DeleteCertificatesBySelector_Call) Run(run func(ctx context.Context, namespace string, selector map[string]string)) *MockUsecase_DeleteCertificatesBySelector_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_DeleteCertificatesBySelector_Call) Return(deleteCollectionResponse *v1.DeleteCollectionResponse, err error) *MockUsecase_DeleteCertificatesBySelector_Call {
+	_c.Call.Return(deleteCollectionResponse, err)
+	return _c
+}
+
+func (_c *MockUsecase_DeleteCertificatesBySelector_Call) RunAndReturn(run func(ctx context.Context, namespace string, selector map[string]string) (*v1.DeleteCollectionResponse, error)) *MockUsecase_DeleteCertificatesBySelector_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCertificate provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) GetCertificate(ctx context.Context, namespace string, name string, options *port.GetOptions) (*v1.Certificate, error) {
 	ret := _mock.Called(ctx, namespace, name, options)
@@ -250,6 +324,80 @@ func (_c *MockUsecase_GetCertificate_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// GetCertificateUsage provides a mock function for the type MockUsecase
+func (_mock *MockUsecase) GetCertificateUsage(ctx context.Context, namespace string, name string) (*v1.CertificateUsage, error) {
+	ret := _mock.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCertificateUsage")
+	}
+
+	var r0 *v1.CertificateUsage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*v1.CertificateUsage, error)); ok {
+		return returnFunc(ctx, namespace, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *v1.CertificateUsage); ok {
+		r0 = returnFunc(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.CertificateUsage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUsecase_GetCertificateUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCertificateUsage'
+type MockUsecase_GetCertificateUsage_Call struct {
+	*mock.Call
+}
+
+// GetCertificateUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+func (_e *MockUsecase_Expecter) GetCertificateUsage(ctx interface{}, namespace interface{}, name interface{}) *MockUsecase_GetCertificateUsage_Call {
+	return &MockUsecase_GetCertificateUsage_Call{Call: _e.mock.On("GetCertificateUsage", ctx, namespace, name)}
+}
+
+func (_c *MockUsecase_GetCertificateUsage_Call) Run(run func(ctx context.Context, namespace string, name string)) *MockUsecase_GetCertificateUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUsecase_GetCertificateUsage_Call) Return(certificateUsage *v1.CertificateUsage, err error) *MockUsecase_GetCertificateUsage_Call {
+	_c.Call.Return(certificateUsage, err)
+	return _c
+}
+
+func (_c *MockUsecase_GetCertificateUsage_Call) RunAndReturn(run func(ctx context.Context, namespace string, name string) (*v1.CertificateUsage, error)) *MockUsecase_GetCertificateUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListCertificates provides a mock function for the type MockUsecase
 func (_mock *MockUsecase) ListCertificates(ctx context.Context, options *port.ListOptions) (*v1.ListResponse[v1.Certificate], error) {
 	ret := _mock.Called(ctx, options)
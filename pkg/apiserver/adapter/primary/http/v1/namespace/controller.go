@@ -67,6 +67,18 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 }
 
 // List retrieves a list of namespaces.
+//
+// @Summary  List Namespaces
+// @Tags namespace
+// @Description Retrieve a list of namespaces.
+// @Produce json
+// @Success 200 {object} v1.ListResponse[v1.Namespace]
+// @Param limit query int false "Maximum number of namespaces to return"
+// @Param continue query string false "Token to continue listing namespaces"
+// @Param includeDeleted query bool false "Include soft-deleted namespaces"
+// @Failure 400 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces [get].
 func (c *Controller) List(ctx *gin.Context) {
 	limit, err := ginutil.ParseInt64(ctx, "limit", 0)
 	if err != nil {
@@ -113,6 +125,18 @@ func (c *Controller) List(ctx *gin.Context) {
 }
 
 // Get retrieves a namespace by name.
+//
+// @Summary  Get Namespace
+// @Tags namespace
+// @Description Retrieve a namespace by its name.
+// @Produce json
+// @Success 200 {object} v1.Namespace
+// @Param namespace path string true "Name of the namespace"
+// @Param includeDeleted query bool false "Include a soft-deleted namespace"
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace} [get].
 func (c *Controller) Get(ctx *gin.Context) {
 	name, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
@@ -154,6 +178,17 @@ func (c *Controller) Get(ctx *gin.Context) {
 }
 
 // Create creates a new namespace.
+//
+// @Summary  Create Namespace
+// @Tags namespace
+// @Description Create a new namespace.
+// @Accept json
+// @Produce json
+// @Success 201 {object} v1.Namespace
+// @Param namespace body v1.Namespace true "Namespace to create"
+// @Failure 400 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces [post].
 func (c *Controller) Create(ctx *gin.Context) {
 	var req v1.Namespace
 
@@ -190,6 +225,19 @@ func (c *Controller) Create(ctx *gin.Context) {
 }
 
 // Update updates an existing namespace.
+//
+// @Summary  Update Namespace
+// @Tags namespace
+// @Description Update an existing namespace.
+// @Accept json
+// @Produce json
+// @Success 200 {object} v1.Namespace
+// @Param namespace path string true "Name of the namespace"
+// @Param body body v1.Namespace true "Updated namespace"
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace} [put].
 func (c *Controller) Update(ctx *gin.Context) {
 	name, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
@@ -231,6 +279,16 @@ func (c *Controller) Update(ctx *gin.Context) {
 }
 
 // Delete deletes a namespace by name.
+//
+// @Summary  Delete Namespace
+// @Tags namespace
+// @Description Delete a namespace by its name. The default namespace cannot be deleted.
+// @Param namespace path string true "Name of the namespace"
+// @Success 204
+// @Failure 400 {object} map[string]any
+// @Failure 404 {object} map[string]any
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/namespaces/{namespace} [delete].
 func (c *Controller) Delete(ctx *gin.Context) {
 	name, err := ginutil.ParseString(ctx, "namespace", true)
 	if err != nil {
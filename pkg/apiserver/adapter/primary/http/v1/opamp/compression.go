@@ -0,0 +1,106 @@
+package opamp
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+	headerAcceptEncoding  = "Accept-Encoding"
+	// encodingGzip is the only encoding value opamp-go recognizes, in either header.
+	encodingGzip = "gzip"
+
+	// gzipID1 and gzipID2 start every gzip stream. An OpAMP protobuf message never starts
+	// with them: 0x1f would be field 3 with the invalid wire type 7.
+	gzipID1     = 0x1f
+	gzipID2     = 0x8b
+	gzipIDBytes = 2
+)
+
+// WithCompression makes the server compress what it sends to agents that advertise
+// support for it: WebSocket connections negotiate permessage-deflate, and plain HTTP
+// responses are gzip-encoded when the request's Accept-Encoding lists gzip. On a
+// compressed WebSocket connection the message size limit applies both to the compressed
+// frames and to the size a message inflates to.
+func WithCompression(enabled bool) Option {
+	return func(c *Controller) {
+		c.enableCompression = enabled
+	}
+}
+
+// normalizeEncoding rewrites the encoding headers of a plain HTTP OpAMP request to the
+// exact values opamp-go compares them with. A gzip-compressed body is detected from its
+// Content-Encoding, in any case or as "x-gzip", or else from its leading bytes.
+func (c *Controller) normalizeEncoding(req *http.Request) {
+	if isGzipEncoding(req.Header.Get(headerContentEncoding)) || hasGzipMagic(req) {
+		req.Header.Set(headerContentEncoding, encodingGzip)
+	}
+
+	if c.enableCompression && acceptsGzip(req.Header.Values(headerAcceptEncoding)) {
+		req.Header.Set(headerAcceptEncoding, encodingGzip)
+	}
+}
+
+// hasGzipMagic reports whether the request body starts with the gzip header. The bytes
+// peeked at stay in the body.
+func hasGzipMagic(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return false
+	}
+
+	reader := bufio.NewReader(req.Body)
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{reader, req.Body}
+
+	head, err := reader.Peek(gzipIDBytes)
+
+	return err == nil && head[0] == gzipID1 && head[1] == gzipID2
+}
+
+func isGzipEncoding(encoding string) bool {
+	encoding = strings.TrimSpace(encoding)
+
+	return strings.EqualFold(encoding, encodingGzip) || strings.EqualFold(encoding, "x-gzip")
+}
+
+// acceptsGzip reports whether Accept-Encoding header values list gzip without a zero
+// quality, e.g. "deflate, gzip;q=0.8".
+func acceptsGzip(values []string) bool {
+	for _, value := range values {
+		for coding := range strings.SplitSeq(value, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if !isGzipEncoding(name) {
+				continue
+			}
+
+			if codingQuality(params) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// codingQuality returns the q parameter of a content coding, 1 when it has none.
+func codingQuality(params string) float64 {
+	for param := range strings.SplitSeq(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+
+		quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err == nil {
+			return quality
+		}
+	}
+
+	return 1
+}
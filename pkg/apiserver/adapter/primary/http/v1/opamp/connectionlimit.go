@@ -0,0 +1,145 @@
+package opamp
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultConnectionRetryAfter is the Retry-After an agent over the per-IP connection
+	// limit is first answered with.
+	DefaultConnectionRetryAfter = 5 * time.Second
+	// maxConnectionRetryAfter caps the Retry-After, which doubles with every connection
+	// an IP has rejected in a row.
+	maxConnectionRetryAfter = 5 * time.Minute
+)
+
+// WithMaxConnectionsPerIP limits how many OpAMP connections one client IP may hold open at
+// once; 0 or a negative value removes the limit. The client IP honours the trusted proxies
+// configured on the gin engine.
+func WithMaxConnectionsPerIP(maxConnections int) Option {
+	return func(c *Controller) {
+		if maxConnections > 0 {
+			c.connectionLimiter = newConnectionLimiter(maxConnections, DefaultConnectionRetryAfter)
+		}
+	}
+}
+
+// connectionLimiter counts the OpAMP connections open per client IP. A WebSocket
+// connection holds its slot until the connection is closed, a plain HTTP request until
+// it is answered.
+type connectionLimiter struct {
+	maxConnections int
+	retryAfter     time.Duration
+
+	mu   sync.Mutex
+	byIP map[string]*ipConnections
+}
+
+type ipConnections struct {
+	open int
+	// rejected is the number of connections rejected in a row since the last accepted one.
+	rejected int
+}
+
+func newConnectionLimiter(maxConnections int, retryAfter time.Duration) *connectionLimiter {
+	return &connectionLimiter{
+		maxConnections: maxConnections,
+		retryAfter:     retryAfter,
+		mu:             sync.Mutex{},
+		byIP:           make(map[string]*ipConnections),
+	}
+}
+
+// acquire takes a connection slot for ip. When ip is at the limit it returns false and
+// how long the client should wait before connecting again; the wait doubles with every
+// rejection in a row, so a client that keeps reconnecting backs off further.
+func (l *connectionLimiter) acquire(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	conns, ok := l.byIP[ip]
+	if !ok {
+		conns = &ipConnections{open: 0, rejected: 0}
+		l.byIP[ip] = conns
+	}
+
+	if conns.open >= l.maxConnections {
+		wait := l.retryAfter
+		for i := 0; i < conns.rejected && wait < maxConnectionRetryAfter; i++ {
+			wait *= 2
+		}
+
+		conns.rejected++
+
+		return false, min(wait, maxConnectionRetryAfter)
+	}
+
+	conns.open++
+	conns.rejected = 0
+
+	return true, 0
+}
+
+// release frees a connection slot of ip.
+func (l *connectionLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	conns, ok := l.byIP[ip]
+	if !ok {
+		return
+	}
+
+	conns.open--
+	if conns.open <= 0 {
+		delete(l.byIP, ip)
+	}
+}
+
+// retryAfterSeconds formats a wait as the seconds of a Retry-After header, rounded up.
+func retryAfterSeconds(wait time.Duration) string {
+	return strconv.FormatInt(int64((wait+time.Second-1)/time.Second), 10)
+}
+
+// connectionSlotWriter hands a connection slot over to the WebSocket connection the
+// upgrader hijacks, which releases it once closed. A request that is not upgraded keeps
+// the slot until Handle returns.
+type connectionSlotWriter struct {
+	gin.ResponseWriter
+
+	release  func()
+	hijacked bool
+}
+
+// Hijack implements http.Hijacker.
+func (w *connectionSlotWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, readWriter, err := w.ResponseWriter.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hijack the OpAMP connection: %w", err)
+	}
+
+	w.hijacked = true
+
+	return &connectionSlotConn{Conn: conn, release: sync.OnceFunc(w.release)}, readWriter, nil
+}
+
+// connectionSlotConn releases its connection slot when closed.
+type connectionSlotConn struct {
+	net.Conn
+
+	release func()
+}
+
+// Close implements net.Conn.
+func (c *connectionSlotConn) Close() error {
+	defer c.release()
+
+	return c.Conn.Close() //nolint:wrapcheck // the error of the wrapped connection is passed through as is
+}
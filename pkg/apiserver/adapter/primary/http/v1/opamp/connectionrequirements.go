@@ -0,0 +1,72 @@
+package opamp
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/gorilla/websocket"
+)
+
+// WithRequiredHeaders makes the server reject OpAMP connections that do not present every
+// header in headers with the given value, such as a shared secret. An empty value only
+// requires the header to be present. The check applies to WebSocket upgrades and plain
+// HTTP requests alike, and a rejected connection is answered with 403.
+func WithRequiredHeaders(headers map[string]string) Option {
+	return func(c *Controller) {
+		if len(headers) == 0 {
+			return
+		}
+
+		c.requiredHeaders = make(map[string]string, len(headers))
+		for name, value := range headers {
+			c.requiredHeaders[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+}
+
+// WithRequiredSubprotocol makes the server reject WebSocket upgrades whose
+// Sec-WebSocket-Protocol header does not offer subprotocol with 400. Plain HTTP requests
+// have no subprotocol and are not checked. An empty subprotocol removes the requirement.
+//
+// The subprotocol is only checked: opamp-go's upgrader does not echo it back in the
+// handshake response, which WebSocket clients other than browsers accept.
+func WithRequiredSubprotocol(subprotocol string) Option {
+	return func(c *Controller) {
+		c.requiredSubprotocol = subprotocol
+	}
+}
+
+// checkConnectionRequirements returns the status to reject req with, or 0 when req meets
+// the configured required headers and subprotocol.
+func (c *Controller) checkConnectionRequirements(req *http.Request, isWebSocket bool) int {
+	for name, want := range c.requiredHeaders {
+		values, ok := req.Header[name]
+		if !ok || !slices.ContainsFunc(values, func(got string) bool { return headerValueMatches(got, want) }) {
+			c.logger.Warn("rejecting OpAMP connection without a required header",
+				slog.String("header", name), slog.String("remoteAddr", req.RemoteAddr))
+
+			return http.StatusForbidden
+		}
+	}
+
+	if isWebSocket && c.requiredSubprotocol != "" &&
+		!slices.Contains(websocket.Subprotocols(req), c.requiredSubprotocol) {
+		c.logger.Warn("rejecting OpAMP connection without the required subprotocol",
+			slog.String("subprotocol", c.requiredSubprotocol), slog.String("remoteAddr", req.RemoteAddr))
+
+		return http.StatusBadRequest
+	}
+
+	return 0
+}
+
+// headerValueMatches compares a header value in constant time, as it may be a secret.
+func headerValueMatches(got, want string) bool {
+	if want == "" {
+		return true
+	}
+
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
@@ -3,6 +3,7 @@ package opamp
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -23,6 +24,14 @@ type Controller struct {
 
 	opampServer       opampServer.OpAMPServer
 	enableCompression bool
+	// maxMessageBytes is the largest message an agent may send; non-positive means unlimited.
+	maxMessageBytes int64
+	// connectionLimiter caps the connections per client IP; nil means unlimited.
+	connectionLimiter *connectionLimiter
+	// requiredHeaders are the headers, by canonical name, a connection must present.
+	requiredHeaders map[string]string
+	// requiredSubprotocol is the subprotocol a WebSocket upgrade must offer; empty means any.
+	requiredSubprotocol string
 
 	// usecases
 	opampUsecase usecase.OpAMPUsecase
@@ -35,6 +44,7 @@ type Option func(*Controller)
 func NewController(
 	opampUsecase usecase.OpAMPUsecase,
 	logger *slog.Logger,
+	opts ...Option,
 ) *Controller {
 	ops := opampServer.New(&Logger{
 		logger: logger,
@@ -45,12 +55,21 @@ func NewController(
 		opampUsecase: opampUsecase,
 
 		enableCompression: false,
+		maxMessageBytes:   DefaultMaxMessageBytes,
+		connectionLimiter: nil,
+
+		requiredHeaders:     nil,
+		requiredSubprotocol: "",
 
 		handler:     nil, // fill below
 		ConnContext: nil, // fill below
 		opampServer: ops,
 	}
 
+	for _, opt := range opts {
+		opt(controller)
+	}
+
 	var err error
 
 	controller.handler, controller.ConnContext, err = ops.Attach(opampServer.Settings{
@@ -71,6 +90,7 @@ func NewController(
 
 // OnConnecting is a method that handles the connection request.
 // It is an adapter for the opampServer's OnConnecting callback.
+// A connection without the configured required headers or subprotocol is rejected.
 func (c *Controller) OnConnecting(req *http.Request) types.ConnectionResponse {
 	c.logger.Debug("OnConnecting", slog.Any("req", req))
 
@@ -79,6 +99,16 @@ func (c *Controller) OnConnecting(req *http.Request) types.ConnectionResponse {
 	// HTTP connections use POST method without upgrade
 	isWebSocket := req.Header.Get("Upgrade") == "websocket"
 
+	rejectStatus := c.checkConnectionRequirements(req, isWebSocket)
+	if rejectStatus != 0 {
+		//exhaustruct:ignore
+		return types.ConnectionResponse{
+			Accept:             false,
+			HTTPStatusCode:     rejectStatus,
+			HTTPResponseHeader: map[string]string{},
+		}
+	}
+
 	return types.ConnectionResponse{
 		Accept:             true,
 		HTTPStatusCode:     http.StatusOK,
@@ -114,7 +144,88 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 }
 
 // Handle is a method that handles the HTTP request.
+// Messages larger than the configured limit close a WebSocket connection and are answered
+// with 413 over plain HTTP. A client IP over the connection limit is answered with 429.
+// A gzip-compressed plain HTTP message is decompressed before it is decoded.
 func (c *Controller) Handle(ctx *gin.Context) {
 	c.logger.Info("Handle", "message", "start")
+
+	if c.connectionLimiter != nil {
+		release, ok := c.acquireConnectionSlot(ctx)
+		if !ok {
+			return
+		}
+
+		defer release()
+	}
+
+	// opamp-go serves requests with a protobuf body over plain HTTP and upgrades the rest.
+	plainHTTP := ctx.GetHeader("Content-Type") == "application/x-protobuf"
+	if plainHTTP {
+		c.normalizeEncoding(ctx.Request)
+	}
+
+	if c.maxMessageBytes <= 0 {
+		c.handler(ctx.Writer, ctx.Request)
+
+		return
+	}
+
+	if !plainHTTP {
+		c.handler(&messageLimitWriter{
+			ResponseWriter: ctx.Writer,
+			maxBytes:       c.maxMessageBytes,
+			logger:         c.logger,
+		}, ctx.Request)
+
+		return
+	}
+
+	err := limitRequestBody(ctx.Request, c.maxMessageBytes)
+
+	switch {
+	case errors.Is(err, ErrMessageTooLarge):
+		c.logger.Warn("rejecting OpAMP request", slog.String("error", err.Error()))
+		ctx.AbortWithStatus(http.StatusRequestEntityTooLarge)
+
+		return
+	case err != nil:
+		c.logger.Debug("cannot read OpAMP request", slog.String("error", err.Error()))
+		ctx.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
 	c.handler(ctx.Writer, ctx.Request)
 }
+
+// acquireConnectionSlot takes a connection slot for the client IP and makes ctx.Writer
+// hand it to the WebSocket connection, if the request is upgraded. The returned func
+// releases the slot otherwise. When the IP is over the limit, the request is answered
+// with 429 and a Retry-After telling the agent when to reconnect.
+func (c *Controller) acquireConnectionSlot(ctx *gin.Context) (func(), bool) {
+	clientIP := ctx.ClientIP()
+
+	ok, wait := c.connectionLimiter.acquire(clientIP)
+	if !ok {
+		c.logger.Warn("rejecting OpAMP connection over the per-IP limit",
+			slog.String("clientIP", clientIP), slog.Duration("retryAfter", wait))
+		ctx.Header("Retry-After", retryAfterSeconds(wait))
+		ctx.AbortWithStatus(http.StatusTooManyRequests)
+
+		return nil, false
+	}
+
+	writer := &connectionSlotWriter{
+		ResponseWriter: ctx.Writer,
+		release:        func() { c.connectionLimiter.release(clientIP) },
+		hijacked:       false,
+	}
+	ctx.Writer = writer
+
+	return func() {
+		if !writer.hijacked {
+			writer.release()
+		}
+	}, true
+}
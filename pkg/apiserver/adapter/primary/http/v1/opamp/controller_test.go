@@ -1,18 +1,30 @@
 package opamp_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	opamptypes "github.com/open-telemetry/opamp-go/server/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/opamp"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
@@ -23,24 +35,38 @@ func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m)
 }
 
-// spyUsecase is a no-op OpAMPUsecase that records OnConnectedWithType calls. A plain spy is
+// spyUsecase is a no-op OpAMPUsecase that records OnConnectedWithType and OnMessage calls. A plain spy is
 // used instead of testify so the opamp-go server can drive callbacks freely during Handle
 // without tripping strict expectations.
 type spyUsecase struct {
+	// mu guards the OnConnectedWithType fields against WebSocket connections opened at once.
+	mu                       sync.Mutex
 	onConnectedWithTypeCalls int
 	lastIsWebSocket          bool
+	lastMessage              *protobufs.AgentToServer
+	// onMessageCalls is atomic because WebSocket messages are handled on opamp-go's goroutine.
+	onMessageCalls atomic.Int32
 }
 
 func (s *spyUsecase) OnConnected(_ context.Context, _ opamptypes.Connection) {}
 
 func (s *spyUsecase) OnConnectedWithType(_ context.Context, _ opamptypes.Connection, isWebSocket bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.onConnectedWithTypeCalls++
 	s.lastIsWebSocket = isWebSocket
 }
 
 func (s *spyUsecase) OnMessage(
-	_ context.Context, _ opamptypes.Connection, _ *protobufs.AgentToServer,
+	_ context.Context, _ opamptypes.Connection, message *protobufs.AgentToServer,
 ) *protobufs.ServerToAgent {
+	s.mu.Lock()
+	s.lastMessage = message
+	s.mu.Unlock()
+
+	s.onMessageCalls.Add(1)
+
 	return nil
 }
 
@@ -128,3 +154,408 @@ func TestController_Handle(t *testing.T) {
 
 	assert.NotEqual(t, http.StatusNotFound, recorder.Code)
 }
+
+func TestController_Handle_MessageSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	const maxMessageBytes = 1024
+
+	newServer := func(t *testing.T) (*httptest.Server, *spyUsecase) {
+		t.Helper()
+
+		spy := &spyUsecase{}
+		ctrlBase := testutil.NewBase(t).ForController()
+		ctrlBase.SetupRouter(opamp.NewController(spy, slog.Default(), opamp.WithMaxMessageBytes(maxMessageBytes)))
+
+		server := httptest.NewServer(ctrlBase.Router)
+		t.Cleanup(server.Close)
+
+		return server, spy
+	}
+
+	dial := func(t *testing.T, server *httptest.Server) *websocket.Conn {
+		t.Helper()
+
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/opamp"
+
+		conn, resp, err := websocket.DefaultDialer.DialContext(t.Context(), url, nil)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		t.Cleanup(func() { _ = conn.Close() })
+
+		return conn
+	}
+
+	// assertClosed asserts that the server closes the connection, well before the read deadline.
+	assertClosed := func(t *testing.T, conn *websocket.Conn) {
+		t.Helper()
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		_, _, err := conn.ReadMessage()
+		require.Error(t, err)
+
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			assert.False(t, netErr.Timeout(), "the connection should be closed, not left waiting")
+		}
+	}
+
+	t.Run("websocket message within the limit is handled", func(t *testing.T) {
+		t.Parallel()
+
+		server, spy := newServer(t)
+		conn := dial(t, server)
+
+		require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, agentToServerMessage(t, 0)))
+		assert.Eventually(t, func() bool { return spy.onMessageCalls.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("oversized websocket message closes the connection", func(t *testing.T) {
+		t.Parallel()
+
+		server, spy := newServer(t)
+		conn := dial(t, server)
+
+		require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, agentToServerMessage(t, 2*maxMessageBytes)))
+		assertClosed(t, conn)
+		assert.Zero(t, spy.onMessageCalls.Load())
+	})
+
+	t.Run("frame header announcing a huge payload closes the connection before it is sent", func(t *testing.T) {
+		t.Parallel()
+
+		server, spy := newServer(t)
+		conn := dial(t, server)
+
+		// A masked, final binary frame claiming 1 TiB of payload; none of it follows.
+		header := []byte{0x82, 0x80 | 127, 0, 0, 1, 0, 0, 0, 0, 0, 1, 2, 3, 4}
+		_, err := conn.NetConn().Write(header)
+		require.NoError(t, err)
+
+		assertClosed(t, conn)
+		assert.Zero(t, spy.onMessageCalls.Load())
+	})
+
+	t.Run("compressed websocket message is limited by its inflated size", func(t *testing.T) {
+		t.Parallel()
+
+		spy := &spyUsecase{}
+		server := newHTTPServer(t, opamp.NewController(spy, slog.Default(),
+			opamp.WithMaxMessageBytes(maxMessageBytes), opamp.WithCompression(true)))
+
+		//exhaustruct:ignore
+		dialer := websocket.Dialer{EnableCompression: true}
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/opamp"
+
+		conn, resp, err := dialer.DialContext(t.Context(), url, nil)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		t.Cleanup(func() { _ = conn.Close() })
+		require.Contains(t, resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+		// Both messages compress to well within the limit.
+		require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, agentToServerMessage(t, maxMessageBytes/2)))
+		assert.Eventually(t, func() bool { return spy.onMessageCalls.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, agentToServerMessage(t, 64*maxMessageBytes)))
+		assertClosed(t, conn)
+		assert.Equal(t, int32(1), spy.onMessageCalls.Load())
+	})
+
+	t.Run("oversized plain http request is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		server, spy := newServer(t)
+
+		var compressed bytes.Buffer
+
+		writer := gzip.NewWriter(&compressed)
+		_, err := writer.Write(agentToServerMessage(t, 2*maxMessageBytes)[1:])
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		for name, encoding := range map[string]string{"identity": "", "gzip": "gzip"} {
+			body := agentToServerMessage(t, 2*maxMessageBytes)[1:]
+			if encoding != "" {
+				body = compressed.Bytes()
+			}
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+"/api/v1/opamp",
+				bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			req.Header.Set("Content-Encoding", encoding)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode, name)
+		}
+
+		assert.Zero(t, spy.onMessageCalls.Load())
+	})
+}
+
+func TestController_Handle_Compression(t *testing.T) {
+	t.Parallel()
+
+	message := agentToServerMessage(t, 64)[1:]
+
+	var compressed bytes.Buffer
+
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write(message)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	post := func(t *testing.T, server *httptest.Server, contentEncoding, acceptEncoding string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+"/api/v1/opamp",
+			bytes.NewReader(compressed.Bytes()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("Content-Encoding", contentEncoding)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+
+		return resp
+	}
+
+	for _, maxMessageBytes := range []int64{0, -1} {
+		for name, contentEncoding := range map[string]string{
+			"gzip":                   "gzip",
+			"upper case":             "GZIP",
+			"x-gzip":                 "x-gzip",
+			"detected without label": "",
+		} {
+			t.Run(fmt.Sprintf("%s with max message bytes %d", name, maxMessageBytes), func(t *testing.T) {
+				t.Parallel()
+
+				spy := &spyUsecase{}
+				server := newHTTPServer(t, opamp.NewController(spy, slog.Default(), opamp.WithMaxMessageBytes(maxMessageBytes)))
+
+				resp := post(t, server, contentEncoding, "identity")
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+				spy.mu.Lock()
+				defer spy.mu.Unlock()
+
+				require.NotNil(t, spy.lastMessage)
+
+				//exhaustruct:ignore
+				want := &protobufs.AgentToServer{}
+				require.NoError(t, proto.Unmarshal(message, want))
+				assert.True(t, proto.Equal(want, spy.lastMessage), "the decompressed message is decoded")
+			})
+		}
+	}
+
+	t.Run("responses are compressed for agents advertising gzip", func(t *testing.T) {
+		t.Parallel()
+
+		server := newHTTPServer(t, opamp.NewController(&spyUsecase{}, slog.Default(), opamp.WithCompression(true)))
+
+		resp := post(t, server, "gzip", "deflate, gzip;q=0.5")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+		reader, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+
+		//exhaustruct:ignore
+		response := &protobufs.ServerToAgent{}
+		require.NoError(t, proto.Unmarshal(body, response))
+
+		resp = post(t, server, "gzip", "deflate, gzip;q=0")
+		assert.Empty(t, resp.Header.Get("Content-Encoding"), "gzip;q=0 refuses gzip")
+	})
+}
+
+// newHTTPServer serves the controller with its ConnContext, which opamp-go needs to answer
+// plain HTTP requests.
+func newHTTPServer(t *testing.T, controller *opamp.Controller) *httptest.Server {
+	t.Helper()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	ctrlBase.SetupRouter(controller)
+
+	server := httptest.NewUnstartedServer(ctrlBase.Router)
+	server.Config.ConnContext = controller.ConnContext
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestController_Handle_ConnectionLimitPerIP(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	ctrlBase.SetupRouter(opamp.NewController(&spyUsecase{}, slog.Default(), opamp.WithMaxConnectionsPerIP(2)))
+
+	server := httptest.NewServer(ctrlBase.Router)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/opamp"
+
+	// dial connects as clientIP, which the test router takes from X-Forwarded-For as it
+	// trusts every proxy.
+	dial := func(clientIP string) (*websocket.Conn, *http.Response, error) {
+		header := http.Header{}
+		header.Set("X-Forwarded-For", clientIP)
+
+		conn, resp, err := websocket.DefaultDialer.DialContext(t.Context(), url, header)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if conn != nil {
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+
+		return conn, resp, err //nolint:wrapcheck // test helper
+	}
+
+	first, _, err := dial("203.0.113.1")
+	require.NoError(t, err)
+
+	_, _, err = dial("203.0.113.1")
+	require.NoError(t, err)
+
+	// Connections beyond the cap are rejected, with a Retry-After that grows.
+	for _, wantRetryAfter := range []string{"5", "10"} {
+		_, resp, err := dial("203.0.113.1")
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, wantRetryAfter, resp.Header.Get("Retry-After"))
+	}
+
+	// The cap is per IP.
+	_, _, err = dial("203.0.113.2")
+	require.NoError(t, err)
+
+	// Closing a connection frees its slot.
+	require.NoError(t, first.Close())
+	assert.Eventually(t, func() bool {
+		_, _, err := dial("203.0.113.1")
+
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestController_Handle_ConnectionRequirements(t *testing.T) {
+	t.Parallel()
+
+	ctrlBase := testutil.NewBase(t).ForController()
+	ctrlBase.SetupRouter(opamp.NewController(&spyUsecase{}, slog.Default(),
+		opamp.WithRequiredHeaders(map[string]string{"x-test-header": "secret"}),
+		opamp.WithRequiredSubprotocol("opamp")))
+
+	server := httptest.NewServer(ctrlBase.Router)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/opamp"
+
+	dial := func(header http.Header, subprotocols ...string) (*http.Response, error) {
+		dialer := *websocket.DefaultDialer
+		dialer.Subprotocols = subprotocols
+
+		conn, resp, err := dialer.DialContext(t.Context(), url, header)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if conn != nil {
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+
+		return resp, err //nolint:wrapcheck // test helper
+	}
+
+	withHeader := func(value string) http.Header {
+		header := http.Header{}
+		header.Set("X-Test-Header", value)
+
+		return header
+	}
+
+	t.Run("connection presenting the header and subprotocol is upgraded", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := dial(withHeader("secret"), "other", "opamp")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	})
+
+	t.Run("connection without the header is forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := dial(http.Header{}, "opamp")
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("connection with the wrong header value is forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := dial(withHeader("guess"), "opamp")
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("upgrade without the subprotocol is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := dial(withHeader("secret"), "other")
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("plain http request without the header is forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+"/api/v1/opamp",
+			bytes.NewReader(nil))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+// agentToServerMessage returns an AgentToServer message framed for the OpAMP WebSocket
+// transport (a zero header byte before the protobuf), padded with at least padding bytes.
+func agentToServerMessage(t *testing.T, padding int) []byte {
+	t.Helper()
+
+	//exhaustruct:ignore
+	message := &protobufs.AgentToServer{
+		InstanceUid: make([]byte, 16),
+		//exhaustruct:ignore
+		AgentDescription: &protobufs.AgentDescription{
+			IdentifyingAttributes: []*protobufs.KeyValue{{
+				Key: "padding",
+				//exhaustruct:ignore
+				Value: &protobufs.AnyValue{
+					Value: &protobufs.AnyValue_StringValue{StringValue: strings.Repeat("x", padding)},
+				},
+			}},
+		},
+	}
+
+	data, err := proto.Marshal(message)
+	require.NoError(t, err)
+
+	return append([]byte{0}, data...)
+}
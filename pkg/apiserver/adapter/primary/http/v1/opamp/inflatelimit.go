@@ -0,0 +1,119 @@
+package opamp
+
+import (
+	"compress/flate"
+	"fmt"
+	"io"
+)
+
+// inflateLimiter inflates the payload of a compressed WebSocket message (RFC 7692) as it
+// arrives, to tell how large the message grows once the WebSocket library inflates it.
+// The payload is inflated in a goroutine that asks for input only once it has inflated
+// everything it was given, so write returns only after its bytes are accounted for.
+type inflateLimiter struct {
+	maxBytes int64
+
+	input  chan []byte
+	needed chan struct{}
+	done   chan error
+	// finished is set once the goroutine has returned, with err the reason it did.
+	finished bool
+	err      error
+}
+
+func newInflateLimiter(maxBytes int64) *inflateLimiter {
+	limiter := &inflateLimiter{
+		maxBytes: maxBytes,
+		input:    make(chan []byte),
+		needed:   make(chan struct{}),
+		done:     make(chan error, 1),
+		finished: false,
+		err:      nil,
+	}
+
+	go limiter.run()
+
+	limiter.wait()
+
+	return limiter
+}
+
+// write inflates compressed payload bytes, failing with ErrMessageTooLarge once the message
+// inflates beyond the limit.
+func (l *inflateLimiter) write(compressed []byte) error {
+	if l.finished {
+		return l.err
+	}
+
+	l.input <- compressed
+	l.wait()
+
+	return l.err
+}
+
+// close ends the message and waits for the goroutine to return.
+func (l *inflateLimiter) close() error {
+	if !l.finished {
+		close(l.input)
+
+		l.finished = true
+		l.err = <-l.done
+	}
+
+	return l.err
+}
+
+func (l *inflateLimiter) wait() {
+	select {
+	case <-l.needed:
+	case err := <-l.done:
+		l.finished = true
+		l.err = err
+	}
+}
+
+func (l *inflateLimiter) run() {
+	reader := flate.NewReader(&inflateInput{limiter: l, chunk: nil, closed: false})
+
+	inflated, _ := io.Copy(io.Discard, io.LimitReader(reader, l.maxBytes+1))
+	if inflated > l.maxBytes {
+		l.done <- fmt.Errorf("%w: message inflating to more than %d bytes", ErrMessageTooLarge, l.maxBytes)
+
+		return
+	}
+
+	// Invalid compressed data is left to the WebSocket library to reject.
+	l.done <- nil
+}
+
+// inflateInput hands the inflating goroutine the payload bytes written to the limiter.
+type inflateInput struct {
+	limiter *inflateLimiter
+	chunk   []byte
+	closed  bool
+}
+
+// Read implements io.Reader.
+func (r *inflateInput) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, io.EOF
+	}
+
+	if len(r.chunk) == 0 {
+		r.limiter.needed <- struct{}{}
+
+		chunk, ok := <-r.limiter.input
+		if !ok {
+			r.closed = true
+
+			return 0, io.EOF
+		}
+
+		r.chunk = chunk
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
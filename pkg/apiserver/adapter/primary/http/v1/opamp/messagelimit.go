@@ -0,0 +1,321 @@
+package opamp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxMessageBytes is the largest OpAMP message an agent may send unless configured otherwise.
+const DefaultMaxMessageBytes = 16 << 20
+
+const (
+	// maxFrameHeaderBytes is the size of the largest WebSocket frame header (RFC 6455 5.2):
+	// 2 fixed bytes, an 8-byte extended payload length and a 4-byte masking key.
+	maxFrameHeaderBytes = 14
+
+	frameFinBit        = 0x80
+	frameRSV1Bit       = 0x40
+	frameOpcodeMask    = 0x0f
+	frameMaskBit       = 0x80
+	framePayloadMask   = 0x7f
+	frameMaskKeyBytes  = 4
+	frameLength16      = 126
+	frameLength64      = 127
+	firstControlOpcode = 0x8
+)
+
+// ErrMessageTooLarge is returned when an agent sends an OpAMP message larger than the limit.
+var ErrMessageTooLarge = errors.New("OpAMP message exceeds the maximum size")
+
+// WithMaxMessageBytes limits the size of the OpAMP messages agents may send.
+// 0 keeps DefaultMaxMessageBytes and a negative value removes the limit.
+func WithMaxMessageBytes(maxBytes int64) Option {
+	return func(c *Controller) {
+		if maxBytes != 0 {
+			c.maxMessageBytes = maxBytes
+		}
+	}
+}
+
+// limitRequestBody reads the body of a plain HTTP OpAMP request, decompressing it when it is
+// gzip-encoded, and replaces it with the bytes read. It fails with ErrMessageTooLarge as soon
+// as more than maxBytes are read, so an oversized or highly compressed body is never buffered
+// in full.
+func limitRequestBody(req *http.Request, maxBytes int64) error {
+	var body io.Reader = req.Body
+
+	// opamp-go would decompress the body again, so the header goes with the compression.
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return fmt.Errorf("failed to decompress OpAMP request body: %w", err)
+		}
+
+		defer func() { _ = reader.Close() }()
+
+		body = reader
+
+		req.Header.Del("Content-Encoding")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read OpAMP request body: %w", err)
+	}
+
+	if int64(len(data)) > maxBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrMessageTooLarge, maxBytes)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+
+	return nil
+}
+
+// messageLimitWriter hands the WebSocket upgrader a connection that enforces the message
+// size limit. opamp-go reads whole messages into memory and offers no read limit of its own.
+type messageLimitWriter struct {
+	gin.ResponseWriter
+
+	maxBytes int64
+	logger   *slog.Logger
+}
+
+// Hijack implements http.Hijacker.
+func (w *messageLimitWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, readWriter, err := w.ResponseWriter.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hijack the OpAMP connection: %w", err)
+	}
+
+	//exhaustruct:ignore
+	limited := &messageLimitConn{
+		Conn:    conn,
+		logger:  w.logger,
+		scanner: frameScanner{maxBytes: w.maxBytes},
+	}
+
+	return limited, readWriter, nil
+}
+
+// messageLimitConn follows the frame headers an agent sends and closes the connection as
+// soon as a header announces a message beyond the limit, before its payload is read, or
+// as soon as a compressed message inflates beyond it.
+type messageLimitConn struct {
+	net.Conn
+
+	logger  *slog.Logger
+	scanner frameScanner
+	err     error
+}
+
+// Read implements net.Conn.
+func (c *messageLimitConn) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.Conn.Read(p)
+
+	scanErr := c.scanner.scan(p[:n])
+	if scanErr != nil || err != nil {
+		c.scanner.close()
+	}
+
+	if scanErr != nil {
+		c.err = scanErr
+		c.logger.Warn("closing OpAMP connection",
+			slog.String("remoteAddr", c.RemoteAddr().String()),
+			slog.String("error", scanErr.Error()))
+
+		_ = c.Conn.Close()
+
+		return 0, scanErr
+	}
+
+	return n, err //nolint:wrapcheck // the error of the wrapped connection is passed through as is
+}
+
+// frameScanner sums the payload lengths of the data frames of each WebSocket message, and
+// inflates the payload of compressed messages (permessage-deflate) to limit the size they
+// inflate to as well. Control frames are not part of a message and are left to the
+// WebSocket library.
+type frameScanner struct {
+	maxBytes int64
+
+	header       [maxFrameHeaderBytes]byte
+	headerLen    int
+	remaining    uint64
+	messageBytes uint64
+
+	// controlFrame and finalFrame describe the frame whose payload is being read.
+	controlFrame bool
+	finalFrame   bool
+	// mask and payloadOffset unmask the payload of the current frame.
+	mask          [frameMaskKeyBytes]byte
+	payloadOffset int
+	// inflater inflates the current message when it is compressed, and is nil otherwise.
+	inflater *inflateLimiter
+}
+
+func (s *frameScanner) scan(data []byte) error {
+	for len(data) > 0 {
+		if s.remaining > 0 {
+			skip := min(uint64(len(data)), s.remaining)
+
+			err := s.inflate(data[:skip])
+			if err != nil {
+				return err
+			}
+
+			s.remaining -= skip
+			data = data[skip:]
+
+			if s.remaining == 0 {
+				err = s.endFrame()
+				if err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		s.header[s.headerLen] = data[0]
+		s.headerLen++
+		data = data[1:]
+
+		if s.headerLen < 2 || s.headerLen < s.headerSize() {
+			continue
+		}
+
+		err := s.endHeader()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headerSize returns the size of the current frame header; the first two bytes tell it.
+func (s *frameScanner) headerSize() int {
+	size := 2
+
+	switch s.header[1] & framePayloadMask {
+	case frameLength16:
+		size += 2
+	case frameLength64:
+		size += 8
+	}
+
+	if s.header[1]&frameMaskBit != 0 {
+		size += frameMaskKeyBytes
+	}
+
+	return size
+}
+
+func (s *frameScanner) endHeader() error {
+	var length uint64
+
+	switch code := s.header[1] & framePayloadMask; code {
+	case frameLength16:
+		length = uint64(binary.BigEndian.Uint16(s.header[2:4]))
+	case frameLength64:
+		length = binary.BigEndian.Uint64(s.header[2:10])
+	default:
+		length = uint64(code)
+	}
+
+	if s.header[1]&frameMaskBit != 0 {
+		copy(s.mask[:], s.header[s.headerLen-frameMaskKeyBytes:s.headerLen])
+	} else {
+		s.mask = [frameMaskKeyBytes]byte{}
+	}
+
+	opcode := s.header[0] & frameOpcodeMask
+
+	s.headerLen = 0
+	s.remaining = length
+	s.payloadOffset = 0
+	s.controlFrame = opcode >= firstControlOpcode
+	s.finalFrame = s.header[0]&frameFinBit != 0
+
+	if s.controlFrame {
+		return nil
+	}
+
+	if length > math.MaxInt64-s.messageBytes || s.messageBytes+length > uint64(s.maxBytes) {
+		return fmt.Errorf("%w: message of more than %d bytes", ErrMessageTooLarge, s.maxBytes)
+	}
+
+	s.messageBytes += length
+
+	// RSV1 on the first frame of a message marks it compressed (RFC 7692 6).
+	if opcode != 0 && s.header[0]&frameRSV1Bit != 0 {
+		s.close()
+		s.inflater = newInflateLimiter(s.maxBytes)
+	}
+
+	if length == 0 {
+		return s.endFrame()
+	}
+
+	return nil
+}
+
+// inflate passes the payload of the current frame to the inflater, if the frame belongs to
+// a compressed message.
+func (s *frameScanner) inflate(payload []byte) error {
+	if s.controlFrame || s.inflater == nil {
+		return nil
+	}
+
+	unmasked := make([]byte, len(payload))
+	for index, value := range payload {
+		unmasked[index] = value ^ s.mask[(s.payloadOffset+index)%frameMaskKeyBytes]
+	}
+
+	s.payloadOffset += len(payload)
+
+	return s.inflater.write(unmasked)
+}
+
+// endFrame is called once the payload of a frame has been read in full.
+func (s *frameScanner) endFrame() error {
+	if s.controlFrame || !s.finalFrame {
+		return nil
+	}
+
+	s.messageBytes = 0
+
+	if s.inflater == nil {
+		return nil
+	}
+
+	err := s.inflater.close()
+	s.inflater = nil
+
+	return err
+}
+
+// close stops inflating the current message, if it is compressed.
+func (s *frameScanner) close() {
+	if s.inflater != nil {
+		_ = s.inflater.close()
+		s.inflater = nil
+	}
+}
@@ -0,0 +1,174 @@
+//nolint:testpackage // white-box: frameScanner is unexported and only reachable through a hijacked connection.
+package opamp
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maskedFrame returns a client frame with the given first header byte and payload size,
+// using the 16-bit extended length when the size does not fit in 7 bits.
+func maskedFrame(first byte, size int) []byte {
+	var frame []byte
+	if size < frameLength16 {
+		frame = []byte{first, frameMaskBit | byte(size)}
+	} else {
+		frame = []byte{first, frameMaskBit | frameLength16, byte(size >> 8), byte(size)}
+	}
+
+	frame = append(frame, 1, 2, 3, 4)
+
+	return append(frame, make([]byte, size)...)
+}
+
+func TestFrameScanner(t *testing.T) {
+	t.Parallel()
+
+	const (
+		binaryFin    = 0x82
+		binary       = 0x02
+		continuation = 0x00
+		continueFin  = 0x80
+		pingFin      = 0x89
+		maxBytes     = 1000
+		withinLimit  = 600
+		overLimit    = maxBytes + 1
+	)
+
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		wantErr bool
+	}{
+		{
+			name:   "separate messages are counted separately",
+			frames: [][]byte{maskedFrame(binaryFin, withinLimit), maskedFrame(binaryFin, withinLimit)},
+		},
+		{
+			name:   "control frames between fragments are not counted",
+			frames: [][]byte{maskedFrame(binary, withinLimit), maskedFrame(pingFin, 125), maskedFrame(continueFin, 300)},
+		},
+		{
+			name:    "a single frame over the limit",
+			frames:  [][]byte{maskedFrame(binaryFin, overLimit)},
+			wantErr: true,
+		},
+		{
+			name: "fragments of one message add up",
+			frames: [][]byte{
+				maskedFrame(binary, withinLimit), maskedFrame(continuation, 0), maskedFrame(continueFin, withinLimit),
+			},
+			wantErr: true,
+		},
+		{
+			name: "a 64-bit length beyond int64",
+			frames: [][]byte{
+				{binaryFin, frameMaskBit | frameLength64, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1, 2, 3, 4},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			//exhaustruct:ignore
+			scanner := frameScanner{maxBytes: maxBytes}
+
+			var stream []byte
+			for _, frame := range test.frames {
+				stream = append(stream, frame...)
+			}
+
+			var err error
+
+			// Feed one byte at a time, so every header is split across reads.
+			for index := 0; index < len(stream) && err == nil; index++ {
+				err = scanner.scan(stream[index : index+1])
+			}
+
+			if test.wantErr {
+				require.ErrorIs(t, err, ErrMessageTooLarge)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// compressedFrames returns a message of size zero bytes compressed the way permessage-deflate
+// does, split into two masked frames; RSV1 is set on the first one.
+func compressedFrames(t *testing.T, size int) [][]byte {
+	t.Helper()
+
+	var compressed bytes.Buffer
+
+	writer, err := flate.NewWriter(&compressed, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = writer.Write(make([]byte, size))
+	require.NoError(t, err)
+	require.NoError(t, writer.Flush())
+
+	payload := bytes.TrimSuffix(compressed.Bytes(), []byte{0, 0, 0xff, 0xff})
+	half := len(payload) / 2
+
+	frame := func(first byte, part []byte) []byte {
+		framed := maskedFrame(first, len(part))
+		for index, value := range part {
+			framed[len(framed)-len(part)+index] = value ^ byte(index%frameMaskKeyBytes+1)
+		}
+
+		return framed
+	}
+
+	return [][]byte{frame(frameRSV1Bit|0x02, payload[:half]), frame(frameFinBit, payload[half:])}
+}
+
+func TestFrameScanner_CompressedMessages(t *testing.T) {
+	t.Parallel()
+
+	const maxBytes = 1000
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "a message inflating within the limit", size: maxBytes},
+		{name: "a message inflating beyond the limit", size: 100 * maxBytes, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			//exhaustruct:ignore
+			scanner := frameScanner{maxBytes: maxBytes}
+			defer scanner.close()
+
+			var stream []byte
+			for _, frame := range compressedFrames(t, test.size) {
+				stream = append(stream, frame...)
+			}
+
+			require.Less(t, len(stream), maxBytes, "the compressed frames must be within the limit")
+
+			var err error
+
+			for index := 0; index < len(stream) && err == nil; index++ {
+				err = scanner.scan(stream[index : index+1])
+			}
+
+			if test.wantErr {
+				require.ErrorIs(t, err, ErrMessageTooLarge)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Package openapi serves the API description generated from the handlers' swagger annotations.
+package openapi
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/docs"
+)
+
+// Controller serves the generated OpenAPI (Swagger 2.0) document.
+type Controller struct {
+	logger *slog.Logger
+}
+
+// NewController creates a new instance of the Controller struct with the provided settings.
+func NewController(logger *slog.Logger) *Controller {
+	return &Controller{
+		logger: logger,
+	}
+}
+
+// RoutesInfo returns the routes information for the openapi controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/openapi.json",
+			Handler:     "http.v1.openapi.Get",
+			HandlerFunc: c.Get,
+		},
+	}
+}
+
+// Get returns the OpenAPI document of the API. The same document backs the Swagger UI
+// served under /swagger.
+//
+// @Summary Get OpenAPI Document
+// @Tags openapi
+// @Description Retrieve the OpenAPI (Swagger 2.0) document describing the API.
+// @Produce json
+// @Success 200 {object} map[string]any
+// @Router /api/v1/openapi.json [get].
+func (c *Controller) Get(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+}
@@ -0,0 +1,43 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/openapi"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestOpenAPIController_Get(t *testing.T) {
+	t.Parallel()
+
+	base := testutil.NewBase(t)
+	ctrlBase := base.ForController()
+
+	controller := openapi.NewController(base.Logger)
+	ctrlBase.SetupRouter(controller)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/openapi.json", nil)
+	require.NoError(t, err)
+	ctrlBase.Router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+
+	body := recorder.Body.String()
+	require.True(t, gjson.Valid(body), "the document must be valid JSON")
+	assert.Equal(t, "2.0", gjson.Get(body, "swagger").String())
+	assert.True(t, gjson.Get(body, `paths.\/api\/v1\/namespaces\/{namespace}\/agents`).Exists())
+	assert.True(t, gjson.Get(body, `paths.\/api\/v1\/namespaces\/{namespace}\/agents\/{id}.get`).Exists())
+}
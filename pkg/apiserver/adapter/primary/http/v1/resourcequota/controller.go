@@ -0,0 +1,68 @@
+// Package resourcequota provides the HTTP controller for reporting resource quota usage.
+package resourcequota
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// Controller is a struct that handles HTTP requests related to resource quotas.
+type Controller struct {
+	logger *slog.Logger
+
+	// usecases
+	resourceQuotaUsecase usecase.ResourceQuotaManageUsecase
+}
+
+// NewController creates a new instance of the Controller struct.
+func NewController(
+	logger *slog.Logger,
+	resourceQuotaUsecase usecase.ResourceQuotaManageUsecase,
+) *Controller {
+	return &Controller{
+		logger:               logger,
+		resourceQuotaUsecase: resourceQuotaUsecase,
+	}
+}
+
+// RoutesInfo returns the routes information for the controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      "GET",
+			Path:        "/api/v1/quotas",
+			Handler:     "http.v1.resourcequota.List",
+			HandlerFunc: c.List,
+		},
+	}
+}
+
+// List handles the request to list the usage of every resource quota.
+//
+// @Summary List Resource Quotas
+// @Tags resourcequota
+// @Description  Retrieve how many resources of each kind exist against their quota.
+// @Accept  json
+// @Produce json
+// @Success 200 {object} v1.ListResponse[v1.ResourceQuota]
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/quotas [get].
+func (c *Controller) List(ctx *gin.Context) {
+	var quotas *v1.ListResponse[v1.ResourceQuota]
+
+	quotas, err := c.resourceQuotaUsecase.ListResourceQuotas(ctx.Request.Context())
+	if err != nil {
+		c.logger.Error("failed to list resource quotas", "error", err.Error())
+		ginutil.InternalServerError(ctx, err, "An error occurred while listing resource quotas.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quotas)
+}
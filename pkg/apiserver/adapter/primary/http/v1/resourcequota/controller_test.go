@@ -0,0 +1,101 @@
+package resourcequota_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/resourcequota"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var errBoom = errors.New("boom")
+
+// mockResourceQuotaUsecase is a testify mock of usecase.ResourceQuotaManageUsecase.
+type mockResourceQuotaUsecase struct {
+	mock.Mock
+}
+
+func newMockResourceQuotaUsecase(t *testing.T) *mockResourceQuotaUsecase {
+	t.Helper()
+
+	m := &mockResourceQuotaUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockResourceQuotaUsecase) ListResourceQuotas(
+	ctx context.Context,
+) (*v1.ListResponse[v1.ResourceQuota], error) {
+	args := m.Called(ctx)
+
+	res, _ := args.Get(0).(*v1.ListResponse[v1.ResourceQuota])
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func TestResourceQuotaController_List(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the usage of every quota", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockResourceQuotaUsecase(t)
+		controller := resourcequota.NewController(slog.Default(), usecase)
+		ctrlBase.SetupRouter(controller)
+
+		quotas := []v1.ResourceQuota{
+			{Resource: "AgentGroup", Used: 3, Limit: 10},
+			{Resource: "Certificate", Used: 1, Limit: 0},
+		}
+		usecase.On("ListResourceQuotas", mock.Anything).
+			Return(v1.NewResourceQuotaListResponse(quotas, v1.ListMeta{RemainingItemCount: 0, Continue: ""}), nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/quotas", nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		assert.Equal(t, v1.ResourceQuotaKind, gjson.Get(body, "kind").String())
+		assert.Equal(t, "AgentGroup", gjson.Get(body, "items.0.resource").String())
+		assert.Equal(t, int64(3), gjson.Get(body, "items.0.used").Int())
+		assert.Equal(t, int64(10), gjson.Get(body, "items.0.limit").Int())
+	})
+
+	t.Run("returns 500 when the usecase fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockResourceQuotaUsecase(t)
+		controller := resourcequota.NewController(slog.Default(), usecase)
+		ctrlBase.SetupRouter(controller)
+
+		usecase.On("ListResourceQuotas", mock.Anything).Return(nil, errBoom)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/quotas", nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
@@ -0,0 +1,68 @@
+// Package summary provides the HTTP controller for the agent fleet summary.
+package summary
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+// Controller is a struct that handles HTTP requests related to the fleet summary.
+type Controller struct {
+	logger *slog.Logger
+
+	// usecases
+	summaryUsecase usecase.SummaryManageUsecase
+}
+
+// NewController creates a new instance of the Controller struct.
+func NewController(
+	logger *slog.Logger,
+	summaryUsecase usecase.SummaryManageUsecase,
+) *Controller {
+	return &Controller{
+		logger:         logger,
+		summaryUsecase: summaryUsecase,
+	}
+}
+
+// RoutesInfo returns the routes information for the controller.
+func (c *Controller) RoutesInfo() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{
+			Method:      "GET",
+			Path:        "/api/v1/summary",
+			Handler:     "http.v1.summary.Get",
+			HandlerFunc: c.Get,
+		},
+	}
+}
+
+// Get handles the request to summarize the health of the agent fleet.
+//
+// @Summary Get Fleet Summary
+// @Tags summary
+// @Description  Count the agents of every namespace: in total, connected, healthy, unhealthy and with pending commands.
+// @Accept  json
+// @Produce json
+// @Success 200 {object} v1.Summary
+// @Failure 500 {object} map[string]any
+// @Router /api/v1/summary [get].
+func (c *Controller) Get(ctx *gin.Context) {
+	var summary *v1.Summary
+
+	summary, err := c.summaryUsecase.GetSummary(ctx.Request.Context())
+	if err != nil {
+		c.logger.Error("failed to get summary", "error", err.Error())
+		ginutil.InternalServerError(ctx, err, "An error occurred while getting the summary.")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,104 @@
+package summary_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.uber.org/goleak"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/summary"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var errBoom = errors.New("boom")
+
+// mockSummaryUsecase is a testify mock of usecase.SummaryManageUsecase.
+type mockSummaryUsecase struct {
+	mock.Mock
+}
+
+func newMockSummaryUsecase(t *testing.T) *mockSummaryUsecase {
+	t.Helper()
+
+	m := &mockSummaryUsecase{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+
+func (m *mockSummaryUsecase) GetSummary(ctx context.Context) (*v1.Summary, error) {
+	args := m.Called(ctx)
+
+	res, _ := args.Get(0).(*v1.Summary)
+
+	return res, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func TestSummaryController_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the fleet counts", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockSummaryUsecase(t)
+		controller := summary.NewController(slog.Default(), usecase)
+		ctrlBase.SetupRouter(controller)
+
+		usecase.On("GetSummary", mock.Anything).Return(&v1.Summary{
+			Kind:                      v1.SummaryKind,
+			APIVersion:                v1.APIVersion,
+			TotalAgents:               10,
+			ConnectedAgents:           8,
+			HealthyAgents:             7,
+			UnhealthyAgents:           1,
+			AgentsWithPendingCommands: 2,
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/summary", nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		assert.Equal(t, v1.SummaryKind, gjson.Get(body, "kind").String())
+		assert.Equal(t, int64(10), gjson.Get(body, "totalAgents").Int())
+		assert.Equal(t, int64(8), gjson.Get(body, "connectedAgents").Int())
+		assert.Equal(t, int64(7), gjson.Get(body, "healthyAgents").Int())
+		assert.Equal(t, int64(1), gjson.Get(body, "unhealthyAgents").Int())
+		assert.Equal(t, int64(2), gjson.Get(body, "agentsWithPendingCommands").Int())
+	})
+
+	t.Run("returns 500 when the usecase fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctrlBase := testutil.NewBase(t).ForController()
+		usecase := newMockSummaryUsecase(t)
+		controller := summary.NewController(slog.Default(), usecase)
+		ctrlBase.SetupRouter(controller)
+
+		usecase.On("GetSummary", mock.Anything).Return(nil, errBoom)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/summary", nil)
+		require.NoError(t, err)
+		ctrlBase.Router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
@@ -7,18 +7,41 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	v1version "github.com/minuk-dev/opampcommander/api/v1/version"
 	"github.com/minuk-dev/opampcommander/pkg/version"
 )
 
+// HeaderName is the response header that carries the server version on every response.
+const HeaderName = "X-Opampcommander-Version"
+
+// NewHeaderMiddleware returns a middleware that sets HeaderName on every response, so a
+// client can tell which server build answered without calling the version endpoint.
+func NewHeaderMiddleware() gin.HandlerFunc {
+	return newHeaderMiddleware(version.Get())
+}
+
+func newHeaderMiddleware(info v1version.Info) gin.HandlerFunc {
+	gitVersion := info.GitVersion
+
+	return func(ctx *gin.Context) {
+		ctx.Header(HeaderName, gitVersion)
+		ctx.Next()
+	}
+}
+
 // Controller is a struct that implements the version controller.
 type Controller struct {
 	logger *slog.Logger
+
+	// getVersion returns the build info the binary was built with.
+	getVersion func() v1version.Info
 }
 
 // NewController creates a new instance of the Controller struct with the provided settings.
 func NewController(logger *slog.Logger) *Controller {
 	return &Controller{
-		logger: logger,
+		logger:     logger,
+		getVersion: version.Get,
 	}
 }
 
@@ -45,6 +68,6 @@ func (c *Controller) RoutesInfo() gin.RoutesInfo {
 func (c *Controller) GetVersion(ctx *gin.Context) {
 	c.logger.Debug("GetVersion called")
 
-	versionInfo := version.Get()
+	versionInfo := c.getVersion()
 	ctx.JSON(http.StatusOK, versionInfo)
 }
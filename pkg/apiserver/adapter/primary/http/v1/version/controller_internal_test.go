@@ -0,0 +1,62 @@
+//nolint:testpackage // white-box test that swaps the build info the controller reports
+package version
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	v1version "github.com/minuk-dev/opampcommander/api/v1/version"
+)
+
+func TestVersionController_GetVersionReportsBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	//exhaustruct:ignore
+	buildInfo := v1version.Info{
+		GitVersion: "v1.2.3",
+		GitCommit:  "0123456789abcdef0123456789abcdef01234567",
+		BuildDate:  "2026-01-02T03:04:05Z",
+		GoVersion:  "go1.99.0",
+	}
+
+	controller := &Controller{
+		logger:     slog.New(slog.DiscardHandler),
+		getVersion: func() v1version.Info { return buildInfo },
+	}
+
+	router := gin.New()
+	router.Use(newHeaderMiddleware(buildInfo))
+
+	for _, route := range controller.RoutesInfo() {
+		router.Handle(route.Method, route.Path, route.HandlerFunc)
+	}
+
+	router.GET("/api/v1/ping", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/version", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	assert.Equal(t, "v1.2.3", gjson.Get(body, "gitVersion").String())
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", gjson.Get(body, "gitCommit").String())
+	assert.Equal(t, "2026-01-02T03:04:05Z", gjson.Get(body, "buildDate").String())
+	assert.Equal(t, "go1.99.0", gjson.Get(body, "goVersion").String())
+	assert.Equal(t, "v1.2.3", recorder.Header().Get(HeaderName))
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/ping", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "v1.2.3", recorder.Header().Get(HeaderName), "every response carries the version header")
+}
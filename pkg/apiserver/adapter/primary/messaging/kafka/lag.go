@@ -0,0 +1,288 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+	metricapi "go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// ConsumerLagGaugeName is the metric reporting how many events on the topic this
+	// server's consumer group has not consumed yet.
+	ConsumerLagGaugeName = "opamp.server.event_consumer_lag"
+
+	// DefaultLagCheckInterval is how often consumer lag is measured when no interval is set.
+	DefaultLagCheckInterval = 30 * time.Second
+
+	consumerLagMonitorName = "kafka-consumer-lag"
+	consumerLagMeterName   = "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/kafka"
+)
+
+// ErrConsumerLagNotMeasured is returned by ConsumerLagMonitor.Lag before the first
+// measurement has succeeded.
+var ErrConsumerLagNotMeasured = errors.New("consumer lag has not been measured yet")
+
+// OffsetReader reads the offsets consumer lag is computed from.
+type OffsetReader interface {
+	// LatestOffsets returns the offset the next message will be written at, per partition.
+	LatestOffsets(topic string) (map[int32]int64, error)
+	// CommittedOffsets returns the offset group committed, per partition. Partitions the
+	// group has not committed on yet are omitted.
+	CommittedOffsets(group, topic string, partitions []int32) (map[int32]int64, error)
+}
+
+// LagSettings tunes a ConsumerLagMonitor.
+type LagSettings struct {
+	// CheckInterval is how often lag is measured. A zero or negative value selects
+	// DefaultLagCheckInterval.
+	CheckInterval time.Duration
+	// MaxLag is the lag above which the server reports itself not ready. Zero disables the
+	// readiness check; the lag is still measured and exported.
+	MaxLag int64
+}
+
+// ConsumerLagMonitor periodically measures how far this server's consumer group is behind
+// on the event topic. The latest measurement is exported as a gauge and drives readiness,
+// so a server that falls behind on inter-server events stops receiving traffic.
+type ConsumerLagMonitor struct {
+	reader   OffsetReader
+	group    string
+	topic    string
+	settings LagSettings
+	gauge    metricapi.Int64Gauge
+	logger   *slog.Logger
+
+	mu       sync.RWMutex
+	lag      int64
+	measured bool
+}
+
+// NewConsumerLagMonitor creates a ConsumerLagMonitor for group on topic. The meter
+// provider is nil when metrics are disabled.
+func NewConsumerLagMonitor(
+	reader OffsetReader,
+	group, topic string,
+	settings LagSettings,
+	meterProvider metricapi.MeterProvider,
+	logger *slog.Logger,
+) *ConsumerLagMonitor {
+	if settings.CheckInterval <= 0 {
+		settings.CheckInterval = DefaultLagCheckInterval
+	}
+
+	return &ConsumerLagMonitor{
+		reader:   reader,
+		group:    group,
+		topic:    topic,
+		settings: settings,
+		gauge:    newConsumerLagGauge(meterProvider),
+		logger:   logger,
+		mu:       sync.RWMutex{},
+		lag:      0,
+		measured: false,
+	}
+}
+
+// newConsumerLagGauge creates the lag gauge, falling back to a no-op one so a missing
+// metric never stops the consumer.
+func newConsumerLagGauge(meterProvider metricapi.MeterProvider) metricapi.Int64Gauge {
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	gauge, err := meterProvider.Meter(consumerLagMeterName).Int64Gauge(ConsumerLagGaugeName,
+		metricapi.WithDescription("Number of events on the event topic the server's consumer group has not consumed."),
+		metricapi.WithUnit("{message}"),
+	)
+	if err != nil {
+		return metricnoop.Int64Gauge{}
+	}
+
+	return gauge
+}
+
+// Name implements scheduler.Scheduler.
+func (m *ConsumerLagMonitor) Name() string {
+	return consumerLagMonitorName
+}
+
+// Run implements scheduler.Scheduler. It measures the lag immediately and then once per
+// check interval until ctx is done.
+func (m *ConsumerLagMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.settings.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		err := m.Measure(ctx)
+		if err != nil {
+			m.logger.Warn("failed to measure kafka consumer lag",
+				slog.String("group", m.group),
+				slog.String("topic", m.topic),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Measure reads the current offsets, records the total lag across partitions and keeps it
+// for Lag and Ready. A failed measurement leaves the previous one in place.
+func (m *ConsumerLagMonitor) Measure(ctx context.Context) error {
+	latest, err := m.reader.LatestOffsets(m.topic)
+	if err != nil {
+		return fmt.Errorf("read latest offsets of topic %s: %w", m.topic, err)
+	}
+
+	partitions := make([]int32, 0, len(latest))
+	for partition := range latest {
+		partitions = append(partitions, partition)
+	}
+
+	committed, err := m.reader.CommittedOffsets(m.group, m.topic, partitions)
+	if err != nil {
+		return fmt.Errorf("read committed offsets of group %s: %w", m.group, err)
+	}
+
+	var lag int64
+
+	for partition, latestOffset := range latest {
+		// A group that has not committed on a partition starts at the newest offset, so it
+		// is not behind there.
+		committedOffset, ok := committed[partition]
+		if !ok || committedOffset >= latestOffset {
+			continue
+		}
+
+		lag += latestOffset - committedOffset
+	}
+
+	m.mu.Lock()
+	m.lag = lag
+	m.measured = true
+	m.mu.Unlock()
+
+	m.gauge.Record(ctx, lag, metricapi.WithAttributes(
+		attribute.String("topic", m.topic),
+		attribute.String("consumer_group", m.group),
+	))
+
+	return nil
+}
+
+// Lag returns the total lag of the latest successful measurement.
+func (m *ConsumerLagMonitor) Lag() (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.measured {
+		return 0, ErrConsumerLagNotMeasured
+	}
+
+	return m.lag, nil
+}
+
+// Ready reports whether the latest measured lag is within the configured maximum, with the
+// lag as the reason when it is not. The server is considered ready until a measurement
+// says otherwise, so an unreachable broker alone does not take it out of service.
+func (m *ConsumerLagMonitor) Ready() (bool, string) {
+	lag, err := m.Lag()
+	if err != nil || m.settings.MaxLag <= 0 || lag <= m.settings.MaxLag {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("consumer group %s is %d messages behind on topic %s (max %d)",
+		m.group, lag, m.topic, m.settings.MaxLag)
+}
+
+// SaramaOffsetReader reads offsets through a sarama client.
+type SaramaOffsetReader struct {
+	client sarama.Client
+}
+
+var _ OffsetReader = (*SaramaOffsetReader)(nil)
+
+// NewSaramaOffsetReader creates a SaramaOffsetReader. The client stays owned by the caller.
+func NewSaramaOffsetReader(client sarama.Client) *SaramaOffsetReader {
+	return &SaramaOffsetReader{
+		client: client,
+	}
+}
+
+// LatestOffsets implements OffsetReader.
+func (r *SaramaOffsetReader) LatestOffsets(topic string) (map[int32]int64, error) {
+	partitions, err := r.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("list partitions: %w", err)
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+
+	for _, partition := range partitions {
+		offset, err := r.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("get newest offset of partition %d: %w", partition, err)
+		}
+
+		offsets[partition] = offset
+	}
+
+	return offsets, nil
+}
+
+// CommittedOffsets implements OffsetReader.
+func (r *SaramaOffsetReader) CommittedOffsets(
+	group, topic string,
+	partitions []int32,
+) (map[int32]int64, error) {
+	coordinator, err := r.client.Coordinator(group)
+	if err != nil {
+		return nil, fmt.Errorf("find coordinator: %w", err)
+	}
+
+	request := sarama.NewOffsetFetchRequest(r.client.Config().Version, group,
+		map[string][]int32{topic: partitions})
+
+	response, err := coordinator.FetchOffset(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetch offsets: %w", err)
+	}
+
+	if !errors.Is(response.Err, sarama.ErrNoError) {
+		return nil, fmt.Errorf("fetch offsets: %w", response.Err)
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+
+	for _, partition := range partitions {
+		block := response.GetBlock(topic, partition)
+		if block == nil {
+			continue
+		}
+
+		if !errors.Is(block.Err, sarama.ErrNoError) {
+			return nil, fmt.Errorf("fetch offset of partition %d: %w", partition, block.Err)
+		}
+
+		// Kafka answers -1 for partitions the group has not committed on.
+		if block.Offset < 0 {
+			continue
+		}
+
+		offsets[partition] = block.Offset
+	}
+
+	return offsets, nil
+}
@@ -0,0 +1,132 @@
+package kafka_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	inkafka "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/kafka"
+)
+
+var errBrokerUnavailable = errors.New("broker unavailable")
+
+// fakeOffsetReader serves fixed offsets, or err when set.
+type fakeOffsetReader struct {
+	latest    map[int32]int64
+	committed map[int32]int64
+	err       error
+}
+
+func (f *fakeOffsetReader) LatestOffsets(string) (map[int32]int64, error) {
+	return f.latest, f.err
+}
+
+func (f *fakeOffsetReader) CommittedOffsets(string, string, []int32) (map[int32]int64, error) {
+	return f.committed, f.err
+}
+
+// recordedLag reads the consumer lag gauge from reader.
+func recordedLag(t *testing.T, reader *sdkmetric.ManualReader) (int64, bool) {
+	t.Helper()
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if m.Name != inkafka.ConsumerLagGaugeName {
+				continue
+			}
+
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			require.True(t, ok)
+			require.Len(t, gauge.DataPoints, 1)
+
+			group, _ := gauge.DataPoints[0].Attributes.Value(attribute.Key("consumer_group"))
+			assert.Equal(t, "group-a", group.AsString())
+
+			return gauge.DataPoints[0].Value, true
+		}
+	}
+
+	return 0, false
+}
+
+func TestConsumerLagMonitor_ReflectsLag(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	offsets := &fakeOffsetReader{
+		latest:    map[int32]int64{0: 120, 1: 80, 2: 10},
+		committed: map[int32]int64{0: 20, 1: 75},
+	}
+	monitor := inkafka.NewConsumerLagMonitor(offsets, "group-a", "events",
+		inkafka.LagSettings{MaxLag: 50},
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		slog.New(slog.DiscardHandler))
+
+	ready, _ := monitor.Ready()
+	assert.True(t, ready, "the server is ready before the first measurement")
+
+	_, err := monitor.Lag()
+	require.ErrorIs(t, err, inkafka.ErrConsumerLagNotMeasured)
+
+	// Partition 2 has no committed offset, so it does not count.
+	require.NoError(t, monitor.Measure(t.Context()))
+
+	lag, err := monitor.Lag()
+	require.NoError(t, err)
+	assert.Equal(t, int64(105), lag)
+
+	recorded, ok := recordedLag(t, reader)
+	require.True(t, ok, "the lag gauge is recorded")
+	assert.Equal(t, int64(105), recorded)
+
+	ready, reason := monitor.Ready()
+	assert.False(t, ready)
+	assert.Contains(t, reason, "105 messages behind")
+
+	// The consumer catches up.
+	offsets.committed = map[int32]int64{0: 100, 1: 80}
+	require.NoError(t, monitor.Measure(t.Context()))
+
+	recorded, _ = recordedLag(t, reader)
+	assert.Equal(t, int64(20), recorded)
+
+	ready, _ = monitor.Ready()
+	assert.True(t, ready)
+
+	// A failed measurement keeps the previous one.
+	offsets.err = errBrokerUnavailable
+	require.ErrorIs(t, monitor.Measure(t.Context()), errBrokerUnavailable)
+
+	lag, err = monitor.Lag()
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), lag)
+}
+
+func TestConsumerLagMonitor_ZeroMaxLagNeverFailsReadiness(t *testing.T) {
+	t.Parallel()
+
+	offsets := &fakeOffsetReader{
+		latest:    map[int32]int64{0: 1000},
+		committed: map[int32]int64{0: 0},
+	}
+	monitor := inkafka.NewConsumerLagMonitor(offsets, "group-a", "events",
+		inkafka.LagSettings{}, nil, slog.New(slog.DiscardHandler))
+
+	require.NoError(t, monitor.Measure(t.Context()))
+
+	lag, err := monitor.Lag()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), lag)
+
+	ready, _ := monitor.Ready()
+	assert.True(t, ready)
+}
@@ -0,0 +1,26 @@
+// Package enrichment provides outbound adapters that derive server-owned labels for
+// agents, plus a no-op default used when no enrichment is configured.
+package enrichment
+
+import (
+	"context"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ agentport.AgentEnrichmentPort = (*NoopAdapter)(nil)
+
+// NoopAdapter is an [agentport.AgentEnrichmentPort] that derives nothing. It is wired
+// when no enrichment is configured, so agents carry no server-owned labels.
+type NoopAdapter struct{}
+
+// NewNoopAdapter creates a NoopAdapter.
+func NewNoopAdapter() *NoopAdapter {
+	return &NoopAdapter{}
+}
+
+// EnrichAgent returns no labels.
+func (a *NoopAdapter) EnrichAgent(context.Context, *agentmodel.Agent, string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
@@ -0,0 +1,52 @@
+package enrichment
+
+import (
+	"context"
+	"maps"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ agentport.AgentEnrichmentPort = (*StaticAdapter)(nil)
+
+// StaticAdapter is an [agentport.AgentEnrichmentPort] that looks the agent's labels up in
+// a fixed table, keyed by the value of one of the agent's attributes (e.g. "host.name"
+// mapping each host to its region and owner). It is the simplest stand-in for an
+// inventory or GeoIP lookup.
+type StaticAdapter struct {
+	attribute string
+	labels    map[string]map[string]string
+}
+
+// NewStaticAdapter creates a StaticAdapter giving an agent whose attribute has a value
+// the labels listed for that value. An agent without the attribute, or with a value not
+// listed, gets no labels.
+func NewStaticAdapter(attribute string, labels map[string]map[string]string) *StaticAdapter {
+	return &StaticAdapter{
+		attribute: attribute,
+		labels:    labels,
+	}
+}
+
+// EnrichAgent returns the labels listed for the agent's attribute value. The attribute is
+// looked up among the identifying attributes first, then the non-identifying ones.
+func (a *StaticAdapter) EnrichAgent(
+	_ context.Context,
+	agent *agentmodel.Agent,
+	_ string,
+) (map[string]string, error) {
+	description := agent.Metadata.Description
+
+	value, ok := description.IdentifyingAttributes[a.attribute]
+	if !ok {
+		value, ok = description.NonIdentifyingAttributes[a.attribute]
+	}
+
+	labels, listed := a.labels[value]
+	if !ok || !listed {
+		return map[string]string{}, nil
+	}
+
+	return maps.Clone(labels), nil
+}
@@ -0,0 +1,66 @@
+package enrichment_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/enrichment"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestStaticAdapter_EnrichAgent(t *testing.T) {
+	t.Parallel()
+
+	adapter := enrichment.NewStaticAdapter("host.name", map[string]map[string]string{
+		"collector-eu-1": {"region": "eu-west-1", "owner": "team-observability"},
+	})
+
+	newAgent := func(identifying, nonIdentifying map[string]string) *agentmodel.Agent {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Metadata.Description.IdentifyingAttributes = identifying
+		agent.Metadata.Description.NonIdentifyingAttributes = nonIdentifying
+
+		return agent
+	}
+
+	cases := []struct {
+		name  string
+		agent *agentmodel.Agent
+		want  map[string]string
+	}{
+		{
+			name:  "listed value",
+			agent: newAgent(nil, map[string]string{"host.name": "collector-eu-1"}),
+			want:  map[string]string{"region": "eu-west-1", "owner": "team-observability"},
+		},
+		{
+			name: "identifying attribute wins",
+			agent: newAgent(map[string]string{"host.name": "collector-eu-1"},
+				map[string]string{"host.name": "collector-us-1"}),
+			want: map[string]string{"region": "eu-west-1", "owner": "team-observability"},
+		},
+		{
+			name:  "unlisted value",
+			agent: newAgent(nil, map[string]string{"host.name": "collector-us-1"}),
+			want:  map[string]string{},
+		},
+		{
+			name:  "missing attribute",
+			agent: newAgent(nil, nil),
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			labels, err := adapter.EnrichAgent(t.Context(), tc.agent, "10.0.0.1:4317")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, labels)
+		})
+	}
+}
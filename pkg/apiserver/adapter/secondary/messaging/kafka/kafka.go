@@ -24,9 +24,10 @@ var (
 
 // EventSenderAdapter implements agentport.ServerEventSenderPort using Kafka CloudEvents sender.
 type EventSenderAdapter struct {
-	sender cloudevents.Client
-	logger *slog.Logger
-	clock  clock.Clock
+	sender      cloudevents.Client
+	logger      *slog.Logger
+	clock       clock.Clock
+	keyStrategy kafkamodel.KeyStrategy
 }
 
 // NewEventSenderAdapter creates a new EventSenderAdapter.
@@ -50,12 +51,18 @@ func NewEventSenderAdapter(
 
 	// sender can be nil when events are disabled
 	return &EventSenderAdapter{
-		sender: sender,
-		logger: logger,
-		clock:  clock.NewRealClock(),
+		sender:      sender,
+		logger:      logger,
+		clock:       clock.NewRealClock(),
+		keyStrategy: kafkamodel.KeyStrategyInstanceUID,
 	}, nil
 }
 
+// SetKeyStrategy sets how sent events are keyed for partitioning.
+func (e *EventSenderAdapter) SetKeyStrategy(strategy kafkamodel.KeyStrategy) {
+	e.keyStrategy = strategy
+}
+
 // SendMessageToServer implements agentport.ServerEventSenderPort.
 func (e *EventSenderAdapter) SendMessageToServer(
 	ctx context.Context,
@@ -73,6 +80,10 @@ func (e *EventSenderAdapter) SendMessageToServer(
 	event.SetSpecVersion(kafkamodel.CloudEventMessageSpec)
 	event.SetTime(e.clock.Now())
 
+	if key := kafkamodel.PartitionKey(e.keyStrategy, message); key != "" {
+		event.SetExtension(kafkamodel.PartitionKeyExtension, key)
+	}
+
 	err := event.SetData(kafkamodel.CloudEventContentType, message.Payload)
 	if err != nil {
 		return fmt.Errorf("failed to set event data for server %s: %w", serverID, err)
@@ -0,0 +1,85 @@
+package packagedownload
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker is a consecutive-failure circuit breaker for one origin. It opens after
+// threshold consecutive failures and rejects attempts while open. Once openDuration has
+// passed it lets a single probe through: a success closes it, a failure opens it again.
+type breaker struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	failures     int
+	openUntil    time.Time
+	probing      bool
+}
+
+func newBreaker(threshold int, openDuration time.Duration) *breaker {
+	return &breaker{
+		mu:           sync.Mutex{},
+		threshold:    threshold,
+		openDuration: openDuration,
+		failures:     0,
+		openUntil:    time.Time{},
+		probing:      false,
+	}
+}
+
+// allow reports whether an attempt may be made at now.
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if b.probing || now.Before(b.openUntil) {
+		return false
+	}
+
+	b.probing = true
+
+	return true
+}
+
+func (b *breaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// release ends an attempt that says nothing about the origin, such as one the caller
+// cancelled, so that a probe it held does not keep the breaker from ever probing again.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// idle reports whether the breaker is closed with no failures, or has been open for long
+// enough that it would probe, so dropping it loses nothing but a stale failure count.
+func (b *breaker) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.probing && (b.failures == 0 || !now.Before(b.openUntil))
+}
+
+func (b *breaker) recordFailure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.openDuration)
+	}
+}
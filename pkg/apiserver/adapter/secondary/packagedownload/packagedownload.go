@@ -0,0 +1,282 @@
+// Package packagedownload provides an outbound adapter that implements
+// [agentport.AgentPackageDownloadPort] over HTTP. Each download attempt is bounded by a
+// timeout, failed attempts are retried with exponential backoff, and a circuit breaker per
+// origin fails downloads immediately once the origin has failed repeatedly, so a flaky or
+// dead origin cannot hang the request that triggered the download.
+package packagedownload
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+const (
+	// DefaultTimeout bounds a single download attempt.
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxAttempts is how many times a failing download is tried.
+	DefaultMaxAttempts = 3
+	// DefaultRetryBackoff is the wait before the first retry.
+	DefaultRetryBackoff = time.Second
+	// DefaultFailureThreshold is how many consecutive failed attempts open an origin's breaker.
+	DefaultFailureThreshold = 5
+	// DefaultOpenDuration is how long an open breaker fails downloads before probing the origin.
+	DefaultOpenDuration = time.Minute
+
+	// maxTrackedOrigins is how many origins have a breaker before idle breakers are dropped.
+	maxTrackedOrigins = 1024
+)
+
+// ErrUnexpectedStatus is returned when the origin answers with a status other than 200 OK.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+var _ agentport.AgentPackageDownloadPort = (*Adapter)(nil)
+
+// Settings tunes an Adapter. A zero or negative field selects its default.
+type Settings struct {
+	// Timeout bounds a single download attempt, including reading the body.
+	Timeout time.Duration
+	// MaxAttempts is how many times a failing download is tried.
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry. It doubles on each further retry.
+	RetryBackoff time.Duration
+	// FailureThreshold is how many consecutive failed attempts open an origin's breaker.
+	FailureThreshold int
+	// OpenDuration is how long an open breaker fails downloads from its origin before it
+	// lets a single attempt through to probe it.
+	OpenDuration time.Duration
+}
+
+// Adapter downloads agent package artifacts over HTTP.
+type Adapter struct {
+	client   *http.Client
+	settings Settings
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// NewAdapter creates an Adapter with the given settings.
+func NewAdapter(settings Settings, logger *slog.Logger) *Adapter {
+	return &Adapter{
+		//exhaustruct:ignore
+		client:   &http.Client{},
+		settings: withDefaults(settings),
+		clock:    clock.NewRealClock(),
+		logger:   logger,
+		mu:       sync.Mutex{},
+		breakers: make(map[string]*breaker),
+	}
+}
+
+func withDefaults(settings Settings) Settings {
+	if settings.Timeout <= 0 {
+		settings.Timeout = DefaultTimeout
+	}
+
+	if settings.MaxAttempts <= 0 {
+		settings.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if settings.RetryBackoff <= 0 {
+		settings.RetryBackoff = DefaultRetryBackoff
+	}
+
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+
+	if settings.OpenDuration <= 0 {
+		settings.OpenDuration = DefaultOpenDuration
+	}
+
+	return settings
+}
+
+// FetchAgentPackageContentHash implements [agentport.AgentPackageDownloadPort].
+func (a *Adapter) FetchAgentPackageContentHash(
+	ctx context.Context,
+	downloadURL string,
+	headers map[string]string,
+) ([]byte, error) {
+	origin, err := originOf(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	originBreaker := a.breakerFor(origin)
+
+	var lastErr error
+
+	for attempt := 1; attempt <= a.settings.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			err = a.wait(ctx, a.settings.RetryBackoff<<(attempt-2)) //nolint:gosec // attempt >= 2
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !originBreaker.allow(a.clock.Now()) {
+			return nil, fmt.Errorf("%w: %s failed %d times in a row; not retrying until the breaker closes",
+				agentmodel.ErrPackageOriginUnavailable, origin, a.settings.FailureThreshold)
+		}
+
+		hash, err := a.fetch(ctx, downloadURL, headers)
+		if err == nil {
+			originBreaker.recordSuccess()
+
+			return hash, nil
+		}
+
+		// The caller gave up, which says nothing about the origin.
+		if ctx.Err() != nil {
+			originBreaker.release()
+
+			return nil, fmt.Errorf("download %s: %w", downloadURL, ctx.Err())
+		}
+
+		if !isRetryable(err) {
+			// The origin answered, so it is up even though the download failed.
+			originBreaker.recordSuccess()
+
+			return nil, err
+		}
+
+		originBreaker.recordFailure(a.clock.Now())
+
+		lastErr = err
+
+		a.logger.Warn("agent package download attempt failed",
+			slog.String("url", downloadURL),
+			slog.Int("attempt", attempt),
+			slog.Int("maxAttempts", a.settings.MaxAttempts),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return nil, fmt.Errorf("download failed after %d attempts: %w", a.settings.MaxAttempts, lastErr)
+}
+
+// fetch downloads downloadURL once and returns the SHA-256 digest of the body.
+func (a *Adapter) fetch(ctx context.Context, downloadURL string, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.settings.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request for %s: %w", downloadURL, err)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", downloadURL, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{URL: downloadURL, StatusCode: resp.StatusCode}
+	}
+
+	hasher := sha256.New()
+
+	_, err = io.Copy(hasher, resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", downloadURL, err)
+	}
+
+	return hasher.Sum(nil), nil
+}
+
+func (a *Adapter) wait(ctx context.Context, delay time.Duration) error {
+	timer := a.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("wait to retry download: %w", ctx.Err())
+	case <-timer.C():
+		return nil
+	}
+}
+
+// breakerFor returns the breaker of origin, creating it if needed. Once maxTrackedOrigins
+// origins have one, idle breakers are dropped first, so downloads from ever new origins
+// cannot grow the map without bound.
+func (a *Adapter) breakerFor(origin string) *breaker {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	originBreaker, ok := a.breakers[origin]
+	if ok {
+		return originBreaker
+	}
+
+	if len(a.breakers) >= maxTrackedOrigins {
+		now := a.clock.Now()
+		maps.DeleteFunc(a.breakers, func(_ string, b *breaker) bool {
+			return b.idle(now)
+		})
+	}
+
+	originBreaker = newBreaker(a.settings.FailureThreshold, a.settings.OpenDuration)
+	a.breakers[origin] = originBreaker
+
+	return originBreaker
+}
+
+// originOf returns the scheme and host of rawURL, which key the circuit breakers.
+func originOf(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid download URL %q: %w", rawURL, err)
+	}
+
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
+
+// statusError is returned when the origin answers with a status other than 200 OK.
+type statusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("download %s: %s %d", e.URL, ErrUnexpectedStatus, e.StatusCode)
+}
+
+func (e *statusError) Unwrap() error {
+	return ErrUnexpectedStatus
+}
+
+// isRetryable reports whether a failed attempt may succeed when retried: transport errors,
+// server errors and rate limiting are, while other client errors such as 404 are not.
+func isRetryable(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
@@ -0,0 +1,216 @@
+package packagedownload_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/packagedownload"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+const artifact = "collector binary"
+
+// newOrigin serves artifact, failing with 503 while fail returns true for the request count.
+func newOrigin(t *testing.T, hits *atomic.Int32, fail func(hit int32) bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit := hits.Add(1)
+
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		if fail(hit) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, artifact)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newAdapter(settings packagedownload.Settings) *packagedownload.Adapter {
+	return packagedownload.NewAdapter(settings, slog.New(slog.DiscardHandler))
+}
+
+func TestAdapter_RetriesFlakyOrigin(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	origin := newOrigin(t, &hits, func(hit int32) bool { return hit <= 2 })
+	adapter := newAdapter(packagedownload.Settings{
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		RetryBackoff:     time.Millisecond,
+		FailureThreshold: 5,
+		OpenDuration:     time.Minute,
+	})
+
+	hash, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL+"/collector",
+		map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, err)
+
+	expected := sha256.Sum256([]byte(artifact))
+	assert.Equal(t, expected[:], hash)
+	assert.Equal(t, int32(3), hits.Load(), "two failed attempts are retried")
+}
+
+func TestAdapter_BreakerOpensOnFailingOrigin(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	origin := newOrigin(t, &hits, func(int32) bool { return true })
+	adapter := newAdapter(packagedownload.Settings{
+		Timeout:          time.Second,
+		MaxAttempts:      2,
+		RetryBackoff:     time.Millisecond,
+		FailureThreshold: 3,
+		OpenDuration:     time.Minute,
+	})
+	headers := map[string]string{"Authorization": "Bearer token"}
+
+	_, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL+"/collector", headers)
+	require.ErrorIs(t, err, packagedownload.ErrUnexpectedStatus)
+	require.NotErrorIs(t, err, agentmodel.ErrPackageOriginUnavailable)
+
+	// The third failure opens the breaker, which rejects the retry after it.
+	_, err = adapter.FetchAgentPackageContentHash(t.Context(), origin.URL+"/collector", headers)
+	require.ErrorIs(t, err, agentmodel.ErrPackageOriginUnavailable)
+	assert.Equal(t, int32(3), hits.Load())
+
+	_, err = adapter.FetchAgentPackageContentHash(t.Context(), origin.URL+"/other", headers)
+	require.ErrorIs(t, err, agentmodel.ErrPackageOriginUnavailable)
+	assert.Equal(t, int32(3), hits.Load(), "an open breaker does not contact the origin")
+}
+
+func TestAdapter_BreakerClosesAfterSuccessfulProbe(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits      atomic.Int32
+		recovered atomic.Bool
+	)
+
+	origin := newOrigin(t, &hits, func(int32) bool { return !recovered.Load() })
+	adapter := newAdapter(packagedownload.Settings{
+		Timeout:          time.Second,
+		MaxAttempts:      1,
+		RetryBackoff:     time.Millisecond,
+		FailureThreshold: 1,
+		OpenDuration:     50 * time.Millisecond,
+	})
+	headers := map[string]string{"Authorization": "Bearer token"}
+
+	_, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, headers)
+	require.ErrorIs(t, err, packagedownload.ErrUnexpectedStatus)
+
+	_, err = adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, headers)
+	require.ErrorIs(t, err, agentmodel.ErrPackageOriginUnavailable)
+
+	recovered.Store(true)
+
+	assert.Eventually(t, func() bool {
+		_, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, headers)
+
+		return err == nil
+	}, time.Second, 10*time.Millisecond, "the breaker lets a probe through once open duration passes")
+}
+
+func TestAdapter_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(origin.Close)
+
+	adapter := newAdapter(packagedownload.Settings{
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		RetryBackoff:     time.Millisecond,
+		FailureThreshold: 1,
+		OpenDuration:     time.Minute,
+	})
+
+	for range 2 {
+		_, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, nil)
+		require.ErrorIs(t, err, packagedownload.ErrUnexpectedStatus)
+	}
+
+	assert.Equal(t, int32(2), hits.Load(), "a 404 is neither retried nor counted against the origin")
+}
+
+func TestAdapter_CancelledProbeDoesNotKeepBreakerOpen(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits      atomic.Int32
+		hang      atomic.Bool
+		recovered atomic.Bool
+	)
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		if hang.Load() {
+			<-r.Context().Done()
+
+			return
+		}
+
+		if !recovered.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, artifact)
+	}))
+	t.Cleanup(origin.Close)
+
+	adapter := newAdapter(packagedownload.Settings{
+		Timeout:          time.Second,
+		MaxAttempts:      1,
+		RetryBackoff:     time.Millisecond,
+		FailureThreshold: 1,
+		OpenDuration:     20 * time.Millisecond,
+	})
+
+	_, err := adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, nil)
+	require.ErrorIs(t, err, packagedownload.ErrUnexpectedStatus)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The probe is cancelled by its caller while the origin is still answering.
+	hang.Store(true)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = adapter.FetchAgentPackageContentHash(ctx, origin.URL, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	hang.Store(false)
+	recovered.Store(true)
+
+	_, err = adapter.FetchAgentPackageContentHash(t.Context(), origin.URL, nil)
+	require.NoError(t, err, "a cancelled probe lets the next attempt probe the origin")
+}
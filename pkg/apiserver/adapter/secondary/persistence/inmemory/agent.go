@@ -3,11 +3,15 @@ package inmemory
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
@@ -67,6 +71,21 @@ func (r *AgentRepository) PutAgent(_ context.Context, agent *agentmodel.Agent) e
 	return nil
 }
 
+// GetAgentByNewInstanceUID implements agentport.AgentPersistencePort.
+func (r *AgentRepository) GetAgentByNewInstanceUID(
+	_ context.Context,
+	newInstanceUID uuid.UUID,
+) (*agentmodel.Agent, error) {
+	agents := r.store.snapshot(false, func(agent *agentmodel.Agent) bool {
+		return agent.Spec.NewInstanceUID == newInstanceUID
+	})
+	if len(agents) == 0 {
+		return nil, errResourceNotExist()
+	}
+
+	return agents[0], nil
+}
+
 // DeleteAgent implements agentport.AgentPersistencePort.
 func (r *AgentRepository) DeleteAgent(_ context.Context, instanceUID uuid.UUID) error {
 	return r.store.delete(instanceUID)
@@ -80,10 +99,17 @@ func (r *AgentRepository) ListAgents(
 ) (*model.ListResponse[*agentmodel.Agent], error) {
 	connectedOnly := options != nil && options.ConnectedOnly
 
-	var identifyingAttributes, nonIdentifyingAttributes map[string]string
+	var (
+		identifyingAttributes, nonIdentifyingAttributes map[string]string
+		connectionType                                  string
+		capabilities                                    uint64
+	)
+
 	if options != nil {
 		identifyingAttributes = options.IdentifyingAttributes
 		nonIdentifyingAttributes = options.NonIdentifyingAttributes
+		connectionType = options.ConnectionType
+		capabilities = options.Capabilities
 	}
 
 	return r.store.list(options, func(agent *agentmodel.Agent) bool {
@@ -91,6 +117,14 @@ func (r *AgentRepository) ListAgents(
 			return false
 		}
 
+		if connectionType != "" && agent.Status.ConnectionType.String() != connectionType {
+			return false
+		}
+
+		if !hasCapabilities(agent, capabilities) {
+			return false
+		}
+
 		if !matchesAttributes(agent.Metadata.Description.IdentifyingAttributes, identifyingAttributes) {
 			return false
 		}
@@ -111,8 +145,13 @@ func (r *AgentRepository) ListAgentsBySelector(
 ) (*model.ListResponse[*agentmodel.Agent], error) {
 	connectedOnly := options != nil && options.ConnectedOnly
 
+	var capabilities uint64
+	if options != nil {
+		capabilities = options.Capabilities
+	}
+
 	return r.store.list(options, func(agent *agentmodel.Agent) bool {
-		if !matchesSelector(agent, selector) {
+		if !selector.Matches(agent) || !hasCapabilities(agent, capabilities) {
 			return false
 		}
 
@@ -156,17 +195,164 @@ func (r *AgentRepository) SearchAgents(
 	})
 }
 
+// ListAgentsByPackage implements agentport.AgentPersistencePort.
+func (r *AgentRepository) ListAgentsByPackage(
+	_ context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	connectedOnly := options != nil && options.ConnectedOnly
+
+	return r.store.list(options, func(agent *agentmodel.Agent) bool {
+		if agent.Metadata.Namespace != namespace {
+			return false
+		}
+
+		if !agent.Status.PackageStatuses.HasPackage(packageName, packageVersion) {
+			return false
+		}
+
+		return !connectedOnly || r.isConnected(agent)
+	})
+}
+
+// ListAgentAttributes implements agentport.AgentPersistencePort.
+func (r *AgentRepository) ListAgentAttributes(
+	_ context.Context,
+	valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	valueLimit = max(valueLimit, 0)
+
+	identifying := make(map[string]map[string]struct{})
+	nonIdentifying := make(map[string]map[string]struct{})
+
+	for _, agent := range r.store.snapshot(false, func(*agentmodel.Agent) bool { return true }) {
+		collectAttributeValues(identifying, agent.Metadata.Description.IdentifyingAttributes)
+		collectAttributeValues(nonIdentifying, agent.Metadata.Description.NonIdentifyingAttributes)
+	}
+
+	return &agentmodel.AttributeCatalog{
+		IdentifyingAttributes:    attributeCatalogEntries(identifying, valueLimit),
+		NonIdentifyingAttributes: attributeCatalogEntries(nonIdentifying, valueLimit),
+	}, nil
+}
+
+func collectAttributeValues(seen map[string]map[string]struct{}, attributes map[string]string) {
+	for key, value := range attributes {
+		if seen[key] == nil {
+			seen[key] = make(map[string]struct{})
+		}
+
+		seen[key][value] = struct{}{}
+	}
+}
+
+// attributeCatalogEntries mirrors the MongoDB aggregation: keys sorted, each with its
+// smallest valueLimit distinct values.
+func attributeCatalogEntries(seen map[string]map[string]struct{}, valueLimit int) []agentmodel.AttributeCatalogEntry {
+	entries := make([]agentmodel.AttributeCatalogEntry, 0, len(seen))
+
+	for _, key := range slices.Sorted(maps.Keys(seen)) {
+		var values []string
+		if valueLimit > 0 {
+			values = slices.Sorted(maps.Keys(seen[key]))
+		}
+
+		entries = append(entries, agentmodel.AttributeCatalogEntry{
+			Key:             key,
+			Values:          values[:min(len(values), valueLimit)],
+			ValuesTruncated: len(values) > valueLimit,
+		})
+	}
+
+	return entries
+}
+
+// GetAgentFleetSummary implements agentport.AgentPersistencePort. Like the agent group
+// statistics, healthy and unhealthy counts only consider connected agents.
+func (r *AgentRepository) GetAgentFleetSummary(_ context.Context) (*agentmodel.AgentFleetSummary, error) {
+	//exhaustruct:ignore
+	summary := &agentmodel.AgentFleetSummary{}
+
+	for _, agent := range r.store.snapshot(false, func(*agentmodel.Agent) bool { return true }) {
+		summary.NumAgents++
+
+		if agent.PendingCommandCount() > 0 {
+			summary.NumAgentsWithPendingCommands++
+		}
+
+		if !r.isConnected(agent) {
+			continue
+		}
+
+		summary.NumConnectedAgents++
+
+		if agent.Status.ComponentHealth.Healthy {
+			summary.NumHealthyAgents++
+		} else {
+			summary.NumUnhealthyAgents++
+		}
+	}
+
+	return summary, nil
+}
+
+// GetCommandQueueSummary implements agentport.AgentPersistencePort.
+func (r *AgentRepository) GetCommandQueueSummary(
+	_ context.Context,
+	maxPending int,
+) (*agentmodel.CommandQueueSummary, error) {
+	//exhaustruct:ignore
+	summary := &agentmodel.CommandQueueSummary{}
+
+	for _, agent := range r.store.snapshot(false, func(*agentmodel.Agent) bool { return true }) {
+		pending := agent.PendingCommandCount()
+		summary.NumPendingCommands += int64(pending)
+
+		if maxPending > 0 && pending >= maxPending {
+			summary.NumFullQueues++
+		}
+	}
+
+	return summary, nil
+}
+
+// ListAgentsConnectionLostBy implements agentport.AgentPersistencePort. Like the MongoDB
+// adapter, the agents whose connection was lost first are returned first.
+func (r *AgentRepository) ListAgentsConnectionLostBy(
+	_ context.Context,
+	lostBy time.Time,
+	limit int,
+) ([]*agentmodel.Agent, error) {
+	agents := r.store.snapshot(false, func(agent *agentmodel.Agent) bool {
+		return agent.IsInDisconnectGrace() && !agent.Status.ConnectionLostAt.After(lostBy)
+	})
+	slices.SortStableFunc(agents, func(a, b *agentmodel.Agent) int {
+		return a.Status.ConnectionLostAt.Compare(b.Status.ConnectionLostAt)
+	})
+
+	return agents[:min(len(agents), limit)], nil
+}
+
 // isConnected mirrors the MongoDB connected filter: the explicit Connected flag
 // plus heartbeat staleness, evaluated against the repository clock.
 func (r *AgentRepository) isConnected(agent *agentmodel.Agent) bool {
 	return agent.IsConnectedAt(r.clock.Now(), agentmodel.DefaultConnectionStaleness)
 }
 
+// hasCapabilities reports whether the agent reported every capability bit in capabilities.
+// Zero selects every agent.
+func hasCapabilities(agentModel *agentmodel.Agent, capabilities uint64) bool {
+	return agentModel.Metadata.Capabilities.Has(agent.Capability(capabilities))
+}
+
 // agentsMatchingSelector returns every stored agent (including hard-undeletable
 // ones) matching the selector. Used by the agent-group repository to compute
 // group statistics.
 func (r *AgentRepository) agentsMatchingSelector(selector agentmodel.AgentSelector) []*agentmodel.Agent {
 	return r.store.snapshot(false, func(agent *agentmodel.Agent) bool {
-		return matchesSelector(agent, selector)
+		return selector.Matches(agent)
 	})
 }
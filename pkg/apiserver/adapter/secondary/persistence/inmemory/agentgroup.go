@@ -2,6 +2,7 @@ package inmemory
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
@@ -63,6 +64,22 @@ func (r *AgentGroupRepository) ListAgentGroups(
 	return resp, nil
 }
 
+// ListAgentGroupNamesIgnoringCase implements agentport.AgentGroupPersistencePort.
+func (r *AgentGroupRepository) ListAgentGroupNamesIgnoringCase(
+	_ context.Context, namespace string, name string,
+) ([]string, error) {
+	agentGroups := r.store.snapshot(false, func(ag *agentmodel.AgentGroup) bool {
+		return ag.Metadata.Namespace == namespace && strings.EqualFold(ag.Metadata.Name, name)
+	})
+
+	names := make([]string, 0, len(agentGroups))
+	for _, agentGroup := range agentGroups {
+		names = append(names, agentGroup.Metadata.Name)
+	}
+
+	return names, nil
+}
+
 // PutAgentGroup implements agentport.AgentGroupPersistencePort.
 func (r *AgentGroupRepository) PutAgentGroup(
 	_ context.Context, namespace string, name string, agentGroup *agentmodel.AgentGroup,
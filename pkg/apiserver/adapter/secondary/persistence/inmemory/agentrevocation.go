@@ -0,0 +1,46 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ agentport.AgentRevocationPersistencePort = (*AgentRevocationRepository)(nil)
+
+// AgentRevocationRepository is the in-memory implementation of
+// [agentport.AgentRevocationPersistencePort]. Revocations are hard-deleted.
+type AgentRevocationRepository struct {
+	store *store[uuid.UUID, *agentmodel.AgentRevocation]
+}
+
+// NewAgentRevocationRepository creates a new in-memory AgentRevocationRepository.
+func NewAgentRevocationRepository() *AgentRevocationRepository {
+	return &AgentRevocationRepository{
+		store: newStore[uuid.UUID](cloneAgentRevocation, nil),
+	}
+}
+
+// GetAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) GetAgentRevocation(
+	_ context.Context, instanceUID uuid.UUID,
+) (*agentmodel.AgentRevocation, error) {
+	return r.store.get(instanceUID, nil)
+}
+
+// PutAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) PutAgentRevocation(
+	_ context.Context, revocation *agentmodel.AgentRevocation,
+) error {
+	r.store.put(revocation.InstanceUID, revocation)
+
+	return nil
+}
+
+// DeleteAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) DeleteAgentRevocation(_ context.Context, instanceUID uuid.UUID) error {
+	return r.store.delete(instanceUID)
+}
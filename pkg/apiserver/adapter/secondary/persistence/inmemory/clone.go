@@ -56,6 +56,17 @@ func cloneContainer(container *agentmodel.Container) *agentmodel.Container {
 	return &cloned
 }
 
+// cloneAgentRevocation copies a revocation; it holds only value fields.
+func cloneAgentRevocation(revocation *agentmodel.AgentRevocation) *agentmodel.AgentRevocation {
+	if revocation == nil {
+		return nil
+	}
+
+	cloned := *revocation
+
+	return &cloned
+}
+
 func cloneStringPtr(str *string) *string {
 	if str == nil {
 		return nil
@@ -112,6 +123,12 @@ func cloneAgentGroup(agentGroup *agentmodel.AgentGroup) *agentmodel.AgentGroup {
 	cloned.Metadata.Attributes = maps.Clone(agentGroup.Metadata.Attributes)
 	cloned.Spec.Selector.IdentifyingAttributes = maps.Clone(agentGroup.Spec.Selector.IdentifyingAttributes)
 	cloned.Spec.Selector.NonIdentifyingAttributes = maps.Clone(agentGroup.Spec.Selector.NonIdentifyingAttributes)
+	cloned.Spec.Selector.AbsentIdentifyingAttributes = slices.Clone(agentGroup.Spec.Selector.AbsentIdentifyingAttributes)
+	cloned.Spec.Selector.AbsentNonIdentifyingAttributes = slices.Clone(
+		agentGroup.Spec.Selector.AbsentNonIdentifyingAttributes)
+	cloned.Spec.Selector.IdentifyingMatchExpressions = slices.Clone(agentGroup.Spec.Selector.IdentifyingMatchExpressions)
+	cloned.Spec.Selector.NonIdentifyingMatchExpressions = slices.Clone(
+		agentGroup.Spec.Selector.NonIdentifyingMatchExpressions)
 	cloned.Spec.AgentConnectionConfig = cloneAgentGroupConnectionConfig(agentGroup.Spec.AgentConnectionConfig)
 	cloned.Status.Conditions = slices.Clone(agentGroup.Status.Conditions)
 
@@ -124,6 +141,29 @@ func cloneAgentGroup(agentGroup *agentmodel.AgentGroup) *agentmodel.AgentGroup {
 		cloned.Spec.AgentRemoteConfigs = configs
 	}
 
+	if agentGroup.Spec.Rollout != nil {
+		rollout := *agentGroup.Spec.Rollout
+		rollout.PreviousAgentRemoteConfigs = nil
+
+		for i := range agentGroup.Spec.Rollout.PreviousAgentRemoteConfigs {
+			rollout.PreviousAgentRemoteConfigs = append(rollout.PreviousAgentRemoteConfigs,
+				*cloneAgentGroupRemoteConfig(&agentGroup.Spec.Rollout.PreviousAgentRemoteConfigs[i]))
+		}
+
+		cloned.Spec.Rollout = &rollout
+	}
+
+	if agentGroup.Spec.PreviousRemoteConfigs != nil {
+		previous := &agentmodel.AgentGroupPreviousRemoteConfigs{AgentRemoteConfigs: nil}
+
+		for i := range agentGroup.Spec.PreviousRemoteConfigs.AgentRemoteConfigs {
+			previous.AgentRemoteConfigs = append(previous.AgentRemoteConfigs,
+				*cloneAgentGroupRemoteConfig(&agentGroup.Spec.PreviousRemoteConfigs.AgentRemoteConfigs[i]))
+		}
+
+		cloned.Spec.PreviousRemoteConfigs = previous
+	}
+
 	return &cloned
 }
 
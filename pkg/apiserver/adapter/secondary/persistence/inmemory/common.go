@@ -1,7 +1,6 @@
 package inmemory
 
 import (
-	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
@@ -25,25 +24,6 @@ func errConflict() error {
 	return model.ErrConflict
 }
 
-// matchesSelector reports whether the agent satisfies every identifying and
-// non-identifying attribute in the selector. An empty selector matches all
-// agents, mirroring the MongoDB selector-to-filter behaviour.
-func matchesSelector(agent *agentmodel.Agent, selector agentmodel.AgentSelector) bool {
-	for key, value := range selector.IdentifyingAttributes {
-		if agent.Metadata.Description.IdentifyingAttributes[key] != value {
-			return false
-		}
-	}
-
-	for key, value := range selector.NonIdentifyingAttributes {
-		if agent.Metadata.Description.NonIdentifyingAttributes[key] != value {
-			return false
-		}
-	}
-
-	return true
-}
-
 // matchesAttributes reports whether the stored attribute map contains every
 // key=value pair in the selector (an AND of equality conditions). An empty
 // selector matches everything, mirroring the MongoDB attribute filter used by the
@@ -12,7 +12,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/persistencetest"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
@@ -194,6 +197,159 @@ func TestAgentRepository_ListByNonIdentifyingAttributesSelector(t *testing.T) {
 	assert.Empty(t, resp.Items)
 }
 
+func TestAgentRepository_ListByConnectionType(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := inmemory.NewAgentRepository()
+
+	websocketAgent := agentmodel.NewAgent(uuid.New())
+	websocketAgent.Status.ConnectionType = agentmodel.ConnectionTypeWebSocket
+	require.NoError(t, repo.PutAgent(ctx, websocketAgent))
+
+	httpAgent := agentmodel.NewAgent(uuid.New())
+	httpAgent.Status.ConnectionType = agentmodel.ConnectionTypeHTTP
+	require.NoError(t, repo.PutAgent(ctx, httpAgent))
+
+	//exhaustruct:ignore
+	resp, err := repo.ListAgents(ctx, "default", &model.ListOptions{
+		ConnectionType: agentmodel.ConnectionTypeWebSocket.String(),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, websocketAgent.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+
+	//exhaustruct:ignore
+	resp, err = repo.ListAgents(ctx, "default", &model.ListOptions{
+		ConnectionType: agentmodel.ConnectionTypeUnknown.String(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+}
+
+func TestAgentRepository_ListByCapabilities(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := inmemory.NewAgentRepository()
+
+	acceptsPackages := uint64(agent.AgentCapabilityAcceptsPackages)
+
+	withPackages := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-a"))
+	withPackages.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityReportsStatus) |
+		agent.Capabilities(agent.AgentCapabilityAcceptsPackages)
+	withPackages.Status.Connected = true
+	withPackages.Status.LastReportedAt = time.Now()
+	require.NoError(t, repo.PutAgent(ctx, withPackages))
+
+	statusOnly := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-b"))
+	statusOnly.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityReportsStatus)
+	statusOnly.Status.Connected = true
+	statusOnly.Status.LastReportedAt = time.Now()
+	require.NoError(t, repo.PutAgent(ctx, statusOnly))
+
+	// A disconnected agent with the capability is left out of a connected-only listing.
+	disconnected := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-b"))
+	disconnected.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityAcceptsPackages)
+	require.NoError(t, repo.PutAgent(ctx, disconnected))
+
+	//exhaustruct:ignore
+	resp, err := repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{}, &model.ListOptions{
+		ConnectedOnly: true,
+		Capabilities:  acceptsPackages,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, withPackages.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+
+	//exhaustruct:ignore
+	resp, err = repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{}, &model.ListOptions{
+		ConnectedOnly: true,
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 2, "no capability filter lists every connected agent")
+
+	//exhaustruct:ignore
+	resp, err = repo.ListAgents(ctx, "team-b", &model.ListOptions{Capabilities: acceptsPackages})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, disconnected.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+}
+
+func TestAgentRepository_ListBySelectorAbsentAttributes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := inmemory.NewAgentRepository()
+
+	withHost := agentmodel.NewAgent(uuid.New())
+	withHost.Metadata.Description.IdentifyingAttributes = map[string]string{"service.name": "otel-collector"}
+	withHost.Metadata.Description.NonIdentifyingAttributes = map[string]string{"host.name": "node-1"}
+	require.NoError(t, repo.PutAgent(ctx, withHost))
+
+	// An attribute reported with an empty value is still present.
+	emptyHost := agentmodel.NewAgent(uuid.New())
+	emptyHost.Metadata.Description.IdentifyingAttributes = map[string]string{"service.name": "otel-collector"}
+	emptyHost.Metadata.Description.NonIdentifyingAttributes = map[string]string{"host.name": ""}
+	require.NoError(t, repo.PutAgent(ctx, emptyHost))
+
+	withoutHost := agentmodel.NewAgent(uuid.New())
+	withoutHost.Metadata.Description.IdentifyingAttributes = map[string]string{"service.name": "otel-collector"}
+	require.NoError(t, repo.PutAgent(ctx, withoutHost))
+
+	resp, err := repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+		IdentifyingAttributes:          map[string]string{"service.name": "otel-collector"},
+		AbsentNonIdentifyingAttributes: []string{"host.name"},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, withoutHost.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+
+	// Absence is checked per attribute map: host.name is not an identifying attribute.
+	resp, err = repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+		AbsentIdentifyingAttributes: []string{"host.name"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 3)
+}
+
+func TestAgentRepository_ListBySelectorNumericComparison(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := inmemory.NewAgentRepository()
+
+	agentOnPort := func(port string) *agentmodel.Agent {
+		a := agentmodel.NewAgent(uuid.New())
+		a.Metadata.Description.NonIdentifyingAttributes = map[string]string{"port": port}
+		require.NoError(t, repo.PutAgent(ctx, a))
+
+		return a
+	}
+
+	low := agentOnPort("80")
+	high := agentOnPort("8080")
+	agentOnPort("http")
+
+	resp, err := repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+		NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+			{Key: "port", Operator: agentmodel.AttributeMatchOperatorGreaterThan, Value: 1024},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, high.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+
+	resp, err = repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+		NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+			{Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, low.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+}
+
 func TestNamespaceRepository_SoftDeleteHiddenUnlessIncluded(t *testing.T) {
 	t.Parallel()
 
@@ -351,6 +507,31 @@ func TestAgentRepository_GetReturnsIsolatedCopy(t *testing.T) {
 	assert.Equal(t, agentmodel.DefaultNamespaceName, reread.Metadata.Namespace)
 }
 
+func TestAgentGroupRepository_ListAgentGroupNamesIgnoringCase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	groupRepo := inmemory.NewAgentGroupRepository(inmemory.NewAgentRepository())
+
+	for _, agentGroup := range []*agentmodel.AgentGroup{
+		agentmodel.NewAgentGroup("default", "Prod", nil, time.Now(), "tester"),
+		agentmodel.NewAgentGroup("default", "staging", nil, time.Now(), "tester"),
+		agentmodel.NewAgentGroup("other", "prod", nil, time.Now(), "tester"),
+	} {
+		_, err := groupRepo.PutAgentGroup(ctx, agentGroup.Metadata.Namespace, agentGroup.Metadata.Name, agentGroup)
+		require.NoError(t, err)
+	}
+
+	deleted := agentmodel.NewAgentGroup("default", "PROD", nil, time.Now(), "tester")
+	deleted.MarkDeleted(time.Now(), "tester")
+	_, err := groupRepo.PutAgentGroup(ctx, "default", "PROD", deleted)
+	require.NoError(t, err)
+
+	names, err := groupRepo.ListAgentGroupNamesIgnoringCase(ctx, "default", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Prod"}, names)
+}
+
 // TestAgentGroupRepository_ConcurrentAccessNoRace exercises the store under
 // concurrent readers and writers; it is meaningful under `go test -race`, where
 // it would previously have reported a data race on the shared stored pointer.
@@ -778,3 +959,37 @@ func TestEndpointRepository_PutOptimisticConcurrency(t *testing.T) {
 	assert.Equal(t, "https://a.example.com", stored.Spec.URL, "the losing writer must not overwrite the winner")
 	assert.Equal(t, int64(2), stored.Metadata.ResourceVersion)
 }
+
+func TestAgentRevocationRepository_PutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := inmemory.NewAgentRevocationRepository()
+	uid := uuid.New()
+	now := time.Date(2026, 6, 19, 0, 0, 0, 0, time.UTC)
+
+	_, err := repo.GetAgentRevocation(ctx, uid)
+	require.ErrorIs(t, err, model.ErrResourceNotExist)
+
+	require.NoError(t, repo.PutAgentRevocation(ctx, agentmodel.NewAgentRevocation(uid, "admin", "leaked", now)))
+
+	got, err := repo.GetAgentRevocation(ctx, uid)
+	require.NoError(t, err)
+	assert.Equal(t, uid, got.InstanceUID)
+	assert.Equal(t, "leaked", got.Reason)
+	assert.Equal(t, now, got.RevokedAt)
+
+	require.NoError(t, repo.DeleteAgentRevocation(ctx, uid))
+
+	_, err = repo.GetAgentRevocation(ctx, uid)
+	require.ErrorIs(t, err, model.ErrResourceNotExist)
+	require.ErrorIs(t, repo.DeleteAgentRevocation(ctx, uid), model.ErrResourceNotExist)
+}
+
+func TestAgentRepository_Conformance(t *testing.T) {
+	t.Parallel()
+
+	persistencetest.RunAgentPersistenceSuite(t, func(*testing.T) agentport.AgentPersistencePort {
+		return inmemory.NewAgentRepository()
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -88,28 +89,113 @@ func (a *AgentRepository) GetAgent(ctx context.Context, instanceUID uuid.UUID) (
 	return entity.ToDomain(), nil
 }
 
+// GetAgentByNewInstanceUID implements agentport.AgentPersistencePort.
+func (a *AgentRepository) GetAgentByNewInstanceUID(
+	ctx context.Context,
+	newInstanceUID uuid.UUID,
+) (*agentmodel.Agent, error) {
+	filter := bson.M{
+		entity.AgentNewInstanceUIDFieldName: bson.Binary{
+			Subtype: bson.TypeBinaryUUID,
+			Data:    newInstanceUID[:],
+		},
+	}
+
+	var agentEntity entity.Agent
+
+	err := a.collection.FindOne(ctx, filter).Decode(&agentEntity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, model.ErrResourceNotExist
+		}
+
+		return nil, fmt.Errorf("failed to get agent by new instance UID from mongodb: %w", err)
+	}
+
+	return agentEntity.ToDomain(), nil
+}
+
+// ListAgentsConnectionLostBy implements agentport.AgentPersistencePort. The agents whose
+// connection was lost first are returned first.
+func (a *AgentRepository) ListAgentsConnectionLostBy(
+	ctx context.Context,
+	lostBy time.Time,
+	limit int,
+) ([]*agentmodel.Agent, error) {
+	filter := bson.M{
+		"status.connected":                    true,
+		entity.AgentConnectionLostAtFieldName: bson.M{"$lte": bson.NewDateTimeFromTime(lostBy)},
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: entity.AgentConnectionLostAtFieldName, Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := a.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents whose connection was lost from mongodb: %w", err)
+	}
+
+	defer func() {
+		closeErr := cursor.Close(ctx)
+		if closeErr != nil {
+			a.logger.Warn("failed to close mongodb cursor", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var entities []*entity.Agent
+
+	err = cursor.All(ctx, &entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode agents whose connection was lost from mongodb: %w", err)
+	}
+
+	return lo.Map(entities, func(item *entity.Agent, _ int) *agentmodel.Agent {
+		return item.ToDomain()
+	}), nil
+}
+
 // ListAgents implements agentport.AgentPersistencePort.
 func (a *AgentRepository) ListAgents(
 	ctx context.Context,
 	namespace string,
 	options *model.ListOptions,
 ) (*model.ListResponse[*agentmodel.Agent], error) {
+	if options == nil {
+		//exhaustruct:ignore
+		options = &model.ListOptions{}
+	}
+
+	scope := newAgentListScope(namespace, options.ConnectedOnly, options.ConnectionType, options.Capabilities,
+		options.IdentifyingAttributes, options.NonIdentifyingAttributes)
+
+	continueTokenObjectID, err := scope.decode(options.Continue)
+	if err != nil {
+		return nil, err
+	}
+
 	conditions := []bson.M{{"metadata.namespace": sanitizeResourceName(namespace)}}
 
-	if options != nil {
-		if options.ConnectedOnly {
-			conditions = append(conditions, connectedMatchFilter())
-		}
-		// Each attribute condition is a separate $elemMatch on the same field, so
-		// they must be combined with $and (via buildFilter) rather than flattened
-		// into one map, which would drop all but the last.
-		conditions = append(conditions,
-			IdentifyingAttributesSelectorToMatchConditions(options.IdentifyingAttributes)...)
-		conditions = append(conditions,
-			NonIdentifyingAttributesSelectorToMatchConditions(options.NonIdentifyingAttributes)...)
+	if options.ConnectedOnly {
+		conditions = append(conditions, connectedMatchFilter())
+	}
+
+	if options.ConnectionType != "" {
+		conditions = append(conditions, connectionTypeMatchFilter(options.ConnectionType))
 	}
 
-	resp, err := a.common.listWithFilter(ctx, options, buildFilter(conditions))
+	if options.Capabilities != 0 {
+		conditions = append(conditions, capabilitiesMatchFilter(options.Capabilities))
+	}
+
+	// Each attribute condition is a separate $elemMatch on the same field, so
+	// they must be combined with $and (via buildFilter) rather than flattened
+	// into one map, which would drop all but the last.
+	conditions = append(conditions,
+		IdentifyingAttributesSelectorToMatchConditions(options.IdentifyingAttributes)...)
+	conditions = append(conditions,
+		NonIdentifyingAttributesSelectorToMatchConditions(options.NonIdentifyingAttributes)...)
+
+	resp, err := a.common.listWithFilterAfter(ctx, options, continueTokenObjectID, buildFilter(conditions))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list agents from persistence: %w", err)
 	}
@@ -118,11 +204,98 @@ func (a *AgentRepository) ListAgents(
 		Items: lo.Map(resp.Items, func(item *entity.Agent, _ int) *agentmodel.Agent {
 			return item.ToDomain()
 		}),
-		Continue:           resp.Continue,
+		Continue:           scope.encode(resp.Continue),
+		RemainingItemCount: resp.RemainingItemCount,
+	}, nil
+}
+
+// connectionTypeMatchFilter selects agents whose status.connectionType is connectionType.
+// The entity omits the field when it is empty, so "Unknown" also matches agents stored
+// without one.
+func connectionTypeMatchFilter(connectionType string) bson.M {
+	if connectionType == agentmodel.ConnectionTypeUnknown.String() {
+		return bson.M{"status.connectionType": bson.M{"$in": bson.A{connectionType, nil}}}
+	}
+
+	return bson.M{"status.connectionType": connectionType}
+}
+
+// capabilitiesMatchFilter selects agents whose metadata.capabilities bitmask has every bit
+// of capabilities set. Agents stored without capabilities never match.
+func capabilitiesMatchFilter(capabilities uint64) bson.M {
+	//nolint:gosec // OpAMP defines capability bits well below the sign bit.
+	return bson.M{"metadata.capabilities": bson.M{"$bitsAllSet": int64(capabilities)}}
+}
+
+// ListAgentsByPackage implements agentport.AgentPersistencePort.
+func (a *AgentRepository) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	if options == nil {
+		//exhaustruct:ignore
+		options = &model.ListOptions{}
+	}
+
+	scope := newAgentPackageScope(namespace, options.ConnectedOnly, packageName, packageVersion)
+
+	continueTokenObjectID, err := scope.decode(options.Continue)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []bson.M{
+		{"metadata.namespace": sanitizeResourceName(namespace)},
+		packageMatchFilter(packageName, packageVersion),
+	}
+
+	if options.ConnectedOnly {
+		conditions = append(conditions, connectedMatchFilter())
+	}
+
+	resp, err := a.common.listWithFilterAfter(ctx, options, continueTokenObjectID, buildFilter(conditions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents by package from persistence: %w", err)
+	}
+
+	return &model.ListResponse[*agentmodel.Agent]{
+		Items: lo.Map(resp.Items, func(item *entity.Agent, _ int) *agentmodel.Agent {
+			return item.ToDomain()
+		}),
+		Continue:           scope.encode(resp.Continue),
 		RemainingItemCount: resp.RemainingItemCount,
 	}, nil
 }
 
+// packageMatchFilter selects the agents that reported the named package at version, or at
+// any version when version is empty. Package statuses are stored as a map keyed by package
+// name, and a name may contain dots, so it cannot be spliced into a field path; the map is
+// expanded with $objectToArray instead, as MongoDB 4.4 has no $getField. The name and
+// version are wrapped in $literal so that a leading "$" is not read as a field path.
+func packageMatchFilter(name, version string) bson.M {
+	match := []any{bson.M{"$eq": []any{"$$package.k", bson.M{"$literal": name}}}}
+	if version != "" {
+		match = append(match, bson.M{"$eq": []any{"$$package.v.agentHasVersion", bson.M{"$literal": version}}})
+	}
+
+	packages := bson.M{"$ifNull": []any{"$status.packageStatuses.packages", bson.M{}}}
+
+	return bson.M{
+		"$expr": bson.M{
+			"$anyElementTrue": []any{bson.M{
+				"$map": bson.M{
+					"input": bson.M{"$objectToArray": packages},
+					"as":    "package",
+					"in":    bson.M{"$and": match},
+				},
+			}},
+		},
+	}
+}
+
 // PutAgent implements agentport.AgentPersistencePort.
 //
 // PutAgent is an optimistic-concurrency write: it only succeeds when the stored
@@ -200,9 +373,11 @@ func (a *AgentRepository) ListAgentsBySelector(
 		options = &model.ListOptions{}
 	}
 
-	continueTokenObjectID, err := bson.ObjectIDFromHex(options.Continue)
-	if err != nil && options.Continue != "" {
-		return nil, fmt.Errorf("invalid continue token: %w", err)
+	scope := newAgentSelectorScope(options.ConnectedOnly, options.Capabilities, selector)
+
+	continueTokenObjectID, err := scope.decode(options.Continue)
+	if err != nil {
+		return nil, err
 	}
 
 	allConditions := SelectorToMatchConditions(AgentSelectorToEntity(selector))
@@ -211,6 +386,10 @@ func (a *AgentRepository) ListAgentsBySelector(
 		allConditions = append(allConditions, connectedMatchFilter())
 	}
 
+	if options.Capabilities != 0 {
+		allConditions = append(allConditions, capabilitiesMatchFilter(options.Capabilities))
+	}
+
 	// Add continue token condition if present
 	continueTokenFilter := withContinueToken(continueTokenObjectID)
 	if continueTokenFilter != nil {
@@ -282,7 +461,7 @@ func (a *AgentRepository) ListAgentsBySelector(
 		Items: lo.Map(entitiesRetval, func(item *entity.Agent, _ int) *agentmodel.Agent {
 			return item.ToDomain()
 		}),
-		Continue:           continueTokenRetval,
+		Continue:           scope.encode(continueTokenRetval),
 		RemainingItemCount: countRetval - int64(len(entitiesRetval)),
 	}, nil
 }
@@ -290,8 +469,13 @@ func (a *AgentRepository) ListAgentsBySelector(
 // AgentSelectorToEntity converts a domain AgentSelector to a persistence entity AgentSelector.
 func AgentSelectorToEntity(selector agentmodel.AgentSelector) entity.AgentSelector {
 	return entity.AgentSelector{
-		IdentifyingAttributes:    selector.IdentifyingAttributes,
-		NonIdentifyingAttributes: selector.NonIdentifyingAttributes,
+		IdentifyingAttributes:          selector.IdentifyingAttributes,
+		NonIdentifyingAttributes:       selector.NonIdentifyingAttributes,
+		AbsentIdentifyingAttributes:    selector.AbsentIdentifyingAttributes,
+		AbsentNonIdentifyingAttributes: selector.AbsentNonIdentifyingAttributes,
+		IdentifyingMatchExpressions:    entity.AttributeMatchExpressionsFromDomain(selector.IdentifyingMatchExpressions),
+		NonIdentifyingMatchExpressions: entity.AttributeMatchExpressionsFromDomain(selector.NonIdentifyingMatchExpressions),
+		CaseInsensitive:                selector.CaseInsensitive,
 	}
 }
 
@@ -509,6 +693,31 @@ func (a *AgentRepository) ensureIndexes(ctx context.Context) {
 		a.logger.Warn("failed to create index for instanceUidString", slog.String("error", err.Error()))
 	}
 
+	// Only agents pending reassignment carry a new instance UID, so the index is sparse.
+	//exhaustruct:ignore
+	newInstanceUIDIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: entity.AgentNewInstanceUIDFieldName, Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+
+	_, err = a.collection.Indexes().CreateOne(ctx, newInstanceUIDIndex)
+	if err != nil {
+		a.logger.Warn("failed to create index for newInstanceUID", slog.String("error", err.Error()))
+	}
+
+	// Only agents in their disconnect grace period carry a connectionLostAt, so the index is
+	// sparse.
+	//exhaustruct:ignore
+	connectionLostAtIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: entity.AgentConnectionLostAtFieldName, Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+
+	_, err = a.collection.Indexes().CreateOne(ctx, connectionLostAtIndex)
+	if err != nil {
+		a.logger.Warn("failed to create index for connectionLostAt", slog.String("error", err.Error()))
+	}
+
 	// The unique index on metadata.instanceUid — which PutAgent's optimistic-concurrency
 	// create path relies on — is owned by the centralized EnsureSchema (mongodb.go) so it
 	// is not declared twice with conflicting options.
@@ -373,6 +373,165 @@ func TestAgentMongoAdapter_ListAgentsByNonIdentifyingSelector(t *testing.T) {
 	assert.Empty(t, none.Items)
 }
 
+func TestAgentMongoAdapter_ListAgentsBySelector_AbsentAttributes(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(
+		ctx,
+		testMongoDBImage,
+	)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := client.Disconnect(ctx)
+		require.NoError(t, err)
+	})
+
+	database := client.Database("testdb_selector_absent")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+
+	withHost := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes:    map[string]string{"service.name": "otel-collector"},
+		NonIdentifyingAttributes: map[string]string{"host.name": "node-1"},
+	}))
+	require.NoError(t, agentRepository.PutAgent(ctx, withHost))
+
+	// An attribute reported with an empty value is still present.
+	emptyHost := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes:    map[string]string{"service.name": "otel-collector"},
+		NonIdentifyingAttributes: map[string]string{"host.name": ""},
+	}))
+	require.NoError(t, agentRepository.PutAgent(ctx, emptyHost))
+
+	withoutHost := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes: map[string]string{"service.name": "otel-collector"},
+	}))
+	require.NoError(t, agentRepository.PutAgent(ctx, withoutHost))
+
+	t.Run("present attribute excludes the agent", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			IdentifyingAttributes:          map[string]string{"service.name": "otel-collector"},
+			AbsentNonIdentifyingAttributes: []string{"host.name"},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, withoutHost.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+		assert.Equal(t, int64(0), resp.RemainingItemCount)
+	})
+
+	t.Run("absence is checked per attribute map", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			AbsentIdentifyingAttributes: []string{"host.name"},
+		}, nil)
+		require.NoError(t, err)
+		assert.Len(t, resp.Items, 3)
+	})
+
+	t.Run("absent key also required present matches nothing", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			NonIdentifyingAttributes:       map[string]string{"host.name": "node-1"},
+			AbsentNonIdentifyingAttributes: []string{"host.name"},
+		}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+	})
+}
+
+func TestAgentMongoAdapter_ListAgentsBySelector_NumericComparison(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	database := client.Database("testdb_selector_numeric")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+
+	agentOnPort := func(port string) *agentmodel.Agent {
+		a := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+			IdentifyingAttributes:    map[string]string{"service.name": "otel-collector"},
+			NonIdentifyingAttributes: map[string]string{"port": port},
+		}))
+		require.NoError(t, agentRepository.PutAgent(ctx, a))
+
+		return a
+	}
+
+	low := agentOnPort("80")
+	high := agentOnPort("10000")
+	// Attributes are stored as strings; a value that does not parse as a number matches
+	// neither comparison.
+	agentOnPort("http")
+	// An agent without the attribute matches neither comparison either.
+	require.NoError(t, agentRepository.PutAgent(ctx, agentmodel.NewAgent(uuid.New(),
+		agentmodel.WithDescription(&agent.Description{
+			IdentifyingAttributes: map[string]string{"service.name": "otel-collector"},
+		}))))
+
+	t.Run("greater than", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorGreaterThan, Value: 1024},
+			},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, high.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+		assert.Equal(t, int64(0), resp.RemainingItemCount)
+	})
+
+	t.Run("less than", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024},
+			},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, low.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+	})
+
+	t.Run("comparison is checked per attribute map", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			IdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorGreaterThan, Value: 0},
+			},
+		}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+	})
+}
+
 func TestAgentMongoAdapter_ListAgents_ConnectedOnly(t *testing.T) {
 	testcontainers.SkipIfProviderIsNotHealthy(t)
 	t.Parallel()
@@ -424,6 +583,105 @@ func TestAgentMongoAdapter_ListAgents_ConnectedOnly(t *testing.T) {
 	assert.Len(t, all.Items, 3)
 }
 
+func TestAgentMongoAdapter_ListAgents_ConnectionType(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	database := client.Database("testdb_connection_type")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+
+	uidsByType := map[agentmodel.ConnectionType]uuid.UUID{}
+	for _, connectionType := range []agentmodel.ConnectionType{
+		agentmodel.ConnectionTypeWebSocket,
+		agentmodel.ConnectionTypeHTTP,
+		agentmodel.ConnectionTypeUnknown,
+	} {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Status.ConnectionType = connectionType
+		require.NoError(t, agentRepository.PutAgent(ctx, agent))
+
+		uidsByType[connectionType] = agent.Metadata.InstanceUID
+	}
+
+	for connectionType, instanceUID := range uidsByType {
+		//exhaustruct:ignore
+		resp, err := agentRepository.ListAgents(ctx, "default", &model.ListOptions{
+			ConnectionType: connectionType.String(),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1, connectionType.String())
+		assert.Equal(t, instanceUID, resp.Items[0].Metadata.InstanceUID)
+	}
+
+	//exhaustruct:ignore
+	all, err := agentRepository.ListAgents(ctx, "default", &model.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, all.Items, 3)
+}
+
+func TestAgentMongoAdapter_ListAgentsBySelector_Capabilities(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	database := client.Database("testdb_capabilities")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+
+	withPackages := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-a"))
+	withPackages.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityReportsStatus) |
+		agent.Capabilities(agent.AgentCapabilityAcceptsPackages)
+	require.NoError(t, agentRepository.PutAgent(ctx, withPackages))
+
+	statusOnly := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-b"))
+	statusOnly.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityReportsStatus)
+	require.NoError(t, agentRepository.PutAgent(ctx, statusOnly))
+
+	withoutCapabilities := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace("team-b"))
+	require.NoError(t, agentRepository.PutAgent(ctx, withoutCapabilities))
+
+	//exhaustruct:ignore
+	resp, err := agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{}, &model.ListOptions{
+		Capabilities: uint64(agent.AgentCapabilityAcceptsPackages),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, withPackages.Metadata.InstanceUID, resp.Items[0].Metadata.InstanceUID)
+
+	//exhaustruct:ignore
+	resp, err = agentRepository.ListAgentsBySelector(ctx, agentmodel.AgentSelector{}, &model.ListOptions{
+		Capabilities: uint64(agent.AgentCapabilityReportsStatus),
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 2)
+}
+
 func TestAgentMongoAdapter_PutAgent(t *testing.T) {
 	testcontainers.SkipIfProviderIsNotHealthy(t)
 	t.Parallel()
@@ -998,6 +1256,119 @@ func TestAgentMongoAdapter_ListAgentsBySelector(t *testing.T) {
 	})
 }
 
+func TestAgentMongoAdapter_ContinueTokenScope(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	database := client.Database("testdb_continue_token_scope")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+
+	for range 5 {
+		require.NoError(t, agentRepository.PutAgent(ctx, agentmodel.NewAgent(uuid.New())))
+	}
+
+	emptySelector := agentmodel.AgentSelector{IdentifyingAttributes: nil, NonIdentifyingAttributes: nil}
+
+	listPage, err := agentRepository.ListAgents(ctx, "default", &model.ListOptions{Limit: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, listPage.Continue)
+
+	selectorPage, err := agentRepository.ListAgentsBySelector(ctx, emptySelector, &model.ListOptions{Limit: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, selectorPage.Continue)
+
+	t.Run("token resumes the query that issued it", func(t *testing.T) {
+		t.Parallel()
+
+		seen := map[uuid.UUID]bool{}
+		for _, item := range listPage.Items {
+			seen[item.Metadata.InstanceUID] = true
+		}
+
+		continueToken := listPage.Continue
+		for continueToken != "" {
+			page, err := agentRepository.ListAgents(ctx, "default", &model.ListOptions{Limit: 2, Continue: continueToken})
+			require.NoError(t, err)
+
+			for _, item := range page.Items {
+				assert.False(t, seen[item.Metadata.InstanceUID], "agent returned twice")
+				seen[item.Metadata.InstanceUID] = true
+			}
+
+			continueToken = page.Continue
+		}
+
+		assert.Len(t, seen, 5)
+	})
+
+	t.Run("list token is rejected by a selector list", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgentsBySelector(ctx, emptySelector,
+			&model.ListOptions{Limit: 2, Continue: listPage.Continue})
+		require.ErrorIs(t, err, mongodb.ErrInvalidContinueToken)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("selector token is rejected by a plain list", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := agentRepository.ListAgents(ctx, "default",
+			&model.ListOptions{Limit: 2, Continue: selectorPage.Continue})
+		require.ErrorIs(t, err, mongodb.ErrInvalidContinueToken)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("token is rejected when the filter changes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := agentRepository.ListAgents(ctx, "default",
+			&model.ListOptions{Limit: 2, Continue: listPage.Continue, ConnectedOnly: true})
+		require.ErrorIs(t, err, mongodb.ErrInvalidContinueToken)
+
+		_, err = agentRepository.ListAgents(ctx, "other",
+			&model.ListOptions{Limit: 2, Continue: listPage.Continue})
+		require.ErrorIs(t, err, mongodb.ErrInvalidContinueToken)
+
+		selector := agentmodel.AgentSelector{
+			IdentifyingAttributes:    map[string]string{"service.name": "test-service"},
+			NonIdentifyingAttributes: nil,
+		}
+		_, err = agentRepository.ListAgentsBySelector(ctx, selector,
+			&model.ListOptions{Limit: 2, Continue: selectorPage.Continue})
+		require.ErrorIs(t, err, mongodb.ErrInvalidContinueToken)
+	})
+
+	t.Run("malformed token fails the same way on both lists", func(t *testing.T) {
+		t.Parallel()
+
+		_, listErr := agentRepository.ListAgents(ctx, "default",
+			&model.ListOptions{Limit: 2, Continue: "invalid-token"})
+		require.ErrorIs(t, listErr, mongodb.ErrInvalidContinueToken)
+
+		_, selectorErr := agentRepository.ListAgentsBySelector(ctx, emptySelector,
+			&model.ListOptions{Limit: 2, Continue: "invalid-token"})
+		require.ErrorIs(t, selectorErr, mongodb.ErrInvalidContinueToken)
+
+		assert.Equal(t, listErr.Error(), selectorErr.Error())
+	})
+}
+
 func TestAgentMongoAdapter_NewInstanceUID(t *testing.T) {
 	testcontainers.SkipIfProviderIsNotHealthy(t)
 	t.Parallel()
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+// ListAgentAttributes implements agentport.AgentPersistencePort.
+func (a *AgentRepository) ListAgentAttributes(
+	ctx context.Context,
+	valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	valueLimit = max(valueLimit, 0)
+
+	identifying, err := a.aggregateAttributeCatalog(ctx, entity.IdentifyingAttributesFieldName, valueLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identifying attributes: %w", err)
+	}
+
+	nonIdentifying, err := a.aggregateAttributeCatalog(ctx, entity.NonIdentifyingAttributesFieldName, valueLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list non-identifying attributes: %w", err)
+	}
+
+	return &agentmodel.AttributeCatalog{
+		IdentifyingAttributes:    identifying,
+		NonIdentifyingAttributes: nonIdentifying,
+	}, nil
+}
+
+// aggregateAttributeCatalog lists the distinct keys of the attribute array fieldName across
+// every agent, sorted, each with its smallest valueLimit distinct values.
+func (a *AgentRepository) aggregateAttributeCatalog(
+	ctx context.Context,
+	fieldName string,
+	valueLimit int,
+) ([]agentmodel.AttributeCatalogEntry, error) {
+	cursor, err := a.collection.Aggregate(ctx, attributeCatalogPipeline(fieldName, valueLimit),
+		options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate attributes: %w", err)
+	}
+
+	defer func() {
+		closeErr := cursor.Close(ctx)
+		if closeErr != nil {
+			a.logger.Warn("failed to close mongodb cursor", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var results []struct {
+		Key    string   `bson:"_id"`
+		Values []string `bson:"values"`
+	}
+
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attributes: %w", err)
+	}
+
+	entries := make([]agentmodel.AttributeCatalogEntry, 0, len(results))
+	for _, result := range results {
+		entries = append(entries, agentmodel.AttributeCatalogEntry{
+			Key:             result.Key,
+			Values:          result.Values[:min(len(result.Values), valueLimit)],
+			ValuesTruncated: len(result.Values) > valueLimit,
+		})
+	}
+
+	return entries, nil
+}
+
+// attributeCatalogPipeline builds the aggregation behind aggregateAttributeCatalog. Values
+// are de-duplicated and sorted before being collected per key, and one more than
+// valueLimit is kept so the caller can tell whether the list was truncated.
+func attributeCatalogPipeline(fieldName string, valueLimit int) mongo.Pipeline {
+	if valueLimit <= 0 {
+		return mongo.Pipeline{
+			{{Key: "$unwind", Value: "$" + fieldName}},
+			{{Key: "$group", Value: bson.M{"_id": "$" + fieldName + ".key"}}},
+			{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$unwind", Value: "$" + fieldName}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"key": "$" + fieldName + ".key", "value": "$" + fieldName + ".value"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.key", Value: 1}, {Key: "_id.value", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$_id.key",
+			"values": bson.M{"$push": "$_id.value"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"values": bson.M{"$slice": bson.A{"$values", valueLimit + 1}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
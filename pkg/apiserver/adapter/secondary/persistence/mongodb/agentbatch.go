@@ -0,0 +1,360 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+const (
+	// DefaultAgentWriteMaxBatchSize is the number of pending agent writes that triggers a
+	// flush before the flush interval elapses.
+	DefaultAgentWriteMaxBatchSize = 500
+
+	// agentBatchWriteTimeout bounds a single flush. Callers' own contexts are not used for
+	// the shared BulkWrite, since one cancelled caller must not fail the whole batch.
+	agentBatchWriteTimeout = 10 * time.Second
+)
+
+var _ agentport.AgentPersistencePort = (*BatchingAgentRepository)(nil)
+
+// agentBulkWriter is the part of *mongo.Collection the batching repository writes through.
+type agentBulkWriter interface {
+	BulkWrite(ctx context.Context, models []mongo.WriteModel,
+		opts ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error)
+}
+
+// BatchingAgentRepository is an AgentRepository whose PutAgent calls are buffered and
+// written together with a single unordered BulkWrite every flush interval, or as soon as
+// maxBatchSize writes are pending. Reads and deletes go straight to the AgentRepository.
+//
+// PutAgent keeps its contract: it blocks until its write is flushed and reports a
+// model.ErrConflict exactly like AgentRepository.PutAgent. Writes to the same agent are
+// applied in the order they were made, each in its own BulkWrite. Start must be called
+// before writes are buffered; until then, and once Stop has flushed the buffer, PutAgent
+// writes directly.
+type BatchingAgentRepository struct {
+	*AgentRepository
+
+	writer        agentBulkWriter
+	flushInterval time.Duration
+	maxBatchSize  int
+	// currentVersions returns the stored resource version of each agent. It settles which
+	// writes of a round matched when the BulkWrite counts alone cannot tell.
+	currentVersions func(ctx context.Context, instanceUIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+
+	mu      sync.Mutex
+	running bool
+	pending []*pendingAgentWrite
+	full    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// pendingAgentWrite is a buffered PutAgent waiting for its flush.
+type pendingAgentWrite struct {
+	instanceUID uuid.UUID
+	expected    int64
+	doc         *entity.Agent
+	result      chan error
+}
+
+// NewBatchingAgentRepository wraps repository so that its agent writes are batched every
+// flushInterval. A non-positive maxBatchSize uses DefaultAgentWriteMaxBatchSize.
+func NewBatchingAgentRepository(
+	repository *AgentRepository,
+	flushInterval time.Duration,
+	maxBatchSize int,
+) *BatchingAgentRepository {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultAgentWriteMaxBatchSize
+	}
+
+	return &BatchingAgentRepository{
+		AgentRepository: repository,
+		writer:          repository.collection,
+		flushInterval:   flushInterval,
+		maxBatchSize:    maxBatchSize,
+		currentVersions: repository.resourceVersions,
+		mu:              sync.Mutex{},
+		running:         false,
+		pending:         nil,
+		full:            make(chan struct{}, 1),
+		stop:            make(chan struct{}),
+		stopped:         make(chan struct{}),
+	}
+}
+
+// Start starts the flush loop. From now on PutAgent buffers its writes.
+func (r *BatchingAgentRepository) Start() {
+	r.mu.Lock()
+	r.running = true
+	r.mu.Unlock()
+
+	go r.flushLoop()
+}
+
+// Stop stops the flush loop and flushes the writes still buffered, so none is lost on
+// shutdown. PutAgent calls made afterwards write directly.
+func (r *BatchingAgentRepository) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	wasRunning := r.running
+	r.running = false
+	r.mu.Unlock()
+
+	if !wasRunning {
+		return nil
+	}
+
+	close(r.stop)
+
+	select {
+	case <-r.stopped:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop the agent write flush loop: %w", ctx.Err())
+	}
+
+	r.flush(ctx)
+
+	return nil
+}
+
+// PutAgent implements agentport.AgentPersistencePort. It buffers the write and waits until
+// it is flushed. When ctx ends first, the write may still be applied by the flush.
+func (r *BatchingAgentRepository) PutAgent(ctx context.Context, agent *agentmodel.Agent) error {
+	expected := agent.Metadata.ResourceVersion
+
+	doc := entity.AgentFromDomain(agent)
+	doc.Metadata.ResourceVersion = expected + 1
+
+	write := &pendingAgentWrite{
+		instanceUID: agent.Metadata.InstanceUID,
+		expected:    expected,
+		doc:         doc,
+		result:      make(chan error, 1),
+	}
+
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+
+		return r.AgentRepository.PutAgent(ctx, agent)
+	}
+
+	r.pending = append(r.pending, write)
+	if len(r.pending) >= r.maxBatchSize {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+	r.mu.Unlock()
+
+	select {
+	case err := <-write.result:
+		if err != nil {
+			return err
+		}
+
+		agent.Metadata.ResourceVersion = expected + 1
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to put agent to persistence: %w", ctx.Err())
+	}
+}
+
+func (r *BatchingAgentRepository) flushLoop() {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		case <-r.full:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), agentBatchWriteTimeout)
+		r.flush(ctx)
+		cancel()
+	}
+}
+
+// flush writes every pending write. Writes are split into rounds holding at most one write
+// per agent, so a later write to an agent is only attempted after the earlier one settled.
+func (r *BatchingAgentRepository) flush(ctx context.Context) {
+	r.mu.Lock()
+	writes := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	for _, round := range splitAgentWriteRounds(writes) {
+		r.writeRound(ctx, round)
+	}
+}
+
+// splitAgentWriteRounds keeps the order of writes: the n-th write to an agent goes to the
+// n-th round.
+func splitAgentWriteRounds(writes []*pendingAgentWrite) [][]*pendingAgentWrite {
+	var rounds [][]*pendingAgentWrite
+
+	seen := make(map[uuid.UUID]int, len(writes))
+
+	for _, write := range writes {
+		index := seen[write.instanceUID]
+		seen[write.instanceUID] = index + 1
+
+		if index == len(rounds) {
+			rounds = append(rounds, nil)
+		}
+
+		rounds[index] = append(rounds[index], write)
+	}
+
+	return rounds
+}
+
+// writeRound writes a round with one BulkWrite and delivers each write's result. The
+// filters are the compare-and-swap ones of AgentRepository.PutAgent.
+func (r *BatchingAgentRepository) writeRound(ctx context.Context, round []*pendingAgentWrite) {
+	models := make([]mongo.WriteModel, 0, len(round))
+
+	for _, write := range round {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{
+				entity.AgentKeyFieldName: r.common.KeyQueryFunc(write.instanceUID),
+				resourceVersionFieldName: write.expected,
+			}).
+			SetReplacement(write.doc).
+			SetUpsert(write.expected == 0))
+	}
+
+	result, err := r.writer.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	errs := make([]error, len(round))
+	settled := make([]bool, len(round))
+
+	var bulkErr mongo.BulkWriteException
+
+	switch {
+	case err == nil:
+	case errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil:
+		for _, writeErr := range bulkErr.WriteErrors {
+			errs[writeErr.Index] = agentWriteError(round[writeErr.Index], writeErr.WriteError)
+			settled[writeErr.Index] = true
+		}
+	default:
+		for _, write := range round {
+			write.result <- fmt.Errorf("failed to put agent to persistence: %w", err)
+		}
+
+		return
+	}
+
+	for index := range result.UpsertedIDs {
+		settled[index] = true
+	}
+
+	r.settleReplacements(ctx, round, result.MatchedCount, settled, errs)
+
+	for index, write := range round {
+		write.result <- errs[index]
+	}
+}
+
+// settleReplacements decides which of the unsettled writes matched their expected version.
+// When every one of them matched the counts say so; otherwise the stored versions are read
+// back, and a write whose version is not the one it wrote is reported as a conflict.
+func (r *BatchingAgentRepository) settleReplacements(
+	ctx context.Context,
+	round []*pendingAgentWrite,
+	matchedCount int64,
+	settled []bool,
+	errs []error,
+) {
+	var unsettled []int
+
+	for index := range round {
+		if !settled[index] {
+			unsettled = append(unsettled, index)
+		}
+	}
+
+	if int64(len(unsettled)) == matchedCount {
+		return
+	}
+
+	instanceUIDs := make([]uuid.UUID, 0, len(unsettled))
+	for _, index := range unsettled {
+		instanceUIDs = append(instanceUIDs, round[index].instanceUID)
+	}
+
+	versions, err := r.currentVersions(ctx, instanceUIDs)
+
+	for _, index := range unsettled {
+		write := round[index]
+
+		switch {
+		case err != nil:
+			errs[index] = fmt.Errorf("failed to confirm agent write: %w", err)
+		case versions[write.instanceUID] != write.expected+1:
+			errs[index] = fmt.Errorf("%w: agent %s was modified concurrently", model.ErrConflict, write.instanceUID)
+		}
+	}
+}
+
+func agentWriteError(write *pendingAgentWrite, writeErr mongo.WriteError) error {
+	if mongo.IsDuplicateKeyError(writeErr) {
+		return fmt.Errorf("%w: agent %s was created concurrently", model.ErrConflict, write.instanceUID)
+	}
+
+	return fmt.Errorf("failed to put agent to persistence: %w", writeErr)
+}
+
+// resourceVersions returns the stored resource version of each of the given agents.
+func (a *AgentRepository) resourceVersions(
+	ctx context.Context,
+	instanceUIDs []uuid.UUID,
+) (map[uuid.UUID]int64, error) {
+	keys := make([]any, 0, len(instanceUIDs))
+	for _, instanceUID := range instanceUIDs {
+		keys = append(keys, a.common.KeyQueryFunc(instanceUID))
+	}
+
+	cursor, err := a.collection.Find(ctx,
+		bson.M{entity.AgentKeyFieldName: bson.M{"$in": keys}},
+		options.Find().SetProjection(bson.M{entity.AgentKeyFieldName: 1, resourceVersionFieldName: 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent resource versions: %w", err)
+	}
+
+	var docs []entity.Agent
+
+	err = cursor.All(ctx, &docs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode agent resource versions: %w", err)
+	}
+
+	versions := make(map[uuid.UUID]int64, len(docs))
+	for _, doc := range docs {
+		versions[uuid.UUID(doc.Metadata.InstanceUID.Data)] = doc.Metadata.ResourceVersion
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,223 @@
+package mongodb
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// fakeAgentBulkWriter records the BulkWrite calls. Every upsert is reported as inserted and
+// every replacement as matched, unless matchedCount overrides the matched count.
+type fakeAgentBulkWriter struct {
+	mu           sync.Mutex
+	batchSizes   []int
+	matchedCount *int64
+}
+
+func (f *fakeAgentBulkWriter) BulkWrite(
+	_ context.Context,
+	models []mongo.WriteModel,
+	_ ...options.Lister[options.BulkWriteOptions],
+) (*mongo.BulkWriteResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batchSizes = append(f.batchSizes, len(models))
+
+	//exhaustruct:ignore
+	result := &mongo.BulkWriteResult{UpsertedIDs: map[int64]any{}}
+
+	for index, writeModel := range models {
+		replace, ok := writeModel.(*mongo.ReplaceOneModel)
+		if ok && replace.Upsert != nil && *replace.Upsert {
+			result.UpsertedIDs[int64(index)] = bson.NewObjectID()
+			result.UpsertedCount++
+
+			continue
+		}
+
+		result.MatchedCount++
+	}
+
+	if f.matchedCount != nil {
+		result.MatchedCount = *f.matchedCount
+	}
+
+	return result, nil
+}
+
+func (f *fakeAgentBulkWriter) calls() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]int(nil), f.batchSizes...)
+}
+
+func newTestBatchingAgentRepository(
+	flushInterval time.Duration,
+	maxBatchSize int,
+) (*BatchingAgentRepository, *fakeAgentBulkWriter) {
+	//exhaustruct:ignore
+	repo := &AgentRepository{logger: slog.Default()}
+	//exhaustruct:ignore
+	repo.common = commonEntityAdapter[entity.Agent, uuid.UUID]{
+		KeyQueryFunc: func(key uuid.UUID) any {
+			return bson.Binary{Subtype: bson.TypeBinaryUUID, Data: key[:]}
+		},
+	}
+
+	writer := &fakeAgentBulkWriter{}
+	batching := NewBatchingAgentRepository(repo, flushInterval, maxBatchSize)
+	batching.writer = writer
+
+	return batching, writer
+}
+
+func TestBatchingAgentRepository_RapidUpdatesAreBulkWritten(t *testing.T) {
+	t.Parallel()
+
+	const agentCount = 200
+
+	repo, writer := newTestBatchingAgentRepository(50*time.Millisecond, 0)
+	repo.Start()
+
+	agents := make([]*agentmodel.Agent, agentCount)
+
+	var wg sync.WaitGroup
+
+	for index := range agents {
+		agents[index] = agentmodel.NewAgent(uuid.New())
+
+		wg.Go(func() {
+			assert.NoError(t, repo.PutAgent(t.Context(), agents[index]))
+		})
+	}
+
+	wg.Wait()
+	require.NoError(t, repo.Stop(t.Context()))
+
+	calls := writer.calls()
+	total := 0
+
+	for _, size := range calls {
+		total += size
+	}
+
+	assert.Equal(t, agentCount, total)
+	assert.Less(t, len(calls), agentCount/10, "updates should be coalesced into a few bulk writes")
+
+	for _, agent := range agents {
+		assert.Equal(t, int64(1), agent.Metadata.ResourceVersion)
+	}
+}
+
+func TestBatchingAgentRepository_MaxBatchSizeFlushesEarly(t *testing.T) {
+	t.Parallel()
+
+	repo, writer := newTestBatchingAgentRepository(time.Hour, 3)
+	repo.Start()
+
+	t.Cleanup(func() { _ = repo.Stop(context.Background()) })
+
+	var wg sync.WaitGroup
+
+	for range 3 {
+		wg.Go(func() {
+			assert.NoError(t, repo.PutAgent(t.Context(), agentmodel.NewAgent(uuid.New())))
+		})
+	}
+
+	wg.Wait()
+	assert.Equal(t, []int{3}, writer.calls())
+}
+
+func TestBatchingAgentRepository_StopFlushesPendingWrites(t *testing.T) {
+	t.Parallel()
+
+	repo, writer := newTestBatchingAgentRepository(time.Hour, 0)
+	repo.Start()
+
+	agent := agentmodel.NewAgent(uuid.New())
+	done := make(chan error, 1)
+
+	go func() { done <- repo.PutAgent(t.Context(), agent) }()
+
+	require.Eventually(t, func() bool {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+
+		return len(repo.pending) == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, repo.Stop(t.Context()))
+	require.NoError(t, <-done)
+	assert.Equal(t, []int{1}, writer.calls())
+	assert.Equal(t, int64(1), agent.Metadata.ResourceVersion)
+}
+
+func TestBatchingAgentRepository_UnmatchedReplacementConflicts(t *testing.T) {
+	t.Parallel()
+
+	repo, writer := newTestBatchingAgentRepository(time.Hour, 0)
+	writer.matchedCount = new(int64)
+
+	fresh := agentmodel.NewAgent(uuid.New())
+	fresh.Metadata.ResourceVersion = 1
+	stale := agentmodel.NewAgent(uuid.New())
+	stale.Metadata.ResourceVersion = 1
+
+	// fresh was written by the round, stale was changed by someone else in the meantime.
+	repo.currentVersions = func(context.Context, []uuid.UUID) (map[uuid.UUID]int64, error) {
+		return map[uuid.UUID]int64{
+			fresh.Metadata.InstanceUID: 2,
+			stale.Metadata.InstanceUID: 5,
+		}, nil
+	}
+
+	round := []*pendingAgentWrite{newPendingAgentWrite(fresh), newPendingAgentWrite(stale)}
+	repo.writeRound(t.Context(), round)
+
+	require.NoError(t, <-round[0].result)
+	require.ErrorIs(t, <-round[1].result, model.ErrConflict)
+}
+
+func TestSplitAgentWriteRounds_KeepsPerAgentOrder(t *testing.T) {
+	t.Parallel()
+
+	first, second := uuid.New(), uuid.New()
+	writes := []*pendingAgentWrite{
+		{instanceUID: first, expected: 1},
+		{instanceUID: second, expected: 7},
+		{instanceUID: first, expected: 2},
+		{instanceUID: first, expected: 3},
+	}
+
+	rounds := splitAgentWriteRounds(writes)
+
+	require.Len(t, rounds, 3)
+	assert.Equal(t, []*pendingAgentWrite{writes[0], writes[1]}, rounds[0])
+	assert.Equal(t, []*pendingAgentWrite{writes[2]}, rounds[1])
+	assert.Equal(t, []*pendingAgentWrite{writes[3]}, rounds[2])
+}
+
+func newPendingAgentWrite(agent *agentmodel.Agent) *pendingAgentWrite {
+	return &pendingAgentWrite{
+		instanceUID: agent.Metadata.InstanceUID,
+		expected:    agent.Metadata.ResourceVersion,
+		doc:         entity.AgentFromDomain(agent),
+		result:      make(chan error, 1),
+	}
+}
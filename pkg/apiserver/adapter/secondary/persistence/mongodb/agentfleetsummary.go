@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+// GetAgentFleetSummary implements agentport.AgentPersistencePort.
+//
+// The counts are computed by a single $group over the agents collection, so no agent is
+// loaded. Like the agent group statistics, healthy and unhealthy counts only consider
+// connected agents.
+func (a *AgentRepository) GetAgentFleetSummary(ctx context.Context) (*agentmodel.AgentFleetSummary, error) {
+	cursor, err := a.collection.Aggregate(ctx, agentFleetSummaryPipeline())
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate agent fleet summary: %w", err)
+	}
+
+	defer func() {
+		closeErr := cursor.Close(ctx)
+		if closeErr != nil {
+			a.logger.Warn("failed to close mongodb cursor", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var result struct {
+		NumAgents                    int64 `bson:"numAgents"`
+		NumConnectedAgents           int64 `bson:"numConnectedAgents"`
+		NumHealthyAgents             int64 `bson:"numHealthyAgents"`
+		NumUnhealthyAgents           int64 `bson:"numUnhealthyAgents"`
+		NumAgentsWithPendingCommands int64 `bson:"numAgentsWithPendingCommands"`
+	}
+
+	// An empty collection yields no group document, which leaves every count at zero.
+	if cursor.Next(ctx) {
+		err := cursor.Decode(&result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode agent fleet summary: %w", err)
+		}
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent fleet summary: %w", err)
+	}
+
+	return &agentmodel.AgentFleetSummary{
+		NumAgents:                    result.NumAgents,
+		NumConnectedAgents:           result.NumConnectedAgents,
+		NumHealthyAgents:             result.NumHealthyAgents,
+		NumUnhealthyAgents:           result.NumUnhealthyAgents,
+		NumAgentsWithPendingCommands: result.NumAgentsWithPendingCommands,
+	}, nil
+}
+
+// agentFleetSummaryPipeline builds the aggregation behind GetAgentFleetSummary.
+// NOTE: Do NOT query status.conditions field - it can be null and causes MongoDB aggregation errors.
+func agentFleetSummaryPipeline() []bson.M {
+	countIf := func(condition any) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": []any{condition, 1, 0}}}
+	}
+
+	return []bson.M{
+		{
+			"$group": bson.M{
+				"_id":                nil,
+				"numAgents":          bson.M{"$sum": 1},
+				"numConnectedAgents": countIf(connectedAggExpr()),
+				"numHealthyAgents": countIf(bson.M{"$and": []any{
+					connectedAggExpr(),
+					bson.M{"$eq": []any{"$status.componentHealth.healthy", true}},
+				}}),
+				"numUnhealthyAgents": countIf(bson.M{"$and": []any{
+					connectedAggExpr(),
+					bson.M{"$ne": []any{"$status.componentHealth.healthy", true}},
+				}}),
+				"numAgentsWithPendingCommands": countIf(pendingCommandsAggExpr()),
+			},
+		},
+	}
+}
+
+// GetCommandQueueSummary implements agentport.AgentPersistencePort.
+//
+// The totals are computed by a single $group over the agents collection, so no agent is
+// loaded.
+func (a *AgentRepository) GetCommandQueueSummary(
+	ctx context.Context,
+	maxPending int,
+) (*agentmodel.CommandQueueSummary, error) {
+	cursor, err := a.collection.Aggregate(ctx, commandQueueSummaryPipeline(maxPending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate command queue summary: %w", err)
+	}
+
+	defer func() {
+		closeErr := cursor.Close(ctx)
+		if closeErr != nil {
+			a.logger.Warn("failed to close mongodb cursor", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var result struct {
+		NumPendingCommands int64 `bson:"numPendingCommands"`
+		NumFullQueues      int64 `bson:"numFullQueues"`
+	}
+
+	// An empty collection yields no group document, which leaves both totals at zero.
+	if cursor.Next(ctx) {
+		err := cursor.Decode(&result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode command queue summary: %w", err)
+		}
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command queue summary: %w", err)
+	}
+
+	return &agentmodel.CommandQueueSummary{
+		NumPendingCommands: result.NumPendingCommands,
+		NumFullQueues:      result.NumFullQueues,
+	}, nil
+}
+
+// commandQueueSummaryPipeline builds the aggregation behind GetCommandQueueSummary.
+func commandQueueSummaryPipeline(maxPending int) []bson.M {
+	// An unbounded queue is never full.
+	var full any = false
+	if maxPending > 0 {
+		full = bson.M{"$gte": []any{pendingCommandCountAggExpr(), maxPending}}
+	}
+
+	return []bson.M{
+		{
+			"$group": bson.M{
+				"_id":                nil,
+				"numPendingCommands": bson.M{"$sum": pendingCommandCountAggExpr()},
+				"numFullQueues":      bson.M{"$sum": bson.M{"$cond": []any{full, 1, 0}}},
+			},
+		},
+	}
+}
+
+// pendingCommandsAggExpr mirrors agentmodel.Agent.PendingCommandCount being above zero.
+func pendingCommandsAggExpr() bson.M {
+	return bson.M{"$gt": []any{pendingCommandCountAggExpr(), 0}}
+}
+
+// pendingCommandCountAggExpr mirrors agentmodel.Agent.PendingCommandCount: one per
+// outstanding report request, plus one when a restart was required after the agent last
+// started. A restart that was never required is stored as the zero time, which is before
+// any start time.
+func pendingCommandCountAggExpr() bson.M {
+	return bson.M{"$add": []any{
+		bson.M{"$size": bson.M{"$ifNull": []any{"$spec.pendingReports", bson.A{}}}},
+		bson.M{"$cond": []any{
+			bson.M{"$gt": []any{
+				bson.M{"$toLong": "$spec.requiredRestartedAt"},
+				bson.M{"$ifNull": []any{"$status.componentHealth.startTimeUnixMilli", 0}},
+			}},
+			1,
+			0,
+		}},
+	}}
+}
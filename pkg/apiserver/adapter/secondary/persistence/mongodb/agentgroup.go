@@ -2,10 +2,13 @@ package mongodb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/jellydator/ttlcache/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -23,14 +26,32 @@ const (
 	agentGroupNamespaceFieldName = "metadata.namespace"
 	agentGroupNameFieldName      = "metadata.name"
 	agentGroupDeletedAtFieldName = "metadata.deletedAt"
+
+	// agentGroupStatisticsTTL bounds how stale an AgentGroup's agent counts may be.
+	// Without it, listing groups runs one aggregation over the agents per group on
+	// every request.
+	agentGroupStatisticsTTL = 5 * time.Second
+	// agentGroupStatisticsCapacity is the number of distinct selectors whose counts are kept.
+	agentGroupStatisticsCapacity = 1000
 )
 
+// agentGroupNameCollation matches agent group names case-insensitively, for rejecting a new
+// group whose name differs from an existing one only by case. The matching index in
+// mongodb.go declares the same collation so the lookup stays indexed.
+//
+//nolint:gochecknoglobals,exhaustruct // shared, immutable collation; only Locale/Strength apply.
+var agentGroupNameCollation = &options.Collation{Locale: "en", Strength: collationStrengthCaseInsensitive}
+
 // AgentGroupMongoAdapter is a struct that implements the AgentGroupPersistencePort interface.
 type AgentGroupMongoAdapter struct {
 	collection      *mongo.Collection
 	agentCollection *mongo.Collection
 	common          commonEntityAdapter[entity.AgentGroup, string]
 	logger          *slog.Logger
+
+	// statisticsCache holds recent agent counts keyed by selector, so groups sharing a
+	// selector, and repeated reads of one group, reuse a single aggregation.
+	statisticsCache *ttlcache.Cache[string, *entity.AgentGroupStatistics]
 }
 
 // NewAgentGroupRepository creates a new instance of AgentGroupMongoAdapter.
@@ -51,6 +72,11 @@ func NewAgentGroupRepository(
 		collection:      collection,
 		agentCollection: agentCollection,
 		logger:          logger,
+		statisticsCache: ttlcache.New(
+			ttlcache.WithTTL[string, *entity.AgentGroupStatistics](agentGroupStatisticsTTL),
+			ttlcache.WithCapacity[string, *entity.AgentGroupStatistics](agentGroupStatisticsCapacity),
+			ttlcache.WithDisableTouchOnHit[string, *entity.AgentGroupStatistics](),
+		),
 		common: newCommonAdapter(
 			logger,
 			collection,
@@ -90,7 +116,7 @@ func (a *AgentGroupMongoAdapter) GetAgentGroup(
 		return nil, fmt.Errorf("decode agent group: %w", err)
 	}
 
-	agentGroupStatistics, err := a.getAgentGroupStatistics(ctx, &agentGroupEntity)
+	agentGroupStatistics, err := a.cachedAgentGroupStatistics(ctx, &agentGroupEntity)
 	if err != nil {
 		return nil, fmt.Errorf("get agent group statistics: %w", err)
 	}
@@ -110,7 +136,7 @@ func (a *AgentGroupMongoAdapter) ListAgentGroups(
 	// Convert entities to domain models with statistics
 	items := make([]*agentmodel.AgentGroup, 0, len(resp.Items))
 	for _, item := range resp.Items {
-		agentGroupStatistics, err := a.getAgentGroupStatistics(ctx, item)
+		agentGroupStatistics, err := a.cachedAgentGroupStatistics(ctx, item)
 		if err != nil {
 			return nil, fmt.Errorf("get agent group statistics for %s: %w", item.Metadata.Name, err)
 		}
@@ -127,6 +153,41 @@ func (a *AgentGroupMongoAdapter) ListAgentGroups(
 	}, nil
 }
 
+// ListAgentGroupNamesIgnoringCase implements agentport.AgentGroupPersistencePort.
+func (a *AgentGroupMongoAdapter) ListAgentGroupNamesIgnoringCase(
+	ctx context.Context, namespace string, name string,
+) ([]string, error) {
+	findOptions := options.Find().
+		SetCollation(agentGroupNameCollation).
+		SetProjection(bson.M{agentGroupNameFieldName: 1})
+
+	cursor, err := a.collection.Find(ctx, a.filterByNamespaceAndNameExcludingDeleted(namespace, name), findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list agent group names ignoring case: %w", err)
+	}
+
+	defer func() {
+		closeErr := cursor.Close(ctx)
+		if closeErr != nil {
+			a.logger.Warn("failed to close mongodb cursor", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var entities []*entity.AgentGroup
+
+	err = cursor.All(ctx, &entities)
+	if err != nil {
+		return nil, fmt.Errorf("decode agent group names: %w", err)
+	}
+
+	names := make([]string, 0, len(entities))
+	for _, en := range entities {
+		names = append(names, en.Metadata.Name)
+	}
+
+	return names, nil
+}
+
 // PutAgentGroup implements agentport.AgentGroupPersistencePort.
 //
 //nolint:godox // Reason: TODO comment.
@@ -173,6 +234,34 @@ func (a *AgentGroupMongoAdapter) filterByNamespaceAndNameExcludingDeleted(namesp
 	return filter
 }
 
+// cachedAgentGroupStatistics returns the group's agent counts, aggregating them only when
+// no counts for the same selector were computed within agentGroupStatisticsTTL.
+func (a *AgentGroupMongoAdapter) cachedAgentGroupStatistics(
+	ctx context.Context,
+	agentGroupEntity *entity.AgentGroup,
+) (*entity.AgentGroupStatistics, error) {
+	key, err := json.Marshal(agentGroupEntity.Spec.Selector)
+	if err != nil {
+		return a.getAgentGroupStatistics(ctx, agentGroupEntity)
+	}
+
+	if item := a.statisticsCache.Get(string(key)); item != nil {
+		statistics := *item.Value()
+
+		return &statistics, nil
+	}
+
+	statistics, err := a.getAgentGroupStatistics(ctx, agentGroupEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := *statistics
+	a.statisticsCache.Set(string(key), &cached, ttlcache.DefaultTTL)
+
+	return statistics, nil
+}
+
 //nolint:funlen // Reason: mongodb aggregation pipeline is long.
 func (a *AgentGroupMongoAdapter) getAgentGroupStatistics(
 	ctx context.Context,
@@ -92,6 +92,63 @@ func TestAgentGroupMongoAdapter_Statistics_ConnectedIsStalenessAware(t *testing.
 	assert.Equal(t, 2, loaded.Status.NumNotConnectedAgents)
 }
 
+func TestAgentGroupMongoAdapter_Statistics_CountsPerSelector(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+
+	ctx := t.Context()
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	database := client.Database("testdb_agentgroup_selector_stats")
+	agentRepository := mongodb.NewAgentRepository(database, base.Logger)
+	agentGroupAdapter := mongodb.NewAgentGroupRepository(database, base.Logger)
+
+	for _, env := range []string{"prod", "prod", "dev"} {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Metadata.Description.IdentifyingAttributes = map[string]string{"env": env}
+		require.NoError(t, agentRepository.PutAgent(ctx, agent))
+	}
+
+	// Groups are listed and fetched repeatedly; counts are cached per selector, so
+	// groups with different selectors must never share an entry.
+	for _, env := range []string{"prod", "dev"} {
+		group := agentmodel.NewAgentGroup("default", env, agentmodel.OfAttributes(nil), time.Now(), "tester")
+		group.Spec.Selector.IdentifyingAttributes = map[string]string{"env": env}
+		_, err = agentGroupAdapter.PutAgentGroup(ctx, group.Metadata.Namespace, group.Metadata.Name, group)
+		require.NoError(t, err)
+	}
+
+	list, err := agentGroupAdapter.ListAgentGroups(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+
+	counts := make(map[string]int, len(list.Items))
+	for _, item := range list.Items {
+		counts[item.Metadata.Name] = item.Status.NumAgents
+	}
+
+	assert.Equal(t, map[string]int{"prod": 2, "dev": 1}, counts)
+
+	prod, err := agentGroupAdapter.GetAgentGroup(ctx, "default", "prod", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, prod.Status.NumAgents)
+
+	dev, err := agentGroupAdapter.GetAgentGroup(ctx, "default", "dev", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dev.Status.NumAgents)
+}
+
 func TestAgentGroupMongoAdapter_GetAgentGroup(t *testing.T) {
 	testcontainers.SkipIfProviderIsNotHealthy(t)
 	t.Parallel()
@@ -418,6 +475,40 @@ func TestAgentGroupMongoAdapter_DeleteAgentGroup(t *testing.T) {
 	})
 }
 
+func TestAgentGroupMongoAdapter_ListAgentGroupNamesIgnoringCase(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+
+	ctx := t.Context()
+	client, adapter := setupAgentGroupMongoAdapter(t)
+	t.Cleanup(func() {
+		err := client.Disconnect(ctx)
+		require.NoError(t, err)
+	})
+
+	// given
+	for _, agentGroup := range []*agentmodel.AgentGroup{
+		agentmodel.NewAgentGroup("default", "Prod", nil, time.Now(), "tester"),
+		agentmodel.NewAgentGroup("default", "staging", nil, time.Now(), "tester"),
+		agentmodel.NewAgentGroup("other", "prod", nil, time.Now(), "tester"),
+	} {
+		_, err := adapter.PutAgentGroup(ctx, agentGroup.Metadata.Namespace, agentGroup.Metadata.Name, agentGroup)
+		require.NoError(t, err)
+	}
+
+	deleted := agentmodel.NewAgentGroup("default", "PROD", nil, time.Now(), "tester")
+	deleted.MarkDeleted(time.Now(), "tester")
+	_, err := adapter.PutAgentGroup(ctx, "default", "PROD", deleted)
+	require.NoError(t, err)
+
+	// when
+	names, err := adapter.ListAgentGroupNamesIgnoringCase(ctx, "default", "prod")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Prod"}, names)
+}
+
 func TestAgentGroupMongoAdapter_AttributesShouldBeSameAfterSaveAndLoad(t *testing.T) {
 	testcontainers.SkipIfProviderIsNotHealthy(t)
 	t.Parallel()
@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ agentport.AgentRevocationPersistencePort = (*AgentRevocationRepository)(nil)
+
+const (
+	agentRevocationCollectionName = "agentrevocations"
+)
+
+// AgentRevocationRepository implements the AgentRevocationPersistencePort interface.
+// Revocations are hard-deleted: removing one is what lets the agent back in.
+type AgentRevocationRepository struct {
+	common commonEntityAdapter[entity.AgentRevocation, uuid.UUID]
+}
+
+// NewAgentRevocationRepository creates a new instance of AgentRevocationRepository.
+func NewAgentRevocationRepository(
+	mongoDatabase *mongo.Database,
+	logger *slog.Logger,
+) *AgentRevocationRepository {
+	collection := mongoDatabase.Collection(agentRevocationCollectionName)
+	keyFunc := func(revocationEntity *entity.AgentRevocation) uuid.UUID {
+		return uuid.UUID(revocationEntity.InstanceUID.Data)
+	}
+	keyQueryFunc := func(key uuid.UUID) any {
+		return bson.Binary{
+			Subtype: bson.TypeBinaryUUID,
+			Data:    key[:],
+		}
+	}
+
+	return &AgentRevocationRepository{
+		common: newCommonAdapter(
+			logger,
+			collection,
+			entity.AgentRevocationKeyFieldName,
+			keyFunc,
+			keyQueryFunc,
+		),
+	}
+}
+
+// GetAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) GetAgentRevocation(
+	ctx context.Context, instanceUID uuid.UUID,
+) (*agentmodel.AgentRevocation, error) {
+	revocationEntity, err := r.common.get(ctx, instanceUID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent revocation: %w", err)
+	}
+
+	return revocationEntity.ToDomain(), nil
+}
+
+// PutAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) PutAgentRevocation(
+	ctx context.Context, revocation *agentmodel.AgentRevocation,
+) error {
+	err := r.common.put(ctx, entity.AgentRevocationFromDomain(revocation))
+	if err != nil {
+		return fmt.Errorf("put agent revocation: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAgentRevocation implements agentport.AgentRevocationPersistencePort.
+func (r *AgentRevocationRepository) DeleteAgentRevocation(ctx context.Context, instanceUID uuid.UUID) error {
+	err := r.common.deleteOne(ctx, instanceUID)
+	if err != nil {
+		return fmt.Errorf("delete agent revocation: %w", err)
+	}
+
+	return nil
+}
@@ -28,14 +28,18 @@ const (
 
 // CertificateMongoAdapter is a struct that implements the CertificatePersistencePort interface.
 type CertificateMongoAdapter struct {
-	collection *mongo.Collection
-	common     commonEntityAdapter[entity.Certificate, string]
-	logger     *slog.Logger
+	collection  *mongo.Collection
+	common      commonEntityAdapter[entity.Certificate, string]
+	fieldCipher *FieldCipher
+	logger      *slog.Logger
 }
 
-// NewCertificateRepository creates a new instance of CertificateMongoAdapter.
+// NewCertificateRepository creates a new instance of CertificateMongoAdapter. Private keys
+// are encrypted with fieldCipher before they are stored; a nil fieldCipher stores them as
+// they are.
 func NewCertificateRepository(
 	mongoDatabase *mongo.Database,
+	fieldCipher *FieldCipher,
 	logger *slog.Logger,
 ) *CertificateMongoAdapter {
 	collection := mongoDatabase.Collection(certificateCollectionName)
@@ -47,8 +51,9 @@ func NewCertificateRepository(
 	}
 
 	return &CertificateMongoAdapter{
-		collection: collection,
-		logger:     logger,
+		collection:  collection,
+		fieldCipher: fieldCipher,
+		logger:      logger,
 		common: newCommonAdapter(
 			logger,
 			collection,
@@ -88,7 +93,7 @@ func (c *CertificateMongoAdapter) GetCertificate(
 		return nil, fmt.Errorf("decode certificate: %w", err)
 	}
 
-	return certificateEntity.ToDomain(), nil
+	return c.toDomain(&certificateEntity)
 }
 
 // ListCertificate implements agentport.CertificatePersistencePort.
@@ -102,7 +107,12 @@ func (c *CertificateMongoAdapter) ListCertificate(
 
 	items := make([]*agentmodel.Certificate, 0, len(resp.Items))
 	for _, item := range resp.Items {
-		items = append(items, item.ToDomain())
+		certificate, err := c.toDomain(item)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, certificate)
 	}
 
 	return &model.ListResponse[*agentmodel.Certificate]{
@@ -120,7 +130,14 @@ func (c *CertificateMongoAdapter) PutCertificate(
 	namespace := certificate.Metadata.Namespace
 	name := certificate.Metadata.Name
 
-	_, err := c.collection.ReplaceOne(ctx,
+	encryptedPrivateKey, err := c.fieldCipher.Encrypt(certificateEntity.Spec.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt certificate private key: %w", err)
+	}
+
+	certificateEntity.Spec.PrivateKey = encryptedPrivateKey
+
+	_, err = c.collection.ReplaceOne(ctx,
 		c.filterByNamespaceAndName(namespace, name),
 		certificateEntity,
 		options.Replace().SetUpsert(true),
@@ -134,6 +151,20 @@ func (c *CertificateMongoAdapter) PutCertificate(
 	return certificate, nil
 }
 
+// toDomain converts a stored certificate to the domain model, decrypting its private key.
+func (c *CertificateMongoAdapter) toDomain(certificateEntity *entity.Certificate) (*agentmodel.Certificate, error) {
+	privateKey, err := c.fieldCipher.Decrypt(certificateEntity.Spec.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt private key of certificate %s/%s: %w",
+			certificateEntity.Metadata.Namespace, certificateEntity.Metadata.Name, err)
+	}
+
+	certificate := certificateEntity.ToDomain()
+	certificate.Spec.PrivateKey = privateKey
+
+	return certificate, nil
+}
+
 func (c *CertificateMongoAdapter) filterByNamespaceAndName(
 	namespace, name string,
 ) bson.M {
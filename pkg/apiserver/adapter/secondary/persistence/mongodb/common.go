@@ -90,22 +90,40 @@ func (a *commonEntityAdapter[Entity, KeyType]) list(
 	return a.listWithFilter(ctx, options, nil)
 }
 
-//nolint:funlen // Reason: unavoidable, runs find + count and assembles a list response.
 func (a *commonEntityAdapter[Entity, KeyType]) listWithFilter(
 	ctx context.Context,
 	options *model.ListOptions,
 	extraFilter bson.M,
 ) (*model.ListResponse[*Entity], error) {
-	if options == nil {
-		//exhaustruct:ignore
-		options = &model.ListOptions{}
+	var continueToken string
+	if options != nil {
+		continueToken = options.Continue
 	}
 
-	continueTokenObjectID, err := bson.ObjectIDFromHex(options.Continue)
-	if err != nil && options.Continue != "" {
+	continueTokenObjectID, err := bson.ObjectIDFromHex(continueToken)
+	if err != nil && continueToken != "" {
 		return nil, fmt.Errorf("invalid continue token: %w", err)
 	}
 
+	return a.listWithFilterAfter(ctx, options, continueTokenObjectID, extraFilter)
+}
+
+// listWithFilterAfter is listWithFilter with the cursor already decoded, for
+// callers that carry their own continue token encoding. options.Continue is
+// ignored; the returned Continue is the raw hex cursor of the last entity.
+//
+//nolint:funlen // Reason: unavoidable, runs find + count and assembles a list response.
+func (a *commonEntityAdapter[Entity, KeyType]) listWithFilterAfter(
+	ctx context.Context,
+	options *model.ListOptions,
+	continueTokenObjectID bson.ObjectID,
+	extraFilter bson.M,
+) (*model.ListResponse[*Entity], error) {
+	if options == nil {
+		//exhaustruct:ignore
+		options = &model.ListOptions{}
+	}
+
 	var baseFilter bson.M
 	if options.IncludeDeleted {
 		baseFilter = extraFilter
@@ -0,0 +1,46 @@
+package mongodb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	mongoTestContainer "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/persistencetest"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/testutil"
+)
+
+func TestAgentMongoAdapter_Conformance(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+
+	base := testutil.NewBase(t)
+	ctx := t.Context()
+
+	mongoDBContainer, err := mongoTestContainer.Run(ctx, testMongoDBImage)
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := client.Disconnect(ctx)
+		require.NoError(t, err)
+	})
+
+	// Every subtest gets its own database, so one container serves the whole suite.
+	persistencetest.RunAgentPersistenceSuite(t, func(*testing.T) agentport.AgentPersistencePort {
+		database := client.Database("conformance_" + strings.ReplaceAll(uuid.NewString(), "-", ""))
+
+		return mongodb.NewAgentRepository(database, base.Logger)
+	})
+}
@@ -0,0 +1,168 @@
+package mongodb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// ErrInvalidContinueToken is returned when a continue token is malformed or was
+// issued for a different query than the one it is presented to. It wraps
+// model.ErrInvalidArgument so the API answers 400 rather than 500.
+var ErrInvalidContinueToken = fmt.Errorf("invalid continue token: %w", model.ErrInvalidArgument)
+
+const (
+	continueTokenSeparator = "."
+	// continueTokenScopeBytes is how many bytes of the query digest are kept in the
+	// token; it only has to tell queries apart, not resist forgery.
+	continueTokenScopeBytes = 8
+)
+
+// continueTokenScope binds agent list continue tokens to the query that issued
+// them. The cursor itself is the last returned _id, which is only meaningful
+// together with the filter it was read under: resuming a selector list from a
+// token of an unfiltered list silently skips or repeats agents instead of
+// failing. A token is therefore "<cursor>.<scope>", and decode rejects a token
+// whose scope does not match the query being run.
+type continueTokenScope string
+
+// newAgentListScope returns the scope of a namespaced agent list.
+func newAgentListScope(
+	namespace string,
+	connectedOnly bool,
+	connectionType string,
+	capabilities uint64,
+	identifyingAttributes, nonIdentifyingAttributes map[string]string,
+) continueTokenScope {
+	return newContinueTokenScope("list",
+		"namespace="+namespace,
+		"connectedOnly="+strconv.FormatBool(connectedOnly),
+		"connectionType="+connectionType,
+		"capabilities="+strconv.FormatUint(capabilities, 10),
+		"identifying="+canonicalAttributes(identifyingAttributes),
+		"nonIdentifying="+canonicalAttributes(nonIdentifyingAttributes),
+	)
+}
+
+// newAgentPackageScope returns the scope of a namespaced list of agents by package.
+func newAgentPackageScope(namespace string, connectedOnly bool, packageName, packageVersion string) continueTokenScope {
+	return newContinueTokenScope("package",
+		"namespace="+namespace,
+		"connectedOnly="+strconv.FormatBool(connectedOnly),
+		"name="+packageName,
+		"version="+packageVersion,
+	)
+}
+
+// newAgentSelectorScope returns the scope of a cross-namespace selector list.
+func newAgentSelectorScope(
+	connectedOnly bool,
+	capabilities uint64,
+	selector agentmodel.AgentSelector,
+) continueTokenScope {
+	return newContinueTokenScope("selector",
+		"connectedOnly="+strconv.FormatBool(connectedOnly),
+		"capabilities="+strconv.FormatUint(capabilities, 10),
+		"identifying="+canonicalAttributes(selector.IdentifyingAttributes),
+		"nonIdentifying="+canonicalAttributes(selector.NonIdentifyingAttributes),
+		"absentIdentifying="+canonicalKeys(selector.AbsentIdentifyingAttributes),
+		"absentNonIdentifying="+canonicalKeys(selector.AbsentNonIdentifyingAttributes),
+		"identifyingExpressions="+canonicalExpressions(selector.IdentifyingMatchExpressions),
+		"nonIdentifyingExpressions="+canonicalExpressions(selector.NonIdentifyingMatchExpressions),
+		"caseInsensitive="+strconv.FormatBool(selector.CaseInsensitive),
+	)
+}
+
+func newContinueTokenScope(kind string, parts ...string) continueTokenScope {
+	hash := sha256.New()
+
+	// Each part is length-prefixed so that no two different part lists hash the
+	// same input.
+	for _, part := range append([]string{kind}, parts...) {
+		_, _ = fmt.Fprintf(hash, "%d:%s;", len(part), part)
+	}
+
+	return continueTokenScope(hex.EncodeToString(hash.Sum(nil)[:continueTokenScopeBytes]))
+}
+
+// canonicalAttributes renders attributes in key order, quoting keys and values
+// so that separators inside them cannot collide.
+func canonicalAttributes(attributes map[string]string) string {
+	var builder strings.Builder
+
+	for _, key := range slices.Sorted(maps.Keys(attributes)) {
+		builder.WriteString(strconv.Quote(key))
+		builder.WriteByte('=')
+		builder.WriteString(strconv.Quote(attributes[key]))
+		builder.WriteByte(',')
+	}
+
+	return builder.String()
+}
+
+// canonicalKeys renders a key set sorted and de-duplicated, quoting each key.
+func canonicalKeys(keys []string) string {
+	var builder strings.Builder
+
+	for _, key := range slices.Compact(slices.Sorted(slices.Values(keys))) {
+		builder.WriteString(strconv.Quote(key))
+		builder.WriteByte(',')
+	}
+
+	return builder.String()
+}
+
+// canonicalExpressions renders match expressions sorted and de-duplicated, quoting each key.
+func canonicalExpressions(expressions []agentmodel.AttributeMatchExpression) string {
+	rendered := make([]string, 0, len(expressions))
+	for _, expression := range expressions {
+		rendered = append(rendered, strconv.Quote(expression.Key)+string(expression.Operator)+
+			strconv.FormatFloat(expression.Value, 'g', -1, 64))
+	}
+
+	return strings.Join(slices.Compact(slices.Sorted(slices.Values(rendered))), ",")
+}
+
+// encode wraps a raw cursor (the hex _id of the last returned entity) into a
+// token bound to this scope. The empty cursor, meaning "no more pages", stays
+// empty.
+func (s continueTokenScope) encode(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	return cursor + continueTokenSeparator + string(s)
+}
+
+// decode validates a token against this scope and returns the cursor to resume
+// after. The empty token yields bson.NilObjectID, i.e. the first page.
+func (s continueTokenScope) decode(token string) (bson.ObjectID, error) {
+	if token == "" {
+		return bson.NilObjectID, nil
+	}
+
+	cursor, scope, found := strings.Cut(token, continueTokenSeparator)
+	if !found {
+		return bson.NilObjectID, fmt.Errorf("%w: malformed token", ErrInvalidContinueToken)
+	}
+
+	objectID, err := bson.ObjectIDFromHex(cursor)
+	if err != nil {
+		return bson.NilObjectID, fmt.Errorf("%w: %w", ErrInvalidContinueToken, err)
+	}
+
+	if continueTokenScope(scope) != s {
+		return bson.NilObjectID, fmt.Errorf("%w: token was issued for a different query", ErrInvalidContinueToken)
+	}
+
+	return objectID, nil
+}
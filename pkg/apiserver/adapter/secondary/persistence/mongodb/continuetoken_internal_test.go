@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestContinueTokenScope_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	scope := newAgentListScope("default", false, "", 0, map[string]string{"service.name": "a"}, nil)
+	cursor := bson.NewObjectID()
+
+	token := scope.encode(cursor.Hex())
+
+	decoded, err := scope.decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	assert.Empty(t, scope.encode(""), "the last page must not hand out a token")
+
+	first, err := scope.decode("")
+	require.NoError(t, err)
+	assert.Equal(t, bson.NilObjectID, first)
+}
+
+func TestContinueTokenScope_DistinguishesQueries(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]string{"service.name": "a", "host.name": "b"}
+	base := newAgentListScope("default", false, "", 0, attrs, nil)
+
+	// Map iteration order must not matter.
+	assert.Equal(t, base, newAgentListScope("default", false, "", 0,
+		map[string]string{"host.name": "b", "service.name": "a"}, nil))
+
+	others := map[string]continueTokenScope{
+		"selector with same attributes": newAgentSelectorScope(false, 0,
+			agentmodel.AgentSelector{IdentifyingAttributes: attrs}),
+		"other namespace":                     newAgentListScope("other", false, "", 0, attrs, nil),
+		"connected only":                      newAgentListScope("default", true, "", 0, attrs, nil),
+		"connection type":                     newAgentListScope("default", false, "WebSocket", 0, attrs, nil),
+		"capabilities":                        newAgentListScope("default", false, "", 8, attrs, nil),
+		"attributes moved to non-identifying": newAgentListScope("default", false, "", 0, nil, attrs),
+		"separator inside a value": newAgentListScope("default", false, "", 0,
+			map[string]string{"service.name": `a",host.name="b`}, nil),
+	}
+
+	for name, other := range others {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.NotEqual(t, base, other)
+
+			_, err := other.decode(base.encode(bson.NewObjectID().Hex()))
+			require.ErrorIs(t, err, ErrInvalidContinueToken)
+		})
+	}
+}
+
+func TestContinueTokenScope_SelectorAbsentAttributes(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]string{"service.name": "a"}
+	base := newAgentSelectorScope(false, 0, agentmodel.AgentSelector{IdentifyingAttributes: attrs})
+	absent := newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+		IdentifyingAttributes:       attrs,
+		AbsentIdentifyingAttributes: []string{"host.name", "os.type"},
+	})
+
+	assert.NotEqual(t, base, absent)
+	// Order and duplicates of absent keys must not matter.
+	assert.Equal(t, absent, newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+		IdentifyingAttributes:       attrs,
+		AbsentIdentifyingAttributes: []string{"os.type", "host.name", "os.type"},
+	}))
+	assert.NotEqual(t, absent, newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+		IdentifyingAttributes:          attrs,
+		AbsentNonIdentifyingAttributes: []string{"host.name", "os.type"},
+	}))
+}
+
+func TestContinueTokenScope_SelectorMatchExpressions(t *testing.T) {
+	t.Parallel()
+
+	greaterThan := agentmodel.AttributeMatchExpression{
+		Key: "port", Operator: agentmodel.AttributeMatchOperatorGreaterThan, Value: 1024,
+	}
+	lessThan := agentmodel.AttributeMatchExpression{
+		Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024,
+	}
+	assert.NotEqual(t,
+		newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+			NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{greaterThan},
+		}),
+		newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+			NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{lessThan},
+		}))
+}
+
+func TestContinueTokenScope_SelectorCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]string{"os.type": "linux"}
+	assert.NotEqual(t,
+		newAgentSelectorScope(false, 0, agentmodel.AgentSelector{IdentifyingAttributes: attrs}),
+		newAgentSelectorScope(false, 0, agentmodel.AgentSelector{
+			IdentifyingAttributes: attrs,
+			CaseInsensitive:       true,
+		}))
+}
+
+func TestContinueTokenScope_RejectsMalformedTokens(t *testing.T) {
+	t.Parallel()
+
+	scope := newAgentSelectorScope(false, 0, agentmodel.AgentSelector{})
+
+	for _, token := range []string{
+		"invalid-token",
+		bson.NewObjectID().Hex(),
+		"not-hex." + string(scope),
+	} {
+		_, err := scope.decode(token)
+		require.ErrorIs(t, err, ErrInvalidContinueToken, token)
+	}
+}
@@ -16,6 +16,13 @@ const (
 	// AgentKeyFieldName is the field name used as the key for Agent entities in MongoDB.
 	AgentKeyFieldName string = "metadata.instanceUid"
 
+	// AgentNewInstanceUIDFieldName is the field name for the pending new instance UID in MongoDB.
+	AgentNewInstanceUIDFieldName string = "spec.newInstanceUID"
+
+	// AgentConnectionLostAtFieldName is the field name for the start of an agent's disconnect
+	// grace period in MongoDB.
+	AgentConnectionLostAtFieldName string = "status.connectionLostAt"
+
 	// IdentifyingAttributesFieldName is the field name for identifying attributes in MongoDB.
 	// It is indexed for efficient querying.
 	IdentifyingAttributesFieldName string = "metadata.description.identifyingAttributes"
@@ -42,6 +49,7 @@ type AgentMetadata struct {
 	Capabilities       *AgentCapabilities       `bson:"capabilities,omitempty"`
 	Description        *AgentDescription        `bson:"description,omitempty"`
 	CustomCapabilities *AgentCustomCapabilities `bson:"customCapabilities,omitempty"`
+	Labels             map[string]string        `bson:"labels,omitempty"`
 }
 
 // AgentSpec represents the desired specification of an agent.
@@ -49,6 +57,17 @@ type AgentSpec struct {
 	NewInstanceUID      *bson.Binary           `bson:"newInstanceUID,omitempty"`
 	RemoteConfig        *AgentSpecRemoteConfig `bson:"remoteConfig,omitempty"`
 	RequiredRestartedAt bson.DateTime          `bson:"requiredRestartedAt,omitempty"`
+	Quarantine          *AgentQuarantine       `bson:"quarantine,omitempty"`
+	ExpectedAttributes  map[string]string      `bson:"expectedAttributes,omitempty"`
+	PendingReports      []string               `bson:"pendingReports,omitempty"`
+}
+
+// AgentQuarantine is the persisted form of a server-set agent quarantine.
+type AgentQuarantine struct {
+	Automatic     bool          `bson:"automatic"`
+	Reason        string        `bson:"reason,omitempty"`
+	QuarantinedBy string        `bson:"quarantinedBy,omitempty"`
+	QuarantinedAt bson.DateTime `bson:"quarantinedAt"`
 }
 
 // AgentStatus represents the current status of an agent.
@@ -68,8 +87,40 @@ type AgentStatus struct {
 	Connected          bool             `bson:"connected,omitempty"`
 	ConnectionType     string           `bson:"connectionType,omitempty"`
 	SequenceNum        uint64           `bson:"sequenceNum,omitempty"`
+	FirstSeenAt        bson.DateTime    `bson:"firstSeenAt,omitempty"`
 	LastCommunicatedAt bson.DateTime    `bson:"lastCommunicatedAt,omitempty"`
 	LastCommunicatedTo string           `bson:"lastCommunicatedTo,omitempty"`
+
+	ConnectionStats *AgentConnectionStats `bson:"connectionStats,omitempty"`
+	// ConnectionLostAt is set while the agent's disconnect grace period runs.
+	ConnectionLostAt bson.DateTime `bson:"connectionLostAt,omitempty"`
+
+	EffectiveConfigReportedAt bson.DateTime                  `bson:"effectiveConfigReportedAt,omitempty"`
+	EffectiveConfigHistory    []AgentEffectiveConfigSnapshot `bson:"effectiveConfigHistory,omitempty"`
+}
+
+// AgentEffectiveConfigSnapshot stores one entry of an agent's effective-config history.
+type AgentEffectiveConfigSnapshot struct {
+	ReportedAt bson.DateTime        `bson:"reportedAt"`
+	Hash       bson.Binary          `bson:"hash"`
+	Truncated  bool                 `bson:"truncated,omitempty"`
+	Config     AgentEffectiveConfig `bson:"config"`
+}
+
+// AgentConnectionStats stores an agent's connection history aggregates.
+type AgentConnectionStats struct {
+	TrackedSince         bson.DateTime            `bson:"trackedSince"`
+	ConnectedSince       bson.DateTime            `bson:"connectedSince,omitempty"`
+	LastDisconnectedAt   bson.DateTime            `bson:"lastDisconnectedAt,omitempty"`
+	TotalConnectedMillis int64                    `bson:"totalConnectedMillis"`
+	DisconnectCount      int64                    `bson:"disconnectCount"`
+	RecentSessions       []AgentConnectionSession `bson:"recentSessions,omitempty"`
+}
+
+// AgentConnectionSession stores one closed connected period of an agent.
+type AgentConnectionSession struct {
+	ConnectedAt    bson.DateTime `bson:"connectedAt"`
+	DisconnectedAt bson.DateTime `bson:"disconnectedAt"`
 }
 
 // AgentCondition represents a condition of an agent in MongoDB.
@@ -85,6 +136,11 @@ type AgentCondition struct {
 type AgentDescription struct {
 	IdentifyingAttributes    KeyValuePairs `bson:"identifyingAttributes,omitempty"`
 	NonIdentifyingAttributes KeyValuePairs `bson:"nonIdentifyingAttributes,omitempty"`
+
+	RawIdentifyingAttributes    KeyValuePairs `bson:"rawIdentifyingAttributes,omitempty"`
+	RawNonIdentifyingAttributes KeyValuePairs `bson:"rawNonIdentifyingAttributes,omitempty"`
+
+	AttributesTruncated bool `bson:"attributesTruncated,omitempty"`
 }
 
 // KeyValuePair is a struct to manage key-value pairs.
@@ -219,6 +275,7 @@ type AgentCustomCapabilities struct {
 type AgentAvailableComponents struct {
 	Components map[string]ComponentDetails `bson:"components"`
 	Hash       bson.Binary                 `bson:"hash"`
+	Truncated  bool                        `bson:"truncated,omitempty"`
 }
 
 // ComponentDetails is a details of a component.
@@ -248,6 +305,7 @@ func (metadata *AgentMetadata) ToDmain() agentmodel.AgentMetadata {
 		//exhaustruct:ignore
 		CustomCapabilities: mo.PointerToOption(metadata.CustomCapabilities.ToDomain()).
 			OrElse(agentmodel.AgentCustomCapabilities{}),
+		Labels: metadata.Labels,
 	}
 }
 
@@ -269,6 +327,9 @@ func (spec *AgentSpec) ToDomain() agentmodel.AgentSpec {
 	}
 	agentSpec.ConnectionInfo = nil
 	agentSpec.RemoteConfig = spec.RemoteConfig.ToDomainPtr()
+	agentSpec.Quarantine = spec.Quarantine.ToDomain()
+	agentSpec.ExpectedAttributes = spec.ExpectedAttributes
+	agentSpec.PendingReports = agentPendingReportsToDomain(spec.PendingReports)
 
 	return agentSpec
 }
@@ -325,9 +386,136 @@ func (status *AgentStatus) ToDomain() agentmodel.AgentStatus {
 		Connected:      status.Connected,
 		ConnectionType: agentmodel.ConnectionTypeFromString(status.ConnectionType),
 		SequenceNum:    status.SequenceNum,
+		FirstSeenAt:    optionalDateTimeToTime(status.FirstSeenAt),
 		LastReportedAt: status.LastCommunicatedAt.Time(),
 		LastReportedTo: status.LastCommunicatedTo,
+
+		ConnectionStats:  status.ConnectionStats.ToDomain(),
+		ConnectionLostAt: optionalDateTimeToTime(status.ConnectionLostAt),
+
+		EffectiveConfigReportedAt: status.EffectiveConfigReportedAt.Time(),
+		EffectiveConfigHistory:    AgentEffectiveConfigHistoryToDomain(status.EffectiveConfigHistory),
+	}
+}
+
+// AgentEffectiveConfigHistoryToDomain converts the stored effective-config history to
+// domain model.
+func AgentEffectiveConfigHistoryToDomain(
+	history []AgentEffectiveConfigSnapshot,
+) []agentmodel.EffectiveConfigSnapshot {
+	if len(history) == 0 {
+		return nil
+	}
+
+	snapshots := make([]agentmodel.EffectiveConfigSnapshot, len(history))
+	for i, snapshot := range history {
+		snapshots[i] = agentmodel.EffectiveConfigSnapshot{
+			ReportedAt: snapshot.ReportedAt.Time(),
+			Hash:       snapshot.Hash.Data,
+			Truncated:  snapshot.Truncated,
+			Config:     *snapshot.Config.ToDomain(),
+		}
+	}
+
+	return snapshots
+}
+
+// AgentEffectiveConfigHistoryFromDomain converts the domain effective-config history to
+// persistence model.
+func AgentEffectiveConfigHistoryFromDomain(
+	history []agentmodel.EffectiveConfigSnapshot,
+) []AgentEffectiveConfigSnapshot {
+	if len(history) == 0 {
+		return nil
+	}
+
+	snapshots := make([]AgentEffectiveConfigSnapshot, len(history))
+	for i, snapshot := range history {
+		snapshots[i] = AgentEffectiveConfigSnapshot{
+			ReportedAt: bson.NewDateTimeFromTime(snapshot.ReportedAt),
+			Hash:       bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: snapshot.Hash},
+			Truncated:  snapshot.Truncated,
+			Config:     *AgentEffectiveConfigFromDomain(&snapshot.Config),
+		}
+	}
+
+	return snapshots
+}
+
+// ToDomain converts the AgentConnectionStats to domain model. A nil receiver means
+// no connection was recorded yet.
+func (s *AgentConnectionStats) ToDomain() agentmodel.AgentConnectionStats {
+	if s == nil {
+		//exhaustruct:ignore
+		return agentmodel.AgentConnectionStats{}
+	}
+
+	var sessions []agentmodel.ConnectionSession
+	if len(s.RecentSessions) > 0 {
+		sessions = make([]agentmodel.ConnectionSession, len(s.RecentSessions))
+		for i, session := range s.RecentSessions {
+			sessions[i] = agentmodel.ConnectionSession{
+				ConnectedAt:    session.ConnectedAt.Time(),
+				DisconnectedAt: session.DisconnectedAt.Time(),
+			}
+		}
+	}
+
+	return agentmodel.AgentConnectionStats{
+		TrackedSince:           optionalDateTimeToTime(s.TrackedSince),
+		ConnectedSince:         optionalDateTimeToTime(s.ConnectedSince),
+		LastDisconnectedAt:     optionalDateTimeToTime(s.LastDisconnectedAt),
+		TotalConnectedDuration: time.Duration(s.TotalConnectedMillis) * time.Millisecond,
+		DisconnectCount:        s.DisconnectCount,
+		RecentSessions:         sessions,
+	}
+}
+
+// AgentConnectionStatsFromDomain converts domain model to persistence model. Stats
+// that never recorded a connection are not stored.
+func AgentConnectionStatsFromDomain(stats *agentmodel.AgentConnectionStats) *AgentConnectionStats {
+	if stats == nil || stats.TrackedSince.IsZero() {
+		return nil
+	}
+
+	var sessions []AgentConnectionSession
+	if len(stats.RecentSessions) > 0 {
+		sessions = make([]AgentConnectionSession, len(stats.RecentSessions))
+		for i, session := range stats.RecentSessions {
+			sessions[i] = AgentConnectionSession{
+				ConnectedAt:    bson.NewDateTimeFromTime(session.ConnectedAt),
+				DisconnectedAt: bson.NewDateTimeFromTime(session.DisconnectedAt),
+			}
+		}
+	}
+
+	return &AgentConnectionStats{
+		TrackedSince:         bson.NewDateTimeFromTime(stats.TrackedSince),
+		ConnectedSince:       optionalTimeToDateTime(stats.ConnectedSince),
+		LastDisconnectedAt:   optionalTimeToDateTime(stats.LastDisconnectedAt),
+		TotalConnectedMillis: stats.TotalConnectedDuration.Milliseconds(),
+		DisconnectCount:      stats.DisconnectCount,
+		RecentSessions:       sessions,
+	}
+}
+
+// optionalTimeToDateTime stores the zero time as the zero DateTime so that
+// omitempty drops it, instead of the far-negative value of year 1.
+func optionalTimeToDateTime(t time.Time) bson.DateTime {
+	if t.IsZero() {
+		return 0
+	}
+
+	return bson.NewDateTimeFromTime(t)
+}
+
+// optionalDateTimeToTime is the inverse of optionalTimeToDateTime.
+func optionalDateTimeToTime(dt bson.DateTime) time.Time {
+	if dt == 0 {
+		return time.Time{}
 	}
+
+	return dt.Time()
 }
 
 // ToDomain converts the AgentCapabilities to domain model.
@@ -348,7 +536,21 @@ func (ad *AgentDescription) ToDomain() *agent.Description {
 	return &agent.Description{
 		IdentifyingAttributes:    ad.IdentifyingAttributes.ToMap(),
 		NonIdentifyingAttributes: ad.NonIdentifyingAttributes.ToMap(),
+
+		// Raw attributes are only recorded when aliases were normalized; keep them nil otherwise.
+		RawIdentifyingAttributes:    rawAttributesToMap(ad.RawIdentifyingAttributes),
+		RawNonIdentifyingAttributes: rawAttributesToMap(ad.RawNonIdentifyingAttributes),
+
+		AttributesTruncated: ad.AttributesTruncated,
+	}
+}
+
+func rawAttributesToMap(kvs KeyValuePairs) map[string]string {
+	if len(kvs) == 0 {
+		return nil
 	}
+
+	return kvs.ToMap()
 }
 
 // ToDomain converts the AgentEffectiveConfig to domain model.
@@ -493,7 +695,8 @@ func (avv *AgentAvailableComponents) ToDomain() *agentmodel.AgentAvailableCompon
 			func(component ComponentDetails, _ string) agentmodel.ComponentDetails {
 				return *component.ToDomain()
 			}),
-		Hash: avv.Hash.Data,
+		Hash:      avv.Hash.Data,
+		Truncated: avv.Truncated,
 	}
 }
 
@@ -538,11 +741,15 @@ func AgentFromDomain(agent *agentmodel.Agent) *Agent {
 			Capabilities:       AgentCapabilitiesFromDomain(&agent.Metadata.Capabilities),
 			Description:        AgentDescriptionFromDomain(&agent.Metadata.Description),
 			CustomCapabilities: AgentCustomCapabilitiesFromDomain(&agent.Metadata.CustomCapabilities),
+			Labels:             agent.Metadata.Labels,
 		},
 		Spec: AgentSpec{
 			NewInstanceUID:      newInstanceUID,
 			RemoteConfig:        AgentSpecRemoteConfigFromDomain(agent.Spec.RemoteConfig),
 			RequiredRestartedAt: agentRestartInfoToBsonDateTime(agent.Spec.RestartInfo),
+			Quarantine:          AgentQuarantineFromDomain(agent.Spec.Quarantine),
+			ExpectedAttributes:  agent.Spec.ExpectedAttributes,
+			PendingReports:      agentPendingReportsFromDomain(agent.Spec.PendingReports),
 		},
 		Status: AgentStatus{
 			EffectiveConfig:     AgentEffectiveConfigFromDomain(&agent.Status.EffectiveConfig),
@@ -554,12 +761,73 @@ func AgentFromDomain(agent *agentmodel.Agent) *Agent {
 			Connected:           agent.Status.Connected,
 			ConnectionType:      agent.Status.ConnectionType.String(),
 			SequenceNum:         agent.Status.SequenceNum,
+			FirstSeenAt:         optionalTimeToDateTime(agent.Status.FirstSeenAt),
 			LastCommunicatedAt:  bson.NewDateTimeFromTime(agent.Status.LastReportedAt),
 			LastCommunicatedTo:  agent.Status.LastReportedTo,
+			ConnectionStats:     AgentConnectionStatsFromDomain(&agent.Status.ConnectionStats),
+			ConnectionLostAt:    optionalTimeToDateTime(agent.Status.ConnectionLostAt),
+
+			EffectiveConfigReportedAt: bson.NewDateTimeFromTime(agent.Status.EffectiveConfigReportedAt),
+			EffectiveConfigHistory:    AgentEffectiveConfigHistoryFromDomain(agent.Status.EffectiveConfigHistory),
 		},
 	}
 }
 
+// ToDomain converts the AgentQuarantine to domain model. A nil receiver means the
+// agent is not quarantined.
+func (aq *AgentQuarantine) ToDomain() *agentmodel.AgentQuarantine {
+	if aq == nil {
+		return nil
+	}
+
+	return &agentmodel.AgentQuarantine{
+		Automatic:     aq.Automatic,
+		Reason:        aq.Reason,
+		QuarantinedBy: aq.QuarantinedBy,
+		QuarantinedAt: aq.QuarantinedAt.Time(),
+	}
+}
+
+// AgentQuarantineFromDomain converts domain model to persistence model.
+func AgentQuarantineFromDomain(quarantine *agentmodel.AgentQuarantine) *AgentQuarantine {
+	if quarantine == nil {
+		return nil
+	}
+
+	return &AgentQuarantine{
+		Automatic:     quarantine.Automatic,
+		Reason:        quarantine.Reason,
+		QuarantinedBy: quarantine.QuarantinedBy,
+		QuarantinedAt: bson.NewDateTimeFromTime(quarantine.QuarantinedAt),
+	}
+}
+
+func agentPendingReportsToDomain(pendingReports []string) []agentmodel.AgentReportKind {
+	if len(pendingReports) == 0 {
+		return nil
+	}
+
+	kinds := make([]agentmodel.AgentReportKind, len(pendingReports))
+	for i, kind := range pendingReports {
+		kinds[i] = agentmodel.AgentReportKind(kind)
+	}
+
+	return kinds
+}
+
+func agentPendingReportsFromDomain(kinds []agentmodel.AgentReportKind) []string {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	pendingReports := make([]string, len(kinds))
+	for i, kind := range kinds {
+		pendingReports[i] = string(kind)
+	}
+
+	return pendingReports
+}
+
 func agentRestartInfoToBsonDateTime(restartInfo *agentmodel.AgentRestartInfo) bson.DateTime {
 	if restartInfo == nil {
 		return bson.NewDateTimeFromTime(time.Time{})
@@ -584,8 +852,11 @@ func AgentDescriptionFromDomain(ads *agent.Description) *AgentDescription {
 	}
 
 	return &AgentDescription{
-		IdentifyingAttributes:    MapToKeyValuePairs(ads.IdentifyingAttributes),
-		NonIdentifyingAttributes: MapToKeyValuePairs(ads.NonIdentifyingAttributes),
+		IdentifyingAttributes:       MapToKeyValuePairs(ads.IdentifyingAttributes),
+		NonIdentifyingAttributes:    MapToKeyValuePairs(ads.NonIdentifyingAttributes),
+		RawIdentifyingAttributes:    MapToKeyValuePairs(ads.RawIdentifyingAttributes),
+		RawNonIdentifyingAttributes: MapToKeyValuePairs(ads.RawNonIdentifyingAttributes),
+		AttributesTruncated:         ads.AttributesTruncated,
 	}
 }
 
@@ -719,6 +990,7 @@ func AgentAvailableComponentsFromDomain(acc *agentmodel.AgentAvailableComponents
 			Subtype: bson.TypeBinaryGeneric,
 			Data:    acc.Hash,
 		},
+		Truncated: acc.Truncated,
 	}
 }
 
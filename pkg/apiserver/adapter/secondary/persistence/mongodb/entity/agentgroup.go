@@ -39,6 +39,25 @@ type AgentGroupSpec struct {
 	// AgentRemoteConfigs is the list of remote configurations applied to agents in the group.
 	AgentRemoteConfigs    []AgentGroupAgentRemoteConfig `bson:"agentRemoteConfigs,omitempty"`
 	AgentConnectionConfig *AgentConnectionConfig        `bson:"agentConnectionConfig,omitempty"`
+	Rollout               *AgentGroupRollout            `bson:"rollout,omitempty"`
+	// ExcludeBaseRemoteConfig keeps the server's base remote configs off the group's agents.
+	ExcludeBaseRemoteConfig bool `bson:"excludeBaseRemoteConfig,omitempty"`
+	// PreviousRemoteConfigs are the remote configs the group had before they last changed.
+	PreviousRemoteConfigs *AgentGroupPreviousRemoteConfigs `bson:"previousRemoteConfigs,omitempty"`
+}
+
+// AgentGroupPreviousRemoteConfigs represents the remote configs an agent group had before
+// they last changed, kept to roll the change back.
+type AgentGroupPreviousRemoteConfigs struct {
+	AgentRemoteConfigs []AgentGroupAgentRemoteConfig `bson:"agentRemoteConfigs,omitempty"`
+}
+
+// AgentGroupRollout represents a rollout of an agent group's remote configs in progress.
+type AgentGroupRollout struct {
+	Percentage                 int                           `bson:"percentage,omitempty"`
+	Count                      int                           `bson:"count,omitempty"`
+	PreviousAgentRemoteConfigs []AgentGroupAgentRemoteConfig `bson:"previousAgentRemoteConfigs,omitempty"`
+	BucketThreshold            int                           `bson:"bucketThreshold,omitempty"`
 }
 
 // AgentGroupStatus represents the status of an agent group in MongoDB.
@@ -50,6 +69,58 @@ type AgentGroupStatus struct {
 type AgentSelector struct {
 	IdentifyingAttributes    map[string]string `json:"identifyingAttributes"`
 	NonIdentifyingAttributes map[string]string `json:"nonIdentifyingAttributes"`
+
+	AbsentIdentifyingAttributes    []string `bson:"absentIdentifyingAttributes,omitempty"`
+	AbsentNonIdentifyingAttributes []string `bson:"absentNonIdentifyingAttributes,omitempty"`
+
+	IdentifyingMatchExpressions    []AttributeMatchExpression `bson:"identifyingMatchExpressions,omitempty"`
+	NonIdentifyingMatchExpressions []AttributeMatchExpression `bson:"nonIdentifyingMatchExpressions,omitempty"`
+
+	CaseInsensitive bool `bson:"caseInsensitive,omitempty"`
+}
+
+// AttributeMatchExpression is a selector condition on an attribute value beyond equality.
+type AttributeMatchExpression struct {
+	Key      string  `bson:"key"`
+	Operator string  `bson:"operator"`
+	Value    float64 `bson:"value"`
+}
+
+// AttributeMatchExpressionsFromDomain converts domain selector match expressions to entities.
+func AttributeMatchExpressionsFromDomain(
+	expressions []agentmodel.AttributeMatchExpression,
+) []AttributeMatchExpression {
+	if len(expressions) == 0 {
+		return nil
+	}
+
+	result := make([]AttributeMatchExpression, 0, len(expressions))
+	for _, expression := range expressions {
+		result = append(result, AttributeMatchExpression{
+			Key:      expression.Key,
+			Operator: string(expression.Operator),
+			Value:    expression.Value,
+		})
+	}
+
+	return result
+}
+
+func attributeMatchExpressionsToDomain(expressions []AttributeMatchExpression) []agentmodel.AttributeMatchExpression {
+	if len(expressions) == 0 {
+		return nil
+	}
+
+	result := make([]agentmodel.AttributeMatchExpression, 0, len(expressions))
+	for _, expression := range expressions {
+		result = append(result, agentmodel.AttributeMatchExpression{
+			Key:      expression.Key,
+			Operator: agentmodel.AttributeMatchOperator(expression.Operator),
+			Value:    expression.Value,
+		})
+	}
+
+	return result
 }
 
 // AgentGroupAgentRemoteConfig represents the remote configuration for agents in the group.
@@ -163,15 +234,44 @@ func (s *AgentGroupSpec) toDomain() agentmodel.AgentGroupSpec {
 	spec := agentmodel.AgentGroupSpec{
 		Priority: s.Priority,
 		Selector: agentmodel.AgentSelector{
-			IdentifyingAttributes:    s.Selector.IdentifyingAttributes,
-			NonIdentifyingAttributes: s.Selector.NonIdentifyingAttributes,
+			IdentifyingAttributes:          s.Selector.IdentifyingAttributes,
+			NonIdentifyingAttributes:       s.Selector.NonIdentifyingAttributes,
+			AbsentIdentifyingAttributes:    s.Selector.AbsentIdentifyingAttributes,
+			AbsentNonIdentifyingAttributes: s.Selector.AbsentNonIdentifyingAttributes,
+			IdentifyingMatchExpressions:    attributeMatchExpressionsToDomain(s.Selector.IdentifyingMatchExpressions),
+			NonIdentifyingMatchExpressions: attributeMatchExpressionsToDomain(s.Selector.NonIdentifyingMatchExpressions),
+			CaseInsensitive:                s.Selector.CaseInsensitive,
 		},
+		ExcludeBaseRemoteConfig: s.ExcludeBaseRemoteConfig,
 	}
 
 	for i := range s.AgentRemoteConfigs {
 		spec.AgentRemoteConfigs = append(spec.AgentRemoteConfigs, s.AgentRemoteConfigs[i].toDomain())
 	}
 
+	if s.Rollout != nil {
+		//nolint:exhaustruct // PreviousAgentRemoteConfigs is set below
+		spec.Rollout = &agentmodel.AgentGroupRollout{
+			Percentage:      s.Rollout.Percentage,
+			Count:           s.Rollout.Count,
+			BucketThreshold: s.Rollout.BucketThreshold,
+		}
+
+		for i := range s.Rollout.PreviousAgentRemoteConfigs {
+			spec.Rollout.PreviousAgentRemoteConfigs = append(
+				spec.Rollout.PreviousAgentRemoteConfigs, s.Rollout.PreviousAgentRemoteConfigs[i].toDomain())
+		}
+	}
+
+	if s.PreviousRemoteConfigs != nil {
+		spec.PreviousRemoteConfigs = &agentmodel.AgentGroupPreviousRemoteConfigs{AgentRemoteConfigs: nil}
+
+		for i := range s.PreviousRemoteConfigs.AgentRemoteConfigs {
+			spec.PreviousRemoteConfigs.AgentRemoteConfigs = append(spec.PreviousRemoteConfigs.AgentRemoteConfigs,
+				s.PreviousRemoteConfigs.AgentRemoteConfigs[i].toDomain())
+		}
+	}
+
 	if s.AgentConnectionConfig != nil {
 		spec.AgentConnectionConfig = &agentmodel.AgentGroupConnectionConfig{
 			OpAMPConnection: &agentmodel.OpAMPConnectionSettings{
@@ -250,9 +350,15 @@ func agentGroupSpecFromDomain(spec agentmodel.AgentGroupSpec) AgentGroupSpec {
 	result := AgentGroupSpec{
 		Priority: spec.Priority,
 		Selector: AgentSelector{
-			IdentifyingAttributes:    spec.Selector.IdentifyingAttributes,
-			NonIdentifyingAttributes: spec.Selector.NonIdentifyingAttributes,
+			IdentifyingAttributes:          spec.Selector.IdentifyingAttributes,
+			NonIdentifyingAttributes:       spec.Selector.NonIdentifyingAttributes,
+			AbsentIdentifyingAttributes:    spec.Selector.AbsentIdentifyingAttributes,
+			AbsentNonIdentifyingAttributes: spec.Selector.AbsentNonIdentifyingAttributes,
+			IdentifyingMatchExpressions:    AttributeMatchExpressionsFromDomain(spec.Selector.IdentifyingMatchExpressions),
+			NonIdentifyingMatchExpressions: AttributeMatchExpressionsFromDomain(spec.Selector.NonIdentifyingMatchExpressions),
+			CaseInsensitive:                spec.Selector.CaseInsensitive,
 		},
+		ExcludeBaseRemoteConfig: spec.ExcludeBaseRemoteConfig,
 	}
 
 	if len(spec.AgentRemoteConfigs) > 0 {
@@ -263,6 +369,29 @@ func agentGroupSpecFromDomain(spec agentmodel.AgentGroupSpec) AgentGroupSpec {
 		}
 	}
 
+	if spec.Rollout != nil {
+		//nolint:exhaustruct // PreviousAgentRemoteConfigs is set below
+		result.Rollout = &AgentGroupRollout{
+			Percentage:      spec.Rollout.Percentage,
+			Count:           spec.Rollout.Count,
+			BucketThreshold: spec.Rollout.BucketThreshold,
+		}
+
+		for i := range spec.Rollout.PreviousAgentRemoteConfigs {
+			result.Rollout.PreviousAgentRemoteConfigs = append(result.Rollout.PreviousAgentRemoteConfigs,
+				agentGroupRemoteConfigFromDomain(&spec.Rollout.PreviousAgentRemoteConfigs[i]))
+		}
+	}
+
+	if spec.PreviousRemoteConfigs != nil {
+		result.PreviousRemoteConfigs = &AgentGroupPreviousRemoteConfigs{AgentRemoteConfigs: nil}
+
+		for i := range spec.PreviousRemoteConfigs.AgentRemoteConfigs {
+			result.PreviousRemoteConfigs.AgentRemoteConfigs = append(result.PreviousRemoteConfigs.AgentRemoteConfigs,
+				agentGroupRemoteConfigFromDomain(&spec.PreviousRemoteConfigs.AgentRemoteConfigs[i]))
+		}
+	}
+
 	if spec.AgentConnectionConfig != nil {
 		result.AgentConnectionConfig = &AgentConnectionConfig{
 			OpAMP: ConnectionSettings{
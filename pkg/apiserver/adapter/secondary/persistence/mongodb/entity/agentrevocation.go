@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+const (
+	// AgentRevocationKeyFieldName is the key field name for agent revocations.
+	AgentRevocationKeyFieldName = "instanceUid"
+)
+
+// AgentRevocation is the MongoDB entity for a revoked agent instance UID.
+type AgentRevocation struct {
+	ID          *bson.ObjectID `bson:"_id,omitempty"`
+	InstanceUID bson.Binary    `bson:"instanceUid"`
+	Reason      string         `bson:"reason,omitempty"`
+	RevokedBy   string         `bson:"revokedBy"`
+	RevokedAt   time.Time      `bson:"revokedAt"`
+}
+
+// ToDomain converts the entity to the domain model.
+func (e *AgentRevocation) ToDomain() *agentmodel.AgentRevocation {
+	return &agentmodel.AgentRevocation{
+		InstanceUID: uuid.UUID(e.InstanceUID.Data),
+		Reason:      e.Reason,
+		RevokedBy:   e.RevokedBy,
+		RevokedAt:   e.RevokedAt,
+	}
+}
+
+// AgentRevocationFromDomain converts the domain model to the entity.
+func AgentRevocationFromDomain(revocation *agentmodel.AgentRevocation) *AgentRevocation {
+	return &AgentRevocation{
+		ID: nil,
+		InstanceUID: bson.Binary{
+			Subtype: bson.TypeBinaryUUID,
+			Data:    revocation.InstanceUID[:],
+		},
+		Reason:    revocation.Reason,
+		RevokedBy: revocation.RevokedBy,
+		RevokedAt: revocation.RevokedAt,
+	}
+}
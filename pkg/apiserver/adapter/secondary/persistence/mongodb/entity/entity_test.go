@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
@@ -186,3 +187,41 @@ func TestEndpointEntity_RoundTrip(t *testing.T) {
 	require.NotNil(t, got.Spec.MetricsQuery)
 	assert.Equal(t, "sum(rate(m[5m]))", got.Spec.MetricsQuery.Metrics)
 }
+
+func TestAgentEntity_EffectiveConfigHistoryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	reportedAt := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	limits := agentmodel.DefaultEffectiveConfigHistoryLimits()
+	domainAgent := agentmodel.NewAgent(uuid.New())
+
+	for i, body := range []string{"receivers: {}", "receivers: {otlp: {}}"} {
+		domainAgent.RecordEffectiveConfig(&agentmodel.AgentEffectiveConfig{
+			ConfigMap: agentmodel.AgentConfigMap{
+				ConfigMap: map[string]agentmodel.AgentConfigFile{
+					"collector.yaml": {Body: []byte(body), ContentType: "text/yaml"},
+				},
+			},
+		}, reportedAt.Add(time.Duration(i)*time.Minute), limits)
+	}
+
+	require.Len(t, domainAgent.Status.EffectiveConfigHistory, 2)
+
+	raw, err := bson.Marshal(entity.AgentFromDomain(domainAgent))
+	require.NoError(t, err)
+
+	var stored entity.Agent
+	require.NoError(t, bson.Unmarshal(raw, &stored))
+
+	got := stored.ToDomain()
+
+	require.Len(t, got.Status.EffectiveConfigHistory, 2)
+
+	for i, want := range domainAgent.Status.EffectiveConfigHistory {
+		snapshot := got.Status.EffectiveConfigHistory[i]
+		assert.True(t, want.ReportedAt.Equal(snapshot.ReportedAt), "ReportedAt must survive the round trip")
+		assert.Equal(t, want.Hash, snapshot.Hash)
+		assert.Equal(t, want.Truncated, snapshot.Truncated)
+		assert.Equal(t, want.Config, snapshot.Config)
+	}
+}
@@ -0,0 +1,217 @@
+package mongodb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// KeyURISchemeBase64Key names a key given inline, base64 encoded: base64key://<key>.
+	KeyURISchemeBase64Key = "base64key"
+	// KeyURISchemeFile names a key read from a file holding it base64 encoded: file:///path.
+	KeyURISchemeFile = "file"
+
+	// fieldKeySize is the size of both the key encryption key and the per-value data keys.
+	// They are AES-256 keys.
+	fieldKeySize = 32
+)
+
+// encryptedFieldPrefix marks a stored value as an envelope written by FieldCipher. Values
+// without it were stored before encryption was enabled and are read back as they are.
+var encryptedFieldPrefix = []byte("OCENC1")
+
+var (
+	// ErrUnsupportedKeyURI is returned when the field encryption key URI has an unknown scheme.
+	ErrUnsupportedKeyURI = errors.New("unsupported field encryption key URI")
+	// ErrInvalidFieldEncryptionKey is returned when the configured key is not a 32-byte key.
+	ErrInvalidFieldEncryptionKey = errors.New("field encryption key must be 32 bytes")
+	// ErrFieldEncryptionKeyMissing is returned when an encrypted value is read but no key is
+	// configured to decrypt it.
+	ErrFieldEncryptionKeyMissing = errors.New("stored value is encrypted but no field encryption key is configured")
+	// ErrMalformedEncryptedField is returned when an encrypted value is truncated.
+	ErrMalformedEncryptedField = errors.New("malformed encrypted field")
+)
+
+// FieldCipher encrypts secret fields, such as certificate private keys, before they are
+// written to MongoDB and decrypts them on read.
+//
+// It uses envelope encryption: each value is sealed with a fresh random data key, and the
+// data key is sealed with the configured key encryption key and stored next to the value.
+// A nil FieldCipher leaves values as they are.
+type FieldCipher struct {
+	keyEncryptionKey cipher.AEAD
+}
+
+// NewFieldCipher creates a FieldCipher from a key URI. The supported schemes are
+// base64key://<base64 key> and file:///path/to/key, where the file holds the base64 key.
+// An empty URI disables encryption and returns a nil FieldCipher.
+func NewFieldCipher(keyURI string) (*FieldCipher, error) {
+	if keyURI == "" {
+		return nil, nil //nolint:nilnil // a nil FieldCipher means encryption is disabled
+	}
+
+	key, err := loadFieldEncryptionKey(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FieldCipher{keyEncryptionKey: aead}, nil
+}
+
+// Encrypt seals plaintext into an envelope. Empty values are stored as they are.
+func (c *FieldCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	if c == nil || len(plaintext) == 0 {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, fieldKeySize)
+
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+
+	dataAEAD, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := bytes.Clone(encryptedFieldPrefix)
+
+	envelope, err = seal(c.keyEncryptionKey, envelope, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+
+	envelope, err = seal(dataAEAD, envelope, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt field: %w", err)
+	}
+
+	return envelope, nil
+}
+
+// Decrypt opens an envelope written by Encrypt. A value without the envelope prefix was
+// stored before encryption was enabled and is returned as it is.
+func (c *FieldCipher) Decrypt(stored []byte) ([]byte, error) {
+	if !bytes.HasPrefix(stored, encryptedFieldPrefix) {
+		return stored, nil
+	}
+
+	if c == nil {
+		return nil, ErrFieldEncryptionKeyMissing
+	}
+
+	rest := stored[len(encryptedFieldPrefix):]
+
+	dataKey, rest, err := open(c.keyEncryptionKey, rest, fieldKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+
+	dataAEAD, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, _, err := open(dataAEAD, rest, len(rest)-dataAEAD.NonceSize()-dataAEAD.Overhead())
+	if err != nil {
+		return nil, fmt.Errorf("decrypt field: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// seal appends a random nonce and the sealed plaintext to dst.
+func seal(aead cipher.AEAD, dst, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	dst = append(dst, nonce...)
+
+	return aead.Seal(dst, nonce, plaintext, nil), nil
+}
+
+// open reads a nonce and a sealed value of plaintextSize bytes from the start of src, and
+// returns the opened value with what follows it.
+func open(aead cipher.AEAD, src []byte, plaintextSize int) ([]byte, []byte, error) {
+	sealedSize := aead.NonceSize() + plaintextSize + aead.Overhead()
+	if plaintextSize < 0 || len(src) < sealedSize {
+		return nil, nil, ErrMalformedEncryptedField
+	}
+
+	nonce := src[:aead.NonceSize()]
+
+	plaintext, err := aead.Open(nil, nonce, src[aead.NonceSize():sealedSize], nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open sealed value: %w", err)
+	}
+
+	return plaintext, src[sealedSize:], nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create AES cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+func loadFieldEncryptionKey(keyURI string) ([]byte, error) {
+	parsed, err := url.Parse(keyURI)
+	if err != nil {
+		return nil, fmt.Errorf("parse field encryption key URI: %w", err)
+	}
+
+	var encoded string
+
+	switch parsed.Scheme {
+	case KeyURISchemeBase64Key:
+		encoded = strings.TrimPrefix(keyURI, KeyURISchemeBase64Key+"://")
+	case KeyURISchemeFile:
+		content, err := os.ReadFile(parsed.Path)
+		if err != nil {
+			return nil, fmt.Errorf("read field encryption key file: %w", err)
+		}
+
+		encoded = string(content)
+	default:
+		return nil, fmt.Errorf("%w: scheme %q (supported: %s, %s)",
+			ErrUnsupportedKeyURI, parsed.Scheme, KeyURISchemeBase64Key, KeyURISchemeFile)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode field encryption key: %w", err)
+	}
+
+	if len(key) != fieldKeySize {
+		return nil, fmt.Errorf("%w: got %d bytes", ErrInvalidFieldEncryptionKey, len(key))
+	}
+
+	return key, nil
+}
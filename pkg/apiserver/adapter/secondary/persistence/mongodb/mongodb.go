@@ -46,6 +46,7 @@ var (
 		agentGroupCollectionName,
 		agentPackageCollectionName,
 		agentRemoteConfigCollectionName,
+		agentRevocationCollectionName,
 		certificateCollectionName,
 		namespaceCollectionName,
 		serverCollectionName,
@@ -119,6 +120,15 @@ var (
 					},
 					Options: nil,
 				},
+				{
+					Keys: bson.D{
+						{Key: agentGroupNamespaceFieldName, Value: 1},
+						{Key: agentGroupNameFieldName, Value: 1},
+					},
+					// Case-insensitive collation so this index backs the collated
+					// ListAgentGroupNamesIgnoringCase lookup (same agentGroupNameCollation).
+					Options: options.Index().SetCollation(agentGroupNameCollation),
+				},
 			},
 		},
 		{
@@ -175,6 +185,18 @@ var (
 				},
 			},
 		},
+		{
+			collectionName: agentRevocationCollectionName,
+			indexes: []mongo.IndexModel{
+				// Backs the revocation check the OpAMP handler runs on every agent message.
+				{
+					Keys: bson.D{
+						{Key: "instanceUid", Value: 1},
+					},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+		},
 		{
 			collectionName: namespaceCollectionName,
 			indexes: []mongo.IndexModel{
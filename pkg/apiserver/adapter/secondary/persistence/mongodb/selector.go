@@ -1,35 +1,71 @@
 package mongodb
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb/entity"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 )
 
 // SelectorToMatchConditions converts an AgentSelector to a list of MongoDB match conditions.
 func SelectorToMatchConditions(selector entity.AgentSelector) []bson.M {
 	// Build match conditions for identifying attributes
-	identifyingConditions := IdentifyingAttributesSelectorToMatchConditions(selector.IdentifyingAttributes)
+	identifyingConditions := attributesSelectorToMatchConditions(entity.IdentifyingAttributesFieldName,
+		selector.IdentifyingAttributes, selector.CaseInsensitive)
 
 	// Build match conditions for non-identifying attributes
-	nonIdentifyingConditions := NonIdentifyingAttributesSelectorToMatchConditions(selector.NonIdentifyingAttributes)
+	nonIdentifyingConditions := attributesSelectorToMatchConditions(entity.NonIdentifyingAttributesFieldName,
+		selector.NonIdentifyingAttributes, selector.CaseInsensitive)
+
+	// Build match conditions for attributes that must not be reported
+	absentConditions := mergeConditions(
+		AbsentAttributesSelectorToMatchConditions(entity.IdentifyingAttributesFieldName,
+			selector.AbsentIdentifyingAttributes),
+		AbsentAttributesSelectorToMatchConditions(entity.NonIdentifyingAttributesFieldName,
+			selector.AbsentNonIdentifyingAttributes),
+	)
+
+	// Build match conditions for attribute values compared beyond equality
+	expressionConditions := mergeConditions(
+		AttributeExpressionsToMatchConditions(entity.IdentifyingAttributesFieldName,
+			selector.IdentifyingMatchExpressions),
+		AttributeExpressionsToMatchConditions(entity.NonIdentifyingAttributesFieldName,
+			selector.NonIdentifyingMatchExpressions),
+	)
 
 	// Combine all conditions
-	allConditions := mergeConditions(identifyingConditions, nonIdentifyingConditions)
+	allConditions := mergeConditions(identifyingConditions, nonIdentifyingConditions, absentConditions,
+		expressionConditions)
 
 	return allConditions
 }
 
 // IdentifyingAttributesSelectorToMatchConditions converts identifying attributes to MongoDB match conditions.
 func IdentifyingAttributesSelectorToMatchConditions(attributes map[string]string) []bson.M {
+	return attributesSelectorToMatchConditions(entity.IdentifyingAttributesFieldName, attributes, false)
+}
+
+// NonIdentifyingAttributesSelectorToMatchConditions converts non-identifying attributes to MongoDB match conditions.
+func NonIdentifyingAttributesSelectorToMatchConditions(attributes map[string]string) []bson.M {
+	return attributesSelectorToMatchConditions(entity.NonIdentifyingAttributesFieldName, attributes, false)
+}
+
+func attributesSelectorToMatchConditions(
+	fieldName string,
+	attributes map[string]string,
+	caseInsensitive bool,
+) []bson.M {
 	conditions := make([]bson.M, 0, len(attributes))
 	for key, value := range attributes {
 		conditions = append(conditions, bson.M{
-			entity.IdentifyingAttributesFieldName: bson.M{
+			fieldName: bson.M{
 				"$elemMatch": bson.M{
 					"key":   key,
-					"value": value,
+					"value": attributeValueCondition(value, caseInsensitive),
 				},
 			},
 		})
@@ -38,15 +74,33 @@ func IdentifyingAttributesSelectorToMatchConditions(attributes map[string]string
 	return conditions
 }
 
-// NonIdentifyingAttributesSelectorToMatchConditions converts non-identifying attributes to MongoDB match conditions.
-func NonIdentifyingAttributesSelectorToMatchConditions(attributes map[string]string) []bson.M {
-	conditions := make([]bson.M, 0, len(attributes))
-	for key, value := range attributes {
+// attributeValueCondition matches an attribute value. A case-insensitive match uses an
+// anchored regex rather than a collation: a collation applies to the whole query, so it
+// would also fold the namespace and continue-token comparisons, and it cannot ignore
+// surrounding whitespace.
+func attributeValueCondition(value string, caseInsensitive bool) any {
+	if !caseInsensitive {
+		return value
+	}
+
+	return bson.M{
+		"$regex":   `^\s*` + regexp.QuoteMeta(strings.TrimSpace(value)) + `\s*$`,
+		"$options": "i",
+	}
+}
+
+// AbsentAttributesSelectorToMatchConditions converts attribute keys that must not be reported
+// into MongoDB match conditions on the given attribute array field. A document without the
+// field at all also matches, like an agent that reported no attributes.
+func AbsentAttributesSelectorToMatchConditions(fieldName string, keys []string) []bson.M {
+	conditions := make([]bson.M, 0, len(keys))
+	for _, key := range keys {
 		conditions = append(conditions, bson.M{
-			entity.NonIdentifyingAttributesFieldName: bson.M{
-				"$elemMatch": bson.M{
-					"key":   key,
-					"value": value,
+			fieldName: bson.M{
+				"$not": bson.M{
+					"$elemMatch": bson.M{
+						"key": key,
+					},
 				},
 			},
 		})
@@ -55,6 +109,79 @@ func NonIdentifyingAttributesSelectorToMatchConditions(attributes map[string]str
 	return conditions
 }
 
+// AttributeExpressionsToMatchConditions converts match expressions into MongoDB match
+// conditions on the given attribute array field. Attribute values are stored as strings,
+// so a numeric comparison converts the value to a double first; a value that does not
+// parse converts to null and never matches. The null check is explicit because MongoDB
+// orders null before every number, which would make any unparseable value "less than".
+func AttributeExpressionsToMatchConditions(
+	fieldName string,
+	expressions []entity.AttributeMatchExpression,
+) []bson.M {
+	conditions := make([]bson.M, 0, len(expressions))
+
+	for _, expression := range expressions {
+		operator, ok := numericComparisonOperator(expression.Operator)
+		if !ok {
+			// An unknown operator matches no agent, like the domain selector.
+			conditions = append(conditions, bson.M{"$expr": false})
+
+			continue
+		}
+
+		conditions = append(conditions, bson.M{
+			"$expr": bson.M{
+				"$anyElementTrue": bson.A{bson.M{
+					"$map": bson.M{
+						"input": bson.M{
+							"$filter": bson.M{
+								"input": bson.M{"$ifNull": bson.A{"$" + fieldName, bson.A{}}},
+								"as":    "attribute",
+								"cond":  bson.M{"$eq": bson.A{"$$attribute.key", expression.Key}},
+							},
+						},
+						"as": "attribute",
+						"in": bson.M{
+							"$let": bson.M{
+								"vars": bson.M{
+									"number": bson.M{
+										"$convert": bson.M{
+											"input":   "$$attribute.value",
+											"to":      "double",
+											"onError": nil,
+											"onNull":  nil,
+										},
+									},
+								},
+								"in": bson.M{
+									"$and": bson.A{
+										bson.M{"$ne": bson.A{"$$number", nil}},
+										bson.M{operator: bson.A{"$$number", expression.Value}},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		})
+	}
+
+	return conditions
+}
+
+// numericComparisonOperator returns the MongoDB aggregation operator of a match expression operator.
+func numericComparisonOperator(operator string) (string, bool) {
+	switch agentmodel.AttributeMatchOperator(operator) {
+	case agentmodel.AttributeMatchOperatorGreaterThan:
+		return "$gt", true
+	case agentmodel.AttributeMatchOperatorLessThan:
+		return "$lt", true
+	default:
+		return "", false
+	}
+}
+
 func mergeConditions(conds ...[]bson.M) []bson.M {
 	return lo.FlatMap(conds, func(cond []bson.M, _ int) []bson.M {
 		return cond
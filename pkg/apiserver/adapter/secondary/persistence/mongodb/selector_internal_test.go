@@ -0,0 +1,34 @@
+package mongodb
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAttributeValueCondition(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Linux", attributeValueCondition("Linux", false))
+
+	condition, ok := attributeValueCondition(" Linux.x86 ", true).(bson.M)
+	require.True(t, ok)
+	assert.Equal(t, "i", condition["$options"])
+
+	pattern, ok := condition["$regex"].(string)
+	require.True(t, ok)
+
+	// The pattern is plain PCRE, so Go's regexp evaluates it the same way MongoDB does.
+	re := regexp.MustCompile("(?i)" + pattern)
+
+	for _, value := range []string{"linux.x86", "LINUX.X86", "  Linux.x86\t"} {
+		assert.True(t, re.MatchString(value), value)
+	}
+
+	for _, value := range []string{"linuxax86", "linux.x86_64", "gnu linux.x86"} {
+		assert.False(t, re.MatchString(value), value)
+	}
+}
@@ -0,0 +1,506 @@
+// Package persistencetest provides conformance suites shared by the persistence adapters,
+// so every backend is held to the same port contract instead of its own test coverage.
+package persistencetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// AgentRepositoryFactory returns an empty agent repository. It is called once per
+// subtest, so the subtests never see each other's agents.
+type AgentRepositoryFactory func(t *testing.T) agentport.AgentPersistencePort
+
+// RunAgentPersistenceSuite exercises the AgentPersistencePort contract against the
+// repositories newRepository returns: get/put/delete round trips, not-found and conflict
+// errors, namespace and selector filtering, and pagination through continue tokens.
+func RunAgentPersistenceSuite(t *testing.T, newRepository AgentRepositoryFactory) {
+	t.Helper()
+
+	t.Run("get of a missing agent is not found", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newRepository(t)
+
+		got, err := repo.GetAgent(t.Context(), uuid.New())
+		require.ErrorIs(t, err, model.ErrResourceNotExist)
+		assert.Nil(t, got)
+	})
+
+	t.Run("put then get round trips and bumps the resource version", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		agent := newAgent("default", map[string]string{"service.name": "web"})
+		agent.SetLabels(map[string]string{"region": "eu-west-1"})
+
+		require.NoError(t, repo.PutAgent(ctx, agent))
+		assert.Equal(t, int64(1), agent.Metadata.ResourceVersion)
+
+		got, err := repo.GetAgent(ctx, agent.Metadata.InstanceUID)
+		require.NoError(t, err)
+		assert.Equal(t, agent.Metadata.InstanceUID, got.Metadata.InstanceUID)
+		assert.Equal(t, "default", got.Metadata.Namespace)
+		assert.Equal(t, map[string]string{"service.name": "web"}, got.Metadata.Description.IdentifyingAttributes)
+		assert.Equal(t, map[string]string{"region": "eu-west-1"}, got.Metadata.Labels)
+		assert.Equal(t, int64(1), got.Metadata.ResourceVersion)
+
+		require.NoError(t, repo.PutAgent(ctx, got))
+		assert.Equal(t, int64(2), got.Metadata.ResourceVersion)
+	})
+
+	t.Run("put of a stale agent conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		agent := newAgent("default", nil)
+		require.NoError(t, repo.PutAgent(ctx, agent))
+
+		stale, err := repo.GetAgent(ctx, agent.Metadata.InstanceUID)
+		require.NoError(t, err)
+		require.NoError(t, repo.PutAgent(ctx, agent))
+
+		require.ErrorIs(t, repo.PutAgent(ctx, stale), model.ErrConflict)
+	})
+
+	t.Run("delete removes the agent and a second delete is not found", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		agent := newAgent("default", nil)
+		require.NoError(t, repo.PutAgent(ctx, agent))
+
+		require.NoError(t, repo.DeleteAgent(ctx, agent.Metadata.InstanceUID))
+
+		_, err := repo.GetAgent(ctx, agent.Metadata.InstanceUID)
+		require.ErrorIs(t, err, model.ErrResourceNotExist)
+		require.ErrorIs(t, repo.DeleteAgent(ctx, agent.Metadata.InstanceUID), model.ErrResourceNotExist)
+	})
+
+	t.Run("list is scoped to the namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		inNamespace := putAgents(t, repo, "ns-a", nil, 2)
+		putAgents(t, repo, "ns-b", nil, 1)
+
+		resp, err := repo.ListAgents(ctx, "ns-a", nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, inNamespace, instanceUIDs(resp.Items))
+		assert.Equal(t, int64(0), resp.RemainingItemCount)
+
+		resp, err = repo.ListAgents(ctx, "ns-empty", nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+	})
+
+	t.Run("list pages through every agent exactly once", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newRepository(t)
+		want := putAgents(t, repo, "default", nil, 5)
+
+		got := collectPages(t, func(options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error) {
+			return repo.ListAgents(t.Context(), "default", options)
+		}, 2, []int64{3, 1, 0})
+		assert.ElementsMatch(t, want, got)
+	})
+
+	t.Run("list filters by attributes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		web := putAgents(t, repo, "default", map[string]string{"service.name": "web"}, 2)
+		putAgents(t, repo, "default", map[string]string{"service.name": "db"}, 1)
+
+		//exhaustruct:ignore
+		resp, err := repo.ListAgents(ctx, "default", &model.ListOptions{
+			IdentifyingAttributes: map[string]string{"service.name": "web"},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, web, instanceUIDs(resp.Items))
+	})
+
+	t.Run("list by selector matches across namespaces", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		web := putAgents(t, repo, "ns-a", map[string]string{"service.name": "web"}, 1)
+		web = append(web, putAgents(t, repo, "ns-b", map[string]string{"service.name": "web"}, 1)...)
+		putAgents(t, repo, "ns-a", map[string]string{"service.name": "db"}, 1)
+
+		//exhaustruct:ignore
+		resp, err := repo.ListAgentsBySelector(ctx, agentmodel.AgentSelector{
+			IdentifyingAttributes: map[string]string{"service.name": "web"},
+		}, nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, web, instanceUIDs(resp.Items))
+	})
+
+	t.Run("list by selector matches values exactly unless case-insensitive", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		web := putAgents(t, repo, "default", map[string]string{"service.name": "Web "}, 1)
+		web = append(web, putAgents(t, repo, "default", map[string]string{"service.name": "web"}, 1)...)
+		putAgents(t, repo, "default", map[string]string{"service.name": "web.1"}, 1)
+
+		//exhaustruct:ignore
+		selector := agentmodel.AgentSelector{IdentifyingAttributes: map[string]string{"service.name": "WEB"}}
+
+		resp, err := repo.ListAgentsBySelector(ctx, selector, nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+
+		selector.CaseInsensitive = true
+
+		resp, err = repo.ListAgentsBySelector(ctx, selector, nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, web, instanceUIDs(resp.Items))
+	})
+
+	t.Run("list by selector pages through every match exactly once", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newRepository(t)
+		want := putAgents(t, repo, "default", map[string]string{"service.name": "web"}, 5)
+		putAgents(t, repo, "default", map[string]string{"service.name": "db"}, 2)
+
+		//exhaustruct:ignore
+		selector := agentmodel.AgentSelector{IdentifyingAttributes: map[string]string{"service.name": "web"}}
+
+		got := collectPages(t, func(options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error) {
+			return repo.ListAgentsBySelector(t.Context(), selector, options)
+		}, 2, []int64{3, 1, 0})
+		assert.ElementsMatch(t, want, got)
+	})
+
+	t.Run("list by package matches the reported package version", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		oldCollectors := putAgentsWithPackage(t, repo, "default", "otelcol.contrib", "0.114.0", 2)
+		newCollectors := putAgentsWithPackage(t, repo, "default", "otelcol.contrib", "0.115.0", 1)
+		putAgentsWithPackage(t, repo, "default", "fluent-bit", "0.114.0", 1)
+		putAgentsWithPackage(t, repo, "other", "otelcol.contrib", "0.114.0", 1)
+		putAgents(t, repo, "default", nil, 1)
+
+		resp, err := repo.ListAgentsByPackage(ctx, "default", "otelcol.contrib", "0.114.0", nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, oldCollectors, instanceUIDs(resp.Items))
+
+		resp, err = repo.ListAgentsByPackage(ctx, "default", "otelcol.contrib", "", nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, append(oldCollectors, newCollectors...), instanceUIDs(resp.Items))
+
+		resp, err = repo.ListAgentsByPackage(ctx, "default", "otelcol", "0.114.0", nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+
+		got := collectPages(t, func(options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error) {
+			return repo.ListAgentsByPackage(t.Context(), "default", "otelcol.contrib", "", options)
+		}, 2, []int64{1, 0})
+		assert.ElementsMatch(t, append(oldCollectors, newCollectors...), got)
+	})
+
+	t.Run("list attributes returns the distinct keys and capped values", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+		putAgents(t, repo, "default", map[string]string{"service.name": "web", "host.name": "a"}, 2)
+		putAgents(t, repo, "default", map[string]string{"service.name": "db"}, 1)
+		putAgents(t, repo, "other", map[string]string{"service.name": "cache", "host.name": "b"}, 1)
+
+		agent := newAgent("default", nil)
+		agent.Metadata.Description.NonIdentifyingAttributes = map[string]string{"os.type": "linux"}
+		require.NoError(t, repo.PutAgent(ctx, agent))
+
+		catalog, err := repo.ListAgentAttributes(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []agentmodel.AttributeCatalogEntry{
+			{Key: "host.name", Values: []string{"a", "b"}},
+			{Key: "service.name", Values: []string{"cache", "db"}, ValuesTruncated: true},
+		}, catalog.IdentifyingAttributes)
+		assert.Equal(t, []agentmodel.AttributeCatalogEntry{
+			{Key: "os.type", Values: []string{"linux"}},
+		}, catalog.NonIdentifyingAttributes)
+
+		catalog, err = repo.ListAgentAttributes(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host.name", "service.name"},
+			lo.Map(catalog.IdentifyingAttributes, func(entry agentmodel.AttributeCatalogEntry, _ int) string {
+				assert.Empty(t, entry.Values)
+				assert.False(t, entry.ValuesTruncated)
+
+				return entry.Key
+			}))
+	})
+
+	t.Run("fleet summary counts agents by state", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+
+		summary, err := repo.GetAgentFleetSummary(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &agentmodel.AgentFleetSummary{}, summary)
+
+		now := time.Now()
+		startedAt := now.Add(-time.Hour)
+		newAgentInState := func(namespace string, connected, healthy bool) *agentmodel.Agent {
+			agent := newAgent(namespace, nil)
+			agent.Status.Connected = connected
+			agent.Status.LastReportedAt = now
+			agent.Status.ComponentHealth.Healthy = healthy
+			agent.Status.ComponentHealth.StartTime = startedAt
+
+			return agent
+		}
+
+		healthy := newAgentInState("default", true, true)
+		unhealthy := newAgentInState("other", true, false)
+		withPendingReport := newAgentInState("default", true, true)
+		withPendingReport.Spec.PendingReports = []agentmodel.AgentReportKind{agentmodel.AgentReportKindHealth}
+		disconnectedWithPendingRestart := newAgentInState("default", false, true)
+		disconnectedWithPendingRestart.Spec.RestartInfo = &agentmodel.AgentRestartInfo{RequiredRestartedAt: now}
+		restarted := newAgentInState("default", true, false)
+		restarted.Spec.RestartInfo = &agentmodel.AgentRestartInfo{RequiredRestartedAt: startedAt.Add(-time.Minute)}
+		stale := newAgentInState("default", true, true)
+		stale.Status.LastReportedAt = now.Add(-2 * agentmodel.DefaultConnectionStaleness)
+
+		for _, agent := range []*agentmodel.Agent{
+			healthy, unhealthy, withPendingReport, disconnectedWithPendingRestart, restarted, stale,
+		} {
+			require.NoError(t, repo.PutAgent(ctx, agent))
+		}
+
+		summary, err = repo.GetAgentFleetSummary(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &agentmodel.AgentFleetSummary{
+			NumAgents:                    6,
+			NumConnectedAgents:           4,
+			NumHealthyAgents:             2,
+			NumUnhealthyAgents:           2,
+			NumAgentsWithPendingCommands: 2,
+		}, summary)
+	})
+
+	t.Run("command queue summary totals pending commands", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+
+		summary, err := repo.GetCommandQueueSummary(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &agentmodel.CommandQueueSummary{}, summary)
+
+		now := time.Now()
+		newAgentWithCommands := func(reports []agentmodel.AgentReportKind, restart bool) *agentmodel.Agent {
+			agent := newAgent("default", nil)
+			agent.Status.ComponentHealth.StartTime = now.Add(-time.Hour)
+			agent.Spec.PendingReports = reports
+
+			if restart {
+				agent.Spec.RestartInfo = &agentmodel.AgentRestartInfo{RequiredRestartedAt: now}
+			}
+
+			return agent
+		}
+
+		for _, agent := range []*agentmodel.Agent{
+			newAgentWithCommands(nil, false),
+			newAgentWithCommands([]agentmodel.AgentReportKind{agentmodel.AgentReportKindHealth}, false),
+			newAgentWithCommands([]agentmodel.AgentReportKind{agentmodel.AgentReportKindHealth}, true),
+			newAgentWithCommands([]agentmodel.AgentReportKind{
+				agentmodel.AgentReportKindHealth, agentmodel.AgentReportKindEffectiveConfig,
+			}, true),
+		} {
+			require.NoError(t, repo.PutAgent(ctx, agent))
+		}
+
+		summary, err = repo.GetCommandQueueSummary(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &agentmodel.CommandQueueSummary{NumPendingCommands: 6, NumFullQueues: 2}, summary)
+
+		// Unbounded queues are never full.
+		summary, err = repo.GetCommandQueueSummary(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, &agentmodel.CommandQueueSummary{NumPendingCommands: 6, NumFullQueues: 0}, summary)
+	})
+
+	t.Run("list by connection lost returns the agents in an elapsed grace period", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		repo := newRepository(t)
+
+		// MongoDB stores milliseconds, so the times are truncated to round trip exactly.
+		now := time.Now().Truncate(time.Millisecond)
+		lostBy := now.Add(-time.Minute)
+		newAgentLostAt := func(connected bool, lostAt time.Time) *agentmodel.Agent {
+			agent := newAgent("default", nil)
+			agent.Status.Connected = connected
+			agent.Status.LastReportedAt = now
+			agent.Status.ConnectionLostAt = lostAt
+
+			return agent
+		}
+
+		lostAtBoundary := newAgentLostAt(true, lostBy)
+		lostEarlier := newAgentLostAt(true, lostBy.Add(-time.Minute))
+		lostLater := newAgentLostAt(true, lostBy.Add(time.Second))
+		reconnected := newAgentLostAt(true, time.Time{})
+		disconnected := newAgentLostAt(false, lostBy.Add(-time.Minute))
+
+		for _, agent := range []*agentmodel.Agent{lostAtBoundary, lostEarlier, lostLater, reconnected, disconnected} {
+			require.NoError(t, repo.PutAgent(ctx, agent))
+		}
+
+		agents, err := repo.ListAgentsConnectionLostBy(ctx, lostBy, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{lostEarlier.Metadata.InstanceUID, lostAtBoundary.Metadata.InstanceUID},
+			instanceUIDs(agents), "the longest-lost agents come first")
+
+		agents, err = repo.ListAgentsConnectionLostBy(ctx, lostBy, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{lostEarlier.Metadata.InstanceUID}, instanceUIDs(agents))
+	})
+
+	t.Run("list rejects a malformed continue token", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newRepository(t)
+		putAgents(t, repo, "default", nil, 1)
+
+		//exhaustruct:ignore
+		_, err := repo.ListAgents(t.Context(), "default", &model.ListOptions{Limit: 1, Continue: "not-a-token"})
+		require.Error(t, err)
+	})
+}
+
+func newAgent(namespace string, identifyingAttributes map[string]string) *agentmodel.Agent {
+	agent := agentmodel.NewAgent(uuid.New(), agentmodel.WithNamespace(namespace))
+	agent.Metadata.Description.IdentifyingAttributes = identifyingAttributes
+
+	return agent
+}
+
+// putAgents stores count agents in namespace with the given identifying attributes and
+// returns their instance UIDs.
+func putAgents(
+	t *testing.T,
+	repo agentport.AgentPersistencePort,
+	namespace string,
+	identifyingAttributes map[string]string,
+	count int,
+) []uuid.UUID {
+	t.Helper()
+
+	uids := make([]uuid.UUID, 0, count)
+
+	for range count {
+		agent := newAgent(namespace, identifyingAttributes)
+		require.NoError(t, repo.PutAgent(t.Context(), agent))
+
+		uids = append(uids, agent.Metadata.InstanceUID)
+	}
+
+	return uids
+}
+
+// putAgentsWithPackage stores count agents in namespace that report the named package at
+// version and returns their instance UIDs.
+func putAgentsWithPackage(
+	t *testing.T,
+	repo agentport.AgentPersistencePort,
+	namespace string,
+	packageName string,
+	version string,
+	count int,
+) []uuid.UUID {
+	t.Helper()
+
+	uids := make([]uuid.UUID, 0, count)
+
+	for range count {
+		agent := newAgent(namespace, nil)
+		//exhaustruct:ignore
+		agent.Status.PackageStatuses.Packages = map[string]agentmodel.AgentPackageStatusEntry{
+			packageName: {Name: packageName, AgentHasVersion: version},
+		}
+		require.NoError(t, repo.PutAgent(t.Context(), agent))
+
+		uids = append(uids, agent.Metadata.InstanceUID)
+	}
+
+	return uids
+}
+
+// collectPages follows continue tokens with the given page size until no item remains,
+// checking RemainingItemCount after every page against wantRemaining, and returns the
+// instance UIDs of every listed agent. Following the token of the last page lists nothing.
+func collectPages(
+	t *testing.T,
+	list func(options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error),
+	limit int64,
+	wantRemaining []int64,
+) []uuid.UUID {
+	t.Helper()
+
+	var (
+		uids          []uuid.UUID
+		continueToken string
+	)
+
+	for page, remaining := range wantRemaining {
+		//exhaustruct:ignore
+		resp, err := list(&model.ListOptions{Limit: limit, Continue: continueToken})
+		require.NoError(t, err, "page %d", page)
+		require.Equal(t, remaining, resp.RemainingItemCount, "remaining items after page %d", page)
+		require.LessOrEqual(t, int64(len(resp.Items)), limit, "items on page %d", page)
+
+		uids = append(uids, instanceUIDs(resp.Items)...)
+		continueToken = resp.Continue
+
+		if remaining > 0 {
+			require.NotEmpty(t, continueToken, "continue token of page %d", page)
+		}
+	}
+
+	if continueToken != "" {
+		//exhaustruct:ignore
+		resp, err := list(&model.ListOptions{Limit: limit, Continue: continueToken})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items, "the token of the last page lists nothing")
+	}
+
+	assert.Len(t, lo.Uniq(uids), len(uids), "an agent was listed twice")
+
+	return uids
+}
+
+func instanceUIDs(agents []*agentmodel.Agent) []uuid.UUID {
+	return lo.Map(agents, func(agent *agentmodel.Agent, _ int) uuid.UUID {
+		return agent.Metadata.InstanceUID
+	})
+}
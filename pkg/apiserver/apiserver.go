@@ -6,6 +6,8 @@
 package apiserver
 
 import (
+	"context"
+
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/app"
 )
@@ -18,6 +20,13 @@ func New(settings config.ServerSettings) *Server {
 	return app.New(settings)
 }
 
+// Bootstrap applies the manifests in file (e.g. Agent documents registering agents
+// before they connect) to the store configured by settings, then returns.
+func Bootstrap(ctx context.Context, settings config.ServerSettings, file string) error {
+	//nolint:wrapcheck // thin pass-through to the composition root's Bootstrap
+	return app.Bootstrap(ctx, settings, file)
+}
+
 // VisualizeError renders an FX dependency-graph error into a human-readable form,
 // so callers can pretty-print startup failures without importing FX directly.
 func VisualizeError(err error) (string, error) {
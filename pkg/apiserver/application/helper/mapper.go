@@ -2,7 +2,11 @@
 package helper
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +20,34 @@ import (
 	usermodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user"
 )
 
+// ErrMalformedInput is returned when an API model carries a value that cannot be
+// converted to its domain representation (e.g. an instance UID that is not a
+// UUID). It wraps model.ErrInvalidArgument so the HTTP layer maps it to a 400.
+var ErrMalformedInput = fmt.Errorf("malformed input: %w", model.ErrInvalidArgument)
+
+// ConversionError describes a single API field that failed to convert to its
+// domain representation. It matches ErrMalformedInput via errors.Is, and also
+// exposes the underlying parse error.
+type ConversionError struct {
+	// Field is the JSON path of the offending field (e.g. "spec.newInstanceUid").
+	Field string
+	// Value is the raw value supplied by the client.
+	Value string
+	// Err is the underlying parse error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Value, e.Err)
+}
+
+// Unwrap returns both the sentinel and the underlying cause so errors.Is works
+// against ErrMalformedInput (and model.ErrInvalidArgument) as well as the cause.
+func (e *ConversionError) Unwrap() []error {
+	return []error{ErrMalformedInput, e.Err}
+}
+
 // Mapper is a struct that provides methods to map between domain models and API models.
 //
 // The injected clock is consulted for time-sensitive derivations (e.g. evaluating
@@ -25,9 +57,12 @@ import (
 // connectionStaleness controls how long after an agent's LastReportedAt the
 // API still treats it as connected. Operators tuning the OpAMP heartbeat
 // interval should pass a value at least 2-3× their interval.
+//
+// defaultConfigContentType is reported for config files stored without a content type.
 type Mapper struct {
-	clock               clock.PassiveClock
-	connectionStaleness time.Duration
+	clock                    clock.PassiveClock
+	connectionStaleness      time.Duration
+	defaultConfigContentType string
 }
 
 // NewMapper creates a new instance of Mapper. A nil clock falls back to
@@ -43,7 +78,13 @@ func NewMapper(clk clock.PassiveClock, connectionStaleness time.Duration) *Mappe
 		connectionStaleness = agentmodel.DefaultConnectionStaleness
 	}
 
-	return &Mapper{clock: clk, connectionStaleness: connectionStaleness}
+	return &Mapper{clock: clk, connectionStaleness: connectionStaleness, defaultConfigContentType: TextYAML}
+}
+
+// SetDefaultConfigContentType sets the content type reported for config files stored
+// without one. The default is TextYAML.
+func (mapper *Mapper) SetDefaultConfigContentType(contentType string) {
+	mapper.defaultConfigContentType = contentType
 }
 
 // MapAPIToAgentGroup maps an API model AgentGroup to a domain model AgentGroup.
@@ -84,18 +125,30 @@ func (mapper *Mapper) MapAPIToAgentGroup(apiAgentGroup *v1.AgentGroup) *agentmod
 			Attributes: agentmodel.OfAttributes(apiAgentGroup.Metadata.Attributes),
 		},
 		Spec: agentmodel.AgentGroupSpec{
-			Priority: apiAgentGroup.Spec.Priority,
-			Selector: agentmodel.AgentSelector{
-				IdentifyingAttributes:    apiAgentGroup.Spec.Selector.IdentifyingAttributes,
-				NonIdentifyingAttributes: apiAgentGroup.Spec.Selector.NonIdentifyingAttributes,
-			},
-			AgentRemoteConfigs:    agentRemoteConfigs,
-			AgentConnectionConfig: agentConnectionConfig,
+			Priority:                apiAgentGroup.Spec.Priority,
+			Selector:                mapper.MapAPIToAgentSelector(&apiAgentGroup.Spec.Selector),
+			AgentRemoteConfigs:      agentRemoteConfigs,
+			AgentConnectionConfig:   agentConnectionConfig,
+			Rollout:                 mapAgentGroupRolloutFromAPI(apiAgentGroup.Spec.Rollout),
+			ExcludeBaseRemoteConfig: apiAgentGroup.Spec.ExcludeBaseRemoteConfig,
 		},
 		// Note: Status is not mapped here as it is usually managed by the system.
 	}
 }
 
+// MapAPIToAgentSelector converts an API AgentSelector to the domain model.
+func (mapper *Mapper) MapAPIToAgentSelector(selector *v1.AgentSelector) agentmodel.AgentSelector {
+	return agentmodel.AgentSelector{
+		IdentifyingAttributes:          selector.IdentifyingAttributes,
+		NonIdentifyingAttributes:       selector.NonIdentifyingAttributes,
+		AbsentIdentifyingAttributes:    selector.AbsentIdentifyingAttributes,
+		AbsentNonIdentifyingAttributes: selector.AbsentNonIdentifyingAttributes,
+		IdentifyingMatchExpressions:    mapAttributeMatchExpressionsFromAPI(selector.IdentifyingMatchExpressions),
+		NonIdentifyingMatchExpressions: mapAttributeMatchExpressionsFromAPI(selector.NonIdentifyingMatchExpressions),
+		CaseInsensitive:                selector.CaseInsensitive,
+	}
+}
+
 // MapAgentGroupToAPI maps a domain model AgentGroup to an API model AgentGroup.
 func (mapper *Mapper) MapAgentGroupToAPI(domainAgentGroup *agentmodel.AgentGroup) *v1.AgentGroup {
 	if domainAgentGroup == nil {
@@ -112,15 +165,25 @@ func (mapper *Mapper) MapAgentGroupToAPI(domainAgentGroup *agentmodel.AgentGroup
 			Name:       domainAgentGroup.Metadata.Name,
 			CreatedAt:  v1.NewTime(domainAgentGroup.Metadata.CreatedAt),
 			DeletedAt:  mapDeletedAtToAPI(domainAgentGroup.Metadata.DeletedAt),
+			DeletedBy:  model.DeletedBy(domainAgentGroup.Status.Conditions),
 			Attributes: v1.Attributes(domainAgentGroup.Metadata.Attributes),
 		},
 		Spec: v1.Spec{
 			Priority: domainAgentGroup.Spec.Priority,
 			Selector: v1.AgentSelector{
-				IdentifyingAttributes:    domainAgentGroup.Spec.Selector.IdentifyingAttributes,
-				NonIdentifyingAttributes: domainAgentGroup.Spec.Selector.NonIdentifyingAttributes,
+				IdentifyingAttributes:          domainAgentGroup.Spec.Selector.IdentifyingAttributes,
+				NonIdentifyingAttributes:       domainAgentGroup.Spec.Selector.NonIdentifyingAttributes,
+				AbsentIdentifyingAttributes:    domainAgentGroup.Spec.Selector.AbsentIdentifyingAttributes,
+				AbsentNonIdentifyingAttributes: domainAgentGroup.Spec.Selector.AbsentNonIdentifyingAttributes,
+				IdentifyingMatchExpressions: mapAttributeMatchExpressionsToAPI(
+					domainAgentGroup.Spec.Selector.IdentifyingMatchExpressions),
+				NonIdentifyingMatchExpressions: mapAttributeMatchExpressionsToAPI(
+					domainAgentGroup.Spec.Selector.NonIdentifyingMatchExpressions),
+				CaseInsensitive: domainAgentGroup.Spec.Selector.CaseInsensitive,
 			},
-			AgentConfig: agentConfig,
+			AgentConfig:             agentConfig,
+			Rollout:                 mapAgentGroupRolloutToAPI(domainAgentGroup.Spec.Rollout),
+			ExcludeBaseRemoteConfig: domainAgentGroup.Spec.ExcludeBaseRemoteConfig,
 		},
 		Status: v1.Status{
 			NumAgents:             domainAgentGroup.Status.NumAgents,
@@ -134,60 +197,75 @@ func (mapper *Mapper) MapAgentGroupToAPI(domainAgentGroup *agentmodel.AgentGroup
 }
 
 // MapAPIToAgent maps an API model Agent to a domain model Agent.
-func (mapper *Mapper) MapAPIToAgent(apiAgent *v1.Agent) *agentmodel.Agent {
+// It returns a *ConversionError when a field is malformed, rather than
+// silently substituting a zero value.
+func (mapper *Mapper) MapAPIToAgent(apiAgent *v1.Agent) (*agentmodel.Agent, error) {
+	newInstanceUID, err := mapper.mapNewInstanceUIDFromAPI(apiAgent.Spec.NewInstanceUID)
+	if err != nil {
+		return nil, err
+	}
+
 	//exhaustruct:ignore
 	return &agentmodel.Agent{
 		Metadata: agentmodel.AgentMetadata{
 			InstanceUID: apiAgent.Metadata.InstanceUID,
 			Namespace:   apiAgent.Metadata.Namespace,
 			Description: agent.Description{
-				IdentifyingAttributes:    apiAgent.Metadata.Description.IdentifyingAttributes,
-				NonIdentifyingAttributes: apiAgent.Metadata.Description.NonIdentifyingAttributes,
+				IdentifyingAttributes:       apiAgent.Metadata.Description.IdentifyingAttributes,
+				NonIdentifyingAttributes:    apiAgent.Metadata.Description.NonIdentifyingAttributes,
+				RawIdentifyingAttributes:    apiAgent.Metadata.Description.RawIdentifyingAttributes,
+				RawNonIdentifyingAttributes: apiAgent.Metadata.Description.RawNonIdentifyingAttributes,
+				AttributesTruncated:         apiAgent.Metadata.Description.AttributesTruncated,
 			},
 			Capabilities:       agent.Capabilities(apiAgent.Metadata.Capabilities),
 			CustomCapabilities: mapper.mapCustomCapabilitiesFromAPI(&apiAgent.Metadata.CustomCapabilities),
 		},
 		//exhaustruct:ignore
 		Spec: agentmodel.AgentSpec{
-			NewInstanceUID:    mapper.mapNewInstanceUIDFromAPI(apiAgent.Spec.NewInstanceUID),
+			NewInstanceUID:    newInstanceUID,
 			RemoteConfig:      mapper.mapRemoteConfigFromAPI(&apiAgent.Spec.RemoteConfig),
 			PackagesAvailable: mapper.mapPackagesAvailableFromAPI(&apiAgent.Spec.PackagesAvailable),
 			RestartInfo:       mapper.mapRestartInfoFromAPI(apiAgent.Spec.RestartRequiredAt),
 		},
 		// Note: Status is not mapped here as it is usually managed by the system.
-	}
+	}, nil
 }
 
 // MapAgentToAPI maps a domain model Agent to an API model Agent.
 func (mapper *Mapper) MapAgentToAPI(agent *agentmodel.Agent) *v1.Agent {
+	now := mapper.clock.Now()
+
 	return &v1.Agent{
+		Kind:       v1.AgentKind,
+		APIVersion: v1.APIVersion,
 		Metadata: v1.AgentMetadata{
 			InstanceUID: agent.Metadata.InstanceUID,
 			Namespace:   agent.Metadata.Namespace,
 			Type:        string(agent.Metadata.Description.AgentType()),
 			Description: v1.AgentDescription{
-				IdentifyingAttributes:    agent.Metadata.Description.IdentifyingAttributes,
-				NonIdentifyingAttributes: agent.Metadata.Description.NonIdentifyingAttributes,
+				IdentifyingAttributes:       agent.Metadata.Description.IdentifyingAttributes,
+				NonIdentifyingAttributes:    agent.Metadata.Description.NonIdentifyingAttributes,
+				RawIdentifyingAttributes:    agent.Metadata.Description.RawIdentifyingAttributes,
+				RawNonIdentifyingAttributes: agent.Metadata.Description.RawNonIdentifyingAttributes,
+				AttributesTruncated:         agent.Metadata.Description.AttributesTruncated,
 			},
 			Capabilities:       v1.AgentCapabilities(agent.Metadata.Capabilities),
 			CustomCapabilities: mapper.mapCustomCapabilitiesToAPI(&agent.Metadata.CustomCapabilities),
+			Labels:             agent.Metadata.Labels,
 		},
 		//exhaustruct:ignore
 		Spec: v1.AgentSpec{
-			NewInstanceUID:    mapper.mapNewInstanceUIDToAPI(agent.Spec.NewInstanceUID[:]),
-			RemoteConfig:      mapper.mapRemoteConfigToAPI(agent.Spec.RemoteConfig),
-			PackagesAvailable: mapper.mapPackagesAvailableToAPI(agent.Spec.PackagesAvailable),
-			RestartRequiredAt: mapper.mapRestartRequiredAtToAPI(agent.Spec.RestartInfo),
+			NewInstanceUID:     mapper.mapNewInstanceUIDToAPI(agent.Spec.NewInstanceUID[:]),
+			RemoteConfig:       mapper.mapRemoteConfigToAPI(agent.Spec.RemoteConfig),
+			PackagesAvailable:  mapper.mapPackagesAvailableToAPI(agent.Spec.PackagesAvailable),
+			RestartRequiredAt:  mapper.mapRestartRequiredAtToAPI(agent.Spec.RestartInfo),
+			Quarantine:         mapQuarantineToAPI(agent.Spec.Quarantine),
+			ExpectedAttributes: maps.Clone(agent.Spec.ExpectedAttributes),
+			PendingReports:     mapPendingReportsToAPI(agent.Spec.PendingReports),
 		},
 		Status: v1.AgentStatus{
-			EffectiveConfig: v1.AgentEffectiveConfig{
-				ConfigMap: v1.AgentConfigMap{
-					ConfigMap: lo.MapValues(agent.Status.EffectiveConfig.ConfigMap.ConfigMap,
-						func(value agentmodel.AgentConfigFile, _ string) v1.AgentConfigFile {
-							return mapper.mapConfigFileToAPI(value)
-						}),
-				},
-			},
+			EffectiveConfig:           mapper.mapEffectiveConfigToAPI(agent.Status.EffectiveConfig),
+			EffectiveConfigReportedAt: mapper.formatTime(agent.Status.EffectiveConfigReportedAt),
 			PackageStatuses: v1.AgentPackageStatuses{
 				Packages: lo.MapValues(agent.Status.PackageStatuses.Packages,
 					func(value agentmodel.AgentPackageStatusEntry, _ string) v1.AgentStatusPackageEntry {
@@ -201,17 +279,78 @@ func (mapper *Mapper) MapAgentToAPI(agent *agentmodel.Agent) *v1.Agent {
 			ComponentHealth:     mapper.mapComponentHealthToAPI(&agent.Status.ComponentHealth),
 			AvailableComponents: mapper.mapAvailableComponentsToAPI(&agent.Status.AvailableComponents),
 			Conditions:          mapper.mapAgentConditionsToAPI(agent.Status.Conditions),
+			ConfigInSync:        agent.IsConfigInSync(),
 			// Derive effective connectedness from heartbeat staleness so HTTP-polling
 			// agents that stop polling are reported as disconnected, even though the
 			// stored Status.Connected flag is only flipped on WebSocket close.
-			Connected:      agent.IsConnectedAt(mapper.clock.Now(), mapper.connectionStaleness),
-			ConnectionType: agent.Status.ConnectionType.String(),
-			SequenceNum:    agent.Status.SequenceNum,
-			LastReportedAt: mapper.formatTime(agent.Status.LastReportedAt),
+			Connected:        agent.IsConnectedAt(now, mapper.connectionStaleness),
+			ConnectionState:  string(agent.ConnectionStateAt(now, mapper.connectionStaleness)),
+			ConnectionLostAt: mapper.formatTime(agent.Status.ConnectionLostAt),
+			ConnectionType:   agent.Status.ConnectionType.String(),
+			SequenceNum:      agent.Status.SequenceNum,
+			FirstSeenAt:      mapper.formatTime(agent.Status.FirstSeenAt),
+			LastReportedAt:   mapper.formatTime(agent.Status.LastReportedAt),
 		},
 	}
 }
 
+// MapAgentUptimeToAPI maps an agent's domain uptime view to the API model.
+func (mapper *Mapper) MapAgentUptimeToAPI(instanceUID uuid.UUID, uptime agentmodel.AgentUptime) *v1.AgentUptime {
+	const percent = 100
+
+	return &v1.AgentUptime{
+		InstanceUID:            instanceUID,
+		Connected:              uptime.Connected,
+		ConnectedSince:         v1.NewTime(uptime.ConnectedSince),
+		LastDisconnectedAt:     v1.NewTime(uptime.LastDisconnectedAt),
+		TotalConnectedSeconds:  uptime.TotalConnectedDuration.Seconds(),
+		DisconnectCount:        uptime.DisconnectCount,
+		WindowSeconds:          uptime.Window.Seconds(),
+		WindowConnectedSeconds: uptime.WindowConnectedDuration.Seconds(),
+		WindowUptimePercentage: uptime.WindowUptimeRatio * percent,
+	}
+}
+
+// MapAgentRevocationToAPI maps a domain agent revocation to the API model.
+func (mapper *Mapper) MapAgentRevocationToAPI(revocation *agentmodel.AgentRevocation) *v1.AgentRevocation {
+	return &v1.AgentRevocation{
+		InstanceUID: revocation.InstanceUID,
+		Reason:      revocation.Reason,
+		RevokedBy:   revocation.RevokedBy,
+		RevokedAt:   v1.NewTime(revocation.RevokedAt),
+	}
+}
+
+// MapAgentEffectiveConfigHistoryToAPI maps an agent's effective-config history to the API model.
+func (mapper *Mapper) MapAgentEffectiveConfigHistoryToAPI(
+	instanceUID uuid.UUID,
+	history []agentmodel.EffectiveConfigSnapshot,
+) *v1.AgentEffectiveConfigHistory {
+	items := make([]v1.AgentEffectiveConfigSnapshot, len(history))
+	for i, snapshot := range history {
+		items[i] = v1.AgentEffectiveConfigSnapshot{
+			ReportedAt:      v1.NewTime(snapshot.ReportedAt),
+			Hash:            hex.EncodeToString(snapshot.Hash),
+			Truncated:       snapshot.Truncated,
+			EffectiveConfig: mapper.mapEffectiveConfigToAPI(snapshot.Config),
+		}
+	}
+
+	return &v1.AgentEffectiveConfigHistory{
+		InstanceUID: instanceUID,
+		Items:       items,
+	}
+}
+
+// MapAgentEffectiveConfigToAPI maps an agent's reported effective config to the API model.
+func (mapper *Mapper) MapAgentEffectiveConfigToAPI(
+	effectiveConfig agentmodel.AgentEffectiveConfig,
+) *v1.AgentEffectiveConfig {
+	mapped := mapper.mapEffectiveConfigToAPI(effectiveConfig)
+
+	return &mapped
+}
+
 // MapAgentPackageToAPI maps a domain model AgentPackage to an API model AgentPackage.
 func (mapper *Mapper) MapAgentPackageToAPI(agentPackage *agentmodel.AgentPackage) *v1.AgentPackage {
 	var deletedAt *v1.Time
@@ -230,6 +369,7 @@ func (mapper *Mapper) MapAgentPackageToAPI(agentPackage *agentmodel.AgentPackage
 			Attributes: v1.Attributes(agentPackage.Metadata.Attributes),
 			CreatedAt:  v1.NewTime(agentPackage.Metadata.CreatedAt),
 			DeletedAt:  deletedAt,
+			DeletedBy:  model.DeletedBy(agentPackage.Status.Conditions),
 		},
 		Spec: v1.AgentPackageSpec{
 			PackageType: agentPackage.Spec.PackageType,
@@ -290,6 +430,7 @@ func (mapper *Mapper) MapCertificateToAPI(domain *agentmodel.Certificate) *v1.Ce
 			Attributes: v1.Attributes(domain.Metadata.Attributes),
 			CreatedAt:  v1.NewTime(domain.Metadata.CreatedAt),
 			DeletedAt:  mapDeletedAtToAPI(domain.Metadata.DeletedAt),
+			DeletedBy:  model.DeletedBy(domain.Status.Conditions),
 		},
 		Spec: v1.CertificateSpec{
 			Cert:       string(domain.Spec.Cert),
@@ -348,6 +489,8 @@ func (mapper *Mapper) MapAgentRemoteConfigToAPI(
 			Namespace:  domain.Metadata.Namespace,
 			Attributes: v1.Attributes(domain.Metadata.Attributes),
 			CreatedAt:  v1.NewTime(domain.Metadata.CreatedAt),
+			DeletedAt:  mapDeletedAtPtrToAPI(domain.Metadata.DeletedAt),
+			DeletedBy:  model.DeletedBy(domain.Status.Conditions),
 		},
 		Spec: v1.AgentRemoteConfigSpec{
 			Value:       string(domain.Spec.Value),
@@ -385,6 +528,20 @@ func (mapper *Mapper) MapAPIToAgentRemoteConfig(
 	}
 }
 
+// MapAPIToAgentConfigFiles maps an API model AgentConfigMap to domain config files keyed by name.
+func (mapper *Mapper) MapAPIToAgentConfigFiles(api *v1.AgentConfigMap) map[string]agentmodel.AgentConfigFile {
+	if api == nil {
+		return map[string]agentmodel.AgentConfigFile{}
+	}
+
+	return lo.MapValues(api.ConfigMap, func(configFile v1.AgentConfigFile, _ string) agentmodel.AgentConfigFile {
+		return agentmodel.AgentConfigFile{
+			Body:        []byte(configFile.Body),
+			ContentType: configFile.ContentType,
+		}
+	})
+}
+
 // MapEndpointToAPI maps a domain model Endpoint to an API model.
 func (mapper *Mapper) MapEndpointToAPI(
 	domain *agentmodel.Endpoint,
@@ -401,6 +558,8 @@ func (mapper *Mapper) MapEndpointToAPI(
 			Namespace:  domain.Metadata.Namespace,
 			Attributes: v1.Attributes(domain.Metadata.Attributes),
 			CreatedAt:  v1.NewTime(domain.Metadata.CreatedAt),
+			DeletedAt:  mapDeletedAtPtrToAPI(domain.Metadata.DeletedAt),
+			DeletedBy:  model.DeletedBy(domain.Status.Conditions),
 		},
 		Spec: v1.EndpointSpec{
 			URL:      domain.Spec.URL,
@@ -685,11 +844,27 @@ const (
 	// TextYAML is the content type for YAML.
 	TextYAML = "text/yaml"
 	// Empty is the content type for empty.
-	// Empty content type is treated as YAML by default.
+	// Empty content type is treated as YAML by default; see ConfigContentTypeForFormat.
 	// Due to spec miss, old otel-collector sends empty content type even though it should be YAML.
 	Empty = ""
 )
 
+// ErrUnknownConfigFormat is returned by ConfigContentTypeForFormat for a format it does not know.
+var ErrUnknownConfigFormat = errors.New("unknown config format")
+
+// ConfigContentTypeForFormat returns the content type of a config format name, "yaml" or
+// "json". An empty name means "yaml".
+func ConfigContentTypeForFormat(format string) (string, error) {
+	switch format {
+	case "", "yaml":
+		return TextYAML, nil
+	case "json":
+		return TextJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q (expected yaml or json)", ErrUnknownConfigFormat, format)
+	}
+}
+
 // MapNamespaceToAPI maps a domain Namespace to an API Namespace.
 func (mapper *Mapper) MapNamespaceToAPI(
 	namespace *agentmodel.Namespace,
@@ -703,6 +878,7 @@ func (mapper *Mapper) MapNamespaceToAPI(
 			Annotations: namespace.Metadata.Annotations,
 			CreatedAt:   v1.NewTime(namespace.Metadata.CreatedAt),
 			DeletedAt:   mapDeletedAtPtrToAPI(namespace.Metadata.DeletedAt),
+			DeletedBy:   model.DeletedBy(namespace.Status.Conditions),
 		},
 		Status: v1.NamespaceStatus{
 			Conditions: mapper.mapConditionsToAPI(
@@ -762,17 +938,24 @@ func (mapper *Mapper) mapRemoteConfigFromAPI(
 	}
 }
 
-func (mapper *Mapper) mapNewInstanceUIDFromAPI(newInstanceUID string) uuid.UUID {
+// mapNewInstanceUIDFromAPI parses the optional newInstanceUid. An empty value
+// means "no reassignment requested" and maps to uuid.Nil; anything else must be
+// a valid UUID.
+func (mapper *Mapper) mapNewInstanceUIDFromAPI(newInstanceUID string) (uuid.UUID, error) {
 	if newInstanceUID == "" {
-		return uuid.Nil
+		return uuid.Nil, nil
 	}
 
 	uid, err := uuid.Parse(newInstanceUID)
 	if err != nil {
-		return uuid.Nil
+		return uuid.Nil, &ConversionError{
+			Field: "spec.newInstanceUid",
+			Value: newInstanceUID,
+			Err:   err,
+		}
 	}
 
-	return uid
+	return uid, nil
 }
 
 func (mapper *Mapper) mapRestartInfoFromAPI(restartRequiredAt *v1.Time) *agentmodel.AgentRestartInfo {
@@ -815,14 +998,9 @@ func (mapper *Mapper) mapRemoteConfigToAPI(remoteConfig *agentmodel.AgentSpecRem
 		return v1.AgentSpecRemoteConfig{}
 	}
 
-	// Extract config names from ConfigMap
-	names := make([]string, 0, len(remoteConfig.ConfigMap.ConfigMap))
-	for name := range remoteConfig.ConfigMap.ConfigMap {
-		names = append(names, name)
-	}
-
+	// Sorted so the names do not follow the map's random iteration order.
 	return v1.AgentSpecRemoteConfig{
-		RemoteConfigNames: names,
+		RemoteConfigNames: slices.Sorted(maps.Keys(remoteConfig.ConfigMap.ConfigMap)),
 	}
 }
 
@@ -852,8 +1030,14 @@ func (mapper *Mapper) mapRestartRequiredAtToAPI(restartInfo *agentmodel.AgentRes
 func (mapper *Mapper) mapCustomCapabilitiesToAPI(
 	customCapabilities *agentmodel.AgentCustomCapabilities,
 ) v1.AgentCustomCapabilities {
+	// The agent reports custom capabilities as a set, so their order carries no meaning.
+	var capabilities []string
+	if len(customCapabilities.Capabilities) > 0 {
+		capabilities = slices.Sorted(slices.Values(customCapabilities.Capabilities))
+	}
+
 	return v1.AgentCustomCapabilities{
-		Capabilities: customCapabilities.Capabilities,
+		Capabilities: capabilities,
 	}
 }
 
@@ -886,14 +1070,36 @@ func (mapper *Mapper) mapAvailableComponentsToAPI(
 
 	return v1.AgentAvailableComponents{
 		Components: components,
+		Truncated:  availableComponents.Truncated,
+	}
+}
+
+// mapEffectiveConfigToAPI maps an effective config, marking its primary file.
+func (mapper *Mapper) mapEffectiveConfigToAPI(effectiveConfig agentmodel.AgentEffectiveConfig) v1.AgentEffectiveConfig {
+	primary, hasPrimary := effectiveConfig.ConfigMap.PrimaryFileName()
+
+	return v1.AgentEffectiveConfig{
+		ConfigMap: v1.AgentConfigMap{
+			ConfigMap: lo.MapValues(effectiveConfig.ConfigMap.ConfigMap,
+				func(value agentmodel.AgentConfigFile, name string) v1.AgentConfigFile {
+					configFile := mapper.mapConfigFileToAPI(value)
+					configFile.Primary = hasPrimary && name == primary
+
+					return configFile
+				}),
+		},
 	}
 }
 
 func (mapper *Mapper) mapConfigFileToAPI(configFile agentmodel.AgentConfigFile) v1.AgentConfigFile {
 	switch configFile.ContentType {
+	case Empty:
+		return v1.AgentConfigFile{
+			Body:        string(configFile.Body),
+			ContentType: mapper.defaultConfigContentType,
+		}
 	case TextJSON,
-		TextYAML,
-		Empty:
+		TextYAML:
 		return v1.AgentConfigFile{
 			Body:        string(configFile.Body),
 			ContentType: configFile.ContentType,
@@ -1089,6 +1295,69 @@ func mapGroupRemoteConfigToAPI(domain *agentmodel.AgentGroupAgentRemoteConfig) v
 	return api
 }
 
+// mapAgentGroupRolloutFromAPI maps an API rollout to the domain. The previous remote configs
+// are left for the server to record, so a client cannot swap in configs it never rolled out.
+func mapAgentGroupRolloutFromAPI(api *v1.AgentGroupRollout) *agentmodel.AgentGroupRollout {
+	if api == nil {
+		return nil
+	}
+
+	return &agentmodel.AgentGroupRollout{
+		Percentage:                 api.Percentage,
+		Count:                      api.Count,
+		PreviousAgentRemoteConfigs: nil,
+		BucketThreshold:            0,
+	}
+}
+
+// mapAgentGroupRolloutToAPI maps a domain rollout to its API representation.
+func mapAgentGroupRolloutToAPI(domain *agentmodel.AgentGroupRollout) *v1.AgentGroupRollout {
+	if domain == nil {
+		return nil
+	}
+
+	previous := make([]v1.AgentGroupRemoteConfig, 0, len(domain.PreviousAgentRemoteConfigs))
+	for i := range domain.PreviousAgentRemoteConfigs {
+		previous = append(previous, mapGroupRemoteConfigToAPI(&domain.PreviousAgentRemoteConfigs[i]))
+	}
+
+	return &v1.AgentGroupRollout{
+		Percentage:                 domain.Percentage,
+		Count:                      domain.Count,
+		PreviousAgentRemoteConfigs: previous,
+	}
+}
+
+// mapAttributeMatchExpressionsFromAPI maps API selector match expressions to the domain.
+func mapAttributeMatchExpressionsFromAPI(api []v1.AttributeMatchExpression) []agentmodel.AttributeMatchExpression {
+	if len(api) == 0 {
+		return nil
+	}
+
+	return lo.Map(api, func(expression v1.AttributeMatchExpression, _ int) agentmodel.AttributeMatchExpression {
+		return agentmodel.AttributeMatchExpression{
+			Key:      expression.Key,
+			Operator: agentmodel.AttributeMatchOperator(expression.Operator),
+			Value:    expression.Value,
+		}
+	})
+}
+
+// mapAttributeMatchExpressionsToAPI maps domain selector match expressions to the API.
+func mapAttributeMatchExpressionsToAPI(domain []agentmodel.AttributeMatchExpression) []v1.AttributeMatchExpression {
+	if len(domain) == 0 {
+		return nil
+	}
+
+	return lo.Map(domain, func(expression agentmodel.AttributeMatchExpression, _ int) v1.AttributeMatchExpression {
+		return v1.AttributeMatchExpression{
+			Key:      expression.Key,
+			Operator: v1.AttributeMatchOperator(expression.Operator),
+			Value:    expression.Value,
+		}
+	})
+}
+
 func (mapper *Mapper) mapAgentGroupAgentConfigToAPI(domainAgentGroup *agentmodel.AgentGroup) *v1.AgentConfig {
 	if len(domainAgentGroup.Spec.AgentRemoteConfigs) == 0 && domainAgentGroup.Spec.AgentConnectionConfig == nil {
 		return nil
@@ -1140,3 +1409,27 @@ func mapDeletedAtToAPI(t time.Time) *v1.Time {
 
 	return p(v1.NewTime(t))
 }
+
+// mapQuarantineToAPI maps the agent's quarantine record; nil means not quarantined.
+func mapPendingReportsToAPI(kinds []agentmodel.AgentReportKind) []v1.AgentReportKind {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	return lo.Map(kinds, func(kind agentmodel.AgentReportKind, _ int) v1.AgentReportKind {
+		return v1.AgentReportKind(kind)
+	})
+}
+
+func mapQuarantineToAPI(quarantine *agentmodel.AgentQuarantine) *v1.AgentQuarantine {
+	if quarantine == nil {
+		return nil
+	}
+
+	return &v1.AgentQuarantine{
+		Automatic:     quarantine.Automatic,
+		Reason:        quarantine.Reason,
+		QuarantinedBy: quarantine.QuarantinedBy,
+		QuarantinedAt: v1.NewTime(quarantine.QuarantinedAt),
+	}
+}
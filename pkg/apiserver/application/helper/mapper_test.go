@@ -4,12 +4,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/utils/clock"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
 func TestMapAPIToAgentPackage(t *testing.T) {
@@ -94,3 +97,129 @@ func TestMapAPIToEndpoint_Nil(t *testing.T) {
 	mapper := helper.NewMapper(clock.RealClock{}, 0)
 	assert.Nil(t, mapper.MapAPIToEndpoint(nil))
 }
+
+func TestMapAPIToAgent_NewInstanceUID(t *testing.T) {
+	t.Parallel()
+
+	mapper := helper.NewMapper(clock.RealClock{}, 0)
+
+	t.Run("empty means no reassignment", func(t *testing.T) {
+		t.Parallel()
+
+		//exhaustruct:ignore
+		got, err := mapper.MapAPIToAgent(&v1.Agent{})
+
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Nil, got.Spec.NewInstanceUID)
+	})
+
+	t.Run("valid uuid is parsed", func(t *testing.T) {
+		t.Parallel()
+
+		uid := uuid.New()
+		//exhaustruct:ignore
+		got, err := mapper.MapAPIToAgent(&v1.Agent{Spec: v1.AgentSpec{NewInstanceUID: uid.String()}})
+
+		require.NoError(t, err)
+		assert.Equal(t, uid, got.Spec.NewInstanceUID)
+	})
+
+	t.Run("malformed uuid is rejected instead of becoming uuid.Nil", func(t *testing.T) {
+		t.Parallel()
+
+		//exhaustruct:ignore
+		got, err := mapper.MapAPIToAgent(&v1.Agent{Spec: v1.AgentSpec{NewInstanceUID: "not-a-uuid"}})
+
+		require.Error(t, err)
+		assert.Nil(t, got)
+		require.ErrorIs(t, err, helper.ErrMalformedInput)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+
+		var convErr *helper.ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "spec.newInstanceUid", convErr.Field)
+		assert.Equal(t, "not-a-uuid", convErr.Value)
+	})
+}
+
+func TestMapAgentToAPI_SortsMapDerivedSlices(t *testing.T) {
+	t.Parallel()
+
+	mapper := helper.NewMapper(clock.RealClock{}, 0)
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.Spec.RemoteConfig = &agentmodel.AgentSpecRemoteConfig{
+		ConfigMap: agentmodel.AgentConfigMap{
+			ConfigMap: map[string]agentmodel.AgentConfigFile{
+				"receivers":  {Body: []byte("a"), ContentType: "text/yaml"},
+				"exporters":  {Body: []byte("b"), ContentType: "text/yaml"},
+				"processors": {Body: []byte("c"), ContentType: "text/yaml"},
+				"extensions": {Body: []byte("d"), ContentType: "text/yaml"},
+			},
+		},
+	}
+	agent.Metadata.CustomCapabilities.Capabilities = []string{"io.opentelemetry.pprof", "com.example.b", "com.example.a"}
+
+	for range 20 {
+		got := mapper.MapAgentToAPI(agent)
+
+		assert.Equal(t, []string{"exporters", "extensions", "processors", "receivers"},
+			got.Spec.RemoteConfig.RemoteConfigNames)
+		assert.Equal(t, []string{"com.example.a", "com.example.b", "io.opentelemetry.pprof"},
+			got.Metadata.CustomCapabilities.Capabilities)
+	}
+
+	assert.Equal(t, []string{"io.opentelemetry.pprof", "com.example.b", "com.example.a"},
+		agent.Metadata.CustomCapabilities.Capabilities, "the domain model is not reordered")
+}
+
+func TestMapAgentToAPI_DefaultConfigContentType(t *testing.T) {
+	t.Parallel()
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.Status.EffectiveConfig.ConfigMap.ConfigMap = map[string]agentmodel.AgentConfigFile{
+		"collector": {Body: []byte(`{"receivers": {}}`), ContentType: helper.Empty},
+	}
+
+	mapper := helper.NewMapper(clock.RealClock{}, 0)
+	file := mapper.MapAgentToAPI(agent).Status.EffectiveConfig.ConfigMap.ConfigMap["collector"]
+	assert.Equal(t, helper.TextYAML, file.ContentType, "YAML is the default")
+
+	mapper.SetDefaultConfigContentType(helper.TextJSON)
+
+	file = mapper.MapAgentToAPI(agent).Status.EffectiveConfig.ConfigMap.ConfigMap["collector"]
+	assert.Equal(t, helper.TextJSON, file.ContentType)
+	assert.JSONEq(t, `{"receivers": {}}`, file.Body)
+}
+
+func TestConfigContentTypeForFormat(t *testing.T) {
+	t.Parallel()
+
+	for format, expected := range map[string]string{
+		"":     helper.TextYAML,
+		"yaml": helper.TextYAML,
+		"json": helper.TextJSON,
+	} {
+		contentType, err := helper.ConfigContentTypeForFormat(format)
+		require.NoError(t, err)
+		assert.Equal(t, expected, contentType, format)
+	}
+
+	_, err := helper.ConfigContentTypeForFormat("toml")
+	require.ErrorIs(t, err, helper.ErrUnknownConfigFormat)
+}
+
+func TestMapAgentToAPI_MarksPrimaryEffectiveConfigFile(t *testing.T) {
+	t.Parallel()
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.Status.EffectiveConfig.ConfigMap.ConfigMap = map[string]agentmodel.AgentConfigFile{
+		"config.yaml":    {Body: []byte("service: {}"), ContentType: helper.TextYAML},
+		"receivers.yaml": {Body: []byte("receivers: {}"), ContentType: helper.TextYAML},
+	}
+
+	got := helper.NewMapper(clock.RealClock{}, 0).MapAgentToAPI(agent).Status.EffectiveConfig.ConfigMap.ConfigMap
+
+	assert.True(t, got["config.yaml"].Primary)
+	assert.False(t, got["receivers.yaml"].Primary)
+	assert.Equal(t, "receivers: {}", got["receivers.yaml"].Body)
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+
+	metricapi "go.opentelemetry.io/otel/metric"
+
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+const (
+	// PendingCommandsGaugeName is the metric reporting how many commands are queued for the
+	// agents of every namespace that they have not acted on yet. It carries no per-agent
+	// attribute, so its cardinality does not grow with the fleet.
+	PendingCommandsGaugeName = "opamp.agent.pending_commands"
+	// FullCommandQueuesGaugeName is the metric reporting how many agents have as many
+	// commands queued as allowed, so that further ones are rejected.
+	FullCommandQueuesGaugeName = "opamp.agent.full_command_queues"
+
+	pendingCommandsMeterName = "github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+)
+
+// RegisterPendingCommandsGauges registers the agent command queue gauges on meterProvider.
+// Each collection reads the totals of the whole fleet through usecase, so they hold whichever
+// server queued or drained the commands. A queue counts as full once it holds maxPending
+// commands. The provider is nil when metrics are disabled, and gauges that cannot be
+// registered are skipped: losing the metric must not fail the server.
+func RegisterPendingCommandsGauges(
+	meterProvider metricapi.MeterProvider,
+	usecase agentport.AgentCommandQueueSummaryUsecase,
+	maxPending int,
+) {
+	if meterProvider == nil || usecase == nil {
+		return
+	}
+
+	meter := meterProvider.Meter(pendingCommandsMeterName)
+
+	pendingCommands, err := meter.Int64ObservableGauge(PendingCommandsGaugeName,
+		metricapi.WithDescription("Number of commands queued for agents that they have not acted on yet."),
+		metricapi.WithUnit("{command}"),
+	)
+	if err != nil {
+		return
+	}
+
+	fullQueues, err := meter.Int64ObservableGauge(FullCommandQueuesGaugeName,
+		metricapi.WithDescription("Number of agents whose command queue is at the configured cap."),
+		metricapi.WithUnit("{agent}"),
+	)
+	if err != nil {
+		return
+	}
+
+	_, _ = meter.RegisterCallback(func(ctx context.Context, observer metricapi.Observer) error {
+		summary, err := usecase.GetCommandQueueSummary(ctx, maxPending)
+		if err != nil {
+			return fmt.Errorf("failed to observe agent command queues: %w", err)
+		}
+
+		observer.ObserveInt64(pendingCommands, summary.NumPendingCommands)
+		observer.ObserveInt64(fullQueues, summary.NumFullQueues)
+
+		return nil
+	}, pendingCommands, fullQueues)
+}
@@ -0,0 +1,61 @@
+package helper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+type stubCommandQueueSummaryUsecase struct {
+	summary    agentmodel.CommandQueueSummary
+	maxPending int
+}
+
+func (s *stubCommandQueueSummaryUsecase) GetCommandQueueSummary(
+	_ context.Context,
+	maxPending int,
+) (*agentmodel.CommandQueueSummary, error) {
+	s.maxPending = maxPending
+
+	return &s.summary, nil
+}
+
+func TestRegisterPendingCommandsGauges_ObservesFleetTotals(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	usecase := &stubCommandQueueSummaryUsecase{
+		summary:    agentmodel.CommandQueueSummary{NumPendingCommands: 7, NumFullQueues: 2},
+		maxPending: 0,
+	}
+	helper.RegisterPendingCommandsGauges(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), usecase, 3)
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+	assert.Equal(t, 3, usecase.maxPending)
+
+	observed := map[string]int64{}
+
+	for _, metric := range resourceMetrics.ScopeMetrics[0].Metrics {
+		data, ok := metric.Data.(metricdata.Gauge[int64])
+		require.True(t, ok)
+		// Each gauge is one series for the whole fleet: nothing identifies an agent.
+		require.Len(t, data.DataPoints, 1)
+		assert.Equal(t, 0, data.DataPoints[0].Attributes.Len())
+
+		observed[metric.Name] = data.DataPoints[0].Value
+	}
+
+	assert.Equal(t, map[string]int64{
+		helper.PendingCommandsGaugeName:   7,
+		helper.FullCommandQueuesGaugeName: 2,
+	}, observed)
+}
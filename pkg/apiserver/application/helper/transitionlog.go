@@ -0,0 +1,135 @@
+package helper
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+const (
+	// AgentStateTransitionLogMessage is the message of every agent state transition log entry.
+	AgentStateTransitionLogMessage = "agent state transition"
+
+	// DefaultAgentTransitionLogMaxPerSecond is how many agent state transitions are logged
+	// per second when no limit is configured.
+	DefaultAgentTransitionLogMaxPerSecond = 100
+
+	agentTransitionLogWindow = time.Second
+)
+
+// AgentTransitionLogger writes one structured info entry per agent state transition, for
+// auditing. Every entry carries the transition, its from and to states, and the agent's
+// instance UID, namespace and identifying attributes.
+//
+// Entries are rate-limited across all agents so a fleet-wide event, such as a server
+// restart reconnecting every agent, cannot flood the log. Entries beyond the limit are
+// dropped, and the next entry logged reports how many were. A nil AgentTransitionLogger
+// logs nothing.
+type AgentTransitionLogger struct {
+	logger       *slog.Logger
+	clock        clock.PassiveClock
+	maxPerWindow int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	logged      int
+	dropped     int
+}
+
+// NewAgentTransitionLogger creates an AgentTransitionLogger logging at most maxPerSecond
+// transitions per second. A maxPerSecond of 0 or less selects
+// DefaultAgentTransitionLogMaxPerSecond.
+func NewAgentTransitionLogger(logger *slog.Logger, clk clock.PassiveClock, maxPerSecond int) *AgentTransitionLogger {
+	if maxPerSecond <= 0 {
+		maxPerSecond = DefaultAgentTransitionLogMaxPerSecond
+	}
+
+	return &AgentTransitionLogger{
+		logger:       logger,
+		clock:        clk,
+		maxPerWindow: maxPerSecond,
+		mu:           sync.Mutex{},
+		windowStart:  time.Time{},
+		logged:       0,
+		dropped:      0,
+	}
+}
+
+// LogTransitions logs the transitions of agent since before was taken with
+// agentmodel.Agent.StateSnapshot.
+func (l *AgentTransitionLogger) LogTransitions(
+	ctx context.Context,
+	before agentmodel.AgentStateSnapshot,
+	agent *agentmodel.Agent,
+) {
+	if l == nil {
+		return
+	}
+
+	for _, transition := range before.TransitionsTo(agent.StateSnapshot()) {
+		dropped, allowed := l.allow()
+		if dropped > 0 {
+			l.logger.LogAttrs(ctx, slog.LevelWarn, "dropped agent state transition log entries over the rate limit",
+				slog.Int("dropped", dropped),
+				slog.Int("maxPerSecond", l.maxPerWindow),
+			)
+		}
+
+		if !allowed {
+			continue
+		}
+
+		l.logger.LogAttrs(ctx, slog.LevelInfo, AgentStateTransitionLogMessage, transitionAttrs(transition, agent)...)
+	}
+}
+
+// allow reports whether another entry fits in the current window, and how many entries
+// were dropped since the last one logged, which the caller reports once.
+func (l *AgentTransitionLogger) allow() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	if now.Sub(l.windowStart) >= agentTransitionLogWindow {
+		l.windowStart = now
+		l.logged = 0
+	}
+
+	if l.logged >= l.maxPerWindow {
+		l.dropped++
+
+		return 0, false
+	}
+
+	l.logged++
+
+	dropped := l.dropped
+	l.dropped = 0
+
+	return dropped, true
+}
+
+func transitionAttrs(transition agentmodel.AgentStateTransition, agent *agentmodel.Agent) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("transition", string(transition.Kind)),
+		slog.String("from", transition.From),
+		slog.String("to", transition.To),
+		slog.String("instanceUID", agent.Metadata.InstanceUID.String()),
+		slog.String("namespace", agent.Metadata.Namespace),
+		slog.Any("identifyingAttributes", agent.Metadata.Description.IdentifyingAttributes),
+	}
+
+	if transition.Kind == agentmodel.AgentStateTransitionConfigFailed {
+		attrs = append(attrs, slog.String("error", agent.Status.RemoteConfigStatus.ErrorMessage))
+	}
+
+	if transition.Kind == agentmodel.AgentStateTransitionHealthChanged && !agent.Status.ComponentHealth.Healthy {
+		attrs = append(attrs, slog.String("error", agent.Status.ComponentHealth.LastError))
+	}
+
+	return attrs
+}
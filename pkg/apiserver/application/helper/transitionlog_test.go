@@ -0,0 +1,119 @@
+package helper_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+// logEntries decodes the JSON log lines written to buf.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+
+	for line := range strings.Lines(buf.String()) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func newTransitionTestAgent() *agentmodel.Agent {
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.Metadata.Namespace = "default"
+	agent.Metadata.Description.IdentifyingAttributes = map[string]string{"service.name": "collector"}
+	agent.Status.Connected = true
+	agent.Status.ComponentHealth.Healthy = true
+
+	return agent
+}
+
+func TestAgentTransitionLogger_HealthTransition(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	clk := clocktesting.NewFakeClock(time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC))
+	transitionLogger := helper.NewAgentTransitionLogger(slog.New(slog.NewJSONHandler(&buf, nil)), clk, 0)
+
+	agent := newTransitionTestAgent()
+	before := agent.StateSnapshot()
+
+	agent.Status.ComponentHealth.Healthy = false
+	agent.Status.ComponentHealth.LastError = "exporter queue is full"
+
+	transitionLogger.LogTransitions(t.Context(), before, agent)
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, helper.AgentStateTransitionLogMessage, entry["msg"])
+	assert.Equal(t, string(agentmodel.AgentStateTransitionHealthChanged), entry["transition"])
+	assert.Equal(t, "healthy", entry["from"])
+	assert.Equal(t, "unhealthy", entry["to"])
+	assert.Equal(t, agent.Metadata.InstanceUID.String(), entry["instanceUID"])
+	assert.Equal(t, "default", entry["namespace"])
+	assert.Equal(t, map[string]any{"service.name": "collector"}, entry["identifyingAttributes"])
+	assert.Equal(t, "exporter queue is full", entry["error"])
+}
+
+func TestAgentTransitionLogger_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	clk := clocktesting.NewFakeClock(time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC))
+	transitionLogger := helper.NewAgentTransitionLogger(slog.New(slog.NewJSONHandler(&buf, nil)), clk, 2)
+
+	disconnect := func() {
+		agent := newTransitionTestAgent()
+		before := agent.StateSnapshot()
+		agent.RecordDisconnectedAt(clk.Now())
+		transitionLogger.LogTransitions(t.Context(), before, agent)
+	}
+
+	for range 5 {
+		disconnect()
+	}
+
+	assert.Len(t, logEntries(t, &buf), 2, "entries over the limit are dropped")
+
+	buf.Reset()
+	clk.Step(time.Second)
+	disconnect()
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "WARN", entries[0]["level"])
+	assert.InDelta(t, 3, entries[0]["dropped"], 0)
+	assert.Equal(t, string(agentmodel.AgentStateTransitionDisconnected), entries[1]["transition"])
+}
+
+func TestAgentTransitionLogger_Nil(t *testing.T) {
+	t.Parallel()
+
+	var transitionLogger *helper.AgentTransitionLogger
+
+	agent := newTransitionTestAgent()
+	before := agent.StateSnapshot()
+	agent.Status.Connected = false
+
+	assert.NotPanics(t, func() { transitionLogger.LogTransitions(t.Context(), before, agent) })
+}
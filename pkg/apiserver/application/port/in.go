@@ -4,6 +4,7 @@ package port
 import (
 	"errors"
 
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
@@ -17,7 +18,30 @@ var ErrResourceNotExist = model.ErrResourceNotExist
 // enforced in the domain layer) so the HTTP layer can match it without importing the domain.
 var ErrAgentConnected = agentport.ErrAgentConnected
 
+// ErrNewInstanceUIDInUse is returned when an agent is asked to take a new instance UID that
+// another agent already has or is pending reassignment to. It aliases the domain sentinel
+// so the HTTP layer can map it to a 409.
+var ErrNewInstanceUIDInUse = agentport.ErrNewInstanceUIDInUse
+
+// ErrAgentHasNoRemoteConfig is returned when a remote config resend is requested for an agent
+// with no remote config to offer. It aliases the domain sentinel so the HTTP layer can map it
+// to a 409.
+var ErrAgentHasNoRemoteConfig = agentport.ErrAgentHasNoRemoteConfig
+
+// ErrAgentNotConnected is returned when a reconnect is requested for an agent that is not
+// connected. It aliases the domain sentinel so the HTTP layer can map it to a 409.
+var ErrAgentNotConnected = agentport.ErrAgentNotConnected
+
+// ErrUnsupportedAgentOperation is returned when the agent's capabilities do not allow the
+// requested operation. It aliases the domain sentinel so the HTTP layer can map it to a 409.
+var ErrUnsupportedAgentOperation = agentmodel.ErrUnsupportedAgentOperation
+
 // ErrAgentNamespaceMismatch is returned when an agent exists but does not belong to the
 // requested namespace. From that namespace's perspective the agent does not exist, so
 // callers should map this to a 404.
 var ErrAgentNamespaceMismatch = errors.New("agent does not belong to the specified namespace")
+
+// ErrAgentCommandQueueFull is returned when a command is queued for an agent whose pending
+// command queue is at its cap. It aliases the domain sentinel so the HTTP layer can map it
+// to a 429.
+var ErrAgentCommandQueueFull = agentmodel.ErrCommandQueueFull
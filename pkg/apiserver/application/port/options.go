@@ -31,6 +31,16 @@ type ListOptions struct {
 	// It is combined with IdentifyingAttributes via AND, and is a no-op for
 	// resources that have no non-identifying attributes.
 	NonIdentifyingAttributes map[string]string
+
+	// ConnectionType, when non-empty, restricts an agent listing to agents whose last
+	// connection has this type (one of the v1.ConnectionType* names). It is a no-op for
+	// resources that have no connection.
+	ConnectionType string
+
+	// Capabilities, when non-zero, restricts an agent listing to agents that reported
+	// every capability bit set in it (see v1.AgentCapabilities). It is a no-op for
+	// resources that have no capabilities.
+	Capabilities uint64
 }
 
 // ToDomain converts the application-level list options to the domain model.
@@ -47,6 +57,8 @@ func (o *ListOptions) ToDomain() *model.ListOptions {
 		ConnectedOnly:            o.ConnectedOnly,
 		IdentifyingAttributes:    o.IdentifyingAttributes,
 		NonIdentifyingAttributes: o.NonIdentifyingAttributes,
+		ConnectionType:           o.ConnectionType,
+		Capabilities:             o.Capabilities,
 	}
 }
 
@@ -109,6 +109,12 @@ func (m *mockConnectionUsecase) SendServerToAgent(
 	return args.Error(0) //nolint:wrapcheck // mock error
 }
 
+func (m *mockConnectionUsecase) DisconnectAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	args := m.Called(ctx, instanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 // mockClusterConnectionUsecase is a mock implementation of agentport.ClusterConnectionUsecase.
 type mockClusterConnectionUsecase struct {
 	mock.Mock
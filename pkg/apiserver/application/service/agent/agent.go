@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	metricapi "go.opentelemetry.io/otel/metric"
 	"k8s.io/utils/clock"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
@@ -17,6 +18,7 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
 var (
@@ -26,6 +28,12 @@ var (
 	// It aliases the application port sentinel so the HTTP layer can map it to a 404 while existing
 	// references to this package-level name keep working.
 	ErrAgentNamespaceMismatch = applicationport.ErrAgentNamespaceMismatch
+	// ErrAgentDisconnectUnavailable is returned when a reconnect is requested but no
+	// disconnect publisher is configured.
+	ErrAgentDisconnectUnavailable = errors.New("agent disconnect is not available")
+	// ErrEffectiveConfigWatchUnavailable is returned when an effective config watch is
+	// requested but no watcher is configured.
+	ErrEffectiveConfigWatchUnavailable = errors.New("agent effective config watch is not available")
 )
 
 var _ usecase.AgentManageUsecase = (*Service)(nil)
@@ -37,10 +45,20 @@ type Service struct {
 	agentNotificationUsecase   agentport.AgentNotificationUsecase
 	endpointDetectionUsecase   agentport.EndpointDetectionUsecase
 	cacheInvalidationPublisher agentport.AgentCacheInvalidationPublisher
+	agentGroupUsecase          agentport.AgentGroupUsecase
+	disconnectPublisher        agentport.AgentDisconnectPublisher
+	effectiveConfigWatcher     agentport.AgentEffectiveConfigWatcher
 
 	// mapper
-	mapper *helper.Mapper
-	logger *slog.Logger
+	mapper                   *helper.Mapper
+	defaultConfigContentType string
+	clock                    clock.Clock
+	logger                   *slog.Logger
+
+	// maxPendingCommands caps the commands queued per agent; 0 means unbounded.
+	maxPendingCommands int
+	// transitionLogger logs the commands queued for agents. Nil when they are not logged.
+	transitionLogger *helper.AgentTransitionLogger
 }
 
 // New creates a new instance of the Service struct.
@@ -58,10 +76,103 @@ func New(
 		agentNotificationUsecase:   agentNotificationUsecase,
 		endpointDetectionUsecase:   endpointDetectionUsecase,
 		cacheInvalidationPublisher: cacheInvalidationPublisher,
+		agentGroupUsecase:          nil,
+		disconnectPublisher:        nil,
+		effectiveConfigWatcher:     nil,
 
-		mapper: helper.NewMapper(realClock, agentmodel.DefaultConnectionStaleness),
-		logger: logger,
+		mapper:                   helper.NewMapper(realClock, agentmodel.DefaultConnectionStaleness),
+		defaultConfigContentType: helper.TextYAML,
+		clock:                    realClock,
+		logger:                   logger,
+
+		maxPendingCommands: 0,
+		transitionLogger:   nil,
+	}
+}
+
+// SetClock overrides the clock used to evaluate connection state. Intended for tests.
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+	s.mapper = helper.NewMapper(c, agentmodel.DefaultConnectionStaleness)
+	s.mapper.SetDefaultConfigContentType(s.defaultConfigContentType)
+}
+
+// SetDefaultConfigContentType sets the content type reported for agent config files
+// stored without one.
+func (s *Service) SetDefaultConfigContentType(contentType string) {
+	s.defaultConfigContentType = contentType
+	s.mapper.SetDefaultConfigContentType(contentType)
+}
+
+// SetMaxPendingCommands caps how many commands may be queued for a single agent. Once an
+// agent's queue is full, further commands are rejected with ErrAgentCommandQueueFull.
+// 0 or less means unbounded.
+func (s *Service) SetMaxPendingCommands(maxPending int) {
+	s.maxPendingCommands = maxPending
+}
+
+// SetMeterProvider registers the agent command queue gauges on meterProvider, read through
+// summaryUsecase. They count full queues against the cap set by SetMaxPendingCommands, so
+// it is called after that.
+func (s *Service) SetMeterProvider(
+	meterProvider metricapi.MeterProvider,
+	summaryUsecase agentport.AgentCommandQueueSummaryUsecase,
+) {
+	helper.RegisterPendingCommandsGauges(meterProvider, summaryUsecase, s.maxPendingCommands)
+}
+
+// SetAgentGroupUsecase sets the usecase used to attribute desired config entries to the
+// agent groups contributing them. Without it, every entry is attributed to the agent.
+func (s *Service) SetAgentGroupUsecase(agentGroupUsecase agentport.AgentGroupUsecase) {
+	s.agentGroupUsecase = agentGroupUsecase
+}
+
+// SetAgentDisconnectPublisher sets the publisher used to close an agent's connection on the
+// server holding it. Without it, reconnect requests fail.
+func (s *Service) SetAgentDisconnectPublisher(disconnectPublisher agentport.AgentDisconnectPublisher) {
+	s.disconnectPublisher = disconnectPublisher
+}
+
+// SetAgentEffectiveConfigWatcher sets the watcher that follows agents' effective config
+// changes. Without it, effective config watches fail.
+func (s *Service) SetAgentEffectiveConfigWatcher(watcher agentport.AgentEffectiveConfigWatcher) {
+	s.effectiveConfigWatcher = watcher
+}
+
+// SetAgentTransitionLogger sets the logger of the agents' state transitions, which logs
+// the commands queued for them. Nil disables the logging.
+func (s *Service) SetAgentTransitionLogger(logger *helper.AgentTransitionLogger) {
+	s.transitionLogger = logger
+}
+
+// GetAgentUptime implements usecase.AgentManageUsecase.
+func (s *Service) GetAgentUptime(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*v1.AgentUptime, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	uptime := agent.UptimeAt(s.clock.Now(), agentmodel.DefaultConnectionStaleness)
+
+	return s.mapper.MapAgentUptimeToAPI(instanceUID, uptime), nil
+}
+
+// GetAgentEffectiveConfigHistory implements usecase.AgentManageUsecase.
+func (s *Service) GetAgentEffectiveConfigHistory(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*v1.AgentEffectiveConfigHistory, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
 	}
+
+	return s.mapper.MapAgentEffectiveConfigHistoryToAPI(instanceUID, agent.Status.EffectiveConfigHistory), nil
 }
 
 // ListAgentEndpoints implements usecase.AgentManageUsecase. It returns a read-only view
@@ -155,6 +266,98 @@ func (s *Service) SearchAgents(
 	}, nil
 }
 
+// ListAgentsByPackage implements usecase.AgentManageUsecase.
+func (s *Service) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *applicationport.ListOptions,
+) (*v1.ListResponse[v1.Agent], error) {
+	response, err := s.agentUsecase.ListAgentsByPackage(ctx, namespace, packageName, packageVersion,
+		options.ToDomain())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents by package: %w", err)
+	}
+
+	return &v1.ListResponse[v1.Agent]{
+		Kind:       v1.AgentKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.ListMeta{
+			Continue:           response.Continue,
+			RemainingItemCount: response.RemainingItemCount,
+		},
+		Items: lo.Map(response.Items, func(agent *agentmodel.Agent, _ int) v1.Agent {
+			return *s.mapper.MapAgentToAPI(agent)
+		}),
+	}, nil
+}
+
+// ListAgentCapabilities implements usecase.AgentManageUsecase.
+func (s *Service) ListAgentCapabilities(
+	ctx context.Context,
+	options *applicationport.ListOptions,
+) (*v1.ListResponse[v1.AgentCapabilitySummary], error) {
+	domainOptions := options.ToDomain()
+	if domainOptions == nil {
+		//exhaustruct:ignore
+		domainOptions = &model.ListOptions{}
+	}
+
+	// The matrix describes what the server can do with agents right now, so it only
+	// covers agents that are connected.
+	domainOptions.ConnectedOnly = true
+
+	//exhaustruct:ignore
+	response, err := s.agentUsecase.ListAgentsBySelector(ctx, agentmodel.AgentSelector{}, domainOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent capabilities: %w", err)
+	}
+
+	return &v1.ListResponse[v1.AgentCapabilitySummary]{
+		Kind:       v1.AgentCapabilitySummaryKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.ListMeta{
+			Continue:           response.Continue,
+			RemainingItemCount: response.RemainingItemCount,
+		},
+		Items: lo.Map(response.Items, func(agent *agentmodel.Agent, _ int) v1.AgentCapabilitySummary {
+			capabilities := v1.AgentCapabilities(agent.Metadata.Capabilities)
+
+			return v1.AgentCapabilitySummary{
+				Namespace:       agent.Metadata.Namespace,
+				InstanceUID:     agent.Metadata.InstanceUID,
+				Type:            string(agent.Metadata.Description.AgentType()),
+				Capabilities:    capabilities,
+				CapabilityNames: lo.Ternary(capabilities == 0, []string{}, capabilities.Names()),
+			}
+		}),
+	}, nil
+}
+
+// ListAgentAttributes implements usecase.AgentManageUsecase.
+func (s *Service) ListAgentAttributes(ctx context.Context, valueLimit int) (*v1.AgentAttributeCatalog, error) {
+	catalog, err := s.agentUsecase.ListAgentAttributes(ctx, min(valueLimit, agentmodel.MaxAttributeValueLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent attributes: %w", err)
+	}
+
+	return &v1.AgentAttributeCatalog{
+		IdentifyingAttributes:    mapAttributeCatalogEntries(catalog.IdentifyingAttributes),
+		NonIdentifyingAttributes: mapAttributeCatalogEntries(catalog.NonIdentifyingAttributes),
+	}, nil
+}
+
+func mapAttributeCatalogEntries(entries []agentmodel.AttributeCatalogEntry) []v1.AgentAttribute {
+	return lo.Map(entries, func(entry agentmodel.AttributeCatalogEntry, _ int) v1.AgentAttribute {
+		return v1.AgentAttribute{
+			Key:             entry.Key,
+			Values:          entry.Values,
+			ValuesTruncated: entry.ValuesTruncated,
+		}
+	})
+}
+
 // DeleteAgent implements [usecase.AgentManageUsecase].
 //
 // Only disconnected agents may be deleted. The connection guard is enforced by the
@@ -193,10 +396,23 @@ func (s *Service) UpdateAgent(
 		return nil, err
 	}
 
-	agent := s.mapper.MapAPIToAgent(api)
+	agent, err := s.mapper.MapAPIToAgent(api)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map agent: %w", err)
+	}
+
+	before := existing.StateSnapshot()
+
+	// Handle restart request; RestartInfo is nil when the request does not ask for one.
+	if agent.Spec.RestartInfo != nil && !agent.Spec.RestartInfo.RequiredRestartedAt.IsZero() {
+		// Re-timing a restart that is still pending does not queue another command.
+		if !existing.ShouldBeRestarted() {
+			err = s.ensureCommandQueueCapacity(existing)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	// Handle restart request
-	if !agent.Spec.RestartInfo.RequiredRestartedAt.IsZero() {
 		restartErr := existing.SetRestartRequired(agent.Spec.RestartInfo.RequiredRestartedAt)
 		if restartErr != nil {
 			return nil, fmt.Errorf("failed to set restart required: %w", restartErr)
@@ -204,7 +420,12 @@ func (s *Service) UpdateAgent(
 	}
 
 	// Update other spec fields if provided
-	if agent.Spec.NewInstanceUID != uuid.Nil {
+	if agent.Spec.NewInstanceUID != uuid.Nil && agent.Spec.NewInstanceUID != existing.Spec.NewInstanceUID {
+		err = s.agentUsecase.CheckNewInstanceUIDAvailable(ctx, instanceUID, agent.Spec.NewInstanceUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set new instance UID: %w", err)
+		}
+
 		existing.Spec.NewInstanceUID = agent.Spec.NewInstanceUID
 	}
 
@@ -217,6 +438,8 @@ func (s *Service) UpdateAgent(
 		return nil, fmt.Errorf("failed to update agent: %w", err)
 	}
 
+	s.transitionLogger.LogTransitions(ctx, before, existing)
+
 	// Notify about agent update
 	notifyErr := s.agentNotificationUsecase.NotifyAgentUpdated(ctx, existing)
 	if notifyErr != nil {
@@ -228,6 +451,288 @@ func (s *Service) UpdateAgent(
 	return s.mapper.MapAgentToAPI(existing), nil
 }
 
+// RequestAgentReport implements [usecase.AgentManageUsecase].
+func (s *Service) RequestAgentReport(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+	kind v1.AgentReportKind,
+) (*v1.Agent, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A repeated request for a report that is still pending is recorded once, so only a
+	// new request counts against the queue.
+	if !agent.HasPendingReport(agentmodel.AgentReportKind(kind)) {
+		err = s.ensureCommandQueueCapacity(agent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	before := agent.StateSnapshot()
+
+	err = agent.RequestReport(agentmodel.AgentReportKind(kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request agent report: %w", err)
+	}
+
+	err = s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save agent: %w", err)
+	}
+
+	s.transitionLogger.LogTransitions(ctx, before, agent)
+
+	// The flags reach a connected agent on this push; a disconnected one gets them in the
+	// response to its next message.
+	notifyErr := s.agentNotificationUsecase.NotifyAgentUpdated(ctx, agent)
+	if notifyErr != nil {
+		s.logger.Error("failed to notify agent updated", "error", notifyErr.Error())
+	}
+
+	s.invalidatePeerCaches(ctx, instanceUID)
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// ensureCommandQueueCapacity rejects queueing another command for an agent whose queue is
+// at the configured cap, typically because the agent is offline and not draining it.
+func (s *Service) ensureCommandQueueCapacity(agent *agentmodel.Agent) error {
+	err := agent.EnsureCommandQueueCapacity(s.maxPendingCommands)
+	if err != nil {
+		return fmt.Errorf("failed to queue agent command: %w", err)
+	}
+
+	return nil
+}
+
+// ResendAgentRemoteConfig implements [usecase.AgentManageUsecase].
+func (s *Service) ResendAgentRemoteConfig(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*v1.Agent, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !agent.HasRemoteConfig() {
+		return nil, fmt.Errorf("failed to resend remote config: %w", applicationport.ErrAgentHasNoRemoteConfig)
+	}
+
+	// The spec is unchanged, but saving bumps the resource version. The server holding the
+	// agent's connection skips notifications for a version it already delivered, so without
+	// the bump the resend of a delivered-but-missed offer would be dropped.
+	err = s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save agent: %w", err)
+	}
+
+	// A disconnected agent is not notified; the response to its next message carries the
+	// remote config anyway.
+	notifyErr := s.agentNotificationUsecase.NotifyAgentUpdated(ctx, agent)
+	if notifyErr != nil {
+		s.logger.Error("failed to notify agent updated", "error", notifyErr.Error())
+	}
+
+	s.invalidatePeerCaches(ctx, instanceUID)
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// SetAgentDirectRemoteConfig implements [usecase.AgentManageUsecase].
+func (s *Service) SetAgentDirectRemoteConfig(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+	configMap *v1.AgentConfigMap,
+) (*v1.AgentDesiredConfig, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := s.mapper.MapAPIToAgentConfigFiles(configMap)
+	if len(configs) > 0 && !agent.IsRemoteConfigSupported() {
+		return nil, fmt.Errorf("failed to set direct remote config: %w", applicationport.ErrUnsupportedAgentOperation)
+	}
+
+	agent.SetDirectRemoteConfigs(configs)
+
+	err = s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save agent: %w", err)
+	}
+
+	// A disconnected agent is not notified; the response to its next message offers the
+	// remote config anyway.
+	notifyErr := s.agentNotificationUsecase.NotifyAgentUpdated(ctx, agent)
+	if notifyErr != nil {
+		s.logger.Error("failed to notify agent updated", "error", notifyErr.Error())
+	}
+
+	s.invalidatePeerCaches(ctx, instanceUID)
+
+	return s.desiredConfig(ctx, agent)
+}
+
+// ReconnectAgent implements [usecase.AgentManageUsecase].
+//
+// The server holding the agent's connection closes it; the agent then reconnects on its own
+// and re-establishes its session, reporting its full state again.
+func (s *Service) ReconnectAgent(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !agent.IsConnectedAt(s.clock.Now(), agentmodel.DefaultConnectionStaleness) {
+		return nil, fmt.Errorf("failed to reconnect agent: %w", applicationport.ErrAgentNotConnected)
+	}
+
+	if s.disconnectPublisher == nil {
+		return nil, fmt.Errorf("failed to reconnect agent: %w", ErrAgentDisconnectUnavailable)
+	}
+
+	err = s.disconnectPublisher.RequestAgentDisconnect(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconnect agent: %w", err)
+	}
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// GetAgentDesiredConfig implements [usecase.AgentManageUsecase].
+func (s *Service) GetAgentDesiredConfig(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*v1.AgentDesiredConfig, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.desiredConfig(ctx, agent)
+}
+
+// desiredConfig builds the agent's desired config, naming the source of each entry.
+func (s *Service) desiredConfig(ctx context.Context, agent *agentmodel.Agent) (*v1.AgentDesiredConfig, error) {
+	var err error
+
+	sources := map[string]*agentmodel.AgentGroup{}
+	if s.agentGroupUsecase != nil {
+		sources, err = s.agentGroupUsecase.GetRemoteConfigSources(ctx, agent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remote config sources: %w", err)
+		}
+	}
+
+	configMap := make(map[string]v1.AgentDesiredConfigEntry)
+
+	if agent.Spec.RemoteConfig != nil {
+		for name, file := range agent.Spec.RemoteConfig.ConfigMap.ConfigMap {
+			configMap[name] = v1.AgentDesiredConfigEntry{
+				Body:        string(file.Body),
+				ContentType: lo.CoalesceOrEmpty(file.ContentType, s.defaultConfigContentType),
+				Source:      desiredConfigSource(sources[name]),
+			}
+		}
+	}
+
+	return &v1.AgentDesiredConfig{
+		Namespace:   agent.Metadata.Namespace,
+		InstanceUID: agent.Metadata.InstanceUID,
+		ConfigMap:   configMap,
+	}, nil
+}
+
+// WatchAgentEffectiveConfig implements [usecase.AgentManageUsecase].
+func (s *Service) WatchAgentEffectiveConfig(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (<-chan *v1.AgentEffectiveConfig, error) {
+	if s.effectiveConfigWatcher == nil {
+		return nil, ErrEffectiveConfigWatchUnavailable
+	}
+
+	// The watch starts before the snapshot is read, so a change in between is not missed.
+	changes, stop := s.effectiveConfigWatcher.WatchAgentEffectiveConfig(instanceUID)
+
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		stop()
+
+		return nil, err
+	}
+
+	configs := make(chan *v1.AgentEffectiveConfig, 1)
+	configs <- s.mapper.MapAgentEffectiveConfigToAPI(agent.Status.EffectiveConfig)
+
+	go func() {
+		defer close(configs)
+		defer stop()
+
+		last := agent.Status.EffectiveConfig
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			}
+
+			agent, err := s.agentUsecase.GetAgent(ctx, instanceUID)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Warn("failed to read agent for effective config watch",
+						"instanceUID", instanceUID.String(), "error", err.Error())
+				}
+
+				return
+			}
+
+			// A change already in the snapshot, or announced twice, is not emitted again.
+			if last.Equal(&agent.Status.EffectiveConfig) {
+				continue
+			}
+
+			last = agent.Status.EffectiveConfig
+
+			select {
+			case configs <- s.mapper.MapAgentEffectiveConfigToAPI(last):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return configs, nil
+}
+
+// desiredConfigSource describes where a desired config entry comes from. Entries no
+// matching agent group declares are attributed to the agent itself.
+func desiredConfigSource(group *agentmodel.AgentGroup) v1.AgentDesiredConfigSource {
+	if group == nil {
+		return v1.AgentDesiredConfigSource{
+			Kind:      v1.AgentDesiredConfigSourceAgent,
+			Namespace: "",
+			Name:      "",
+		}
+	}
+
+	return v1.AgentDesiredConfigSource{
+		Kind:      v1.AgentDesiredConfigSourceAgentGroup,
+		Namespace: group.Metadata.Namespace,
+		Name:      group.Metadata.Name,
+	}
+}
+
 // invalidatePeerCaches asks other nodes to drop their cached copy of the agent after a
 // local API mutation, so they don't serve it stale until their TTL expires. It is
 // best-effort: failures are logged, never surfaced to the API caller, since the entry
@@ -5,15 +5,20 @@ import (
 	"errors"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
 
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agent"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
@@ -110,6 +115,45 @@ func (m *MockAgentUsecase) SearchAgents(
 	return args.Get(0).(*model.ListResponse[*agentmodel.Agent]), args.Error(1)
 }
 
+func (m *MockAgentUsecase) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	//nolint:wrapcheck,forcetypeassert // mock error
+	return args.Get(0).(*model.ListResponse[*agentmodel.Agent]), args.Error(1)
+}
+
+func (m *MockAgentUsecase) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentUsecase) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 type MockAgentNotificationUsecase struct {
 	mock.Mock
 }
@@ -237,6 +281,201 @@ func TestService_SearchAgents(t *testing.T) {
 	})
 }
 
+func TestService_GetAgentUptime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("computes uptime over connect/disconnect cycles", func(t *testing.T) {
+		t.Parallel()
+
+		// given: an agent connected for 6h, away 2h, connected 12h, away 2h, and
+		// connected again for the last 2h of a 24h day.
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+		service.SetClock(clocktesting.NewFakeClock(start.Add(24 * time.Hour)))
+
+		instanceUID := uuid.New()
+		domainAgent := agentmodel.NewAgent(instanceUID)
+
+		// heartbeat reports every 30s over [from, to), as a connected agent does.
+		heartbeat := func(from, to time.Duration) {
+			for at := from; at < to; at += 30 * time.Second {
+				domainAgent.UpdateLastCommunicationInfo(start.Add(at), nil)
+			}
+		}
+
+		heartbeat(0, 6*time.Hour)
+		domainAgent.RecordDisconnectedAt(start.Add(6 * time.Hour))
+		heartbeat(8*time.Hour, 20*time.Hour)
+		domainAgent.RecordDisconnectedAt(start.Add(20 * time.Hour))
+		heartbeat(22*time.Hour, 24*time.Hour)
+
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(domainAgent, nil)
+
+		// when
+		uptime, err := service.GetAgentUptime(ctx, "default", instanceUID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, instanceUID, uptime.InstanceUID)
+		assert.True(t, uptime.Connected)
+		assert.Equal(t, start.Add(22*time.Hour), uptime.ConnectedSince.UTC())
+		assert.Equal(t, start.Add(20*time.Hour), uptime.LastDisconnectedAt.UTC())
+		assert.Equal(t, int64(2), uptime.DisconnectCount)
+		assert.InDelta(t, (20 * time.Hour).Seconds(), uptime.TotalConnectedSeconds, 0.001)
+		assert.InDelta(t, (24 * time.Hour).Seconds(), uptime.WindowSeconds, 0.001)
+		assert.InDelta(t, (20 * time.Hour).Seconds(), uptime.WindowConnectedSeconds, 0.001)
+		assert.InDelta(t, 100*20.0/24.0, uptime.WindowUptimePercentage, 0.001)
+		mockAgentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("rejects an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		// when
+		uptime, err := service.GetAgentUptime(ctx, "other", instanceUID)
+
+		// then
+		require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+		assert.Nil(t, uptime)
+	})
+}
+
+func TestService_GetAgentEffectiveConfigHistory(t *testing.T) {
+	t.Parallel()
+
+	// given
+	ctx := t.Context()
+	mockAgentUsecase := new(MockAgentUsecase)
+	mockNotificationUsecase := new(MockAgentNotificationUsecase)
+	service := agent.New(
+		mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+		noopCacheInvalidationPublisher{}, slog.Default())
+
+	instanceUID := uuid.New()
+	domainAgent := agentmodel.NewAgent(instanceUID)
+	reportedAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	domainAgent.RecordEffectiveConfig(&agentmodel.AgentEffectiveConfig{
+		ConfigMap: agentmodel.AgentConfigMap{
+			ConfigMap: map[string]agentmodel.AgentConfigFile{
+				"collector.yaml": {Body: []byte("a: 1"), ContentType: "text/yaml"},
+			},
+		},
+	}, reportedAt, agentmodel.DefaultEffectiveConfigHistoryLimits())
+
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(domainAgent, nil)
+
+	// when
+	history, err := service.GetAgentEffectiveConfigHistory(ctx, "default", instanceUID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, instanceUID, history.InstanceUID)
+	require.Len(t, history.Items, 1)
+	assert.Equal(t, reportedAt, history.Items[0].ReportedAt.UTC())
+	assert.Len(t, history.Items[0].Hash, 64)
+	assert.Equal(t, "a: 1", history.Items[0].EffectiveConfig.ConfigMap.ConfigMap["collector.yaml"].Body)
+	mockAgentUsecase.AssertExpectations(t)
+}
+
+// stubEffectiveConfigWatcher hands out a single watch whose changes the test triggers.
+type stubEffectiveConfigWatcher struct {
+	changes chan struct{}
+	stopped chan struct{}
+}
+
+func (w *stubEffectiveConfigWatcher) WatchAgentEffectiveConfig(uuid.UUID) (<-chan struct{}, func()) {
+	return w.changes, func() { close(w.stopped) }
+}
+
+func TestService_WatchAgentEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	// given
+	ctx, cancel := context.WithCancel(t.Context())
+	mockAgentUsecase := new(MockAgentUsecase)
+	watcher := &stubEffectiveConfigWatcher{changes: make(chan struct{}, 1), stopped: make(chan struct{})}
+	service := agent.New(
+		mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+		noopCacheInvalidationPublisher{}, slog.Default())
+	service.SetAgentEffectiveConfigWatcher(watcher)
+
+	instanceUID := uuid.New()
+	withConfig := func(body string) *agentmodel.Agent {
+		domainAgent := agentmodel.NewAgent(instanceUID)
+		domainAgent.Status.EffectiveConfig = agentmodel.AgentEffectiveConfig{
+			ConfigMap: agentmodel.AgentConfigMap{
+				ConfigMap: map[string]agentmodel.AgentConfigFile{
+					"collector.yaml": {Body: []byte(body), ContentType: "text/yaml"},
+				},
+			},
+		}
+
+		return domainAgent
+	}
+
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(withConfig("a: 1"), nil).Once()
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(withConfig("a: 2"), nil)
+
+	// when
+	configs, err := service.WatchAgentEffectiveConfig(ctx, "default", instanceUID)
+	require.NoError(t, err)
+
+	snapshot := <-configs
+	watcher.changes <- struct{}{}
+	change := <-configs
+
+	cancel()
+
+	// then
+	assert.Equal(t, "a: 1", snapshot.ConfigMap.ConfigMap["collector.yaml"].Body)
+	assert.Equal(t, "a: 2", change.ConfigMap.ConfigMap["collector.yaml"].Body)
+
+	_, open := <-configs
+	assert.False(t, open, "the channel closes once the watch context is done")
+	<-watcher.stopped
+}
+
+func TestService_WatchAgentEffectiveConfig_RejectsAgentInAnotherNamespace(t *testing.T) {
+	t.Parallel()
+
+	// given
+	ctx := t.Context()
+	mockAgentUsecase := new(MockAgentUsecase)
+	watcher := &stubEffectiveConfigWatcher{changes: make(chan struct{}), stopped: make(chan struct{})}
+	service := agent.New(
+		mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+		noopCacheInvalidationPublisher{}, slog.Default())
+	service.SetAgentEffectiveConfigWatcher(watcher)
+
+	instanceUID := uuid.New()
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+	// when
+	configs, err := service.WatchAgentEffectiveConfig(ctx, "other", instanceUID)
+
+	// then
+	require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+	assert.Nil(t, configs)
+	<-watcher.stopped
+}
+
 func TestService_DeleteAgent(t *testing.T) {
 	t.Parallel()
 
@@ -366,6 +605,17 @@ func (s *spyCacheInvalidationPublisher) BroadcastAgentCacheInvalidation(
 	return nil
 }
 
+// spyDisconnectPublisher records the agents it was asked to disconnect.
+type spyDisconnectPublisher struct {
+	requested []uuid.UUID
+}
+
+func (s *spyDisconnectPublisher) RequestAgentDisconnect(_ context.Context, agnt *agentmodel.Agent) error {
+	s.requested = append(s.requested, agnt.Metadata.InstanceUID)
+
+	return nil
+}
+
 func TestService_DeleteAgent_BroadcastsCacheInvalidation(t *testing.T) {
 	t.Parallel()
 
@@ -386,3 +636,503 @@ func TestService_DeleteAgent_BroadcastsCacheInvalidation(t *testing.T) {
 	require.Len(t, spy.broadcasted, 1)
 	assert.Equal(t, instanceUID, spy.broadcasted[0])
 }
+
+func TestService_UpdateAgent_RejectsMalformedNewInstanceUID(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockAgentUsecase := new(MockAgentUsecase)
+	mockNotificationUsecase := new(MockAgentNotificationUsecase)
+	service := agent.New(
+		mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+		noopCacheInvalidationPublisher{}, slog.Default())
+
+	instanceUID := uuid.New()
+	domainAgent := agentmodel.NewAgent(instanceUID)
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(domainAgent, nil)
+
+	//exhaustruct:ignore
+	_, err := service.UpdateAgent(ctx, "default", instanceUID, &v1.Agent{
+		Spec: v1.AgentSpec{NewInstanceUID: "not-a-uuid"},
+	})
+
+	// A malformed UID surfaces as an invalid-argument error (400) rather than being
+	// silently dropped as uuid.Nil, and nothing is persisted.
+	require.ErrorIs(t, err, model.ErrInvalidArgument)
+	mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+}
+
+func TestService_UpdateAgent_RejectsNewInstanceUIDInUse(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockAgentUsecase := new(MockAgentUsecase)
+	mockNotificationUsecase := new(MockAgentNotificationUsecase)
+	service := agent.New(
+		mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+		noopCacheInvalidationPublisher{}, slog.Default())
+
+	instanceUID := uuid.New()
+	takenUID := uuid.New()
+	mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+	mockAgentUsecase.On("CheckNewInstanceUIDAvailable", ctx, instanceUID, takenUID).
+		Return(applicationport.ErrNewInstanceUIDInUse)
+
+	//exhaustruct:ignore
+	_, err := service.UpdateAgent(ctx, "default", instanceUID, &v1.Agent{
+		Spec: v1.AgentSpec{NewInstanceUID: takenUID.String()},
+	})
+
+	require.ErrorIs(t, err, applicationport.ErrNewInstanceUIDInUse)
+	mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+	mockAgentUsecase.AssertExpectations(t)
+}
+
+func TestService_RequestAgentReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records the request and notifies the agent", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityReportsHealth)
+		existing := agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities))
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+		mockAgentUsecase.On("SaveAgent", ctx, existing).Return(nil)
+		mockNotificationUsecase.On("NotifyAgentUpdated", ctx, existing).Return(nil)
+
+		result, err := service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindHealth)
+
+		require.NoError(t, err)
+		assert.Equal(t, []v1.AgentReportKind{v1.AgentReportKindHealth}, result.Spec.PendingReports)
+		mockAgentUsecase.AssertExpectations(t)
+		mockNotificationUsecase.AssertExpectations(t)
+	})
+
+	t.Run("rejects a report the agent cannot send", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		_, err := service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindEffectiveConfig)
+
+		require.ErrorIs(t, err, applicationport.ErrUnsupportedAgentOperation)
+		mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a command once the agent's queue is full", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+		service.SetMaxPendingCommands(2)
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityReportsEffectiveConfig |
+			modelagent.AgentCapabilityReportsHealth | modelagent.AgentCapabilityReportsAvailableComponents)
+		existing := agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities))
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+		mockAgentUsecase.On("SaveAgent", ctx, existing).Return(nil)
+		mockNotificationUsecase.On("NotifyAgentUpdated", ctx, existing).Return(nil)
+
+		// The agent is offline, so nothing drains the queue while it fills up to the cap.
+		_, err := service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindEffectiveConfig)
+		require.NoError(t, err)
+		_, err = service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindHealth)
+		require.NoError(t, err)
+		assert.Equal(t, 2, existing.PendingCommandCount())
+
+		_, err = service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindAvailableComponents)
+		require.ErrorIs(t, err, applicationport.ErrAgentCommandQueueFull)
+		assert.False(t, existing.HasPendingReport(agentmodel.AgentReportKindAvailableComponents))
+		mockAgentUsecase.AssertNumberOfCalls(t, "SaveAgent", 2)
+
+		// Repeating a request that is still pending queues nothing, so it is not rejected.
+		_, err = service.RequestAgentReport(ctx, "default", instanceUID, v1.AgentReportKindHealth)
+		require.NoError(t, err)
+	})
+}
+
+func TestService_GetAgent_ConfigInSync(t *testing.T) {
+	t.Parallel()
+
+	capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsRemoteConfig)
+	newAgent := func(t *testing.T) *agentmodel.Agent {
+		t.Helper()
+
+		domainAgent := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+		require.NoError(t, domainAgent.ApplyRemoteConfig("collector.yaml", agentmodel.AgentConfigFile{
+			Body:        []byte("receivers: {}"),
+			ContentType: "application/yaml",
+		}))
+
+		return domainAgent
+	}
+
+	desiredHash, err := newAgent(t).DesiredRemoteConfigHash()
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		reported agentmodel.AgentRemoteConfigStatus
+		want     bool
+	}{
+		"applied desired hash": {
+			reported: agentmodel.AgentRemoteConfigStatus{
+				LastRemoteConfigHash: desiredHash, Status: agentmodel.RemoteConfigStatusApplied,
+			},
+			want: true,
+		},
+		"applied stale hash": {
+			reported: agentmodel.AgentRemoteConfigStatus{
+				LastRemoteConfigHash: []byte("stale"), Status: agentmodel.RemoteConfigStatusApplied,
+			},
+			want: false,
+		},
+		"desired hash failed to apply": {
+			reported: agentmodel.AgentRemoteConfigStatus{
+				LastRemoteConfigHash: desiredHash, Status: agentmodel.RemoteConfigStatusFailed,
+			},
+			want: false,
+		},
+		"nothing reported": {
+			//exhaustruct:ignore
+			reported: agentmodel.AgentRemoteConfigStatus{},
+			want:     false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			mockAgentUsecase := new(MockAgentUsecase)
+			service := agent.New(
+				mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+				noopCacheInvalidationPublisher{}, slog.Default())
+
+			domainAgent := newAgent(t)
+			domainAgent.Status.RemoteConfigStatus = tc.reported
+
+			mockAgentUsecase.On("GetAgent", ctx, domainAgent.Metadata.InstanceUID).Return(domainAgent, nil)
+
+			got, err := service.GetAgent(ctx, "default", domainAgent.Metadata.InstanceUID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.Status.ConfigInSync)
+		})
+	}
+
+	t.Run("an agent offered no remote config is in sync", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		service := agent.New(
+			mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		domainAgent := agentmodel.NewAgent(uuid.New())
+		mockAgentUsecase.On("GetAgent", ctx, domainAgent.Metadata.InstanceUID).Return(domainAgent, nil)
+
+		got, err := service.GetAgent(ctx, "default", domainAgent.Metadata.InstanceUID)
+		require.NoError(t, err)
+		assert.True(t, got.Status.ConfigInSync)
+	})
+}
+
+func TestService_ResendAgentRemoteConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("offers the stored remote config again", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsRemoteConfig)
+		existing := agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities))
+		require.NoError(t, existing.ApplyRemoteConfig("collector.yaml", agentmodel.AgentConfigFile{
+			Body:        []byte("receivers: {}"),
+			ContentType: "application/yaml",
+		}))
+
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+		mockAgentUsecase.On("SaveAgent", ctx, existing).Return(nil)
+
+		var notified *agentmodel.Agent
+
+		mockNotificationUsecase.On("NotifyAgentUpdated", ctx, existing).
+			Run(func(args mock.Arguments) {
+				notified = args.Get(1).(*agentmodel.Agent) //nolint:forcetypeassert // set up above
+			}).
+			Return(nil)
+
+		_, err := service.ResendAgentRemoteConfig(ctx, "default", instanceUID)
+
+		require.NoError(t, err)
+		mockAgentUsecase.AssertExpectations(t)
+		mockNotificationUsecase.AssertExpectations(t)
+
+		// The notified agent is what the server holding the connection builds the offer from.
+		msg := agentservice.NewServerToAgentBuilder(nil, slog.Default()).Build(ctx, notified)
+		require.NotNil(t, msg.GetRemoteConfig())
+
+		configFile, ok := msg.GetRemoteConfig().GetConfig().GetConfigMap()["collector.yaml"]
+		require.True(t, ok)
+		assert.Equal(t, []byte("receivers: {}"), configFile.GetBody())
+	})
+
+	t.Run("rejects an agent with no remote config", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		_, err := service.ResendAgentRemoteConfig(ctx, "default", instanceUID)
+
+		require.ErrorIs(t, err, applicationport.ErrAgentHasNoRemoteConfig)
+		mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+		mockNotificationUsecase.AssertNotCalled(t, "NotifyAgentUpdated", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsRemoteConfig)
+		existing := agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities))
+		require.NoError(t, existing.ApplyRemoteConfig("collector.yaml", agentmodel.AgentConfigFile{
+			Body:        []byte("receivers: {}"),
+			ContentType: "application/yaml",
+		}))
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+
+		_, err := service.ResendAgentRemoteConfig(ctx, "other", instanceUID)
+
+		require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+		mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+		mockNotificationUsecase.AssertNotCalled(t, "NotifyAgentUpdated", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_SetAgentDirectRemoteConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("offers the direct config next to the group-derived one", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsRemoteConfig)
+		existing := agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities))
+		require.NoError(t, existing.ApplyRemoteConfig("production/collector", agentmodel.AgentConfigFile{
+			Body:        []byte("receivers: {}"),
+			ContentType: "text/yaml",
+		}))
+
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+		mockAgentUsecase.On("SaveAgent", ctx, existing).Return(nil)
+		mockNotificationUsecase.On("NotifyAgentUpdated", ctx, existing).Return(nil)
+
+		desired, err := service.SetAgentDirectRemoteConfig(ctx, "default", instanceUID, &v1.AgentConfigMap{
+			ConfigMap: map[string]v1.AgentConfigFile{
+				"debug": {Body: "log_level: debug", ContentType: "text/yaml", Primary: false},
+			},
+		})
+
+		require.NoError(t, err)
+		mockAgentUsecase.AssertExpectations(t)
+		mockNotificationUsecase.AssertExpectations(t)
+
+		assert.Len(t, desired.ConfigMap, 2)
+		assert.Equal(t, "receivers: {}", desired.ConfigMap["production/collector"].Body)
+		assert.Equal(t, "log_level: debug", desired.ConfigMap["@agent/debug"].Body)
+		assert.Equal(t, v1.AgentDesiredConfigSourceAgent, desired.ConfigMap["@agent/debug"].Source.Kind)
+	})
+
+	t.Run("rejects an agent that does not accept remote config", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		_, err := service.SetAgentDirectRemoteConfig(ctx, "default", instanceUID, &v1.AgentConfigMap{
+			ConfigMap: map[string]v1.AgentConfigFile{
+				"debug": {Body: "log_level: debug", ContentType: "text/yaml", Primary: false},
+			},
+		})
+
+		require.ErrorIs(t, err, applicationport.ErrUnsupportedAgentOperation)
+		mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		mockNotificationUsecase := new(MockAgentNotificationUsecase)
+		service := agent.New(
+			mockAgentUsecase, mockNotificationUsecase, stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsRemoteConfig)
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).
+			Return(agentmodel.NewAgent(instanceUID, agentmodel.WithCapabilities(&capabilities)), nil)
+
+		_, err := service.SetAgentDirectRemoteConfig(ctx, "other", instanceUID, &v1.AgentConfigMap{
+			ConfigMap: map[string]v1.AgentConfigFile{
+				"debug": {Body: "log_level: debug", ContentType: "text/yaml", Primary: false},
+			},
+		})
+
+		require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+		mockAgentUsecase.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_GetAgentDesiredConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		service := agent.New(
+			mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		desired, err := service.GetAgentDesiredConfig(ctx, "other", instanceUID)
+
+		require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+		assert.Nil(t, desired)
+	})
+}
+
+func TestService_ReconnectAgent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("asks the server holding the connection to close it", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		publisher := new(spyDisconnectPublisher)
+		service := agent.New(
+			mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+		service.SetAgentDisconnectPublisher(publisher)
+
+		instanceUID := uuid.New()
+		existing := agentmodel.NewAgent(instanceUID)
+		existing.Status.Connected = true
+		existing.Status.LastReportedAt = time.Now()
+		existing.Status.LastReportedTo = "server-1"
+
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+
+		_, err := service.ReconnectAgent(ctx, "default", instanceUID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{instanceUID}, publisher.requested)
+	})
+
+	t.Run("rejects an agent that is not connected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		publisher := new(spyDisconnectPublisher)
+		service := agent.New(
+			mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+		service.SetAgentDisconnectPublisher(publisher)
+
+		instanceUID := uuid.New()
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		_, err := service.ReconnectAgent(ctx, "default", instanceUID)
+
+		require.ErrorIs(t, err, applicationport.ErrAgentNotConnected)
+		assert.Empty(t, publisher.requested)
+	})
+
+	t.Run("rejects an agent in another namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUsecase := new(MockAgentUsecase)
+		publisher := new(spyDisconnectPublisher)
+		service := agent.New(
+			mockAgentUsecase, new(MockAgentNotificationUsecase), stubEndpointDetectionUsecase{},
+			noopCacheInvalidationPublisher{}, slog.Default())
+		service.SetAgentDisconnectPublisher(publisher)
+
+		instanceUID := uuid.New()
+		existing := agentmodel.NewAgent(instanceUID)
+		existing.Status.Connected = true
+		existing.Status.LastReportedAt = time.Now()
+		existing.Status.LastReportedTo = "server-1"
+
+		mockAgentUsecase.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+
+		_, err := service.ReconnectAgent(ctx, "other", instanceUID)
+
+		require.ErrorIs(t, err, applicationport.ErrAgentNamespaceMismatch)
+		assert.Empty(t, publisher.requested)
+	})
+}
@@ -0,0 +1,84 @@
+// Package agentexpectedattributes provides the application service for the attributes an
+// agent is expected to keep reporting.
+package agentexpectedattributes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var _ usecase.AgentExpectedAttributesManageUsecase = (*Service)(nil)
+
+// Service implements usecase.AgentExpectedAttributesManageUsecase. It resolves the agent
+// within the caller's namespace and the acting user, and delegates the rest to the domain
+// AgentExpectedAttributesUsecase.
+type Service struct {
+	agentUsecase                   agentport.AgentUsecase
+	agentExpectedAttributesUsecase agentport.AgentExpectedAttributesUsecase
+
+	mapper *helper.Mapper
+	logger *slog.Logger
+}
+
+// New creates a new agent expected attributes application service.
+func New(
+	agentUsecase agentport.AgentUsecase,
+	agentExpectedAttributesUsecase agentport.AgentExpectedAttributesUsecase,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		agentUsecase:                   agentUsecase,
+		agentExpectedAttributesUsecase: agentExpectedAttributesUsecase,
+		mapper:                         helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness),
+		logger:                         logger,
+	}
+}
+
+// SetAgentExpectedAttributes implements [usecase.AgentExpectedAttributesManageUsecase].
+func (s *Service) SetAgentExpectedAttributes(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+	attributes map[string]string,
+) (*v1.Agent, error) {
+	agent, err := s.agentUsecase.GetAgent(ctx, instanceUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	// An agent in another namespace is reported as not found.
+	if agent.Metadata.Namespace != namespace {
+		return nil, fmt.Errorf("failed to get agent: %w", applicationport.ErrAgentNamespaceMismatch)
+	}
+
+	err = s.agentExpectedAttributesUsecase.SetExpectedAttributes(ctx, agent, attributes, s.actor(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set expected attributes: %w", err)
+	}
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// actor resolves the acting user, falling back to an anonymous identity.
+func (s *Service) actor(ctx context.Context) string {
+	user, err := security.GetUser(ctx)
+	if err != nil {
+		s.logger.Warn("failed to get user from context", slog.String("error", err.Error()))
+
+		user = security.NewAnonymousUser()
+	}
+
+	return user.String()
+}
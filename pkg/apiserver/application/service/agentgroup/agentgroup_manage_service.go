@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -22,37 +24,68 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
+// agentGroupFailuresPageSize is how many of an agent group's agents are read at a time
+// while looking for the ones that failed to apply its remote configs.
+const agentGroupFailuresPageSize = 500
+
 // ErrAgentGroupAlreadyExists is returned when an agent group with the same name already exists.
-var ErrAgentGroupAlreadyExists = errors.New("agent group already exists")
+// It wraps model.ErrResourceAlreadyExist, so the HTTP layer answers 409 Conflict.
+var ErrAgentGroupAlreadyExists = fmt.Errorf("agent group %w", model.ErrResourceAlreadyExist)
 
 var _ usecase.AgentGroupManageUsecase = (*ManageService)(nil)
 
 // ManageService implements usecase.AgentGroupManageUsecase. You can inject repository or other dependencies as needed.
 type ManageService struct {
-	agentgroupUsecase agentport.AgentGroupUsecase
-	agentUsecase      agentport.AgentUsecase
-	mapper            *helper.Mapper
-	sanityFilter      *filter.Sanity
-	clock             clock.Clock
-	logger            *slog.Logger
+	agentgroupUsecase   agentport.AgentGroupUsecase
+	agentUsecase        agentport.AgentUsecase
+	remoteConfigUsecase agentport.AgentRemoteConfigUsecase
+	changePublisher     agentport.AgentGroupChangePublisher
+	quotaUsecase        agentport.ResourceQuotaUsecase
+	mapper              *helper.Mapper
+	sanityFilter        *filter.Sanity
+	clock               clock.Clock
+	logger              *slog.Logger
+
+	// nameUsecase, when set, rejects creating a group whose name differs from an existing
+	// group in the same namespace only by case.
+	nameUsecase agentport.AgentGroupNameUsecase
 }
 
 // NewManageService returns a new ManageService.
 func NewManageService(
 	agentgroupUsecase agentport.AgentGroupUsecase,
 	agentUsecase agentport.AgentUsecase,
+	remoteConfigUsecase agentport.AgentRemoteConfigUsecase,
+	changePublisher agentport.AgentGroupChangePublisher,
+	quotaUsecase agentport.ResourceQuotaUsecase,
 	logger *slog.Logger,
 ) *ManageService {
 	realClock := clock.NewRealClock()
 
 	return &ManageService{
-		agentgroupUsecase: agentgroupUsecase,
-		agentUsecase:      agentUsecase,
-		mapper:            helper.NewMapper(realClock, agentmodel.DefaultConnectionStaleness),
-		sanityFilter:      filter.NewSanity(),
-		clock:             realClock,
-		logger:            logger,
+		agentgroupUsecase:   agentgroupUsecase,
+		agentUsecase:        agentUsecase,
+		remoteConfigUsecase: remoteConfigUsecase,
+		changePublisher:     changePublisher,
+		quotaUsecase:        quotaUsecase,
+		mapper:              helper.NewMapper(realClock, agentmodel.DefaultConnectionStaleness),
+		sanityFilter:        filter.NewSanity(),
+		clock:               realClock,
+		logger:              logger,
+
+		nameUsecase: nil,
+	}
+}
+
+// SetCaseInsensitiveNames makes CreateAgentGroup reject a name that differs from an
+// existing group in the same namespace only by case, e.g. "prod" next to "Prod".
+// nameUsecase looks up such names; it is unused when enabled is false.
+func (s *ManageService) SetCaseInsensitiveNames(enabled bool, nameUsecase agentport.AgentGroupNameUsecase) {
+	if !enabled {
+		nameUsecase = nil
 	}
+
+	s.nameUsecase = nameUsecase
 }
 
 // GetAgentGroup returns an agent group by its namespace and name.
@@ -123,6 +156,35 @@ func (s *ManageService) ListAgentsByAgentGroup(
 	}, nil
 }
 
+// PreviewAgentSelector implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) PreviewAgentSelector(
+	ctx context.Context,
+	selector *v1.AgentSelector,
+	sampleSize int64,
+) (*v1.AgentSelectorPreview, error) {
+	domainSelector := s.mapper.MapAPIToAgentSelector(selector)
+
+	err := domainSelector.Validate("")
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent selector: %w", err)
+	}
+
+	//exhaustruct:ignore
+	domainResp, err := s.agentUsecase.ListAgentsBySelector(ctx, domainSelector, &model.ListOptions{
+		Limit: sampleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list agents by selector: %w", err)
+	}
+
+	return &v1.AgentSelectorPreview{
+		Count: int64(len(domainResp.Items)) + domainResp.RemainingItemCount,
+		Agents: lo.Map(domainResp.Items, func(agent *agentmodel.Agent, _ int) v1.Agent {
+			return *s.mapper.MapAgentToAPI(agent)
+		}),
+	}, nil
+}
+
 // ListAgentGroupsByAgent lists the agent groups in the given namespace whose selector matches
 // the agent identified by instanceUID. It returns port.ErrAgentNamespaceMismatch when the agent
 // exists but in a different namespace, so the HTTP layer can map that to a 404.
@@ -171,6 +233,18 @@ func (s *ManageService) CreateAgentGroup(
 		return nil, fmt.Errorf("%w: %s/%s", ErrAgentGroupAlreadyExists, namespace, name)
 	}
 
+	if s.nameUsecase != nil {
+		err := s.checkNameDiffersInCase(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.quotaUsecase.CheckResourceQuota(ctx, agentmodel.QuotaResourceAgentGroup)
+	if err != nil {
+		return nil, fmt.Errorf("create agent group: %w", err)
+	}
+
 	createdBy, err := security.GetUser(ctx)
 	if err != nil {
 		s.logger.Warn("failed to get user from context", slog.String("error", err.Error()))
@@ -179,6 +253,20 @@ func (s *ManageService) CreateAgentGroup(
 	}
 
 	domainAgentGroup := s.mapper.MapAPIToAgentGroup(agentGroup)
+	domainAgentGroup.InheritRolloutBaseline(nil)
+
+	err = s.validateRemoteConfigRefs(ctx, domainAgentGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rollout kept as it was keeps the agents it already covers.
+	if domainAgentGroup.NeedsRolloutThreshold() {
+		err = s.resolveRolloutThreshold(ctx, domainAgentGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Set the created condition with createdBy information
 	now := s.clock.Now()
@@ -201,7 +289,28 @@ func (s *ManageService) CreateAgentGroup(
 	return s.mapper.MapAgentGroupToAPI(domainAgentGroup), nil
 }
 
+// checkNameDiffersInCase fails with ErrAgentGroupAlreadyExists when an agent group in
+// namespace already has name, or a name equal to it ignoring case.
+func (s *ManageService) checkNameDiffersInCase(ctx context.Context, namespace, name string) error {
+	existingNames, err := s.nameUsecase.ListAgentGroupNamesIgnoringCase(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("list agent group names: %w", err)
+	}
+
+	if slices.Contains(existingNames, name) {
+		return fmt.Errorf("%w: %s/%s", ErrAgentGroupAlreadyExists, namespace, name)
+	}
+
+	if len(existingNames) > 0 {
+		return fmt.Errorf("%w: %s/%s differs from %q only in case",
+			ErrAgentGroupAlreadyExists, namespace, name, existingNames[0])
+	}
+
+	return nil
+}
+
 // UpdateAgentGroup updates an existing agent group.
+// A group that does not exist, or was deleted, is not created and yields model.ErrResourceNotExist.
 func (s *ManageService) UpdateAgentGroup(
 	ctx context.Context,
 	namespace string,
@@ -225,6 +334,21 @@ func (s *ManageService) UpdateAgentGroup(
 
 	// Sanitize: preserve immutable fields from existing agent group
 	domainAgentGroup = s.sanityFilter.Sanitize(existingAgentGroup, domainAgentGroup)
+	domainAgentGroup.InheritRolloutBaseline(existingAgentGroup)
+	domainAgentGroup.RememberPreviousRemoteConfigs(existingAgentGroup)
+
+	err = s.validateRemoteConfigRefs(ctx, domainAgentGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rollout kept as it was keeps the agents it already covers.
+	if domainAgentGroup.NeedsRolloutThreshold() {
+		err = s.resolveRolloutThreshold(ctx, domainAgentGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	now := s.clock.Now()
 	updatedCondition := model.Condition{
@@ -256,9 +380,67 @@ func (s *ManageService) UpdateAgentGroup(
 		return nil, fmt.Errorf("update agent group: %w", err)
 	}
 
+	s.publishConfigChange(ctx, existingAgentGroup, updatedAgentGroup, updatedBy.String(), now)
+
 	return s.mapper.MapAgentGroupToAPI(updatedAgentGroup), nil
 }
 
+// validateRemoteConfigRefs rejects AgentRemoteConfigRef entries that could only fail later,
+// at propagation time: a ref that does not resolve to a live AgentRemoteConfig in the group's
+// namespace, or one that refers to its own entry. AgentRemoteConfigs hold no refs themselves,
+// so an entry naming itself is the only cycle a ref can form.
+func (s *ManageService) validateRemoteConfigRefs(ctx context.Context, agentGroup *agentmodel.AgentGroup) error {
+	for i, remoteConfig := range agentGroup.Spec.AgentRemoteConfigs {
+		if remoteConfig.AgentRemoteConfigRef == nil {
+			continue
+		}
+
+		ref := *remoteConfig.AgentRemoteConfigRef
+		field := fmt.Sprintf("spec.agentConfig.agentRemoteConfigs[%d].agentRemoteConfigRef", i)
+
+		if remoteConfig.AgentRemoteConfigName != nil && *remoteConfig.AgentRemoteConfigName == ref {
+			return &model.FieldError{Field: field, Value: ref, Reason: "agent remote config ref refers to its own entry"}
+		}
+
+		arc, err := s.remoteConfigUsecase.GetAgentRemoteConfig(ctx, agentGroup.Metadata.Namespace, ref, nil)
+		if err != nil && !errors.Is(err, model.ErrResourceNotExist) {
+			return fmt.Errorf("resolve agent remote config ref %q: %w", ref, err)
+		}
+
+		if err != nil || arc.IsDeleted() {
+			return &model.FieldError{
+				Field:  field,
+				Value:  ref,
+				Reason: fmt.Sprintf("agent remote config %q does not exist in namespace %q", ref, agentGroup.Metadata.Namespace),
+			}
+		}
+	}
+
+	return nil
+}
+
+// publishConfigChange announces which config keys the update added, removed or modified.
+// The update is already saved, so a failure to publish is only logged.
+func (s *ManageService) publishConfigChange(
+	ctx context.Context,
+	before, after *agentmodel.AgentGroup,
+	changedBy string,
+	changedAt time.Time,
+) {
+	change := agentmodel.NewAgentGroupConfigChange(before, after, changedBy, changedAt)
+	if change.IsEmpty() {
+		return
+	}
+
+	err := s.changePublisher.PublishAgentGroupChange(ctx, change)
+	if err != nil {
+		s.logger.Warn("failed to publish agent group change",
+			slog.String("namespace", change.Namespace),
+			slog.String("name", change.Name),
+			slog.String("error", err.Error()))
+	}
+}
+
 // DeleteAgentGroup marks an agent group as deleted.
 func (s *ManageService) DeleteAgentGroup(
 	ctx context.Context,
@@ -281,3 +463,232 @@ func (s *ManageService) DeleteAgentGroup(
 
 	return nil
 }
+
+// PropagateAgentGroup implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) PropagateAgentGroup(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*v1.AgentGroupPropagationResult, error) {
+	propagation, err := s.agentgroupUsecase.PropagateAgentGroup(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("propagate agent group: %w", err)
+	}
+
+	if len(propagation.Failed) > 0 {
+		s.logger.Warn("agent group propagation failed for some agents",
+			slog.String("namespace", namespace),
+			slog.String("name", name),
+			slog.Int("failed", len(propagation.Failed)))
+	}
+
+	return mapPropagationToAPI(propagation), nil
+}
+
+// ApplyAgentGroupToAgents implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) ApplyAgentGroupToAgents(
+	ctx context.Context,
+	namespace string,
+	name string,
+	request *v1.AgentGroupApplyRequest,
+) (*v1.AgentGroupPropagationResult, error) {
+	if len(request.InstanceUIDs) == 0 {
+		return nil, &model.FieldError{Field: "instanceUids", Value: request.InstanceUIDs, Reason: "must not be empty"}
+	}
+
+	propagation, err := s.agentgroupUsecase.ApplyAgentGroupToAgents(ctx, namespace, name, request.InstanceUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("apply agent group to agents: %w", err)
+	}
+
+	if len(propagation.Failed) > 0 {
+		s.logger.Warn("applying agent group failed for some agents",
+			slog.String("namespace", namespace),
+			slog.String("name", name),
+			slog.Int("failed", len(propagation.Failed)))
+	}
+
+	return mapPropagationToAPI(propagation), nil
+}
+
+func mapPropagationToAPI(propagation *agentmodel.AgentGroupPropagation) *v1.AgentGroupPropagationResult {
+	return &v1.AgentGroupPropagationResult{
+		Updated:   propagation.Updated,
+		Unchanged: propagation.Unchanged,
+		Failed: lo.Map(propagation.Failed,
+			func(failure agentmodel.AgentPropagationFailure, _ int) v1.AgentGroupPropagationFailure {
+				return v1.AgentGroupPropagationFailure{
+					InstanceUID: failure.InstanceUID,
+					Error:       failure.Err.Error(),
+				}
+			}),
+	}
+}
+
+// ListAgentGroupFailures implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) ListAgentGroupFailures(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*v1.AgentGroupFailures, error) {
+	agentGroup, err := s.agentgroupUsecase.GetAgentGroup(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent group: %w", err)
+	}
+
+	failures := []v1.AgentGroupFailure{}
+
+	var continueToken string
+
+	for {
+		//exhaustruct:ignore
+		agentsResp, err := s.agentUsecase.ListAgentsBySelector(ctx, agentGroup.Spec.Selector, &model.ListOptions{
+			Limit:    agentGroupFailuresPageSize,
+			Continue: continueToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list agents by agent group: %w", err)
+		}
+
+		for _, agent := range agentsResp.Items {
+			failure, failed, err := s.agentGroupFailure(ctx, agentGroup, agent)
+			if err != nil {
+				return nil, err
+			}
+
+			if failed {
+				failures = append(failures, failure)
+			}
+		}
+
+		if agentsResp.Continue == "" {
+			break
+		}
+
+		continueToken = agentsResp.Continue
+	}
+
+	return &v1.AgentGroupFailures{Items: failures}, nil
+}
+
+// agentGroupFailure reports whether the agent failed to apply a remote config holding the
+// agent group's configs. An agent whose failing config holds no config from the group, e.g.
+// because another group's config of the same name wins, is not a failure of the group.
+func (s *ManageService) agentGroupFailure(
+	ctx context.Context,
+	agentGroup *agentmodel.AgentGroup,
+	agent *agentmodel.Agent,
+) (v1.AgentGroupFailure, bool, error) {
+	status := agent.Status.RemoteConfigStatus
+	if status.Status != agentmodel.RemoteConfigStatusFailed {
+		return v1.AgentGroupFailure{}, false, nil
+	}
+
+	sources, err := s.agentgroupUsecase.GetRemoteConfigSources(ctx, agent)
+	if err != nil {
+		return v1.AgentGroupFailure{}, false, fmt.Errorf("get remote config sources of agent %s: %w",
+			agent.Metadata.InstanceUID, err)
+	}
+
+	var configNames []string
+
+	for configName, source := range sources {
+		if source.Metadata.Namespace == agentGroup.Metadata.Namespace &&
+			source.Metadata.Name == agentGroup.Metadata.Name {
+			configNames = append(configNames, configName)
+		}
+	}
+
+	if len(configNames) == 0 {
+		return v1.AgentGroupFailure{}, false, nil
+	}
+
+	slices.Sort(configNames)
+
+	return v1.AgentGroupFailure{
+		InstanceUID:       agent.Metadata.InstanceUID,
+		RemoteConfigNames: configNames,
+		ErrorMessage:      status.ErrorMessage,
+		ReportedAt:        v1.NewTime(status.LastUpdatedAt),
+	}, true, nil
+}
+
+// resolveRolloutThreshold fixes which agents the group's rollout covers, against the number
+// of agents its selector matches now. The group's statistics are not used, as they may be
+// cached or not computed yet, which would resolve a count against the wrong number of agents.
+func (s *ManageService) resolveRolloutThreshold(ctx context.Context, agentGroup *agentmodel.AgentGroup) error {
+	numAgents := 0
+
+	if agentGroup.Spec.Rollout.Count > 0 {
+		//exhaustruct:ignore
+		resp, err := s.agentUsecase.ListAgentsBySelector(ctx, agentGroup.Spec.Selector, &model.ListOptions{Limit: 1})
+		if err != nil {
+			return fmt.Errorf("count agents of agent group %s/%s: %w",
+				agentGroup.Metadata.Namespace, agentGroup.Metadata.Name, err)
+		}
+
+		numAgents = len(resp.Items) + int(resp.RemainingItemCount)
+	}
+
+	agentGroup.ResolveRolloutThreshold(numAgents)
+
+	return nil
+}
+
+// AdvanceAgentGroupRollout implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) AdvanceAgentGroupRollout(
+	ctx context.Context,
+	namespace string,
+	name string,
+	advance *v1.AgentGroupRolloutAdvance,
+) (*v1.AgentGroup, error) {
+	agentGroup, err := s.agentgroupUsecase.GetAgentGroup(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent group: %w", err)
+	}
+
+	err = agentGroup.AdvanceRollout(advance.Percentage, advance.Count)
+	if err != nil {
+		return nil, fmt.Errorf("advance rollout of agent group %s/%s: %w", namespace, name, err)
+	}
+
+	if agentGroup.HasRollout() {
+		err = s.resolveRolloutThreshold(ctx, agentGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Saving propagates the group, so the agents the rollout now covers get its configs.
+	agentGroup, err = s.agentgroupUsecase.SaveAgentGroup(ctx, namespace, name, agentGroup)
+	if err != nil {
+		return nil, fmt.Errorf("save agent group: %w", err)
+	}
+
+	return s.mapper.MapAgentGroupToAPI(agentGroup), nil
+}
+
+// RollbackAgentGroup implements usecase.AgentGroupManageUsecase.
+func (s *ManageService) RollbackAgentGroup(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*v1.AgentGroup, error) {
+	agentGroup, err := s.agentgroupUsecase.GetAgentGroup(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent group: %w", err)
+	}
+
+	err = agentGroup.RollbackRemoteConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("roll back agent group %s/%s: %w", namespace, name, err)
+	}
+
+	// Saving propagates the group, so its agents are offered the restored configs.
+	agentGroup, err = s.agentgroupUsecase.SaveAgentGroup(ctx, namespace, name, agentGroup)
+	if err != nil {
+		return nil, fmt.Errorf("save agent group: %w", err)
+	}
+
+	return s.mapper.MapAgentGroupToAPI(agentGroup), nil
+}
@@ -3,6 +3,7 @@ package agentgroup_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
 	agentgroupsvc "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentgroup"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
 )
@@ -100,6 +103,19 @@ func (m *mockAgentGroupUsecase) ApplyMatchingAgentGroupsToAgent(ctx context.Cont
 	return args.Error(0) //nolint:wrapcheck // mock error
 }
 
+func (m *mockAgentGroupUsecase) GetRemoteConfigSources(
+	ctx context.Context, agent *agentmodel.Agent,
+) (map[string]*agentmodel.AgentGroup, error) {
+	args := m.Called(ctx, agent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	sources, _ := args.Get(0).(map[string]*agentmodel.AgentGroup)
+
+	return sources, args.Error(1) //nolint:wrapcheck // mock error
+}
+
 func (m *mockAgentGroupUsecase) ReconcileAgent(ctx context.Context, agent *agentmodel.Agent) error {
 	args := m.Called(ctx, agent)
 
@@ -112,6 +128,41 @@ func (m *mockAgentGroupUsecase) ReconcileAgentGroup(ctx context.Context, namespa
 	return args.Error(0) //nolint:wrapcheck // mock error
 }
 
+func (m *mockAgentGroupUsecase) PropagateAgentGroup(
+	ctx context.Context,
+	namespace, name string,
+) (*agentmodel.AgentGroupPropagation, error) {
+	args := m.Called(ctx, namespace, name)
+	propagation, _ := args.Get(0).(*agentmodel.AgentGroupPropagation)
+
+	return propagation, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockAgentGroupUsecase) ApplyAgentGroupToAgents(
+	ctx context.Context,
+	namespace, name string,
+	instanceUIDs []uuid.UUID,
+) (*agentmodel.AgentGroupPropagation, error) {
+	args := m.Called(ctx, namespace, name, instanceUIDs)
+	propagation, _ := args.Get(0).(*agentmodel.AgentGroupPropagation)
+
+	return propagation, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+// mockAgentGroupNameUsecase is a mock implementation of agentport.AgentGroupNameUsecase.
+type mockAgentGroupNameUsecase struct {
+	mock.Mock
+}
+
+func (m *mockAgentGroupNameUsecase) ListAgentGroupNamesIgnoringCase(
+	ctx context.Context, namespace, name string,
+) ([]string, error) {
+	args := m.Called(ctx, namespace, name)
+	names, _ := args.Get(0).([]string)
+
+	return names, args.Error(1) //nolint:wrapcheck // mock error
+}
+
 // mockAgentUsecase is a mock implementation of agentport.AgentUsecase.
 type mockAgentUsecase struct {
 	mock.Mock
@@ -190,12 +241,118 @@ func (m *mockAgentUsecase) SearchAgents(
 	return resp, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *mockAgentUsecase) ListAgentsByPackage(
+	ctx context.Context, namespace string, packageName string, packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	resp, _ := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+
+	return resp, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockAgentUsecase) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockAgentUsecase) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
+// mockRemoteConfigUsecase mocks the AgentRemoteConfig lookups used to validate refs.
+// Methods the service does not call are left to the embedded nil interface.
+type mockRemoteConfigUsecase struct {
+	agentport.AgentRemoteConfigUsecase
+	mock.Mock
+}
+
+func (m *mockRemoteConfigUsecase) GetAgentRemoteConfig(
+	ctx context.Context, namespace, name string, options *model.GetOptions,
+) (*agentmodel.AgentRemoteConfig, error) {
+	args := m.Called(ctx, namespace, name, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	cfg, _ := args.Get(0).(*agentmodel.AgentRemoteConfig)
+
+	return cfg, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+// spyChangePublisher records the AgentGroup changes it was asked to publish.
+type spyChangePublisher struct {
+	changes []*agentmodel.AgentGroupConfigChange
+}
+
+func (s *spyChangePublisher) PublishAgentGroupChange(
+	_ context.Context, change *agentmodel.AgentGroupConfigChange,
+) error {
+	s.changes = append(s.changes, change)
+
+	return nil
+}
+
 func newSvc(t *testing.T, group *mockAgentGroupUsecase, agent *mockAgentUsecase) *agentgroupsvc.ManageService {
 	t.Helper()
 
+	return newSvcWithPublisher(t, group, agent, &spyChangePublisher{})
+}
+
+func newSvcWithPublisher(
+	t *testing.T, group *mockAgentGroupUsecase, agent *mockAgentUsecase, publisher *spyChangePublisher,
+) *agentgroupsvc.ManageService {
+	t.Helper()
+
 	base := testutil.NewBase(t)
 
-	return agentgroupsvc.NewManageService(group, agent, base.Logger)
+	return agentgroupsvc.NewManageService(
+		group, agent, new(mockRemoteConfigUsecase), publisher, unlimitedQuota(), base.Logger)
+}
+
+func newSvcWithRemoteConfigs(
+	t *testing.T, group *mockAgentGroupUsecase, remoteConfigs *mockRemoteConfigUsecase,
+) *agentgroupsvc.ManageService {
+	t.Helper()
+
+	base := testutil.NewBase(t)
+
+	return agentgroupsvc.NewManageService(
+		group, new(mockAgentUsecase), remoteConfigs, &spyChangePublisher{}, unlimitedQuota(), base.Logger)
+}
+
+// unlimitedQuota returns a quota service with no quota set, so it never counts anything.
+func unlimitedQuota() *agentservice.ResourceQuotaService {
+	return agentservice.NewResourceQuotaService(nil, nil)
+}
+
+// exhaustedQuota rejects every create as if each quota were used up.
+type exhaustedQuota struct{}
+
+func (exhaustedQuota) CheckResourceQuota(_ context.Context, resource agentmodel.QuotaResource) error {
+	return fmt.Errorf("%w: %s", model.ErrQuotaExceeded, resource)
+}
+
+func (exhaustedQuota) ListResourceQuotaUsage(context.Context) ([]agentmodel.ResourceQuotaUsage, error) {
+	return nil, nil
 }
 
 func newGroup() *agentmodel.AgentGroup {
@@ -334,6 +491,25 @@ func TestService_CreateAgentGroup(t *testing.T) {
 		mockGroup.AssertExpectations(t)
 	})
 
+	t.Run("rejects once the quota is used up", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		base := testutil.NewBase(t)
+		mockGroup := new(mockAgentGroupUsecase)
+		svc := agentgroupsvc.NewManageService(mockGroup, new(mockAgentUsecase), new(mockRemoteConfigUsecase),
+			&spyChangePublisher{}, exhaustedQuota{}, base.Logger)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+
+		result, err := svc.CreateAgentGroup(ctx, apiGroup())
+
+		require.ErrorIs(t, err, model.ErrQuotaExceeded)
+		assert.Nil(t, result)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("save error", func(t *testing.T) {
 		t.Parallel()
 
@@ -354,6 +530,210 @@ func TestService_CreateAgentGroup(t *testing.T) {
 	})
 }
 
+func TestService_CreateAgentGroup_CaseInsensitiveNames(t *testing.T) {
+	t.Parallel()
+
+	namedGroup := func(name string) *v1.AgentGroup {
+		return &v1.AgentGroup{
+			Kind:       v1.AgentGroupKind,
+			APIVersion: v1.APIVersion,
+			Metadata:   v1.Metadata{Namespace: "default", Name: name},
+		}
+	}
+
+	t.Run("rejects a name differing only in case when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		names := new(mockAgentGroupNameUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+		svc.SetCaseInsensitiveNames(true, names)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "prod", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		names.On("ListAgentGroupNamesIgnoringCase", ctx, "default", "prod").
+			Return([]string{"Prod"}, nil)
+
+		result, err := svc.CreateAgentGroup(ctx, namedGroup("prod"))
+		require.ErrorIs(t, err, agentgroupsvc.ErrAgentGroupAlreadyExists)
+		require.ErrorIs(t, err, model.ErrResourceAlreadyExist, "a collision is answered with 409")
+		assert.Contains(t, err.Error(), `differs from "Prod" only in case`)
+		assert.Nil(t, result)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockGroup.AssertNotCalled(t, "ListAgentGroups", mock.Anything, mock.Anything)
+		names.AssertExpectations(t)
+	})
+
+	t.Run("reports an exact duplicate as already existing", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		names := new(mockAgentGroupNameUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+		svc.SetCaseInsensitiveNames(true, names)
+
+		// The group is created between the lookup by name and the case-insensitive one.
+		mockGroup.On("GetAgentGroup", ctx, "default", "prod", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		names.On("ListAgentGroupNamesIgnoringCase", ctx, "default", "prod").
+			Return([]string{"Prod", "prod"}, nil)
+
+		result, err := svc.CreateAgentGroup(ctx, namedGroup("prod"))
+		require.ErrorIs(t, err, agentgroupsvc.ErrAgentGroupAlreadyExists)
+		assert.NotContains(t, err.Error(), "only in case")
+		assert.Nil(t, result)
+	})
+
+	t.Run("creates a name matching no other group when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		names := new(mockAgentGroupNameUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+		svc.SetCaseInsensitiveNames(true, names)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "prod", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		names.On("ListAgentGroupNamesIgnoringCase", ctx, "default", "prod").
+			Return([]string{}, nil)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "prod", mock.Anything).
+			Return(agentmodel.NewAgentGroup("default", "prod", nil, time.Now(), "tester"), nil)
+
+		_, err := svc.CreateAgentGroup(ctx, namedGroup("prod"))
+		require.NoError(t, err)
+		names.AssertExpectations(t)
+	})
+
+	t.Run("fails when the names cannot be looked up", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		names := new(mockAgentGroupNameUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+		svc.SetCaseInsensitiveNames(true, names)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "prod", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		names.On("ListAgentGroupNamesIgnoringCase", ctx, "default", "prod").
+			Return(nil, errMock)
+
+		result, err := svc.CreateAgentGroup(ctx, namedGroup("prod"))
+		require.ErrorIs(t, err, errMock)
+		assert.Nil(t, result)
+	})
+
+	t.Run("allows names differing only in case when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		names := new(mockAgentGroupNameUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+		svc.SetCaseInsensitiveNames(false, names)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "prod", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "prod", mock.Anything).
+			Return(agentmodel.NewAgentGroup("default", "prod", nil, time.Now(), "tester"), nil)
+
+		_, err := svc.CreateAgentGroup(ctx, namedGroup("prod"))
+		require.NoError(t, err)
+		names.AssertNotCalled(t, "ListAgentGroupNamesIgnoringCase", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_AgentGroupRemoteConfigRefValidation(t *testing.T) {
+	t.Parallel()
+
+	const refField = "spec.agentConfig.agentRemoteConfigs[0].agentRemoteConfigRef"
+
+	withRef := func(name *string, ref string) *v1.AgentGroup {
+		group := apiGroup()
+		group.Spec.AgentConfig = &v1.AgentConfig{
+			AgentRemoteConfigs: []v1.AgentGroupRemoteConfig{
+				{AgentRemoteConfigName: name, AgentRemoteConfigRef: &ref, AgentRemoteConfigSpec: nil},
+			},
+		}
+
+		return group
+	}
+
+	t.Run("create rejects a dangling ref", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		remoteConfigs := new(mockRemoteConfigUsecase)
+		svc := newSvcWithRemoteConfigs(t, mockGroup, remoteConfigs)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+		remoteConfigs.On("GetAgentRemoteConfig", ctx, "default", "missing", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+
+		result, err := svc.CreateAgentGroup(ctx, withRef(nil, "missing"))
+
+		assert.Nil(t, result)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+
+		var fieldErr *model.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, refField, fieldErr.Field)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("update rejects a ref to its own entry", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		remoteConfigs := new(mockRemoteConfigUsecase)
+		svc := newSvcWithRemoteConfigs(t, mockGroup, remoteConfigs)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).
+			Return(newGroup(), nil)
+
+		self := "loop"
+		result, err := svc.UpdateAgentGroup(ctx, "default", "g-1", withRef(&self, "loop"))
+
+		assert.Nil(t, result)
+
+		var fieldErr *model.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, refField, fieldErr.Field)
+		assert.Contains(t, fieldErr.Reason, "its own entry")
+		remoteConfigs.AssertNotCalled(t, "GetAgentRemoteConfig", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("update accepts a ref to an existing config", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		remoteConfigs := new(mockRemoteConfigUsecase)
+		svc := newSvcWithRemoteConfigs(t, mockGroup, remoteConfigs)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).
+			Return(newGroup(), nil)
+		remoteConfigs.On("GetAgentRemoteConfig", ctx, "default", "shared", (*model.GetOptions)(nil)).
+			Return(&agentmodel.AgentRemoteConfig{
+				Metadata: agentmodel.AgentRemoteConfigMetadata{Namespace: "default", Name: "shared"},
+			}, nil)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "g-1", mock.Anything).
+			Return(newGroup(), nil)
+
+		_, err := svc.UpdateAgentGroup(ctx, "default", "g-1", withRef(nil, "shared"))
+
+		require.NoError(t, err)
+		remoteConfigs.AssertExpectations(t)
+	})
+}
+
 func TestService_UpdateAgentGroup(t *testing.T) {
 	t.Parallel()
 
@@ -376,6 +756,71 @@ func TestService_UpdateAgentGroup(t *testing.T) {
 		mockGroup.AssertExpectations(t)
 	})
 
+	t.Run("publishes the key-level config diff", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		publisher := &spyChangePublisher{}
+		svc := newSvcWithPublisher(t, mockGroup, new(mockAgentUsecase), publisher)
+
+		inline := func(name, value string) agentmodel.AgentGroupAgentRemoteConfig {
+			return agentmodel.AgentGroupAgentRemoteConfig{
+				AgentRemoteConfigName: &name,
+				AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{Value: []byte(value), ContentType: "text/yaml"},
+				AgentRemoteConfigRef:  nil,
+			}
+		}
+
+		existing := newGroup()
+		existing.Spec.Selector.IdentifyingAttributes = map[string]string{"service.name": "collector"}
+		existing.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{
+			inline("kept", "a: 1"),
+			inline("changed", "b: 1"),
+			inline("dropped", "c: 1"),
+		}
+
+		updated := newGroup()
+		updated.Spec.Selector = existing.Spec.Selector
+		updated.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{
+			inline("kept", "a: 1"),
+			inline("changed", "b: 2"),
+			inline("new", "d: 1"),
+		}
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).Return(existing, nil)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "g-1", mock.Anything).Return(updated, nil)
+
+		_, err := svc.UpdateAgentGroup(ctx, "default", "g-1", apiGroup())
+		require.NoError(t, err)
+
+		require.Len(t, publisher.changes, 1)
+		change := publisher.changes[0]
+		assert.Equal(t, "default", change.Namespace)
+		assert.Equal(t, "g-1", change.Name)
+		assert.Equal(t, []string{"new"}, change.AddedKeys)
+		assert.Equal(t, []string{"dropped"}, change.RemovedKeys)
+		assert.Equal(t, []string{"changed"}, change.ModifiedKeys)
+		assert.False(t, change.SelectorChanged)
+		assert.Equal(t, map[string]string{"service.name": "collector"}, change.Selector.IdentifyingAttributes)
+	})
+
+	t.Run("publishes nothing when configs and selector are unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		publisher := &spyChangePublisher{}
+		svc := newSvcWithPublisher(t, mockGroup, new(mockAgentUsecase), publisher)
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).Return(newGroup(), nil)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "g-1", mock.Anything).Return(newGroup(), nil)
+
+		_, err := svc.UpdateAgentGroup(ctx, "default", "g-1", apiGroup())
+		require.NoError(t, err)
+		assert.Empty(t, publisher.changes)
+	})
+
 	t.Run("get error", func(t *testing.T) {
 		t.Parallel()
 
@@ -392,6 +837,93 @@ func TestService_UpdateAgentGroup(t *testing.T) {
 		assert.Contains(t, err.Error(), "get agent group for update")
 		mockGroup.AssertExpectations(t)
 	})
+
+	t.Run("missing group is not created", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).
+			Return(nil, model.ErrResourceNotExist)
+
+		result, err := svc.UpdateAgentGroup(ctx, "default", "g-1", apiGroup())
+
+		require.ErrorIs(t, err, model.ErrResourceNotExist)
+		assert.Nil(t, result)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_RollbackAgentGroup(t *testing.T) {
+	t.Parallel()
+
+	pushConfig := func(body string) *v1.AgentGroup {
+		name := "collector"
+		group := apiGroup()
+		group.Spec.AgentConfig = &v1.AgentConfig{
+			AgentRemoteConfigs: []v1.AgentGroupRemoteConfig{{
+				AgentRemoteConfigName: &name,
+				AgentRemoteConfigSpec: &v1.AgentRemoteConfigSpec{Value: body, ContentType: "text/yaml"},
+			}},
+		}
+
+		return group
+	}
+
+	t.Run("restores the configs pushed before the last update", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+
+		// The mock keeps the last saved group, like the persistence behind the usecase.
+		stored := newGroup()
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).Return(stored, nil)
+		mockGroup.On("SaveAgentGroup", ctx, "default", "g-1", mock.Anything).
+			Run(func(args mock.Arguments) {
+				saved, _ := args.Get(3).(*agentmodel.AgentGroup)
+				*stored = *saved
+			}).
+			Return(stored, nil)
+
+		_, err := svc.UpdateAgentGroup(ctx, "default", "g-1", pushConfig("v1"))
+		require.NoError(t, err)
+		_, err = svc.UpdateAgentGroup(ctx, "default", "g-1", pushConfig("v2"))
+		require.NoError(t, err)
+
+		result, err := svc.RollbackAgentGroup(ctx, "default", "g-1")
+		require.NoError(t, err)
+
+		require.Len(t, stored.Spec.AgentRemoteConfigs, 1)
+		assert.Equal(t, []byte("v1"), stored.Spec.AgentRemoteConfigs[0].AgentRemoteConfigSpec.Value)
+		require.NotNil(t, result.Spec.AgentConfig)
+		assert.Equal(t, "v1", result.Spec.AgentConfig.AgentRemoteConfigs[0].AgentRemoteConfigSpec.Value)
+
+		// Rolling back again re-applies the configs that were rolled back.
+		_, err = svc.RollbackAgentGroup(ctx, "default", "g-1")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v2"), stored.Spec.AgentRemoteConfigs[0].AgentRemoteConfigSpec.Value)
+	})
+
+	t.Run("rejects a group with no previous configs", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockGroup := new(mockAgentGroupUsecase)
+		svc := newSvc(t, mockGroup, new(mockAgentUsecase))
+
+		mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).Return(newGroup(), nil)
+
+		result, err := svc.RollbackAgentGroup(ctx, "default", "g-1")
+
+		require.ErrorIs(t, err, agentmodel.ErrNoPreviousRemoteConfigs)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+		assert.Nil(t, result)
+		mockGroup.AssertNotCalled(t, "SaveAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestService_DeleteAgentGroup(t *testing.T) {
@@ -472,6 +1004,70 @@ func TestService_ListAgentsByAgentGroup(t *testing.T) {
 	})
 }
 
+func TestService_ListAgentGroupFailures(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockGroup := new(mockAgentGroupUsecase)
+	mockAgent := new(mockAgentUsecase)
+	svc := newSvc(t, mockGroup, mockAgent)
+
+	group := newGroup()
+	otherGroup := agentmodel.NewAgentGroup("default", "g-2", nil, time.Now(), "tester")
+	reportedAt := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	agentWithStatus := func(status agentmodel.RemoteConfigStatus, errorMessage string) *agentmodel.Agent {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Status.RemoteConfigStatus = agentmodel.AgentRemoteConfigStatus{
+			LastRemoteConfigHash: []byte("hash"),
+			Status:               status,
+			ErrorMessage:         errorMessage,
+			LastUpdatedAt:        reportedAt,
+		}
+
+		return agent
+	}
+
+	applied := agentWithStatus(agentmodel.RemoteConfigStatusApplied, "")
+	failed := agentWithStatus(agentmodel.RemoteConfigStatusFailed, "invalid pipeline")
+	failedOnOtherGroup := agentWithStatus(agentmodel.RemoteConfigStatusFailed, "unknown exporter")
+
+	mockGroup.On("GetAgentGroup", ctx, "default", "g-1", (*model.GetOptions)(nil)).Return(group, nil)
+	mockAgent.On("ListAgentsBySelector", ctx, group.Spec.Selector,
+		mock.MatchedBy(func(options *model.ListOptions) bool { return options.Continue == "" })).
+		Return(&model.ListResponse[*agentmodel.Agent]{
+			Items:    []*agentmodel.Agent{applied, failed},
+			Continue: "next",
+		}, nil)
+	mockAgent.On("ListAgentsBySelector", ctx, group.Spec.Selector,
+		mock.MatchedBy(func(options *model.ListOptions) bool { return options.Continue == "next" })).
+		Return(&model.ListResponse[*agentmodel.Agent]{
+			Items: []*agentmodel.Agent{failedOnOtherGroup},
+		}, nil)
+	mockGroup.On("GetRemoteConfigSources", ctx, failed).Return(map[string]*agentmodel.AgentGroup{
+		"g-1/pipeline": group,
+		"g-1/exporter": group,
+		"g-2/receiver": otherGroup,
+	}, nil)
+	mockGroup.On("GetRemoteConfigSources", ctx, failedOnOtherGroup).Return(map[string]*agentmodel.AgentGroup{
+		"g-2/receiver": otherGroup,
+	}, nil)
+
+	result, err := svc.ListAgentGroupFailures(ctx, "default", "g-1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, v1.AgentGroupFailure{
+		InstanceUID:       failed.Metadata.InstanceUID,
+		RemoteConfigNames: []string{"g-1/exporter", "g-1/pipeline"},
+		ErrorMessage:      "invalid pipeline",
+		ReportedAt:        v1.NewTime(reportedAt),
+	}, result.Items[0])
+	mockGroup.AssertExpectations(t)
+	mockAgent.AssertExpectations(t)
+	mockGroup.AssertNotCalled(t, "GetRemoteConfigSources", ctx, applied)
+}
+
 func TestService_ListAgentGroupsByAgent(t *testing.T) {
 	t.Parallel()
 
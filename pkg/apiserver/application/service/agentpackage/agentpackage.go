@@ -14,6 +14,7 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
@@ -25,6 +26,7 @@ var _ usecase.AgentPackageManageUsecase = (*Service)(nil)
 // (stamping, immutable-field preservation) to the domain AgentPackageUsecase.
 type Service struct {
 	agentpackageUsecase agentport.AgentPackageUsecase
+	verificationUsecase agentport.AgentPackageVerificationUsecase
 	mapper              *helper.Mapper
 	clock               clock.Clock
 	logger              *slog.Logger
@@ -33,12 +35,14 @@ type Service struct {
 // NewAgentPackageService creates a new AgentPackageService.
 func NewAgentPackageService(
 	agentpackageUsecase agentport.AgentPackageUsecase,
+	verificationUsecase agentport.AgentPackageVerificationUsecase,
 	logger *slog.Logger,
 ) *Service {
 	realClock := clock.NewRealClock()
 
 	return &Service{
 		agentpackageUsecase: agentpackageUsecase,
+		verificationUsecase: verificationUsecase,
 		mapper:              helper.NewMapper(realClock, 0),
 		clock:               realClock,
 		logger:              logger,
@@ -131,6 +135,63 @@ func (a *Service) DeleteAgentPackage(
 	return nil
 }
 
+// DeleteAgentPackagesBySelector implements [usecase.AgentPackageManageUsecase].
+//
+// Packages are deleted one by one; if a delete fails, the ones already deleted stay
+// deleted and the error is returned.
+func (a *Service) DeleteAgentPackagesBySelector(
+	ctx context.Context,
+	namespace string,
+	selector map[string]string,
+) (*v1.DeleteCollectionResponse, error) {
+	if len(selector) == 0 {
+		return nil, &model.FieldError{Field: "selector", Value: selector, Reason: "must not be empty"}
+	}
+
+	//exhaustruct:ignore
+	agentPackages, err := a.agentpackageUsecase.ListAgentPackages(ctx, &model.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list agent packages: %w", err)
+	}
+
+	deletedAt := a.clock.Now()
+	actor := a.actor(ctx)
+	deleted := 0
+
+	for _, agentPackage := range agentPackages.Items {
+		if agentPackage.Metadata.Namespace != namespace || !agentPackage.Metadata.Attributes.Matches(selector) {
+			continue
+		}
+
+		err = a.agentpackageUsecase.DeleteAgentPackage(ctx, namespace, agentPackage.Metadata.Name, deletedAt, actor)
+		if err != nil {
+			return nil, fmt.Errorf("delete agent package %s: %w", agentPackage.Metadata.Name, err)
+		}
+
+		deleted++
+	}
+
+	return &v1.DeleteCollectionResponse{
+		Kind:         v1.AgentPackageKind,
+		APIVersion:   v1.APIVersion,
+		DeletedCount: deleted,
+	}, nil
+}
+
+// VerifyAgentPackage implements [usecase.AgentPackageManageUsecase].
+func (a *Service) VerifyAgentPackage(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*v1.AgentPackage, error) {
+	verified, err := a.verificationUsecase.VerifyAgentPackage(ctx, namespace, name, a.actor(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("verify agent package: %w", err)
+	}
+
+	return a.mapper.MapAgentPackageToAPI(verified), nil
+}
+
 // actor resolves the acting user from the request context, falling back to an
 // anonymous identity (and logging) when none is present.
 func (a *Service) actor(ctx context.Context) string {
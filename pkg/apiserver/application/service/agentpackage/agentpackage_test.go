@@ -11,9 +11,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
 	agentpackagesvc "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentpackage"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
 )
@@ -118,7 +120,7 @@ func newSvc(t *testing.T, pkg *mockAgentPackageUsecase) *agentpackagesvc.Service
 
 	base := testutil.NewBase(t)
 
-	return agentpackagesvc.NewAgentPackageService(pkg, base.Logger)
+	return agentpackagesvc.NewAgentPackageService(pkg, nil, base.Logger)
 }
 
 func newPkg() *agentmodel.AgentPackage {
@@ -332,3 +334,40 @@ func TestService_DeleteAgentPackage(t *testing.T) {
 		mockPkg.AssertExpectations(t)
 	})
 }
+
+func TestService_DeleteAgentPackagesBySelector(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	base := testutil.NewBase(t)
+	svc := agentpackagesvc.NewAgentPackageService(
+		agentservice.NewAgentPackageService(inmemory.NewAgentPackageRepository()), nil, base.Logger)
+
+	for _, pkg := range []struct{ name, environment string }{
+		{"test-1", "test"},
+		{"test-2", "test"},
+		{"prod-1", "prod"},
+	} {
+		//exhaustruct:ignore
+		_, err := svc.CreateAgentPackage(ctx, &v1.AgentPackage{
+			Kind:       v1.AgentPackageKind,
+			APIVersion: v1.APIVersion,
+			Metadata: v1.AgentPackageMetadata{
+				Namespace:  "default",
+				Name:       pkg.name,
+				Attributes: v1.Attributes{"environment": pkg.environment},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := svc.DeleteAgentPackagesBySelector(ctx, "default", map[string]string{"environment": "test"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.DeletedCount)
+
+	//exhaustruct:ignore
+	remaining, err := svc.ListAgentPackages(ctx, &applicationport.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, remaining.Items, 1)
+	assert.Equal(t, "prod-1", remaining.Items[0].Metadata.Name)
+}
@@ -0,0 +1,123 @@
+// Package agentquarantine provides the application service for manually quarantining agents.
+package agentquarantine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+// defaultReason is recorded when the operator does not give a reason.
+const defaultReason = "Quarantined manually"
+
+var _ usecase.AgentQuarantineManageUsecase = (*Service)(nil)
+
+// Service implements usecase.AgentQuarantineManageUsecase. It resolves the agent within
+// the caller's namespace and the acting user, and delegates the quarantine itself to the
+// domain AgentQuarantineUsecase.
+type Service struct {
+	agentUsecase           agentport.AgentUsecase
+	agentQuarantineUsecase agentport.AgentQuarantineUsecase
+
+	mapper *helper.Mapper
+	logger *slog.Logger
+}
+
+// New creates a new agent quarantine application service.
+func New(
+	agentUsecase agentport.AgentUsecase,
+	agentQuarantineUsecase agentport.AgentQuarantineUsecase,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		agentUsecase:           agentUsecase,
+		agentQuarantineUsecase: agentQuarantineUsecase,
+		mapper:                 helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness),
+		logger:                 logger,
+	}
+}
+
+// QuarantineAgent implements [usecase.AgentQuarantineManageUsecase].
+func (s *Service) QuarantineAgent(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+	reason string,
+) (*v1.Agent, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	err = s.agentQuarantineUsecase.QuarantineAgent(ctx, agent, s.actor(ctx), reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quarantine agent: %w", err)
+	}
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// UnquarantineAgent implements [usecase.AgentQuarantineManageUsecase].
+func (s *Service) UnquarantineAgent(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*v1.Agent, error) {
+	agent, err := s.getAgentInNamespace(ctx, namespace, instanceUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.agentQuarantineUsecase.UnquarantineAgent(ctx, agent, s.actor(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unquarantine agent: %w", err)
+	}
+
+	return s.mapper.MapAgentToAPI(agent), nil
+}
+
+// getAgentInNamespace fetches the agent and reports an agent in another namespace as
+// applicationport.ErrAgentNamespaceMismatch (404).
+func (s *Service) getAgentInNamespace(
+	ctx context.Context,
+	namespace string,
+	instanceUID uuid.UUID,
+) (*agentmodel.Agent, error) {
+	agent, err := s.agentUsecase.GetAgent(ctx, instanceUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	if agent.Metadata.Namespace != namespace {
+		return nil, fmt.Errorf("failed to get agent: %w", applicationport.ErrAgentNamespaceMismatch)
+	}
+
+	return agent, nil
+}
+
+// actor resolves the acting user, falling back to an anonymous identity.
+func (s *Service) actor(ctx context.Context) string {
+	user, err := security.GetUser(ctx)
+	if err != nil {
+		s.logger.Warn("failed to get user from context", slog.String("error", err.Error()))
+
+		user = security.NewAnonymousUser()
+	}
+
+	return user.String()
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -168,10 +169,30 @@ func (*stubAgentGroupUsecase) ApplyMatchingAgentGroupsToAgent(context.Context, *
 	return nil
 }
 
+func (*stubAgentGroupUsecase) GetRemoteConfigSources(
+	context.Context, *agentmodel.Agent,
+) (map[string]*agentmodel.AgentGroup, error) {
+	return nil, nil
+}
+
 func (*stubAgentGroupUsecase) ReconcileAgent(context.Context, *agentmodel.Agent) error { return nil }
 
 func (*stubAgentGroupUsecase) ReconcileAgentGroup(context.Context, string, string) error { return nil }
 
+func (*stubAgentGroupUsecase) PropagateAgentGroup(
+	context.Context, string, string,
+) (*agentmodel.AgentGroupPropagation, error) {
+	//exhaustruct:ignore
+	return &agentmodel.AgentGroupPropagation{}, nil
+}
+
+func (*stubAgentGroupUsecase) ApplyAgentGroupToAgents(
+	context.Context, string, string, []uuid.UUID,
+) (*agentmodel.AgentGroupPropagation, error) {
+	//exhaustruct:ignore
+	return &agentmodel.AgentGroupPropagation{}, nil
+}
+
 // stubEndpointDetectionUsecase is a no-op agentport.EndpointDetectionUsecase.
 // ReconcileEndpointsFromRemoteConfig signals detectCh so a test can wait for the
 // fire-and-forget detection goroutine to run.
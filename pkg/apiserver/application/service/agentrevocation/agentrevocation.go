@@ -0,0 +1,84 @@
+// Package agentrevocation provides the application service for revoking agent instance UIDs.
+package agentrevocation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+// defaultReason is recorded when the operator does not give a reason.
+const defaultReason = "Revoked manually"
+
+var _ usecase.AgentRevocationManageUsecase = (*Service)(nil)
+
+// Service implements usecase.AgentRevocationManageUsecase. It resolves the acting user
+// and delegates to the domain AgentRevocationUsecase.
+type Service struct {
+	agentRevocationUsecase agentport.AgentRevocationUsecase
+
+	mapper *helper.Mapper
+	logger *slog.Logger
+}
+
+// New creates a new agent revocation application service.
+func New(
+	agentRevocationUsecase agentport.AgentRevocationUsecase,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		agentRevocationUsecase: agentRevocationUsecase,
+		mapper:                 helper.NewMapper(clock.NewRealClock(), agentmodel.DefaultConnectionStaleness),
+		logger:                 logger,
+	}
+}
+
+// RevokeAgent implements [usecase.AgentRevocationManageUsecase].
+func (s *Service) RevokeAgent(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	reason string,
+) (*v1.AgentRevocation, error) {
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	revocation, err := s.agentRevocationUsecase.RevokeAgent(ctx, instanceUID, s.actor(ctx), reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke agent: %w", err)
+	}
+
+	return s.mapper.MapAgentRevocationToAPI(revocation), nil
+}
+
+// UnrevokeAgent implements [usecase.AgentRevocationManageUsecase].
+func (s *Service) UnrevokeAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	err := s.agentRevocationUsecase.UnrevokeAgent(ctx, instanceUID)
+	if err != nil {
+		return fmt.Errorf("failed to unrevoke agent: %w", err)
+	}
+
+	return nil
+}
+
+// actor resolves the acting user, falling back to an anonymous identity.
+func (s *Service) actor(ctx context.Context) string {
+	user, err := security.GetUser(ctx)
+	if err != nil {
+		s.logger.Warn("failed to get user from context", slog.String("error", err.Error()))
+
+		user = security.NewAnonymousUser()
+	}
+
+	return user.String()
+}
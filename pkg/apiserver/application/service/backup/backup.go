@@ -0,0 +1,277 @@
+// Package backup provides the application service that exports the managed
+// resources as a backup bundle and restores them from one.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var _ usecase.BackupUsecase = (*Service)(nil)
+
+// exportPageSize is the page size used to walk each resource listing.
+const exportPageSize = 100
+
+// ErrMalformedBundle is returned when an imported bundle is not a BackupBundle or
+// holds a resource without a namespace or name.
+var ErrMalformedBundle = fmt.Errorf("malformed backup bundle: %w", model.ErrInvalidArgument)
+
+// Service implements usecase.BackupUsecase on top of the per-resource manage
+// use cases, so an import goes through the same validation and side effects as
+// the regular create/update endpoints.
+type Service struct {
+	agentGroupUsecase        usecase.AgentGroupManageUsecase
+	certificateUsecase       usecase.CertificateManageUsecase
+	agentPackageUsecase      usecase.AgentPackageManageUsecase
+	agentRemoteConfigUsecase usecase.AgentRemoteConfigManageUsecase
+	clock                    clock.Clock
+	logger                   *slog.Logger
+}
+
+// New creates a new backup application Service.
+func New(
+	agentGroupUsecase usecase.AgentGroupManageUsecase,
+	certificateUsecase usecase.CertificateManageUsecase,
+	agentPackageUsecase usecase.AgentPackageManageUsecase,
+	agentRemoteConfigUsecase usecase.AgentRemoteConfigManageUsecase,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		agentGroupUsecase:        agentGroupUsecase,
+		certificateUsecase:       certificateUsecase,
+		agentPackageUsecase:      agentPackageUsecase,
+		agentRemoteConfigUsecase: agentRemoteConfigUsecase,
+		clock:                    clock.NewRealClock(),
+		logger:                   logger,
+	}
+}
+
+// Export implements [usecase.BackupUsecase].
+func (s *Service) Export(ctx context.Context) (*v1.BackupBundle, error) {
+	certificates, err := listAll(ctx, s.certificateUsecase.ListCertificates)
+	if err != nil {
+		return nil, fmt.Errorf("export certificates: %w", err)
+	}
+
+	agentPackages, err := listAll(ctx, s.agentPackageUsecase.ListAgentPackages)
+	if err != nil {
+		return nil, fmt.Errorf("export agent packages: %w", err)
+	}
+
+	agentRemoteConfigs, err := listAll(ctx, s.agentRemoteConfigUsecase.ListAgentRemoteConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("export agent remote configs: %w", err)
+	}
+
+	agentGroups, err := listAll(ctx, s.agentGroupUsecase.ListAgentGroups)
+	if err != nil {
+		return nil, fmt.Errorf("export agent groups: %w", err)
+	}
+
+	for i := range certificates {
+		certificates[i].Metadata.DeletedAt = nil
+		certificates[i].Status = v1.CertificateStatus{Conditions: nil}
+	}
+
+	for i := range agentPackages {
+		agentPackages[i].Metadata.DeletedAt = nil
+		agentPackages[i].Status = v1.AgentPackageStatus{Conditions: nil}
+	}
+
+	for i := range agentRemoteConfigs {
+		agentRemoteConfigs[i].Metadata.DeletedAt = nil
+		agentRemoteConfigs[i].Metadata.DeletedBy = ""
+		agentRemoteConfigs[i].Status = v1.AgentRemoteConfigStatus{Conditions: nil}
+	}
+
+	for i := range agentGroups {
+		agentGroups[i].Metadata.DeletedAt = nil
+		//exhaustruct:ignore
+		agentGroups[i].Status = v1.Status{}
+	}
+
+	return &v1.BackupBundle{
+		Kind:       v1.BackupBundleKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.BackupBundleMetadata{
+			ExportedAt: v1.NewTime(s.clock.Now()),
+		},
+		AgentGroups:        agentGroups,
+		Certificates:       certificates,
+		AgentPackages:      agentPackages,
+		AgentRemoteConfigs: agentRemoteConfigs,
+	}, nil
+}
+
+// Import implements [usecase.BackupUsecase]. Certificates, agent packages and agent
+// remote configs are restored before agent groups, since a group's connection settings
+// and remote config may refer to them. It stops at the first failing resource; re-running the import after fixing
+// the cause resumes where it left off because already-restored resources are left
+// unchanged.
+func (s *Service) Import(ctx context.Context, bundle *v1.BackupBundle) (*v1.BackupImportResult, error) {
+	if bundle == nil || (bundle.Kind != "" && bundle.Kind != v1.BackupBundleKind) {
+		return nil, fmt.Errorf("%w: expected kind %q", ErrMalformedBundle, v1.BackupBundleKind)
+	}
+
+	result := &v1.BackupImportResult{Created: 0, Updated: 0, Unchanged: 0}
+
+	err := importAll(ctx, result, bundle.Certificates, resourceOps[v1.Certificate]{
+		kind: v1.CertificateKind,
+		key: func(c *v1.Certificate) (string, string) {
+			return c.Metadata.Namespace, c.Metadata.Name
+		},
+		get:    s.certificateUsecase.GetCertificate,
+		create: s.certificateUsecase.CreateCertificate,
+		update: s.certificateUsecase.UpdateCertificate,
+		equal: func(a, b *v1.Certificate) bool {
+			return maps.Equal(a.Metadata.Attributes, b.Metadata.Attributes) && a.Spec == b.Spec
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = importAll(ctx, result, bundle.AgentPackages, resourceOps[v1.AgentPackage]{
+		kind: v1.AgentPackageKind,
+		key: func(p *v1.AgentPackage) (string, string) {
+			return p.Metadata.Namespace, p.Metadata.Name
+		},
+		get:    s.agentPackageUsecase.GetAgentPackage,
+		create: s.agentPackageUsecase.CreateAgentPackage,
+		update: s.agentPackageUsecase.UpdateAgentPackage,
+		equal: func(a, b *v1.AgentPackage) bool {
+			return maps.Equal(a.Metadata.Attributes, b.Metadata.Attributes) && reflect.DeepEqual(a.Spec, b.Spec)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = importAll(ctx, result, bundle.AgentRemoteConfigs, resourceOps[v1.AgentRemoteConfig]{
+		kind: v1.AgentRemoteConfigKind,
+		key: func(c *v1.AgentRemoteConfig) (string, string) {
+			return c.Metadata.Namespace, c.Metadata.Name
+		},
+		get:    s.agentRemoteConfigUsecase.GetAgentRemoteConfig,
+		create: s.agentRemoteConfigUsecase.CreateAgentRemoteConfig,
+		update: s.agentRemoteConfigUsecase.UpdateAgentRemoteConfig,
+		equal: func(a, b *v1.AgentRemoteConfig) bool {
+			return maps.Equal(a.Metadata.Attributes, b.Metadata.Attributes) && a.Spec == b.Spec
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = importAll(ctx, result, bundle.AgentGroups, resourceOps[v1.AgentGroup]{
+		kind: v1.AgentGroupKind,
+		key: func(g *v1.AgentGroup) (string, string) {
+			return g.Metadata.Namespace, g.Metadata.Name
+		},
+		get:    s.agentGroupUsecase.GetAgentGroup,
+		create: s.agentGroupUsecase.CreateAgentGroup,
+		update: s.agentGroupUsecase.UpdateAgentGroup,
+		equal: func(a, b *v1.AgentGroup) bool {
+			return maps.Equal(a.Metadata.Attributes, b.Metadata.Attributes) && reflect.DeepEqual(a.Spec, b.Spec)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("imported backup bundle",
+		slog.Int("created", result.Created),
+		slog.Int("updated", result.Updated),
+		slog.Int("unchanged", result.Unchanged),
+	)
+
+	return result, nil
+}
+
+// resourceOps adapts one resource kind's manage use case to importAll.
+type resourceOps[T any] struct {
+	kind   string
+	key    func(item *T) (string, string)
+	get    func(ctx context.Context, namespace, name string, options *port.GetOptions) (*T, error)
+	create func(ctx context.Context, item *T) (*T, error)
+	update func(ctx context.Context, namespace, name string, item *T) (*T, error)
+	equal  func(existing, desired *T) bool
+}
+
+// importAll creates each missing item and updates each one that differs from
+// what is stored, tallying the outcome into result.
+func importAll[T any](ctx context.Context, result *v1.BackupImportResult, items []T, ops resourceOps[T]) error {
+	for i := range items {
+		item := &items[i]
+
+		namespace, name := ops.key(item)
+		if namespace == "" || name == "" {
+			return fmt.Errorf("%w: %s at index %d has no namespace or name", ErrMalformedBundle, ops.kind, i)
+		}
+
+		existing, err := ops.get(ctx, namespace, name, nil)
+
+		switch {
+		case errors.Is(err, model.ErrResourceNotExist):
+			_, err = ops.create(ctx, item)
+			if err != nil {
+				return fmt.Errorf("import %s %s/%s: %w", ops.kind, namespace, name, err)
+			}
+
+			result.Created++
+		case err != nil:
+			return fmt.Errorf("import %s %s/%s: %w", ops.kind, namespace, name, err)
+		case ops.equal(existing, item):
+			result.Unchanged++
+		default:
+			_, err = ops.update(ctx, namespace, name, item)
+			if err != nil {
+				return fmt.Errorf("import %s %s/%s: %w", ops.kind, namespace, name, err)
+			}
+
+			result.Updated++
+		}
+	}
+
+	return nil
+}
+
+// listAll walks every page of a cross-namespace listing.
+func listAll[T any](
+	ctx context.Context,
+	list func(ctx context.Context, options *port.ListOptions) (*v1.ListResponse[T], error),
+) ([]T, error) {
+	var (
+		items        []T
+		continueFrom string
+	)
+
+	for {
+		//exhaustruct:ignore
+		page, err := list(ctx, &port.ListOptions{
+			Limit:    exportPageSize,
+			Continue: continueFrom,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+
+		if page.Metadata.Continue == "" {
+			return items, nil
+		}
+
+		continueFrom = page.Metadata.Continue
+	}
+}
@@ -0,0 +1,473 @@
+package backup_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/backup"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// memStore is an in-memory, insertion-ordered resource store. Listing returns
+// one item per page so the export exercises continue tokens.
+type memStore[T any] struct {
+	key     func(item *T) (string, string)
+	keys    []string
+	items   map[string]T
+	creates int
+	updates int
+}
+
+func newMemStore[T any](key func(item *T) (string, string)) *memStore[T] {
+	return &memStore[T]{key: key, keys: nil, items: map[string]T{}, creates: 0, updates: 0}
+}
+
+func (m *memStore[T]) get(namespace, name string) (*T, error) {
+	item, ok := m.items[namespace+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: %w", namespace, name, model.ErrResourceNotExist)
+	}
+
+	return &item, nil
+}
+
+func (m *memStore[T]) put(item *T) {
+	namespace, name := m.key(item)
+
+	k := namespace + "/" + name
+	if !slices.Contains(m.keys, k) {
+		m.keys = append(m.keys, k)
+	}
+
+	m.items[k] = *item
+}
+
+func (m *memStore[T]) list(options *port.ListOptions) *v1.ListResponse[T] {
+	index := 0
+	if options != nil && options.Continue != "" {
+		index, _ = strconv.Atoi(options.Continue)
+	}
+
+	response := &v1.ListResponse[T]{Kind: "", APIVersion: v1.APIVersion, Metadata: v1.ListMeta{}, Items: nil}
+	if index < len(m.keys) {
+		response.Items = []T{m.items[m.keys[index]]}
+	}
+
+	if index+1 < len(m.keys) {
+		response.Metadata.Continue = strconv.Itoa(index + 1)
+	}
+
+	return response
+}
+
+type fakeCertificates struct {
+	usecase.CertificateManageUsecase
+
+	store *memStore[v1.Certificate]
+}
+
+func (f *fakeCertificates) GetCertificate(
+	_ context.Context, namespace, name string, _ *port.GetOptions,
+) (*v1.Certificate, error) {
+	return f.store.get(namespace, name)
+}
+
+func (f *fakeCertificates) ListCertificates(
+	_ context.Context, options *port.ListOptions,
+) (*v1.ListResponse[v1.Certificate], error) {
+	return f.store.list(options), nil
+}
+
+func (f *fakeCertificates) CreateCertificate(_ context.Context, c *v1.Certificate) (*v1.Certificate, error) {
+	f.store.creates++
+	f.store.put(c)
+
+	return c, nil
+}
+
+func (f *fakeCertificates) UpdateCertificate(
+	_ context.Context, _, _ string, c *v1.Certificate,
+) (*v1.Certificate, error) {
+	f.store.updates++
+	f.store.put(c)
+
+	return c, nil
+}
+
+type fakeAgentPackages struct {
+	usecase.AgentPackageManageUsecase
+
+	store *memStore[v1.AgentPackage]
+}
+
+func (f *fakeAgentPackages) GetAgentPackage(
+	_ context.Context, namespace, name string, _ *port.GetOptions,
+) (*v1.AgentPackage, error) {
+	return f.store.get(namespace, name)
+}
+
+func (f *fakeAgentPackages) ListAgentPackages(
+	_ context.Context, options *port.ListOptions,
+) (*v1.ListResponse[v1.AgentPackage], error) {
+	return f.store.list(options), nil
+}
+
+func (f *fakeAgentPackages) CreateAgentPackage(_ context.Context, p *v1.AgentPackage) (*v1.AgentPackage, error) {
+	f.store.creates++
+	f.store.put(p)
+
+	return p, nil
+}
+
+func (f *fakeAgentPackages) UpdateAgentPackage(
+	_ context.Context, _, _ string, p *v1.AgentPackage,
+) (*v1.AgentPackage, error) {
+	f.store.updates++
+	f.store.put(p)
+
+	return p, nil
+}
+
+type fakeAgentRemoteConfigs struct {
+	usecase.AgentRemoteConfigManageUsecase
+
+	store *memStore[v1.AgentRemoteConfig]
+}
+
+func (f *fakeAgentRemoteConfigs) GetAgentRemoteConfig(
+	_ context.Context, namespace, name string, _ *port.GetOptions,
+) (*v1.AgentRemoteConfig, error) {
+	return f.store.get(namespace, name)
+}
+
+func (f *fakeAgentRemoteConfigs) ListAgentRemoteConfigs(
+	_ context.Context, options *port.ListOptions,
+) (*v1.ListResponse[v1.AgentRemoteConfig], error) {
+	return f.store.list(options), nil
+}
+
+func (f *fakeAgentRemoteConfigs) CreateAgentRemoteConfig(
+	_ context.Context, c *v1.AgentRemoteConfig,
+) (*v1.AgentRemoteConfig, error) {
+	f.store.creates++
+	f.store.put(c)
+
+	return c, nil
+}
+
+func (f *fakeAgentRemoteConfigs) UpdateAgentRemoteConfig(
+	_ context.Context, _, _ string, c *v1.AgentRemoteConfig,
+) (*v1.AgentRemoteConfig, error) {
+	f.store.updates++
+	f.store.put(c)
+
+	return c, nil
+}
+
+// fakeAgentGroups rejects a group referring to a remote config that does not exist,
+// like the real manage service does.
+type fakeAgentGroups struct {
+	usecase.AgentGroupManageUsecase
+
+	store         *memStore[v1.AgentGroup]
+	remoteConfigs *memStore[v1.AgentRemoteConfig]
+}
+
+func (f *fakeAgentGroups) checkRemoteConfigRefs(g *v1.AgentGroup) error {
+	if g.Spec.AgentConfig == nil {
+		return nil
+	}
+
+	for _, remoteConfig := range g.Spec.AgentConfig.AgentRemoteConfigs {
+		if remoteConfig.AgentRemoteConfigRef == nil {
+			continue
+		}
+
+		_, err := f.remoteConfigs.get(g.Metadata.Namespace, *remoteConfig.AgentRemoteConfigRef)
+		if err != nil {
+			return fmt.Errorf("%w: %w", model.ErrInvalidArgument, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeAgentGroups) GetAgentGroup(
+	_ context.Context, namespace, name string, _ *port.GetOptions,
+) (*v1.AgentGroup, error) {
+	return f.store.get(namespace, name)
+}
+
+func (f *fakeAgentGroups) ListAgentGroups(
+	_ context.Context, options *port.ListOptions,
+) (*v1.ListResponse[v1.AgentGroup], error) {
+	return f.store.list(options), nil
+}
+
+func (f *fakeAgentGroups) CreateAgentGroup(_ context.Context, g *v1.AgentGroup) (*v1.AgentGroup, error) {
+	err := f.checkRemoteConfigRefs(g)
+	if err != nil {
+		return nil, err
+	}
+
+	f.store.creates++
+	f.store.put(g)
+
+	return g, nil
+}
+
+func (f *fakeAgentGroups) UpdateAgentGroup(
+	_ context.Context, _, _ string, g *v1.AgentGroup,
+) (*v1.AgentGroup, error) {
+	err := f.checkRemoteConfigRefs(g)
+	if err != nil {
+		return nil, err
+	}
+
+	f.store.updates++
+	f.store.put(g)
+
+	return g, nil
+}
+
+type fixture struct {
+	svc                *backup.Service
+	certificates       *memStore[v1.Certificate]
+	agentPackages      *memStore[v1.AgentPackage]
+	agentRemoteConfigs *memStore[v1.AgentRemoteConfig]
+	agentGroups        *memStore[v1.AgentGroup]
+}
+
+func newFixture() *fixture {
+	certificates := newMemStore(func(c *v1.Certificate) (string, string) {
+		return c.Metadata.Namespace, c.Metadata.Name
+	})
+	agentPackages := newMemStore(func(p *v1.AgentPackage) (string, string) {
+		return p.Metadata.Namespace, p.Metadata.Name
+	})
+	agentRemoteConfigs := newMemStore(func(c *v1.AgentRemoteConfig) (string, string) {
+		return c.Metadata.Namespace, c.Metadata.Name
+	})
+	agentGroups := newMemStore(func(g *v1.AgentGroup) (string, string) {
+		return g.Metadata.Namespace, g.Metadata.Name
+	})
+
+	svc := backup.New(
+		&fakeAgentGroups{store: agentGroups, remoteConfigs: agentRemoteConfigs},
+		&fakeCertificates{store: certificates},
+		&fakeAgentPackages{store: agentPackages},
+		&fakeAgentRemoteConfigs{store: agentRemoteConfigs},
+		slog.New(slog.DiscardHandler),
+	)
+
+	return &fixture{
+		svc:                svc,
+		certificates:       certificates,
+		agentPackages:      agentPackages,
+		agentRemoteConfigs: agentRemoteConfigs,
+		agentGroups:        agentGroups,
+	}
+}
+
+//nolint:funlen // fixture data
+func seed(f *fixture) {
+	configName := "otel"
+	configRef := "shared-exporters"
+	created := v1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	f.certificates.put(&v1.Certificate{
+		Kind:       v1.CertificateKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.CertificateMetadata{
+			Name: "server-tls", Namespace: "default",
+			Attributes: v1.Attributes{"env": "prod"}, CreatedAt: created, DeletedAt: nil,
+		},
+		Spec: v1.CertificateSpec{Cert: "cert-pem", PrivateKey: "key-pem", CaCert: "ca-pem"},
+		Status: v1.CertificateStatus{Conditions: []v1.Condition{{
+			Type: v1.ConditionTypeCreated, LastTransitionTime: created,
+			Status: v1.ConditionStatusTrue, Reason: "", Message: "created",
+		}}},
+	})
+	f.certificates.put(&v1.Certificate{
+		Kind:       v1.CertificateKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.CertificateMetadata{
+			Name: "client-tls", Namespace: "staging",
+			Attributes: nil, CreatedAt: created, DeletedAt: nil,
+		},
+		Spec:   v1.CertificateSpec{Cert: "client-pem", PrivateKey: "", CaCert: ""},
+		Status: v1.CertificateStatus{Conditions: nil},
+	})
+	f.agentPackages.put(&v1.AgentPackage{
+		Kind:       v1.AgentPackageKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.AgentPackageMetadata{
+			Name: "collector", Namespace: "default",
+			Attributes: v1.Attributes{}, CreatedAt: created, DeletedAt: nil,
+		},
+		Spec: v1.AgentPackageSpec{
+			PackageType: "TopLevel", Version: "0.120.0", DownloadURL: "https://example.com/otelcol",
+			ContentHash: []byte{1, 2}, Signature: nil, Headers: map[string]string{"X-Token": "t"}, Hash: []byte{3},
+		},
+		Status: v1.AgentPackageStatus{Conditions: nil},
+	})
+	f.agentRemoteConfigs.put(&v1.AgentRemoteConfig{
+		Kind:       v1.AgentRemoteConfigKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.AgentRemoteConfigMetadata{
+			Name: configRef, Namespace: "default",
+			Attributes: v1.Attributes{"team": "obs"}, CreatedAt: created, DeletedAt: nil, DeletedBy: "",
+		},
+		Spec: v1.AgentRemoteConfigSpec{Value: "exporters: {otlp: {}}", ContentType: "application/yaml"},
+		Status: v1.AgentRemoteConfigStatus{Conditions: []v1.Condition{{
+			Type: v1.ConditionTypeCreated, LastTransitionTime: created,
+			Status: v1.ConditionStatusTrue, Reason: "", Message: "created",
+		}}},
+	})
+	f.agentGroups.put(&v1.AgentGroup{
+		Kind:       v1.AgentGroupKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.Metadata{
+			Namespace: "default", Name: "linux",
+			Attributes: v1.Attributes{"team": "obs"}, CreatedAt: created, DeletedAt: nil,
+		},
+		Spec: v1.Spec{
+			Priority: 10,
+			Selector: v1.AgentSelector{
+				IdentifyingAttributes:    map[string]string{"os.type": "linux"},
+				NonIdentifyingAttributes: nil,
+			},
+			AgentConfig: &v1.AgentConfig{
+				AgentRemoteConfigs: []v1.AgentGroupRemoteConfig{
+					{AgentRemoteConfigName: &configName},
+					{AgentRemoteConfigRef: &configRef},
+				},
+				ConnectionSettings: nil,
+			},
+		},
+		Status: v1.Status{NumAgents: 3, NumConnectedAgents: 2},
+	})
+	f.agentGroups.put(&v1.AgentGroup{
+		Kind:       v1.AgentGroupKind,
+		APIVersion: v1.APIVersion,
+		Metadata: v1.Metadata{
+			Namespace: "staging", Name: "all",
+			Attributes: nil, CreatedAt: created, DeletedAt: nil,
+		},
+		Spec:   v1.Spec{Priority: 0},
+		Status: v1.Status{NumAgents: 7},
+	})
+}
+
+func TestService_ExportImport_Parity(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	source := newFixture()
+	seed(source)
+
+	bundle, err := source.svc.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, v1.BackupBundleKind, bundle.Kind)
+	require.Len(t, bundle.Certificates, 2)
+	require.Len(t, bundle.AgentPackages, 1)
+	require.Len(t, bundle.AgentRemoteConfigs, 1)
+	require.Len(t, bundle.AgentGroups, 2)
+
+	// Ephemeral status is not exported.
+	assert.Empty(t, bundle.Certificates[0].Status.Conditions)
+	assert.Empty(t, bundle.AgentRemoteConfigs[0].Status.Conditions)
+	assert.Zero(t, bundle.AgentGroups[0].Status.NumAgents)
+	// Secret material is, so the bundle can actually restore the certificate.
+	assert.Equal(t, "key-pem", bundle.Certificates[0].Spec.PrivateKey)
+
+	// Importing into an empty store works only if the remote config a group refers to
+	// is restored before the group.
+	target := newFixture()
+
+	result, err := target.svc.Import(ctx, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, &v1.BackupImportResult{Created: 6, Updated: 0, Unchanged: 0}, result)
+
+	restored, err := target.svc.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, bundle.Certificates, restored.Certificates)
+	assert.Equal(t, bundle.AgentPackages, restored.AgentPackages)
+	assert.Equal(t, bundle.AgentRemoteConfigs, restored.AgentRemoteConfigs)
+	assert.Equal(t, bundle.AgentGroups, restored.AgentGroups)
+}
+
+func TestService_Import_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	source := newFixture()
+	seed(source)
+
+	bundle, err := source.svc.Export(ctx)
+	require.NoError(t, err)
+
+	target := newFixture()
+
+	_, err = target.svc.Import(ctx, bundle)
+	require.NoError(t, err)
+
+	t.Run("re-importing the same bundle changes nothing", func(t *testing.T) {
+		result, err := target.svc.Import(ctx, bundle)
+		require.NoError(t, err)
+		assert.Equal(t, &v1.BackupImportResult{Created: 0, Updated: 0, Unchanged: 6}, result)
+		assert.Equal(t, 2, target.certificates.creates)
+		assert.Zero(t, target.certificates.updates)
+		assert.Equal(t, 1, target.agentRemoteConfigs.creates)
+		assert.Zero(t, target.agentRemoteConfigs.updates)
+		assert.Zero(t, target.agentGroups.updates)
+	})
+
+	t.Run("a changed spec is updated in place", func(t *testing.T) {
+		bundle.AgentGroups[0].Spec.Priority = 99
+
+		result, err := target.svc.Import(ctx, bundle)
+		require.NoError(t, err)
+		assert.Equal(t, &v1.BackupImportResult{Created: 0, Updated: 1, Unchanged: 5}, result)
+
+		group, err := target.agentGroups.get("default", "linux")
+		require.NoError(t, err)
+		assert.Equal(t, 99, group.Spec.Priority)
+	})
+}
+
+func TestService_Import_RejectsMalformedBundle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wrong kind", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newFixture().svc.Import(t.Context(), &v1.BackupBundle{Kind: v1.AgentGroupKind})
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+	})
+
+	t.Run("resource without a name", func(t *testing.T) {
+		t.Parallel()
+
+		bundle := &v1.BackupBundle{
+			Certificates: []v1.Certificate{{Metadata: v1.CertificateMetadata{Namespace: "default"}}},
+		}
+
+		_, err := newFixture().svc.Import(t.Context(), bundle)
+		require.ErrorIs(t, err, backup.ErrMalformedBundle)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
@@ -14,17 +15,25 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
 var _ usecase.CertificateManageUsecase = (*Service)(nil)
 
+// certificateUsageAgentsPageSize is how many agents of an agent group are read at a time
+// while collecting the agents that use a certificate.
+const certificateUsageAgentsPageSize = 500
+
 // Service is a service for managing certificates. It maps between the HTTP DTOs
 // and the domain, resolves the acting user, and delegates all lifecycle rules
 // (stamping, immutable-field preservation) to the domain CertificateUsecase.
 type Service struct {
 	certificateUsecase agentport.CertificateUsecase
+	agentGroupUsecase  agentport.AgentGroupUsecase
+	agentUsecase       agentport.AgentUsecase
+	quotaUsecase       agentport.ResourceQuotaUsecase
 	mapper             *helper.Mapper
 	clock              clock.Clock
 	logger             *slog.Logger
@@ -33,12 +42,18 @@ type Service struct {
 // NewCertificateService creates a new CertificateService.
 func NewCertificateService(
 	certificateUsecase agentport.CertificateUsecase,
+	agentGroupUsecase agentport.AgentGroupUsecase,
+	agentUsecase agentport.AgentUsecase,
+	quotaUsecase agentport.ResourceQuotaUsecase,
 	logger *slog.Logger,
 ) *Service {
 	realClock := clock.NewRealClock()
 
 	return &Service{
 		certificateUsecase: certificateUsecase,
+		agentGroupUsecase:  agentGroupUsecase,
+		agentUsecase:       agentUsecase,
+		quotaUsecase:       quotaUsecase,
 		mapper:             helper.NewMapper(realClock, 0),
 		clock:              realClock,
 		logger:             logger,
@@ -88,6 +103,11 @@ func (s *Service) CreateCertificate(
 	ctx context.Context,
 	apiModel *v1.Certificate,
 ) (*v1.Certificate, error) {
+	err := s.quotaUsecase.CheckResourceQuota(ctx, agentmodel.QuotaResourceCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate: %w", err)
+	}
+
 	domainModel := s.mapper.MapAPIToCertificate(apiModel)
 
 	created, err := s.certificateUsecase.CreateCertificate(ctx, domainModel, s.actor(ctx))
@@ -131,6 +151,147 @@ func (s *Service) DeleteCertificate(
 	return nil
 }
 
+// DeleteCertificatesBySelector implements [usecase.CertificateManageUsecase].
+//
+// Certificates are deleted one by one; if a delete fails, the ones already deleted stay
+// deleted and the error is returned.
+func (s *Service) DeleteCertificatesBySelector(
+	ctx context.Context,
+	namespace string,
+	selector map[string]string,
+) (*v1.DeleteCollectionResponse, error) {
+	if len(selector) == 0 {
+		return nil, &model.FieldError{Field: "selector", Value: selector, Reason: "must not be empty"}
+	}
+
+	//exhaustruct:ignore
+	certificates, err := s.certificateUsecase.ListCertificate(ctx, &model.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list certificates: %w", err)
+	}
+
+	deletedAt := s.clock.Now()
+	actor := s.actor(ctx)
+	deleted := 0
+
+	for _, certificate := range certificates.Items {
+		if certificate.Metadata.Namespace != namespace || !certificate.Metadata.Attributes.Matches(selector) {
+			continue
+		}
+
+		_, err = s.certificateUsecase.DeleteCertificate(ctx, namespace, certificate.Metadata.Name, deletedAt, actor)
+		if err != nil {
+			return nil, fmt.Errorf("delete certificate %s: %w", certificate.Metadata.Name, err)
+		}
+
+		deleted++
+	}
+
+	return &v1.DeleteCollectionResponse{
+		Kind:         v1.CertificateKind,
+		APIVersion:   v1.APIVersion,
+		DeletedCount: deleted,
+	}, nil
+}
+
+// GetCertificateUsage implements [usecase.CertificateManageUsecase].
+//
+// Only agent groups in the certificate's namespace can use it, and only their agents in the
+// same namespace are reported. An agent in several of those groups is reported once.
+func (s *Service) GetCertificateUsage(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*v1.CertificateUsage, error) {
+	_, err := s.certificateUsecase.GetCertificate(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get certificate: %w", err)
+	}
+
+	//exhaustruct:ignore
+	agentGroups, err := s.agentGroupUsecase.ListAgentGroups(ctx, &model.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list agent groups: %w", err)
+	}
+
+	usage := &v1.CertificateUsage{
+		AgentGroups: []string{},
+		Agents:      []v1.CertificateUsageAgent{},
+	}
+	agentIndexes := make(map[uuid.UUID]int)
+
+	for _, agentGroup := range agentGroups.Items {
+		if agentGroup.IsDeleted() || agentGroup.Metadata.Namespace != namespace ||
+			!agentGroup.ReferencesCertificate(name) {
+			continue
+		}
+
+		usage.AgentGroups = append(usage.AgentGroups, agentGroup.Metadata.Name)
+
+		err = s.collectCertificateUsageAgents(ctx, agentGroup, usage, agentIndexes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return usage, nil
+}
+
+// collectCertificateUsageAgents adds the agents of agentGroup to usage, or adds agentGroup to
+// the ones already there. agentIndexes locates the agents already in usage.
+func (s *Service) collectCertificateUsageAgents(
+	ctx context.Context,
+	agentGroup *agentmodel.AgentGroup,
+	usage *v1.CertificateUsage,
+	agentIndexes map[uuid.UUID]int,
+) error {
+	var continueToken string
+
+	for {
+		//exhaustruct:ignore
+		agentsResp, err := s.agentUsecase.ListAgentsBySelector(ctx, agentGroup.Spec.Selector, &model.ListOptions{
+			Limit:    certificateUsageAgentsPageSize,
+			Continue: continueToken,
+		})
+		if err != nil {
+			return fmt.Errorf("list agents of agent group %s: %w", agentGroup.Metadata.Name, err)
+		}
+
+		for _, agent := range agentsResp.Items {
+			if agent.Metadata.Namespace != agentGroup.Metadata.Namespace {
+				continue
+			}
+
+			instanceUID := agent.Metadata.InstanceUID
+			if index, ok := agentIndexes[instanceUID]; ok {
+				usage.Agents[index].AgentGroups = append(usage.Agents[index].AgentGroups, agentGroup.Metadata.Name)
+
+				continue
+			}
+
+			accepted, err := agent.AcceptedOfferedConnectionSettings()
+			if err != nil {
+				return fmt.Errorf("check connection settings of agent %s: %w", instanceUID, err)
+			}
+
+			agentIndexes[instanceUID] = len(usage.Agents)
+			usage.Agents = append(usage.Agents, v1.CertificateUsageAgent{
+				InstanceUID:  instanceUID,
+				AgentGroups:  []string{agentGroup.Metadata.Name},
+				Status:       agent.Status.ConnectionSettingsStatus.Status.String(),
+				ErrorMessage: agent.Status.ConnectionSettingsStatus.ErrorMessage,
+				Accepted:     accepted,
+			})
+		}
+
+		if agentsResp.Continue == "" {
+			return nil
+		}
+
+		continueToken = agentsResp.Continue
+	}
+}
+
 // actor resolves the acting user from the request context, falling back to an
 // anonymous identity (and logging) when none is present.
 func (s *Service) actor(ctx context.Context) string {
@@ -3,18 +3,28 @@ package certificate_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
 	applicationport "github.com/minuk-dev/opampcommander/pkg/apiserver/application/port"
 	certificatesvc "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/certificate"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
 	"github.com/minuk-dev/opampcommander/pkg/testutil"
 )
 
@@ -126,7 +136,8 @@ func newSvc(t *testing.T, cert *mockCertificateUsecase) *certificatesvc.Service
 
 	base := testutil.NewBase(t)
 
-	return certificatesvc.NewCertificateService(cert, base.Logger)
+	return certificatesvc.NewCertificateService(cert, nil, nil,
+		agentservice.NewResourceQuotaService(nil, nil), base.Logger)
 }
 
 func newCert() *agentmodel.Certificate {
@@ -344,3 +355,299 @@ func TestService_DeleteCertificate(t *testing.T) {
 		mockCert.AssertExpectations(t)
 	})
 }
+
+func TestService_DeleteCertificate_RecordsDeletedBy(t *testing.T) {
+	t.Parallel()
+
+	base := testutil.NewBase(t)
+	repository := inmemory.NewCertificateRepository()
+	svc := certificatesvc.NewCertificateService(
+		agentservice.NewCertificateService(repository, base.Logger), nil, nil,
+		agentservice.NewResourceQuotaService(nil, repository),
+		base.Logger)
+
+	email := "alice@example.com"
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/", nil)
+	security.SetUser(ginCtx, &security.User{Authenticated: true, Email: &email})
+	ctx := ginCtx.Request.Context()
+
+	//exhaustruct:ignore
+	_, err := svc.CreateCertificate(ctx, &v1.Certificate{
+		Kind:       v1.CertificateKind,
+		APIVersion: v1.APIVersion,
+		Metadata:   v1.CertificateMetadata{Namespace: "default", Name: "cert-1"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteCertificate(ctx, "default", "cert-1"))
+
+	stored, err := repository.GetCertificate(ctx, "default", "cert-1", &model.GetOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, email, model.DeletedBy(stored.Status.Conditions))
+
+	deleted, err := svc.GetCertificate(ctx, "default", "cert-1", &applicationport.GetOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.NotNil(t, deleted.Metadata.DeletedAt)
+	assert.Equal(t, email, deleted.Metadata.DeletedBy)
+}
+
+func TestService_DeleteCertificatesBySelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes only the matching certificates in the namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		base := testutil.NewBase(t)
+		repository := inmemory.NewCertificateRepository()
+		svc := certificatesvc.NewCertificateService(
+			agentservice.NewCertificateService(repository, base.Logger), nil, nil,
+			agentservice.NewResourceQuotaService(nil, repository),
+			base.Logger)
+
+		for _, cert := range []struct{ namespace, name, environment string }{
+			{"default", "test-1", "test"},
+			{"default", "test-2", "test"},
+			{"default", "prod-1", "prod"},
+			{"other", "test-3", "test"},
+		} {
+			//exhaustruct:ignore
+			_, err := svc.CreateCertificate(ctx, &v1.Certificate{
+				Kind:       v1.CertificateKind,
+				APIVersion: v1.APIVersion,
+				Metadata: v1.CertificateMetadata{
+					Namespace:  cert.namespace,
+					Name:       cert.name,
+					Attributes: v1.Attributes{"environment": cert.environment},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		resp, err := svc.DeleteCertificatesBySelector(ctx, "default", map[string]string{"environment": "test"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.DeletedCount)
+
+		//exhaustruct:ignore
+		remaining, err := svc.ListCertificates(ctx, &applicationport.ListOptions{})
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(remaining.Items))
+		for _, item := range remaining.Items {
+			names = append(names, item.Metadata.Namespace+"/"+item.Metadata.Name)
+		}
+
+		assert.ElementsMatch(t, []string{"default/prod-1", "other/test-3"}, names)
+	})
+
+	t.Run("empty selector is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockCert := new(mockCertificateUsecase)
+		svc := newSvc(t, mockCert)
+
+		_, err := svc.DeleteCertificatesBySelector(ctx, "default", map[string]string{})
+
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+		mockCert.AssertNotCalled(t, "ListCertificate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CreateCertificate_ResourceQuota(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	base := testutil.NewBase(t)
+	repository := inmemory.NewCertificateRepository()
+	quota := agentservice.NewResourceQuotaService(nil, repository)
+	quota.SetResourceQuotas(agentmodel.ResourceQuotas{agentmodel.QuotaResourceCertificate: 2})
+	svc := certificatesvc.NewCertificateService(
+		agentservice.NewCertificateService(repository, base.Logger), nil, nil, quota, base.Logger)
+
+	create := func(namespace, name string) error {
+		//exhaustruct:ignore
+		_, err := svc.CreateCertificate(ctx, &v1.Certificate{
+			Kind:       v1.CertificateKind,
+			APIVersion: v1.APIVersion,
+			Metadata:   v1.CertificateMetadata{Namespace: namespace, Name: name},
+		})
+
+		return err
+	}
+
+	require.NoError(t, create("default", "cert-1"))
+	require.NoError(t, create("other", "cert-2"), "the quota counts across namespaces")
+
+	err := create("default", "cert-3")
+	require.ErrorIs(t, err, model.ErrQuotaExceeded)
+
+	_, err = svc.GetCertificate(ctx, "default", "cert-3", nil)
+	require.ErrorIs(t, err, model.ErrResourceNotExist, "a rejected create stores nothing")
+
+	require.NoError(t, svc.DeleteCertificate(ctx, "default", "cert-1"))
+	require.NoError(t, create("default", "cert-3"), "deleting frees a slot")
+}
+
+// selectorAgentUsecase lists the agents matching a selector in a single page.
+type selectorAgentUsecase struct {
+	agentport.AgentUsecase
+
+	agents []*agentmodel.Agent
+}
+
+func (s *selectorAgentUsecase) ListAgentsBySelector(
+	_ context.Context, selector agentmodel.AgentSelector, _ *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	//exhaustruct:ignore
+	return &model.ListResponse[*agentmodel.Agent]{
+		Items: lo.Filter(s.agents, func(agent *agentmodel.Agent, _ int) bool { return selector.Matches(agent) }),
+	}, nil
+}
+
+// staticAgentGroupUsecase lists a fixed set of agent groups in a single page.
+type staticAgentGroupUsecase struct {
+	agentport.AgentGroupUsecase
+
+	agentGroups []*agentmodel.AgentGroup
+}
+
+func (s *staticAgentGroupUsecase) ListAgentGroups(
+	context.Context, *model.ListOptions,
+) (*model.ListResponse[*agentmodel.AgentGroup], error) {
+	//exhaustruct:ignore
+	return &model.ListResponse[*agentmodel.AgentGroup]{Items: s.agentGroups}, nil
+}
+
+func TestService_GetCertificateUsage(t *testing.T) {
+	t.Parallel()
+
+	selector := agentmodel.AgentSelector{IdentifyingAttributes: map[string]string{"service.name": "collector"}}
+
+	newAgentGroup := func(namespace, name string, config *agentmodel.AgentGroupConnectionConfig) *agentmodel.AgentGroup {
+		agentGroup := agentmodel.NewAgentGroup(namespace, name, nil, time.Now(), "test")
+		agentGroup.Spec.Selector = selector
+		agentGroup.Spec.AgentConnectionConfig = config
+
+		return agentGroup
+	}
+
+	newAgent := func(t *testing.T, namespace string, status agentmodel.AgentConnectionSettingsStatus) *agentmodel.Agent {
+		t.Helper()
+
+		connectionInfo, err := agentmodel.NewConnectionInfo(
+			&agentmodel.AgentOpAMPConnectionSettings{DestinationEndpoint: "wss://opamp.example/v1/opamp"},
+			nil, nil, nil, nil,
+		)
+		require.NoError(t, err)
+
+		capabilities := agent.Capabilities(agent.AgentCapabilityAcceptsOpAMPConnectionSettings)
+		a := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+		a.Metadata.Namespace = namespace
+		a.Metadata.Description.IdentifyingAttributes = map[string]string{"service.name": "collector"}
+		a.Spec.ConnectionInfo = connectionInfo
+
+		if status.LastConnectionSettingsHash == nil {
+			status.LastConnectionSettingsHash = connectionInfo.Hash.Bytes()
+		}
+
+		a.Status.ConnectionSettingsStatus = status
+
+		return a
+	}
+
+	certificateName := "cert-1"
+	otherCertificateName := "cert-2"
+
+	t.Run("reports agents that accepted and failed the connection settings", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		base := testutil.NewBase(t)
+
+		acceptedAgent := newAgent(t, "default", agentmodel.AgentConnectionSettingsStatus{
+			Status: agentmodel.ConnectionSettingsStatusApplied,
+		})
+		failedAgent := newAgent(t, "default", agentmodel.AgentConnectionSettingsStatus{
+			Status:       agentmodel.ConnectionSettingsStatusFailed,
+			ErrorMessage: "x509: certificate signed by unknown authority",
+		})
+		staleAgent := newAgent(t, "default", agentmodel.AgentConnectionSettingsStatus{
+			LastConnectionSettingsHash: []byte("previous"),
+			Status:                     agentmodel.ConnectionSettingsStatusApplied,
+		})
+		otherNamespaceAgent := newAgent(t, "other", agentmodel.AgentConnectionSettingsStatus{
+			Status: agentmodel.ConnectionSettingsStatusApplied,
+		})
+
+		deletedAgentGroup := newAgentGroup("default", "deleted", &agentmodel.AgentGroupConnectionConfig{
+			OpAMPConnection: &agentmodel.OpAMPConnectionSettings{CertificateName: &certificateName},
+		})
+		deletedAgentGroup.MarkDeleted(time.Now(), "test")
+
+		agentGroupUsecase := &staticAgentGroupUsecase{agentGroups: []*agentmodel.AgentGroup{
+			newAgentGroup("default", "collectors", &agentmodel.AgentGroupConnectionConfig{
+				OpAMPConnection: &agentmodel.OpAMPConnectionSettings{CertificateName: &certificateName},
+			}),
+			newAgentGroup("default", "metrics", &agentmodel.AgentGroupConnectionConfig{
+				OwnMetrics: &agentmodel.TelemetryConnectionSettings{CertificateName: &certificateName},
+			}),
+			newAgentGroup("default", "gateways", &agentmodel.AgentGroupConnectionConfig{
+				OpAMPConnection: &agentmodel.OpAMPConnectionSettings{CertificateName: &otherCertificateName},
+			}),
+			newAgentGroup("default", "plain", nil),
+			newAgentGroup("other", "collectors", &agentmodel.AgentGroupConnectionConfig{
+				OpAMPConnection: &agentmodel.OpAMPConnectionSettings{CertificateName: &certificateName},
+			}),
+			deletedAgentGroup,
+		}}
+		agentUsecase := &selectorAgentUsecase{
+			agents: []*agentmodel.Agent{acceptedAgent, failedAgent, staleAgent, otherNamespaceAgent},
+		}
+
+		mockCert := new(mockCertificateUsecase)
+		mockCert.On("GetCertificate", ctx, "default", certificateName, mock.Anything).Return(newCert(), nil)
+
+		svc := certificatesvc.NewCertificateService(mockCert, agentGroupUsecase, agentUsecase,
+			agentservice.NewResourceQuotaService(nil, nil), base.Logger)
+
+		usage, err := svc.GetCertificateUsage(ctx, "default", certificateName)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"collectors", "metrics"}, usage.AgentGroups)
+		assert.Equal(t, []v1.CertificateUsageAgent{
+			{
+				InstanceUID: acceptedAgent.Metadata.InstanceUID,
+				AgentGroups: []string{"collectors", "metrics"},
+				Status:      "APPLIED",
+				Accepted:    true,
+			},
+			{
+				InstanceUID:  failedAgent.Metadata.InstanceUID,
+				AgentGroups:  []string{"collectors", "metrics"},
+				Status:       "FAILED",
+				ErrorMessage: "x509: certificate signed by unknown authority",
+				Accepted:     false,
+			},
+			{
+				InstanceUID: staleAgent.Metadata.InstanceUID,
+				AgentGroups: []string{"collectors", "metrics"},
+				Status:      "APPLIED",
+				Accepted:    false,
+			},
+		}, usage.Agents)
+	})
+
+	t.Run("missing certificate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockCert := new(mockCertificateUsecase)
+		mockCert.On("GetCertificate", ctx, "default", certificateName, mock.Anything).
+			Return(nil, model.ErrResourceNotExist)
+
+		_, err := newSvc(t, mockCert).GetCertificateUsage(ctx, "default", certificateName)
+		require.ErrorIs(t, err, model.ErrResourceNotExist)
+	})
+}
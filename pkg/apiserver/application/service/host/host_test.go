@@ -155,6 +155,46 @@ func (m *mockAgentUsecase) SearchAgents(
 	return resp, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *mockAgentUsecase) ListAgentsByPackage(
+	ctx context.Context, namespace string, packageName string, packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	resp, ok := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+	if !ok {
+		return nil, errMock
+	}
+
+	return resp, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockAgentUsecase) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *mockAgentUsecase) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 func newSvc(t *testing.T, host *mockHostUsecase, agent *mockAgentUsecase) *hostsvc.Service {
 	t.Helper()
 
@@ -0,0 +1,277 @@
+//nolint:testpackage // white-box test of the unexported connection cleanup
+package opamp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+// storedAgentUsecase keeps the last saved agent as the stored one.
+type storedAgentUsecase struct {
+	agentport.AgentUsecase
+
+	stored *agentmodel.Agent
+}
+
+func (s *storedAgentUsecase) GetAgent(_ context.Context, _ uuid.UUID) (*agentmodel.Agent, error) {
+	return s.stored.Clone(), nil
+}
+
+func (s *storedAgentUsecase) ListAgentsConnectionLostBy(
+	_ context.Context, lostBy time.Time, _ int,
+) ([]*agentmodel.Agent, error) {
+	if !s.stored.IsInDisconnectGrace() || s.stored.Status.ConnectionLostAt.After(lostBy) {
+		return nil, nil
+	}
+
+	return []*agentmodel.Agent{s.stored.Clone()}, nil
+}
+
+func (s *storedAgentUsecase) SaveAgent(_ context.Context, agent *agentmodel.Agent) error {
+	s.stored = agent.Clone()
+
+	return nil
+}
+
+// singleConnectionUsecase resolves every connection to the same agent connection.
+type singleConnectionUsecase struct {
+	agentport.ConnectionUsecase
+
+	connection *agentmodel.Connection
+}
+
+func (s *singleConnectionUsecase) GetConnectionByID(context.Context, any) (*agentmodel.Connection, error) {
+	return s.connection, nil
+}
+
+func (s *singleConnectionUsecase) DeleteConnection(context.Context, *agentmodel.Connection) error {
+	return nil
+}
+
+func TestCleanUpConnection_DisconnectGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	const grace = 30 * time.Second
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.UpdateLastCommunicationInfo(start, nil)
+
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+	connection.SetInstanceUID(agent.Metadata.InstanceUID)
+
+	agentUC := &storedAgentUsecase{stored: agent}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             agentUC,
+		connectionUsecase:        &singleConnectionUsecase{connection: connection},
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		disconnectGracePeriod:    grace,
+		disconnectGraceUsecase:   agentUC,
+	}
+
+	// The WebSocket closes a second after the last report.
+	testClock.now = start.Add(time.Second)
+	require.NoError(t, svc.cleanUpConnection(t.Context(), newRecordingConnection(t, false)))
+
+	stateAt := func(now time.Time) agentmodel.AgentConnectionState {
+		return agentUC.stored.ConnectionStateAt(now, agentmodel.DefaultConnectionStaleness)
+	}
+
+	assert.Equal(t, agentmodel.AgentConnectionStateGrace, stateAt(testClock.now))
+
+	testClock.now = start.Add(time.Second + grace - time.Millisecond)
+	svc.finishDisconnectGraces(t.Context())
+	assert.True(t, agentUC.stored.IsConnectedAt(testClock.now, agentmodel.DefaultConnectionStaleness),
+		"the agent stays connected within the grace period")
+	assert.Equal(t, agentmodel.AgentConnectionStateGrace, stateAt(testClock.now))
+
+	testClock.now = start.Add(time.Second + grace)
+	svc.finishDisconnectGraces(t.Context())
+	assert.False(t, agentUC.stored.IsConnectedAt(testClock.now, agentmodel.DefaultConnectionStaleness),
+		"the agent is disconnected once the grace period elapses")
+	assert.Equal(t, start.Add(time.Second), agentUC.stored.Status.ConnectionStats.LastDisconnectedAt)
+}
+
+func TestCleanUpConnection_ReconnectWithinGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	const grace = 30 * time.Second
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.UpdateLastCommunicationInfo(start, nil)
+
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+	connection.SetInstanceUID(agent.Metadata.InstanceUID)
+
+	agentUC := &storedAgentUsecase{stored: agent}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             agentUC,
+		connectionUsecase:        &singleConnectionUsecase{connection: connection},
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		disconnectGracePeriod:    grace,
+		disconnectGraceUsecase:   agentUC,
+	}
+
+	require.NoError(t, svc.cleanUpConnection(t.Context(), newRecordingConnection(t, false)))
+
+	// The agent reconnects and reports before the grace period elapses.
+	testClock.now = start.Add(5 * time.Second)
+	agentUC.stored.UpdateLastCommunicationInfo(testClock.now, nil)
+
+	testClock.now = start.Add(grace)
+	svc.finishDisconnectGraces(t.Context())
+
+	assert.Equal(t, agentmodel.AgentConnectionStateConnected,
+		agentUC.stored.ConnectionStateAt(testClock.now, agentmodel.DefaultConnectionStaleness))
+	assert.Zero(t, agentUC.stored.Status.ConnectionStats.DisconnectCount)
+}
+
+func TestDisconnectGracePeriod_LogsTheDisconnectTransitionOnce(t *testing.T) {
+	t.Parallel()
+
+	const grace = 30 * time.Second
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.UpdateLastCommunicationInfo(start, nil)
+
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+	connection.SetInstanceUID(agent.Metadata.InstanceUID)
+
+	var logs bytes.Buffer
+
+	agentUC := &storedAgentUsecase{stored: agent}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             agentUC,
+		connectionUsecase:        &singleConnectionUsecase{connection: connection},
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		disconnectGracePeriod:    grace,
+		disconnectGraceUsecase:   agentUC,
+		transitionLogger: helper.NewAgentTransitionLogger(
+			slog.New(slog.NewJSONHandler(&logs, nil)), testClock, 0),
+	}
+
+	require.NoError(t, svc.cleanUpConnection(t.Context(), newRecordingConnection(t, false)))
+	assert.Empty(t, logs.String(), "an agent in its grace period is still connected")
+
+	testClock.now = start.Add(grace)
+	svc.finishDisconnectGraces(t.Context())
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"transition":"Disconnected"`)
+	assert.Contains(t, lines[0], `"instanceUID":"`+agent.Metadata.InstanceUID.String()+`"`)
+}
+
+func TestFinishDisconnectGraces_FinishesAGracePeriodStartedByAnotherServer(t *testing.T) {
+	t.Parallel()
+
+	const grace = 30 * time.Second
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start.Add(grace)}
+
+	// The connection closed on a server that stopped before the grace period elapsed, so
+	// only the stored agent knows about it.
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.UpdateLastCommunicationInfo(start, nil)
+	agent.RecordConnectionLostAt(start)
+
+	agentUC := &storedAgentUsecase{stored: agent}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             agentUC,
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		disconnectGracePeriod:    grace,
+		disconnectGraceUsecase:   agentUC,
+		transitionLogger:         helper.NewAgentTransitionLogger(slog.New(slog.DiscardHandler), testClock, 0),
+	}
+
+	svc.finishDisconnectGraces(t.Context())
+
+	assert.Equal(t, agentmodel.AgentConnectionStateDisconnected,
+		agentUC.stored.ConnectionStateAt(testClock.now, agentmodel.DefaultConnectionStaleness))
+	assert.Equal(t, start, agentUC.stored.Status.ConnectionStats.LastDisconnectedAt)
+}
+
+// recordingDeliveryTracker records the agents whose deliveries it was told to forget.
+type recordingDeliveryTracker struct {
+	forgotten []uuid.UUID
+}
+
+func (r *recordingDeliveryTracker) ForgetAgentDeliveries(instanceUID uuid.UUID) {
+	r.forgotten = append(r.forgotten, instanceUID)
+}
+
+func TestForgetTransientAgentState(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+	tracker := &recordingDeliveryTracker{}
+	svc := &Service{
+		logger:          slog.New(slog.DiscardHandler),
+		deliveryTracker: tracker,
+	}
+	svc.lastSaveAt.Store(instanceUID.String(), time.Now())
+
+	svc.forgetTransientAgentState(svc.logger, instanceUID)
+
+	assert.Equal(t, []uuid.UUID{instanceUID}, tracker.forgotten)
+
+	_, throttled := svc.lastSaveAt.Load(instanceUID.String())
+	assert.False(t, throttled, "the first message after the reconnect is persisted immediately")
+}
+
+func TestCleanUpConnection_ForgetsDeliveriesWithoutAnAnnouncedDisconnect(t *testing.T) {
+	t.Parallel()
+
+	agent := agentmodel.NewAgent(uuid.New())
+
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+	connection.SetInstanceUID(agent.Metadata.InstanceUID)
+
+	testClock := &persistTestClock{now: time.Now()}
+	tracker := &recordingDeliveryTracker{}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             &storedAgentUsecase{stored: agent},
+		connectionUsecase:        &singleConnectionUsecase{connection: connection},
+		deliveryTracker:          tracker,
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		transitionLogger:         helper.NewAgentTransitionLogger(slog.New(slog.DiscardHandler), testClock, 0),
+	}
+
+	// The connection closes without the agent sending agent_disconnect first, e.g. because
+	// it crashed before applying an offer pushed to it.
+	require.NoError(t, svc.cleanUpConnection(t.Context(), newRecordingConnection(t, false)))
+
+	assert.Equal(t, []uuid.UUID{agent.Metadata.InstanceUID}, tracker.forgotten,
+		"the offer is pushed again after the agent reconnects")
+}
@@ -0,0 +1,105 @@
+package opamp
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+// EffectiveConfigPersistence controls when an effective config an agent reports makes the
+// server persist the agent. It only concerns the effective config: other reports in the
+// same message are persisted as usual. The zero value persists every report.
+type EffectiveConfigPersistence struct {
+	// OnChangeOnly stops an effective config identical to the stored one from causing a
+	// write. It is still recorded as reported, and saved with the agent's next write.
+	OnChangeOnly bool
+	// SampleInterval is the minimum interval between persisting an agent's changed
+	// effective configs. A config changed sooner is held back and taken by the agent's
+	// first message after the interval, unless a newer one replaced it meanwhile. Zero
+	// persists every change.
+	SampleInterval time.Duration
+}
+
+// effectiveConfigSample tracks an agent's effective-config sampling.
+type effectiveConfigSample struct {
+	// sampledAt is when a changed effective config was last taken.
+	sampledAt time.Time
+	// held is the latest changed effective config reported within the interval, or nil.
+	held *agentmodel.AgentEffectiveConfig
+}
+
+// SetEffectiveConfigPersistence sets when reported effective configs are persisted, so
+// agents with large, frequently changing configs do not cost a write for every report.
+func (s *Service) SetEffectiveConfigPersistence(persistence EffectiveConfigPersistence) {
+	s.effectiveConfigPersistence = persistence
+}
+
+// sampleEffectiveConfig decides what becomes of the effective config an agent reported,
+// nil when the message carries none. It returns the config to record on the agent, nil
+// to leave the agent's config as it is, and whether recording it warrants a write.
+//
+// A config identical to the agent's current one is recorded and drops any held config,
+// as the agent is back on the stored one. Otherwise a held config may be taken now, even
+// by a message that carries no config.
+func (s *Service) sampleEffectiveConfig(
+	agent *agentmodel.Agent,
+	reported *agentmodel.AgentEffectiveConfig,
+	now time.Time,
+) (*agentmodel.AgentEffectiveConfig, bool) {
+	persistence := s.effectiveConfigPersistence
+	instanceUID := agent.Metadata.InstanceUID
+
+	if reported != nil && reported.Equal(&agent.Status.EffectiveConfig) {
+		s.dropHeldEffectiveConfig(instanceUID)
+
+		return reported, !persistence.OnChangeOnly
+	}
+
+	if persistence.SampleInterval <= 0 {
+		return reported, reported != nil
+	}
+
+	key := instanceUID.String()
+	sample := &effectiveConfigSample{sampledAt: time.Time{}, held: nil}
+
+	if value, found := s.effectiveConfigSamples.Load(key); found {
+		sample, _ = value.(*effectiveConfigSample)
+	}
+
+	if reported != nil {
+		sample.held = reported
+	}
+
+	if sample.held == nil {
+		return nil, false
+	}
+
+	if !sample.sampledAt.IsZero() && now.Sub(sample.sampledAt) < persistence.SampleInterval {
+		s.effectiveConfigSamples.Store(key, sample)
+
+		return nil, false
+	}
+
+	taken := sample.held
+	s.effectiveConfigSamples.Store(key, &effectiveConfigSample{sampledAt: now, held: nil})
+
+	return taken, true
+}
+
+// dropHeldEffectiveConfig forgets the effective config held back for the agent, keeping
+// when one was last sampled.
+func (s *Service) dropHeldEffectiveConfig(instanceUID uuid.UUID) {
+	key := instanceUID.String()
+
+	value, found := s.effectiveConfigSamples.Load(key)
+	if !found {
+		return
+	}
+
+	sample, _ := value.(*effectiveConfigSample)
+	if sample != nil && sample.held != nil {
+		s.effectiveConfigSamples.Store(key, &effectiveConfigSample{sampledAt: sample.sampledAt, held: nil})
+	}
+}
@@ -0,0 +1,38 @@
+package opamp
+
+import (
+	"context"
+	"log/slog"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+// SetAgentEnricher sets the enricher deriving the agents' server-owned labels, called
+// when an agent is first seen and whenever it reports a new description. Nil disables
+// the enrichment.
+func (s *Service) SetAgentEnricher(enricher agentport.AgentEnrichmentPort) {
+	s.enricher = enricher
+}
+
+// enrichAgent replaces the agent's server-owned labels with the ones the enricher derives.
+// A failed enrichment is logged and leaves the labels as they are.
+func (s *Service) enrichAgent(
+	ctx context.Context,
+	logger *slog.Logger,
+	agent *agentmodel.Agent,
+	remoteAddr string,
+) {
+	if s.enricher == nil {
+		return
+	}
+
+	labels, err := s.enricher.EnrichAgent(ctx, agent, remoteAddr)
+	if err != nil {
+		logger.Warn("failed to enrich agent; keeping its labels", slog.String("error", err.Error()))
+
+		return
+	}
+
+	agent.SetLabels(labels)
+}
@@ -0,0 +1,84 @@
+//nolint:testpackage // white-box test of the unexported enrichment step
+package opamp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+var errEnrichmentUnavailable = errors.New("enrichment source unavailable")
+
+// stubAgentEnricher labels every agent with the region of the address it reports from,
+// or fails with err when set.
+type stubAgentEnricher struct {
+	regions map[string]string
+	err     error
+	calls   int
+}
+
+func (e *stubAgentEnricher) EnrichAgent(
+	_ context.Context,
+	_ *agentmodel.Agent,
+	remoteAddr string,
+) (map[string]string, error) {
+	e.calls++
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return map[string]string{"region": e.regions[remoteAddr]}, nil
+}
+
+func TestEnrichAgent_LabelsArePersisted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	agentUC := &countingAgentUsecase{}
+	enricher := &stubAgentEnricher{regions: map[string]string{"10.0.0.1:4317": "eu-west-1"}}
+	svc := &Service{
+		clock:                 &persistTestClock{now: now},
+		logger:                slog.New(slog.DiscardHandler),
+		agentUsecase:          agentUC,
+		hostUsecase:           noopObserver{},
+		containerUsecase:      noopContainerObserver{},
+		heartbeatSaveThrottle: DefaultHeartbeatSaveThrottle,
+	}
+	svc.SetAgentEnricher(enricher)
+
+	instanceUID := uuid.New()
+	message := &protobufs.AgentToServer{AgentDescription: &protobufs.AgentDescription{}}
+
+	agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+
+	svc.enrichAgent(t.Context(), svc.logger, agent, "10.0.0.1:4317")
+	svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(message, false),
+		agent, deferred, now)
+
+	require.Equal(t, 1, agentUC.saves)
+	assert.Equal(t, map[string]string{"region": "eu-west-1"}, agentUC.saved.Metadata.Labels)
+
+	t.Run("a failed enrichment keeps the labels", func(t *testing.T) {
+		t.Parallel()
+
+		failing := &stubAgentEnricher{err: errEnrichmentUnavailable}
+		svc := &Service{logger: slog.New(slog.DiscardHandler), enricher: failing}
+
+		agent := agentUC.saved.Clone()
+		svc.enrichAgent(t.Context(), svc.logger, agent, "10.0.0.2:4317")
+
+		assert.Equal(t, 1, failing.calls)
+		assert.Equal(t, map[string]string{"region": "eu-west-1"}, agent.Metadata.Labels)
+	})
+}
@@ -0,0 +1,69 @@
+package opamp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.opentelemetry.io/otel/attribute"
+	metricapi "go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// Reasons an AgentToServer message is answered with an error_response. They are the
+// "reason" attribute of the error response counter, so keep them stable.
+const (
+	rejectReasonInvalidInstanceUID    = "invalid_instance_uid"
+	rejectReasonRevoked               = "revoked"
+	rejectReasonAgentStateUnavailable = "agent_state_unavailable"
+	rejectReasonReportNotApplied      = "report_not_applied"
+)
+
+// errorResponseCounterName is the metric counting the error responses sent to agents.
+const errorResponseCounterName = "opamp.server.error_responses"
+
+// newErrorResponseCounter creates the error response counter from meterProvider. The
+// provider is nil when metrics are disabled, and a counter that cannot be created is
+// replaced by a no-op one: losing the metric must not stop agents from being served.
+func newErrorResponseCounter(meterProvider metricapi.MeterProvider) metricapi.Int64Counter {
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	counter, err := meterProvider.Meter(tracerName).Int64Counter(errorResponseCounterName,
+		metricapi.WithDescription("Number of AgentToServer messages answered with an error_response."),
+		metricapi.WithUnit("{message}"),
+	)
+	if err != nil {
+		return metricnoop.Int64Counter{}
+	}
+
+	return counter
+}
+
+// rejectMessage builds the error-only response for a message the server refuses to
+// process, logging the rejection and counting it by error type and reason.
+func (s *Service) rejectMessage(
+	ctx context.Context,
+	logger *slog.Logger,
+	instanceUID uuid.UUID,
+	reason string,
+	errorType protobufs.ServerErrorResponseType,
+	message string,
+) *protobufs.ServerToAgent {
+	logger.Warn("rejecting agent message",
+		slog.String("reason", reason),
+		slog.String("errorType", errorType.String()),
+		slog.String("errorMessage", message),
+	)
+
+	if s.errorResponseCounter != nil {
+		s.errorResponseCounter.Add(ctx, 1, metricapi.WithAttributes(
+			attribute.String("error_type", errorType.String()),
+			attribute.String("reason", reason),
+		))
+	}
+
+	return s.createErrorServerToAgent(instanceUID, errorType, message)
+}
@@ -0,0 +1,80 @@
+//nolint:testpackage // white-box test of the unexported rejection path
+package opamp
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// errorResponseCounts reads the error response counter, keyed by its reason attribute.
+func errorResponseCounts(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+
+	counts := map[string]int64{}
+
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if m.Name != errorResponseCounterName {
+				continue
+			}
+
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+
+			for _, point := range sum.DataPoints {
+				reason, _ := point.Attributes.Value(attribute.Key("reason"))
+				counts[reason.AsString()] += point.Value
+			}
+		}
+	}
+
+	return counts
+}
+
+func TestOnMessage_RejectedMessagesAreCounted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("revoked agent", func(t *testing.T) {
+		t.Parallel()
+
+		instanceUID := uuid.New()
+		reader := sdkmetric.NewManualReader()
+		svc := newRevocationTestService(instanceUID)
+		svc.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+		response := svc.OnMessage(t.Context(), newRecordingConnection(t, true),
+			&protobufs.AgentToServer{InstanceUid: instanceUID[:]})
+
+		require.NotNil(t, response.GetErrorResponse())
+		assert.Equal(t, protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
+			response.GetErrorResponse().GetType())
+		assert.Equal(t, map[string]int64{rejectReasonRevoked: 1}, errorResponseCounts(t, reader))
+	})
+
+	t.Run("malformed instance uid", func(t *testing.T) {
+		t.Parallel()
+
+		reader := sdkmetric.NewManualReader()
+		svc := newRevocationTestService()
+		svc.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+		// Reaching any usecase would panic: the message is refused before it is looked at.
+		response := svc.OnMessage(t.Context(), newRecordingConnection(t, false),
+			&protobufs.AgentToServer{InstanceUid: []byte{0x01, 0x02}})
+
+		require.NotNil(t, response.GetErrorResponse())
+		assert.Equal(t, protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
+			response.GetErrorResponse().GetType())
+		assert.Equal(t, map[string]int64{rejectReasonInvalidInstanceUID: 1}, errorResponseCounts(t, reader))
+	})
+}
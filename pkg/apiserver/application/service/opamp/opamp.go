@@ -3,21 +3,30 @@ package opamp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/open-telemetry/opamp-go/server/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	metricapi "go.opentelemetry.io/otel/metric"
+	traceapi "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
@@ -43,19 +52,37 @@ const (
 	// and eligible for GC. Set generously above the throttle window so we never evict
 	// a live agent's entry mid-throttle.
 	DefaultLastSaveAtTTL = 30 * time.Minute
+	// DefaultDisconnectGraceCheckInterval is how often agents whose connection was lost
+	// are checked for an elapsed disconnect grace period.
+	DefaultDisconnectGraceCheckInterval = time.Second
+	// disconnectGraceBatchSize caps how many agents with an elapsed disconnect grace period
+	// are marked disconnected per check; the rest are left to the next checks.
+	disconnectGraceBatchSize = 100
+
+	// tracerName is the instrumentation scope of the spans around OpAMP message handling.
+	tracerName = "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/opamp"
 )
 
 // Service is a struct that implements the OpAMPUsecase interface.
 type Service struct {
-	clock                    clock.Clock
-	logger                   *slog.Logger
-	agentUsecase             agentport.AgentUsecase
-	agentGroupUsecase        agentport.AgentGroupUsecase
-	agentRemoteConfigUsecase agentport.AgentRemoteConfigUsecase
-	hostUsecase              agentport.HostUsecase
-	containerUsecase         agentport.ContainerUsecase
-	serverIdentityProvider   agentport.ServerIdentityProvider
-	serverToAgentBuilder     *agentservice.ServerToAgentBuilder
+	clock                        clock.Clock
+	logger                       *slog.Logger
+	tracer                       traceapi.Tracer
+	errorResponseCounter         metricapi.Int64Counter
+	attributeDenylist            modelagent.AttributeDenylist
+	attributeAliases             modelagent.AttributeAliases
+	attributeLimits              modelagent.AttributeLimits
+	effectiveConfigHistoryLimits agentmodel.EffectiveConfigHistoryLimits
+	availableComponentsLimits    agentmodel.AvailableComponentsLimits
+	defaultConfigContentType     string
+	agentUsecase                 agentport.AgentUsecase
+	agentGroupUsecase            agentport.AgentGroupUsecase
+	agentRemoteConfigUsecase     agentport.AgentRemoteConfigUsecase
+	hostUsecase                  agentport.HostUsecase
+	containerUsecase             agentport.ContainerUsecase
+	agentRevocationUsecase       agentport.AgentRevocationUsecase
+	serverIdentityProvider       agentport.ServerIdentityProvider
+	serverToAgentBuilder         *agentservice.ServerToAgentBuilder
 
 	agentNotificationUsecase agentport.AgentNotificationUsecase
 
@@ -68,6 +95,49 @@ type Service struct {
 	lastSaveAt            sync.Map // instanceUID(string) -> time.Time
 	lastSaveAtGCInterval  time.Duration
 	lastSaveAtTTL         time.Duration
+
+	// unsavedHeartbeats holds the latest heartbeat of each agent whose save was throttled,
+	// so the agent's last-known connection state is still persisted when it disconnects
+	// or the server shuts down.
+	unsavedHeartbeats sync.Map // instanceUID(string) -> agentmodel.AgentHeartbeat
+
+	// minReportInterval is the minimum interval between persisting an agent's reports.
+	// Zero disables it. Reports arriving sooner are applied to the agent held in
+	// deferredSaves, which is persisted once the interval has passed. The held agent is
+	// only reused while it is as recent as the stored one; see loadAgent.
+	minReportInterval time.Duration
+	deferredSaves     sync.Map // instanceUID(string) -> *agentmodel.Agent
+
+	// unsavedReports replays the reports applied to an agent since it was last saved. A
+	// save that loses its version check to another write, such as an API update made
+	// while the reports were deferred, re-applies them to the agent as stored instead of
+	// dropping them.
+	unsavedReports sync.Map // instanceUID(string) -> []agentReport
+
+	effectiveConfigPersistence EffectiveConfigPersistence
+	effectiveConfigSamples     sync.Map // instanceUID(string) -> *effectiveConfigSample
+
+	// disconnectGracePeriod is how long an agent whose WebSocket closed stays connected
+	// before it is marked disconnected. Zero marks it disconnected on close.
+	disconnectGracePeriod time.Duration
+	// disconnectGraceUsecase finds the agents whose grace period has elapsed. Nil when no
+	// grace period is set.
+	disconnectGraceUsecase agentport.AgentDisconnectGraceUsecase
+
+	// deliveryTracker is told to forget the updates pushed to an agent that sends
+	// agent_disconnect. Nil when none is set.
+	deliveryTracker agentport.AgentDeliveryTracker
+
+	// effectiveConfigChangePublisher announces the agents that reported a changed effective
+	// config, once the agent is saved. Nil when none is set.
+	effectiveConfigChangePublisher agentport.AgentEffectiveConfigChangePublisher
+	effectiveConfigChanges         sync.Map // instanceUID(string) -> struct{}
+
+	// transitionLogger logs the agents' state transitions. Nil when they are not logged.
+	transitionLogger *helper.AgentTransitionLogger
+
+	// enricher derives the agents' server-owned labels. Nil when agents are not enriched.
+	enricher agentport.AgentEnrichmentPort
 }
 
 // New creates a new instance of the OpAMP service.
@@ -81,30 +151,138 @@ func New(
 	agentRemoteConfigUsecase agentport.AgentRemoteConfigUsecase,
 	hostUsecase agentport.HostUsecase,
 	containerUsecase agentport.ContainerUsecase,
+	agentRevocationUsecase agentport.AgentRevocationUsecase,
+	traceProvider traceapi.TracerProvider,
 	logger *slog.Logger,
 ) *Service {
+	// The provider is nil when tracing is disabled.
+	if traceProvider == nil {
+		traceProvider = noop.NewTracerProvider()
+	}
+
 	return &Service{
-		clock:                    clock.NewRealClock(),
-		logger:                   logger,
-		agentUsecase:             agentUsecase,
-		connectionUsecase:        connectionUsecase,
-		serverIdentityProvider:   serverIdentityProvider,
-		serverToAgentBuilder:     serverToAgentBuilder,
-		agentGroupUsecase:        agentGroupUsecase,
-		agentNotificationUsecase: agentNotificationUsecase,
-		agentRemoteConfigUsecase: agentRemoteConfigUsecase,
-		hostUsecase:              hostUsecase,
-		containerUsecase:         containerUsecase,
-		closedConnectionCh:       make(chan types.Connection, 1), // buffered channel
+		clock:                        clock.NewRealClock(),
+		logger:                       logger,
+		tracer:                       traceProvider.Tracer(tracerName),
+		errorResponseCounter:         newErrorResponseCounter(nil),
+		attributeDenylist:            nil,
+		attributeAliases:             modelagent.DefaultAttributeAliases(),
+		attributeLimits:              modelagent.DefaultAttributeLimits(),
+		effectiveConfigHistoryLimits: agentmodel.DefaultEffectiveConfigHistoryLimits(),
+		availableComponentsLimits:    agentmodel.DefaultAvailableComponentsLimits(),
+		defaultConfigContentType:     helper.TextYAML,
+		agentUsecase:                 agentUsecase,
+		connectionUsecase:            connectionUsecase,
+		serverIdentityProvider:       serverIdentityProvider,
+		serverToAgentBuilder:         serverToAgentBuilder,
+		agentGroupUsecase:            agentGroupUsecase,
+		agentNotificationUsecase:     agentNotificationUsecase,
+		agentRemoteConfigUsecase:     agentRemoteConfigUsecase,
+		hostUsecase:                  hostUsecase,
+		containerUsecase:             containerUsecase,
+		agentRevocationUsecase:       agentRevocationUsecase,
+		closedConnectionCh:           make(chan types.Connection, 1), // buffered channel
 
 		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
 		heartbeatSaveThrottle:    DefaultHeartbeatSaveThrottle,
 		lastSaveAt:               sync.Map{},
+		unsavedHeartbeats:        sync.Map{},
 		lastSaveAtGCInterval:     DefaultLastSaveAtGCInterval,
 		lastSaveAtTTL:            DefaultLastSaveAtTTL,
+		minReportInterval:        0,
+		deferredSaves:            sync.Map{},
+		unsavedReports:           sync.Map{},
+		disconnectGracePeriod:    0,
+		disconnectGraceUsecase:   nil,
+		deliveryTracker:          nil,
+
+		effectiveConfigPersistence:     EffectiveConfigPersistence{OnChangeOnly: false, SampleInterval: 0},
+		effectiveConfigSamples:         sync.Map{},
+		effectiveConfigChangePublisher: nil,
+		effectiveConfigChanges:         sync.Map{},
+		transitionLogger:               nil,
+		enricher:                       nil,
 	}
 }
 
+// SetMeterProvider sets the provider of the metrics the service records, such as the
+// count of error responses sent to agents. A nil provider disables them.
+func (s *Service) SetMeterProvider(meterProvider metricapi.MeterProvider) {
+	s.errorResponseCounter = newErrorResponseCounter(meterProvider)
+}
+
+// SetAttributeDenylist replaces the patterns of reported agent attribute keys that are
+// never stored.
+func (s *Service) SetAttributeDenylist(denylist modelagent.AttributeDenylist) {
+	s.attributeDenylist = denylist
+}
+
+// SetAttributeAliases replaces the aliases used to canonicalize reported agent attributes.
+func (s *Service) SetAttributeAliases(aliases modelagent.AttributeAliases) {
+	s.attributeAliases = aliases
+}
+
+// SetAttributeLimits replaces the limits on how many reported agent attributes are stored.
+func (s *Service) SetAttributeLimits(limits modelagent.AttributeLimits) {
+	s.attributeLimits = limits
+}
+
+// SetEffectiveConfigHistoryLimits replaces the limits on the effective-config history kept
+// per agent.
+func (s *Service) SetEffectiveConfigHistoryLimits(limits agentmodel.EffectiveConfigHistoryLimits) {
+	s.effectiveConfigHistoryLimits = limits
+}
+
+// SetAvailableComponentsLimits replaces the limits on the available components tree stored
+// per agent.
+func (s *Service) SetAvailableComponentsLimits(limits agentmodel.AvailableComponentsLimits) {
+	s.availableComponentsLimits = limits
+}
+
+// SetDefaultConfigContentType sets the content type recorded for reported effective-config
+// files that carry none.
+func (s *Service) SetDefaultConfigContentType(contentType string) {
+	s.defaultConfigContentType = contentType
+}
+
+// SetMinReportInterval sets the minimum interval between persisting an agent's reports.
+// Reports an agent sends sooner are coalesced: the latest state is persisted once the
+// interval has passed. Zero persists every report.
+func (s *Service) SetMinReportInterval(interval time.Duration) {
+	s.minReportInterval = interval
+}
+
+// SetDisconnectGracePeriod sets how long an agent whose WebSocket closed keeps counting as
+// connected. An agent that reconnects within it is never marked disconnected, so a brief
+// network blip does not flip its connected state. Zero marks agents disconnected on close.
+// The grace periods that have elapsed are found through disconnectGraceUsecase, so a grace
+// period started on another server, or on one that has since stopped, is finished too.
+func (s *Service) SetDisconnectGracePeriod(
+	grace time.Duration,
+	disconnectGraceUsecase agentport.AgentDisconnectGraceUsecase,
+) {
+	s.disconnectGracePeriod = grace
+	s.disconnectGraceUsecase = disconnectGraceUsecase
+}
+
+// SetAgentDeliveryTracker sets the tracker of updates pushed to agents, which is cleared
+// for an agent when it sends agent_disconnect.
+func (s *Service) SetAgentDeliveryTracker(tracker agentport.AgentDeliveryTracker) {
+	s.deliveryTracker = tracker
+}
+
+// SetAgentEffectiveConfigChangePublisher sets the publisher told about agents whose
+// reported effective config changed, so watches of the agent on any server emit it.
+func (s *Service) SetAgentEffectiveConfigChangePublisher(publisher agentport.AgentEffectiveConfigChangePublisher) {
+	s.effectiveConfigChangePublisher = publisher
+}
+
+// SetAgentTransitionLogger sets the logger of the agents' state transitions, such as
+// connects, health changes and applied remote configs. Nil disables the logging.
+func (s *Service) SetAgentTransitionLogger(logger *helper.AgentTransitionLogger) {
+	s.transitionLogger = logger
+}
+
 // Name returns the name of the service.
 func (s *Service) Name() string {
 	return "opamp"
@@ -120,10 +298,31 @@ func (s *Service) Run(ctx context.Context) error {
 	gcTicker := time.NewTicker(s.effectiveLastSaveAtGCInterval())
 	defer gcTicker.Stop()
 
+	// Deferred saves are only ever made while minReportInterval is set.
+	var flushC <-chan time.Time
+
+	if s.minReportInterval > 0 {
+		flushTicker := time.NewTicker(s.minReportInterval)
+		defer flushTicker.Stop()
+
+		flushC = flushTicker.C
+	}
+
+	// Agents are only left in their disconnect grace period while disconnectGracePeriod is set.
+	var graceC <-chan time.Time
+
+	if s.disconnectGracePeriod > 0 && s.disconnectGraceUsecase != nil {
+		graceTicker := time.NewTicker(DefaultDisconnectGraceCheckInterval)
+		defer graceTicker.Stop()
+
+		graceC = graceTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			s.logger.Info("context done, exiting service loop")
+			s.persistUnsavedState(ctx)
 
 			return fmt.Errorf("service loop exited: %w", ctx.Err())
 		case conn := <-s.closedConnectionCh:
@@ -137,6 +336,10 @@ func (s *Service) Run(ctx context.Context) error {
 			}
 		case <-gcTicker.C:
 			s.gcLastSaveAt()
+		case <-flushC:
+			s.flushDeferredSaves(ctx)
+		case <-graceC:
+			s.finishDisconnectGraces(ctx)
 		}
 	}
 }
@@ -187,6 +390,7 @@ func (s *Service) OnConnectedWithType(ctx context.Context, conn types.Connection
 }
 
 // OnMessage implements usecase.OpAMPUsecase.
+// [0] refuse revoked instance UIDs, closing their WebSocket connection
 // [1] find agentmodel.Connection by types.Connection
 // [1-1] if not found, unexpected case because all connections should be created when OnConnected is called.
 // so, leave error log and skip connection processing.
@@ -196,13 +400,27 @@ func (s *Service) OnConnectedWithType(ctx context.Context, conn types.Connection
 // [4] save the updated agent
 // [5] fetch ServerToAgent message to send back to the agent
 // [6] return the ServerToAgent message.
+//
+// The handling runs in an "opamp.OnMessage" span, so the persistence calls it makes
+// show up as its children.
 func (s *Service) OnMessage(
 	ctx context.Context,
 	conn types.Connection,
 	message *protobufs.AgentToServer,
-) *protobufs.ServerToAgent {
+) (response *protobufs.ServerToAgent) {
 	remoteAddr := conn.Connection().RemoteAddr().String()
-	instanceUID := uuid.UUID(message.GetInstanceUid())
+	// A malformed instance_uid leaves instanceUID as uuid.Nil; the message is rejected
+	// below, once the span and logger exist to record it.
+	instanceUID, instanceUIDErr := uuid.FromBytes(message.GetInstanceUid())
+
+	ctx, span := s.tracer.Start(ctx, "opamp.OnMessage",
+		traceapi.WithSpanKind(traceapi.SpanKindServer),
+		traceapi.WithAttributes(
+			attribute.String("opamp.instance_uid", instanceUID.String()),
+			attribute.Int64("opamp.sequence_num", int64(message.GetSequenceNum())), //nolint:gosec // fits in practice
+		),
+	)
+	defer func() { endMessageSpan(span, response) }()
 
 	logger := s.logger.With(
 		slog.String("method", "OnMessage"),
@@ -211,8 +429,18 @@ func (s *Service) OnMessage(
 	)
 	logger.Info("start")
 
-	if response := s.handleInstanceUIDConflict(ctx, logger, conn, instanceUID, message); response != nil {
-		return response
+	if instanceUIDErr != nil {
+		return s.rejectMessage(ctx, logger, instanceUID, rejectReasonInvalidInstanceUID,
+			protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
+			"instance_uid must be 16 bytes")
+	}
+
+	if revokedResponse, revoked := s.rejectRevokedAgent(ctx, logger, conn, instanceUID); revoked {
+		return revokedResponse
+	}
+
+	if conflictResponse := s.handleInstanceUIDConflict(ctx, logger, conn, instanceUID, message); conflictResponse != nil {
+		return conflictResponse
 	}
 
 	connection, logger := s.prepareConnection(ctx, logger, conn, instanceUID)
@@ -222,13 +450,13 @@ func (s *Service) OnMessage(
 		logger.Warn("failed to get current server", slog.String("error", err.Error()))
 	}
 
-	agent, err := s.agentUsecase.GetOrCreateAgent(ctx, instanceUID)
+	agent, deferred, err := s.loadAgent(ctx, instanceUID)
 	if err != nil {
 		logger.Error("failed to get agent", slog.String("error", err.Error()))
 
 		// The server could not load the agent's state, so it cannot process this message.
 		// Signal it as Unavailable (a transient server-side failure) so the agent retries.
-		return s.createErrorServerToAgent(instanceUID,
+		return s.rejectMessage(ctx, logger, instanceUID, rejectReasonAgentStateUnavailable,
 			protobufs.ServerErrorResponseType_ServerErrorResponseType_Unavailable,
 			"failed to load agent state")
 	}
@@ -239,33 +467,57 @@ func (s *Service) OnMessage(
 	// arrival, not on SaveAgent return — Mongo latency spikes would otherwise
 	// push the next throttle boundary out by the write duration.
 	receivedAt := s.clock.Now()
+	before := agent.StateSnapshot()
 
 	// Update agent connection status
 	agent.UpdateLastCommunicationInfo(receivedAt, connection)
 
-	reportErr := s.reportAndReconcileGroups(ctx, logger, message, agent, currentServer)
+	persistEffectiveConfig, reportErr := s.reportAndReconcileGroups(ctx, logger, message, agent, currentServer)
 	if reportErr != nil {
 		// The agent's report could not be absorbed into its state. Return an error-only
 		// response (BadRequest) rather than a desired-state message the agent would ignore,
-		// and skip persistence so partially-applied state is not written.
-		return s.createErrorServerToAgent(instanceUID,
+		// and skip persistence so partially-applied state is not written. A deferred save
+		// is dropped with it, since that agent now holds the partially-applied report.
+		s.unsavedReports.Delete(instanceUID.String())
+
+		return s.rejectMessage(ctx, logger, instanceUID, rejectReasonReportNotApplied,
 			protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
 			reportErr.Error())
 	}
 
-	s.maybePersistAgent(ctx, logger, instanceUID, message, agent, receivedAt)
+	if message.GetAgentDescription() != nil {
+		s.enrichAgent(ctx, logger, agent, remoteAddr)
+	}
+
+	s.transitionLogger.LogTransitions(ctx, before, agent)
+	s.maybePersistAgent(ctx, logger, instanceUID, isHeartbeatOnly(message, persistEffectiveConfig),
+		agent, deferred, receivedAt)
+
+	if message.GetAgentDisconnect() != nil {
+		s.forgetTransientAgentState(logger, instanceUID)
+	}
 
 	// Note: NotifyAgentUpdated is NOT called here to avoid infinite loop.
 	// OnMessage already sends a response via fetchServerToAgent.
 	// NotifyAgentUpdated should only be called when agent is updated externally (e.g., via API).
 
-	response := s.fetchServerToAgent(ctx, agent)
+	response = s.fetchServerToAgent(ctx, agent)
 
 	logger.Info("end successfully")
 
 	return response
 }
 
+// endMessageSpan marks the OnMessage span as failed when the agent is sent an error
+// response, then ends it.
+func endMessageSpan(span traceapi.Span, response *protobufs.ServerToAgent) {
+	if errorResponse := response.GetErrorResponse(); errorResponse != nil {
+		span.SetStatus(codes.Error, errorResponse.GetErrorMessage())
+	}
+
+	span.End()
+}
+
 // OnReadMessageError implements usecase.OpAMPUsecase.
 func (s *Service) OnReadMessageError(
 	conn types.Connection,
@@ -316,7 +568,8 @@ func (s *Service) OnConnectionClose(conn types.Connection) {
 // gcLastSaveAt removes lastSaveAt entries older than lastSaveAtTTL. Entries for
 // HTTP-polling agents are never cleared by cleanUpConnection (WebSocket-only),
 // so this sweep is what bounds the map's footprint when HTTP agents go away
-// without explicit teardown.
+// without explicit teardown. Effective-config samples last taken before the same
+// cutoff are removed with them.
 func (s *Service) gcLastSaveAt() {
 	cutoff := s.clock.Now().Add(-s.effectiveLastSaveAtTTL())
 	removed := 0
@@ -332,6 +585,26 @@ func (s *Service) gcLastSaveAt() {
 		return true
 	})
 
+	// An agent that has not reported since the cutoff is stale by now, so its last
+	// heartbeat no longer changes how it is shown.
+	s.unsavedHeartbeats.Range(func(key, val any) bool {
+		heartbeat, isHeartbeat := val.(agentmodel.AgentHeartbeat)
+		if !isHeartbeat || heartbeat.ReportedAt.Before(cutoff) {
+			s.unsavedHeartbeats.Delete(key)
+		}
+
+		return true
+	})
+
+	s.effectiveConfigSamples.Range(func(key, val any) bool {
+		sample, isSample := val.(*effectiveConfigSample)
+		if !isSample || sample.sampledAt.Before(cutoff) {
+			s.effectiveConfigSamples.Delete(key)
+		}
+
+		return true
+	})
+
 	if removed > 0 {
 		s.logger.Debug("garbage-collected stale lastSaveAt entries",
 			slog.Int("removed", removed),
@@ -360,21 +633,47 @@ func (s *Service) effectiveLastSaveAtTTL() time.Duration {
 	return s.lastSaveAtTTL
 }
 
+// report absorbs the reports of agentToServer into the agent. The effective config it
+// carries is not taken from it: effectiveConfig is the one to record, as decided by
+// sampleEffectiveConfig.
 func (s *Service) report(
 	agent *agentmodel.Agent,
 	agentToServer *protobufs.AgentToServer,
+	effectiveConfig *agentmodel.AgentEffectiveConfig,
 	by *agentmodel.Server,
+	now time.Time,
 ) error {
-	now := s.clock.Now()
-
 	// Update communication info
 	agent.RecordLastReported(by, now, agentToServer.GetSequenceNum())
 
-	err := agent.ReportDescription(descToDomain(agentToServer.GetAgentDescription()))
+	description, stripped := descToDomain(agentToServer.GetAgentDescription(),
+		s.attributeDenylist, s.attributeAliases, s.attributeLimits)
+	if stripped > 0 {
+		s.logger.Info("stripped denylisted attributes from the agent description",
+			slog.String("instanceUID", agent.Metadata.InstanceUID.String()),
+			slog.Int("strippedAttributes", stripped),
+		)
+	}
+
+	if description != nil && description.AttributesTruncated {
+		s.logger.Warn("agent reported attributes beyond the configured limits; the excess was dropped",
+			slog.String("instanceUID", agent.Metadata.InstanceUID.String()),
+			slog.Int("identifyingAttributes", len(agentToServer.GetAgentDescription().GetIdentifyingAttributes())),
+			slog.Int("nonIdentifyingAttributes", len(agentToServer.GetAgentDescription().GetNonIdentifyingAttributes())),
+			slog.Int("maxCount", s.attributeLimits.MaxCount),
+			slog.Int("maxTotalBytes", s.attributeLimits.MaxTotalBytes),
+		)
+	}
+
+	err := agent.ReportDescription(description)
 	if err != nil {
 		return fmt.Errorf("failed to report description: %w", err)
 	}
 
+	if description != nil {
+		agent.RecordAttributeDrift(now, "agent")
+	}
+
 	err = agent.ReportComponentHealth(healthToDomain(agentToServer.GetHealth()))
 	if err != nil {
 		return fmt.Errorf("failed to report component health: %w", err)
@@ -387,11 +686,13 @@ func (s *Service) report(
 		return fmt.Errorf("failed to report capabilities: %w", err)
 	}
 
-	err = agent.ReportEffectiveConfig(effectiveConfigToDomain(agentToServer.GetEffectiveConfig()))
+	err = agent.ReportEffectiveConfig(effectiveConfig, now)
 	if err != nil {
 		return fmt.Errorf("failed to report effective config: %w", err)
 	}
 
+	agent.RecordEffectiveConfig(effectiveConfig, now, s.effectiveConfigHistoryLimits)
+
 	err = agent.ReportRemoteConfigStatus(remoteConfigStatusToDomain(agentToServer.GetRemoteConfigStatus(), now))
 	if err != nil {
 		return fmt.Errorf("failed to report remote config status: %w", err)
@@ -403,6 +704,15 @@ func (s *Service) report(
 		return fmt.Errorf("failed to report connection settings status: %w", err)
 	}
 
+	if agentToServer.GetConnectionSettingsStatus() != nil {
+		_, driftErr := agent.RecordConnectionSettingsDrift(now, "agent")
+		if driftErr != nil {
+			s.logger.Warn("failed to compare the agent's connection settings hash with the offered one",
+				slog.String("instanceUID", agent.Metadata.InstanceUID.String()),
+				slog.String("error", driftErr.Error()))
+		}
+	}
+
 	err = agent.ReportPackageStatuses(packageStatusToDomain(agentToServer.GetPackageStatuses()))
 	if err != nil {
 		return fmt.Errorf("failed to report package statuses: %w", err)
@@ -413,7 +723,8 @@ func (s *Service) report(
 		return fmt.Errorf("failed to report custom capabilities: %w", err)
 	}
 
-	err = agent.ReportAvailableComponents(availableComponentsToDomain(agentToServer.GetAvailableComponents()))
+	err = agent.ReportAvailableComponents(
+		availableComponentsToDomain(agentToServer.GetAvailableComponents(), s.availableComponentsLimits))
 	if err != nil {
 		return fmt.Errorf("failed to report available components: %w", err)
 	}
@@ -455,17 +766,35 @@ func (s *Service) cleanUpConnection(ctx context.Context, conn types.Connection)
 	// (a) flip agent.Status.Connected on every poll, and (b) defeat the heartbeat-save
 	// throttle by writing to MongoDB on every request.
 	if !connection.IsAnonymous() && connection.Type == agentmodel.ConnectionTypeWebSocket {
-		agent, err := s.agentUsecase.GetAgent(ctx, connection.InstanceUID)
+		// A deferred save carries reports not persisted yet; it is written along with the
+		// disconnect.
+		agent := s.takeDeferredSave(connection.InstanceUID)
+		s.unsavedReports.Delete(connection.InstanceUID.String())
+
+		if agent == nil {
+			agent, err = s.agentUsecase.GetAgent(ctx, connection.InstanceUID)
+		}
+
+		heartbeat, throttled := s.takeUnsavedHeartbeat(connection.InstanceUID)
+
 		if err != nil {
 			logger.Error("failed to get agent for connection close", slog.String("error", err.Error()))
 			// even if getting agent fails, proceed to delete the connection
 		} else {
-			agent.Status.Connected = false
+			before := agent.StateSnapshot()
+			// The connection closed after the agent's last heartbeat, not its last save.
+			if throttled {
+				agent.RestoreHeartbeat(heartbeat)
+			}
+
+			s.recordConnectionClosed(agent)
 
 			err = s.agentUsecase.SaveAgent(ctx, agent)
 			if err != nil {
 				logger.Error("failed to save agent connection status", slog.String("error", err.Error()))
 				// even if saving fails, proceed to delete the connection
+			} else {
+				s.transitionLogger.LogTransitions(ctx, before, agent)
 			}
 		}
 	}
@@ -475,16 +804,108 @@ func (s *Service) cleanUpConnection(ctx context.Context, conn types.Connection)
 		return fmt.Errorf("failed to delete connection: %w", err)
 	}
 
-	// WebSocket close is a genuine disconnect — clear the throttle entry so the first
-	// message after reconnect is persisted immediately. HTTP polling agents do not get
-	// here because their close is treated as request-end, not disconnect.
+	// WebSocket close is a genuine disconnect, announced or not, e.g. when the agent
+	// crashed or the network dropped. HTTP polling agents do not get here because their
+	// close is treated as request-end, not disconnect.
 	if !connection.IsAnonymous() && connection.Type == agentmodel.ConnectionTypeWebSocket {
-		s.lastSaveAt.Delete(connection.InstanceUID.String())
+		s.forgetTransientAgentState(logger, connection.InstanceUID)
 	}
 
 	return nil
 }
 
+// forgetTransientAgentState drops the in-memory state this server keeps about an agent that
+// announced it is disconnecting or whose WebSocket connection closed. The agent may have
+// gone away before applying an offer that was already pushed to it, so the record of that
+// push is forgotten and the offer is sent again after it reconnects. The heartbeat throttle
+// entry is dropped too, so the first message after the reconnect is persisted immediately.
+func (s *Service) forgetTransientAgentState(logger *slog.Logger, instanceUID uuid.UUID) {
+	logger.Info("agent disconnected; forgetting offers pushed to it")
+
+	s.lastSaveAt.Delete(instanceUID.String())
+	s.effectiveConfigSamples.Delete(instanceUID.String())
+
+	if s.deliveryTracker != nil {
+		s.deliveryTracker.ForgetAgentDeliveries(instanceUID)
+	}
+}
+
+// recordConnectionClosed marks the agent disconnected, or starts its disconnect grace
+// period when one is configured.
+func (s *Service) recordConnectionClosed(agent *agentmodel.Agent) {
+	now := s.clock.Now()
+	if s.disconnectGracePeriod <= 0 {
+		agent.RecordDisconnectedAt(now)
+
+		return
+	}
+
+	agent.RecordConnectionLostAt(now)
+}
+
+// finishDisconnectGraces marks disconnected the agents whose disconnect grace period has
+// elapsed. They are looked up in the store rather than remembered by the server the
+// connection closed on, so every server finishes them, including the grace periods of a
+// server that stopped before they elapsed. Servers racing to mark the same agent conflict
+// on save, and only the first one succeeds.
+func (s *Service) finishDisconnectGraces(ctx context.Context) {
+	now := s.clock.Now()
+
+	listCtx, cancel := context.WithTimeout(ctx, s.onConnectionCloseTimeout)
+	defer cancel()
+
+	agents, err := s.disconnectGraceUsecase.ListAgentsConnectionLostBy(
+		listCtx, now.Add(-s.disconnectGracePeriod), disconnectGraceBatchSize)
+	if err != nil {
+		s.logger.Error("failed to list agents whose disconnect grace period elapsed",
+			slog.String("error", err.Error()))
+
+		return
+	}
+
+	for _, agent := range agents {
+		s.finishDisconnectGrace(ctx, agent, now)
+	}
+}
+
+// finishDisconnectGrace marks the agent disconnected, unless it reported again meanwhile.
+func (s *Service) finishDisconnectGrace(ctx context.Context, agent *agentmodel.Agent, now time.Time) {
+	instanceUID := agent.Metadata.InstanceUID.String()
+
+	// A deferred save is only made for a report, so the agent is back.
+	if _, reported := s.deferredSaves.Load(instanceUID); reported {
+		return
+	}
+
+	logger := s.logger.With(
+		slog.String("method", "finishDisconnectGrace"),
+		slog.String("instanceUID", instanceUID),
+	)
+
+	before := agent.StateSnapshot()
+	if !agent.FinishDisconnectGrace(now, s.disconnectGracePeriod) {
+		return
+	}
+
+	saveCtx, cancel := context.WithTimeout(ctx, s.onConnectionCloseTimeout)
+	defer cancel()
+
+	err := s.agentUsecase.SaveAgent(saveCtx, agent)
+	if errors.Is(err, model.ErrConflict) {
+		logger.Debug("agent changed before its disconnect grace period was finished; leaving it as is")
+
+		return
+	}
+
+	if err != nil {
+		logger.Error("failed to save agent connection status", slog.String("error", err.Error()))
+
+		return
+	}
+
+	s.transitionLogger.LogTransitions(saveCtx, before, agent)
+}
+
 // prepareConnection resolves the agentmodel.Connection for the incoming network connection,
 // injects the instanceUID, and decorates the logger with connection-scoped fields. Errors
 // are logged and the caller is expected to continue without the connection if it is nil.
@@ -536,11 +957,12 @@ func (s *Service) injectInstanceUIDToConnection(
 }
 
 // shouldPersistAgent decides whether the agent's in-memory state should be flushed to
-// the datastore for this incoming message. Non-heartbeat messages (carrying any
-// reported field) are always persisted. For heartbeat-only messages — which dominate
-// the volume at scale — persistence is throttled per agent to amortise writes.
-func (s *Service) shouldPersistAgent(instanceUID uuid.UUID, message *protobufs.AgentToServer) bool {
-	if !isHeartbeatOnly(message) {
+// the datastore for an incoming message, heartbeatOnly as reported by isHeartbeatOnly.
+// Non-heartbeat messages (carrying any reported field) are always persisted. For
+// heartbeat-only messages — which dominate the volume at scale — persistence is
+// throttled per agent to amortise writes.
+func (s *Service) shouldPersistAgent(instanceUID uuid.UUID, heartbeatOnly bool) bool {
+	if !heartbeatOnly {
 		return true
 	}
 
@@ -560,14 +982,21 @@ func (s *Service) shouldPersistAgent(instanceUID uuid.UUID, message *protobufs.A
 // isHeartbeatOnly reports whether the AgentToServer message carries no reported field
 // updates beyond identification. The fixed Capabilities bitfield is intentionally
 // excluded — agents include it on every message even when nothing has changed.
-func isHeartbeatOnly(msg *protobufs.AgentToServer) bool {
+//
+// The effective config counts by persistEffectiveConfig, as decided by
+// sampleEffectiveConfig, rather than by its presence: an identical or held-back config
+// leaves the message a heartbeat, and taking a held-back one makes any message count.
+func isHeartbeatOnly(msg *protobufs.AgentToServer, persistEffectiveConfig bool) bool {
+	if persistEffectiveConfig {
+		return false
+	}
+
 	if msg == nil {
 		return true
 	}
 
 	return msg.GetAgentDescription() == nil &&
 		msg.GetHealth() == nil &&
-		msg.GetEffectiveConfig() == nil &&
 		msg.GetRemoteConfigStatus() == nil &&
 		msg.GetConnectionSettingsStatus() == nil &&
 		msg.GetPackageStatuses() == nil &&
@@ -615,7 +1044,8 @@ func identityChanged(prev identitySnapshot, agent *agentmodel.Agent) bool {
 // the snapshot avoids two map allocations on every heartbeat plus a full ListAgentGroups
 // scan when agents put monotonic counters under NonIdentifyingAttributes.
 //
-// It returns the report error (if any) so the caller can surface it to the agent as an
+// It reports whether the effective config recorded warrants persisting the agent, and
+// returns the report error (if any) so the caller can surface it to the agent as an
 // error_response; on error the group reconcile is skipped because the agent's state may be
 // inconsistent.
 func (s *Service) reportAndReconcileGroups(
@@ -624,7 +1054,7 @@ func (s *Service) reportAndReconcileGroups(
 	message *protobufs.AgentToServer,
 	agent *agentmodel.Agent,
 	currentServer *agentmodel.Server,
-) error {
+) (bool, error) {
 	hasDescription := message.GetAgentDescription() != nil
 
 	var prevIdentity identitySnapshot
@@ -632,47 +1062,370 @@ func (s *Service) reportAndReconcileGroups(
 		prevIdentity = snapshotIdentity(agent)
 	}
 
-	err := s.report(agent, message, currentServer)
+	// Reporting replaces the effective config as a whole, so the previous one stays intact.
+	prevEffectiveConfig := agent.Status.EffectiveConfig
+
+	effectiveConfig, persistEffectiveConfig := s.sampleEffectiveConfig(agent,
+		effectiveConfigToDomain(message.GetEffectiveConfig(), s.defaultConfigContentType), s.clock.Now())
+
+	err := s.report(agent, message, effectiveConfig, currentServer, s.clock.Now())
 	if err != nil {
 		logger.Error("failed to report agent", slog.String("error", err.Error()))
 
-		return err
+		return false, err
+	}
+
+	s.recordUnsavedReport(agent, message, effectiveConfig, currentServer)
+
+	if effectiveConfig != nil && !prevEffectiveConfig.Equal(&agent.Status.EffectiveConfig) {
+		// Announced once the agent is saved, so a watcher reading it sees the new config.
+		s.effectiveConfigChanges.Store(agent.Metadata.InstanceUID.String(), struct{}{})
 	}
 
 	if hasDescription {
 		s.maybeApplyMatchingAgentGroups(ctx, logger, agent, prevIdentity)
 	}
 
-	return nil
+	return persistEffectiveConfig, nil
 }
 
 // maybePersistAgent writes the agent through the throttle if the message warrants it,
-// updating the lastSaveAt anchor on success so the next throttle window is measured
-// from this arrival time.
+// heartbeatOnly as reported by isHeartbeatOnly, updating the lastSaveAt anchor on success
+// so the next throttle window is measured from this arrival time. deferred reports
+// whether the agent came from deferredSaves and so holds reports that still have to be
+// persisted.
+//
+// Within minReportInterval of the last save the agent is kept in deferredSaves instead,
+// so a flood of reports costs one write per interval and the latest state wins.
 func (s *Service) maybePersistAgent(
 	ctx context.Context,
 	logger *slog.Logger,
 	instanceUID uuid.UUID,
-	message *protobufs.AgentToServer,
+	heartbeatOnly bool,
 	agent *agentmodel.Agent,
+	deferred bool,
 	receivedAt time.Time,
 ) {
-	if !s.shouldPersistAgent(instanceUID, message) {
+	if s.withinMinReportInterval(instanceUID, receivedAt) {
+		if deferred || !heartbeatOnly {
+			s.deferredSaves.Store(instanceUID.String(), agent)
+		} else {
+			s.holdHeartbeat(instanceUID, agent)
+		}
+
 		return
 	}
 
+	if !deferred && !s.shouldPersistAgent(instanceUID, heartbeatOnly) {
+		s.holdHeartbeat(instanceUID, agent)
+
+		return
+	}
+
+	s.saveAgent(ctx, logger, instanceUID, agent, receivedAt)
+}
+
+// holdHeartbeat keeps only the heartbeat of an agent whose heartbeat-only report is not
+// saved, as the agent itself is dropped.
+func (s *Service) holdHeartbeat(instanceUID uuid.UUID, agent *agentmodel.Agent) {
+	s.unsavedHeartbeats.Store(instanceUID.String(), agent.Heartbeat())
+	s.unsavedReports.Delete(instanceUID.String())
+}
+
+// saveAgent persists the agent and, on success, anchors the throttle windows on savedAt.
+// When the agent was written elsewhere since it was loaded, the reports applied to it are
+// replayed onto the agent as now stored, and that agent is saved instead.
+func (s *Service) saveAgent(
+	ctx context.Context,
+	logger *slog.Logger,
+	instanceUID uuid.UUID,
+	agent *agentmodel.Agent,
+	savedAt time.Time,
+) {
+	reports := s.takeUnsavedReports(instanceUID)
+
 	err := s.agentUsecase.SaveAgent(ctx, agent)
+	if errors.Is(err, model.ErrConflict) && len(reports) > 0 {
+		logger.Info("agent was changed since it was loaded; replaying its unsaved reports",
+			slog.Int("reports", len(reports)))
+
+		agent, err = s.replayReports(ctx, instanceUID, reports)
+		if err == nil {
+			err = s.agentUsecase.SaveAgent(ctx, agent)
+		}
+	}
+
 	if err != nil {
 		logger.Error("failed to save agent", slog.String("error", err.Error()))
 
 		return
 	}
 
-	s.lastSaveAt.Store(instanceUID.String(), receivedAt)
+	s.lastSaveAt.Store(instanceUID.String(), savedAt)
+	s.unsavedHeartbeats.Delete(instanceUID.String())
 
+	s.publishEffectiveConfigChange(ctx, logger, instanceUID)
 	s.observeEnvironment(ctx, logger, agent)
 }
 
+// agentReport re-applies one report to an agent.
+type agentReport func(agent *agentmodel.Agent) error
+
+// recordUnsavedReport remembers how to re-apply the report just applied to agent, along
+// with the heartbeat it carried, until the agent is saved.
+func (s *Service) recordUnsavedReport(
+	agent *agentmodel.Agent,
+	message *protobufs.AgentToServer,
+	effectiveConfig *agentmodel.AgentEffectiveConfig,
+	by *agentmodel.Server,
+) {
+	heartbeat := agent.Heartbeat()
+	replay := func(target *agentmodel.Agent) error {
+		target.RestoreHeartbeat(heartbeat)
+
+		return s.report(target, message, effectiveConfig, by, heartbeat.ReportedAt)
+	}
+
+	key := agent.Metadata.InstanceUID.String()
+	previous, _ := s.unsavedReports.Load(key)
+	reports, _ := previous.([]agentReport)
+	s.unsavedReports.Store(key, append(slices.Clip(reports), replay))
+}
+
+// takeUnsavedReports removes and returns the reports applied to the agent since its last save.
+func (s *Service) takeUnsavedReports(instanceUID uuid.UUID) []agentReport {
+	value, found := s.unsavedReports.LoadAndDelete(instanceUID.String())
+	if !found {
+		return nil
+	}
+
+	reports, _ := value.([]agentReport)
+
+	return reports
+}
+
+// replayReports loads the agent as stored and re-applies reports to it in order.
+func (s *Service) replayReports(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	reports []agentReport,
+) (*agentmodel.Agent, error) {
+	agent, err := s.agentUsecase.GetAgent(ctx, instanceUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload agent: %w", err)
+	}
+
+	err = applyReports(agent, reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return agent, nil
+}
+
+// applyReports re-applies reports to agent in order.
+func applyReports(agent *agentmodel.Agent, reports []agentReport) error {
+	for _, replay := range reports {
+		err := replay(agent)
+		if err != nil {
+			return fmt.Errorf("failed to replay report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// publishEffectiveConfigChange announces the agent's effective config when a report saved
+// with it changed the config. Failures are logged: a missed change only delays watchers
+// until the next one.
+func (s *Service) publishEffectiveConfigChange(ctx context.Context, logger *slog.Logger, instanceUID uuid.UUID) {
+	_, changed := s.effectiveConfigChanges.LoadAndDelete(instanceUID.String())
+	if !changed || s.effectiveConfigChangePublisher == nil {
+		return
+	}
+
+	err := s.effectiveConfigChangePublisher.PublishAgentEffectiveConfigChange(ctx, instanceUID)
+	if err != nil {
+		logger.Warn("failed to publish effective config change", slog.String("error", err.Error()))
+	}
+}
+
+// withinMinReportInterval reports whether the agent was saved less than minReportInterval
+// before now.
+func (s *Service) withinMinReportInterval(instanceUID uuid.UUID, now time.Time) bool {
+	if s.minReportInterval <= 0 {
+		return false
+	}
+
+	last, found := s.lastSaveAt.Load(instanceUID.String())
+	if !found {
+		return false
+	}
+
+	lastAt, isTime := last.(time.Time)
+
+	return isTime && now.Sub(lastAt) < s.minReportInterval
+}
+
+// loadAgent loads or creates the agent. When its save is deferred it reports true, and
+// the reports coalesced within minReportInterval are carried over so they build on each
+// other: the deferred agent is returned as is while it still matches the stored one, and
+// otherwise its unsaved reports are re-applied to the stored agent. An API write made
+// meanwhile, such as a new remote config or a restart, is then in the response instead
+// of being hidden, and later overwritten, by the deferred copy.
+// Taking the deferred agent out of deferredSaves gives the caller sole ownership of it.
+func (s *Service) loadAgent(ctx context.Context, instanceUID uuid.UUID) (*agentmodel.Agent, bool, error) {
+	deferredAgent := s.takeDeferredSave(instanceUID)
+
+	agent, err := s.agentUsecase.GetOrCreateAgent(ctx, instanceUID)
+	if err != nil {
+		if deferredAgent != nil {
+			// The coalesced reports are still worth answering and saving; the next
+			// message loads the stored agent again.
+			return deferredAgent, true, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to get or create agent: %w", err)
+	}
+
+	if deferredAgent == nil {
+		return agent, false, nil
+	}
+
+	if deferredAgent.Metadata.ResourceVersion == agent.Metadata.ResourceVersion {
+		return deferredAgent, true, nil
+	}
+
+	// The reports stay recorded, so saveAgent can still replay them on a later conflict.
+	value, _ := s.unsavedReports.Load(instanceUID.String())
+	reports, _ := value.([]agentReport)
+
+	err = applyReports(agent, reports)
+	if err != nil {
+		s.unsavedReports.Delete(instanceUID.String())
+
+		return nil, false, err
+	}
+
+	return agent, true, nil
+}
+
+// takeDeferredSave removes and returns the agent whose save is deferred, or nil.
+func (s *Service) takeDeferredSave(instanceUID uuid.UUID) *agentmodel.Agent {
+	value, found := s.deferredSaves.LoadAndDelete(instanceUID.String())
+	if !found {
+		return nil
+	}
+
+	agent, _ := value.(*agentmodel.Agent)
+
+	return agent
+}
+
+// flushDeferredSaves persists the deferred saves whose minReportInterval has passed. An
+// agent that keeps reporting is saved by its own next message instead; this catches the
+// agents that went quiet right after a coalesced report.
+func (s *Service) flushDeferredSaves(ctx context.Context) {
+	now := s.clock.Now()
+
+	s.deferredSaves.Range(func(key, _ any) bool {
+		keyString, _ := key.(string)
+
+		instanceUID, err := uuid.Parse(keyString)
+		if err != nil || s.withinMinReportInterval(instanceUID, now) {
+			return true
+		}
+
+		agent := s.takeDeferredSave(instanceUID)
+		if agent == nil {
+			return true
+		}
+
+		saveCtx, cancel := context.WithTimeout(ctx, s.onConnectionCloseTimeout)
+		s.saveAgent(saveCtx, s.logger.With(
+			slog.String("method", "flushDeferredSaves"),
+			slog.String("instanceUID", instanceUID.String()),
+		), instanceUID, agent, now)
+		cancel()
+
+		return true
+	})
+}
+
+// takeUnsavedHeartbeat removes and returns the agent's heartbeat held back by the throttle.
+func (s *Service) takeUnsavedHeartbeat(instanceUID uuid.UUID) (agentmodel.AgentHeartbeat, bool) {
+	value, found := s.unsavedHeartbeats.LoadAndDelete(instanceUID.String())
+	if !found {
+		return agentmodel.AgentHeartbeat{}, false
+	}
+
+	heartbeat, isHeartbeat := value.(agentmodel.AgentHeartbeat)
+
+	return heartbeat, isHeartbeat
+}
+
+// persistUnsavedState writes the agent state only this server holds before it shuts down:
+// the deferred saves, whatever their minReportInterval, and the heartbeats held back by the
+// throttle. Without it, a restart would drop the agents' last reports, leaving their
+// LastReportedAt and connection state as of their last save. ctx is already done, so the
+// writes get their own deadline.
+func (s *Service) persistUnsavedState(ctx context.Context) {
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.onConnectionCloseTimeout)
+	defer cancel()
+
+	logger := s.logger.With(slog.String("method", "persistUnsavedState"))
+	now := s.clock.Now()
+
+	s.deferredSaves.Range(func(key, _ any) bool {
+		keyString, _ := key.(string)
+
+		instanceUID, err := uuid.Parse(keyString)
+		if err != nil {
+			return true
+		}
+
+		if agent := s.takeDeferredSave(instanceUID); agent != nil {
+			s.saveAgent(saveCtx, logger.With(slog.String("instanceUID", keyString)), instanceUID, agent, now)
+		}
+
+		return true
+	})
+
+	s.unsavedHeartbeats.Range(func(key, _ any) bool {
+		keyString, _ := key.(string)
+
+		instanceUID, err := uuid.Parse(keyString)
+		if err != nil {
+			return true
+		}
+
+		heartbeat, throttled := s.takeUnsavedHeartbeat(instanceUID)
+		if !throttled {
+			return true
+		}
+
+		agentLogger := logger.With(slog.String("instanceUID", keyString))
+
+		agent, err := s.agentUsecase.GetAgent(saveCtx, instanceUID)
+		if err != nil {
+			agentLogger.Error("failed to get agent to persist its last heartbeat", slog.String("error", err.Error()))
+
+			return true
+		}
+
+		if !agent.RestoreHeartbeat(heartbeat) {
+			return true
+		}
+
+		err = s.agentUsecase.SaveAgent(saveCtx, agent)
+		if err != nil {
+			agentLogger.Error("failed to persist the agent's last heartbeat", slog.String("error", err.Error()))
+		}
+
+		return true
+	})
+}
+
 // observeEnvironment discovers and upserts the host/container the agent runs in
 // from its reported description. It rides the same throttle as agent persistence
 // so the discovery inventory advances at the agent-save cadence rather than on
@@ -2,6 +2,8 @@
 package opamp
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"testing"
 	"time"
@@ -9,7 +11,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
@@ -17,9 +24,10 @@ func TestIsHeartbeatOnly(t *testing.T) {
 	t.Parallel()
 
 	cases := []struct {
-		name string
-		msg  *protobufs.AgentToServer
-		want bool
+		name                   string
+		msg                    *protobufs.AgentToServer
+		persistEffectiveConfig bool
+		want                   bool
 	}{
 		{
 			name: "nil message",
@@ -57,7 +65,22 @@ func TestIsHeartbeatOnly(t *testing.T) {
 			msg: &protobufs.AgentToServer{
 				EffectiveConfig: &protobufs.EffectiveConfig{},
 			},
-			want: false,
+			persistEffectiveConfig: true,
+			want:                   false,
+		},
+		{
+			name: "effective config not worth persisting is a heartbeat",
+			msg: &protobufs.AgentToServer{
+				EffectiveConfig: &protobufs.EffectiveConfig{},
+			},
+			persistEffectiveConfig: false,
+			want:                   true,
+		},
+		{
+			name:                   "taking a held-back effective config is not a heartbeat",
+			msg:                    &protobufs.AgentToServer{},
+			persistEffectiveConfig: true,
+			want:                   false,
 		},
 		{
 			name: "remote config status present is not a heartbeat",
@@ -107,7 +130,7 @@ func TestIsHeartbeatOnly(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			assert.Equal(t, tc.want, isHeartbeatOnly(tc.msg))
+			assert.Equal(t, tc.want, isHeartbeatOnly(tc.msg, tc.persistEffectiveConfig))
 		})
 	}
 }
@@ -129,10 +152,10 @@ func TestShouldPersistAgent(t *testing.T) {
 	now := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
 	throttle := 60 * time.Second
 
-	heartbeat := &protobufs.AgentToServer{}
-	nonHeartbeat := &protobufs.AgentToServer{
+	heartbeat := isHeartbeatOnly(&protobufs.AgentToServer{}, false)
+	nonHeartbeat := isHeartbeatOnly(&protobufs.AgentToServer{
 		Health: &protobufs.ComponentHealth{Healthy: true},
-	}
+	}, false)
 
 	t.Run("non-heartbeat always persists regardless of lastSaveAt", func(t *testing.T) {
 		t.Parallel()
@@ -183,6 +206,440 @@ func TestShouldPersistAgent(t *testing.T) {
 	})
 }
 
+// countingAgentUsecase counts agent writes and keeps the last agent written.
+type countingAgentUsecase struct {
+	agentport.AgentUsecase
+
+	saves int
+	saved *agentmodel.Agent
+}
+
+func (c *countingAgentUsecase) GetOrCreateAgent(_ context.Context, instanceUID uuid.UUID) (*agentmodel.Agent, error) {
+	if c.saved != nil {
+		return c.saved.Clone(), nil
+	}
+
+	return agentmodel.NewAgent(instanceUID), nil
+}
+
+func (c *countingAgentUsecase) SaveAgent(_ context.Context, agent *agentmodel.Agent) error {
+	c.saves++
+	c.saved = agent
+
+	return nil
+}
+
+// noopObserver satisfies the host and container usecases the save path observes.
+type noopObserver struct {
+	agentport.HostUsecase
+}
+
+func (noopObserver) ObserveAgent(context.Context, *agentmodel.Agent) error { return nil }
+
+type noopContainerObserver struct {
+	agentport.ContainerUsecase
+}
+
+func (noopContainerObserver) ObserveAgent(context.Context, *agentmodel.Agent) error { return nil }
+
+func TestMaybePersistAgent_CoalescesRapidReports(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+	agentUC := &countingAgentUsecase{}
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   slog.New(slog.DiscardHandler),
+		agentUsecase:             agentUC,
+		hostUsecase:              noopObserver{},
+		containerUsecase:         noopContainerObserver{},
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		heartbeatSaveThrottle:    DefaultHeartbeatSaveThrottle,
+		minReportInterval:        time.Second,
+	}
+	instanceUID := uuid.New()
+	status := &protobufs.AgentToServer{Health: &protobufs.ComponentHealth{Healthy: true}}
+
+	// A misconfigured collector reports every 100ms for two seconds.
+	for sequenceNum := range uint64(20) {
+		testClock.now = start.Add(time.Duration(sequenceNum) * 100 * time.Millisecond) //nolint:gosec // small
+
+		agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+
+		agent.RecordLastReported(nil, testClock.now, sequenceNum)
+		svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(status, false),
+			agent, deferred, testClock.now)
+	}
+
+	assert.Equal(t, 2, agentUC.saves, "reports within the interval share a write")
+	assert.Equal(t, uint64(10), agentUC.saved.Status.SequenceNum)
+
+	// The collector goes quiet; the deferred latest report is flushed once the interval passes.
+	testClock.now = start.Add(2500 * time.Millisecond)
+	svc.flushDeferredSaves(t.Context())
+
+	assert.Equal(t, 3, agentUC.saves)
+	assert.Equal(t, uint64(19), agentUC.saved.Status.SequenceNum, "the latest report wins")
+
+	svc.flushDeferredSaves(t.Context())
+	assert.Equal(t, 3, agentUC.saves, "nothing is left to flush")
+}
+
+func TestSaveAgent_ReplaysDeferredReportsOverAnAPIWrite(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+	logger := slog.New(slog.DiscardHandler)
+	agentUC := agentservice.NewAgentService(inmemory.NewAgentRepository(), logger,
+		agentservice.DefaultAgentCacheConfig(), "")
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   logger,
+		agentUsecase:             agentUC,
+		hostUsecase:              noopObserver{},
+		containerUsecase:         noopContainerObserver{},
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		heartbeatSaveThrottle:    DefaultHeartbeatSaveThrottle,
+		minReportInterval:        time.Second,
+	}
+	instanceUID := uuid.New()
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+
+	report := func(sequenceNum uint64, healthy bool) {
+		message := &protobufs.AgentToServer{
+			SequenceNum: sequenceNum,
+			Health:      &protobufs.ComponentHealth{Healthy: healthy},
+		}
+
+		agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+
+		agent.UpdateLastCommunicationInfo(testClock.now, connection)
+
+		persistEffectiveConfig, err := svc.reportAndReconcileGroups(t.Context(), svc.logger, message, agent, nil)
+		require.NoError(t, err)
+
+		svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(message, persistEffectiveConfig),
+			agent, deferred, testClock.now)
+	}
+
+	report(1, true)
+
+	// The agent turns unhealthy within the interval, so the report is deferred.
+	testClock.now = start.Add(100 * time.Millisecond)
+	report(2, false)
+
+	// Meanwhile the API writes the agent.
+	stored, err := agentUC.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	stored.SetLabels(map[string]string{"team": "observability"})
+	require.NoError(t, agentUC.SaveAgent(t.Context(), stored))
+
+	testClock.now = start.Add(2 * time.Second)
+	svc.flushDeferredSaves(t.Context())
+
+	saved, err := agentUC.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "observability"}, saved.Metadata.Labels, "the API write is kept")
+	assert.False(t, saved.Status.ComponentHealth.Healthy, "the deferred report is not lost")
+	assert.Equal(t, uint64(2), saved.Status.SequenceNum)
+	assert.Equal(t, start.Add(100*time.Millisecond), saved.Status.LastReportedAt)
+}
+
+func TestLoadAgent_DeferredReportsBuildOnAnAPIWrite(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+	logger := slog.New(slog.DiscardHandler)
+	agentUC := agentservice.NewAgentService(inmemory.NewAgentRepository(), logger,
+		agentservice.DefaultAgentCacheConfig(), "")
+	svc := &Service{
+		clock:                    testClock,
+		logger:                   logger,
+		agentUsecase:             agentUC,
+		hostUsecase:              noopObserver{},
+		containerUsecase:         noopContainerObserver{},
+		serverToAgentBuilder:     agentservice.NewServerToAgentBuilder(nil, logger),
+		onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+		heartbeatSaveThrottle:    DefaultHeartbeatSaveThrottle,
+		minReportInterval:        time.Second,
+	}
+	instanceUID := uuid.New()
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+
+	report := func(sequenceNum uint64, healthy bool) *agentmodel.Agent {
+		message := &protobufs.AgentToServer{
+			SequenceNum:  sequenceNum,
+			Capabilities: uint64(protobufs.AgentCapabilities_AgentCapabilities_AcceptsRestartCommand),
+			Health:       &protobufs.ComponentHealth{Healthy: healthy},
+		}
+
+		agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+
+		agent.UpdateLastCommunicationInfo(testClock.now, connection)
+
+		persistEffectiveConfig, err := svc.reportAndReconcileGroups(t.Context(), svc.logger, message, agent, nil)
+		require.NoError(t, err)
+
+		svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(message, persistEffectiveConfig),
+			agent, deferred, testClock.now)
+
+		return agent
+	}
+
+	report(1, true)
+
+	// The agent turns unhealthy within the interval, so the report is deferred.
+	testClock.now = start.Add(100 * time.Millisecond)
+	report(2, false)
+
+	// Meanwhile the API asks for a restart.
+	stored, err := agentUC.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	require.NoError(t, stored.SetRestartRequired(start.Add(150*time.Millisecond)))
+	require.NoError(t, agentUC.SaveAgent(t.Context(), stored))
+
+	// The next report is still within the interval.
+	testClock.now = start.Add(200 * time.Millisecond)
+	agent := report(3, false)
+
+	assert.True(t, agent.ShouldBeRestarted(), "the API write is not hidden by the deferred agent")
+	assert.False(t, agent.Status.ComponentHealth.Healthy, "the deferred report is kept")
+	assert.Equal(t, uint64(3), agent.Status.SequenceNum)
+
+	response := svc.fetchServerToAgent(t.Context(), agent)
+	require.NotNil(t, response.GetCommand())
+	assert.Equal(t, protobufs.CommandType_CommandType_Restart, response.GetCommand().GetType())
+
+	testClock.now = start.Add(2 * time.Second)
+	svc.flushDeferredSaves(t.Context())
+
+	saved, err := agentUC.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	assert.True(t, saved.ShouldBeRestarted(), "the restart is not overwritten")
+	assert.Equal(t, uint64(3), saved.Status.SequenceNum)
+}
+
+func TestMaybePersistAgent_PersistsEveryReportWithoutMinInterval(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+	agentUC := &countingAgentUsecase{}
+	svc := &Service{
+		clock:                 testClock,
+		logger:                slog.New(slog.DiscardHandler),
+		agentUsecase:          agentUC,
+		hostUsecase:           noopObserver{},
+		containerUsecase:      noopContainerObserver{},
+		heartbeatSaveThrottle: DefaultHeartbeatSaveThrottle,
+	}
+	instanceUID := uuid.New()
+	status := &protobufs.AgentToServer{Health: &protobufs.ComponentHealth{Healthy: true}}
+
+	for sequenceNum := range uint64(5) {
+		testClock.now = start.Add(time.Duration(sequenceNum) * 100 * time.Millisecond) //nolint:gosec // small
+
+		agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+		assert.False(t, deferred)
+
+		svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(status, false),
+			agent, deferred, testClock.now)
+	}
+
+	assert.Equal(t, 5, agentUC.saves)
+}
+
+// recordingEffectiveConfigChangePublisher records the agents announced as changed.
+type recordingEffectiveConfigChangePublisher struct {
+	published []uuid.UUID
+}
+
+func (p *recordingEffectiveConfigChangePublisher) PublishAgentEffectiveConfigChange(
+	_ context.Context,
+	instanceUID uuid.UUID,
+) error {
+	p.published = append(p.published, instanceUID)
+
+	return nil
+}
+
+func TestSaveAgent_PublishesChangedEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	testClock := &persistTestClock{now: time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)}
+	publisher := &recordingEffectiveConfigChangePublisher{}
+	svc := &Service{
+		clock:                          testClock,
+		logger:                         slog.New(slog.DiscardHandler),
+		agentUsecase:                   &countingAgentUsecase{},
+		hostUsecase:                    noopObserver{},
+		containerUsecase:               noopContainerObserver{},
+		heartbeatSaveThrottle:          DefaultHeartbeatSaveThrottle,
+		effectiveConfigChangePublisher: publisher,
+	}
+	instanceUID := uuid.New()
+	agent := agentmodel.NewAgent(instanceUID)
+	reportConfig := func(body string) {
+		message := &protobufs.AgentToServer{
+			EffectiveConfig: &protobufs.EffectiveConfig{
+				ConfigMap: &protobufs.AgentConfigMap{
+					ConfigMap: map[string]*protobufs.AgentConfigFile{
+						"collector.yaml": {Body: []byte(body), ContentType: "text/yaml"},
+					},
+				},
+			},
+		}
+
+		_, err := svc.reportAndReconcileGroups(t.Context(), svc.logger, message, agent, nil)
+		require.NoError(t, err)
+
+		svc.saveAgent(t.Context(), svc.logger, instanceUID, agent, testClock.now)
+	}
+
+	reportConfig("a: 1")
+	reportConfig("a: 1")
+	reportConfig("a: 2")
+
+	assert.Equal(t, []uuid.UUID{instanceUID, instanceUID}, publisher.published,
+		"only reports changing the effective config are announced")
+}
+
+func TestEffectiveConfigPersistence(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+
+	newService := func(persistence EffectiveConfigPersistence) (*Service, *persistTestClock, *countingAgentUsecase) {
+		testClock := &persistTestClock{now: start}
+		agentUC := &countingAgentUsecase{}
+
+		return &Service{
+			clock:                      testClock,
+			logger:                     slog.New(slog.DiscardHandler),
+			agentUsecase:               agentUC,
+			hostUsecase:                noopObserver{},
+			containerUsecase:           noopContainerObserver{},
+			heartbeatSaveThrottle:      time.Hour,
+			effectiveConfigPersistence: persistence,
+		}, testClock, agentUC
+	}
+
+	// receive handles a message the way OnMessage does, without the connection plumbing.
+	receive := func(t *testing.T, svc *Service, instanceUID uuid.UUID, message *protobufs.AgentToServer) {
+		t.Helper()
+
+		agent, deferred, err := svc.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+
+		persistEffectiveConfig, err := svc.reportAndReconcileGroups(t.Context(), svc.logger, message, agent, nil)
+		require.NoError(t, err)
+
+		svc.maybePersistAgent(t.Context(), svc.logger, instanceUID, isHeartbeatOnly(message, persistEffectiveConfig),
+			agent, deferred, svc.clock.Now())
+	}
+
+	configMessage := func(body string) *protobufs.AgentToServer {
+		return &protobufs.AgentToServer{
+			EffectiveConfig: &protobufs.EffectiveConfig{
+				ConfigMap: &protobufs.AgentConfigMap{
+					ConfigMap: map[string]*protobufs.AgentConfigFile{
+						"collector.yaml": {Body: []byte(body), ContentType: "text/yaml"},
+					},
+				},
+			},
+		}
+	}
+
+	storedConfig := func(agentUC *countingAgentUsecase) string {
+		return string(agentUC.saved.Status.EffectiveConfig.ConfigMap.ConfigMap["collector.yaml"].Body)
+	}
+
+	t.Run("identical configs are persisted once", func(t *testing.T) {
+		t.Parallel()
+
+		svc, testClock, agentUC := newService(EffectiveConfigPersistence{OnChangeOnly: true, SampleInterval: 0})
+		instanceUID := uuid.New()
+
+		for i := range 5 {
+			testClock.now = start.Add(time.Duration(i) * time.Second)
+			receive(t, svc, instanceUID, configMessage("a: 1"))
+		}
+
+		assert.Equal(t, 1, agentUC.saves, "only the first, changed config costs a write")
+		assert.Equal(t, "a: 1", storedConfig(agentUC))
+	})
+
+	t.Run("identical configs are persisted every time by default", func(t *testing.T) {
+		t.Parallel()
+
+		svc, testClock, agentUC := newService(EffectiveConfigPersistence{OnChangeOnly: false, SampleInterval: 0})
+		instanceUID := uuid.New()
+
+		for i := range 5 {
+			testClock.now = start.Add(time.Duration(i) * time.Second)
+			receive(t, svc, instanceUID, configMessage("a: 1"))
+		}
+
+		assert.Equal(t, 5, agentUC.saves)
+	})
+
+	t.Run("changed configs are sampled once per interval", func(t *testing.T) {
+		t.Parallel()
+
+		svc, testClock, agentUC := newService(EffectiveConfigPersistence{OnChangeOnly: true, SampleInterval: time.Minute})
+		instanceUID := uuid.New()
+
+		receive(t, svc, instanceUID, configMessage("a: 0"))
+
+		for i := 1; i <= 5; i++ {
+			testClock.now = start.Add(time.Duration(i) * time.Second)
+			receive(t, svc, instanceUID, configMessage(fmt.Sprintf("a: %d", i)))
+		}
+
+		assert.Equal(t, 1, agentUC.saves, "changes within the interval are held back")
+		assert.Equal(t, "a: 0", storedConfig(agentUC))
+
+		// Other reports are persisted as usual, without the held-back config.
+		receive(t, svc, instanceUID, &protobufs.AgentToServer{Health: &protobufs.ComponentHealth{Healthy: true}})
+		assert.Equal(t, 2, agentUC.saves)
+		assert.Equal(t, "a: 0", storedConfig(agentUC))
+
+		// The first message after the interval, even a heartbeat, takes the latest config.
+		testClock.now = start.Add(time.Minute)
+		receive(t, svc, instanceUID, &protobufs.AgentToServer{})
+		assert.Equal(t, 3, agentUC.saves)
+		assert.Equal(t, "a: 5", storedConfig(agentUC))
+	})
+
+	t.Run("reverting to the stored config drops the held-back one", func(t *testing.T) {
+		t.Parallel()
+
+		svc, testClock, agentUC := newService(EffectiveConfigPersistence{OnChangeOnly: true, SampleInterval: time.Minute})
+		instanceUID := uuid.New()
+
+		receive(t, svc, instanceUID, configMessage("a: 0"))
+
+		testClock.now = start.Add(time.Second)
+		receive(t, svc, instanceUID, configMessage("a: 1"))
+
+		testClock.now = start.Add(2 * time.Second)
+		receive(t, svc, instanceUID, configMessage("a: 0"))
+
+		testClock.now = start.Add(time.Minute)
+		receive(t, svc, instanceUID, &protobufs.AgentToServer{})
+
+		assert.Equal(t, 1, agentUC.saves)
+		assert.Equal(t, "a: 0", storedConfig(agentUC))
+	})
+}
+
 // persistTestClock is a fixed clock for the persistence-throttle tests.
 // We reuse the existing test clock pattern from server_test.go but keep this
 // file self-contained.
@@ -2,6 +2,8 @@ package opamp
 
 import (
 	"encoding/base64"
+	"maps"
+	"slices"
 	"strconv"
 	"time"
 
@@ -12,15 +14,34 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/timeutil"
 )
 
-func descToDomain(desc *protobufs.AgentDescription) *modelagent.Description {
+// descToDomain converts the reported description, renaming aliased attribute keys to
+// their canonical form so selectors and search see one key per concept. Attributes
+// beyond limits are dropped first, so the raw copy kept by aliasing is bounded too.
+// It also returns how many denylisted attributes were stripped.
+func descToDomain(
+	desc *protobufs.AgentDescription,
+	denylist modelagent.AttributeDenylist,
+	aliases modelagent.AttributeAliases,
+	limits modelagent.AttributeLimits,
+) (*modelagent.Description, int) {
 	if desc == nil {
-		return nil
+		return nil, 0
 	}
 
-	return &modelagent.Description{
-		IdentifyingAttributes:    toMap(desc.GetIdentifyingAttributes()),
-		NonIdentifyingAttributes: toMap(desc.GetNonIdentifyingAttributes()),
+	description := &modelagent.Description{
+		IdentifyingAttributes:       toMap(desc.GetIdentifyingAttributes()),
+		NonIdentifyingAttributes:    toMap(desc.GetNonIdentifyingAttributes()),
+		RawIdentifyingAttributes:    nil,
+		RawNonIdentifyingAttributes: nil,
+		AttributesTruncated:         false,
 	}
+	// Denylisted keys go before anything else, so they never reach the raw copies kept
+	// by aliasing and do not count against the limits.
+	stripped := denylist.StripDescription(description)
+	limits.LimitDescription(description)
+	aliases.NormalizeDescription(description)
+
+	return description, stripped
 }
 
 // remoteConfigStatusToDomain converts the agent's reported remote-config status. lastUpdatedAt
@@ -103,16 +124,31 @@ func anyValueToString(value *protobufs.AnyValue) string {
 	}
 }
 
+// maxComponentDepth bounds how deep nested component health is converted. It is recursive
+// in the protocol, so without a bound a crafted message would drive the conversion (and
+// the stored document) as deep as the sender likes. Real collectors nest a handful of
+// levels; anything below the bound is dropped. Available components, recursive as well,
+// are bounded by the configurable AvailableComponentsLimits instead.
+const maxComponentDepth = 16
+
 func healthToDomain(health *protobufs.ComponentHealth) *agentmodel.AgentComponentHealth {
+	return healthToDomainAtDepth(health, 1)
+}
+
+func healthToDomainAtDepth(health *protobufs.ComponentHealth, depth int) *agentmodel.AgentComponentHealth {
 	if health == nil {
 		return nil
 	}
 
-	componentHealthMap := make(map[string]agentmodel.AgentComponentHealth, len(health.GetComponentHealthMap()))
+	var componentHealthMap map[string]agentmodel.AgentComponentHealth
+
+	if depth < maxComponentDepth {
+		componentHealthMap = make(map[string]agentmodel.AgentComponentHealth, len(health.GetComponentHealthMap()))
 
-	for subComponentName, subComponentHealth := range health.GetComponentHealthMap() {
-		if converted := healthToDomain(subComponentHealth); converted != nil {
-			componentHealthMap[subComponentName] = *converted
+		for subComponentName, subComponentHealth := range health.GetComponentHealthMap() {
+			if converted := healthToDomainAtDepth(subComponentHealth, depth+1); converted != nil {
+				componentHealthMap[subComponentName] = *converted
+			}
 		}
 	}
 
@@ -126,16 +162,26 @@ func healthToDomain(health *protobufs.ComponentHealth) *agentmodel.AgentComponen
 	}
 }
 
-func effectiveConfigToDomain(effectiveConfig *protobufs.EffectiveConfig) *agentmodel.AgentEffectiveConfig {
+// effectiveConfigToDomain converts the reported effective config. Files reported without
+// a content type, as older collectors do, are recorded as defaultContentType.
+func effectiveConfigToDomain(
+	effectiveConfig *protobufs.EffectiveConfig,
+	defaultContentType string,
+) *agentmodel.AgentEffectiveConfig {
 	if effectiveConfig == nil {
 		return nil
 	}
 
 	configMap := make(map[string]agentmodel.AgentConfigFile, len(effectiveConfig.GetConfigMap().GetConfigMap()))
 	for key, value := range effectiveConfig.GetConfigMap().GetConfigMap() {
+		contentType := value.GetContentType()
+		if contentType == "" {
+			contentType = defaultContentType
+		}
+
 		configMap[key] = agentmodel.AgentConfigFile{
 			Body:        value.GetBody(),
-			ContentType: value.GetContentType(),
+			ContentType: contentType,
 		}
 	}
 
@@ -170,34 +216,88 @@ func packageStatusToDomain(packageStatuses *protobufs.PackageStatuses) *agentmod
 	}
 }
 
+// availableComponentsToDomain converts the reported available components, keeping only the
+// part of the tree within limits. The result is flagged Truncated when components are dropped.
 func availableComponentsToDomain(
 	availableComponents *protobufs.AvailableComponents,
+	limits agentmodel.AvailableComponentsLimits,
 ) *agentmodel.AgentAvailableComponents {
 	if availableComponents == nil {
 		return nil
 	}
 
-	components := make(map[string]agentmodel.ComponentDetails, len(availableComponents.GetComponents()))
-	for key, value := range availableComponents.GetComponents() {
-		components[key] = componentDetailsToDomain(value)
-	}
+	kept, truncated := keptComponents(availableComponents.GetComponents(), limits)
 
 	return &agentmodel.AgentAvailableComponents{
-		Components: components,
+		Components: componentDetailsToDomain(availableComponents.GetComponents(), kept),
 		Hash:       availableComponents.GetHash(),
+		Truncated:  truncated,
+	}
+}
+
+// keptComponents walks the components tree breadth first, in key order within a level, and
+// returns the components within limits and whether any were dropped. Walking level by level
+// keeps the shallow components, which identify what the agent runs, ahead of deep ones.
+func keptComponents(
+	components map[string]*protobufs.ComponentDetails,
+	limits agentmodel.AvailableComponentsLimits,
+) (map[*protobufs.ComponentDetails]struct{}, bool) {
+	kept := make(map[*protobufs.ComponentDetails]struct{})
+	level := sortedComponents(components)
+
+	for depth := 1; len(level) > 0; depth++ {
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return kept, true
+		}
+
+		var next []*protobufs.ComponentDetails
+
+		for _, component := range level {
+			if limits.MaxNodes > 0 && len(kept) >= limits.MaxNodes {
+				return kept, true
+			}
+
+			kept[component] = struct{}{}
+			next = append(next, sortedComponents(component.GetSubComponentMap())...)
+		}
+
+		level = next
 	}
+
+	return kept, false
 }
 
-func componentDetailsToDomain(componentDetails *protobufs.ComponentDetails) agentmodel.ComponentDetails {
-	metadata := toMap(componentDetails.GetMetadata())
+// sortedComponents returns the non-nil components of a level ordered by their key.
+func sortedComponents(components map[string]*protobufs.ComponentDetails) []*protobufs.ComponentDetails {
+	sorted := make([]*protobufs.ComponentDetails, 0, len(components))
 
-	subComponentMap := make(map[string]agentmodel.ComponentDetails, len(componentDetails.GetSubComponentMap()))
-	for key, value := range componentDetails.GetSubComponentMap() {
-		subComponentMap[key] = componentDetailsToDomain(value)
+	for _, key := range slices.Sorted(maps.Keys(components)) {
+		if component := components[key]; component != nil {
+			sorted = append(sorted, component)
+		}
 	}
 
-	return agentmodel.ComponentDetails{
-		Metadata:        metadata,
-		SubComponentMap: subComponentMap,
+	return sorted
+}
+
+// componentDetailsToDomain converts the kept components of a level and, recursively,
+// their kept sub-components.
+func componentDetailsToDomain(
+	components map[string]*protobufs.ComponentDetails,
+	kept map[*protobufs.ComponentDetails]struct{},
+) map[string]agentmodel.ComponentDetails {
+	converted := make(map[string]agentmodel.ComponentDetails, len(components))
+
+	for key, component := range components {
+		if _, ok := kept[component]; !ok {
+			continue
+		}
+
+		converted[key] = agentmodel.ComponentDetails{
+			Metadata:        toMap(component.GetMetadata()),
+			SubComponentMap: componentDetailsToDomain(component.GetSubComponentMap(), kept),
+		}
 	}
+
+	return converted
 }
@@ -2,19 +2,26 @@
 package opamp
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	"github.com/minuk-dev/opampcommander/pkg/timeutil"
 )
 
 func TestDescToDomain_Nil(t *testing.T) {
 	t.Parallel()
-	assert.Nil(t, descToDomain(nil))
+
+	got, stripped := descToDomain(nil, nil, nil, modelagent.AttributeLimits{})
+	assert.Nil(t, got)
+	assert.Zero(t, stripped)
 }
 
 // TestAnyValueToString_NestedAndUnknown covers the fallback branches: array/kvlist values fall
@@ -132,7 +139,7 @@ func TestEffectiveConfigToDomain(t *testing.T) {
 
 	t.Run("nil returns nil", func(t *testing.T) {
 		t.Parallel()
-		assert.Nil(t, effectiveConfigToDomain(nil))
+		assert.Nil(t, effectiveConfigToDomain(nil, helper.TextYAML))
 	})
 
 	t.Run("maps config files", func(t *testing.T) {
@@ -147,7 +154,7 @@ func TestEffectiveConfigToDomain(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, helper.TextYAML)
 
 		require.NotNil(t, got)
 		require.Contains(t, got.ConfigMap.ConfigMap, "otel.yaml")
@@ -155,6 +162,21 @@ func TestEffectiveConfigToDomain(t *testing.T) {
 		assert.Equal(t, []byte("receivers: {}"), file.Body)
 		assert.Equal(t, "application/yaml", file.ContentType)
 	})
+
+	t.Run("records a missing content type as the configured default", func(t *testing.T) {
+		t.Parallel()
+
+		got := effectiveConfigToDomain(&protobufs.EffectiveConfig{
+			ConfigMap: &protobufs.AgentConfigMap{
+				ConfigMap: map[string]*protobufs.AgentConfigFile{
+					"otel.json": {Body: []byte(`{"receivers": {}}`)},
+				},
+			},
+		}, helper.TextJSON)
+
+		require.NotNil(t, got)
+		assert.Equal(t, helper.TextJSON, got.ConfigMap.ConfigMap["otel.json"].ContentType)
+	})
 }
 
 func TestPackageStatusToDomain(t *testing.T) {
@@ -201,7 +223,7 @@ func TestAvailableComponentsToDomain(t *testing.T) {
 
 	t.Run("nil returns nil", func(t *testing.T) {
 		t.Parallel()
-		assert.Nil(t, availableComponentsToDomain(nil))
+		assert.Nil(t, availableComponentsToDomain(nil, agentmodel.DefaultAvailableComponentsLimits()))
 	})
 
 	t.Run("maps components, metadata and nested sub-components", func(t *testing.T) {
@@ -223,10 +245,11 @@ func TestAvailableComponentsToDomain(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, agentmodel.DefaultAvailableComponentsLimits())
 
 		require.NotNil(t, got)
 		assert.Equal(t, []byte{0xFF}, got.Hash)
+		assert.False(t, got.Truncated)
 
 		require.Contains(t, got.Components, "receivers")
 		receivers := got.Components["receivers"]
@@ -235,4 +258,51 @@ func TestAvailableComponentsToDomain(t *testing.T) {
 		require.Contains(t, receivers.SubComponentMap, "otlp")
 		assert.Equal(t, "stable", receivers.SubComponentMap["otlp"].Metadata["stability"])
 	})
+
+	t.Run("truncates a deeply nested tree at the configured depth", func(t *testing.T) {
+		t.Parallel()
+
+		const maxDepth = 3
+
+		got := availableComponentsToDomain(&protobufs.AvailableComponents{
+			Components: map[string]*protobufs.ComponentDetails{"root": nestedComponentDetails(100)},
+		}, agentmodel.AvailableComponentsLimits{MaxDepth: maxDepth, MaxNodes: 0})
+
+		require.NotNil(t, got)
+		assert.True(t, got.Truncated)
+		assert.Equal(t, maxDepth, componentDetailsDepth(got.Components["root"]))
+	})
+
+	t.Run("a tree exactly at the depth limit is not truncated", func(t *testing.T) {
+		t.Parallel()
+
+		got := availableComponentsToDomain(&protobufs.AvailableComponents{
+			Components: map[string]*protobufs.ComponentDetails{"root": nestedComponentDetails(3)},
+		}, agentmodel.AvailableComponentsLimits{MaxDepth: 3, MaxNodes: 3})
+
+		require.NotNil(t, got)
+		assert.False(t, got.Truncated)
+		assert.Equal(t, 3, componentDetailsDepth(got.Components["root"]))
+	})
+
+	t.Run("keeps shallow components first when over the node limit", func(t *testing.T) {
+		t.Parallel()
+
+		got := availableComponentsToDomain(&protobufs.AvailableComponents{
+			Components: map[string]*protobufs.ComponentDetails{
+				"exporters":  {},
+				"processors": {},
+				"receivers": {SubComponentMap: map[string]*protobufs.ComponentDetails{
+					"otlp":       {},
+					"prometheus": {},
+				}},
+			},
+		}, agentmodel.AvailableComponentsLimits{MaxDepth: 0, MaxNodes: 4})
+
+		require.NotNil(t, got)
+		assert.True(t, got.Truncated)
+		assert.Len(t, got.Components, 3, "every top-level component fits before any sub-component")
+		assert.Equal(t, []string{"otlp"}, slices.Collect(maps.Keys(got.Components["receivers"].SubComponentMap)),
+			"sub-components are kept in key order")
+	})
 }
@@ -0,0 +1,155 @@
+//nolint:testpackage // white-box test of the unexported protobuf->domain converters
+package opamp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestComponentConversion_BoundsDepth(t *testing.T) {
+	t.Parallel()
+
+	const reportedDepth = maxComponentDepth * 4
+
+	health := nestedHealth(reportedDepth)
+	details := nestedComponentDetails(reportedDepth)
+
+	assert.Equal(t, maxComponentDepth, healthDepth(healthToDomain(health)))
+
+	components := availableComponentsToDomain(&protobufs.AvailableComponents{
+		Components: map[string]*protobufs.ComponentDetails{"root": details},
+	}, agentmodel.DefaultAvailableComponentsLimits())
+	require.NotNil(t, components)
+	assert.True(t, components.Truncated)
+	assert.Equal(t, agentmodel.DefaultAvailableComponentsMaxDepth, componentDetailsDepth(components.Components["root"]))
+}
+
+func TestComponentConversion_NilEntries(t *testing.T) {
+	t.Parallel()
+
+	health := healthToDomain(&protobufs.ComponentHealth{
+		ComponentHealthMap: map[string]*protobufs.ComponentHealth{"missing": nil},
+	})
+	require.NotNil(t, health)
+	assert.NotContains(t, health.ComponentHealthMap, "missing")
+
+	components := availableComponentsToDomain(&protobufs.AvailableComponents{
+		Components: map[string]*protobufs.ComponentDetails{
+			"missing": nil,
+			"partial": {
+				Metadata:        []*protobufs.KeyValue{nil, {Key: "k"}},
+				SubComponentMap: map[string]*protobufs.ComponentDetails{"missing": nil},
+			},
+		},
+	}, agentmodel.DefaultAvailableComponentsLimits())
+	require.NotNil(t, components)
+	assert.Equal(t, "", components.Components["partial"].Metadata["k"])
+}
+
+// FuzzProtobufsToDomain feeds arbitrary AgentToServer encodings through the converters,
+// which must neither panic nor produce component trees deeper than their depth limits.
+func FuzzProtobufsToDomain(f *testing.F) {
+	for _, seed := range []*protobufs.AgentToServer{
+		{},
+		{
+			Health: nestedHealth(maxComponentDepth * 2),
+			AvailableComponents: &protobufs.AvailableComponents{
+				Components: map[string]*protobufs.ComponentDetails{
+					"root": nestedComponentDetails(maxComponentDepth * 2),
+				},
+			},
+		},
+		{
+			AgentDescription: &protobufs.AgentDescription{
+				IdentifyingAttributes: []*protobufs.KeyValue{{Key: "service.name", Value: strValue("collector")}},
+			},
+			EffectiveConfig: &protobufs.EffectiveConfig{
+				ConfigMap: &protobufs.AgentConfigMap{
+					ConfigMap: map[string]*protobufs.AgentConfigFile{"collector.yaml": nil},
+				},
+			},
+			PackageStatuses: &protobufs.PackageStatuses{
+				Packages: map[string]*protobufs.PackageStatus{"pkg": nil},
+			},
+		},
+	} {
+		encoded, err := proto.Marshal(seed)
+		require.NoError(f, err)
+		f.Add(encoded)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var message protobufs.AgentToServer
+		if proto.Unmarshal(data, &message) != nil {
+			return
+		}
+
+		descToDomain(message.GetAgentDescription(), nil, nil, modelagent.DefaultAttributeLimits())
+		remoteConfigStatusToDomain(message.GetRemoteConfigStatus(), time.Time{})
+		connectionSettingsStatusToDomain(message.GetConnectionSettingsStatus())
+		customCapabilitiesToDomain(message.GetCustomCapabilities())
+		effectiveConfigToDomain(message.GetEffectiveConfig(), helper.TextYAML)
+		packageStatusToDomain(message.GetPackageStatuses())
+
+		if health := healthToDomain(message.GetHealth()); health != nil {
+			assert.LessOrEqual(t, healthDepth(health), maxComponentDepth)
+		}
+
+		limits := agentmodel.DefaultAvailableComponentsLimits()
+		if components := availableComponentsToDomain(message.GetAvailableComponents(), limits); components != nil {
+			for _, component := range components.Components {
+				assert.LessOrEqual(t, componentDetailsDepth(component), limits.MaxDepth)
+			}
+		}
+	})
+}
+
+func nestedHealth(depth int) *protobufs.ComponentHealth {
+	health := &protobufs.ComponentHealth{Healthy: true}
+	for range depth - 1 {
+		health = &protobufs.ComponentHealth{
+			Healthy:            true,
+			ComponentHealthMap: map[string]*protobufs.ComponentHealth{"child": health},
+		}
+	}
+
+	return health
+}
+
+func nestedComponentDetails(depth int) *protobufs.ComponentDetails {
+	details := &protobufs.ComponentDetails{}
+	for range depth - 1 {
+		details = &protobufs.ComponentDetails{
+			SubComponentMap: map[string]*protobufs.ComponentDetails{"child": details},
+		}
+	}
+
+	return details
+}
+
+func healthDepth(health *agentmodel.AgentComponentHealth) int {
+	deepest := 0
+	for _, sub := range health.ComponentHealthMap {
+		deepest = max(deepest, healthDepth(&sub))
+	}
+
+	return deepest + 1
+}
+
+func componentDetailsDepth(details agentmodel.ComponentDetails) int {
+	deepest := 0
+	for _, sub := range details.SubComponentMap {
+		deepest = max(deepest, componentDetailsDepth(sub))
+	}
+
+	return deepest + 1
+}
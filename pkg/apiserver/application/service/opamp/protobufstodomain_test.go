@@ -2,12 +2,19 @@
 package opamp
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 )
 
 func strValue(s string) *protobufs.AnyValue {
@@ -30,7 +37,7 @@ func TestDescToDomain_PreservesNonStringAttributes(t *testing.T) {
 		NonIdentifyingAttributes: nil,
 	}
 
-	got := descToDomain(desc)
+	got, _ := descToDomain(desc, nil, nil, modelagent.DefaultAttributeLimits())
 
 	require.NotNil(t, got)
 	assert.Equal(t, map[string]string{
@@ -41,6 +48,125 @@ func TestDescToDomain_PreservesNonStringAttributes(t *testing.T) {
 	}, got.IdentifyingAttributes)
 }
 
+func TestDescToDomain_NormalizesAliasedAttributes(t *testing.T) {
+	t.Parallel()
+
+	desc := &protobufs.AgentDescription{
+		IdentifyingAttributes: []*protobufs.KeyValue{
+			{Key: "service.name", Value: strValue("collector")},
+		},
+		NonIdentifyingAttributes: []*protobufs.KeyValue{
+			{Key: "host.hostname", Value: strValue("node-1")},
+			{Key: "os.type", Value: strValue("linux")},
+		},
+	}
+
+	got, _ := descToDomain(desc, nil, modelagent.DefaultAttributeAliases(), modelagent.DefaultAttributeLimits())
+
+	require.NotNil(t, got)
+	assert.Equal(t, map[string]string{"host.name": "node-1", "os.type": "linux"}, got.NonIdentifyingAttributes)
+	assert.Equal(t, map[string]string{"host.hostname": "node-1", "os.type": "linux"}, got.RawNonIdentifyingAttributes)
+	assert.Equal(t, "node-1", got.Host().Name)
+	// Nothing was aliased among the identifying attributes, so no raw copy is kept.
+	assert.Nil(t, got.RawIdentifyingAttributes)
+}
+
+func TestDescToDomain_TruncatesOversizedAttributes(t *testing.T) {
+	t.Parallel()
+
+	nonIdentifying := make([]*protobufs.KeyValue, 0, 1000)
+	for i := range 1000 {
+		nonIdentifying = append(nonIdentifying, &protobufs.KeyValue{
+			Key:   fmt.Sprintf("custom.attr.%04d", i),
+			Value: strValue(strings.Repeat("x", 1024)),
+		})
+	}
+
+	desc := &protobufs.AgentDescription{
+		IdentifyingAttributes: []*protobufs.KeyValue{
+			{Key: "service.name", Value: strValue("collector")},
+		},
+		NonIdentifyingAttributes: nonIdentifying,
+	}
+	limits := modelagent.AttributeLimits{MaxCount: 100, MaxTotalBytes: 16 * 1024}
+
+	got, _ := descToDomain(desc, nil, nil, limits)
+
+	require.NotNil(t, got)
+	assert.True(t, got.AttributesTruncated)
+	assert.Equal(t, map[string]string{"service.name": "collector"}, got.IdentifyingAttributes)
+	assert.NotEmpty(t, got.NonIdentifyingAttributes)
+	assert.LessOrEqual(t, len(got.NonIdentifyingAttributes), limits.MaxCount)
+
+	totalBytes := 0
+	for key, value := range got.NonIdentifyingAttributes {
+		totalBytes += len(key) + len(value)
+	}
+
+	assert.LessOrEqual(t, totalBytes, limits.MaxTotalBytes)
+
+	// The same report within the limits is stored untouched.
+	got, _ = descToDomain(&protobufs.AgentDescription{
+		IdentifyingAttributes:    desc.GetIdentifyingAttributes(),
+		NonIdentifyingAttributes: nonIdentifying[:10],
+	}, nil, nil, limits)
+	assert.False(t, got.AttributesTruncated)
+	assert.Len(t, got.NonIdentifyingAttributes, 10)
+}
+
+func TestDescToDomain_StripsDenylistedAttributes(t *testing.T) {
+	t.Parallel()
+
+	desc := &protobufs.AgentDescription{
+		IdentifyingAttributes: []*protobufs.KeyValue{
+			{Key: "service.name", Value: strValue("collector")},
+		},
+		NonIdentifyingAttributes: []*protobufs.KeyValue{
+			{Key: "host.hostname", Value: strValue("node-1")},
+			{Key: "auth.token", Value: strValue("secret")},
+		},
+	}
+
+	got, stripped := descToDomain(desc, modelagent.AttributeDenylist{"auth.token"},
+		modelagent.DefaultAttributeAliases(), modelagent.DefaultAttributeLimits())
+
+	require.NotNil(t, got)
+	assert.Equal(t, 1, stripped)
+	assert.Equal(t, map[string]string{"host.name": "node-1"}, got.NonIdentifyingAttributes)
+	// The raw copy kept by aliasing does not bring the stripped key back.
+	assert.Equal(t, map[string]string{"host.hostname": "node-1"}, got.RawNonIdentifyingAttributes)
+}
+
+func TestReport_StoresDescriptionWithoutDenylistedAttributes(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{
+		clock:             &persistTestClock{now: time.Now()},
+		logger:            slog.Default(),
+		attributeDenylist: modelagent.AttributeDenylist{"*.token"},
+		attributeAliases:  nil,
+		attributeLimits:   modelagent.DefaultAttributeLimits(),
+	}
+	agent := agentmodel.NewAgent(uuid.New())
+
+	err := service.report(agent, &protobufs.AgentToServer{
+		AgentDescription: &protobufs.AgentDescription{
+			IdentifyingAttributes: []*protobufs.KeyValue{
+				{Key: "service.name", Value: strValue("collector")},
+				{Key: "exporter.token", Value: strValue("secret")},
+			},
+			NonIdentifyingAttributes: []*protobufs.KeyValue{
+				{Key: "auth.token", Value: strValue("secret")},
+			},
+		},
+	}, nil, nil, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"service.name": "collector"},
+		agent.Metadata.Description.IdentifyingAttributes)
+	assert.NotContains(t, agent.Metadata.Description.NonIdentifyingAttributes, "auth.token")
+}
+
 func TestAnyValueToString(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,105 @@
+//nolint:testpackage // white-box test of the unexported persistence paths
+package opamp
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	mongoTestContainer "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/mongodb"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+)
+
+func TestRun_PersistsLastKnownConnectionStateAcrossRestart(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+
+	// The same image the persistence adapter tests run, which also runs on arm64.
+	mongoDBContainer, err := mongoTestContainer.Run(t.Context(), "mongo:4.4.10")
+	require.NoError(t, err)
+
+	mongoDBURI, err := mongoDBContainer.ConnectionString(t.Context())
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoDBURI))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(context.WithoutCancel(t.Context())))
+	})
+
+	database := client.Database("testdb")
+	start := time.Date(2026, time.May, 26, 12, 0, 0, 0, time.UTC)
+	testClock := &persistTestClock{now: start}
+
+	// newServer builds a server process: its agent cache and throttle state start empty,
+	// only the database is shared with the servers before it.
+	newServer := func() *Service {
+		logger := slog.New(slog.DiscardHandler)
+
+		return &Service{
+			clock: testClock,
+			agentUsecase: agentservice.NewAgentService(mongodb.NewAgentRepository(database, logger), logger,
+				agentservice.DefaultAgentCacheConfig(), ""),
+			logger:                   logger,
+			hostUsecase:              noopObserver{},
+			containerUsecase:         noopContainerObserver{},
+			onConnectionCloseTimeout: DefaultOnConnectionCloseTimeout,
+			heartbeatSaveThrottle:    DefaultHeartbeatSaveThrottle,
+		}
+	}
+
+	instanceUID := uuid.New()
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+	before := newServer()
+
+	report := func(sequenceNum uint64, heartbeatOnly bool) {
+		agent, deferred, err := before.loadAgent(t.Context(), instanceUID)
+		require.NoError(t, err)
+
+		agent.UpdateLastCommunicationInfo(testClock.now, connection)
+		agent.RecordLastReported(nil, testClock.now, sequenceNum)
+		before.maybePersistAgent(t.Context(), before.logger, instanceUID, heartbeatOnly, agent, deferred, testClock.now)
+	}
+
+	// The agent connects, then sends a heartbeat the throttle does not persist.
+	report(1, false)
+
+	testClock.now = start.Add(5 * time.Second)
+	report(2, true)
+
+	stored, err := before.agentUsecase.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stored.Status.SequenceNum, "the heartbeat is throttled")
+
+	// The server shuts down.
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+
+	go func() { done <- before.Run(ctx) }()
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// A restarted server restores the agent's last-known state from persistence.
+	after := newServer()
+	testClock.now = start.Add(10 * time.Second)
+
+	restored, err := after.agentUsecase.GetAgent(t.Context(), instanceUID)
+	require.NoError(t, err)
+	assert.True(t, restored.Status.LastReportedAt.Equal(start.Add(5*time.Second)),
+		"LastReportedAt is the heartbeat's, got %s", restored.Status.LastReportedAt)
+	assert.Equal(t, uint64(2), restored.Status.SequenceNum)
+	assert.Equal(t, agentmodel.ConnectionTypeWebSocket, restored.Status.ConnectionType)
+	assert.Equal(t, agentmodel.AgentConnectionStateConnected,
+		restored.ConnectionStateAt(testClock.now, agentmodel.DefaultConnectionStaleness))
+}
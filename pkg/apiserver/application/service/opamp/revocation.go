@@ -0,0 +1,58 @@
+package opamp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server/types"
+)
+
+// revokedAgentMessage is the error message sent to a revoked agent.
+const revokedAgentMessage = "agent instance UID has been revoked"
+
+// rejectRevokedAgent refuses a message from a revoked instance UID before any agent state
+// is loaded or written. It reports whether the message was rejected, and if so the
+// response OnMessage should return.
+//
+// A WebSocket agent is sent the error and then disconnected, so the response is nil: the
+// socket is already gone. A plain HTTP agent has no connection to close, so the error is
+// returned as the HTTP reply instead, and every later poll is refused the same way.
+//
+// A failed revocation lookup lets the message through; the agent load that follows hits
+// the same store and fails on its own if the store is down.
+func (s *Service) rejectRevokedAgent(
+	ctx context.Context,
+	logger *slog.Logger,
+	conn types.Connection,
+	instanceUID uuid.UUID,
+) (*protobufs.ServerToAgent, bool) {
+	revoked, err := s.agentRevocationUsecase.IsAgentRevoked(ctx, instanceUID)
+	if err != nil {
+		logger.Warn("failed to check agent revocation", slog.String("error", err.Error()))
+
+		return nil, false
+	}
+
+	if !revoked {
+		return nil, false
+	}
+
+	response := s.rejectMessage(ctx, logger, instanceUID, rejectReasonRevoked,
+		protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
+		revokedAgentMessage)
+
+	err = conn.Send(ctx, response)
+	if err != nil {
+		// Send only works over WebSocket; for plain HTTP the response is the reply.
+		return response, true
+	}
+
+	err = conn.Disconnect()
+	if err != nil {
+		logger.Warn("failed to disconnect revoked agent", slog.String("error", err.Error()))
+	}
+
+	return nil, true
+}
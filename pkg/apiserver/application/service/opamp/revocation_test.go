@@ -0,0 +1,138 @@
+//nolint:testpackage // white-box test of the unexported revocation check
+package opamp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var errHTTPConnection = errors.New("cannot operate over HTTP connection")
+
+// stubRevocationUsecase reports a fixed set of instance UIDs as revoked.
+type stubRevocationUsecase struct {
+	agentport.AgentRevocationUsecase
+
+	revoked map[uuid.UUID]bool
+}
+
+func (s *stubRevocationUsecase) IsAgentRevoked(_ context.Context, instanceUID uuid.UUID) (bool, error) {
+	return s.revoked[instanceUID], nil
+}
+
+// recordingConnection is a types.Connection that records what the handler did with it.
+// With http set it behaves like opamp-go's plain HTTP connection, which can neither send
+// nor disconnect.
+type recordingConnection struct {
+	netConn net.Conn
+	http    bool
+
+	sent         []*protobufs.ServerToAgent
+	disconnected bool
+}
+
+func newRecordingConnection(t *testing.T, http bool) *recordingConnection {
+	t.Helper()
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	return &recordingConnection{netConn: server, http: http}
+}
+
+func (c *recordingConnection) Connection() net.Conn { return c.netConn }
+
+func (c *recordingConnection) Send(_ context.Context, message *protobufs.ServerToAgent) error {
+	if c.http {
+		return errHTTPConnection
+	}
+
+	c.sent = append(c.sent, message)
+
+	return nil
+}
+
+func (c *recordingConnection) Disconnect() error {
+	if c.http {
+		return errHTTPConnection
+	}
+
+	c.disconnected = true
+
+	return nil
+}
+
+func newRevocationTestService(revoked ...uuid.UUID) *Service {
+	revocationUC := &stubRevocationUsecase{revoked: map[uuid.UUID]bool{}}
+	for _, instanceUID := range revoked {
+		revocationUC.revoked[instanceUID] = true
+	}
+
+	return &Service{
+		clock:                  clock.NewRealClock(),
+		logger:                 slog.New(slog.DiscardHandler),
+		tracer:                 noop.NewTracerProvider().Tracer(tracerName),
+		agentRevocationUsecase: revocationUC,
+	}
+}
+
+func TestOnMessage_RevokedAgentIsDisconnected(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+	svc := newRevocationTestService(instanceUID)
+	conn := newRecordingConnection(t, false)
+
+	// The service has no agent or connection usecases: reaching them would panic, so
+	// this also proves the message is refused before any agent state is touched.
+	response := svc.OnMessage(t.Context(), conn, &protobufs.AgentToServer{InstanceUid: instanceUID[:]})
+
+	assert.Nil(t, response)
+	assert.True(t, conn.disconnected)
+	require.Len(t, conn.sent, 1)
+	assert.Equal(t, protobufs.ServerErrorResponseType_ServerErrorResponseType_BadRequest,
+		conn.sent[0].GetErrorResponse().GetType())
+	assert.Equal(t, revokedAgentMessage, conn.sent[0].GetErrorResponse().GetErrorMessage())
+}
+
+func TestOnMessage_RevokedHTTPAgentGetsErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+	svc := newRevocationTestService(instanceUID)
+	conn := newRecordingConnection(t, true)
+
+	response := svc.OnMessage(t.Context(), conn, &protobufs.AgentToServer{InstanceUid: instanceUID[:]})
+
+	require.NotNil(t, response)
+	assert.Equal(t, revokedAgentMessage, response.GetErrorResponse().GetErrorMessage())
+	assert.Equal(t, instanceUID[:], response.GetInstanceUid())
+}
+
+func TestRejectRevokedAgent_NotRevokedProceeds(t *testing.T) {
+	t.Parallel()
+
+	svc := newRevocationTestService(uuid.New())
+	conn := newRecordingConnection(t, false)
+
+	response, rejected := svc.rejectRevokedAgent(t.Context(), svc.logger, conn, uuid.New())
+
+	assert.False(t, rejected)
+	assert.Nil(t, response)
+	assert.False(t, conn.disconnected)
+	assert.Empty(t, conn.sent)
+}
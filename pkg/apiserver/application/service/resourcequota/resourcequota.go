@@ -0,0 +1,50 @@
+// Package resourcequota provides the implementation of the ResourceQuotaManageUsecase interface.
+package resourcequota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ usecase.ResourceQuotaManageUsecase = (*Service)(nil)
+
+// Service is a struct that implements the ResourceQuotaManageUsecase interface.
+type Service struct {
+	resourceQuotaUsecase agentport.ResourceQuotaUsecase
+}
+
+// New creates a new instance of the Service struct.
+func New(resourceQuotaUsecase agentport.ResourceQuotaUsecase) *Service {
+	return &Service{
+		resourceQuotaUsecase: resourceQuotaUsecase,
+	}
+}
+
+// ListResourceQuotas implements [usecase.ResourceQuotaManageUsecase].
+func (s *Service) ListResourceQuotas(ctx context.Context) (*v1.ListResponse[v1.ResourceQuota], error) {
+	usages, err := s.resourceQuotaUsecase.ListResourceQuotaUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+
+	return v1.NewResourceQuotaListResponse(
+		lo.Map(usages, func(usage agentmodel.ResourceQuotaUsage, _ int) v1.ResourceQuota {
+			return v1.ResourceQuota{
+				Resource: string(usage.Resource),
+				Used:     usage.Used,
+				Limit:    usage.Limit,
+			}
+		}),
+		v1.ListMeta{
+			RemainingItemCount: 0,
+			Continue:           "",
+		},
+	), nil
+}
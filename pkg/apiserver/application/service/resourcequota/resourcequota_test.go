@@ -0,0 +1,36 @@
+package resourcequota_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	resourcequotasvc "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/resourcequota"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+)
+
+func TestService_ListResourceQuotas(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	groups := inmemory.NewAgentGroupRepository(inmemory.NewAgentRepository())
+	quota := agentservice.NewResourceQuotaService(groups, inmemory.NewCertificateRepository())
+	quota.SetResourceQuotas(agentmodel.ResourceQuotas{agentmodel.QuotaResourceAgentGroup: 10})
+
+	_, err := groups.PutAgentGroup(ctx, "default", "g-1",
+		agentmodel.NewAgentGroup("default", "g-1", nil, time.Now(), "tester"))
+	require.NoError(t, err)
+
+	resp, err := resourcequotasvc.New(quota).ListResourceQuotas(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, v1.ResourceQuotaKind, resp.Kind)
+	assert.Equal(t, []v1.ResourceQuota{
+		{Resource: "AgentGroup", Used: 1, Limit: 10},
+		{Resource: "Certificate", Used: 0, Limit: 0},
+	}, resp.Items)
+}
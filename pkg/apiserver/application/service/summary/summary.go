@@ -0,0 +1,43 @@
+// Package summary provides the implementation of the SummaryManageUsecase interface.
+package summary
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+var _ usecase.SummaryManageUsecase = (*Service)(nil)
+
+// Service is a struct that implements the SummaryManageUsecase interface.
+type Service struct {
+	agentFleetSummaryUsecase agentport.AgentFleetSummaryUsecase
+}
+
+// New creates a new instance of the Service struct.
+func New(agentFleetSummaryUsecase agentport.AgentFleetSummaryUsecase) *Service {
+	return &Service{
+		agentFleetSummaryUsecase: agentFleetSummaryUsecase,
+	}
+}
+
+// GetSummary implements [usecase.SummaryManageUsecase].
+func (s *Service) GetSummary(ctx context.Context) (*v1.Summary, error) {
+	summary, err := s.agentFleetSummaryUsecase.GetAgentFleetSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get summary: %w", err)
+	}
+
+	return &v1.Summary{
+		Kind:                      v1.SummaryKind,
+		APIVersion:                v1.APIVersion,
+		TotalAgents:               summary.NumAgents,
+		ConnectedAgents:           summary.NumConnectedAgents,
+		HealthyAgents:             summary.NumHealthyAgents,
+		UnhealthyAgents:           summary.NumUnhealthyAgents,
+		AgentsWithPendingCommands: summary.NumAgentsWithPendingCommands,
+	}, nil
+}
@@ -28,6 +28,20 @@ type AgentManageUsecase interface {
 	// attributes.
 	SearchAgents(ctx context.Context, namespace string, query string,
 		options *port.ListOptions) (*v1.ListResponse[v1.Agent], error)
+	// ListAgentsByPackage returns a paged list of the agents in namespace that
+	// reported the named package at packageVersion (any version when empty), e.g.
+	// to track which agents a package rollout has not reached yet.
+	ListAgentsByPackage(ctx context.Context, namespace string, packageName string, packageVersion string,
+		options *port.ListOptions) (*v1.ListResponse[v1.Agent], error)
+	// ListAgentCapabilities returns a paged list of the decoded capabilities of every
+	// connected agent across all namespaces, for a capability matrix. options.Capabilities
+	// narrows it to agents that reported all of the given capability bits.
+	ListAgentCapabilities(ctx context.Context,
+		options *port.ListOptions) (*v1.ListResponse[v1.AgentCapabilitySummary], error)
+	// ListAgentAttributes returns the distinct attribute keys agents reported across all
+	// namespaces, each with up to valueLimit of its distinct values; none when valueLimit
+	// is 0. valueLimit is capped at agentmodel.MaxAttributeValueLimit.
+	ListAgentAttributes(ctx context.Context, valueLimit int) (*v1.AgentAttributeCatalog, error)
 	// UpdateAgent applies a desired-state change to the agent (e.g. linking a
 	// remote config). It is optimistic-concurrency controlled and returns
 	// model.ErrConflict if the stored agent changed since it was read.
@@ -41,4 +55,39 @@ type AgentManageUsecase interface {
 	// to, extracted from its reported effective configuration (not persisted).
 	ListAgentEndpoints(ctx context.Context, namespace string,
 		instanceUID uuid.UUID) (*v1.ListResponse[v1.Endpoint], error)
+	// GetAgentUptime returns the agent's connection statistics: total connected
+	// time, disconnect count and uptime over the last 24 hours.
+	GetAgentUptime(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.AgentUptime, error)
+	// GetAgentEffectiveConfigHistory returns the distinct effective configs the agent
+	// reported, oldest first, as far back as the configured history limits allow.
+	GetAgentEffectiveConfigHistory(ctx context.Context, namespace string,
+		instanceUID uuid.UUID) (*v1.AgentEffectiveConfigHistory, error)
+	// RequestAgentReport asks the agent to report one part of its state again. The
+	// request stays pending until the agent reports it, and yields
+	// ErrUnsupportedAgentOperation when the agent's capabilities say it never will.
+	RequestAgentReport(ctx context.Context, namespace string, instanceUID uuid.UUID,
+		kind v1.AgentReportKind) (*v1.Agent, error)
+	// ResendAgentRemoteConfig offers the agent its current desired remote config again,
+	// for when it missed a push. It yields ErrAgentHasNoRemoteConfig when the agent has
+	// no remote config it can be offered.
+	ResendAgentRemoteConfig(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error)
+	// SetAgentDirectRemoteConfig replaces the remote configs set on the agent directly, outside
+	// of any agent group, and offers the agent the result. Agent group propagation keeps
+	// them. An empty config map removes them. It yields ErrUnsupportedAgentOperation when
+	// the agent does not accept remote config.
+	SetAgentDirectRemoteConfig(ctx context.Context, namespace string, instanceUID uuid.UUID,
+		configMap *v1.AgentConfigMap) (*v1.AgentDesiredConfig, error)
+	// ReconnectAgent closes the agent's connection so it reconnects and re-establishes its
+	// session. It yields ErrAgentNotConnected when the agent is not connected.
+	ReconnectAgent(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error)
+	// GetAgentDesiredConfig returns the remote config the server intends to offer the
+	// agent, with the agent group or the agent itself as the source of each entry.
+	GetAgentDesiredConfig(ctx context.Context, namespace string,
+		instanceUID uuid.UUID) (*v1.AgentDesiredConfig, error)
+	// WatchAgentEffectiveConfig returns a channel that first receives the agent's current
+	// effective config, then the config each time the agent reports a different one
+	// through any server. The channel is closed once ctx is done or the agent can no
+	// longer be read.
+	WatchAgentEffectiveConfig(ctx context.Context, namespace string,
+		instanceUID uuid.UUID) (<-chan *v1.AgentEffectiveConfig, error)
 }
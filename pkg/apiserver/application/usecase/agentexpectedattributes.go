@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// AgentExpectedAttributesManageUsecase lets an operator declare the attributes an agent
+// must keep reporting. The agent's AttributeDrift condition is True while it does not.
+// The operation is scoped by namespace like AgentManageUsecase and returns the updated agent.
+type AgentExpectedAttributesManageUsecase interface {
+	// SetAgentExpectedAttributes replaces the agent's expected attributes. An empty map
+	// removes every expectation.
+	SetAgentExpectedAttributes(ctx context.Context, namespace string, instanceUID uuid.UUID,
+		attributes map[string]string) (*v1.Agent, error)
+}
@@ -39,8 +39,32 @@ type AgentGroupManageUsecase interface {
 	CreateAgentGroup(ctx context.Context, agentGroup *v1.AgentGroup) (*v1.AgentGroup, error)
 	// UpdateAgentGroup replaces the named group's spec; it is
 	// optimistic-concurrency controlled (model.ErrConflict on a stale write).
+	// It never creates the group: a missing or deleted group is model.ErrResourceNotExist.
 	UpdateAgentGroup(ctx context.Context, namespace string, name string,
 		agentGroup *v1.AgentGroup) (*v1.AgentGroup, error)
 	// DeleteAgentGroup removes the named group.
 	DeleteAgentGroup(ctx context.Context, namespace string, name string) error
+	// PropagateAgentGroup re-applies the named group to its matching agents without
+	// modifying it, e.g. to retry after a partially failed propagation, and reports
+	// which agents were updated, unchanged, or failed.
+	PropagateAgentGroup(ctx context.Context, namespace string, name string) (*v1.AgentGroupPropagationResult, error)
+	// ApplyAgentGroupToAgents applies the named group's remote configs to exactly the given
+	// agents, whether or not its selector matches them, and reports which agents were
+	// updated, unchanged, or failed.
+	ApplyAgentGroupToAgents(ctx context.Context, namespace string, name string,
+		request *v1.AgentGroupApplyRequest) (*v1.AgentGroupPropagationResult, error)
+	// ListAgentGroupFailures lists the agents matched by the named group that reported
+	// failing to apply a remote config holding the group's configs, with their errors.
+	ListAgentGroupFailures(ctx context.Context, namespace string, name string) (*v1.AgentGroupFailures, error)
+	// AdvanceAgentGroupRollout moves the named group's rollout forward, so more of its
+	// agents receive its remote configs. Advancing to 100 percent completes the rollout.
+	AdvanceAgentGroupRollout(ctx context.Context, namespace string, name string,
+		advance *v1.AgentGroupRolloutAdvance) (*v1.AgentGroup, error)
+	// RollbackAgentGroup puts the named group back on the remote configs it had before
+	// they last changed and re-applies them to its agents. Rolling back again undoes it.
+	RollbackAgentGroup(ctx context.Context, namespace string, name string) (*v1.AgentGroup, error)
+	// PreviewAgentSelector counts the agents the selector matches without saving anything,
+	// returning up to sampleSize of them.
+	PreviewAgentSelector(ctx context.Context, selector *v1.AgentSelector,
+		sampleSize int64) (*v1.AgentSelectorPreview, error)
 }
@@ -26,4 +26,13 @@ type AgentPackageManageUsecase interface {
 		agentPackage *v1.AgentPackage) (*v1.AgentPackage, error)
 	// DeleteAgentPackage removes the named package.
 	DeleteAgentPackage(ctx context.Context, namespace string, name string) error
+	// DeleteAgentPackagesBySelector soft-deletes every package in namespace whose
+	// attributes match selector. An empty selector is rejected with
+	// model.ErrInvalidArgument rather than deleting the whole namespace.
+	DeleteAgentPackagesBySelector(ctx context.Context, namespace string,
+		selector map[string]string) (*v1.DeleteCollectionResponse, error)
+	// VerifyAgentPackage downloads the named package's artifact and records whether it
+	// matches the content hash as the package's ContentVerified condition. A failed
+	// download is reported on the condition, not as an error.
+	VerifyAgentPackage(ctx context.Context, namespace string, name string) (*v1.AgentPackage, error)
 }
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// AgentQuarantineManageUsecase lets an operator quarantine an agent by hand, or lift a
+// quarantine, independently of the health-based evaluator. While an agent is quarantined
+// agent group propagation does not push new config to it. Both operations are scoped by
+// namespace like AgentManageUsecase and return the updated agent.
+type AgentQuarantineManageUsecase interface {
+	// QuarantineAgent quarantines the agent with an optional human readable reason.
+	QuarantineAgent(ctx context.Context, namespace string, instanceUID uuid.UUID,
+		reason string) (*v1.Agent, error)
+	// UnquarantineAgent lifts the agent's quarantine and re-applies its agent groups.
+	UnquarantineAgent(ctx context.Context, namespace string, instanceUID uuid.UUID) (*v1.Agent, error)
+}
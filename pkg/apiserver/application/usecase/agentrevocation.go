@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// AgentRevocationManageUsecase lets an operator revoke an agent instance UID, so the
+// server refuses and closes its connections, and lift the revocation again. Unlike the
+// other agent operations it is not scoped by namespace: the instance UID is revoked
+// wherever it connects, whether or not the server has seen it yet.
+type AgentRevocationManageUsecase interface {
+	// RevokeAgent revokes the instance UID with an optional human readable reason.
+	RevokeAgent(ctx context.Context, instanceUID uuid.UUID, reason string) (*v1.AgentRevocation, error)
+	// UnrevokeAgent removes the revocation so the agent may connect again.
+	UnrevokeAgent(ctx context.Context, instanceUID uuid.UUID) error
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// BackupUsecase exports the managed resources (agent groups, agent remote configs,
+// certificates and agent packages) as a single bundle and restores them from one. It backs the
+// /api/v1/export and /api/v1/import endpoints.
+type BackupUsecase interface {
+	// Export returns every non-deleted managed resource across namespaces, with
+	// status stripped.
+	Export(ctx context.Context) (*v1.BackupBundle, error)
+	// Import creates the bundle's resources that are missing and updates the ones
+	// that differ, leaving matching resources untouched, so it is safe to re-run.
+	// A malformed bundle surfaces as model.ErrInvalidArgument.
+	Import(ctx context.Context, bundle *v1.BackupBundle) (*v1.BackupImportResult, error)
+}
@@ -26,4 +26,13 @@ type CertificateManageUsecase interface {
 		certificate *v1.Certificate) (*v1.Certificate, error)
 	// DeleteCertificate removes the named certificate.
 	DeleteCertificate(ctx context.Context, namespace string, name string) error
+	// GetCertificateUsage returns the agent groups in namespace whose connection settings
+	// use the named certificate, and whether each of their agents accepted them. A
+	// missing certificate yields model.ErrResourceNotExist.
+	GetCertificateUsage(ctx context.Context, namespace string, name string) (*v1.CertificateUsage, error)
+	// DeleteCertificatesBySelector soft-deletes every certificate in namespace whose
+	// attributes match selector. An empty selector is rejected with
+	// model.ErrInvalidArgument rather than deleting the whole namespace.
+	DeleteCertificatesBySelector(ctx context.Context, namespace string,
+		selector map[string]string) (*v1.DeleteCollectionResponse, error)
 }
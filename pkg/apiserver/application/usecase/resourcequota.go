@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// ResourceQuotaManageUsecase exposes the usage of the configured resource quotas.
+// It is read-only and backs the /api/v1/quotas controller.
+type ResourceQuotaManageUsecase interface {
+	// ListResourceQuotas returns the usage against the quota of every resource that
+	// can be capped, including unlimited ones.
+	ListResourceQuotas(ctx context.Context) (*v1.ListResponse[v1.ResourceQuota], error)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// SummaryManageUsecase reports an overview of the agent fleet.
+// It is read-only and backs the /api/v1/summary controller.
+type SummaryManageUsecase interface {
+	// GetSummary counts the agents of every namespace by their state.
+	GetSummary(ctx context.Context) (*v1.Summary, error)
+}
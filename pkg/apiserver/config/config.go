@@ -3,6 +3,7 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
@@ -15,16 +16,80 @@ import (
 // identity type from the domain) together with the infrastructure settings used
 // only by the composition root (database, event, cache).
 type ServerSettings struct {
-	Address            string
-	ServerID           agentmodel.ServerID
-	DatabaseSettings   DatabaseSettings
-	Security           security.Config
-	ManagementSettings ManagementSettings
-	EventSettings      EventSettings
-	CacheSettings      CacheSettings
-	BootstrapSettings  BootstrapSettings
-	MetricsBackend     MetricsBackendSettings
-	RBACModelPath      string
+	Address                               string
+	TrustedProxies                        []string
+	RequestTimeoutSettings                RequestTimeoutSettings
+	OpAMPSettings                         OpAMPSettings
+	ServerID                              agentmodel.ServerID
+	DatabaseSettings                      DatabaseSettings
+	Security                              security.Config
+	ManagementSettings                    ManagementSettings
+	EventSettings                         EventSettings
+	CacheSettings                         CacheSettings
+	BootstrapSettings                     BootstrapSettings
+	AgentGroupSettings                    AgentGroupSettings
+	AgentAttributeSettings                AgentAttributeSettings
+	AgentQuarantineSettings               AgentQuarantineSettings
+	AgentEffectiveConfigHistorySettings   AgentEffectiveConfigHistorySettings
+	AgentAvailableComponentsSettings      AgentAvailableComponentsSettings
+	AgentEffectiveConfigStalenessSettings AgentEffectiveConfigStalenessSettings
+	AgentCommandSettings                  AgentCommandSettings
+	AgentTransitionLogSettings            AgentTransitionLogSettings
+	AgentConfigFileSettings               AgentConfigFileSettings
+	PackageDownloadSettings               PackageDownloadSettings
+	ResourceQuotaSettings                 ResourceQuotaSettings
+	MetricsBackend                        MetricsBackendSettings
+	AgentEnrichment                       AgentEnrichmentSettings
+	RBACModelPath                         string
+}
+
+// RequestTimeoutSettings bounds how long an HTTP API request may run.
+type RequestTimeoutSettings struct {
+	// Default is the deadline of a request whose route has no override.
+	// Zero selects the built-in default; a negative value leaves requests unbounded.
+	Default time.Duration
+	// Routes overrides Default per route pattern (e.g. "/api/v1/export"). A non-positive
+	// timeout leaves the route unbounded.
+	Routes map[string]time.Duration
+}
+
+// OpAMPSettings configures the OpAMP endpoint agents connect to.
+type OpAMPSettings struct {
+	// MaxMessageBytes is the largest OpAMP message an agent may send. A WebSocket
+	// connection sending a larger message is closed, and a larger plain HTTP request is
+	// rejected. 0 means the default, negative means unlimited.
+	MaxMessageBytes int64
+	// MaxConnectionsPerIP is how many OpAMP connections one client IP may hold open at
+	// once, as seen through TrustedProxies. A connection beyond it is answered with 429 and
+	// a Retry-After that grows while the IP keeps reconnecting. 0 means unlimited.
+	MaxConnectionsPerIP int
+	// MinReportInterval is the minimum interval between persisting an agent's reports.
+	// Reports an agent sends sooner are coalesced and its latest state is persisted once
+	// the interval has passed. 0 persists every report.
+	MinReportInterval time.Duration
+	// EffectiveConfigOnChangeOnly stops an agent reporting the effective config it already
+	// has from causing a write on its own; it is saved with the agent's next write.
+	EffectiveConfigOnChangeOnly bool
+	// EffectiveConfigSampleInterval is the minimum interval between persisting an agent's
+	// changed effective configs, independent of its other reports. A change reported
+	// sooner is held back and the latest one is persisted by the agent's first message
+	// after the interval. 0 persists every change.
+	EffectiveConfigSampleInterval time.Duration
+	// DisconnectGracePeriod is how long an agent whose WebSocket closed keeps counting as
+	// connected, in the "Grace" connection state. An agent reconnecting within it is never
+	// marked disconnected. 0 marks agents disconnected as soon as the connection closes.
+	DisconnectGracePeriod time.Duration
+	// EnableCompression compresses what the server sends to agents advertising support:
+	// WebSocket connections negotiate permessage-deflate and plain HTTP responses are
+	// gzip-encoded. Gzip-compressed agent messages are accepted either way.
+	EnableCompression bool
+	// RequiredHeaders are headers, by name, every OpAMP connection must present with the
+	// given value, e.g. a shared secret. An empty value only requires the header. A
+	// connection without them is rejected with 403.
+	RequiredHeaders map[string]string
+	// RequiredSubprotocol is a subprotocol every WebSocket OpAMP connection must offer in
+	// Sec-WebSocket-Protocol. An upgrade without it is rejected with 400. Empty disables it.
+	RequiredSubprotocol string
 }
 
 // BootstrapSettings configures how the server seeds built-in resources on startup.
@@ -47,6 +112,130 @@ type BootstrapSettings struct {
 	DefaultRole string
 }
 
+// AgentGroupSettings configures how AgentGroups deliver their configuration to agents.
+type AgentGroupSettings struct {
+	// RemoteConfigKeyDelimiter separates the group name from an inline config name in
+	// the key the config is delivered under (e.g. "group/config"). A delimiter inside
+	// either name is escaped with a backslash. Empty means the default "/".
+	RemoteConfigKeyDelimiter string
+	// ForbiddenRemoteConfigKeys are dotted config keys (e.g. "exporters.debug") a group's
+	// remote configs must not set; an AgentGroup delivering one is rejected. A key also
+	// matches collector component IDs of its type, such as "exporters.debug/verbose".
+	ForbiddenRemoteConfigKeys []string
+	// BaseRemoteConfigFiles are paths of remote config files merged, with the lowest
+	// priority, into the remote config of every agent an AgentGroup governs. Each is
+	// delivered under its file name without the extension, and a group config delivered
+	// under the same name overrides it. A group opts its agents out with
+	// spec.excludeBaseRemoteConfig.
+	BaseRemoteConfigFiles []string
+	// CaseInsensitiveNames rejects creating an AgentGroup whose name differs from an
+	// existing group in the same namespace only by case, e.g. "prod" next to "Prod".
+	CaseInsensitiveNames bool
+}
+
+// AgentAttributeSettings configures how reported agent attributes are stored.
+type AgentAttributeSettings struct {
+	// Aliases maps an attribute key some agents report to the canonical key it is
+	// stored under (e.g. "host.hostname" -> "host.name"). Empty means the defaults.
+	Aliases map[string]string
+	// MaxCount and MaxTotalBytes cap the number and the summed key+value size of each
+	// reported attribute map; the excess is dropped. 0 means the default, negative
+	// means unlimited.
+	MaxCount      int
+	MaxTotalBytes int
+	// Denylist holds attribute key patterns (path.Match globs, e.g. "*.token") that
+	// are stripped from reported descriptions before they are stored.
+	Denylist []string
+}
+
+// AgentQuarantineSettings configures the evaluator that quarantines long-unhealthy agents.
+type AgentQuarantineSettings struct {
+	// UnhealthyThreshold is how long an agent must stay unhealthy before it is
+	// quarantined. Zero disables automatic quarantine.
+	UnhealthyThreshold time.Duration
+	// EvaluationInterval is how often agents are evaluated. Zero means one minute.
+	EvaluationInterval time.Duration
+}
+
+// AgentEffectiveConfigHistorySettings configures the effective-config history kept per agent.
+type AgentEffectiveConfigHistorySettings struct {
+	// MaxEntries and MaxTotalBytes cap the number of snapshots and their summed size;
+	// the oldest snapshots are dropped first. 0 means the default. A negative MaxEntries
+	// disables the history, a negative MaxTotalBytes the size cap.
+	MaxEntries    int
+	MaxTotalBytes int
+}
+
+// AgentAvailableComponentsSettings bounds the available components tree stored per agent.
+type AgentAvailableComponentsSettings struct {
+	// MaxDepth and MaxNodes cap the levels of the tree and the components kept across them;
+	// the deepest components are dropped first and the agent is flagged as truncated.
+	// 0 means the default; a negative value disables the cap.
+	MaxDepth int
+	MaxNodes int
+}
+
+// AgentEffectiveConfigStalenessSettings configures the evaluator that flags connected agents
+// whose effective config has not been reported recently.
+type AgentEffectiveConfigStalenessSettings struct {
+	// Window is how long a connected agent may go without reporting its effective config
+	// before it is asked to report it, and then how long it has to answer before it gets
+	// the StaleEffectiveConfig condition. Zero disables the evaluator.
+	Window time.Duration
+	// EvaluationInterval is how often agents are evaluated. Zero means one minute.
+	EvaluationInterval time.Duration
+}
+
+// AgentCommandSettings configures the commands, such as report requests and restarts,
+// queued for agents until they act on them.
+type AgentCommandSettings struct {
+	// MaxPending caps the commands queued for a single agent. A command beyond it is
+	// rejected. 0 or less means unbounded.
+	MaxPending int
+}
+
+// AgentTransitionLogSettings configures the structured log of agent state transitions, such
+// as connects, health changes, applied or failed remote configs and queued or acted-on
+// commands, kept for auditing.
+type AgentTransitionLogSettings struct {
+	// Enabled logs every transition at info level.
+	Enabled bool
+	// MaxPerSecond caps the transitions logged per second across all agents; the excess is
+	// dropped and counted. 0 or less means the default.
+	MaxPerSecond int
+}
+
+// AgentConfigFileSettings configures how agent config files are interpreted.
+type AgentConfigFileSettings struct {
+	// DefaultFormat is the format, "yaml" or "json", assumed for a config file that
+	// carries no content type, as older collectors report. Empty means "yaml".
+	DefaultFormat string
+}
+
+// PackageDownloadSettings configures how the server downloads agent package artifacts,
+// e.g. to verify them against their content hash. A zero field selects its default.
+type PackageDownloadSettings struct {
+	// Timeout bounds a single download attempt.
+	Timeout time.Duration
+	// MaxAttempts is how many times a failing download is tried.
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry; it doubles on each further retry.
+	RetryBackoff time.Duration
+	// FailureThreshold is how many consecutive failed attempts against an origin make
+	// further downloads from it fail immediately.
+	FailureThreshold int
+	// OpenDuration is how long downloads from such an origin fail immediately before one
+	// is let through to probe it.
+	OpenDuration time.Duration
+}
+
+// ResourceQuotaSettings caps how many resources of each kind may exist across all
+// namespaces. A create beyond a cap is rejected. 0 or less means unlimited.
+type ResourceQuotaSettings struct {
+	MaxAgentGroups  int64
+	MaxCertificates int64
+}
+
 // String returns a JSON representation of the ServerSettings struct.
 // It is used for logging and debugging purposes.
 //
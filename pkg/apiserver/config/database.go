@@ -10,6 +10,29 @@ type DatabaseSettings struct {
 	DatabaseName   string
 
 	DDLAuto bool
+
+	// AgentWriteBatch buffers agent writes into bulk writes. Only MongoDB supports it.
+	AgentWriteBatch AgentWriteBatchSettings
+
+	// FieldEncryption encrypts secret fields before they are stored. Only MongoDB supports it.
+	FieldEncryption FieldEncryptionSettings
+}
+
+// FieldEncryptionSettings holds the settings for encrypting secret fields, such as
+// certificate private keys, at rest.
+type FieldEncryptionSettings struct {
+	// KeyURI locates the key encryption key: base64key://<base64 key> gives it inline and
+	// file:///path reads it from a file. The key is 32 bytes. Empty disables encryption.
+	KeyURI string
+}
+
+// AgentWriteBatchSettings holds the settings for batching agent writes.
+type AgentWriteBatchSettings struct {
+	// FlushInterval is how long agent writes are buffered before they are written together.
+	// Zero disables batching, so every agent update is written on its own.
+	FlushInterval time.Duration
+	// MaxSize is the number of buffered agent writes that triggers a flush before FlushInterval elapses.
+	MaxSize int
 }
 
 // DatabaseType represents the type of database to be used.
@@ -0,0 +1,25 @@
+package config
+
+// AgentEnrichmentSettings configures the enrichment that derives server-owned labels for
+// agents. When Type is empty or "none", the no-op adapter is wired and agents carry no
+// labels.
+type AgentEnrichmentSettings struct {
+	// Type selects the enrichment implementation. "static" looks the labels up in
+	// StaticLabels; "" / "none" disables enrichment.
+	Type AgentEnrichmentType
+	// StaticAttribute is the agent attribute (e.g. "host.name") whose value selects the
+	// agent's labels in StaticLabels.
+	StaticAttribute string
+	// StaticLabels maps a value of StaticAttribute to the labels of the agents having it.
+	StaticLabels map[string]map[string]string
+}
+
+// AgentEnrichmentType is the type of enrichment deriving agent labels.
+type AgentEnrichmentType string
+
+const (
+	// AgentEnrichmentTypeStatic looks agent labels up in a static table.
+	AgentEnrichmentTypeStatic AgentEnrichmentType = "static"
+	// AgentEnrichmentTypeNone disables enrichment (no-op adapter).
+	AgentEnrichmentTypeNone AgentEnrichmentType = "none"
+)
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // EventSettings represents the event settings.
 type EventSettings struct {
 	// ProtocolType is the event protocol type.
@@ -15,6 +17,14 @@ type KafkaSettings struct {
 	Brokers []string
 	// Topic is the Kafka topic name for events.
 	Topic string
+	// KeyStrategy selects how events are keyed for partitioning: "instanceUID" (the
+	// default) keeps each agent's events ordered within a partition, "none" sends them unkeyed.
+	KeyStrategy string
+	// MaxConsumerLag is the number of unconsumed events above which the server reports
+	// itself not ready. Zero disables the readiness check; the lag is still exported.
+	MaxConsumerLag int64
+	// LagCheckInterval is how often the consumer lag is measured.
+	LagCheckInterval time.Duration
 }
 
 // EventProtocolType represents the type of event protocol.
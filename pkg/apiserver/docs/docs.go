@@ -16,6 +16,213 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Return the public keys that verify the tokens the server signs (empty unless RS256 is used).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/JSONWebKeySet"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/agents/attributes": {
+            "get": {
+                "description": "List the distinct identifying and non-identifying attribute keys reported by\nagents across all namespaces, e.g. to help write agent group selectors. With\nvalues set, up to that many distinct values are listed per key, at most 100.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "List Agent Attributes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of distinct values to list per key",
+                        "name": "values",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentAttributeCatalog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/agents/capabilities": {
+            "get": {
+                "description": "List the OpAMP capabilities of every connected agent across all namespaces,\ndecoded into flag names, for a capability matrix. With capability set, only the\nagents that reported every given capability are listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "List Agent Capabilities",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Capability flag, e.g. AcceptsPackages (repeatable)",
+                        "name": "capability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agents to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agents",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-AgentCapabilitySummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/agents/{id}/revoke": {
+            "post": {
+                "description": "Revoke an agent instance UID. The server refuses and closes its connections until it is unrevoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Revoke Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional revocation reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/AgentRevocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentRevocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the revocation of an agent instance UID so it may connect again.",
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Unrevoke Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/auth/basic": {
             "get": {
                 "description": "Authenticate using basic auth credentials.",
@@ -446,6 +653,33 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/export": {
+            "get": {
+                "description": "Export all AgentGroups, AgentRemoteConfigs, Certificates and AgentPackages as a backup bundle.\nThe bundle contains certificate private keys; store it accordingly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backup"
+                ],
+                "summary": "Export managed resources",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/BackupBundle"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/hosts": {
             "get": {
                 "description": "Retrieve a list of discovered hosts.",
@@ -607,29 +841,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agentgroups": {
+        "/api/v1/import": {
+            "post": {
+                "description": "Recreate the AgentGroups, AgentRemoteConfigs, Certificates and AgentPackages of a backup bundle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backup"
+                ],
+                "summary": "Import managed resources",
+                "parameters": [
+                    {
+                        "description": "Backup bundle produced by the export endpoint",
+                        "name": "bundle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/BackupBundle"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/BackupImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces": {
             "get": {
-                "description": "Retrieves a list of agent groups with pagination options.",
+                "description": "Retrieve a list of namespaces.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "agentgroup"
+                    "namespace"
                 ],
-                "summary": "List Agent Groups",
+                "summary": "List Namespaces",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Maximum number of agent groups to return",
+                        "description": "Maximum number of namespaces to return",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Token to continue listing agent groups",
+                        "description": "Token to continue listing namespaces",
                         "name": "continue",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Include soft-deleted agent groups",
+                        "description": "Include soft-deleted namespaces",
                         "name": "includeDeleted",
                         "in": "query"
                     }
@@ -638,7 +923,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ListResponse-AgentGroup"
+                            "$ref": "#/definitions/ListResponse-Namespace"
                         }
                     },
                     "400": {
@@ -658,7 +943,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new agent group.",
+                "description": "Create a new namespace.",
                 "consumes": [
                     "application/json"
                 ],
@@ -666,24 +951,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agentgroup"
+                    "namespace"
                 ],
-                "summary": "Create Agent Group",
+                "summary": "Create Namespace",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Namespace",
+                        "description": "Namespace to create",
                         "name": "namespace",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Agent Group to create",
-                        "name": "agentGroup",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/AgentGroup"
+                            "$ref": "#/definitions/Namespace"
                         }
                     }
                 ],
@@ -691,55 +969,47 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/AgentGroup"
+                            "$ref": "#/definitions/Namespace"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agentgroups/{name}": {
+        "/api/v1/namespaces/{namespace}": {
             "get": {
-                "description": "Retrieve an agent group by its ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a namespace by its name.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "agentgroup"
+                    "namespace"
                 ],
-                "summary": "Get Agent Group",
+                "summary": "Get Namespace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Agent Group Name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Namespace",
+                        "description": "Name of the namespace",
                         "name": "namespace",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "boolean",
-                        "description": "Include soft-deleted agent group",
+                        "description": "Include a soft-deleted namespace",
                         "name": "includeDeleted",
                         "in": "query"
                     }
@@ -748,7 +1018,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/AgentGroup"
+                            "$ref": "#/definitions/Namespace"
                         }
                     },
                     "400": {
@@ -775,7 +1045,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing agent group.",
+                "description": "Update an existing namespace.",
                 "consumes": [
                     "application/json"
                 ],
@@ -783,31 +1053,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agentgroup"
+                    "namespace"
                 ],
-                "summary": "Update Agent Group",
+                "summary": "Update Namespace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Namespace",
+                        "description": "Name of the namespace",
                         "name": "namespace",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Agent Group Name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated Agent Group",
-                        "name": "agentGroup",
+                        "description": "Updated namespace",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/AgentGroup"
+                            "$ref": "#/definitions/Namespace"
                         }
                     }
                 ],
@@ -815,49 +1078,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/AgentGroup"
+                            "$ref": "#/definitions/Namespace"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Mark an agent group as deleted.",
+                "description": "Delete a namespace by its name. The default namespace cannot be deleted.",
                 "tags": [
-                    "agentgroup"
+                    "namespace"
                 ],
-                "summary": "Delete Agent Group",
+                "summary": "Delete Namespace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Namespace",
+                        "description": "Name of the namespace",
                         "name": "namespace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Agent Group Name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -867,54 +1126,50 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agentpackages": {
+        "/api/v1/namespaces/{namespace}/agentgroups": {
             "get": {
-                "description": "Retrieve a list of agent packages.",
+                "description": "Retrieves a list of agent groups with pagination options.",
                 "tags": [
-                    "agentpackage"
+                    "agentgroup"
                 ],
-                "summary": "List Agent Packages",
+                "summary": "List Agent Groups",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Namespace",
-                        "name": "namespace",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "Maximum number of agent packages to return",
+                        "description": "Maximum number of agent groups to return",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Token to continue listing agent packages",
+                        "description": "Token to continue listing agent groups",
                         "name": "continue",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Include soft-deleted agent packages",
+                        "description": "Include soft-deleted agent groups",
                         "name": "includeDeleted",
                         "in": "query"
                     }
@@ -923,7 +1178,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ListResponse-AgentPackage"
+                            "$ref": "#/definitions/ListResponse-AgentGroup"
                         }
                     },
                     "400": {
@@ -943,7 +1198,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new agent package.",
+                "description": "Create a new agent group.",
                 "consumes": [
                     "application/json"
                 ],
@@ -951,9 +1206,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agentpackage"
+                    "agentgroup"
                 ],
-                "summary": "Create Agent Package",
+                "summary": "Create Agent Group",
                 "parameters": [
                     {
                         "type": "string",
@@ -963,12 +1218,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Agent Package to create",
-                        "name": "agentPackage",
+                        "description": "Agent Group to create",
+                        "name": "agentGroup",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/AgentPackage"
+                            "$ref": "#/definitions/AgentGroup"
                         }
                     }
                 ],
@@ -976,51 +1231,67 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/AgentPackage"
+                            "$ref": "#/definitions/AgentGroup"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agentpackages/{name}": {
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}": {
             "get": {
-                "description": "Retrieve an agent package by its name.",
+                "description": "Retrieve an agent group by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "agentpackage"
+                    "agentgroup"
                 ],
-                "summary": "Get Agent Package",
+                "summary": "Get Agent Group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Namespace",
-                        "name": "namespace",
+                        "description": "Agent Group Name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Name of the agent package",
-                        "name": "name",
+                        "description": "Namespace",
+                        "name": "namespace",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "boolean",
-                        "description": "Include soft-deleted agent package",
+                        "description": "Include soft-deleted agent group",
                         "name": "includeDeleted",
                         "in": "query"
                     }
@@ -1029,7 +1300,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/AgentPackage"
+                            "$ref": "#/definitions/AgentGroup"
                         }
                     },
                     "400": {
@@ -1056,7 +1327,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing agent package.",
+                "description": "Update an existing agent group. A group that does not exist or was deleted is not created.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1064,9 +1335,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agentpackage"
+                    "agentgroup"
                 ],
-                "summary": "Update Agent Package",
+                "summary": "Update Agent Group",
                 "parameters": [
                     {
                         "type": "string",
@@ -1077,18 +1348,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Name of the agent package",
+                        "description": "Agent Group Name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Agent Package",
-                        "name": "agentPackage",
+                        "description": "Updated Agent Group",
+                        "name": "agentGroup",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/AgentPackage"
+                            "$ref": "#/definitions/AgentGroup"
                         }
                     }
                 ],
@@ -1096,38 +1367,47 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/AgentPackage"
+                            "$ref": "#/definitions/AgentGroup"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete an agent package by its name.",
+                "description": "Mark an agent group as deleted.",
                 "tags": [
-                    "agentpackage"
+                    "agentgroup"
                 ],
-                "summary": "Delete Agent Package",
+                "summary": "Delete Agent Group",
                 "parameters": [
                     {
                         "type": "string",
@@ -1138,7 +1418,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Name of the agent package",
+                        "description": "Agent Group Name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -1151,30 +1431,27 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agents": {
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/agents": {
             "get": {
-                "description": "Retrieve a list of agents in a namespace.",
+                "description": "Retrieve agents belonging to a specific agent group.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1182,9 +1459,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agent"
+                    "agentgroup"
                 ],
-                "summary": "List Agents",
+                "summary": "List Agents by Agent Group",
                 "parameters": [
                     {
                         "type": "string",
@@ -1193,6 +1470,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Maximum number of agents to return",
@@ -1210,26 +1494,6 @@ const docTemplate = `{
                         "description": "When true, return only currently-connected agents",
                         "name": "connected",
                         "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Identifying attribute filter (key=value, repeatable)",
-                        "name": "selector",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Non-identifying attribute (key=value)",
-                        "name": "nonIdentifyingSelector",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1242,21 +1506,30 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agents/search": {
-            "get": {
-                "description": "Search agents by instance UID query in a namespace.",
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/apply": {
+            "post": {
+                "description": "Apply the agent group's remote configs to exactly the listed agents, whether or not its\nselector matches them. Later changes to the group do not reach them; apply it again for that.\nAgents that fail are listed in the result.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1264,9 +1537,1794 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "agent"
+                    "agentgroup"
+                ],
+                "summary": "Apply Agent Group To Agents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agents to apply the group to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroupApplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroupPropagationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/failures": {
+            "get": {
+                "description": "List the agents matched by an agent group that reported failing to apply a remote\nconfig holding the group's configs, with the errors they reported, e.g. after a rollout.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "List Agent Group Failures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroupFailures"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/propagate": {
+            "post": {
+                "description": "Re-apply an agent group to its matching agents without modifying it, e.g. to retry a\npartially failed propagation. Agents that fail are listed in the result.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "Propagate Agent Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroupPropagationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/rollback": {
+            "post": {
+                "description": "Restore the remote configs the agent group had before they last changed and\nre-apply them to all of its agents, ending any rollout. Rolling back again undoes it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "Roll Back Agent Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentgroups/{name}/rollout": {
+            "post": {
+                "description": "Raise the percentage or count of the agent group's agents that receive its remote\nconfigs. The rest stay on the configs from before the rollout; 100 percent completes it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "Advance Agent Group Rollout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent Group Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollout target",
+                        "name": "advance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroupRolloutAdvance"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentpackages": {
+            "get": {
+                "description": "Retrieve a list of agent packages.",
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "List Agent Packages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agent packages to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agent packages",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted agent packages",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-AgentPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new agent package.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Create Agent Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agent Package to create",
+                        "name": "agentPackage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-delete every agent package in the namespace whose attributes match the selector.\nThe selector is required, so an empty request cannot delete the whole namespace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Delete AgentPackages by Selector",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Attribute to match (key=value)",
+                        "name": "selector",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeleteCollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentpackages/{name}": {
+            "get": {
+                "description": "Retrieve an agent package by its name.",
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Get Agent Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent package",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted agent package",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing agent package.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Update Agent Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent package",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Agent Package",
+                        "name": "agentPackage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an agent package by its name.",
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Delete Agent Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent package",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentpackages/{name}/verify": {
+            "post": {
+                "description": "Download the agent package's artifact and record whether it matches the content hash\nas the ContentVerified condition. Downloads are retried, and an origin that keeps\nfailing is not contacted for a while; either way the failure is reported on the\ncondition rather than as an error status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentpackage"
+                ],
+                "summary": "Verify Agent Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent package",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentremoteconfigs": {
+            "get": {
+                "description": "Retrieve a list of agent remote configs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentremoteconfig"
+                ],
+                "summary": "List Agent Remote Configs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agent remote configs to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agent remote configs",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted agent remote configs",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-AgentRemoteConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new agent remote config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentremoteconfig"
+                ],
+                "summary": "Create Agent Remote Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agent remote config to create",
+                        "name": "agentRemoteConfig",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentRemoteConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/AgentRemoteConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agentremoteconfigs/{name}": {
+            "get": {
+                "description": "Retrieve an agent remote config by its name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentremoteconfig"
+                ],
+                "summary": "Get Agent Remote Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent remote config",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include a soft-deleted agent remote config",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentRemoteConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing agent remote config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentremoteconfig"
+                ],
+                "summary": "Update Agent Remote Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent remote config",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated agent remote config",
+                        "name": "agentRemoteConfig",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentRemoteConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentRemoteConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an agent remote config by its name.",
+                "tags": [
+                    "agentremoteconfig"
+                ],
+                "summary": "Delete Agent Remote Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the agent remote config",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents": {
+            "get": {
+                "description": "Retrieve a list of agents in a namespace.\nWith stream=ndjson or \"Accept: application/x-ndjson\", every matching agent is\nstreamed as one JSON object per line instead; limit then sets the page size.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "List Agents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agents to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agents",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "When true, return only currently-connected agents",
+                        "name": "connected",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "http",
+                            "websocket",
+                            "unknown"
+                        ],
+                        "type": "string",
+                        "description": "Connection type filter",
+                        "name": "connectionType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Identifying attribute filter (key=value, repeatable)",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Non-identifying attribute (key=value)",
+                        "name": "nonIdentifyingSelector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to ndjson to stream agents as newline-delimited JSON",
+                        "name": "stream",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/by-package": {
+            "get": {
+                "description": "List the agents in a namespace whose package statuses report the named package,\nat the given version when one is set (e.g. the agents still on an old collector).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "List Agents by Package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Package name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Package version the agent has; any version when omitted",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agents to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agents",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "When true, return only currently-connected agents",
+                        "name": "connected",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/search": {
+            "get": {
+                "description": "Search agents by instance UID query in a namespace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Search Agents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query for instance UID",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of agents to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token to continue listing agents",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "When true, return only currently-connected agents",
+                        "name": "connected",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}": {
+            "get": {
+                "description": "Retrieve an agent by its instance UID in a namespace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Get Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated field paths to return, e.g. metadata,status.componentHealth",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an agent's metadata \u0026 spec in a namespace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Update Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agent update request",
+                        "name": "agent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a disconnected agent by its instance UID in a namespace.\nConnected agents cannot be deleted and return 409 Conflict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Delete Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/agentgroups": {
+            "get": {
+                "description": "Retrieve the agent groups in the namespace whose selector matches the given agent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "List Agent Groups by Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-AgentGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/config": {
+            "put": {
+                "description": "Replace the remote configs set on the agent directly and offer the agent the result.\nThey are delivered under keys prefixed with \"@agent/\", and agent group propagation\nkeeps them. An empty config map removes them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Set Agent Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remote configs keyed by name",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentConfigMap"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/AgentDesiredConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/desired-config": {
+            "get": {
+                "description": "Retrieve the merged remote config the server intends to offer the agent. Each\nentry names its source: the matching agent group it comes from, or the agent itself.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Get Agent Desired Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentDesiredConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/effective-config/history": {
+            "get": {
+                "description": "Retrieve the distinct effective configs the agent reported, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Get Agent Effective Config History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentEffectiveConfigHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/effective-config/watch": {
+            "get": {
+                "description": "Stream the agent's effective config as server-sent \"effectiveConfig\" events:\nfirst the current config, then the config each time the agent reports a\ndifferent one. The stream stays open until the client disconnects.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Watch Agent Effective Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentEffectiveConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/endpoints": {
+            "get": {
+                "description": "Extract the telemetry endpoints from an agent's effective configuration.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "List Agent Endpoints",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-Endpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/expectedattributes": {
+            "put": {
+                "description": "Replace the attributes the agent must keep reporting. The agent's AttributeDrift\ncondition is True while it does not report them. Empty attributes remove every expectation.",
+                "consumes": [
+                    "application/json"
                 ],
-                "summary": "Search Agents",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Set Agent Expected Attributes",
                 "parameters": [
                     {
                         "type": "string",
@@ -1277,35 +3335,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search query for instance UID",
-                        "name": "q",
-                        "in": "query",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Maximum number of agents to return",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Token to continue listing agents",
-                        "name": "continue",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "When true, return only currently-connected agents",
-                        "name": "connected",
-                        "in": "query"
+                        "description": "Expected attributes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AgentExpectedAttributesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ListResponse-Agent"
+                            "$ref": "#/definitions/Agent"
                         }
                     },
                     "400": {
@@ -1314,6 +3363,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorModel"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1323,9 +3378,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agents/{id}": {
-            "get": {
-                "description": "Retrieve an agent by its instance UID in a namespace.",
+        "/api/v1/namespaces/{namespace}/agents/{id}/quarantine": {
+            "post": {
+                "description": "Manually quarantine an agent. A manual quarantine stays until it is lifted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1335,7 +3390,7 @@ const docTemplate = `{
                 "tags": [
                     "agent"
                 ],
-                "summary": "Get Agent",
+                "summary": "Quarantine Agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -1350,6 +3405,14 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Optional quarantine reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/AgentQuarantineRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1379,18 +3442,69 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update an agent's metadata \u0026 spec in a namespace.",
-                "consumes": [
+            "delete": {
+                "description": "Lift a manual or automatic quarantine of an agent.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Unquarantine Agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/reconnect": {
+            "post": {
+                "description": "Close the agent's OpAMP connection on the server holding it. The agent reconnects on its\nown and re-establishes its session, reporting its full state again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "agent"
                 ],
-                "summary": "Update Agent",
+                "summary": "Reconnect Agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -1405,34 +3519,175 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/report/available-components": {
+            "post": {
+                "description": "Ask the agent to re-send its available components with the ReportAvailableComponents flag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Request Agent Available Components Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Agent update request",
-                        "name": "agent",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/Agent"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/report/effective-config": {
+            "post": {
+                "description": "Ask the agent to re-send its effective config. OpAMP has no flag for the effective\nconfig alone, so the agent is sent ReportFullState until it reports the config.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agent"
+                ],
+                "summary": "Request Agent Effective Config Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance UID of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/Agent"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/ErrorModel"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/ErrorModel"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before retrying"
+                            }
                         }
                     },
                     "500": {
@@ -1442,19 +3697,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Permanently delete a disconnected agent by its instance UID in a namespace.\nConnected agents cannot be deleted and return 409 Conflict.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/namespaces/{namespace}/agents/{id}/report/health": {
+            "post": {
+                "description": "Ask the agent to re-send its component health. OpAMP has no flag for the health\nalone, so the agent is sent ReportFullState until it reports its health.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "agent"
                 ],
-                "summary": "Delete Agent",
+                "summary": "Request Agent Health Report",
                 "parameters": [
                     {
                         "type": "string",
@@ -1472,8 +3726,11 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/Agent"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1493,6 +3750,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorModel"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1502,19 +3771,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agents/{id}/agentgroups": {
-            "get": {
-                "description": "Retrieve the agent groups in the namespace whose selector matches the given agent.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/namespaces/{namespace}/agents/{id}/resend-config": {
+            "post": {
+                "description": "Re-deliver the agent's current desired remote config as an OpAMP offer, e.g. after\nthe agent missed a push while disconnected. The agent group is left untouched.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "agentgroup"
+                    "agent"
                 ],
-                "summary": "List Agent Groups by Agent",
+                "summary": "Resend Agent Remote Config",
                 "parameters": [
                     {
                         "type": "string",
@@ -1532,46 +3798,49 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/ListResponse-AgentGroup"
+                            "$ref": "#/definitions/Agent"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorModel"
                         }
                     }
                 }
             }
         },
-        "/api/v1/namespaces/{namespace}/agents/{id}/endpoints": {
+        "/api/v1/namespaces/{namespace}/agents/{id}/uptime": {
             "get": {
-                "description": "Extract the telemetry endpoints from an agent's effective configuration.",
+                "description": "Retrieve an agent's total connected time, disconnect count and last-24h uptime.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "agent"
                 ],
-                "summary": "List Agent Endpoints",
+                "summary": "Get Agent Uptime",
                 "parameters": [
                     {
                         "type": "string",
@@ -1592,7 +3861,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ListResponse-Endpoint"
+                            "$ref": "#/definitions/AgentUptime"
                         }
                     },
                     "400": {
@@ -1717,6 +3986,65 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-delete every certificate in the namespace whose attributes match the selector.\nThe selector is required, so an empty request cannot delete the whole namespace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "Delete Certificates by Selector",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Attribute to match (key=value)",
+                        "name": "selector",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeleteCollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1903,6 +4231,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/namespaces/{namespace}/certificates/{name}/usage": {
+            "get": {
+                "description": "List the agent groups whose connection settings reference a certificate by name, and\ntheir agents with the connection settings status they reported. An agent is accepted\nwhen it reported applying the connection settings currently offered to it, e.g. after\nthe certificate was rotated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "Get Certificate Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the certificate",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CertificateUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/namespaces/{namespace}/connections": {
             "get": {
                 "description": "Retrieve connections in a namespace. By default (scope=local) this returns\nonly the connections held by the server instance handling the request —\nconnections are WebSockets bound to a single node, so in a multi-server (HA)\ndeployment the default is a node-local view. Pass scope=cluster to get a\ncluster-wide view aggregated from each server's periodic snapshot; those\nitems include the owning serverId. For an always-current view of agent\nconnectivity, the agents API remains authoritative.",
@@ -2713,6 +5098,27 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/openapi.json": {
+            "get": {
+                "description": "Retrieve the OpenAPI (Swagger 2.0) document describing the API.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "openapi"
+                ],
+                "summary": "Get OpenAPI Document",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/ping": {
             "get": {
                 "description": "Ping the server to check if it is alive.",
@@ -2743,6 +5149,36 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/quotas": {
+            "get": {
+                "description": "Retrieve how many resources of each kind exist against their quota.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resourcequota"
+                ],
+                "summary": "List Resource Quotas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListResponse-ResourceQuota"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/reconcile/kinds": {
             "get": {
                 "description": "List the resource kinds that support reconcile.",
@@ -2999,11 +5435,63 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorModel"
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorModel"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/selectors/preview": {
+            "post": {
+                "description": "Count the agents across all namespaces that an agent group selector matches\nand return a sample of them, e.g. to check a selector before saving an agent\ngroup. The sample holds limit agents, 10 by default and at most 100.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agentgroup"
+                ],
+                "summary": "Preview Agent Selector",
+                "parameters": [
+                    {
+                        "description": "Selector to preview",
+                        "name": "selector",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentSelector"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of matching agents to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AgentSelectorPreview"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/ErrorModel"
                         }
@@ -3047,6 +5535,36 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/summary": {
+            "get": {
+                "description": "Count the agents of every namespace: in total, connected, healthy, unhealthy and with pending commands.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get Fleet Summary",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Summary"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/users": {
             "get": {
                 "description": "Retrieve a list of users.",
@@ -3437,6 +5955,14 @@ const docTemplate = `{
         "Agent": {
             "type": "object",
             "properties": {
+                "apiVersion": {
+                    "description": "APIVersion is the version of the API.",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind is the type of the resource.",
+                    "type": "string"
+                },
                 "metadata": {
                     "description": "Metadata contains identifying information about the agent.",
                     "allOf": [
@@ -3463,6 +5989,44 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentAttribute": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Values are the distinct values reported for the key, sorted. They are only listed\nwhen requested, and at most as many as requested.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valuesTruncated": {
+                    "description": "ValuesTruncated is set when more values were reported than are listed.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "AgentAttributeCatalog": {
+            "type": "object",
+            "properties": {
+                "identifyingAttributes": {
+                    "description": "IdentifyingAttributes are the distinct identifying attribute keys, sorted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentAttribute"
+                    }
+                },
+                "nonIdentifyingAttributes": {
+                    "description": "NonIdentifyingAttributes are the distinct non-identifying attribute keys, sorted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentAttribute"
+                    }
+                }
+            }
+        },
         "AgentAvailableComponents": {
             "type": "object",
             "properties": {
@@ -3471,6 +6035,37 @@ const docTemplate = `{
                     "additionalProperties": {
                         "$ref": "#/definitions/ComponentDetails"
                     }
+                },
+                "truncated": {
+                    "description": "Truncated is true when the agent reported a larger components tree than the server\nstores and some components were dropped.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "AgentCapabilitySummary": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "description": "Capabilities is the raw capability bitmask the agent reported.",
+                    "type": "integer"
+                },
+                "capabilityNames": {
+                    "description": "CapabilityNames lists the flag names set in Capabilities, e.g. \"AcceptsPackages\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "instanceUid": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "Namespace and InstanceUID identify the agent.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the agent type, as in AgentMetadata.Type.",
+                    "type": "string"
                 }
             }
         },
@@ -3521,6 +6116,10 @@ const docTemplate = `{
                 },
                 "contentType": {
                     "type": "string"
+                },
+                "primary": {
+                    "description": "Primary marks the main file of an effective config with several files. At most one\nfile is marked; none is when the main file cannot be told apart from the rest.",
+                    "type": "boolean"
                 }
             }
         },
@@ -3549,6 +6148,10 @@ const docTemplate = `{
         "AgentDescription": {
             "type": "object",
             "properties": {
+                "attributesTruncated": {
+                    "description": "AttributesTruncated is true when the agent reported more attributes than the\nserver accepts and the excess was dropped.",
+                    "type": "boolean"
+                },
                 "identifyingAttributes": {
                     "description": "IdentifyingAttributes are attributes that uniquely identify the agent.",
                     "type": "object",
@@ -3562,9 +6165,87 @@ const docTemplate = `{
                     "additionalProperties": {
                         "type": "string"
                     }
+                },
+                "rawIdentifyingAttributes": {
+                    "description": "RawIdentifyingAttributes are the identifying attributes as the agent reported them,\npresent only when the server normalized aliased keys.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rawNonIdentifyingAttributes": {
+                    "description": "RawNonIdentifyingAttributes are the non-identifying attributes as the agent reported\nthem, present only when the server normalized aliased keys.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "AgentDesiredConfig": {
+            "type": "object",
+            "properties": {
+                "configMap": {
+                    "description": "ConfigMap holds the desired config entries keyed by config name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/AgentDesiredConfigEntry"
+                    }
+                },
+                "instanceUid": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "Namespace and InstanceUID identify the agent.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentDesiredConfigEntry": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "contentType": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source is where the entry comes from.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/AgentDesiredConfigSource"
+                        }
+                    ]
+                }
+            }
+        },
+        "AgentDesiredConfigSource": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/AgentDesiredConfigSourceKind"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "Namespace and Name identify the contributing agent group when Kind is AgentGroup.\nWhen several matching groups declare the entry, it is the one whose config wins.",
+                    "type": "string"
                 }
             }
         },
+        "AgentDesiredConfigSourceKind": {
+            "type": "string",
+            "enum": [
+                "AgentGroup",
+                "Agent"
+            ],
+            "x-enum-varnames": [
+                "AgentDesiredConfigSourceAgentGroup",
+                "AgentDesiredConfigSourceAgent"
+            ]
+        },
         "AgentEffectiveConfig": {
             "type": "object",
             "properties": {
@@ -3573,6 +6254,59 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentEffectiveConfigHistory": {
+            "type": "object",
+            "properties": {
+                "instanceUid": {
+                    "description": "InstanceUID is the agent the history belongs to.",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Items are the snapshots, oldest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentEffectiveConfigSnapshot"
+                    }
+                }
+            }
+        },
+        "AgentEffectiveConfigSnapshot": {
+            "type": "object",
+            "properties": {
+                "effectiveConfig": {
+                    "description": "EffectiveConfig is the reported config.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/AgentEffectiveConfig"
+                        }
+                    ]
+                },
+                "hash": {
+                    "description": "Hash is the hex-encoded SHA-256 of the config content.",
+                    "type": "string"
+                },
+                "reportedAt": {
+                    "description": "ReportedAt is when the agent first reported this config.",
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "Truncated is true when the config exceeded the server's history size limit and\nonly its file names and content types were kept.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "AgentExpectedAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are the expected attributes with their values. Empty removes every expectation.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "AgentGroup": {
             "type": "object",
             "properties": {
@@ -3593,6 +6327,51 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentGroupApplyRequest": {
+            "type": "object",
+            "properties": {
+                "instanceUids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "AgentGroupFailure": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "description": "ErrorMessage is the error the agent reported.",
+                    "type": "string"
+                },
+                "instanceUid": {
+                    "type": "string"
+                },
+                "remoteConfigNames": {
+                    "description": "RemoteConfigNames are the agent group's remote configs the agent was offered.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reportedAt": {
+                    "description": "ReportedAt is when the agent reported the failure.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentGroupFailures": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentGroupFailure"
+                    }
+                }
+            }
+        },
         "AgentGroupMetadata": {
             "type": "object",
             "properties": {
@@ -3605,6 +6384,9 @@ const docTemplate = `{
                 "deletedAt": {
                     "type": "string"
                 },
+                "deletedBy": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -3613,15 +6395,69 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentGroupPropagationFailure": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "instanceUid": {
+                    "type": "string"
+                }
+            }
+        },
+        "AgentGroupPropagationResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Failed lists the agents the group could not be applied to.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentGroupPropagationFailure"
+                    }
+                },
+                "unchanged": {
+                    "description": "Unchanged is the number of agents that already matched the group.",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "Updated is the number of agents that were changed and saved.",
+                    "type": "integer"
+                }
+            }
+        },
+        "AgentGroupRolloutAdvance": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "integer"
+                }
+            }
+        },
         "AgentGroupSpec": {
             "type": "object",
             "properties": {
                 "agentConfig": {
                     "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentConfig"
                 },
+                "excludeBaseRemoteConfig": {
+                    "description": "ExcludeBaseRemoteConfig keeps the server's base remote configs off the group's agents.\nAn agent receives them only when none of its matching groups excludes them.",
+                    "type": "boolean"
+                },
                 "priority": {
                     "type": "integer"
                 },
+                "rollout": {
+                    "description": "Rollout limits the group's remote configs to a subset of its agents while a change\nis canaried. Without it every agent of the group receives them.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentGroupRollout"
+                        }
+                    ]
+                },
                 "selector": {
                     "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentSelector"
                 }
@@ -3686,6 +6522,13 @@ const docTemplate = `{
                     "description": "InstanceUID is a unique identifier for the agent instance.",
                     "type": "string"
                 },
+                "labels": {
+                    "description": "Labels are server-owned labels derived by agent enrichment, e.g. the agent's region\nor inventory owner. They are read-only through the API.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "namespace": {
                     "description": "Namespace is the namespace the agent belongs to.",
                     "type": "string"
@@ -3728,6 +6571,9 @@ const docTemplate = `{
                 "deletedAt": {
                     "type": "string"
                 },
+                "deletedBy": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -3811,6 +6657,149 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentQuarantine": {
+            "type": "object",
+            "properties": {
+                "automatic": {
+                    "description": "Automatic is true when the server quarantined the agent because it stayed\nunhealthy; such a quarantine is lifted once the agent reports healthy again.",
+                    "type": "boolean"
+                },
+                "quarantinedAt": {
+                    "description": "QuarantinedAt is when the agent was quarantined.",
+                    "type": "string"
+                },
+                "quarantinedBy": {
+                    "description": "QuarantinedBy is the user or system that quarantined the agent.",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason explains why the agent was quarantined.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentQuarantineRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason explains why the agent is being quarantined.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentRemoteConfig": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/AgentRemoteConfigMetadata"
+                },
+                "spec": {
+                    "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentRemoteConfigSpec"
+                },
+                "status": {
+                    "$ref": "#/definitions/AgentRemoteConfigStatus"
+                }
+            }
+        },
+        "AgentRemoteConfigMetadata": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.Attributes"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                }
+            }
+        },
+        "AgentRemoteConfigStatus": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Condition"
+                    }
+                }
+            }
+        },
+        "AgentReportKind": {
+            "type": "string",
+            "enum": [
+                "EffectiveConfig",
+                "Health",
+                "AvailableComponents"
+            ],
+            "x-enum-varnames": [
+                "AgentReportKindEffectiveConfig",
+                "AgentReportKindHealth",
+                "AgentReportKindAvailableComponents"
+            ]
+        },
+        "AgentRevocation": {
+            "type": "object",
+            "properties": {
+                "instanceUid": {
+                    "description": "InstanceUID is the revoked agent instance UID.",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason explains why the agent was revoked.",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "description": "RevokedAt is when the agent was revoked.",
+                    "type": "string"
+                },
+                "revokedBy": {
+                    "description": "RevokedBy is the user who revoked the agent.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentRevocationRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason explains why the agent is being revoked.",
+                    "type": "string"
+                }
+            }
+        },
+        "AgentSelectorPreview": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "description": "Agents is a sample of the matching agents, at most as many as requested.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Agent"
+                    }
+                },
+                "count": {
+                    "description": "Count is the number of agents the selector matches.",
+                    "type": "integer"
+                }
+            }
+        },
         "AgentSpec": {
             "type": "object",
             "properties": {
@@ -3822,6 +6811,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "expectedAttributes": {
+                    "description": "ExpectedAttributes are the attributes the agent must keep reporting, with their values.\nThe AttributeDrift condition is True while the agent does not report them. It is\nread-only here; use the expected attributes endpoint to change it.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "newInstanceUid": {
                     "description": "NewInstanceUID is a new instance UID to inform the agent of its new identity.",
                     "type": "string"
@@ -3834,6 +6830,21 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "pendingReports": {
+                    "description": "PendingReports are the parts of its state the agent was asked to report again and\nhas not reported yet. It is read-only here; use the report endpoints to add to it.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentReportKind"
+                    }
+                },
+                "quarantine": {
+                    "description": "Quarantine is set while the server has stopped pushing new config to the agent.\nIt is read-only here; use the quarantine endpoints to change it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/AgentQuarantine"
+                        }
+                    ]
+                },
                 "remoteConfig": {
                     "description": "RemoteConfig is the remote configuration of the agent.",
                     "allOf": [
@@ -3898,12 +6909,24 @@ const docTemplate = `{
                         "$ref": "#/definitions/Condition"
                     }
                 },
+                "configInSync": {
+                    "description": "ConfigInSync indicates the agent reports having applied the remote config the server\noffers it, by its hash. It is true when no remote config is offered.",
+                    "type": "boolean"
+                },
                 "connected": {
                     "description": "Connected indicates if the agent is currently connected.",
                     "type": "boolean"
                 },
+                "connectionLostAt": {
+                    "description": "ConnectionLostAt is when the agent's connection was lost, set during the grace period.",
+                    "type": "string"
+                },
+                "connectionState": {
+                    "description": "ConnectionState is one of \"Connected\", \"Grace\" or \"Disconnected\". Grace means the\nagent's connection was lost but the disconnect grace period has not elapsed yet;\nthe agent still counts as connected meanwhile.",
+                    "type": "string"
+                },
                 "connectionType": {
-                    "description": "ConnectionType indicates the type of connection the agent is using.",
+                    "description": "ConnectionType indicates the type of connection the agent is using, one of the\nConnectionType* names.",
                     "type": "string"
                 },
                 "effectiveConfig": {
@@ -3914,6 +6937,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "effectiveConfigReportedAt": {
+                    "description": "EffectiveConfigReportedAt is the timestamp when the agent last reported its effective config.",
+                    "type": "string"
+                },
+                "firstSeenAt": {
+                    "description": "FirstSeenAt is the timestamp when the agent first contacted a server.",
+                    "type": "string"
+                },
                 "lastReportedAt": {
                     "description": "LastReportedAt is the timestamp when the agent last reported its status.",
                     "type": "string"
@@ -3932,6 +6963,72 @@ const docTemplate = `{
                 }
             }
         },
+        "AgentUptime": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "description": "Connected reports whether the agent is currently connected.",
+                    "type": "boolean"
+                },
+                "connectedSince": {
+                    "description": "ConnectedSince is when the current connection started; omitted while disconnected.",
+                    "type": "string"
+                },
+                "disconnectCount": {
+                    "description": "DisconnectCount is the number of disconnects since tracking started.",
+                    "type": "integer"
+                },
+                "instanceUid": {
+                    "description": "InstanceUID is the agent the statistics belong to.",
+                    "type": "string"
+                },
+                "lastDisconnectedAt": {
+                    "description": "LastDisconnectedAt is when the agent last disconnected.",
+                    "type": "string"
+                },
+                "totalConnectedSeconds": {
+                    "description": "TotalConnectedSeconds is the connected time since the server started tracking\nthe agent, including the current connection.",
+                    "type": "number"
+                },
+                "windowConnectedSeconds": {
+                    "description": "WindowConnectedSeconds is the connected time within the window.",
+                    "type": "number"
+                },
+                "windowSeconds": {
+                    "description": "WindowSeconds is the trailing period the window fields cover: the last 24\nhours, or less when tracking started more recently.",
+                    "type": "number"
+                },
+                "windowUptimePercentage": {
+                    "description": "WindowUptimePercentage is WindowConnectedSeconds / WindowSeconds as 0-100.",
+                    "type": "number"
+                }
+            }
+        },
+        "AttributeMatchExpression": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "operator": {
+                    "$ref": "#/definitions/AttributeMatchOperator"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "AttributeMatchOperator": {
+            "type": "string",
+            "enum": [
+                "Gt",
+                "Lt"
+            ],
+            "x-enum-varnames": [
+                "AttributeMatchOperatorGreaterThan",
+                "AttributeMatchOperatorLessThan"
+            ]
+        },
         "AuthnTokenResponse": {
             "type": "object",
             "properties": {
@@ -3949,6 +7046,70 @@ const docTemplate = `{
                 }
             }
         },
+        "BackupBundle": {
+            "type": "object",
+            "properties": {
+                "agentGroups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentGroup"
+                    }
+                },
+                "agentPackages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentPackage"
+                    }
+                },
+                "agentRemoteConfigs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentRemoteConfig"
+                    }
+                },
+                "apiVersion": {
+                    "type": "string"
+                },
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Certificate"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/BackupBundleMetadata"
+                }
+            }
+        },
+        "BackupBundleMetadata": {
+            "type": "object",
+            "properties": {
+                "exportedAt": {
+                    "description": "ExportedAt is the time the bundle was exported.",
+                    "type": "string"
+                }
+            }
+        },
+        "BackupImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created is the number of resources that did not exist and were created.",
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "description": "Unchanged is the number of existing resources already matching the bundle.",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "Updated is the number of existing resources whose spec was replaced.",
+                    "type": "integer"
+                }
+            }
+        },
         "Certificate": {
             "type": "object",
             "properties": {
@@ -4005,6 +7166,10 @@ const docTemplate = `{
                     "description": "DeletedAt is the timestamp when the certificate was soft deleted.\nIf nil, the certificate is not deleted.",
                     "type": "string"
                 },
+                "deletedBy": {
+                    "description": "DeletedBy identifies the user or system that soft deleted the certificate.",
+                    "type": "string"
+                },
                 "name": {
                     "description": "Name is the unique name of the certificate.",
                     "type": "string"
@@ -4026,21 +7191,67 @@ const docTemplate = `{
                     "description": "Cert is the PEM-encoded certificate.",
                     "type": "string"
                 },
-                "privateKey": {
-                    "description": "PrivateKey is the PEM-encoded private key.",
-                    "type": "string"
+                "privateKey": {
+                    "description": "PrivateKey is the PEM-encoded private key.",
+                    "type": "string"
+                }
+            }
+        },
+        "CertificateStatus": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "description": "Conditions contains the conditions of the certificate.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Condition"
+                    }
+                }
+            }
+        },
+        "CertificateUsage": {
+            "type": "object",
+            "properties": {
+                "agentGroups": {
+                    "description": "AgentGroups are the names of the agent groups referencing the certificate.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "agents": {
+                    "description": "Agents are the agents of those agent groups.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CertificateUsageAgent"
+                    }
                 }
             }
         },
-        "CertificateStatus": {
+        "CertificateUsageAgent": {
             "type": "object",
             "properties": {
-                "conditions": {
-                    "description": "Conditions contains the conditions of the certificate.",
+                "accepted": {
+                    "description": "Accepted is true when the agent reported it applied the connection settings currently\noffered to it, so it uses the current certificate.",
+                    "type": "boolean"
+                },
+                "agentGroups": {
+                    "description": "AgentGroups are the agent groups referencing the certificate that the agent belongs to.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Condition"
+                        "type": "string"
                     }
+                },
+                "errorMessage": {
+                    "description": "ErrorMessage is the error the agent reported when applying the connection settings failed.",
+                    "type": "string"
+                },
+                "instanceUid": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the connection settings status the agent last reported:\nUNSET, APPLIED, APPLYING or FAILED.",
+                    "type": "string"
                 }
             }
         },
@@ -4084,7 +7295,9 @@ const docTemplate = `{
                 "Connected",
                 "Healthy",
                 "Configured",
-                "Registered"
+                "Registered",
+                "ContentVerified",
+                "RemoteConfigApplied"
             ],
             "x-enum-varnames": [
                 "ConditionTypeCreated",
@@ -4093,7 +7306,9 @@ const docTemplate = `{
                 "ConditionTypeConnected",
                 "ConditionTypeHealthy",
                 "ConditionTypeConfigured",
-                "ConditionTypeRegistered"
+                "ConditionTypeRegistered",
+                "ConditionTypeContentVerified",
+                "ConditionTypeRemoteConfigApplied"
             ]
         },
         "Connection": {
@@ -4270,6 +7485,21 @@ const docTemplate = `{
                 }
             }
         },
+        "DeleteCollectionResponse": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "deletedCount": {
+                    "description": "DeletedCount is the number of resources the request deleted.",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                }
+            }
+        },
         "DeviceAuthnTokenResponse": {
             "type": "object",
             "properties": {
@@ -4328,6 +7558,12 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -4642,6 +7878,47 @@ const docTemplate = `{
                 }
             }
         },
+        "JSONWebKey": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "Algorithm is the JWS algorithm the key is used with, e.g. \"RS256\".",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "Exponent is the base64url-encoded RSA public exponent.",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "KeyID matches the \"kid\" header of the tokens the key verifies.",
+                    "type": "string"
+                },
+                "kty": {
+                    "description": "KeyType is the key family, always \"RSA\".",
+                    "type": "string"
+                },
+                "n": {
+                    "description": "Modulus is the base64url-encoded RSA modulus.",
+                    "type": "string"
+                },
+                "use": {
+                    "description": "Use is the intended use of the key, always \"sig\".",
+                    "type": "string"
+                }
+            }
+        },
+        "JSONWebKeySet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "description": "Keys are the public keys that verify the tokens the server signs.\nIt is empty when tokens are signed with a shared secret (HS256).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/JSONWebKey"
+                    }
+                }
+            }
+        },
         "ListMeta": {
             "type": "object",
             "properties": {
@@ -4673,6 +7950,26 @@ const docTemplate = `{
                 }
             }
         },
+        "ListResponse-AgentCapabilitySummary": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentCapabilitySummary"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/ListMeta"
+                }
+            }
+        },
         "ListResponse-AgentGroup": {
             "type": "object",
             "properties": {
@@ -4713,6 +8010,26 @@ const docTemplate = `{
                 }
             }
         },
+        "ListResponse-AgentRemoteConfig": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AgentRemoteConfig"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/ListMeta"
+                }
+            }
+        },
         "ListResponse-Certificate": {
             "type": "object",
             "properties": {
@@ -4833,6 +8150,46 @@ const docTemplate = `{
                 }
             }
         },
+        "ListResponse-Namespace": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Namespace"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/ListMeta"
+                }
+            }
+        },
+        "ListResponse-ResourceQuota": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ResourceQuota"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/ListMeta"
+                }
+            }
+        },
         "ListResponse-Role": {
             "type": "object",
             "properties": {
@@ -4913,6 +8270,63 @@ const docTemplate = `{
                 }
             }
         },
+        "Namespace": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/NamespaceMetadata"
+                },
+                "status": {
+                    "$ref": "#/definitions/NamespaceStatus"
+                }
+            }
+        },
+        "NamespaceMetadata": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "NamespaceStatus": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Condition"
+                    }
+                }
+            }
+        },
         "OAuth2AuthCodeURLResponse": {
             "type": "object",
             "properties": {
@@ -4989,6 +8403,23 @@ const docTemplate = `{
                 }
             }
         },
+        "ResourceQuota": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Limit is the maximum number of resources that may exist. 0 means unlimited.",
+                    "type": "integer"
+                },
+                "resource": {
+                    "description": "Resource is the kind of resource the quota caps, e.g. AgentGroup or Certificate.",
+                    "type": "string"
+                },
+                "used": {
+                    "description": "Used is the number of resources that exist across all namespaces.",
+                    "type": "integer"
+                }
+            }
+        },
         "Role": {
             "type": "object",
             "properties": {
@@ -5285,6 +8716,37 @@ const docTemplate = `{
                 }
             }
         },
+        "Summary": {
+            "type": "object",
+            "properties": {
+                "agentsWithPendingCommands": {
+                    "description": "AgentsWithPendingCommands is the number of agents with a command they have not acted\non yet, such as a requested restart or report.",
+                    "type": "integer"
+                },
+                "apiVersion": {
+                    "type": "string"
+                },
+                "connectedAgents": {
+                    "description": "ConnectedAgents is the number of agents that are connected.",
+                    "type": "integer"
+                },
+                "healthyAgents": {
+                    "description": "HealthyAgents is the number of connected agents that report being healthy.",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "totalAgents": {
+                    "description": "TotalAgents is the number of agents.",
+                    "type": "integer"
+                },
+                "unhealthyAgents": {
+                    "description": "UnhealthyAgents is the number of connected agents that do not report being healthy.",
+                    "type": "integer"
+                }
+            }
+        },
         "TelemetryConnectionSettings": {
             "type": "object",
             "properties": {
@@ -5522,6 +8984,26 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_minuk-dev_opampcommander_api_v1.AgentGroupRollout": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of the group's agents that receive the group's remote configs,\ninstead of a percentage.",
+                    "type": "integer"
+                },
+                "percentage": {
+                    "description": "Percentage is the share of the group's agents, from 0 to 100, that receive the\ngroup's remote configs.",
+                    "type": "integer"
+                },
+                "previousAgentRemoteConfigs": {
+                    "description": "PreviousAgentRemoteConfigs are the remote configs the rest of the group stays on. The\nserver records them from the group's remote configs when the rollout starts.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_minuk-dev_opampcommander_api_v1.AgentGroupRemoteConfig"
+                    }
+                }
+            }
+        },
         "github_com_minuk-dev_opampcommander_api_v1.AgentRemoteConfigSpec": {
             "type": "object",
             "properties": {
@@ -5536,17 +9018,44 @@ const docTemplate = `{
         "github_com_minuk-dev_opampcommander_api_v1.AgentSelector": {
             "type": "object",
             "properties": {
+                "absentIdentifyingAttributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "absentNonIdentifyingAttributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "caseInsensitive": {
+                    "type": "boolean"
+                },
                 "identifyingAttributes": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
                 },
+                "identifyingMatchExpressions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AttributeMatchExpression"
+                    }
+                },
                 "nonIdentifyingAttributes": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
+                },
+                "nonIdentifyingMatchExpressions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AttributeMatchExpression"
+                    }
                 }
             }
         },
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -43,11 +44,14 @@ func NewAgent(instanceUID uuid.UUID, opts ...AgentOption) *Agent {
 		},
 		//exhaustruct:ignore
 		Spec: AgentSpec{
-			NewInstanceUID:    uuid.Nil,
-			RestartInfo:       nil,
-			RemoteConfig:      nil,
-			ConnectionInfo:    nil,
-			PackagesAvailable: nil,
+			NewInstanceUID:     uuid.Nil,
+			RestartInfo:        nil,
+			RemoteConfig:       nil,
+			ConnectionInfo:     nil,
+			PackagesAvailable:  nil,
+			Quarantine:         nil,
+			ExpectedAttributes: nil,
+			PendingReports:     nil,
 		},
 		Status: AgentStatus{
 			RemoteConfigStatus: AgentRemoteConfigStatus{
@@ -66,6 +70,8 @@ func NewAgent(instanceUID uuid.UUID, opts ...AgentOption) *Agent {
 					ConfigMap: make(map[string]AgentConfigFile),
 				},
 			},
+			EffectiveConfigReportedAt: time.Time{},
+			EffectiveConfigHistory:    nil,
 			//exhaustruct:ignore
 			PackageStatuses: AgentPackageStatuses{
 				Packages: make(map[string]AgentPackageStatusEntry),
@@ -90,9 +96,19 @@ func NewAgent(instanceUID uuid.UUID, opts ...AgentOption) *Agent {
 			},
 			Connected:      false,
 			ConnectionType: ConnectionTypeUnknown,
-			SequenceNum:    0,
-			LastReportedAt: time.Time{},
-			LastReportedTo: "",
+			ConnectionStats: AgentConnectionStats{
+				TrackedSince:           time.Time{},
+				ConnectedSince:         time.Time{},
+				LastDisconnectedAt:     time.Time{},
+				TotalConnectedDuration: 0,
+				DisconnectCount:        0,
+				RecentSessions:         nil,
+			},
+			ConnectionLostAt: time.Time{},
+			SequenceNum:      0,
+			FirstSeenAt:      time.Time{},
+			LastReportedAt:   time.Time{},
+			LastReportedTo:   "",
 		},
 	}
 
@@ -166,7 +182,8 @@ func (a *Agent) NeedFullStateCommand() bool {
 func (a *Agent) HasPendingServerMessages() bool {
 	return a.NeedFullStateCommand() ||
 		a.HasRemoteConfig() ||
-		a.ShouldBeRestarted()
+		a.ShouldBeRestarted() ||
+		len(a.Spec.PendingReports) > 0
 }
 
 // HasInstanceUID checks if the agent has a valid instance UID.
@@ -326,6 +343,11 @@ type AgentMetadata struct {
 
 	// CustomCapabilities is a list of custom capabilities that the Agent supports.
 	CustomCapabilities AgentCustomCapabilities
+
+	// Labels are server-owned labels derived from data outside of what the agent reports,
+	// e.g. its region or inventory owner. They are set by agent enrichment only; the agent
+	// cannot report or change them.
+	Labels map[string]string
 }
 
 // IsComplete checks if all required metadata fields are populated.
@@ -346,9 +368,15 @@ type AgentStatus struct {
 	RemoteConfigStatus       AgentRemoteConfigStatus
 	ConnectionSettingsStatus AgentConnectionSettingsStatus
 	EffectiveConfig          AgentEffectiveConfig
-	PackageStatuses          AgentPackageStatuses
-	ComponentHealth          AgentComponentHealth
-	AvailableComponents      AgentAvailableComponents
+	// EffectiveConfigReportedAt is when the agent last reported its effective config. Agents
+	// report it when it changes and when asked for their full state.
+	EffectiveConfigReportedAt time.Time
+	// EffectiveConfigHistory holds the distinct effective configs the agent reported,
+	// oldest first, bounded by EffectiveConfigHistoryLimits.
+	EffectiveConfigHistory []EffectiveConfigSnapshot
+	PackageStatuses        AgentPackageStatuses
+	ComponentHealth        AgentComponentHealth
+	AvailableComponents    AgentAvailableComponents
 
 	// Conditions is a list of conditions that apply to the agent.
 	// WARNING: Do NOT use Conditions for MongoDB queries or aggregations.
@@ -361,8 +389,16 @@ type AgentStatus struct {
 
 	Connected      bool
 	ConnectionType ConnectionType
-
-	SequenceNum    uint64
+	// ConnectionStats aggregates connect/disconnect transitions for uptime reporting.
+	ConnectionStats AgentConnectionStats
+	// ConnectionLostAt is when the agent's connection closed while its disconnect grace
+	// period runs. It is zero once the agent reconnects or is marked disconnected.
+	ConnectionLostAt time.Time
+
+	SequenceNum uint64
+	// FirstSeenAt is when the agent first contacted a server. It is set once and never
+	// overwritten, unlike LastReportedAt.
+	FirstSeenAt    time.Time
 	LastReportedAt time.Time
 	// LastReportedTo is the ID of the server the agent last reported to.
 	// When you want to get Server object, use `GetServerByID` function from ServerUsecase.
@@ -433,6 +469,18 @@ type AgentSpec struct {
 
 	// PackagesAvailable is the packages available for the agent.
 	PackagesAvailable *AgentSpecPackage
+
+	// Quarantine, when set, stops agent group propagation from pushing new remote
+	// config to the agent. It is server-set; agents never report it.
+	Quarantine *AgentQuarantine
+
+	// ExpectedAttributes are attributes the agent must keep reporting, with their values.
+	// It is server-set; the AttributeDrift condition tracks whether the agent complies.
+	ExpectedAttributes map[string]string
+
+	// PendingReports are the parts of its state the agent was asked to report again and
+	// has not reported yet.
+	PendingReports []AgentReportKind
 }
 
 // AgentSpecRemoteConfig represents the remote config specification for an agent.
@@ -602,7 +650,7 @@ func (a *Agent) IsOpAMPConnectionSettingsSupported() bool {
 
 // IsOwnMetricsSupported checks if the agent supports reporting its own metrics.
 func (a *Agent) IsOwnMetricsSupported() bool {
-	return a.Metadata.Capabilities.HasReportsOwnTraces()
+	return a.Metadata.Capabilities.HasReportsOwnMetrics()
 }
 
 // IsOwnLogsSupported checks if the agent supports reporting its own logs.
@@ -612,12 +660,12 @@ func (a *Agent) IsOwnLogsSupported() bool {
 
 // IsOwnTracesSupported checks if the agent supports reporting its own traces.
 func (a *Agent) IsOwnTracesSupported() bool {
-	return a.Metadata.Capabilities.HasReportsOwnMetrics()
+	return a.Metadata.Capabilities.HasReportsOwnTraces()
 }
 
 // IsOtherConnectionSettingsSupported checks if the agent supports other connection settings.
 func (a *Agent) IsOtherConnectionSettingsSupported() bool {
-	return a.Metadata.Capabilities.HasAcceptsOpAMPConnectionSettings()
+	return a.Metadata.Capabilities.HasOtherConnectionSettings()
 }
 
 // ApplyConnectionSettings applies connection settings to the agent from agent group.
@@ -885,6 +933,18 @@ type AgentEffectiveConfig struct {
 	ConfigMap AgentConfigMap
 }
 
+// Equal reports whether both configs hold the same files with the same content types and
+// bodies. A nil config equals only another nil config.
+func (c *AgentEffectiveConfig) Equal(other *AgentEffectiveConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	return maps.EqualFunc(c.ConfigMap.ConfigMap, other.ConfigMap.ConfigMap, func(a, b AgentConfigFile) bool {
+		return a.ContentType == b.ContentType && bytes.Equal(a.Body, b.Body)
+	})
+}
+
 // AgentConfigMap is a map of configuration files.
 type AgentConfigMap struct {
 	// The config_map field of the AgentConfigSet message is a map of configuration files, where keys are file names.
@@ -933,6 +993,22 @@ const (
 	ConnectionSettingsStatusFailed ConnectionSettingsStatus = 3
 )
 
+// String returns the string representation of the status.
+func (s ConnectionSettingsStatus) String() string {
+	switch s {
+	case ConnectionSettingsStatusUnset:
+		return "UNSET"
+	case ConnectionSettingsStatusApplied:
+		return "APPLIED"
+	case ConnectionSettingsStatusApplying:
+		return "APPLYING"
+	case ConnectionSettingsStatusFailed:
+		return "FAILED"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int32(s))
+	}
+}
+
 // AgentPackageStatuses is a map of package statuses.
 type AgentPackageStatuses struct {
 	Packages                     map[string]AgentPackageStatusEntry
@@ -940,6 +1016,17 @@ type AgentPackageStatuses struct {
 	ErrorMessage                 string
 }
 
+// HasPackage reports whether the agent reported the named package at the given version.
+// An empty version matches any version the agent has.
+func (s *AgentPackageStatuses) HasPackage(name, version string) bool {
+	entry, ok := s.Packages[name]
+	if !ok {
+		return false
+	}
+
+	return version == "" || entry.AgentHasVersion == version
+}
+
 // AgentPackageStatusEntry is the status of a package.
 type AgentPackageStatusEntry struct {
 	Name                 string
@@ -972,6 +1059,9 @@ type AgentCustomCapabilities struct {
 type AgentAvailableComponents struct {
 	Components map[string]ComponentDetails
 	Hash       []byte
+	// Truncated reports that the agent reported a larger tree than AvailableComponentsLimits
+	// allow and some components were dropped.
+	Truncated bool
 }
 
 // ComponentDetails is a details of a component.
@@ -1005,6 +1095,15 @@ func (a *Agent) ReportComponentHealth(health *AgentComponentHealth) error {
 	}
 
 	a.Status.ComponentHealth = *health
+	a.completeReport(AgentReportKindHealth)
+
+	// Track health as a condition so its LastTransitionTime records how long the agent
+	// has been (un)healthy, which the quarantine evaluator measures against.
+	if health.Healthy {
+		a.SetCondition(AgentConditionTypeHealthy, AgentConditionStatusTrue, "agent", "Agent reported healthy")
+	} else {
+		a.SetCondition(AgentConditionTypeHealthy, AgentConditionStatusFalse, "agent", health.LastError)
+	}
 
 	return nil
 }
@@ -1021,12 +1120,22 @@ func (a *Agent) ReportCapabilities(capabilities *agent.Capabilities) error {
 }
 
 // ReportEffectiveConfig is a method to report the effective configuration of the agent.
-func (a *Agent) ReportEffectiveConfig(config *AgentEffectiveConfig) error {
+// It records when the config was reported and clears a StaleEffectiveConfig condition,
+// including one still waiting for the agent to answer a request for the config.
+func (a *Agent) ReportEffectiveConfig(config *AgentEffectiveConfig, now time.Time) error {
 	if config == nil {
 		return nil // No effective config to report
 	}
 
 	a.Status.EffectiveConfig = *config
+	a.Status.EffectiveConfigReportedAt = now
+	a.completeReport(AgentReportKindEffectiveConfig)
+
+	stale := a.GetCondition(AgentConditionTypeStaleEffectiveConfig)
+	if stale != nil && stale.Status != AgentConditionStatusFalse {
+		a.SetConditionAt(AgentConditionTypeStaleEffectiveConfig, AgentConditionStatusFalse, now,
+			"agent", effectiveConfigFreshMessage)
+	}
 
 	return nil
 }
@@ -1082,10 +1191,38 @@ func (a *Agent) ReportAvailableComponents(availableComponents *AgentAvailableCom
 	}
 
 	a.Status.AvailableComponents = *availableComponents
+	a.completeReport(AgentReportKindAvailableComponents)
 
 	return nil
 }
 
+// SetLabels replaces the agent's server-owned labels with the given ones and reports
+// whether they changed. An empty map removes every label.
+func (a *Agent) SetLabels(labels map[string]string) bool {
+	if maps.Equal(a.Metadata.Labels, labels) {
+		return false
+	}
+
+	if len(labels) == 0 {
+		a.Metadata.Labels = nil
+
+		return true
+	}
+
+	a.Metadata.Labels = maps.Clone(labels)
+
+	return true
+}
+
+// RecordFirstSeen sets when the agent was first seen. It does nothing once the time is set.
+func (a *Agent) RecordFirstSeen(firstSeenAt time.Time) {
+	if !a.Status.FirstSeenAt.IsZero() {
+		return
+	}
+
+	a.Status.FirstSeenAt = firstSeenAt
+}
+
 // RecordLastReported updates the last communicated time and server of the agent.
 func (a *Agent) RecordLastReported(by *Server, lastReportedAt time.Time, sequenceNum uint64) {
 	if by != nil {
@@ -1129,14 +1266,28 @@ func (s RemoteConfigStatus) String() string {
 
 // UpdateLastCommunicationInfo updates the last communication info of the agent.
 func (a *Agent) UpdateLastCommunicationInfo(now time.Time, connection *Connection) {
+	connectionType := ConnectionTypeUnknown
+	if connection != nil {
+		connectionType = connection.Type
+	}
+
+	a.recordCommunication(now, connectionType)
+}
+
+// recordCommunication marks the agent connected over connectionType as of now.
+func (a *Agent) recordCommunication(now time.Time, connectionType ConnectionType) {
+	// HTTP-polling agents never signal a disconnect. If the agent went stale since its
+	// last report, that session ended when it was last seen.
+	if a.Status.ConnectionStats.IsConnected() && !a.IsConnectedAt(now, DefaultConnectionStaleness) {
+		a.Status.ConnectionStats.RecordDisconnected(a.Status.LastReportedAt)
+	}
+
+	a.Status.ConnectionStats.RecordConnected(now)
 	a.Status.Connected = true
+	a.Status.ConnectionLostAt = time.Time{}
 
 	a.Status.LastReportedAt = now
-	if connection != nil {
-		a.Status.ConnectionType = connection.Type
-	} else {
-		a.Status.ConnectionType = ConnectionTypeUnknown
-	}
+	a.Status.ConnectionType = connectionType
 }
 
 // IsRemoteConfigSupported checks if the agent supports remote configuration.
@@ -1224,17 +1375,40 @@ func (a *Agent) IsConditionTrue(conditionType AgentConditionType) bool {
 
 // MarkConnected marks the agent as connected and updates the connection condition.
 func (a *Agent) MarkConnected(triggeredBy string) {
+	now := time.Now()
+
 	a.Status.Connected = true
-	a.Status.LastReportedAt = time.Now()
+	a.Status.ConnectionLostAt = time.Time{}
+	a.Status.LastReportedAt = now
+	a.Status.ConnectionStats.RecordConnected(now)
 	a.SetCondition(AgentConditionTypeConnected, AgentConditionStatusTrue, triggeredBy, "Agent connected")
 }
 
 // MarkDisconnected marks the agent as disconnected and updates the connection condition.
 func (a *Agent) MarkDisconnected(triggeredBy string) {
-	a.Status.Connected = false
+	a.RecordDisconnectedAt(time.Now())
 	a.SetCondition(AgentConditionTypeConnected, AgentConditionStatusFalse, triggeredBy, "Agent disconnected")
 }
 
+// RecordDisconnectedAt marks the agent as disconnected at now and closes its
+// current connection session.
+func (a *Agent) RecordDisconnectedAt(now time.Time) {
+	a.Status.Connected = false
+	a.Status.ConnectionLostAt = time.Time{}
+	a.Status.ConnectionStats.RecordDisconnected(now)
+}
+
+// UptimeAt returns the agent's connection statistics as of now. A session whose
+// agent has gone stale is treated as having ended at its last report.
+func (a *Agent) UptimeAt(now time.Time, staleness time.Duration) AgentUptime {
+	stats := a.Status.ConnectionStats.Clone()
+	if stats.IsConnected() && !a.IsConnectedAt(now, staleness) {
+		stats.RecordDisconnected(a.Status.LastReportedAt)
+	}
+
+	return stats.UptimeAt(now)
+}
+
 // RecordInstanceUIDConflict audits an InstanceUIDConflict event on the agent, always
 // replacing any prior conflict condition so the recorded message reflects the most recent
 // occurrence (SetCondition would skip refreshes when the status is unchanged).
@@ -1311,6 +1485,7 @@ func (a *Agent) cloneMetadata() AgentMetadata {
 		CustomCapabilities: AgentCustomCapabilities{
 			Capabilities: cloneStringSlice(a.Metadata.CustomCapabilities.Capabilities),
 		},
+		Labels: maps.Clone(a.Metadata.Labels),
 	}
 
 	return metadata
@@ -1320,16 +1495,24 @@ func (a *Agent) cloneDescription() agent.Description {
 	return agent.Description{
 		IdentifyingAttributes:    maps.Clone(a.Metadata.Description.IdentifyingAttributes),
 		NonIdentifyingAttributes: maps.Clone(a.Metadata.Description.NonIdentifyingAttributes),
+
+		RawIdentifyingAttributes:    maps.Clone(a.Metadata.Description.RawIdentifyingAttributes),
+		RawNonIdentifyingAttributes: maps.Clone(a.Metadata.Description.RawNonIdentifyingAttributes),
+
+		AttributesTruncated: a.Metadata.Description.AttributesTruncated,
 	}
 }
 
 func (a *Agent) cloneSpec() AgentSpec {
 	spec := AgentSpec{
-		NewInstanceUID:    a.Spec.NewInstanceUID,
-		RestartInfo:       a.cloneRestartInfo(),
-		ConnectionInfo:    a.cloneConnectionInfo(),
-		RemoteConfig:      a.cloneRemoteConfig(),
-		PackagesAvailable: a.clonePackagesAvailable(),
+		NewInstanceUID:     a.Spec.NewInstanceUID,
+		RestartInfo:        a.cloneRestartInfo(),
+		ConnectionInfo:     a.cloneConnectionInfo(),
+		RemoteConfig:       a.cloneRemoteConfig(),
+		PackagesAvailable:  a.clonePackagesAvailable(),
+		Quarantine:         a.cloneQuarantine(),
+		ExpectedAttributes: maps.Clone(a.Spec.ExpectedAttributes),
+		PendingReports:     slices.Clone(a.Spec.PendingReports),
 	}
 
 	return spec
@@ -1460,18 +1643,23 @@ func (a *Agent) clonePackagesAvailable() *AgentSpecPackage {
 
 func (a *Agent) cloneStatus() AgentStatus {
 	return AgentStatus{
-		RemoteConfigStatus:       a.cloneRemoteConfigStatus(),
-		ConnectionSettingsStatus: a.cloneConnectionSettingsStatus(),
-		EffectiveConfig:          a.cloneEffectiveConfig(),
-		PackageStatuses:          a.clonePackageStatuses(),
-		ComponentHealth:          a.cloneComponentHealth(a.Status.ComponentHealth),
-		AvailableComponents:      a.cloneAvailableComponents(),
-		Conditions:               a.cloneConditions(),
-		Connected:                a.Status.Connected,
-		ConnectionType:           a.Status.ConnectionType,
-		SequenceNum:              a.Status.SequenceNum,
-		LastReportedAt:           a.Status.LastReportedAt,
-		LastReportedTo:           a.Status.LastReportedTo,
+		RemoteConfigStatus:        a.cloneRemoteConfigStatus(),
+		ConnectionSettingsStatus:  a.cloneConnectionSettingsStatus(),
+		EffectiveConfig:           a.cloneEffectiveConfig(),
+		EffectiveConfigReportedAt: a.Status.EffectiveConfigReportedAt,
+		EffectiveConfigHistory:    cloneEffectiveConfigHistory(a.Status.EffectiveConfigHistory),
+		PackageStatuses:           a.clonePackageStatuses(),
+		ComponentHealth:           a.cloneComponentHealth(a.Status.ComponentHealth),
+		AvailableComponents:       a.cloneAvailableComponents(),
+		Conditions:                a.cloneConditions(),
+		Connected:                 a.Status.Connected,
+		ConnectionType:            a.Status.ConnectionType,
+		ConnectionStats:           a.Status.ConnectionStats.Clone(),
+		ConnectionLostAt:          a.Status.ConnectionLostAt,
+		SequenceNum:               a.Status.SequenceNum,
+		FirstSeenAt:               a.Status.FirstSeenAt,
+		LastReportedAt:            a.Status.LastReportedAt,
+		LastReportedTo:            a.Status.LastReportedTo,
 	}
 }
 
@@ -1493,19 +1681,7 @@ func (a *Agent) cloneConnectionSettingsStatus() AgentConnectionSettingsStatus {
 }
 
 func (a *Agent) cloneEffectiveConfig() AgentEffectiveConfig {
-	configMap := make(map[string]AgentConfigFile, len(a.Status.EffectiveConfig.ConfigMap.ConfigMap))
-	for k, v := range a.Status.EffectiveConfig.ConfigMap.ConfigMap {
-		configMap[k] = AgentConfigFile{
-			Body:        cloneByteSlice(v.Body),
-			ContentType: v.ContentType,
-		}
-	}
-
-	return AgentEffectiveConfig{
-		ConfigMap: AgentConfigMap{
-			ConfigMap: configMap,
-		},
-	}
+	return cloneAgentEffectiveConfig(&a.Status.EffectiveConfig)
 }
 
 func (a *Agent) clonePackageStatuses() AgentPackageStatuses {
@@ -1548,6 +1724,7 @@ func (a *Agent) cloneAvailableComponents() AgentAvailableComponents {
 	return AgentAvailableComponents{
 		Components: cloneComponentDetails(a.Status.AvailableComponents.Components),
 		Hash:       cloneByteSlice(a.Status.AvailableComponents.Hash),
+		Truncated:  a.Status.AvailableComponents.Truncated,
 	}
 }
 
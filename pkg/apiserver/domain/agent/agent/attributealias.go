@@ -0,0 +1,72 @@
+package agent
+
+import "maps"
+
+// AttributeAliases maps an alias attribute key to its canonical key. Different
+// collector versions report the same concept under different keys (e.g.
+// "host.hostname" instead of "host.name"); normalizing them lets selectors and
+// search match on a single key.
+type AttributeAliases map[string]string
+
+// DefaultAttributeAliases returns the aliases applied when none are configured.
+func DefaultAttributeAliases() AttributeAliases {
+	return AttributeAliases{
+		"host.hostname": "host.name",
+	}
+}
+
+// Normalize returns attrs with every alias key renamed to its canonical key, and
+// whether anything was renamed. When both an alias and its canonical key are
+// present the canonical value wins. attrs itself is never modified.
+func (a AttributeAliases) Normalize(attrs map[string]string) (map[string]string, bool) {
+	changed := false
+
+	var normalized map[string]string
+
+	for alias, canonical := range a {
+		value, ok := attrs[alias]
+		if !ok || alias == canonical {
+			continue
+		}
+
+		if normalized == nil {
+			normalized = maps.Clone(attrs)
+		}
+
+		delete(normalized, alias)
+
+		if _, exists := attrs[canonical]; !exists {
+			normalized[canonical] = value
+		}
+
+		changed = true
+	}
+
+	if !changed {
+		return attrs, false
+	}
+
+	return normalized, true
+}
+
+// NormalizeDescription canonicalizes the description's attributes in place. The
+// attributes as reported are kept in the Raw* fields when normalization changed
+// them, and cleared otherwise.
+func (a AttributeAliases) NormalizeDescription(desc *Description) {
+	if desc == nil {
+		return
+	}
+
+	desc.RawIdentifyingAttributes = nil
+	desc.RawNonIdentifyingAttributes = nil
+
+	if identifying, changed := a.Normalize(desc.IdentifyingAttributes); changed {
+		desc.RawIdentifyingAttributes = desc.IdentifyingAttributes
+		desc.IdentifyingAttributes = identifying
+	}
+
+	if nonIdentifying, changed := a.Normalize(desc.NonIdentifyingAttributes); changed {
+		desc.RawNonIdentifyingAttributes = desc.NonIdentifyingAttributes
+		desc.NonIdentifyingAttributes = nonIdentifying
+	}
+}
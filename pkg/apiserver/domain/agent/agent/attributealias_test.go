@@ -0,0 +1,71 @@
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestAttributeAliases_Normalize(t *testing.T) {
+	t.Parallel()
+
+	aliases := agent.AttributeAliases{"host.hostname": "host.name"}
+
+	tests := []struct {
+		name        string
+		attrs       map[string]string
+		want        map[string]string
+		wantChanged bool
+	}{
+		{
+			name:        "alias is renamed",
+			attrs:       map[string]string{"host.hostname": "a"},
+			want:        map[string]string{"host.name": "a"},
+			wantChanged: true,
+		},
+		{
+			name:        "canonical value wins over alias",
+			attrs:       map[string]string{"host.hostname": "a", "host.name": "b"},
+			want:        map[string]string{"host.name": "b"},
+			wantChanged: true,
+		},
+		{
+			name:        "no alias present",
+			attrs:       map[string]string{"host.name": "b"},
+			want:        map[string]string{"host.name": "b"},
+			wantChanged: false,
+		},
+		{
+			name:        "nil attributes",
+			attrs:       nil,
+			want:        nil,
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, changed := aliases.Normalize(tt.attrs)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+func TestAttributeAliases_NormalizeDescription(t *testing.T) {
+	t.Parallel()
+
+	desc := &agent.Description{
+		IdentifyingAttributes: map[string]string{"service.name": "svc", "host.hostname": "node-1"},
+	}
+
+	agent.DefaultAttributeAliases().NormalizeDescription(desc)
+
+	assert.Equal(t, map[string]string{"service.name": "svc", "host.name": "node-1"}, desc.IdentifyingAttributes)
+	assert.Equal(t, map[string]string{"service.name": "svc", "host.hostname": "node-1"}, desc.RawIdentifyingAttributes)
+	assert.Nil(t, desc.RawNonIdentifyingAttributes)
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"path"
+)
+
+// AttributeDenylist lists attribute key patterns that are never stored, so that
+// sensitive values an agent reports (tokens, internal addresses) are dropped before
+// they reach persistence. A pattern is a plain key or a path.Match glob, such as
+// "*.token".
+type AttributeDenylist []string
+
+// Validate reports the first malformed pattern.
+func (d AttributeDenylist) Validate() error {
+	for _, pattern := range d {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("invalid attribute denylist pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether key matches any pattern. Malformed patterns never match.
+func (d AttributeDenylist) Matches(key string) bool {
+	for _, pattern := range d {
+		if matched, err := path.Match(pattern, key); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Strip returns attrs without the denylisted keys, and how many were removed.
+// attrs itself is never modified.
+func (d AttributeDenylist) Strip(attrs map[string]string) (map[string]string, int) {
+	if len(d) == 0 {
+		return attrs, 0
+	}
+
+	var stripped map[string]string
+
+	for key := range attrs {
+		if !d.Matches(key) {
+			continue
+		}
+
+		if stripped == nil {
+			stripped = maps.Clone(attrs)
+		}
+
+		delete(stripped, key)
+	}
+
+	if stripped == nil {
+		return attrs, 0
+	}
+
+	return stripped, len(attrs) - len(stripped)
+}
+
+// StripDescription removes the denylisted keys from the description's attributes in
+// place and returns how many were removed.
+func (d AttributeDenylist) StripDescription(desc *Description) int {
+	if desc == nil {
+		return 0
+	}
+
+	identifying, identifyingStripped := d.Strip(desc.IdentifyingAttributes)
+	nonIdentifying, nonIdentifyingStripped := d.Strip(desc.NonIdentifyingAttributes)
+
+	desc.IdentifyingAttributes = identifying
+	desc.NonIdentifyingAttributes = nonIdentifying
+
+	return identifyingStripped + nonIdentifyingStripped
+}
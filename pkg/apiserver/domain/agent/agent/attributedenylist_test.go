@@ -0,0 +1,80 @@
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestAttributeDenylist_Strip(t *testing.T) {
+	t.Parallel()
+
+	denylist := agent.AttributeDenylist{"auth.token", "*.password"}
+
+	tests := []struct {
+		name         string
+		attrs        map[string]string
+		want         map[string]string
+		wantStripped int
+	}{
+		{
+			name:         "exact key is stripped",
+			attrs:        map[string]string{"auth.token": "secret", "host.name": "a"},
+			want:         map[string]string{"host.name": "a"},
+			wantStripped: 1,
+		},
+		{
+			name:         "glob pattern is stripped",
+			attrs:        map[string]string{"db.password": "secret", "proxy.password": "secret"},
+			want:         map[string]string{},
+			wantStripped: 2,
+		},
+		{
+			name:         "no denylisted key present",
+			attrs:        map[string]string{"host.name": "a"},
+			want:         map[string]string{"host.name": "a"},
+			wantStripped: 0,
+		},
+		{
+			name:         "nil attributes",
+			attrs:        nil,
+			want:         nil,
+			wantStripped: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, stripped := denylist.Strip(tt.attrs)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStripped, stripped)
+		})
+	}
+}
+
+func TestAttributeDenylist_StripDescription(t *testing.T) {
+	t.Parallel()
+
+	desc := &agent.Description{
+		IdentifyingAttributes:    map[string]string{"service.name": "otelcol", "auth.token": "secret"},
+		NonIdentifyingAttributes: map[string]string{"auth.token": "secret", "host.ip": "10.0.0.1"},
+	}
+
+	stripped := agent.AttributeDenylist{"auth.token", "host.ip"}.StripDescription(desc)
+
+	assert.Equal(t, 3, stripped)
+	assert.Equal(t, map[string]string{"service.name": "otelcol"}, desc.IdentifyingAttributes)
+	assert.Empty(t, desc.NonIdentifyingAttributes)
+}
+
+func TestAttributeDenylist_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, agent.AttributeDenylist{"auth.token", "*.password"}.Validate())
+	require.Error(t, agent.AttributeDenylist{"auth.[token"}.Validate())
+}
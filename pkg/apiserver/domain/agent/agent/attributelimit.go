@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"maps"
+	"slices"
+)
+
+const (
+	// DefaultMaxAttributeCount is the default maximum number of attributes kept per
+	// attribute map (identifying or non-identifying).
+	DefaultMaxAttributeCount = 256
+	// DefaultMaxAttributeTotalBytes is the default maximum summed size of keys and
+	// values kept per attribute map.
+	DefaultMaxAttributeTotalBytes = 64 * 1024
+)
+
+// AttributeLimits bounds how much of an agent-reported attribute map is stored,
+// so that a buggy or malicious agent cannot bloat its document. Each map is
+// limited separately. A non-positive limit disables that check.
+type AttributeLimits struct {
+	// MaxCount is the maximum number of attributes kept.
+	MaxCount int
+	// MaxTotalBytes is the maximum of the summed len(key)+len(value) kept.
+	MaxTotalBytes int
+}
+
+// DefaultAttributeLimits returns the limits applied when none are configured.
+func DefaultAttributeLimits() AttributeLimits {
+	return AttributeLimits{
+		MaxCount:      DefaultMaxAttributeCount,
+		MaxTotalBytes: DefaultMaxAttributeTotalBytes,
+	}
+}
+
+// Limit returns attrs cut down to the limits, and whether anything was dropped.
+// Attributes are considered in key order so the kept subset is deterministic
+// across reports; an attribute that does not fit in the remaining size budget is
+// skipped, so one oversized value does not evict every key sorted after it.
+// attrs itself is never modified.
+func (l AttributeLimits) Limit(attrs map[string]string) (map[string]string, bool) {
+	if l.fits(attrs) {
+		return attrs, false
+	}
+
+	limited := make(map[string]string, min(len(attrs), max(l.MaxCount, 0)))
+	totalBytes := 0
+
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		if l.MaxCount > 0 && len(limited) >= l.MaxCount {
+			break
+		}
+
+		value := attrs[key]
+
+		size := len(key) + len(value)
+		if l.MaxTotalBytes > 0 && totalBytes+size > l.MaxTotalBytes {
+			continue
+		}
+
+		limited[key] = value
+		totalBytes += size
+	}
+
+	return limited, true
+}
+
+// LimitDescription truncates the description's attributes in place and sets
+// AttributesTruncated when either map exceeded the limits.
+func (l AttributeLimits) LimitDescription(desc *Description) {
+	if desc == nil {
+		return
+	}
+
+	identifying, identifyingTruncated := l.Limit(desc.IdentifyingAttributes)
+	nonIdentifying, nonIdentifyingTruncated := l.Limit(desc.NonIdentifyingAttributes)
+
+	desc.IdentifyingAttributes = identifying
+	desc.NonIdentifyingAttributes = nonIdentifying
+	desc.AttributesTruncated = identifyingTruncated || nonIdentifyingTruncated
+}
+
+func (l AttributeLimits) fits(attrs map[string]string) bool {
+	if l.MaxCount > 0 && len(attrs) > l.MaxCount {
+		return false
+	}
+
+	if l.MaxTotalBytes <= 0 {
+		return true
+	}
+
+	totalBytes := 0
+	for key, value := range attrs {
+		totalBytes += len(key) + len(value)
+	}
+
+	return totalBytes <= l.MaxTotalBytes
+}
@@ -0,0 +1,82 @@
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestAttributeLimits_Limit(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]string{"a": "1", "b": "22", "c": "333", "d": "4"}
+
+	tests := []struct {
+		name          string
+		limits        agent.AttributeLimits
+		want          map[string]string
+		wantTruncated bool
+	}{
+		{
+			name:          "within limits is untouched",
+			limits:        agent.AttributeLimits{MaxCount: 4, MaxTotalBytes: 64},
+			want:          attrs,
+			wantTruncated: false,
+		},
+		{
+			name:          "count keeps the first keys in order",
+			limits:        agent.AttributeLimits{MaxCount: 2, MaxTotalBytes: 0},
+			want:          map[string]string{"a": "1", "b": "22"},
+			wantTruncated: true,
+		},
+		{
+			name:          "an attribute too large for the remaining budget is skipped",
+			limits:        agent.AttributeLimits{MaxCount: 0, MaxTotalBytes: 7},
+			want:          map[string]string{"a": "1", "b": "22", "d": "4"},
+			wantTruncated: true,
+		},
+		{
+			name:          "non-positive limits disable truncation",
+			limits:        agent.AttributeLimits{MaxCount: 0, MaxTotalBytes: -1},
+			want:          attrs,
+			wantTruncated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, truncated := tt.limits.Limit(attrs)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+
+	assert.Len(t, attrs, 4, "the input map must not be modified")
+}
+
+func TestAttributeLimits_LimitDescription(t *testing.T) {
+	t.Parallel()
+
+	limits := agent.AttributeLimits{MaxCount: 1, MaxTotalBytes: 0}
+
+	desc := &agent.Description{
+		IdentifyingAttributes:    map[string]string{"service.name": "collector"},
+		NonIdentifyingAttributes: map[string]string{"os.type": "linux", "os.version": "6.1"},
+	}
+
+	limits.LimitDescription(desc)
+
+	assert.True(t, desc.AttributesTruncated)
+	assert.Equal(t, map[string]string{"service.name": "collector"}, desc.IdentifyingAttributes)
+	assert.Equal(t, map[string]string{"os.type": "linux"}, desc.NonIdentifyingAttributes)
+
+	// A later report within the limits clears the flag.
+	desc.NonIdentifyingAttributes = map[string]string{"os.type": "linux"}
+	limits.LimitDescription(desc)
+
+	assert.False(t, desc.AttributesTruncated)
+}
@@ -11,6 +11,15 @@ import (
 type Description struct {
 	IdentifyingAttributes    map[string]string
 	NonIdentifyingAttributes map[string]string
+
+	// RawIdentifyingAttributes and RawNonIdentifyingAttributes hold the attributes as
+	// the agent reported them when AttributeAliases renamed any of them; nil otherwise.
+	RawIdentifyingAttributes    map[string]string
+	RawNonIdentifyingAttributes map[string]string
+
+	// AttributesTruncated reports that the agent sent more attributes than
+	// AttributeLimits allow and some were dropped.
+	AttributesTruncated bool
 }
 
 // OS is a required field of AgentDescription
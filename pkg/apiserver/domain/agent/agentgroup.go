@@ -1,12 +1,21 @@
 package agentmodel
 
 import (
+	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 )
 
+const (
+	// MinAgentGroupPriority is the lowest priority an agent group may have.
+	MinAgentGroupPriority = 0
+	// MaxAgentGroupPriority is the highest priority an agent group may have.
+	MaxAgentGroupPriority = 1000
+)
+
 // Attributes represents a map of attributes for the agent group.
 type Attributes map[string]string
 
@@ -40,8 +49,11 @@ func NewAgentGroup(
 				IdentifyingAttributes:    nil,
 				NonIdentifyingAttributes: nil,
 			},
-			AgentRemoteConfigs:    nil,
-			AgentConnectionConfig: nil,
+			AgentRemoteConfigs:      nil,
+			AgentConnectionConfig:   nil,
+			Rollout:                 nil,
+			ExcludeBaseRemoteConfig: false,
+			PreviousRemoteConfigs:   nil,
 		},
 		Status: AgentGroupStatus{
 			NumAgents:             0,
@@ -68,6 +80,34 @@ func (ag *AgentGroup) HasAgentConnectionConfig() bool {
 	return ag.Spec.AgentConnectionConfig != nil
 }
 
+// ReferencesCertificate reports whether any of the agent group's connection settings uses
+// the named certificate. Certificates are looked up in the agent group's namespace.
+func (ag *AgentGroup) ReferencesCertificate(name string) bool {
+	config := ag.Spec.AgentConnectionConfig
+	if config == nil {
+		return false
+	}
+
+	names := []*string{}
+	if config.OpAMPConnection != nil {
+		names = append(names, config.OpAMPConnection.CertificateName)
+	}
+
+	for _, telemetry := range []*TelemetryConnectionSettings{config.OwnMetrics, config.OwnLogs, config.OwnTraces} {
+		if telemetry != nil {
+			names = append(names, telemetry.CertificateName)
+		}
+	}
+
+	for _, other := range config.OtherConnections {
+		names = append(names, other.CertificateName)
+	}
+
+	return slices.ContainsFunc(names, func(certificateName *string) bool {
+		return certificateName != nil && *certificateName == name
+	})
+}
+
 // AgentGroupMetadata represents metadata information for an agent group.
 type AgentGroupMetadata struct {
 	// Namespace is the namespace of the agent group.
@@ -84,6 +124,22 @@ type AgentGroupMetadata struct {
 	DeletedAt time.Time
 }
 
+// ValidatePriority checks that the group's priority is within the allowed range. An
+// out-of-range priority is well-formed but meaningless, so it is reported as
+// model.ErrUnprocessable.
+func (ag *AgentGroup) ValidatePriority() error {
+	priority := ag.Spec.Priority
+	if priority >= MinAgentGroupPriority && priority <= MaxAgentGroupPriority {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", model.ErrUnprocessable, &model.FieldError{
+		Field:  "spec.priority",
+		Value:  priority,
+		Reason: fmt.Sprintf("must be between %d and %d", MinAgentGroupPriority, MaxAgentGroupPriority),
+	})
+}
+
 // AgentGroupSpec represents the specification of an agent group.
 type AgentGroupSpec struct {
 	// Priority is the priority of the agent group.
@@ -98,6 +154,18 @@ type AgentGroupSpec struct {
 
 	// AgentConnection settings for agents in this group.
 	AgentConnectionConfig *AgentGroupConnectionConfig
+
+	// Rollout limits AgentRemoteConfigs to a subset of the group's agents while a change
+	// is canaried. Nil means every agent of the group receives them.
+	Rollout *AgentGroupRollout
+
+	// ExcludeBaseRemoteConfig keeps the server's base remote configs off the group's agents.
+	// An agent receives them only when none of its matching groups excludes them.
+	ExcludeBaseRemoteConfig bool
+
+	// PreviousRemoteConfigs are the remote configs the group had before AgentRemoteConfigs
+	// last changed, which RollbackRemoteConfigs restores. Nil when they never changed.
+	PreviousRemoteConfigs *AgentGroupPreviousRemoteConfigs
 }
 
 // AgentGroupAgentRemoteConfig represents a remote configuration for agents in the group.
@@ -0,0 +1,147 @@
+package agentmodel
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"time"
+)
+
+// AgentGroupConfigChange describes, key by key, how an AgentGroup's remote configs
+// changed in an update, so consumers can react to exactly what changed instead of
+// re-reading the whole group.
+//
+// A config key is the name the group lists the config under: the inline config name,
+// or the referenced AgentRemoteConfig name.
+type AgentGroupConfigChange struct {
+	// Namespace and Name identify the changed AgentGroup.
+	Namespace string
+	Name      string
+	// Selector is the group's selector after the change, i.e. the agents affected by it.
+	Selector AgentSelector
+	// SelectorChanged reports whether the update also changed the selector.
+	SelectorChanged bool
+	// AddedKeys, RemovedKeys and ModifiedKeys are the sorted config keys that were
+	// added, removed, or kept with different content.
+	AddedKeys    []string
+	RemovedKeys  []string
+	ModifiedKeys []string
+	// ChangedAt and ChangedBy record when and by whom the group was updated.
+	ChangedAt time.Time
+	ChangedBy string
+}
+
+// NewAgentGroupConfigChange diffs the remote configs of an AgentGroup before and after
+// an update.
+func NewAgentGroupConfigChange(
+	before, after *AgentGroup,
+	changedBy string,
+	changedAt time.Time,
+) *AgentGroupConfigChange {
+	beforeConfigs := agentGroupConfigsByKey(before)
+	afterConfigs := agentGroupConfigsByKey(after)
+
+	change := &AgentGroupConfigChange{
+		Namespace:       after.Metadata.Namespace,
+		Name:            after.Metadata.Name,
+		Selector:        after.Spec.Selector,
+		SelectorChanged: !sameAgentSelector(before.Spec.Selector, after.Spec.Selector),
+		AddedKeys:       nil,
+		RemovedKeys:     nil,
+		ModifiedKeys:    nil,
+		ChangedAt:       changedAt,
+		ChangedBy:       changedBy,
+	}
+
+	for key, afterConfig := range afterConfigs {
+		beforeConfig, ok := beforeConfigs[key]
+
+		switch {
+		case !ok:
+			change.AddedKeys = append(change.AddedKeys, key)
+		case !sameAgentGroupRemoteConfig(beforeConfig, afterConfig):
+			change.ModifiedKeys = append(change.ModifiedKeys, key)
+		}
+	}
+
+	for key := range beforeConfigs {
+		if _, ok := afterConfigs[key]; !ok {
+			change.RemovedKeys = append(change.RemovedKeys, key)
+		}
+	}
+
+	slices.Sort(change.AddedKeys)
+	slices.Sort(change.RemovedKeys)
+	slices.Sort(change.ModifiedKeys)
+
+	return change
+}
+
+// IsEmpty reports whether the update left both the configs and the selector untouched.
+func (c *AgentGroupConfigChange) IsEmpty() bool {
+	return !c.SelectorChanged &&
+		len(c.AddedKeys) == 0 && len(c.RemovedKeys) == 0 && len(c.ModifiedKeys) == 0
+}
+
+// ConfigKey returns the key the config is listed under in its group: the inline config
+// name, or the referenced resource name. It is empty for a malformed entry.
+func (c AgentGroupAgentRemoteConfig) ConfigKey() string {
+	switch {
+	case c.AgentRemoteConfigName != nil:
+		return *c.AgentRemoteConfigName
+	case c.AgentRemoteConfigRef != nil:
+		return *c.AgentRemoteConfigRef
+	default:
+		return ""
+	}
+}
+
+func agentGroupConfigsByKey(group *AgentGroup) map[string]AgentGroupAgentRemoteConfig {
+	configs := make(map[string]AgentGroupAgentRemoteConfig, len(group.Spec.AgentRemoteConfigs))
+
+	for _, config := range group.Spec.AgentRemoteConfigs {
+		key := config.ConfigKey()
+		if key == "" {
+			continue
+		}
+
+		configs[key] = config
+	}
+
+	return configs
+}
+
+func sameAgentGroupRemoteConfig(a, b AgentGroupAgentRemoteConfig) bool {
+	// Switching between an inline config and a reference of the same name is a change.
+	if (a.AgentRemoteConfigRef != nil) != (b.AgentRemoteConfigRef != nil) {
+		return false
+	}
+
+	if (a.AgentRemoteConfigSpec == nil) != (b.AgentRemoteConfigSpec == nil) {
+		return false
+	}
+
+	if a.AgentRemoteConfigSpec == nil {
+		return true
+	}
+
+	return a.AgentRemoteConfigSpec.ContentType == b.AgentRemoteConfigSpec.ContentType &&
+		bytes.Equal(a.AgentRemoteConfigSpec.Value, b.AgentRemoteConfigSpec.Value)
+}
+
+func sameAgentSelector(a, b AgentSelector) bool {
+	return a.CaseInsensitive == b.CaseInsensitive &&
+		maps.Equal(a.IdentifyingAttributes, b.IdentifyingAttributes) &&
+		maps.Equal(a.NonIdentifyingAttributes, b.NonIdentifyingAttributes) &&
+		sameStringSet(a.AbsentIdentifyingAttributes, b.AbsentIdentifyingAttributes) &&
+		sameStringSet(a.AbsentNonIdentifyingAttributes, b.AbsentNonIdentifyingAttributes) &&
+		slices.Equal(a.IdentifyingMatchExpressions, b.IdentifyingMatchExpressions) &&
+		slices.Equal(a.NonIdentifyingMatchExpressions, b.NonIdentifyingMatchExpressions)
+}
+
+func sameStringSet(a, b []string) bool {
+	a = slices.Sorted(slices.Values(a))
+	b = slices.Sorted(slices.Values(b))
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
@@ -0,0 +1,81 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestNewAgentGroupConfigChange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	name := "shared"
+	inline := agentmodel.AgentGroupAgentRemoteConfig{
+		AgentRemoteConfigName: &name,
+		AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{Value: []byte("a: 1"), ContentType: "text/yaml"},
+		AgentRemoteConfigRef:  nil,
+	}
+	ref := agentmodel.AgentGroupAgentRemoteConfig{
+		AgentRemoteConfigName: nil,
+		AgentRemoteConfigSpec: nil,
+		AgentRemoteConfigRef:  &name,
+	}
+
+	t.Run("switching an inline config to a reference of the same name is a modification", func(t *testing.T) {
+		t.Parallel()
+
+		before := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		before.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{inline}
+		after := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		after.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{ref}
+
+		change := agentmodel.NewAgentGroupConfigChange(before, after, "tester", now)
+
+		assert.Equal(t, []string{"shared"}, change.ModifiedKeys)
+		assert.Empty(t, change.AddedKeys)
+		assert.Empty(t, change.RemovedKeys)
+		assert.False(t, change.IsEmpty())
+	})
+
+	t.Run("a selector-only change is reported", func(t *testing.T) {
+		t.Parallel()
+
+		before := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		before.Spec.Selector.AbsentIdentifyingAttributes = []string{"a", "b"}
+		after := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		after.Spec.Selector.AbsentIdentifyingAttributes = []string{"b"}
+
+		change := agentmodel.NewAgentGroupConfigChange(before, after, "tester", now)
+
+		assert.True(t, change.SelectorChanged)
+		assert.False(t, change.IsEmpty())
+	})
+
+	t.Run("reordering absent keys is not a change", func(t *testing.T) {
+		t.Parallel()
+
+		before := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		before.Spec.Selector.AbsentIdentifyingAttributes = []string{"a", "b"}
+		after := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		after.Spec.Selector.AbsentIdentifyingAttributes = []string{"b", "a"}
+
+		assert.True(t, agentmodel.NewAgentGroupConfigChange(before, after, "tester", now).IsEmpty())
+	})
+
+	t.Run("toggling case-insensitive matching is a selector change", func(t *testing.T) {
+		t.Parallel()
+
+		before := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		after := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		after.Spec.Selector.CaseInsensitive = true
+
+		change := agentmodel.NewAgentGroupConfigChange(before, after, "tester", now)
+
+		assert.True(t, change.SelectorChanged)
+		assert.False(t, change.IsEmpty())
+	})
+}
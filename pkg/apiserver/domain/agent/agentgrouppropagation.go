@@ -0,0 +1,34 @@
+package agentmodel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AgentGroupPropagation summarizes one pass applying an AgentGroup to its matching agents.
+type AgentGroupPropagation struct {
+	// Updated is the number of agents whose spec or conditions changed and were saved.
+	Updated int
+	// Unchanged is the number of agents that already matched the group.
+	Unchanged int
+	// Failed lists the agents the group could not be applied to.
+	Failed []AgentPropagationFailure
+}
+
+// AgentPropagationFailure records why an AgentGroup could not be applied to an agent.
+type AgentPropagationFailure struct {
+	InstanceUID uuid.UUID
+	Err         error
+}
+
+// Err joins the errors of the failed agents, or returns nil when every agent succeeded.
+func (p *AgentGroupPropagation) Err() error {
+	errs := make([]error, 0, len(p.Failed))
+	for _, failure := range p.Failed {
+		errs = append(errs, fmt.Errorf("agent %s: %w", failure.InstanceUID, failure.Err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package agentmodel
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// ErrNoPreviousRemoteConfigs is returned when rolling back an agent group whose remote
+// configs never changed.
+var ErrNoPreviousRemoteConfigs = fmt.Errorf(
+	"agent group has no previous remote configs to roll back to: %w", model.ErrInvalidArgument)
+
+// AgentGroupPreviousRemoteConfigs holds the remote configs an agent group had before they
+// were last changed.
+type AgentGroupPreviousRemoteConfigs struct {
+	// AgentRemoteConfigs are the group's remote configs before the last change. Empty
+	// when the group had none.
+	AgentRemoteConfigs []AgentGroupAgentRemoteConfig
+}
+
+// RememberPreviousRemoteConfigs records the remote configs of previous, the group as it
+// was before this update, so the update can be rolled back. An update that leaves the
+// remote configs as they were keeps the ones remembered before it, so changing only the
+// selector or the priority does not lose the last config change.
+func (ag *AgentGroup) RememberPreviousRemoteConfigs(previous *AgentGroup) {
+	switch {
+	case previous == nil:
+		ag.Spec.PreviousRemoteConfigs = nil
+	case sameGroupRemoteConfigs(ag.Spec.AgentRemoteConfigs, previous.Spec.AgentRemoteConfigs):
+		ag.Spec.PreviousRemoteConfigs = previous.Spec.PreviousRemoteConfigs
+	default:
+		ag.Spec.PreviousRemoteConfigs = &AgentGroupPreviousRemoteConfigs{
+			AgentRemoteConfigs: previous.Spec.AgentRemoteConfigs,
+		}
+	}
+}
+
+// RollbackRemoteConfigs puts the group back on its previous remote configs, for every
+// agent at once: a rollout in progress is dropped. The configs being replaced become the
+// previous ones, so rolling back again re-applies them.
+func (ag *AgentGroup) RollbackRemoteConfigs() error {
+	if ag.Spec.PreviousRemoteConfigs == nil {
+		return ErrNoPreviousRemoteConfigs
+	}
+
+	current := ag.Spec.AgentRemoteConfigs
+
+	ag.Spec.AgentRemoteConfigs = ag.Spec.PreviousRemoteConfigs.AgentRemoteConfigs
+	ag.Spec.PreviousRemoteConfigs = &AgentGroupPreviousRemoteConfigs{AgentRemoteConfigs: current}
+	ag.Spec.Rollout = nil
+
+	return nil
+}
+
+func sameGroupRemoteConfigs(a, b []AgentGroupAgentRemoteConfig) bool {
+	return slices.EqualFunc(a, b, func(x, y AgentGroupAgentRemoteConfig) bool {
+		return equalStringPtr(x.AgentRemoteConfigName, y.AgentRemoteConfigName) &&
+			equalStringPtr(x.AgentRemoteConfigRef, y.AgentRemoteConfigRef) &&
+			sameRemoteConfigSpec(x.AgentRemoteConfigSpec, y.AgentRemoteConfigSpec)
+	})
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func sameRemoteConfigSpec(a, b *AgentRemoteConfigSpec) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.ContentType == b.ContentType && bytes.Equal(a.Value, b.Value)
+}
@@ -0,0 +1,220 @@
+package agentmodel
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/google/uuid"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+const (
+	// MaxRolloutPercentage is the rollout percentage that puts every agent of the group on
+	// its current remote configs. Advancing a rollout to it completes the rollout.
+	MaxRolloutPercentage = 100
+
+	// rolloutBuckets is the number of buckets agents are hashed into to pick a rollout's
+	// subset, fine enough for a count to be honoured on groups of a few thousand agents.
+	rolloutBuckets = 10000
+)
+
+// ErrNoRolloutInProgress is returned when advancing the rollout of an agent group that has none.
+var ErrNoRolloutInProgress = fmt.Errorf("agent group has no rollout in progress: %w", model.ErrInvalidArgument)
+
+// AgentGroupRollout limits the group's remote configs to a deterministic subset of its
+// agents, e.g. to canary a new config on 10% of the group before the rest receives it.
+// The subset is picked by hashing each agent's instance UID alone, so an agent keeps its
+// place across reconciles and servers, and growing the rollout only ever adds agents to it.
+type AgentGroupRollout struct {
+	// Percentage is the share of the group's agents, from 0 to 100, that receive the
+	// group's remote configs.
+	Percentage int
+	// Count is the number of the group's agents that receive the group's remote configs,
+	// instead of a percentage. It is resolved against the group's agent count when the
+	// rollout starts or advances.
+	Count int
+	// PreviousAgentRemoteConfigs are the remote configs the rest of the group stays on.
+	// They are the group's remote configs from before the rollout started.
+	PreviousAgentRemoteConfigs []AgentGroupAgentRemoteConfig
+	// BucketThreshold is how many of the rollout buckets are in the rollout, resolved from
+	// Percentage or Count when the rollout starts or advances. Fixing it then keeps the
+	// agents in a count rollout in place as the group grows or shrinks. Zero means it has
+	// not been resolved yet.
+	BucketThreshold int
+}
+
+// HasRollout reports whether a change of the group's remote configs is being rolled out.
+func (ag *AgentGroup) HasRollout() bool {
+	return ag.Spec.Rollout != nil
+}
+
+// ValidateRollout checks the group's rollout spec, if any.
+func (ag *AgentGroup) ValidateRollout() error {
+	if !ag.HasRollout() {
+		return nil
+	}
+
+	return validateRolloutTarget(ag.Spec.Rollout.Percentage, ag.Spec.Rollout.Count, "spec.rollout.")
+}
+
+// validateRolloutTarget checks a rollout percentage and count, reporting invalid ones
+// under fieldPrefix.
+func validateRolloutTarget(percentage, count int, fieldPrefix string) error {
+	var fieldErrs model.FieldErrors
+
+	if percentage < 0 || percentage > MaxRolloutPercentage {
+		fieldErrs = append(fieldErrs, &model.FieldError{
+			Field: fieldPrefix + "percentage", Value: percentage, Reason: "must be between 0 and 100",
+		})
+	}
+
+	if count < 0 {
+		fieldErrs = append(fieldErrs, &model.FieldError{
+			Field: fieldPrefix + "count", Value: count, Reason: "must not be negative",
+		})
+	}
+
+	if percentage != 0 && count != 0 {
+		fieldErrs = append(fieldErrs, &model.FieldError{
+			Field: fieldPrefix + "count", Value: count, Reason: "must not be set together with percentage",
+		})
+	}
+
+	return fieldErrs.Err()
+}
+
+// InheritRolloutBaseline records the remote configs the agents outside the rollout stay
+// on, taken from previous, the group as it was before this update. A rollout already in
+// progress keeps its baseline even when the remote configs change again, as the rest of
+// the group never received the configs being replaced. A group without a previous
+// version has no baseline, so the agents outside its rollout get no config from it.
+func (ag *AgentGroup) InheritRolloutBaseline(previous *AgentGroup) {
+	if !ag.HasRollout() {
+		return
+	}
+
+	switch {
+	case previous == nil:
+		ag.Spec.Rollout.PreviousAgentRemoteConfigs = nil
+	case previous.HasRollout():
+		ag.Spec.Rollout.PreviousAgentRemoteConfigs = previous.Spec.Rollout.PreviousAgentRemoteConfigs
+
+		// The agents in the rollout stay the same unless its target changes.
+		if ag.Spec.Rollout.Percentage == previous.Spec.Rollout.Percentage &&
+			ag.Spec.Rollout.Count == previous.Spec.Rollout.Count {
+			ag.Spec.Rollout.BucketThreshold = previous.Spec.Rollout.BucketThreshold
+		}
+	default:
+		ag.Spec.Rollout.PreviousAgentRemoteConfigs = previous.Spec.AgentRemoteConfigs
+	}
+}
+
+// AdvanceRollout moves the group's rollout to the given percentage or count. A rollout
+// only grows; to roll back, update the group's remote configs instead. Reaching
+// MaxRolloutPercentage completes the rollout, putting the whole group on its remote configs.
+// Otherwise the new target takes effect once ResolveRolloutThreshold is called.
+func (ag *AgentGroup) AdvanceRollout(percentage, count int) error {
+	if !ag.HasRollout() {
+		return ErrNoRolloutInProgress
+	}
+
+	err := validateRolloutTarget(percentage, count, "")
+	if err != nil {
+		return err
+	}
+
+	rollout := ag.Spec.Rollout
+
+	switch {
+	case percentage == 0 && count == 0:
+		return &model.FieldError{Field: "percentage", Value: percentage, Reason: "percentage or count is required"}
+	case count == 0 && percentage < rollout.Percentage:
+		return &model.FieldError{
+			Field: "percentage", Value: percentage, Reason: fmt.Sprintf("must not be below %d", rollout.Percentage),
+		}
+	case percentage == 0 && count < rollout.Count:
+		return &model.FieldError{Field: "count", Value: count, Reason: fmt.Sprintf("must not be below %d", rollout.Count)}
+	}
+
+	if percentage == MaxRolloutPercentage {
+		ag.Spec.Rollout = nil
+
+		return nil
+	}
+
+	rollout.Percentage = percentage
+	rollout.Count = count
+
+	return nil
+}
+
+// NeedsRolloutThreshold reports whether the group's rollout has a target whose bucket
+// threshold has not been resolved yet.
+func (ag *AgentGroup) NeedsRolloutThreshold() bool {
+	return ag.HasRollout() && ag.Spec.Rollout.BucketThreshold == 0 &&
+		(ag.Spec.Rollout.Percentage > 0 || ag.Spec.Rollout.Count > 0)
+}
+
+// ResolveRolloutThreshold fixes which buckets the rollout covers from its target, given the
+// number of agents the group has now. As a rollout only grows, the threshold never drops
+// below the one already resolved.
+func (ag *AgentGroup) ResolveRolloutThreshold(numAgents int) {
+	if !ag.HasRollout() {
+		return
+	}
+
+	rollout := ag.Spec.Rollout
+	rollout.BucketThreshold = max(rollout.BucketThreshold, rolloutThreshold(rollout.Percentage, rollout.Count, numAgents))
+}
+
+// IsInRollout reports whether the agent is in the subset of the group receiving its
+// remote configs. Every agent is when the group has no rollout.
+func (ag *AgentGroup) IsInRollout(instanceUID uuid.UUID) bool {
+	if !ag.HasRollout() {
+		return true
+	}
+
+	rollout := ag.Spec.Rollout
+
+	threshold := rollout.BucketThreshold
+	if ag.NeedsRolloutThreshold() {
+		// A rollout saved before its threshold was recorded follows the group's agent count.
+		threshold = rolloutThreshold(rollout.Percentage, rollout.Count, ag.Status.NumAgents)
+	}
+
+	return rolloutBucket(instanceUID) < threshold
+}
+
+// rolloutThreshold returns how many buckets a rollout of percentage, or of count out of
+// numAgents agents, covers. A count covering the whole group puts every bucket in it; a
+// count too small for a bucket of its own still gets one.
+func rolloutThreshold(percentage, count, numAgents int) int {
+	if count == 0 {
+		return percentage * rolloutBuckets / MaxRolloutPercentage
+	}
+
+	if numAgents <= count {
+		return rolloutBuckets
+	}
+
+	return max(count*rolloutBuckets/numAgents, 1)
+}
+
+// RemoteConfigsFor returns the remote configs the group offers the agent: its current
+// ones when the agent is in the rollout, the ones from before the rollout otherwise.
+func (ag *AgentGroup) RemoteConfigsFor(instanceUID uuid.UUID) []AgentGroupAgentRemoteConfig {
+	if ag.IsInRollout(instanceUID) {
+		return ag.Spec.AgentRemoteConfigs
+	}
+
+	return ag.Spec.Rollout.PreviousAgentRemoteConfigs
+}
+
+// rolloutBucket hashes an instance UID into one of rolloutBuckets buckets.
+func rolloutBucket(instanceUID uuid.UUID) int {
+	hash := fnv.New64a()
+	_, _ = hash.Write(instanceUID[:])
+
+	return int(hash.Sum64() % rolloutBuckets)
+}
@@ -0,0 +1,179 @@
+package agentmodel_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+func TestAgentGroup_InheritRolloutBaseline(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldRef, newRef, newerRef := "old", "new", "newer"
+
+	previous := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	previous.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{{AgentRemoteConfigRef: &oldRef}}
+
+	// Starting a rollout keeps the rest of the group on the configs it had.
+	updated := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	updated.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{{AgentRemoteConfigRef: &newRef}}
+	updated.Spec.Rollout = &agentmodel.AgentGroupRollout{Percentage: 10}
+	updated.InheritRolloutBaseline(previous)
+	assert.Equal(t, previous.Spec.AgentRemoteConfigs, updated.Spec.Rollout.PreviousAgentRemoteConfigs)
+
+	// Changing the configs again mid-rollout does not move the baseline.
+	again := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	again.Spec.AgentRemoteConfigs = []agentmodel.AgentGroupAgentRemoteConfig{{AgentRemoteConfigRef: &newerRef}}
+	again.Spec.Rollout = &agentmodel.AgentGroupRollout{Percentage: 20}
+	again.InheritRolloutBaseline(updated)
+	assert.Equal(t, previous.Spec.AgentRemoteConfigs, again.Spec.Rollout.PreviousAgentRemoteConfigs)
+
+	// The agents a rollout covers stay put unless its target changes.
+	updated.Spec.Rollout.BucketThreshold = 1000
+	kept := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	kept.Spec.Rollout = &agentmodel.AgentGroupRollout{Percentage: 10}
+	kept.InheritRolloutBaseline(updated)
+	assert.Equal(t, 1000, kept.Spec.Rollout.BucketThreshold)
+
+	again.InheritRolloutBaseline(updated)
+	assert.True(t, again.NeedsRolloutThreshold())
+
+	// A new group has nothing to fall back to.
+	created := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	created.Spec.Rollout = &agentmodel.AgentGroupRollout{
+		Percentage:                 10,
+		PreviousAgentRemoteConfigs: previous.Spec.AgentRemoteConfigs,
+	}
+	created.InheritRolloutBaseline(nil)
+	assert.Nil(t, created.Spec.Rollout.PreviousAgentRemoteConfigs)
+}
+
+func TestAgentGroup_AdvanceRollout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newGroup := func(rollout *agentmodel.AgentGroupRollout) *agentmodel.AgentGroup {
+		group := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+		group.Spec.Rollout = rollout
+
+		return group
+	}
+
+	t.Run("a group without a rollout cannot be advanced", func(t *testing.T) {
+		t.Parallel()
+
+		err := newGroup(nil).AdvanceRollout(50, 0)
+		require.ErrorIs(t, err, agentmodel.ErrNoRolloutInProgress)
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+	})
+
+	t.Run("a rollout only grows", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(&agentmodel.AgentGroupRollout{Percentage: 30})
+
+		var fieldErr *model.FieldError
+
+		require.ErrorAs(t, group.AdvanceRollout(20, 0), &fieldErr)
+		assert.Equal(t, "percentage", fieldErr.Field)
+		require.ErrorAs(t, group.AdvanceRollout(0, 0), &fieldErr)
+		require.ErrorIs(t, group.AdvanceRollout(101, 0), model.ErrInvalidArgument)
+
+		require.NoError(t, group.AdvanceRollout(60, 0))
+		assert.Equal(t, 60, group.Spec.Rollout.Percentage)
+	})
+
+	t.Run("a percentage can be switched to a count", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(&agentmodel.AgentGroupRollout{Percentage: 10})
+
+		require.NoError(t, group.AdvanceRollout(0, 25))
+		assert.Equal(t, 0, group.Spec.Rollout.Percentage)
+		assert.Equal(t, 25, group.Spec.Rollout.Count)
+	})
+
+	t.Run("reaching 100 percent completes the rollout", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(&agentmodel.AgentGroupRollout{Percentage: 10})
+
+		require.NoError(t, group.AdvanceRollout(agentmodel.MaxRolloutPercentage, 0))
+		assert.False(t, group.HasRollout())
+	})
+}
+
+func TestAgentGroup_IsInRollout_KeepsCountRolloutAsGroupGrows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	agents := make([]uuid.UUID, 1000)
+
+	for index := range agents {
+		agents[index] = uuid.NewSHA1(uuid.NameSpaceOID, fmt.Appendf(nil, "agent-%d", index))
+	}
+
+	inRollout := func(group *agentmodel.AgentGroup) []uuid.UUID {
+		var members []uuid.UUID
+
+		for _, instanceUID := range agents[:100] {
+			if group.IsInRollout(instanceUID) {
+				members = append(members, instanceUID)
+			}
+		}
+
+		return members
+	}
+
+	group := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	group.Spec.Rollout = &agentmodel.AgentGroupRollout{Count: 10}
+	require.True(t, group.NeedsRolloutThreshold())
+
+	// The rollout starts on a group of 100 agents.
+	group.ResolveRolloutThreshold(100)
+	require.False(t, group.NeedsRolloutThreshold())
+
+	canaries := inRollout(group)
+	require.NotEmpty(t, canaries)
+	assert.Less(t, len(canaries), 100, "a count rollout covers part of the group")
+
+	// The group grows tenfold, and its statistics go cold, without the canaries changing.
+	for _, numAgents := range []int{1000, 0} {
+		group.Status.NumAgents = numAgents
+		assert.Equal(t, canaries, inRollout(group))
+	}
+
+	// Advancing against the larger group never drops a canary.
+	require.NoError(t, group.AdvanceRollout(0, 20))
+	group.ResolveRolloutThreshold(1000)
+	assert.Subset(t, inRollout(group), canaries)
+}
+
+func TestAgentGroup_ValidateRollout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	group := agentmodel.NewAgentGroup("default", "g", nil, now, "tester")
+	require.NoError(t, group.ValidateRollout())
+
+	group.Spec.Rollout = &agentmodel.AgentGroupRollout{Percentage: 150, Count: -1}
+
+	var fieldErrs model.FieldErrors
+
+	require.ErrorAs(t, group.ValidateRollout(), &fieldErrs)
+
+	fields := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		fields = append(fields, fieldErr.Field)
+	}
+
+	assert.Equal(t, []string{"spec.rollout.percentage", "spec.rollout.count", "spec.rollout.count"}, fields)
+}
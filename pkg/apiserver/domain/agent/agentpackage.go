@@ -1,6 +1,8 @@
 package agentmodel
 
 import (
+	"crypto/sha256"
+	"net/url"
 	"time"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
@@ -27,6 +29,37 @@ func (a *AgentPackage) MarkAsCreated(createdAt time.Time, createdBy string) {
 	})
 }
 
+// Validate checks the agent package a client submitted. It reports every invalid field
+// together as model.FieldErrors rather than stopping at the first.
+func (a *AgentPackage) Validate() error {
+	var fieldErrs model.FieldErrors
+
+	if a.Metadata.Name == "" {
+		fieldErrs = append(fieldErrs, &model.FieldError{
+			Field: "metadata.name", Value: a.Metadata.Name, Reason: "must not be empty",
+		})
+	}
+
+	if a.Spec.DownloadURL != "" {
+		downloadURL, err := url.Parse(a.Spec.DownloadURL)
+		if err != nil || (downloadURL.Scheme != "http" && downloadURL.Scheme != "https") || downloadURL.Host == "" {
+			fieldErrs = append(fieldErrs, &model.FieldError{
+				Field: "spec.downloadUrl", Value: a.Spec.DownloadURL, Reason: "must be an absolute http or https URL",
+			})
+		}
+	}
+
+	if len(a.Spec.ContentHash) != 0 && len(a.Spec.ContentHash) != sha256.Size {
+		fieldErrs = append(fieldErrs, &model.FieldError{
+			Field:  "spec.contentHash",
+			Value:  a.Spec.ContentHash,
+			Reason: "must be a SHA-256 digest of 32 bytes",
+		})
+	}
+
+	return fieldErrs.Err()
+}
+
 // ApplyUpdate copies the mutable fields from incoming into the receiver while
 // preserving immutable identity and lifecycle state (Name, Namespace, CreatedAt,
 // DeletedAt, and Status conditions). Callers should load the stored agent
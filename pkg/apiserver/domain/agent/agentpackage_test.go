@@ -1,6 +1,7 @@
 package agentmodel_test
 
 import (
+	"crypto/sha256"
 	"testing"
 	"time"
 
@@ -29,6 +30,52 @@ func TestAgentPackage_MarkAsCreated(t *testing.T) {
 	assert.Equal(t, "tester", pkg.Status.Conditions[0].Reason)
 }
 
+func TestAgentPackage_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid package", func(t *testing.T) {
+		t.Parallel()
+
+		digest := sha256.Sum256([]byte("collector"))
+		pkg := &agentmodel.AgentPackage{
+			Metadata: agentmodel.AgentPackageMetadata{Name: "pkg", Namespace: "default"},
+			Spec: agentmodel.AgentPackageSpec{
+				DownloadURL: "https://downloads.example.com/otelcol",
+				ContentHash: digest[:],
+			},
+			Status: agentmodel.AgentPackageStatus{},
+		}
+
+		require.NoError(t, pkg.Validate())
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		t.Parallel()
+
+		pkg := &agentmodel.AgentPackage{
+			Metadata: agentmodel.AgentPackageMetadata{Namespace: "default"},
+			Spec: agentmodel.AgentPackageSpec{
+				DownloadURL: "downloads.example.com/otelcol",
+				ContentHash: []byte("short"),
+			},
+			Status: agentmodel.AgentPackageStatus{},
+		}
+
+		err := pkg.Validate()
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+
+		var fieldErrs model.FieldErrors
+		require.ErrorAs(t, err, &fieldErrs)
+
+		fields := make([]string, 0, len(fieldErrs))
+		for _, fieldErr := range fieldErrs {
+			fields = append(fields, fieldErr.Field)
+		}
+
+		assert.Equal(t, []string{"metadata.name", "spec.downloadUrl", "spec.contentHash"}, fields)
+	})
+}
+
 func TestAgentPackage_ApplyUpdate_PreservesIdentityAndLifecycle(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,24 @@
+package agentmodel
+
+// MaxAttributeValueLimit caps how many observed values an attribute catalog lists per key.
+const MaxAttributeValueLimit = 100
+
+// AttributeCatalog lists the distinct attribute keys reported across all agents, to help
+// operators build selectors.
+type AttributeCatalog struct {
+	// IdentifyingAttributes are the identifying attribute keys, sorted by key.
+	IdentifyingAttributes []AttributeCatalogEntry
+	// NonIdentifyingAttributes are the non-identifying attribute keys, sorted by key.
+	NonIdentifyingAttributes []AttributeCatalogEntry
+}
+
+// AttributeCatalogEntry is an attribute key with a sample of the values agents report for it.
+type AttributeCatalogEntry struct {
+	// Key is the attribute key.
+	Key string
+	// Values are the smallest distinct values reported for the key, sorted, up to the
+	// requested limit. It is empty when no values were requested.
+	Values []string
+	// ValuesTruncated reports that more distinct values were reported than Values lists.
+	ValuesTruncated bool
+}
@@ -0,0 +1,28 @@
+package agentmodel
+
+const (
+	// DefaultAvailableComponentsMaxDepth is the default number of levels of an agent's
+	// available components tree that are stored, the top-level components being level 1.
+	DefaultAvailableComponentsMaxDepth = 16
+	// DefaultAvailableComponentsMaxNodes is the default number of components stored across
+	// all levels of an agent's available components tree.
+	DefaultAvailableComponentsMaxNodes = 4096
+)
+
+// AvailableComponentsLimits bounds the available components tree stored on an agent. A
+// non-positive MaxDepth or MaxNodes disables that check.
+type AvailableComponentsLimits struct {
+	// MaxDepth is the deepest level of the tree kept; sub-components below it are dropped.
+	MaxDepth int
+	// MaxNodes is the number of components kept across all levels. Shallower components
+	// are kept first, so a wide or deep subtree cannot crowd out the top-level components.
+	MaxNodes int
+}
+
+// DefaultAvailableComponentsLimits returns the limits applied when none are configured.
+func DefaultAvailableComponentsLimits() AvailableComponentsLimits {
+	return AvailableComponentsLimits{
+		MaxDepth: DefaultAvailableComponentsMaxDepth,
+		MaxNodes: DefaultAvailableComponentsMaxNodes,
+	}
+}
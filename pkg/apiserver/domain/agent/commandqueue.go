@@ -0,0 +1,47 @@
+package agentmodel
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCommandQueueFull is returned when a command is queued for an agent that already has
+// as many pending commands as allowed, typically because the agent is offline and does
+// not drain its queue.
+var ErrCommandQueueFull = errors.New("agent command queue is full")
+
+// CommandQueueSummary totals the command queues of the agents of every namespace.
+type CommandQueueSummary struct {
+	// NumPendingCommands is the number of commands queued for all agents that they have not
+	// acted on yet, as counted by Agent.PendingCommandCount.
+	NumPendingCommands int64
+	// NumFullQueues is the number of agents whose queue holds as many commands as allowed,
+	// so that further ones are rejected with ErrCommandQueueFull.
+	NumFullQueues int64
+}
+
+// PendingCommandCount returns the number of commands queued for the agent that it has not
+// acted on yet: one per outstanding report request plus a pending restart.
+func (a *Agent) PendingCommandCount() int {
+	count := len(a.Spec.PendingReports)
+	if a.ShouldBeRestarted() {
+		count++
+	}
+
+	return count
+}
+
+// EnsureCommandQueueCapacity returns ErrCommandQueueFull when queueing one more command
+// would exceed maxPending. A maxPending of 0 or less means the queue is unbounded.
+func (a *Agent) EnsureCommandQueueCapacity(maxPending int) error {
+	if maxPending <= 0 {
+		return nil
+	}
+
+	pending := a.PendingCommandCount()
+	if pending >= maxPending {
+		return fmt.Errorf("%w: %d of %d commands pending", ErrCommandQueueFull, pending, maxPending)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package agentmodel
+
+import "time"
+
+// AgentConnectionState is the connection state of an agent as shown to users.
+type AgentConnectionState string
+
+const (
+	// AgentConnectionStateConnected means the agent is connected and reporting.
+	AgentConnectionStateConnected AgentConnectionState = "Connected"
+	// AgentConnectionStateGrace means the agent's connection was lost but its disconnect
+	// grace period has not elapsed yet. The agent still counts as connected so a brief
+	// network blip does not flip it to disconnected.
+	AgentConnectionStateGrace AgentConnectionState = "Grace"
+	// AgentConnectionStateDisconnected means the agent is not connected.
+	AgentConnectionStateDisconnected AgentConnectionState = "Disconnected"
+)
+
+// ConnectionStateAt returns the agent's connection state at now. An agent in its
+// disconnect grace period is connected as far as IsConnectedAt is concerned.
+func (a *Agent) ConnectionStateAt(now time.Time, staleness time.Duration) AgentConnectionState {
+	switch {
+	case !a.IsConnectedAt(now, staleness):
+		return AgentConnectionStateDisconnected
+	case a.IsInDisconnectGrace():
+		return AgentConnectionStateGrace
+	default:
+		return AgentConnectionStateConnected
+	}
+}
+
+// IsInDisconnectGrace reports whether the agent's connection was lost and it has not
+// been marked disconnected or reconnected since.
+func (a *Agent) IsInDisconnectGrace() bool {
+	return a.Status.Connected && !a.Status.ConnectionLostAt.IsZero()
+}
+
+// RecordConnectionLostAt starts the agent's disconnect grace period at now. The agent
+// stays connected until FinishDisconnectGrace marks it disconnected, unless it reports
+// again first. A grace period already running keeps its original start.
+func (a *Agent) RecordConnectionLostAt(now time.Time) {
+	if a.Status.ConnectionLostAt.IsZero() {
+		a.Status.ConnectionLostAt = now
+	}
+}
+
+// FinishDisconnectGrace marks the agent disconnected when its grace period has elapsed
+// at now, reporting whether it did. The connection session is closed at the time the
+// connection was lost, not when the grace period ran out.
+func (a *Agent) FinishDisconnectGrace(now time.Time, grace time.Duration) bool {
+	if !a.IsInDisconnectGrace() || now.Sub(a.Status.ConnectionLostAt) < grace {
+		return false
+	}
+
+	a.RecordDisconnectedAt(a.Status.ConnectionLostAt)
+
+	return true
+}
@@ -0,0 +1,66 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgent_DisconnectGrace(t *testing.T) {
+	t.Parallel()
+
+	const grace = 30 * time.Second
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	lostAt := start.Add(10 * time.Second)
+
+	newLostAgent := func() *agentmodel.Agent {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.UpdateLastCommunicationInfo(start, nil)
+		agent.RecordConnectionLostAt(lostAt)
+
+		return agent
+	}
+
+	t.Run("stays connected within the grace period", func(t *testing.T) {
+		t.Parallel()
+
+		agent := newLostAgent()
+		now := lostAt.Add(grace - time.Second)
+
+		assert.False(t, agent.FinishDisconnectGrace(now, grace))
+		assert.True(t, agent.IsConnectedAt(now, agentmodel.DefaultConnectionStaleness))
+		assert.Equal(t, agentmodel.AgentConnectionStateGrace,
+			agent.ConnectionStateAt(now, agentmodel.DefaultConnectionStaleness))
+	})
+
+	t.Run("disconnects at the time the connection was lost once it elapses", func(t *testing.T) {
+		t.Parallel()
+
+		agent := newLostAgent()
+		now := lostAt.Add(grace)
+
+		assert.True(t, agent.FinishDisconnectGrace(now, grace))
+		assert.False(t, agent.IsConnectedAt(now, agentmodel.DefaultConnectionStaleness))
+		assert.Equal(t, agentmodel.AgentConnectionStateDisconnected,
+			agent.ConnectionStateAt(now, agentmodel.DefaultConnectionStaleness))
+		assert.Equal(t, lostAt, agent.Status.ConnectionStats.LastDisconnectedAt)
+		assert.True(t, agent.Status.ConnectionLostAt.IsZero())
+	})
+
+	t.Run("reporting again ends the grace period", func(t *testing.T) {
+		t.Parallel()
+
+		agent := newLostAgent()
+		reconnectedAt := lostAt.Add(time.Second)
+		agent.UpdateLastCommunicationInfo(reconnectedAt, nil)
+
+		assert.False(t, agent.FinishDisconnectGrace(lostAt.Add(grace), grace))
+		assert.Equal(t, agentmodel.AgentConnectionStateConnected,
+			agent.ConnectionStateAt(reconnectedAt, agentmodel.DefaultConnectionStaleness))
+	})
+}
@@ -0,0 +1,133 @@
+package agentmodel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model/vo"
+)
+
+// AgentConditionTypeConnectionSettingsDrift records whether the connection settings the
+// agent last reported on match the ones the server offers it.
+const AgentConditionTypeConnectionSettingsDrift AgentConditionType = "ConnectionSettingsDrift"
+
+// connectionSettingsOfferPrefix is the prefix shared by the connection-settings Offer* names.
+const connectionSettingsOfferPrefix = "connectionSettings."
+
+// OfferedConnectionSettingsHash returns the hash of the connection settings the server
+// offers the agent, exactly as it is sent in ConnectionSettingsOffers.hash. It is nil when
+// nothing is offered, either because no settings are assigned or because the agent lacks
+// the capability for every assigned one.
+//
+// When some settings are withheld the hash is derived from the full settings' hash and the
+// withheld offer names rather than from the filtered settings, so it stays stable across
+// builds and still changes when a capability flip changes what is withheld.
+func (a *Agent) OfferedConnectionSettingsHash() ([]byte, error) {
+	connectionInfo := a.Spec.ConnectionInfo
+	if !connectionInfo.HasConnectionSettings() {
+		return nil, nil
+	}
+
+	var withheld []string
+
+	for _, skip := range a.SkippedOffers() {
+		if strings.HasPrefix(skip.Offer, connectionSettingsOfferPrefix) {
+			withheld = append(withheld, skip.Offer)
+		}
+	}
+
+	if len(withheld) == 0 {
+		return connectionInfo.Hash.Bytes(), nil
+	}
+
+	if len(withheld) == offeredConnectionSettingsCount(connectionInfo) {
+		return nil, nil
+	}
+
+	hash, err := vo.NewHashFromAny(struct {
+		Settings []byte
+		Withheld []string
+	}{
+		Settings: connectionInfo.Hash.Bytes(),
+		Withheld: withheld,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute offered connection settings hash: %w", err)
+	}
+
+	return hash.Bytes(), nil
+}
+
+// AcceptedOfferedConnectionSettings reports whether the agent applied the connection
+// settings the server currently offers it: it reported APPLIED for exactly the hash of
+// OfferedConnectionSettingsHash. An agent offered nothing has accepted nothing.
+func (a *Agent) AcceptedOfferedConnectionSettings() (bool, error) {
+	offered, err := a.OfferedConnectionSettingsHash()
+	if err != nil {
+		return false, err
+	}
+
+	status := a.Status.ConnectionSettingsStatus
+
+	return offered != nil && status.Status == ConnectionSettingsStatusApplied &&
+		bytes.Equal(offered, status.LastConnectionSettingsHash), nil
+}
+
+// RecordConnectionSettingsDrift compares the hash the agent reported in its connection
+// settings status with OfferedConnectionSettingsHash and reflects the result onto the
+// ConnectionSettingsDrift condition. It reports whether the condition changed.
+//
+// Nothing is recorded until the agent reports a hash. Once it has, the condition is True
+// while the hashes differ and False once they match or nothing is offered any more.
+func (a *Agent) RecordConnectionSettingsDrift(now time.Time, triggeredBy string) (bool, error) {
+	reported := a.Status.ConnectionSettingsStatus.LastConnectionSettingsHash
+	prev := a.GetCondition(AgentConditionTypeConnectionSettingsDrift)
+
+	offered, err := a.OfferedConnectionSettingsHash()
+	if err != nil {
+		return false, err
+	}
+
+	if len(reported) == 0 || (offered == nil && prev == nil) {
+		return false, nil
+	}
+
+	status := AgentConditionStatusFalse
+	message := "agent reports the offered connection settings"
+
+	switch {
+	case offered == nil:
+		message = "no connection settings are offered"
+	case !bytes.Equal(offered, reported):
+		status = AgentConditionStatusTrue
+		message = fmt.Sprintf("agent reports connection settings hash %s, server offers %s",
+			hex.EncodeToString(reported), hex.EncodeToString(offered))
+	}
+
+	a.SetConditionAt(AgentConditionTypeConnectionSettingsDrift, status, now, triggeredBy, message)
+
+	return prev == nil || prev.Status != status || prev.Message != message, nil
+}
+
+// offeredConnectionSettingsCount counts the connection-settings offers that are assigned,
+// counted the same way SkippedOffers counts the withheld ones.
+func offeredConnectionSettingsCount(connectionInfo *ConnectionInfo) int {
+	count := 0
+
+	for _, assigned := range []bool{
+		connectionInfo.OpAMP().HasEndpoint(),
+		connectionInfo.OwnMetrics().HasEndpoint(),
+		connectionInfo.OwnLogs().HasEndpoint(),
+		connectionInfo.OwnTraces().HasEndpoint(),
+		len(connectionInfo.OtherConnections()) > 0,
+	} {
+		if assigned {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,67 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestAgent_RecordConnectionSettingsDrift(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newAgent := func(t *testing.T) *agentmodel.Agent {
+		t.Helper()
+
+		connectionInfo, err := agentmodel.NewConnectionInfo(
+			&agentmodel.AgentOpAMPConnectionSettings{DestinationEndpoint: "wss://opamp.example/v1/opamp"},
+			nil, nil, nil, nil,
+		)
+		require.NoError(t, err)
+
+		capabilities := agent.Capabilities(agent.AgentCapabilityAcceptsOpAMPConnectionSettings)
+		a := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+		a.Spec.ConnectionInfo = connectionInfo
+
+		return a
+	}
+
+	t.Run("nothing is recorded before the agent reports a hash", func(t *testing.T) {
+		t.Parallel()
+
+		a := newAgent(t)
+
+		changed, err := a.RecordConnectionSettingsDrift(now, "agent")
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Nil(t, a.GetCondition(agentmodel.AgentConditionTypeConnectionSettingsDrift))
+	})
+
+	t.Run("drift clears once nothing is offered", func(t *testing.T) {
+		t.Parallel()
+
+		a := newAgent(t)
+		a.Status.ConnectionSettingsStatus.LastConnectionSettingsHash = []byte("stale")
+
+		changed, err := a.RecordConnectionSettingsDrift(now, "agent")
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, agentmodel.AgentConditionStatusTrue,
+			a.GetCondition(agentmodel.AgentConditionTypeConnectionSettingsDrift).Status)
+
+		a.Spec.ConnectionInfo = nil
+
+		changed, err = a.RecordConnectionSettingsDrift(now, "agent")
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, agentmodel.AgentConditionStatusFalse,
+			a.GetCondition(agentmodel.AgentConditionTypeConnectionSettingsDrift).Status)
+	})
+}
@@ -0,0 +1,83 @@
+package agentmodel
+
+import "strings"
+
+// DirectRemoteConfigKeyPrefix prefixes the key of every remote config set on an agent
+// directly rather than derived from an agent group, so agent group propagation can tell
+// the two apart and keep direct configs when it recomputes the agent's remote config.
+const DirectRemoteConfigKeyPrefix = "@agent/"
+
+// DirectRemoteConfigKey returns the key a direct remote config with the given name is
+// delivered to the agent under.
+func DirectRemoteConfigKey(name string) string {
+	return DirectRemoteConfigKeyPrefix + name
+}
+
+// IsDirectRemoteConfigKey reports whether key belongs to a direct remote config.
+func IsDirectRemoteConfigKey(key string) bool {
+	return strings.HasPrefix(key, DirectRemoteConfigKeyPrefix)
+}
+
+// DirectRemoteConfigs returns the remote configs set on the agent directly, keyed by name
+// without DirectRemoteConfigKeyPrefix.
+func (a *Agent) DirectRemoteConfigs() map[string]AgentConfigFile {
+	configs := make(map[string]AgentConfigFile)
+
+	if a.Spec.RemoteConfig == nil {
+		return configs
+	}
+
+	for key, file := range a.Spec.RemoteConfig.ConfigMap.ConfigMap {
+		if name, ok := strings.CutPrefix(key, DirectRemoteConfigKeyPrefix); ok {
+			configs[name] = file
+		}
+	}
+
+	return configs
+}
+
+// SetDirectRemoteConfigs replaces the remote configs set on the agent directly with the
+// given ones, keyed by name, leaving the configs derived from agent groups as they are.
+// An empty map removes every direct config.
+func (a *Agent) SetDirectRemoteConfigs(configs map[string]AgentConfigFile) {
+	desired := make(map[string]AgentConfigFile, len(configs))
+
+	if a.Spec.RemoteConfig != nil {
+		for key, file := range a.Spec.RemoteConfig.ConfigMap.ConfigMap {
+			if !IsDirectRemoteConfigKey(key) {
+				desired[key] = file
+			}
+		}
+	}
+
+	for name, file := range configs {
+		desired[DirectRemoteConfigKey(name)] = file
+	}
+
+	if len(desired) == 0 {
+		a.Spec.RemoteConfig = nil
+
+		return
+	}
+
+	a.Spec.RemoteConfig = &AgentSpecRemoteConfig{
+		ConfigMap: AgentConfigMap{
+			ConfigMap: desired,
+		},
+	}
+}
+
+// KeepDirectRemoteConfigs adds the agent's direct remote configs to desired, the remote
+// config about to replace the agent's, under their keys. A direct config wins over a
+// group-derived config under the same key.
+func (a *Agent) KeepDirectRemoteConfigs(desired map[string]AgentConfigFile) {
+	if a.Spec.RemoteConfig == nil {
+		return
+	}
+
+	for key, file := range a.Spec.RemoteConfig.ConfigMap.ConfigMap {
+		if IsDirectRemoteConfigKey(key) {
+			desired[key] = file
+		}
+	}
+}
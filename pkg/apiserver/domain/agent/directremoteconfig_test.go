@@ -0,0 +1,68 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgent_SetDirectRemoteConfigs(t *testing.T) {
+	t.Parallel()
+
+	groupConfig := agentmodel.AgentConfigFile{Body: []byte("receivers: {}"), ContentType: "text/yaml"}
+	debug := agentmodel.AgentConfigFile{Body: []byte("log_level: debug"), ContentType: "text/yaml"}
+	sampling := agentmodel.AgentConfigFile{Body: []byte("sampling: 10"), ContentType: "text/yaml"}
+
+	newAgent := func(t *testing.T) *agentmodel.Agent {
+		t.Helper()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		require.NoError(t, agent.ApplyRemoteConfig("production/collector", groupConfig))
+
+		return agent
+	}
+
+	t.Run("replaces direct configs and keeps group-derived ones", func(t *testing.T) {
+		t.Parallel()
+
+		agent := newAgent(t)
+		agent.SetDirectRemoteConfigs(map[string]agentmodel.AgentConfigFile{"debug": debug})
+		agent.SetDirectRemoteConfigs(map[string]agentmodel.AgentConfigFile{"sampling": sampling})
+
+		assert.Equal(t, map[string]agentmodel.AgentConfigFile{
+			"production/collector": groupConfig,
+			"@agent/sampling":      sampling,
+		}, agent.Spec.RemoteConfig.ConfigMap.ConfigMap)
+		assert.Equal(t, map[string]agentmodel.AgentConfigFile{"sampling": sampling}, agent.DirectRemoteConfigs())
+	})
+
+	t.Run("an empty map removes direct configs", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.SetDirectRemoteConfigs(map[string]agentmodel.AgentConfigFile{"debug": debug})
+		agent.SetDirectRemoteConfigs(nil)
+
+		assert.Nil(t, agent.Spec.RemoteConfig)
+		assert.Empty(t, agent.DirectRemoteConfigs())
+	})
+
+	t.Run("direct configs are kept over group-derived ones", func(t *testing.T) {
+		t.Parallel()
+
+		agent := newAgent(t)
+		agent.SetDirectRemoteConfigs(map[string]agentmodel.AgentConfigFile{"debug": debug})
+
+		desired := map[string]agentmodel.AgentConfigFile{
+			"production/collector": groupConfig,
+			"@agent/debug":         sampling,
+		}
+		agent.KeepDirectRemoteConfigs(desired)
+
+		assert.Equal(t, debug, desired["@agent/debug"])
+	})
+}
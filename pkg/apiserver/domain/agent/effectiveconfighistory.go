@@ -0,0 +1,179 @@
+package agentmodel
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultEffectiveConfigHistoryMaxEntries is the default number of effective-config
+	// snapshots kept per agent.
+	DefaultEffectiveConfigHistoryMaxEntries = 10
+	// DefaultEffectiveConfigHistoryMaxTotalBytes is the default maximum summed size of the
+	// config files kept across an agent's snapshots.
+	DefaultEffectiveConfigHistoryMaxTotalBytes = 1024 * 1024
+)
+
+// EffectiveConfigHistoryLimits bounds the effective-config history stored on an agent.
+// A non-positive MaxEntries disables the history; a non-positive MaxTotalBytes disables
+// the size check.
+type EffectiveConfigHistoryLimits struct {
+	// MaxEntries is the maximum number of snapshots kept.
+	MaxEntries int
+	// MaxTotalBytes is the maximum of the summed Size of the snapshots kept.
+	MaxTotalBytes int
+}
+
+// DefaultEffectiveConfigHistoryLimits returns the limits applied when none are configured.
+func DefaultEffectiveConfigHistoryLimits() EffectiveConfigHistoryLimits {
+	return EffectiveConfigHistoryLimits{
+		MaxEntries:    DefaultEffectiveConfigHistoryMaxEntries,
+		MaxTotalBytes: DefaultEffectiveConfigHistoryMaxTotalBytes,
+	}
+}
+
+// EffectiveConfigSnapshot is an effective config the agent reported, and when it
+// started reporting it.
+type EffectiveConfigSnapshot struct {
+	// ReportedAt is when the agent first reported this config.
+	ReportedAt time.Time
+	// Hash identifies the config content, so an identical report can be recognized even
+	// when the snapshot is truncated.
+	Hash []byte
+	// Truncated is true when the config alone exceeded the size limit and its file
+	// bodies were dropped; file names and content types are still recorded.
+	Truncated bool
+	// Config is the reported effective config.
+	Config AgentEffectiveConfig
+}
+
+// Size is the summed size of the snapshot's file names, content types and bodies.
+func (s *EffectiveConfigSnapshot) Size() int {
+	return effectiveConfigSize(&s.Config)
+}
+
+// RecordEffectiveConfig appends config to the agent's effective-config history when it
+// differs from the newest snapshot, then drops the oldest snapshots until the history
+// fits the limits. Identical reports do not add entries. It reports whether the history
+// changed.
+func (a *Agent) RecordEffectiveConfig(
+	config *AgentEffectiveConfig,
+	now time.Time,
+	limits EffectiveConfigHistoryLimits,
+) bool {
+	if config == nil || limits.MaxEntries <= 0 {
+		return false
+	}
+
+	hash := hashEffectiveConfig(config)
+
+	history := a.Status.EffectiveConfigHistory
+	if len(history) == 0 && len(config.ConfigMap.ConfigMap) == 0 {
+		return false
+	}
+
+	if len(history) > 0 && slices.Equal(history[len(history)-1].Hash, hash) {
+		return false
+	}
+
+	snapshot := EffectiveConfigSnapshot{
+		ReportedAt: now,
+		Hash:       hash,
+		Truncated:  false,
+		Config:     cloneAgentEffectiveConfig(config),
+	}
+
+	if limits.MaxTotalBytes > 0 && snapshot.Size() > limits.MaxTotalBytes {
+		snapshot.Truncated = true
+		for name, file := range snapshot.Config.ConfigMap.ConfigMap {
+			file.Body = nil
+			snapshot.Config.ConfigMap.ConfigMap[name] = file
+		}
+	}
+
+	history = append(history, snapshot)
+
+	totalBytes := 0
+	for _, s := range history {
+		totalBytes += s.Size()
+	}
+
+	// The newest snapshot is always kept; after truncation it fits the size limit alone
+	// unless its names and content types are already too large.
+	for len(history) > 1 &&
+		(len(history) > limits.MaxEntries || (limits.MaxTotalBytes > 0 && totalBytes > limits.MaxTotalBytes)) {
+		totalBytes -= history[0].Size()
+		history = history[1:]
+	}
+
+	a.Status.EffectiveConfigHistory = slices.Clone(history)
+
+	return true
+}
+
+func effectiveConfigSize(config *AgentEffectiveConfig) int {
+	size := 0
+	for name, file := range config.ConfigMap.ConfigMap {
+		size += len(name) + len(file.ContentType) + len(file.Body)
+	}
+
+	return size
+}
+
+// hashEffectiveConfig hashes the config files in name order, length-prefixing every
+// field so that distinct configs cannot produce the same byte stream.
+func hashEffectiveConfig(config *AgentEffectiveConfig) []byte {
+	hasher := sha256.New()
+
+	writeField := func(data []byte) {
+		_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(len(data))))
+		_, _ = hasher.Write(data)
+	}
+
+	configMap := config.ConfigMap.ConfigMap
+	for _, name := range slices.Sorted(maps.Keys(configMap)) {
+		file := configMap[name]
+		writeField([]byte(name))
+		writeField([]byte(file.ContentType))
+		writeField(file.Body)
+	}
+
+	return hasher.Sum(nil)
+}
+
+func cloneAgentEffectiveConfig(config *AgentEffectiveConfig) AgentEffectiveConfig {
+	configMap := make(map[string]AgentConfigFile, len(config.ConfigMap.ConfigMap))
+	for name, file := range config.ConfigMap.ConfigMap {
+		configMap[name] = AgentConfigFile{
+			Body:        cloneByteSlice(file.Body),
+			ContentType: file.ContentType,
+		}
+	}
+
+	return AgentEffectiveConfig{
+		ConfigMap: AgentConfigMap{
+			ConfigMap: configMap,
+		},
+	}
+}
+
+func cloneEffectiveConfigHistory(history []EffectiveConfigSnapshot) []EffectiveConfigSnapshot {
+	if history == nil {
+		return nil
+	}
+
+	cloned := make([]EffectiveConfigSnapshot, len(history))
+	for i, snapshot := range history {
+		cloned[i] = EffectiveConfigSnapshot{
+			ReportedAt: snapshot.ReportedAt,
+			Hash:       cloneByteSlice(snapshot.Hash),
+			Truncated:  snapshot.Truncated,
+			Config:     cloneAgentEffectiveConfig(&snapshot.Config),
+		}
+	}
+
+	return cloned
+}
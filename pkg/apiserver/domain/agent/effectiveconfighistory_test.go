@@ -0,0 +1,95 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func effectiveConfig(body string) *agentmodel.AgentEffectiveConfig {
+	return &agentmodel.AgentEffectiveConfig{
+		ConfigMap: agentmodel.AgentConfigMap{
+			ConfigMap: map[string]agentmodel.AgentConfigFile{
+				"collector.yaml": {Body: []byte(body), ContentType: "text/yaml"},
+			},
+		},
+	}
+}
+
+func TestAgent_RecordEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limits := agentmodel.EffectiveConfigHistoryLimits{MaxEntries: 3, MaxTotalBytes: 0}
+
+	t.Run("identical reports do not add entries", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+
+		assert.True(t, agent.RecordEffectiveConfig(effectiveConfig("a: 1"), now, limits))
+		assert.False(t, agent.RecordEffectiveConfig(effectiveConfig("a: 1"), now.Add(time.Minute), limits))
+		assert.True(t, agent.RecordEffectiveConfig(effectiveConfig("a: 2"), now.Add(2*time.Minute), limits))
+		assert.True(t, agent.RecordEffectiveConfig(effectiveConfig("a: 1"), now.Add(3*time.Minute), limits))
+
+		history := agent.Status.EffectiveConfigHistory
+		require.Len(t, history, 3)
+		assert.Equal(t, now, history[0].ReportedAt)
+		assert.Equal(t, []byte("a: 2"), history[1].Config.ConfigMap.ConfigMap["collector.yaml"].Body)
+		assert.Equal(t, history[0].Hash, history[2].Hash)
+	})
+
+	t.Run("drops the oldest entries beyond the entry limit", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		for i, body := range []string{"a: 1", "a: 2", "a: 3", "a: 4"} {
+			agent.RecordEffectiveConfig(effectiveConfig(body), now.Add(time.Duration(i)*time.Minute), limits)
+		}
+
+		history := agent.Status.EffectiveConfigHistory
+		require.Len(t, history, 3)
+		assert.Equal(t, []byte("a: 2"), history[0].Config.ConfigMap.ConfigMap["collector.yaml"].Body)
+		assert.Equal(t, []byte("a: 4"), history[2].Config.ConfigMap.ConfigMap["collector.yaml"].Body)
+	})
+
+	t.Run("drops the oldest entries beyond the size limit and truncates an oversized one", func(t *testing.T) {
+		t.Parallel()
+
+		// Each snapshot is len("collector.yaml") + len("text/yaml") + 4 = 27 bytes.
+		sized := agentmodel.EffectiveConfigHistoryLimits{MaxEntries: 10, MaxTotalBytes: 60}
+		agent := agentmodel.NewAgent(uuid.New())
+		for i, body := range []string{"a: 1", "a: 2", "a: 3"} {
+			agent.RecordEffectiveConfig(effectiveConfig(body), now.Add(time.Duration(i)*time.Minute), sized)
+		}
+
+		history := agent.Status.EffectiveConfigHistory
+		require.Len(t, history, 2)
+		assert.Equal(t, []byte("a: 2"), history[0].Config.ConfigMap.ConfigMap["collector.yaml"].Body)
+
+		big := effectiveConfig(string(make([]byte, 100)))
+		assert.True(t, agent.RecordEffectiveConfig(big, now.Add(time.Hour), sized))
+
+		history = agent.Status.EffectiveConfigHistory
+		newest := history[len(history)-1]
+		assert.True(t, newest.Truncated)
+		assert.Empty(t, newest.Config.ConfigMap.ConfigMap["collector.yaml"].Body)
+		assert.False(t, agent.RecordEffectiveConfig(big, now.Add(2*time.Hour), sized),
+			"a truncated snapshot still recognizes the identical config")
+	})
+
+	t.Run("disabled history records nothing", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		disabled := agentmodel.EffectiveConfigHistoryLimits{MaxEntries: 0, MaxTotalBytes: 0}
+
+		assert.False(t, agent.RecordEffectiveConfig(effectiveConfig("a: 1"), now, disabled))
+		assert.Empty(t, agent.Status.EffectiveConfigHistory)
+	})
+}
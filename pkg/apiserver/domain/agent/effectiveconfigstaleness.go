@@ -0,0 +1,83 @@
+package agentmodel
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgentConditionTypeStaleEffectiveConfig records whether a connected agent has gone without
+// reporting its effective config for longer than the configured window, so the config the
+// server shows for it may no longer be what the agent runs.
+const AgentConditionTypeStaleEffectiveConfig AgentConditionType = "StaleEffectiveConfig"
+
+// effectiveConfigFreshMessage is the message of a StaleEffectiveConfig condition that is False.
+const effectiveConfigFreshMessage = "Effective config is up to date"
+
+// IsEffectiveConfigStale reports whether the agent is connected and last reported its
+// effective config more than window before now. An agent that never reported one, e.g.
+// because it lacks the ReportsEffectiveConfig capability, is never stale; neither is any
+// agent when window is not positive.
+func (a *Agent) IsEffectiveConfigStale(now time.Time, window time.Duration) bool {
+	if window <= 0 || !a.Status.Connected || a.Status.EffectiveConfigReportedAt.IsZero() {
+		return false
+	}
+
+	return now.Sub(a.Status.EffectiveConfigReportedAt) > window
+}
+
+// RecordEffectiveConfigStaleness sets the StaleEffectiveConfig condition from
+// IsEffectiveConfigStale and reports whether the agent changed, so the caller only saves
+// agents it actually touched. An agent that has never been stale gets no condition.
+//
+// OpAMP agents report their effective config only when it changes, so an agent going
+// without a report may just be running a stable config. A stale agent is therefore first
+// asked to report it, and the condition is Unknown while the request is pending; it
+// becomes True only when the agent has not reported within another window.
+func (a *Agent) RecordEffectiveConfigStaleness(now time.Time, window time.Duration, triggeredBy string) bool {
+	status := AgentConditionStatusFalse
+	message := effectiveConfigFreshMessage
+
+	if a.IsEffectiveConfigStale(now, window) {
+		status, message = a.effectiveConfigStaleness(now, window)
+	}
+
+	prev := a.GetCondition(AgentConditionTypeStaleEffectiveConfig)
+	if prev == nil && status == AgentConditionStatusFalse {
+		return false
+	}
+
+	if prev != nil && prev.Status == status && prev.Message == message {
+		return false
+	}
+
+	a.SetConditionAt(AgentConditionTypeStaleEffectiveConfig, status, now, triggeredBy, message)
+
+	return true
+}
+
+// effectiveConfigStaleness returns the StaleEffectiveConfig condition of an agent whose
+// effective config went unreported for longer than window, asking the agent to report it
+// unless it already was.
+func (a *Agent) effectiveConfigStaleness(now time.Time, window time.Duration) (AgentConditionStatus, string) {
+	unreported := fmt.Sprintf("Effective config not reported for more than %s", window)
+
+	prev := a.GetCondition(AgentConditionTypeStaleEffectiveConfig)
+	switch {
+	case prev != nil && prev.Status == AgentConditionStatusTrue:
+		return prev.Status, prev.Message
+	case prev != nil && prev.Status == AgentConditionStatusUnknown:
+		if now.Sub(prev.LastTransitionTime) > window {
+			return AgentConditionStatusTrue, unreported + ", even when requested"
+		}
+
+		return prev.Status, prev.Message
+	}
+
+	// An agent that no longer reports its effective config cannot be asked for it.
+	err := a.RequestReport(AgentReportKindEffectiveConfig)
+	if err != nil {
+		return AgentConditionStatusTrue, unreported
+	}
+
+	return AgentConditionStatusUnknown, unreported + "; requested it from the agent"
+}
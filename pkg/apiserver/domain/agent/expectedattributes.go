@@ -0,0 +1,109 @@
+package agentmodel
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AgentConditionTypeAttributeDrift records whether the attributes the agent reports
+// match the expected attributes the server holds for it.
+const AgentConditionTypeAttributeDrift AgentConditionType = "AttributeDrift"
+
+// AttributeMismatch is an expected attribute the agent does not report as expected.
+type AttributeMismatch struct {
+	// Key is the attribute key.
+	Key string
+	// Expected is the value the server expects.
+	Expected string
+	// Reported is the value the agent reports; empty when Missing.
+	Reported string
+	// Missing is true when the agent reports no attribute with the key at all.
+	Missing bool
+}
+
+// String describes the mismatch, e.g. `env: expected "prod", reported "dev"`.
+func (m AttributeMismatch) String() string {
+	if m.Missing {
+		return fmt.Sprintf("%s: expected %q, not reported", m.Key, m.Expected)
+	}
+
+	return fmt.Sprintf("%s: expected %q, reported %q", m.Key, m.Expected, m.Reported)
+}
+
+// SetExpectedAttributes replaces the attributes the agent is expected to report. An
+// empty map removes every expectation.
+func (a *Agent) SetExpectedAttributes(expected map[string]string) {
+	if len(expected) == 0 {
+		a.Spec.ExpectedAttributes = nil
+
+		return
+	}
+
+	a.Spec.ExpectedAttributes = maps.Clone(expected)
+}
+
+// AttributeMismatches returns the expected attributes the agent does not report with the
+// expected value, sorted by key. An attribute is looked up among the identifying
+// attributes first and then among the non-identifying ones.
+func (a *Agent) AttributeMismatches() []AttributeMismatch {
+	var mismatches []AttributeMismatch
+
+	description := a.Metadata.Description
+
+	for _, key := range slices.Sorted(maps.Keys(a.Spec.ExpectedAttributes)) {
+		expected := a.Spec.ExpectedAttributes[key]
+
+		reported, ok := description.IdentifyingAttributes[key]
+		if !ok {
+			reported, ok = description.NonIdentifyingAttributes[key]
+		}
+
+		switch {
+		case !ok:
+			mismatches = append(mismatches, AttributeMismatch{Key: key, Expected: expected, Reported: "", Missing: true})
+		case reported != expected:
+			mismatches = append(mismatches, AttributeMismatch{Key: key, Expected: expected, Reported: reported, Missing: false})
+		}
+	}
+
+	return mismatches
+}
+
+// RecordAttributeDrift compares the reported attributes with the expected ones and
+// reflects the result onto the AttributeDrift condition. It reports whether the
+// condition changed.
+//
+// The condition is True while any expected attribute is missing or differs, and False
+// once they all match or the expectations are removed. An agent that never had
+// expectations gets no condition.
+func (a *Agent) RecordAttributeDrift(now time.Time, triggeredBy string) bool {
+	prev := a.GetCondition(AgentConditionTypeAttributeDrift)
+	if len(a.Spec.ExpectedAttributes) == 0 && prev == nil {
+		return false
+	}
+
+	status := AgentConditionStatusFalse
+	message := "agent reports the expected attributes"
+
+	mismatches := a.AttributeMismatches()
+
+	switch {
+	case len(a.Spec.ExpectedAttributes) == 0:
+		message = "no attributes are expected"
+	case len(mismatches) > 0:
+		descriptions := make([]string, 0, len(mismatches))
+		for _, mismatch := range mismatches {
+			descriptions = append(descriptions, mismatch.String())
+		}
+
+		status = AgentConditionStatusTrue
+		message = strings.Join(descriptions, "; ")
+	}
+
+	a.SetConditionAt(AgentConditionTypeAttributeDrift, status, now, triggeredBy, message)
+
+	return prev == nil || prev.Status != status || prev.Message != message
+}
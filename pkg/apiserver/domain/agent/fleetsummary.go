@@ -0,0 +1,17 @@
+package agentmodel
+
+// AgentFleetSummary counts the agents of every namespace by their state, for a dashboard
+// overview of the fleet.
+type AgentFleetSummary struct {
+	// NumAgents is the number of agents.
+	NumAgents int64
+	// NumConnectedAgents is the number of agents that are connected.
+	NumConnectedAgents int64
+	// NumHealthyAgents is the number of connected agents that report being healthy.
+	NumHealthyAgents int64
+	// NumUnhealthyAgents is the number of connected agents that do not report being healthy.
+	NumUnhealthyAgents int64
+	// NumAgentsWithPendingCommands is the number of agents with at least one command they
+	// have not acted on yet, as counted by Agent.PendingCommandCount.
+	NumAgentsWithPendingCommands int64
+}
@@ -0,0 +1,47 @@
+package agentmodel
+
+import "time"
+
+// AgentHeartbeat is the part of an agent's status a heartbeat-only message updates. The
+// server holds it for an agent whose save is throttled, so it can still persist the
+// agent's last-known connection state before it shuts down.
+type AgentHeartbeat struct {
+	// ReportedAt is when the agent reported.
+	ReportedAt time.Time
+	// ReportedTo is the ID of the server the agent reported to.
+	ReportedTo string
+	// SequenceNum is the sequence number of the message.
+	SequenceNum uint64
+	// ConnectionType is how the agent was connected when it reported.
+	ConnectionType ConnectionType
+}
+
+// Heartbeat returns the agent's last heartbeat as recorded in its status.
+func (a *Agent) Heartbeat() AgentHeartbeat {
+	return AgentHeartbeat{
+		ReportedAt:     a.Status.LastReportedAt,
+		ReportedTo:     a.Status.LastReportedTo,
+		SequenceNum:    a.Status.SequenceNum,
+		ConnectionType: a.Status.ConnectionType,
+	}
+}
+
+// RestoreHeartbeat records a heartbeat held back from the agent's stored state, as if the
+// agent had just reported it. It reports whether the agent changed: a heartbeat no newer
+// than the agent's last report, e.g. because the agent reported to another server since,
+// is ignored.
+func (a *Agent) RestoreHeartbeat(heartbeat AgentHeartbeat) bool {
+	if !heartbeat.ReportedAt.After(a.Status.LastReportedAt) {
+		return false
+	}
+
+	a.recordCommunication(heartbeat.ReportedAt, heartbeat.ConnectionType)
+
+	if heartbeat.ReportedTo != "" {
+		a.Status.LastReportedTo = heartbeat.ReportedTo
+	}
+
+	a.Status.SequenceNum = heartbeat.SequenceNum
+
+	return true
+}
@@ -0,0 +1,50 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgent_RestoreHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	connection := agentmodel.NewConnection(nil, agentmodel.ConnectionTypeWebSocket)
+
+	reported := agentmodel.NewAgent(uuid.New())
+	reported.UpdateLastCommunicationInfo(start.Add(5*time.Second), connection)
+	reported.RecordLastReported(nil, start.Add(5*time.Second), 2)
+
+	heartbeat := reported.Heartbeat()
+
+	t.Run("restores a newer heartbeat", func(t *testing.T) {
+		t.Parallel()
+
+		stored := agentmodel.NewAgent(reported.Metadata.InstanceUID)
+		stored.UpdateLastCommunicationInfo(start, nil)
+
+		assert.True(t, stored.RestoreHeartbeat(heartbeat))
+		assert.Equal(t, start.Add(5*time.Second), stored.Status.LastReportedAt)
+		assert.Equal(t, uint64(2), stored.Status.SequenceNum)
+		assert.Equal(t, agentmodel.ConnectionTypeWebSocket, stored.Status.ConnectionType)
+		assert.Equal(t, agentmodel.AgentConnectionStateConnected,
+			stored.ConnectionStateAt(start.Add(10*time.Second), agentmodel.DefaultConnectionStaleness))
+	})
+
+	t.Run("ignores a heartbeat no newer than the last report", func(t *testing.T) {
+		t.Parallel()
+
+		stored := agentmodel.NewAgent(reported.Metadata.InstanceUID)
+		stored.UpdateLastCommunicationInfo(start.Add(time.Minute), nil)
+		stored.RecordLastReported(nil, start.Add(time.Minute), 3)
+
+		assert.False(t, stored.RestoreHeartbeat(heartbeat))
+		assert.Equal(t, start.Add(time.Minute), stored.Status.LastReportedAt)
+		assert.Equal(t, uint64(3), stored.Status.SequenceNum)
+	})
+}
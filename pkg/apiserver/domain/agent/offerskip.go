@@ -0,0 +1,111 @@
+package agentmodel
+
+import (
+	"strings"
+	"time"
+)
+
+// AgentConditionTypeCapabilityMismatch records whether the server desires state for the
+// agent that the agent has not declared the capability to accept (see SkippedOffers).
+const AgentConditionTypeCapabilityMismatch AgentConditionType = "CapabilityMismatch"
+
+// Offer names used in OfferSkip.Offer.
+const (
+	OfferRemoteConfig                 = "remoteConfig"
+	OfferPackagesAvailable            = "packagesAvailable"
+	OfferOpAMPConnectionSettings      = "connectionSettings.opamp"
+	OfferOwnMetricsConnectionSettings = "connectionSettings.ownMetrics"
+	OfferOwnLogsConnectionSettings    = "connectionSettings.ownLogs"
+	OfferOwnTracesConnectionSettings  = "connectionSettings.ownTraces"
+	OfferOtherConnectionSettings      = "connectionSettings.otherConnections"
+)
+
+// OfferSkip is a part of the agent's desired state that is withheld from the
+// ServerToAgent message because the agent did not declare the capability to
+// accept it.
+type OfferSkip struct {
+	// Offer names the withheld part, one of the Offer* constants.
+	Offer string
+	// Reason explains why it was withheld.
+	Reason string
+}
+
+// SkippedOffers returns the assigned offers the agent cannot accept, in a fixed
+// order. It is empty when everything assigned can be delivered.
+func (a *Agent) SkippedOffers() []OfferSkip {
+	var skipped []OfferSkip
+
+	skip := func(offer, reason string) {
+		skipped = append(skipped, OfferSkip{Offer: offer, Reason: reason})
+	}
+
+	if a.HasAssignedRemoteConfig() && !a.IsRemoteConfigSupported() {
+		skip(OfferRemoteConfig, missingCapability("AcceptsRemoteConfig"))
+	}
+
+	if a.Spec.PackagesAvailable != nil && len(a.Spec.PackagesAvailable.Packages) > 0 &&
+		!a.Metadata.Capabilities.HasAcceptsPackages() {
+		skip(OfferPackagesAvailable, missingCapability("AcceptsPackages"))
+	}
+
+	connectionInfo := a.Spec.ConnectionInfo
+	if !connectionInfo.HasConnectionSettings() {
+		return skipped
+	}
+
+	if connectionInfo.OpAMP().HasEndpoint() && !a.IsOpAMPConnectionSettingsSupported() {
+		skip(OfferOpAMPConnectionSettings, missingCapability("AcceptsOpAMPConnectionSettings"))
+	}
+
+	if connectionInfo.OwnMetrics().HasEndpoint() && !a.IsOwnMetricsSupported() {
+		skip(OfferOwnMetricsConnectionSettings, missingCapability("ReportsOwnMetrics"))
+	}
+
+	if connectionInfo.OwnLogs().HasEndpoint() && !a.IsOwnLogsSupported() {
+		skip(OfferOwnLogsConnectionSettings, missingCapability("ReportsOwnLogs"))
+	}
+
+	if connectionInfo.OwnTraces().HasEndpoint() && !a.IsOwnTracesSupported() {
+		skip(OfferOwnTracesConnectionSettings, missingCapability("ReportsOwnTraces"))
+	}
+
+	if len(connectionInfo.OtherConnections()) > 0 && !a.IsOtherConnectionSettingsSupported() {
+		skip(OfferOtherConnectionSettings, missingCapability("AcceptsOtherConnectionSettings"))
+	}
+
+	return skipped
+}
+
+func missingCapability(capability string) string {
+	return "agent lacks the " + capability + " capability"
+}
+
+// RecordCapabilityMismatch reflects SkippedOffers onto the CapabilityMismatch condition and
+// reports whether the condition changed. The condition is True, naming every withheld offer,
+// while something assigned cannot be delivered; it flips to False once that is resolved.
+// An agent that never had a mismatch gets no condition at all.
+func (a *Agent) RecordCapabilityMismatch(now time.Time, triggeredBy string) bool {
+	skipped := a.SkippedOffers()
+	prev := a.GetCondition(AgentConditionTypeCapabilityMismatch)
+
+	if len(skipped) == 0 && prev == nil {
+		return false
+	}
+
+	status := AgentConditionStatusFalse
+	message := "agent accepts everything the server desires for it"
+
+	if len(skipped) > 0 {
+		reasons := make([]string, 0, len(skipped))
+		for _, skip := range skipped {
+			reasons = append(reasons, skip.Offer+": "+skip.Reason)
+		}
+
+		status = AgentConditionStatusTrue
+		message = "withheld offers the agent cannot accept: " + strings.Join(reasons, "; ")
+	}
+
+	a.SetConditionAt(AgentConditionTypeCapabilityMismatch, status, now, triggeredBy, message)
+
+	return prev == nil || prev.Status != status || prev.Message != message
+}
@@ -0,0 +1,78 @@
+package agentmodel
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"maps"
+	"slices"
+)
+
+// AgentPackageFile is one package in the packages offer sent to an agent: the package name
+// it is offered under and the downloadable file the agent fetches for it.
+type AgentPackageFile struct {
+	// Name is the key the package is offered under.
+	Name string
+	// PackageType is the AgentPackage's type ("TopLevel" or "Addon").
+	PackageType string
+	// Version is the package version.
+	Version string
+	// Hash identifies the package; the agent reports it back once installed.
+	Hash []byte
+	// DownloadURL, ContentHash, Signature and Headers describe the file to download.
+	DownloadURL string
+	ContentHash []byte
+	Signature   []byte
+	Headers     map[string]string
+}
+
+// PackageFile returns the file offered for this package under name.
+func (a *AgentPackage) PackageFile(name string) AgentPackageFile {
+	return AgentPackageFile{
+		Name:        name,
+		PackageType: a.Spec.PackageType,
+		Version:     a.Spec.Version,
+		Hash:        a.Spec.Hash,
+		DownloadURL: a.Spec.DownloadURL,
+		ContentHash: a.Spec.ContentHash,
+		Signature:   a.Spec.Signature,
+		Headers:     a.Spec.Headers,
+	}
+}
+
+// AllPackagesHash hashes a set of package files into the offer's all_packages_hash.
+// The files are hashed in name order with every field length-prefixed, so the hash
+// depends only on the set's content: the same files in any order give the same hash,
+// and adding, removing or changing a file changes it.
+func AllPackagesHash(files []AgentPackageFile) []byte {
+	sorted := slices.SortedFunc(slices.Values(files), func(a, b AgentPackageFile) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	hasher := sha256.New()
+
+	writeField := func(data []byte) {
+		_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(len(data))))
+		_, _ = hasher.Write(data)
+	}
+
+	for _, file := range sorted {
+		writeField([]byte(file.Name))
+		writeField([]byte(file.PackageType))
+		writeField([]byte(file.Version))
+		writeField(file.Hash)
+		writeField([]byte(file.DownloadURL))
+		writeField(file.ContentHash)
+		writeField(file.Signature)
+
+		headerKeys := slices.Sorted(maps.Keys(file.Headers))
+		_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(len(headerKeys))))
+
+		for _, key := range headerKeys {
+			writeField([]byte(key))
+			writeField([]byte(file.Headers[key]))
+		}
+	}
+
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func packageFile(name, version string) agentmodel.AgentPackageFile {
+	return agentmodel.AgentPackageFile{
+		Name:        name,
+		PackageType: "TopLevel",
+		Version:     version,
+		Hash:        []byte(name + "@" + version),
+		DownloadURL: "https://packages.example.com/" + name + "/" + version,
+		ContentHash: []byte("content-" + version),
+		Signature:   nil,
+		Headers:     map[string]string{"Authorization": "Bearer t", "X-Mirror": "eu"},
+	}
+}
+
+func TestAllPackagesHash(t *testing.T) {
+	t.Parallel()
+
+	collector := packageFile("collector", "0.120.0")
+	plugin := packageFile("plugin", "1.0.0")
+
+	base := agentmodel.AllPackagesHash([]agentmodel.AgentPackageFile{collector})
+	withPlugin := agentmodel.AllPackagesHash([]agentmodel.AgentPackageFile{collector, plugin})
+
+	assert.NotEqual(t, base, withPlugin, "adding a file changes the hash")
+	assert.Equal(t, withPlugin, agentmodel.AllPackagesHash([]agentmodel.AgentPackageFile{plugin, collector}),
+		"the hash does not depend on file order")
+	assert.Equal(t, withPlugin, agentmodel.AllPackagesHash([]agentmodel.AgentPackageFile{
+		packageFile("collector", "0.120.0"), packageFile("plugin", "1.0.0"),
+	}), "recomputing over equal files gives the same hash")
+
+	upgraded := packageFile("collector", "0.121.0")
+	assert.NotEqual(t, withPlugin, agentmodel.AllPackagesHash([]agentmodel.AgentPackageFile{upgraded, plugin}))
+}
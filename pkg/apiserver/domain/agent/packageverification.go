@@ -0,0 +1,90 @@
+package agentmodel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// ErrPackageOriginUnavailable is returned when an agent package download is not attempted
+// because its origin failed repeatedly and is assumed to be down.
+var ErrPackageOriginUnavailable = errors.New("agent package origin is unavailable")
+
+// RecordContentVerification sets the ContentVerified condition from downloading the
+// package's artifact. It is False with the error when the download failed or the content
+// does not match Spec.ContentHash, and True otherwise. A package without a ContentHash is
+// only checked for being downloadable.
+func (a *AgentPackage) RecordContentVerification(
+	now time.Time,
+	actor string,
+	contentHash []byte,
+	downloadErr error,
+) {
+	status := model.ConditionStatusTrue
+	message := "content hash matches"
+
+	switch {
+	case downloadErr != nil:
+		status = model.ConditionStatusFalse
+		message = fmt.Sprintf("download failed: %v", downloadErr)
+	case len(a.Spec.ContentHash) == 0:
+		message = "downloaded; no content hash to compare"
+	case !bytes.Equal(a.Spec.ContentHash, contentHash):
+		status = model.ConditionStatusFalse
+		message = fmt.Sprintf("content hash mismatch: expected %s, downloaded %s",
+			hex.EncodeToString(a.Spec.ContentHash), hex.EncodeToString(contentHash))
+	}
+
+	a.SetCondition(model.ConditionTypeContentVerified, status, now, actor, message)
+}
+
+// SetCondition upserts a condition in the agent package's status. LastTransitionTime only
+// advances when the status changes; Reason and Message are always refreshed.
+func (a *AgentPackage) SetCondition(
+	conditionType model.ConditionType,
+	status model.ConditionStatus,
+	now time.Time,
+	reason string,
+	message string,
+) {
+	for idx, condition := range a.Status.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+
+		if condition.Status != status {
+			a.Status.Conditions[idx].LastTransitionTime = now
+		}
+
+		a.Status.Conditions[idx].Status = status
+		a.Status.Conditions[idx].Reason = reason
+		a.Status.Conditions[idx].Message = message
+
+		return
+	}
+
+	a.Status.Conditions = append(a.Status.Conditions, model.Condition{
+		Type:               conditionType,
+		LastTransitionTime: now,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// GetCondition returns a copy of the condition of the given type, or nil if absent.
+func (a *AgentPackage) GetCondition(conditionType model.ConditionType) *model.Condition {
+	for _, condition := range a.Status.Conditions {
+		if condition.Type == conditionType {
+			cond := condition
+
+			return &cond
+		}
+	}
+
+	return nil
+}
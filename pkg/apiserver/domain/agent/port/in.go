@@ -28,6 +28,15 @@ var (
 	// ErrDefaultNamespaceUndeletable indicates a delete was attempted on the built-in
 	// default namespace, which is protected.
 	ErrDefaultNamespaceUndeletable = errors.New("default namespace cannot be deleted")
+	// ErrNewInstanceUIDInUse indicates a new instance UID was requested for an agent while
+	// another agent already has that UID or is pending reassignment to it.
+	ErrNewInstanceUIDInUse = errors.New("new instance UID is already in use by another agent")
+	// ErrAgentHasNoRemoteConfig indicates a remote config resend was requested for an agent
+	// that has no remote config the server can offer it.
+	ErrAgentHasNoRemoteConfig = errors.New("agent has no remote config to offer")
+	// ErrAgentNotConnected indicates a reconnect was requested for an agent that has no
+	// open connection to any server.
+	ErrAgentNotConnected = errors.New("agent is not connected")
 )
 
 // AgentUsecase is an interface that defines the methods for agent use cases.
@@ -54,6 +63,39 @@ type AgentUsecase interface {
 	// SearchAgents searches agents by instance UID prefix filtered by namespace.
 	SearchAgents(ctx context.Context, namespace string, query string,
 		options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error)
+	// ListAgentsByPackage lists the agents in namespace that reported the named package at
+	// packageVersion, or at any version when packageVersion is empty.
+	ListAgentsByPackage(ctx context.Context, namespace string, packageName string, packageVersion string,
+		options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error)
+	// ListAgentAttributes returns the distinct attribute keys reported across all agents,
+	// each with up to valueLimit of its distinct values. A valueLimit of 0 lists keys only.
+	ListAgentAttributes(ctx context.Context, valueLimit int) (*agentmodel.AttributeCatalog, error)
+	// CheckNewInstanceUIDAvailable returns ErrNewInstanceUIDInUse when newInstanceUID
+	// cannot be assigned to the agent instanceUID: another agent already has it, or is
+	// pending reassignment to it.
+	CheckNewInstanceUIDAvailable(ctx context.Context, instanceUID uuid.UUID, newInstanceUID uuid.UUID) error
+}
+
+// AgentFleetSummaryUsecase reports how the agents of every namespace are doing.
+type AgentFleetSummaryUsecase interface {
+	// GetAgentFleetSummary counts the agents of every namespace by their state.
+	GetAgentFleetSummary(ctx context.Context) (*agentmodel.AgentFleetSummary, error)
+}
+
+// AgentCommandQueueSummaryUsecase reports how many commands are queued for the agents of
+// every namespace.
+type AgentCommandQueueSummaryUsecase interface {
+	// GetCommandQueueSummary totals the command queues of every agent, counting a queue as
+	// full once it holds maxPending commands. A maxPending of 0 or less never counts one.
+	GetCommandQueueSummary(ctx context.Context, maxPending int) (*agentmodel.CommandQueueSummary, error)
+}
+
+// AgentDisconnectGraceUsecase finds the agents whose disconnect grace period has run out,
+// whichever server their connection was lost on.
+type AgentDisconnectGraceUsecase interface {
+	// ListAgentsConnectionLostBy returns up to limit agents still in their disconnect grace
+	// period whose connection was lost at or before lostBy.
+	ListAgentsConnectionLostBy(ctx context.Context, lostBy time.Time, limit int) ([]*agentmodel.Agent, error)
 }
 
 // AgentNotificationUsecase is an interface for notifying servers about agent changes.
@@ -75,6 +117,61 @@ type AgentCacheInvalidationPublisher interface {
 	BroadcastAgentCacheInvalidation(ctx context.Context, instanceUIDs ...uuid.UUID) error
 }
 
+// AgentGroupChangePublisher announces AgentGroup config changes on the server event bus.
+type AgentGroupChangePublisher interface {
+	// PublishAgentGroupChange sends the change to every alive server, including this
+	// one, so a consumer attached to any node observes it. It is best-effort: a peer
+	// that cannot be reached is logged and skipped.
+	PublishAgentGroupChange(ctx context.Context, change *agentmodel.AgentGroupConfigChange) error
+}
+
+// AgentDisconnectPublisher asks the server holding an agent's connection to close it.
+type AgentDisconnectPublisher interface {
+	// RequestAgentDisconnect sends a disconnect request to the server the agent last
+	// reported to. That server closes the connection, and the agent reconnects on its own.
+	RequestAgentDisconnect(ctx context.Context, agent *agentmodel.Agent) error
+}
+
+// AgentEffectiveConfigChangePublisher announces on the server event bus that an agent
+// reported an effective config different from the one it reported before.
+type AgentEffectiveConfigChangePublisher interface {
+	// PublishAgentEffectiveConfigChange sends the change to every alive server, including
+	// this one, so a watcher attached to any node observes it. It is best-effort: a peer
+	// that cannot be reached is logged and skipped.
+	PublishAgentEffectiveConfigChange(ctx context.Context, instanceUID uuid.UUID) error
+}
+
+// AgentEffectiveConfigWatcher lets a caller on this server follow an agent's effective
+// config changes announced by [AgentEffectiveConfigChangePublisher] on any server.
+type AgentEffectiveConfigWatcher interface {
+	// WatchAgentEffectiveConfig returns a channel that receives a value each time the
+	// agent's effective config changes, and a function ending the watch. The channel
+	// carries no config: the watcher reads the agent again. Changes announced while a
+	// value is still unread are coalesced into it.
+	WatchAgentEffectiveConfig(instanceUID uuid.UUID) (<-chan struct{}, func())
+}
+
+// AgentDeliveryTracker remembers which agent updates this server already pushed to agents.
+type AgentDeliveryTracker interface {
+	// ForgetAgentDeliveries drops what the tracker remembers about updates pushed to the
+	// agent, so an update redelivered after the agent reconnects is pushed again instead of
+	// being skipped as already sent.
+	ForgetAgentDeliveries(instanceUID uuid.UUID)
+}
+
+// RemoteConfigValidator enforces a policy on the remote configs AgentGroups deliver. Every
+// config a group resolves to passes through the registered validators, so a config that
+// breaks a policy is rejected when the group is saved and is never offered to an agent.
+type RemoteConfigValidator interface {
+	// Name identifies the validator in logs and rejection messages.
+	Name() string
+	// ValidateRemoteConfig checks one resolved config, delivered under name. It returns an
+	// error matching agentmodel.ErrRemoteConfigRejected, usually an
+	// *agentmodel.RemoteConfigViolation, when the config breaks the policy; any other error
+	// means the check itself could not run.
+	ValidateRemoteConfig(ctx context.Context, name string, file agentmodel.AgentConfigFile) error
+}
+
 // AgentCacheInvalidator drops a single agent from the local in-process cache. It is the
 // receiving end of [AgentCacheInvalidationPublisher]: a peer's broadcast resolves to this.
 type AgentCacheInvalidator interface {
@@ -139,6 +236,15 @@ type AgentPackageUsecase interface {
 		deletedAt time.Time, deletedBy string) error
 }
 
+// AgentPackageVerificationUsecase verifies agent packages against their artifacts.
+type AgentPackageVerificationUsecase interface {
+	// VerifyAgentPackage downloads the package's artifact, records the outcome as the
+	// package's ContentVerified condition, and persists the package. A failed download is
+	// recorded on the condition rather than returned.
+	VerifyAgentPackage(ctx context.Context, namespace string, name string,
+		actor string) (*agentmodel.AgentPackage, error)
+}
+
 // AgentRemoteConfigUsecase is an interface that defines the methods for agent remote config use cases.
 type AgentRemoteConfigUsecase interface {
 	// GetAgentRemoteConfig retrieves an agent remote config by its namespace and name.
@@ -258,6 +364,10 @@ type AgentGroupUsecase interface {
 	// new description so it picks up its assigned configs without waiting for a group update.
 	// It mutates the agent in place; the caller is responsible for persisting.
 	ApplyMatchingAgentGroupsToAgent(ctx context.Context, agent *agentmodel.Agent) error
+	// GetRemoteConfigSources returns, for each remote config name the agent's matching
+	// groups contribute, the group whose config wins. Names missing from the result were
+	// not set by a currently matching group.
+	GetRemoteConfigSources(ctx context.Context, agent *agentmodel.Agent) (map[string]*agentmodel.AgentGroup, error)
 	// ReconcileAgent re-applies the matching agent groups to the given agent and persists the
 	// result. Unlike ApplyMatchingAgentGroupsToAgent it owns the save, so an on-demand
 	// reconcile of a single agent actually takes effect.
@@ -266,6 +376,50 @@ type AgentGroupUsecase interface {
 	// the same work the background reconcile loop performs. Use this to force a refresh
 	// without waiting for the next tick or mutating the group.
 	ReconcileAgentGroup(ctx context.Context, namespace, name string) error
+	// PropagateAgentGroup re-applies the named agent group to its matching agents and
+	// summarizes the outcome per agent. A failing agent is reported, not returned as error.
+	PropagateAgentGroup(ctx context.Context, namespace, name string) (*agentmodel.AgentGroupPropagation, error)
+	// ApplyAgentGroupToAgents applies the named agent group's remote configs to exactly the
+	// given agents, whether or not its selector matches them, and summarizes the outcome per
+	// agent. A failing agent is reported, not returned as error.
+	ApplyAgentGroupToAgents(
+		ctx context.Context,
+		namespace, name string,
+		instanceUIDs []uuid.UUID,
+	) (*agentmodel.AgentGroupPropagation, error)
+}
+
+// AgentQuarantineUsecase quarantines agents, stopping agent group propagation from
+// pushing new config to them, and lifts the quarantine again.
+type AgentQuarantineUsecase interface {
+	// QuarantineAgent manually quarantines the agent and persists it. A manual quarantine
+	// is never lifted automatically.
+	QuarantineAgent(ctx context.Context, agent *agentmodel.Agent, triggeredBy, reason string) error
+	// UnquarantineAgent lifts the agent's quarantine and re-applies its matching agent
+	// groups so it catches up on config it missed while quarantined.
+	UnquarantineAgent(ctx context.Context, agent *agentmodel.Agent, triggeredBy string) error
+}
+
+// AgentExpectedAttributesUsecase manages the server-owned attributes an agent is expected
+// to keep reporting.
+type AgentExpectedAttributesUsecase interface {
+	// SetExpectedAttributes replaces the agent's expected attributes, re-evaluates its
+	// AttributeDrift condition and persists it. An empty map removes every expectation.
+	SetExpectedAttributes(ctx context.Context, agent *agentmodel.Agent,
+		expected map[string]string, triggeredBy string) error
+}
+
+// AgentRevocationUsecase maintains the blacklist of revoked agent instance UIDs. The OpAMP
+// handler consults it on every message and refuses revoked agents.
+type AgentRevocationUsecase interface {
+	// RevokeAgent revokes the instance UID and closes its live connection on this server.
+	// Revoking an already revoked instance UID replaces the previous revocation.
+	RevokeAgent(ctx context.Context, instanceUID uuid.UUID,
+		revokedBy, reason string) (*agentmodel.AgentRevocation, error)
+	// UnrevokeAgent removes the revocation so the agent may connect again.
+	UnrevokeAgent(ctx context.Context, instanceUID uuid.UUID) error
+	// IsAgentRevoked reports whether the instance UID is revoked.
+	IsAgentRevoked(ctx context.Context, instanceUID uuid.UUID) (bool, error)
 }
 
 // AgentGroupRelatedUsecase is an interface that defines methods related to agent groups.
@@ -278,6 +432,13 @@ type AgentGroupRelatedUsecase interface {
 	) (*model.ListResponse[*agentmodel.Agent], error)
 }
 
+// AgentGroupNameUsecase looks up agent group names ignoring case.
+type AgentGroupNameUsecase interface {
+	// ListAgentGroupNamesIgnoringCase returns the names of the agent groups in namespace
+	// that equal name ignoring case, excluding deleted ones.
+	ListAgentGroupNamesIgnoringCase(ctx context.Context, namespace string, name string) ([]string, error)
+}
+
 // HostUsecase is an interface that defines the methods for host use cases.
 type HostUsecase interface {
 	// GetHost retrieves a host by its ID.
@@ -327,6 +488,17 @@ type CertificateUsecase interface {
 		deletedAt time.Time, deletedBy string) (*agentmodel.Certificate, error)
 }
 
+// ResourceQuotaUsecase enforces and reports the quotas capping how many resources of
+// each kind may exist.
+type ResourceQuotaUsecase interface {
+	// CheckResourceQuota returns an error wrapping model.ErrQuotaExceeded when one more
+	// resource of the given kind would exceed its quota.
+	CheckResourceQuota(ctx context.Context, resource agentmodel.QuotaResource) error
+	// ListResourceQuotaUsage reports the usage of every resource that can be capped,
+	// including unlimited ones.
+	ListResourceQuotaUsage(ctx context.Context) ([]agentmodel.ResourceQuotaUsage, error)
+}
+
 // ServerUsecase is an interface that defines the methods for server use cases.
 type ServerUsecase interface {
 	// ServerUsecase should also implement ServerMessageUsecase.
@@ -388,6 +560,9 @@ type ConnectionUsecase interface {
 	DeleteConnection(ctx context.Context, connection *agentmodel.Connection) error
 	// SendServerToAgent sends a ServerToAgent message to the agent via WebSocket connection.
 	SendServerToAgent(ctx context.Context, instanceUID uuid.UUID, message *protobufs.ServerToAgent) error
+	// DisconnectAgent closes the agent's connection on this server. It fails with
+	// ErrConnectionNotFound when the agent holds no connection here.
+	DisconnectAgent(ctx context.Context, instanceUID uuid.UUID) error
 }
 
 // ClusterConnectionUsecase exposes a cluster-wide view of connections, aggregated from the
@@ -48,6 +48,26 @@ type AgentPersistencePort interface {
 	// SearchAgents searches agents by query filtered by namespace with pagination options.
 	SearchAgents(ctx context.Context, namespace string, query string,
 		options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error)
+	// ListAgentsByPackage retrieves the agents in namespace whose package statuses report the
+	// named package at packageVersion, or at any version when packageVersion is empty.
+	ListAgentsByPackage(ctx context.Context, namespace string, packageName string, packageVersion string,
+		options *model.ListOptions) (*model.ListResponse[*agentmodel.Agent], error)
+	// ListAgentAttributes retrieves the distinct attribute keys reported across all agents,
+	// each with its smallest valueLimit distinct values. A valueLimit of 0 lists keys only.
+	ListAgentAttributes(ctx context.Context, valueLimit int) (*agentmodel.AttributeCatalog, error)
+	// GetAgentByNewInstanceUID retrieves the agent pending reassignment to newInstanceUID.
+	// It returns model.ErrResourceNotExist when no agent is.
+	GetAgentByNewInstanceUID(ctx context.Context, newInstanceUID uuid.UUID) (*agentmodel.Agent, error)
+	// GetAgentFleetSummary counts the agents of every namespace by their state without
+	// loading them.
+	GetAgentFleetSummary(ctx context.Context) (*agentmodel.AgentFleetSummary, error)
+	// GetCommandQueueSummary totals the command queues of every agent without loading them,
+	// counting a queue as full once it holds maxPending commands. A maxPending of 0 or less
+	// means queues are unbounded and never full.
+	GetCommandQueueSummary(ctx context.Context, maxPending int) (*agentmodel.CommandQueueSummary, error)
+	// ListAgentsConnectionLostBy retrieves up to limit connected agents whose connection
+	// was lost at or before lostBy.
+	ListAgentsConnectionLostBy(ctx context.Context, lostBy time.Time, limit int) ([]*agentmodel.Agent, error)
 }
 
 // ServerEventSenderPort is an interface that defines the methods for sending events to servers.
@@ -86,6 +106,9 @@ type AgentGroupPersistencePort interface {
 	// ListAgentGroups retrieves a list of agent groups with pagination options.
 	ListAgentGroups(ctx context.Context,
 		options *model.ListOptions) (*model.ListResponse[*agentmodel.AgentGroup], error)
+	// ListAgentGroupNamesIgnoringCase returns the names of the non-deleted agent groups in
+	// namespace that equal name ignoring case.
+	ListAgentGroupNamesIgnoringCase(ctx context.Context, namespace string, name string) ([]string, error)
 }
 
 // ServerPersistencePort is an interface that defines the methods for server persistence.
@@ -126,6 +149,17 @@ type AgentPackagePersistencePort interface {
 		options *model.ListOptions) (*model.ListResponse[*agentmodel.AgentPackage], error)
 }
 
+// AgentPackageDownloadPort fetches agent package artifacts from their download URL. It is
+// an outbound port implemented by an HTTP adapter.
+type AgentPackageDownloadPort interface {
+	// FetchAgentPackageContentHash downloads the artifact at downloadURL, sending headers
+	// with the request, and returns the SHA-256 digest of its content. It fails with an
+	// error wrapping agentmodel.ErrPackageOriginUnavailable, without contacting the origin,
+	// when the origin has failed repeatedly.
+	FetchAgentPackageContentHash(ctx context.Context, downloadURL string,
+		headers map[string]string) ([]byte, error)
+}
+
 // AgentRemoteConfigPersistencePort is an interface that defines the methods for agent remote config persistence.
 type AgentRemoteConfigPersistencePort interface {
 	// GetAgentRemoteConfig retrieves an agent remote config by its namespace and name.
@@ -180,6 +214,17 @@ type EndpointMetricsQueryPort interface {
 	) (*agentmodel.EndpointThroughput, error)
 }
 
+// AgentEnrichmentPort derives labels for an agent from data outside of what the agent
+// reports, e.g. its region from its address or its owner from an inventory. It is an
+// outbound port; the server calls it when an agent is first seen and whenever the agent
+// reports a new description, and stores the result as the agent's server-owned labels.
+type AgentEnrichmentPort interface {
+	// EnrichAgent returns the labels the agent should carry, replacing the ones it has.
+	// remoteAddr is the address ("host:port") the agent's message came from. An error
+	// leaves the agent's labels as they are.
+	EnrichAgent(ctx context.Context, agent *agentmodel.Agent, remoteAddr string) (map[string]string, error)
+}
+
 // HostPersistencePort is an interface that defines the methods for host persistence.
 type HostPersistencePort interface {
 	// GetHost retrieves a host by its ID. It returns port.ErrResourceNotExist
@@ -213,3 +258,15 @@ type CertificatePersistencePort interface {
 	ListCertificate(ctx context.Context,
 		options *model.ListOptions) (*model.ListResponse[*agentmodel.Certificate], error)
 }
+
+// AgentRevocationPersistencePort is an interface that defines the methods for agent revocation persistence.
+type AgentRevocationPersistencePort interface {
+	// GetAgentRevocation retrieves the revocation of an instance UID. It returns
+	// port.ErrResourceNotExist when the instance UID is not revoked.
+	GetAgentRevocation(ctx context.Context, instanceUID uuid.UUID) (*agentmodel.AgentRevocation, error)
+	// PutAgentRevocation saves or replaces a revocation.
+	PutAgentRevocation(ctx context.Context, revocation *agentmodel.AgentRevocation) error
+	// DeleteAgentRevocation permanently removes the revocation of an instance UID. It returns
+	// port.ErrResourceNotExist when the instance UID is not revoked.
+	DeleteAgentRevocation(ctx context.Context, instanceUID uuid.UUID) error
+}
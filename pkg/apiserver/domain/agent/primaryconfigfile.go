@@ -0,0 +1,37 @@
+package agentmodel
+
+import (
+	"maps"
+	"path"
+	"slices"
+)
+
+// PrimaryFileName returns the key of the main file of the config map, and false when
+// none can be told apart from the rest. OpAMP reserves the empty key for an agent with
+// a single config, so that key wins; a map holding a single file has it as its primary;
+// otherwise the file whose base name is the most preferred conventional name is chosen.
+func (m AgentConfigMap) PrimaryFileName() (string, bool) {
+	if _, ok := m.ConfigMap[""]; ok {
+		return "", true
+	}
+
+	if len(m.ConfigMap) == 1 {
+		for name := range m.ConfigMap {
+			return name, true
+		}
+	}
+
+	// Base names that mark the main file of a multi-file config, most preferred first.
+	conventionalNames := []string{"config.yaml", "config.yml", "config.json", "collector.yaml", "collector.yml"}
+
+	names := slices.Sorted(maps.Keys(m.ConfigMap))
+	for _, conventional := range conventionalNames {
+		for _, name := range names {
+			if path.Base(name) == conventional {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,55 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgentConfigMap_PrimaryFileName(t *testing.T) {
+	t.Parallel()
+
+	file := agentmodel.AgentConfigFile{Body: []byte("receivers: {}"), ContentType: "text/yaml"}
+
+	tests := []struct {
+		name        string
+		files       []string
+		wantPrimary string
+		wantFound   bool
+	}{
+		{name: "empty key wins", files: []string{"", "config.yaml", "extra.yaml"}, wantPrimary: "", wantFound: true},
+		{name: "single file", files: []string{"pipelines.yaml"}, wantPrimary: "pipelines.yaml", wantFound: true},
+		{
+			name:        "conventional name among several",
+			files:       []string{"exporters.yaml", "/etc/otelcol/config.yaml", "receivers.yaml"},
+			wantPrimary: "/etc/otelcol/config.yaml",
+			wantFound:   true,
+		},
+		{
+			name:        "most preferred conventional name",
+			files:       []string{"collector.yaml", "config.yml"},
+			wantPrimary: "config.yml",
+			wantFound:   true,
+		},
+		{name: "no convention matches", files: []string{"a.yaml", "b.yaml"}, wantPrimary: "", wantFound: false},
+		{name: "no files", files: nil, wantPrimary: "", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configMap := agentmodel.AgentConfigMap{ConfigMap: map[string]agentmodel.AgentConfigFile{}}
+			for _, name := range tt.files {
+				configMap.ConfigMap[name] = file
+			}
+
+			primary, found := configMap.PrimaryFileName()
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantPrimary, primary)
+			assert.Len(t, configMap.ConfigMap, len(tt.files), "the other files are preserved")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package agentmodel
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgentConditionTypeQuarantined records whether the server has stopped pushing new
+// config to the agent (see AgentQuarantine).
+const AgentConditionTypeQuarantined AgentConditionType = "Quarantined"
+
+// AgentQuarantine is a server-set flag that freezes the agent's group-driven remote
+// config: while it is set, agent group propagation leaves the agent's current config
+// untouched instead of pushing new config to it.
+type AgentQuarantine struct {
+	// Automatic is true when the health evaluator quarantined the agent. An automatic
+	// quarantine is lifted once the agent reports healthy again; a manual one stays
+	// until it is explicitly lifted.
+	Automatic bool
+	// Reason is a human readable explanation of why the agent was quarantined.
+	Reason string
+	// QuarantinedBy is the identifier of the user or system that quarantined the agent.
+	QuarantinedBy string
+	// QuarantinedAt is when the agent was quarantined.
+	QuarantinedAt time.Time
+}
+
+// IsQuarantined reports whether the agent is quarantined.
+func (a *Agent) IsQuarantined() bool {
+	return a.Spec.Quarantine != nil
+}
+
+// Quarantine marks the agent as quarantined. Quarantining an already quarantined
+// agent replaces the record, so a manual quarantine takes over an automatic one and
+// is no longer lifted on recovery.
+func (a *Agent) Quarantine(now time.Time, triggeredBy, reason string, automatic bool) {
+	a.Spec.Quarantine = &AgentQuarantine{
+		Automatic:     automatic,
+		Reason:        reason,
+		QuarantinedBy: triggeredBy,
+		QuarantinedAt: now,
+	}
+
+	a.SetConditionAt(AgentConditionTypeQuarantined, AgentConditionStatusTrue, now, triggeredBy, reason)
+}
+
+// Unquarantine lifts the agent's quarantine so agent group propagation resumes.
+func (a *Agent) Unquarantine(now time.Time, triggeredBy string) {
+	a.Spec.Quarantine = nil
+
+	a.SetConditionAt(AgentConditionTypeQuarantined, AgentConditionStatusFalse, now, triggeredBy, "Quarantine lifted")
+}
+
+// UnhealthySince returns when the agent last transitioned to unhealthy. ok is false
+// when the agent is healthy or has never reported its health.
+func (a *Agent) UnhealthySince() (time.Time, bool) {
+	condition := a.GetCondition(AgentConditionTypeHealthy)
+	if condition == nil || condition.Status != AgentConditionStatusFalse {
+		return time.Time{}, false
+	}
+
+	return condition.LastTransitionTime, true
+}
+
+// ShouldAutoQuarantine reports whether the agent has been unhealthy for at least
+// threshold and is not yet quarantined.
+func (a *Agent) ShouldAutoQuarantine(now time.Time, threshold time.Duration) bool {
+	if a.IsQuarantined() {
+		return false
+	}
+
+	since, unhealthy := a.UnhealthySince()
+
+	return unhealthy && now.Sub(since) >= threshold
+}
+
+// ShouldLiftAutoQuarantine reports whether the agent is under an automatic
+// quarantine and has since reported healthy.
+func (a *Agent) ShouldLiftAutoQuarantine() bool {
+	return a.Spec.Quarantine != nil && a.Spec.Quarantine.Automatic &&
+		a.IsConditionTrue(AgentConditionTypeHealthy)
+}
+
+// AutoQuarantineReason describes an automatic quarantine for the given threshold.
+func AutoQuarantineReason(threshold time.Duration) string {
+	return fmt.Sprintf("Agent unhealthy for longer than %s", threshold)
+}
+
+func (a *Agent) cloneQuarantine() *AgentQuarantine {
+	if a.Spec.Quarantine == nil {
+		return nil
+	}
+
+	quarantine := *a.Spec.Quarantine
+
+	return &quarantine
+}
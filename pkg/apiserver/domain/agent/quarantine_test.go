@@ -0,0 +1,68 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgent_ShouldAutoQuarantine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("never reported health", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		assert.False(t, agent.ShouldAutoQuarantine(now, time.Hour))
+	})
+
+	t.Run("unhealthy past the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.SetConditionAt(agentmodel.AgentConditionTypeHealthy, agentmodel.AgentConditionStatusFalse,
+			now.Add(-2*time.Hour), "agent", "down")
+		assert.True(t, agent.ShouldAutoQuarantine(now, time.Hour))
+
+		agent.Quarantine(now, "system", "unhealthy", true)
+		assert.False(t, agent.ShouldAutoQuarantine(now, time.Hour), "already quarantined")
+	})
+
+	t.Run("unhealthy within the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.SetConditionAt(agentmodel.AgentConditionTypeHealthy, agentmodel.AgentConditionStatusFalse,
+			now.Add(-time.Minute), "agent", "down")
+		assert.False(t, agent.ShouldAutoQuarantine(now, time.Hour))
+	})
+}
+
+func TestAgent_ShouldLiftAutoQuarantine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	recovered := func(automatic bool) *agentmodel.Agent {
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Quarantine(now, "system", "unhealthy", automatic)
+		agent.SetConditionAt(agentmodel.AgentConditionTypeHealthy, agentmodel.AgentConditionStatusTrue,
+			now, "agent", "ok")
+
+		return agent
+	}
+
+	assert.True(t, recovered(true).ShouldLiftAutoQuarantine())
+	assert.False(t, recovered(false).ShouldLiftAutoQuarantine(), "manual quarantine must be lifted explicitly")
+
+	agent := recovered(true)
+	agent.Unquarantine(now, "admin")
+	assert.False(t, agent.IsQuarantined())
+	assert.False(t, agent.IsConditionTrue(agentmodel.AgentConditionTypeQuarantined))
+}
@@ -0,0 +1,178 @@
+package agentmodel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultRemoteConfigKeyDelimiter separates an AgentGroup name from an inline
+// config name in the key the config is delivered to agents under.
+const DefaultRemoteConfigKeyDelimiter = "/"
+
+// remoteConfigKeyEscape escapes a literal delimiter (or a literal escape) that
+// appears inside a group or config name.
+const remoteConfigKeyEscape = `\`
+
+// ErrInvalidRemoteConfigKeyDelimiter is returned when a configured delimiter is
+// unusable: it must be non-empty and must not contain the escape character.
+var ErrInvalidRemoteConfigKeyDelimiter = errors.New("invalid remote config key delimiter")
+
+// RemoteConfigKeyFormat builds and parses the "{group}{delimiter}{name}" keys an
+// AgentGroup's inline remote configs are namespaced under, so configs from
+// different groups never collide in an agent's config map.
+//
+// A delimiter (or backslash) inside either name is escaped with a backslash, so
+// a config named "a/b" in group "g" becomes `g/a\/b` and still parses back to
+// ("g", "a/b"). Names without the delimiter or a backslash produce the same key
+// as the unescaped format, so existing keys stay stable.
+type RemoteConfigKeyFormat struct {
+	delimiter string
+}
+
+// NewRemoteConfigKeyFormat returns a format using the given delimiter. An empty
+// delimiter falls back to DefaultRemoteConfigKeyDelimiter.
+func NewRemoteConfigKeyFormat(delimiter string) (RemoteConfigKeyFormat, error) {
+	if delimiter == "" {
+		delimiter = DefaultRemoteConfigKeyDelimiter
+	}
+
+	if strings.Contains(delimiter, remoteConfigKeyEscape) {
+		return RemoteConfigKeyFormat{}, fmt.Errorf("%w: %q must not contain %q",
+			ErrInvalidRemoteConfigKeyDelimiter, delimiter, remoteConfigKeyEscape)
+	}
+
+	return RemoteConfigKeyFormat{delimiter: delimiter}, nil
+}
+
+// DefaultRemoteConfigKeyFormat returns the format using DefaultRemoteConfigKeyDelimiter.
+func DefaultRemoteConfigKeyFormat() RemoteConfigKeyFormat {
+	return RemoteConfigKeyFormat{delimiter: DefaultRemoteConfigKeyDelimiter}
+}
+
+// Delimiter returns the delimiter in use. The zero value reports the default.
+func (f RemoteConfigKeyFormat) Delimiter() string {
+	if f.delimiter == "" {
+		return DefaultRemoteConfigKeyDelimiter
+	}
+
+	return f.delimiter
+}
+
+// Join builds the namespaced key for an inline config of the given group.
+func (f RemoteConfigKeyFormat) Join(groupName, configName string) string {
+	return f.escape(groupName) + f.Delimiter() + f.escape(configName)
+}
+
+// Split parses a key produced by Join back into the group and original config
+// name. It splits on the first unescaped delimiter. ok is false when the key has
+// no unescaped delimiter (e.g. a config referenced by AgentRemoteConfigRef,
+// which is delivered under its plain resource name) or is malformed.
+func (f RemoteConfigKeyFormat) Split(key string) (string, string, bool) {
+	delimiter := f.Delimiter()
+
+	var group strings.Builder
+
+	for i := 0; i < len(key); {
+		rest := key[i:]
+
+		switch {
+		case strings.HasPrefix(rest, remoteConfigKeyEscape):
+			literal, width, ok := f.unescapeAt(rest)
+			if !ok {
+				return "", "", false
+			}
+
+			group.WriteString(literal)
+
+			i += width
+		case strings.HasPrefix(rest, delimiter):
+			name, ok := f.unescape(rest[len(delimiter):])
+			if !ok {
+				return "", "", false
+			}
+
+			return group.String(), name, true
+		default:
+			group.WriteByte(key[i])
+
+			i++
+		}
+	}
+
+	return "", "", false
+}
+
+func (f RemoteConfigKeyFormat) escape(name string) string {
+	delimiter := f.Delimiter()
+
+	var out strings.Builder
+
+	for i := 0; i < len(name); {
+		rest := name[i:]
+
+		switch {
+		case strings.HasPrefix(rest, remoteConfigKeyEscape):
+			out.WriteString(remoteConfigKeyEscape + remoteConfigKeyEscape)
+
+			i += len(remoteConfigKeyEscape)
+		case strings.HasPrefix(rest, delimiter):
+			out.WriteString(remoteConfigKeyEscape + delimiter)
+
+			i += len(delimiter)
+		default:
+			out.WriteByte(name[i])
+
+			i++
+		}
+	}
+
+	return out.String()
+}
+
+// unescape reverses escape for a whole name. A bare (unescaped) delimiter is
+// rejected since escape never produces one.
+func (f RemoteConfigKeyFormat) unescape(escaped string) (string, bool) {
+	delimiter := f.Delimiter()
+
+	var out strings.Builder
+
+	for i := 0; i < len(escaped); {
+		rest := escaped[i:]
+
+		switch {
+		case strings.HasPrefix(rest, remoteConfigKeyEscape):
+			literal, width, ok := f.unescapeAt(rest)
+			if !ok {
+				return "", false
+			}
+
+			out.WriteString(literal)
+
+			i += width
+		case strings.HasPrefix(rest, delimiter):
+			return "", false
+		default:
+			out.WriteByte(escaped[i])
+
+			i++
+		}
+	}
+
+	return out.String(), true
+}
+
+// unescapeAt decodes the escape sequence at the start of s, returning the literal
+// it stands for and how many bytes it consumed.
+func (f RemoteConfigKeyFormat) unescapeAt(s string) (string, int, bool) {
+	next := s[len(remoteConfigKeyEscape):]
+
+	switch {
+	case strings.HasPrefix(next, remoteConfigKeyEscape):
+		return remoteConfigKeyEscape, 2 * len(remoteConfigKeyEscape), true
+	case strings.HasPrefix(next, f.Delimiter()):
+		return f.Delimiter(), len(remoteConfigKeyEscape) + len(f.Delimiter()), true
+	default:
+		return "", 0, false
+	}
+}
@@ -0,0 +1,97 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestRemoteConfigKeyFormat_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		delimiter string
+		group     string
+		config    string
+		wantKey   string
+	}{
+		{name: "plain names keep the legacy key", delimiter: "", group: "g", config: "c", wantKey: "g/c"},
+		{name: "delimiter in config name", delimiter: "/", group: "g", config: "a/b", wantKey: `g/a\/b`},
+		{name: "delimiter in group name", delimiter: "/", group: "g/x", config: "c", wantKey: `g\/x/c`},
+		{name: "backslash in name", delimiter: "/", group: "g", config: `a\b`, wantKey: `g/a\\b`},
+		{name: "trailing backslash", delimiter: "/", group: "g", config: `a\`, wantKey: `g/a\\`},
+		{name: "multi-char delimiter", delimiter: "::", group: "g", config: "a::b/c", wantKey: `g::a\::b/c`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			format, err := agentmodel.NewRemoteConfigKeyFormat(tt.delimiter)
+			require.NoError(t, err)
+
+			key := format.Join(tt.group, tt.config)
+			assert.Equal(t, tt.wantKey, key)
+
+			group, config, ok := format.Split(key)
+			require.True(t, ok)
+			assert.Equal(t, tt.group, group)
+			assert.Equal(t, tt.config, config)
+		})
+	}
+}
+
+func TestRemoteConfigKeyFormat_JoinNeverCollides(t *testing.T) {
+	t.Parallel()
+
+	format := agentmodel.DefaultRemoteConfigKeyFormat()
+
+	// Without escaping, all of these would be delivered under "g/a/b".
+	keys := []string{
+		format.Join("g", "a/b"),
+		format.Join("g/a", "b"),
+		format.Join(`g\`, "a/b"),
+		format.Join("g", `a\/b`),
+	}
+
+	assert.Len(t, lo.Uniq(keys), len(keys), "keys: %v", keys)
+}
+
+func TestRemoteConfigKeyFormat_Split(t *testing.T) {
+	t.Parallel()
+
+	format := agentmodel.DefaultRemoteConfigKeyFormat()
+
+	t.Run("plain resource name has no group", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := format.Split("shared-config")
+		assert.False(t, ok)
+	})
+
+	t.Run("unescaped delimiter in name is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := format.Split("g/a/b")
+		assert.False(t, ok)
+	})
+
+	t.Run("dangling escape is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := format.Split(`g/a\x`)
+		assert.False(t, ok)
+	})
+}
+
+func TestNewRemoteConfigKeyFormat_RejectsEscapeCharacter(t *testing.T) {
+	t.Parallel()
+
+	_, err := agentmodel.NewRemoteConfigKeyFormat(`\`)
+	require.ErrorIs(t, err, agentmodel.ErrInvalidRemoteConfigKeyDelimiter)
+}
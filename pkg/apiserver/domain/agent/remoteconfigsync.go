@@ -0,0 +1,54 @@
+package agentmodel
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model/vo"
+)
+
+// offeredConfigFile mirrors the JSON encoding of protobufs.AgentConfigFile, so the hash of
+// the desired config is the one sent to the agent in AgentRemoteConfig.config_hash.
+type offeredConfigFile struct {
+	Body        []byte `json:"body,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// DesiredRemoteConfigHash returns the hash of the remote config the server offers the
+// agent, exactly as it is sent in AgentRemoteConfig.config_hash. It is nil when no remote
+// config is offered, either because none is assigned or because the agent cannot accept it.
+func (a *Agent) DesiredRemoteConfigHash() ([]byte, error) {
+	if !a.HasRemoteConfig() {
+		return nil, nil
+	}
+
+	configMap := make(map[string]offeredConfigFile, len(a.Spec.RemoteConfig.ConfigMap.ConfigMap))
+	for name, configFile := range a.Spec.RemoteConfig.ConfigMap.ConfigMap {
+		configMap[name] = offeredConfigFile{Body: configFile.Body, ContentType: configFile.ContentType}
+	}
+
+	hash, err := vo.NewHashFromAny(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute desired remote config hash: %w", err)
+	}
+
+	return hash.Bytes(), nil
+}
+
+// IsConfigInSync reports whether the agent has applied the remote config the server offers
+// it: the agent reports the desired config's hash with the APPLIED status. An agent that is
+// offered no remote config is in sync.
+func (a *Agent) IsConfigInSync() bool {
+	desired, err := a.DesiredRemoteConfigHash()
+	if err != nil {
+		return false
+	}
+
+	if desired == nil {
+		return true
+	}
+
+	status := a.Status.RemoteConfigStatus
+
+	return status.Status == RemoteConfigStatusApplied && bytes.Equal(status.LastRemoteConfigHash, desired)
+}
@@ -0,0 +1,35 @@
+package agentmodel
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRemoteConfigRejected is matched by every error a remote config validator returns for a
+// config that breaks its policy, as opposed to a validator that failed to run.
+var ErrRemoteConfigRejected = errors.New("remote config rejected by policy")
+
+// RemoteConfigViolation reports the key of a remote config that breaks a validator's policy.
+type RemoteConfigViolation struct {
+	// Validator is the name of the validator that rejected the config.
+	Validator string
+	// Key is the dotted path of the offending key within the config, e.g. "exporters.debug",
+	// or empty when the config as a whole is rejected.
+	Key string
+	// Reason explains why the key was rejected.
+	Reason string
+}
+
+// Error implements the error interface.
+func (v *RemoteConfigViolation) Error() string {
+	if v.Key == "" {
+		return fmt.Sprintf("%s rejected the config: %s", v.Validator, v.Reason)
+	}
+
+	return fmt.Sprintf("%s rejected key %q: %s", v.Validator, v.Key, v.Reason)
+}
+
+// Unwrap makes a RemoteConfigViolation match ErrRemoteConfigRejected.
+func (v *RemoteConfigViolation) Unwrap() error {
+	return ErrRemoteConfigRejected
+}
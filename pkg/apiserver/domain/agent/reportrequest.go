@@ -0,0 +1,60 @@
+package agentmodel
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AgentReportKind is a part of the agent's state the server can ask the agent to report
+// again, without waiting for the agent to report a change on its own.
+type AgentReportKind string
+
+const (
+	// AgentReportKindEffectiveConfig asks the agent to report its effective config.
+	AgentReportKindEffectiveConfig AgentReportKind = "EffectiveConfig"
+	// AgentReportKindHealth asks the agent to report its component health.
+	AgentReportKindHealth AgentReportKind = "Health"
+	// AgentReportKindAvailableComponents asks the agent to report its available components.
+	AgentReportKindAvailableComponents AgentReportKind = "AvailableComponents"
+)
+
+// RequestReport records that the server wants the agent to report kind again. The
+// request stays pending until the agent reports that part of its state, so it survives
+// a reconnect. It returns ErrUnsupportedAgentOperation when the agent's capabilities
+// say it never reports kind.
+func (a *Agent) RequestReport(kind AgentReportKind) error {
+	if !a.reportsKind(kind) {
+		return fmt.Errorf("%w: agent does not report %s", ErrUnsupportedAgentOperation, kind)
+	}
+
+	if !slices.Contains(a.Spec.PendingReports, kind) {
+		a.Spec.PendingReports = append(a.Spec.PendingReports, kind)
+	}
+
+	return nil
+}
+
+// HasPendingReport reports whether the server is still waiting for the agent to report kind.
+func (a *Agent) HasPendingReport(kind AgentReportKind) bool {
+	return slices.Contains(a.Spec.PendingReports, kind)
+}
+
+func (a *Agent) reportsKind(kind AgentReportKind) bool {
+	switch kind {
+	case AgentReportKindEffectiveConfig:
+		return a.Metadata.Capabilities.HasReportsEffectiveConfig()
+	case AgentReportKindHealth:
+		return a.Metadata.Capabilities.HasReportsHealth()
+	case AgentReportKindAvailableComponents:
+		return a.Metadata.Capabilities.HasReportsAvailableComponents()
+	default:
+		return false
+	}
+}
+
+// completeReport drops the pending request for kind once the agent reported it.
+func (a *Agent) completeReport(kind AgentReportKind) {
+	a.Spec.PendingReports = slices.DeleteFunc(a.Spec.PendingReports, func(pending AgentReportKind) bool {
+		return pending == kind
+	})
+}
@@ -0,0 +1,47 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+)
+
+func TestAgent_RequestReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pending until the agent reports it", func(t *testing.T) {
+		t.Parallel()
+
+		capabilities := agent.Capabilities(agent.AgentCapabilityReportsEffectiveConfig | agent.AgentCapabilityReportsHealth)
+		a := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+
+		require.NoError(t, a.RequestReport(agentmodel.AgentReportKindEffectiveConfig))
+		require.NoError(t, a.RequestReport(agentmodel.AgentReportKindHealth))
+		require.NoError(t, a.RequestReport(agentmodel.AgentReportKindHealth))
+		assert.Len(t, a.Spec.PendingReports, 2, "a repeated request is recorded once")
+		assert.True(t, a.HasPendingServerMessages())
+
+		require.NoError(t, a.ReportComponentHealth(&agentmodel.AgentComponentHealth{Healthy: true}))
+		assert.False(t, a.HasPendingReport(agentmodel.AgentReportKindHealth))
+		assert.True(t, a.HasPendingReport(agentmodel.AgentReportKindEffectiveConfig))
+
+		require.NoError(t, a.ReportEffectiveConfig(&agentmodel.AgentEffectiveConfig{}, time.Now()))
+		assert.Empty(t, a.Spec.PendingReports)
+	})
+
+	t.Run("rejected when the agent never reports it", func(t *testing.T) {
+		t.Parallel()
+
+		a := agentmodel.NewAgent(uuid.New())
+
+		err := a.RequestReport(agentmodel.AgentReportKindAvailableComponents)
+		require.ErrorIs(t, err, agentmodel.ErrUnsupportedAgentOperation)
+		assert.Empty(t, a.Spec.PendingReports)
+	})
+}
@@ -0,0 +1,35 @@
+package agentmodel
+
+// QuotaResource is a kind of resource whose count can be capped by a quota.
+type QuotaResource string
+
+const (
+	// QuotaResourceAgentGroup caps the number of agent groups across all namespaces.
+	QuotaResourceAgentGroup QuotaResource = "AgentGroup"
+	// QuotaResourceCertificate caps the number of certificates across all namespaces.
+	QuotaResourceCertificate QuotaResource = "Certificate"
+)
+
+// QuotaResources returns every resource that can be capped, in a stable order.
+func QuotaResources() []QuotaResource {
+	return []QuotaResource{QuotaResourceAgentGroup, QuotaResourceCertificate}
+}
+
+// ResourceQuotas maps a resource to the maximum number of it that may exist. A missing
+// or non-positive limit leaves the resource unlimited.
+type ResourceQuotas map[QuotaResource]int64
+
+// ResourceQuotaUsage is how many resources of one kind exist against its quota.
+type ResourceQuotaUsage struct {
+	// Resource is the kind of resource counted.
+	Resource QuotaResource
+	// Used is the number of resources that exist, not counting deleted ones.
+	Used int64
+	// Limit is the quota. A non-positive limit means unlimited.
+	Limit int64
+}
+
+// AllowsCreate reports whether one more resource fits within the quota.
+func (u ResourceQuotaUsage) AllowsCreate() bool {
+	return u.Limit <= 0 || u.Used < u.Limit
+}
@@ -0,0 +1,32 @@
+package agentmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentRevocation blacklists an agent instance UID. The OpAMP handler refuses and closes
+// connections from a revoked instance UID until the revocation is removed. It is keyed by
+// instance UID alone rather than by namespace, because a compromised agent can report
+// any namespace it likes.
+type AgentRevocation struct {
+	// InstanceUID is the revoked agent instance UID.
+	InstanceUID uuid.UUID
+	// Reason is a human readable explanation of why the agent was revoked.
+	Reason string
+	// RevokedBy is the user who revoked the agent.
+	RevokedBy string
+	// RevokedAt is when the agent was revoked.
+	RevokedAt time.Time
+}
+
+// NewAgentRevocation creates a revocation of the given instance UID.
+func NewAgentRevocation(instanceUID uuid.UUID, revokedBy, reason string, now time.Time) *AgentRevocation {
+	return &AgentRevocation{
+		InstanceUID: instanceUID,
+		Reason:      reason,
+		RevokedBy:   revokedBy,
+		RevokedAt:   now,
+	}
+}
@@ -1,9 +1,173 @@
 package agentmodel
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
 // AgentSelector defines the criteria for selecting agent.
+// All criteria are ANDed; an empty selector matches every agent.
 type AgentSelector struct {
 	// IdentifyingAttributes is a map of identifying attributes used to select agents.
 	IdentifyingAttributes map[string]string
 	// NonIdentifyingAttributes is a map of non-identifying attributes used to select agents.
 	NonIdentifyingAttributes map[string]string
+	// AbsentIdentifyingAttributes lists identifying attribute keys the agent must not report.
+	AbsentIdentifyingAttributes []string
+	// AbsentNonIdentifyingAttributes lists non-identifying attribute keys the agent must not report.
+	AbsentNonIdentifyingAttributes []string
+	// IdentifyingMatchExpressions lists further conditions identifying attribute values must meet.
+	IdentifyingMatchExpressions []AttributeMatchExpression
+	// NonIdentifyingMatchExpressions lists further conditions non-identifying attribute values must meet.
+	NonIdentifyingMatchExpressions []AttributeMatchExpression
+	// CaseInsensitive matches attribute values ignoring case and surrounding whitespace,
+	// so "Linux " selects an agent reporting "linux". Keys are always matched exactly.
+	CaseInsensitive bool
+}
+
+// AttributeMatchOperator is the comparison an AttributeMatchExpression applies.
+type AttributeMatchOperator string
+
+const (
+	// AttributeMatchOperatorGreaterThan matches a numeric attribute value greater than the
+	// expression's value.
+	AttributeMatchOperatorGreaterThan AttributeMatchOperator = "Gt"
+	// AttributeMatchOperatorLessThan matches a numeric attribute value less than the
+	// expression's value.
+	AttributeMatchOperatorLessThan AttributeMatchOperator = "Lt"
+)
+
+// AttributeMatchExpression is a condition on an attribute value beyond equality, e.g.
+// "port > 1024". Attributes are reported as strings, so a numeric comparison only
+// matches an agent reporting the key with a value that parses as a number.
+type AttributeMatchExpression struct {
+	// Key is the attribute the expression applies to.
+	Key string
+	// Operator is the comparison between the attribute value and Value.
+	Operator AttributeMatchOperator
+	// Value is the number the attribute value is compared with.
+	Value float64
+}
+
+// Matches reports whether the attribute value satisfies the expression.
+func (e AttributeMatchExpression) Matches(attributeValue string) bool {
+	number, err := strconv.ParseFloat(attributeValue, 64)
+	if err != nil {
+		return false
+	}
+
+	switch e.Operator {
+	case AttributeMatchOperatorGreaterThan:
+		return number > e.Value
+	case AttributeMatchOperatorLessThan:
+		return number < e.Value
+	default:
+		return false
+	}
+}
+
+// Matches reports whether the agent satisfies the selector: every listed attribute is
+// present with the given value, every match expression holds for a reported attribute,
+// and every absent key is not reported at all. An attribute reported with an empty value
+// is present.
+func (s AgentSelector) Matches(agent *Agent) bool {
+	description := agent.Metadata.Description
+
+	valueEqual := exactValueEqual
+	if s.CaseInsensitive {
+		valueEqual = foldedValueEqual
+	}
+
+	return matchesAttributeSelector(description.IdentifyingAttributes,
+		s.IdentifyingAttributes, s.AbsentIdentifyingAttributes, valueEqual) &&
+		matchesAttributeSelector(description.NonIdentifyingAttributes,
+			s.NonIdentifyingAttributes, s.AbsentNonIdentifyingAttributes, valueEqual) &&
+		matchesAttributeExpressions(description.IdentifyingAttributes, s.IdentifyingMatchExpressions) &&
+		matchesAttributeExpressions(description.NonIdentifyingAttributes, s.NonIdentifyingMatchExpressions)
+}
+
+// Validate checks the selector's match expressions, reporting invalid ones under
+// fieldPrefix, e.g. "spec.selector.".
+func (s AgentSelector) Validate(fieldPrefix string) error {
+	var fieldErrs model.FieldErrors
+
+	fieldErrs = append(fieldErrs,
+		validateAttributeExpressions(s.IdentifyingMatchExpressions, fieldPrefix+"identifyingMatchExpressions")...)
+	fieldErrs = append(fieldErrs,
+		validateAttributeExpressions(s.NonIdentifyingMatchExpressions, fieldPrefix+"nonIdentifyingMatchExpressions")...)
+
+	return fieldErrs.Err()
+}
+
+func validateAttributeExpressions(expressions []AttributeMatchExpression, field string) model.FieldErrors {
+	var fieldErrs model.FieldErrors
+
+	for index, expression := range expressions {
+		prefix := fmt.Sprintf("%s[%d].", field, index)
+
+		if expression.Key == "" {
+			fieldErrs = append(fieldErrs, &model.FieldError{
+				Field: prefix + "key", Value: expression.Key, Reason: "must not be empty",
+			})
+		}
+
+		switch expression.Operator {
+		case AttributeMatchOperatorGreaterThan, AttributeMatchOperatorLessThan:
+		default:
+			fieldErrs = append(fieldErrs, &model.FieldError{
+				Field: prefix + "operator", Value: expression.Operator, Reason: "must be one of Gt, Lt",
+			})
+		}
+	}
+
+	return fieldErrs
+}
+
+func matchesAttributeExpressions(attributes map[string]string, expressions []AttributeMatchExpression) bool {
+	for _, expression := range expressions {
+		attributeValue, ok := attributes[expression.Key]
+		if !ok || !expression.Matches(attributeValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func exactValueEqual(attributeValue, selectorValue string) bool {
+	return attributeValue == selectorValue
+}
+
+func foldedValueEqual(attributeValue, selectorValue string) bool {
+	return strings.EqualFold(strings.TrimSpace(attributeValue), strings.TrimSpace(selectorValue))
+}
+
+func matchesAttributeSelector(
+	attributes, equal map[string]string,
+	absent []string,
+	valueEqual func(attributeValue, selectorValue string) bool,
+) bool {
+	for key, value := range equal {
+		attributeValue, ok := attributes[key]
+		if !ok || !valueEqual(attributeValue, value) {
+			return false
+		}
+	}
+
+	for _, key := range absent {
+		if _, ok := attributes[key]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Matches reports whether the attributes hold every key/value pair of selector.
+// An empty selector matches any attributes.
+func (a Attributes) Matches(selector map[string]string) bool {
+	return matchesAttributeSelector(a, selector, nil, exactValueEqual)
 }
@@ -0,0 +1,165 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+func TestAgentSelector_Matches(t *testing.T) {
+	t.Parallel()
+
+	agentWith := func(identifying, nonIdentifying map[string]string) *agentmodel.Agent {
+		return agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+			IdentifyingAttributes:    identifying,
+			NonIdentifyingAttributes: nonIdentifying,
+		}))
+	}
+
+	collector := map[string]string{"service.name": "otel-collector"}
+
+	tests := []struct {
+		name     string
+		selector agentmodel.AgentSelector
+		agent    *agentmodel.Agent
+		want     bool
+	}{
+		{
+			name:     "empty selector matches everything",
+			selector: agentmodel.AgentSelector{},
+			agent:    agentWith(nil, nil),
+			want:     true,
+		},
+		{
+			name:     "equal attribute",
+			selector: agentmodel.AgentSelector{IdentifyingAttributes: collector},
+			agent:    agentWith(collector, nil),
+			want:     true,
+		},
+		{
+			name:     "missing attribute does not match an empty value",
+			selector: agentmodel.AgentSelector{NonIdentifyingAttributes: map[string]string{"host.name": ""}},
+			agent:    agentWith(collector, nil),
+			want:     false,
+		},
+		{
+			name: "absent key not reported",
+			selector: agentmodel.AgentSelector{
+				IdentifyingAttributes:          collector,
+				AbsentNonIdentifyingAttributes: []string{"host.name"},
+			},
+			agent: agentWith(collector, map[string]string{"os.type": "linux"}),
+			want:  true,
+		},
+		{
+			name:     "absent key reported",
+			selector: agentmodel.AgentSelector{AbsentNonIdentifyingAttributes: []string{"host.name"}},
+			agent:    agentWith(collector, map[string]string{"host.name": "node-1"}),
+			want:     false,
+		},
+		{
+			name:     "absent key reported with an empty value",
+			selector: agentmodel.AgentSelector{AbsentNonIdentifyingAttributes: []string{"host.name"}},
+			agent:    agentWith(collector, map[string]string{"host.name": ""}),
+			want:     false,
+		},
+		{
+			name:     "absence is checked per attribute map",
+			selector: agentmodel.AgentSelector{AbsentIdentifyingAttributes: []string{"host.name"}},
+			agent:    agentWith(collector, map[string]string{"host.name": "node-1"}),
+			want:     true,
+		},
+		{
+			name:     "exact match is case sensitive by default",
+			selector: agentmodel.AgentSelector{NonIdentifyingAttributes: map[string]string{"os.type": "Linux"}},
+			agent:    agentWith(nil, map[string]string{"os.type": "linux"}),
+			want:     false,
+		},
+		{
+			name: "case-insensitive match ignores case and surrounding whitespace",
+			selector: agentmodel.AgentSelector{
+				NonIdentifyingAttributes: map[string]string{"os.type": "Linux"},
+				CaseInsensitive:          true,
+			},
+			agent: agentWith(nil, map[string]string{"os.type": " linux\t"}),
+			want:  true,
+		},
+		{
+			name: "case-insensitive match still compares the whole value",
+			selector: agentmodel.AgentSelector{
+				NonIdentifyingAttributes: map[string]string{"os.type": "Linux"},
+				CaseInsensitive:          true,
+			},
+			agent: agentWith(nil, map[string]string{"os.type": "linux-gnu"}),
+			want:  false,
+		},
+		{
+			name: "numeric greater than",
+			selector: agentmodel.AgentSelector{NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorGreaterThan, Value: 1024},
+			}},
+			agent: agentWith(nil, map[string]string{"port": "8080"}),
+			want:  true,
+		},
+		{
+			name: "numeric comparison is not lexical",
+			selector: agentmodel.AgentSelector{NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024},
+			}},
+			agent: agentWith(nil, map[string]string{"port": "10000"}),
+			want:  false,
+		},
+		{
+			name: "unparseable value does not match either comparison",
+			selector: agentmodel.AgentSelector{NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024},
+			}},
+			agent: agentWith(nil, map[string]string{"port": "http"}),
+			want:  false,
+		},
+		{
+			name: "expression on a missing attribute does not match",
+			selector: agentmodel.AgentSelector{IdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+				{Key: "port", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 1024},
+			}},
+			agent: agentWith(nil, map[string]string{"port": "80"}),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.selector.Matches(tt.agent))
+		})
+	}
+}
+
+func TestAgentSelector_Validate(t *testing.T) {
+	t.Parallel()
+
+	selector := agentmodel.AgentSelector{
+		IdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+			{Key: "replica.index", Operator: agentmodel.AttributeMatchOperatorLessThan, Value: 3},
+		},
+		NonIdentifyingMatchExpressions: []agentmodel.AttributeMatchExpression{
+			{Key: "", Operator: "Ge", Value: 1},
+		},
+	}
+
+	var fieldErrs model.FieldErrors
+
+	require.ErrorAs(t, selector.Validate("spec.selector."), &fieldErrs)
+	require.Len(t, fieldErrs, 2)
+	assert.Equal(t, "spec.selector.nonIdentifyingMatchExpressions[0].key", fieldErrs[0].Field)
+	assert.Equal(t, "spec.selector.nonIdentifyingMatchExpressions[0].operator", fieldErrs[1].Field)
+
+	require.NoError(t, agentmodel.AgentSelector{}.Validate("spec.selector."))
+}
@@ -1,7 +1,11 @@
 // Package serverevent defines server-to-server event models.
 package serverevent
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // MessageType represents a message sent to a server.
 type MessageType string
@@ -17,6 +21,14 @@ const (
 	// MessageTypeInvalidateAgentCache asks the recipient server to drop its cached copy of
 	// the listed agents, so a write made on another node is not served stale from cache.
 	MessageTypeInvalidateAgentCache MessageType = "InvalidateAgentCache"
+	// MessageTypeAgentGroupChanged announces a key-level diff of an AgentGroup's configs.
+	MessageTypeAgentGroupChanged MessageType = "AgentGroupChanged"
+	// MessageTypeDisconnectAgent asks the server holding an agent's connection to close it,
+	// so the agent reconnects and re-establishes its session.
+	MessageTypeDisconnectAgent MessageType = "DisconnectAgent"
+	// MessageTypeAgentEffectiveConfigChanged announces that an agent reported a changed
+	// effective config, so servers watching the agent can emit it.
+	MessageTypeAgentEffectiveConfigChanged MessageType = "AgentEffectiveConfigChanged"
 )
 
 // Message represents a message sent between servers.
@@ -37,6 +49,12 @@ type MessagePayload struct {
 	*MessageForServerToAgent
 	// When Type is MessageTypeInvalidateAgentCache, Payload is MessageForInvalidateAgentCache.
 	*MessageForInvalidateAgentCache
+	// When Type is MessageTypeAgentGroupChanged, Payload is MessageForAgentGroupChanged.
+	*MessageForAgentGroupChanged
+	// When Type is MessageTypeDisconnectAgent, Payload is MessageForDisconnectAgent.
+	*MessageForDisconnectAgent
+	// When Type is MessageTypeAgentEffectiveConfigChanged, Payload is MessageForAgentEffectiveConfigChanged.
+	*MessageForAgentEffectiveConfigChanged
 }
 
 // MessageForServerToAgent represents a message sent from the server to an agent.
@@ -47,6 +65,12 @@ type MessageForServerToAgent struct {
 	// because the message can be delayed or missed.
 	// All servers should check all agents status periodically to handle such cases.
 	TargetAgentInstanceUIDs []uuid.UUID `json:"targetAgentInstanceUids"`
+	// TargetAgentSequenceNums maps a target agent to the sequence number of the update
+	// being announced, so a redelivered message can be recognized and skipped.
+	// The sequence number is the agent's ResourceVersion after the write, not the OpAMP
+	// SequenceNum: the latter only advances when the agent reports, so two API updates
+	// between reports would share it. Agents missing from the map are always processed.
+	TargetAgentSequenceNums map[uuid.UUID]int64 `json:"targetAgentSequenceNums,omitempty"`
 }
 
 // MessageForInvalidateAgentCache carries the agents whose cached copies the recipient
@@ -55,3 +79,44 @@ type MessageForInvalidateAgentCache struct {
 	// AgentInstanceUIDs is the list of agent instance UIDs to invalidate from the cache.
 	AgentInstanceUIDs []uuid.UUID `json:"agentInstanceUids"`
 }
+
+// MessageForDisconnectAgent names the agent whose connection the recipient server should
+// close. It's encoded as json in the CloudEvent data field.
+type MessageForDisconnectAgent struct {
+	// AgentInstanceUID is the instance UID of the agent to disconnect.
+	AgentInstanceUID uuid.UUID `json:"agentInstanceUid"`
+}
+
+// MessageForAgentEffectiveConfigChanged names the agent whose effective config changed.
+// It's encoded as json in the CloudEvent data field.
+type MessageForAgentEffectiveConfigChanged struct {
+	// ChangedAgentInstanceUID is the instance UID of the agent. The config itself is not
+	// sent; the recipient reads the agent from the database.
+	// It is not named AgentInstanceUID: the payloads are flattened into one JSON object,
+	// and a name shared with MessageForDisconnectAgent would drop both fields.
+	ChangedAgentInstanceUID uuid.UUID `json:"changedAgentInstanceUid"`
+}
+
+// MessageForAgentGroupChanged describes which config keys of an AgentGroup were added,
+// removed or modified, and the selector of the agents affected.
+// It's encoded as json in the CloudEvent data field.
+type MessageForAgentGroupChanged struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Selector is the group's selector after the change.
+	Selector        AgentGroupSelector `json:"selector"`
+	SelectorChanged bool               `json:"selectorChanged,omitempty"`
+	AddedKeys       []string           `json:"addedKeys,omitempty"`
+	RemovedKeys     []string           `json:"removedKeys,omitempty"`
+	ModifiedKeys    []string           `json:"modifiedKeys,omitempty"`
+	ChangedAt       time.Time          `json:"changedAt"`
+	ChangedBy       string             `json:"changedBy,omitempty"`
+}
+
+// AgentGroupSelector is the wire form of an AgentGroup's agent selector.
+type AgentGroupSelector struct {
+	IdentifyingAttributes          map[string]string `json:"identifyingAttributes,omitempty"`
+	NonIdentifyingAttributes       map[string]string `json:"nonIdentifyingAttributes,omitempty"`
+	AbsentIdentifyingAttributes    []string          `json:"absentIdentifyingAttributes,omitempty"`
+	AbsentNonIdentifyingAttributes []string          `json:"absentNonIdentifyingAttributes,omitempty"`
+}
@@ -17,8 +17,11 @@ import (
 )
 
 var (
-	_ agentport.AgentUsecase          = (*AgentService)(nil)
-	_ agentport.AgentCacheInvalidator = (*AgentService)(nil)
+	_ agentport.AgentUsecase                    = (*AgentService)(nil)
+	_ agentport.AgentFleetSummaryUsecase        = (*AgentService)(nil)
+	_ agentport.AgentCommandQueueSummaryUsecase = (*AgentService)(nil)
+	_ agentport.AgentDisconnectGraceUsecase     = (*AgentService)(nil)
+	_ agentport.AgentCacheInvalidator           = (*AgentService)(nil)
 )
 
 const (
@@ -44,7 +47,8 @@ type AgentService struct {
 	// defaultNamespace is the namespace assigned to a newly-seen agent that has
 	// not reported a service.namespace. Sourced from configuration.
 	defaultNamespace string
-	// clock is consulted only for the delete connection-guard (staleness evaluation).
+	// clock is consulted for the delete connection-guard (staleness evaluation) and to
+	// stamp when an agent is first seen.
 	clock clock.PassiveClock
 }
 
@@ -171,6 +175,13 @@ func (s *AgentService) GetOrCreateAgent(ctx context.Context, instanceUID uuid.UU
 		}
 	}
 
+	// An agent registered ahead of time has not been seen until it first reports. One
+	// that reported before first-seen times were recorded is left alone rather than
+	// given a made-up time.
+	if agent.Status.LastReportedAt.IsZero() {
+		agent.RecordFirstSeen(s.clock.Now())
+	}
+
 	return agent, nil
 }
 
@@ -275,3 +286,114 @@ func (s *AgentService) SearchAgents(
 
 	return resp, nil
 }
+
+// ListAgentsByPackage implements agentport.AgentUsecase.
+func (s *AgentService) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	resp, err := s.agentPersistencePort.ListAgentsByPackage(ctx, namespace, packageName, packageVersion, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents by package: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAgentAttributes implements agentport.AgentUsecase.
+func (s *AgentService) ListAgentAttributes(
+	ctx context.Context,
+	valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	catalog, err := s.agentPersistencePort.ListAgentAttributes(ctx, valueLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent attributes: %w", err)
+	}
+
+	return catalog, nil
+}
+
+// GetAgentFleetSummary implements agentport.AgentFleetSummaryUsecase.
+//
+// The counts come straight from persistence rather than the cache, which only holds
+// the agents this server has seen.
+func (s *AgentService) GetAgentFleetSummary(ctx context.Context) (*agentmodel.AgentFleetSummary, error) {
+	summary, err := s.agentPersistencePort.GetAgentFleetSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent fleet summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// GetCommandQueueSummary implements agentport.AgentCommandQueueSummaryUsecase.
+//
+// Like the fleet summary, the totals come straight from persistence, so they cover the
+// agents of every server.
+func (s *AgentService) GetCommandQueueSummary(
+	ctx context.Context,
+	maxPending int,
+) (*agentmodel.CommandQueueSummary, error) {
+	summary, err := s.agentPersistencePort.GetCommandQueueSummary(ctx, maxPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command queue summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// ListAgentsConnectionLostBy implements agentport.AgentDisconnectGraceUsecase.
+//
+// The agents come straight from persistence rather than the cache, so a connection lost on
+// another server, or on a server that has since stopped, is found too.
+func (s *AgentService) ListAgentsConnectionLostBy(
+	ctx context.Context,
+	lostBy time.Time,
+	limit int,
+) ([]*agentmodel.Agent, error) {
+	agents, err := s.agentPersistencePort.ListAgentsConnectionLostBy(ctx, lostBy, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents whose connection was lost: %w", err)
+	}
+
+	return agents, nil
+}
+
+// CheckNewInstanceUIDAvailable implements agentport.AgentUsecase.
+//
+// Both lookups go to persistence rather than the cache, so a reassignment requested on
+// another server is seen. The check is not atomic with the later save: two concurrent
+// requests for the same UID can still both pass.
+func (s *AgentService) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	if newInstanceUID == instanceUID {
+		return nil
+	}
+
+	_, err := s.agentPersistencePort.GetAgent(ctx, newInstanceUID)
+	switch {
+	case err == nil:
+		return fmt.Errorf("agent %s already exists: %w", newInstanceUID, agentport.ErrNewInstanceUIDInUse)
+	case !errors.Is(err, model.ErrResourceNotExist):
+		return fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	pending, err := s.agentPersistencePort.GetAgentByNewInstanceUID(ctx, newInstanceUID)
+	switch {
+	case errors.Is(err, model.ErrResourceNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get agent by new instance UID: %w", err)
+	case pending.Metadata.InstanceUID != instanceUID:
+		return fmt.Errorf("agent %s is pending reassignment to %s: %w",
+			pending.Metadata.InstanceUID, newInstanceUID, agentport.ErrNewInstanceUIDInUse)
+	default:
+		return nil
+	}
+}
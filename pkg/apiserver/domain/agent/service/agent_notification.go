@@ -48,14 +48,17 @@ type notificationBatch struct {
 	sourceServerID string
 	targetServerID string
 	uids           []uuid.UUID
+	sequenceNums   map[uuid.UUID]int64
 }
 
-// pendingBucket holds the deduped UIDs pending dispatch for a single target server.
+// pendingBucket holds the deduped UIDs pending dispatch for a single target server,
+// along with the latest update sequence number seen for each of them.
 // Each bucket has its own lock so writes against different target servers don't
 // contend on a single shared mutex.
 type pendingBucket struct {
-	mu   sync.Mutex
-	uids sets.UUID
+	mu           sync.Mutex
+	uids         sets.UUID
+	sequenceNums map[uuid.UUID]int64
 }
 
 // AgentNotificationService handles notifications about agent updates.
@@ -188,7 +191,7 @@ func (s *AgentNotificationService) NotifyAgentUpdated(ctx context.Context, agent
 		return nil
 	}
 
-	s.enqueue(serverID, agent.Metadata.InstanceUID)
+	s.enqueue(serverID, agent.Metadata.InstanceUID, agent.Metadata.ResourceVersion)
 
 	return nil
 }
@@ -220,15 +223,16 @@ func (s *AgentNotificationService) waitForWorkers() {
 	}
 }
 
-func (s *AgentNotificationService) enqueue(serverID string, instanceUID uuid.UUID) {
+func (s *AgentNotificationService) enqueue(serverID string, instanceUID uuid.UUID, sequenceNum int64) {
 	// Load first to avoid allocating a fresh bucket+set on every call. serverIDs
 	// are stable (one per peer server, ~10 in production), so the key is almost
 	// always present after warm-up.
 	val, found := s.pending.Load(serverID)
 	if !found {
 		val, _ = s.pending.LoadOrStore(serverID, &pendingBucket{
-			mu:   sync.Mutex{},
-			uids: sets.NewUUID(),
+			mu:           sync.Mutex{},
+			uids:         sets.NewUUID(),
+			sequenceNums: make(map[uuid.UUID]int64),
 		})
 	}
 
@@ -241,6 +245,11 @@ func (s *AgentNotificationService) enqueue(serverID string, instanceUID uuid.UUI
 
 	bucket.mu.Lock()
 	bucket.uids.Insert(instanceUID)
+
+	if sequenceNum > bucket.sequenceNums[instanceUID] {
+		bucket.sequenceNums[instanceUID] = sequenceNum
+	}
+
 	size := bucket.uids.Len()
 	bucket.mu.Unlock()
 
@@ -262,6 +271,7 @@ func (s *AgentNotificationService) flushAll(ctx context.Context) {
 	type drained struct {
 		targetServerID string
 		uids           []uuid.UUID
+		sequenceNums   map[uuid.UUID]int64
 	}
 
 	var batches []drained
@@ -285,10 +295,12 @@ func (s *AgentNotificationService) flushAll(ctx context.Context) {
 		}
 
 		uids := bucket.uids.List()
+		sequenceNums := bucket.sequenceNums
 		bucket.uids = sets.NewUUID()
+		bucket.sequenceNums = make(map[uuid.UUID]int64)
 		bucket.mu.Unlock()
 
-		batches = append(batches, drained{targetServerID: targetServerID, uids: uids})
+		batches = append(batches, drained{targetServerID: targetServerID, uids: uids, sequenceNums: sequenceNums})
 
 		return true
 	})
@@ -298,6 +310,7 @@ func (s *AgentNotificationService) flushAll(ctx context.Context) {
 			sourceServerID: sourceServerID,
 			targetServerID: item.targetServerID,
 			uids:           item.uids,
+			sequenceNums:   item.sequenceNums,
 		}
 
 		select {
@@ -317,19 +330,14 @@ func (s *AgentNotificationService) dispatchWorker(ctx context.Context) {
 	defer s.workerWG.Done()
 
 	for batch := range s.dispatchCh {
-		s.dispatchBatch(ctx, batch.sourceServerID, batch.targetServerID, batch.uids)
+		s.dispatchBatch(ctx, batch)
 	}
 }
 
-func (s *AgentNotificationService) dispatchBatch(
-	ctx context.Context,
-	sourceServerID string,
-	targetServerID string,
-	uids []uuid.UUID,
-) {
+func (s *AgentNotificationService) dispatchBatch(ctx context.Context, batch notificationBatch) {
 	logger := s.logger.With(
-		slog.String("targetServerID", targetServerID),
-		slog.Int("agentCount", len(uids)),
+		slog.String("targetServerID", batch.targetServerID),
+		slog.Int("agentCount", len(batch.uids)),
 	)
 
 	// On shutdown the parent ctx is cancelled and the dispatch channel is still
@@ -341,7 +349,7 @@ func (s *AgentNotificationService) dispatchBatch(
 		return
 	}
 
-	server, err := s.serverUsecase.GetServer(ctx, targetServerID)
+	server, err := s.serverUsecase.GetServer(ctx, batch.targetServerID)
 	if err != nil {
 		logDispatchFailure(logger, "failed to dispatch notification: cannot get target server", err)
 
@@ -349,14 +357,18 @@ func (s *AgentNotificationService) dispatchBatch(
 	}
 
 	err = s.serverMessageUsecase.SendMessageToServer(ctx, server, serverevent.Message{
-		Source: sourceServerID,
-		Target: targetServerID,
+		Source: batch.sourceServerID,
+		Target: batch.targetServerID,
 		Type:   serverevent.MessageTypeSendServerToAgent,
 		Payload: serverevent.MessagePayload{
 			MessageForServerToAgent: &serverevent.MessageForServerToAgent{
-				TargetAgentInstanceUIDs: uids,
+				TargetAgentInstanceUIDs: batch.uids,
+				TargetAgentSequenceNums: batch.sequenceNums,
 			},
-			MessageForInvalidateAgentCache: nil,
+			MessageForInvalidateAgentCache:        nil,
+			MessageForAgentGroupChanged:           nil,
+			MessageForDisconnectAgent:             nil,
+			MessageForAgentEffectiveConfigChanged: nil,
 		},
 	})
 	if err != nil {
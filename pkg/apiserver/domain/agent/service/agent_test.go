@@ -119,6 +119,93 @@ func (m *MockAgentPersistencePort) SearchAgents(
 	return resp, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *MockAgentPersistencePort) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	resp, ok := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+	if !ok {
+		return nil, errUnexpectedType
+	}
+
+	return resp, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentPersistencePort) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentPersistencePort) GetAgentFleetSummary(ctx context.Context) (*agentmodel.AgentFleetSummary, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	summary, _ := args.Get(0).(*agentmodel.AgentFleetSummary)
+
+	return summary, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentPersistencePort) GetCommandQueueSummary(
+	ctx context.Context, maxPending int,
+) (*agentmodel.CommandQueueSummary, error) {
+	args := m.Called(ctx, maxPending)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	summary, _ := args.Get(0).(*agentmodel.CommandQueueSummary)
+
+	return summary, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentPersistencePort) ListAgentsConnectionLostBy(
+	ctx context.Context, lostBy time.Time, limit int,
+) ([]*agentmodel.Agent, error) {
+	args := m.Called(ctx, lostBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	agents, _ := args.Get(0).([]*agentmodel.Agent)
+
+	return agents, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentPersistencePort) GetAgentByNewInstanceUID(
+	ctx context.Context,
+	newInstanceUID uuid.UUID,
+) (*agentmodel.Agent, error) {
+	args := m.Called(ctx, newInstanceUID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	agent, ok := args.Get(0).(*agentmodel.Agent)
+	if !ok {
+		return nil, errUnexpectedType
+	}
+
+	return agent, args.Error(1) //nolint:wrapcheck // mock error
+}
+
 // MockServerMessageUsecase is a mock implementation of ServerMessageUsecase.
 type MockServerMessageUsecase struct {
 	mock.Mock
@@ -769,6 +856,66 @@ func TestAgentService_DeleteAgent_PersistenceError(t *testing.T) {
 	mockPersistence.AssertExpectations(t)
 }
 
+func TestAgentService_CheckNewInstanceUIDAvailable(t *testing.T) {
+	t.Parallel()
+
+	instanceUID := uuid.New()
+	newInstanceUID := uuid.New()
+
+	pendingAgent := agentmodel.NewAgent(uuid.New())
+	pendingAgent.Spec.NewInstanceUID = newInstanceUID
+
+	selfPending := agentmodel.NewAgent(instanceUID)
+	selfPending.Spec.NewInstanceUID = newInstanceUID
+
+	tests := []struct {
+		name     string
+		existing *agentmodel.Agent
+		pending  *agentmodel.Agent
+		err      error
+	}{
+		{name: "unused UID", existing: nil, pending: nil, err: nil},
+		{name: "UID of an existing agent", existing: agentmodel.NewAgent(newInstanceUID), pending: nil,
+			err: agentport.ErrNewInstanceUIDInUse},
+		{name: "UID pending for another agent", existing: nil, pending: pendingAgent,
+			err: agentport.ErrNewInstanceUIDInUse},
+		{name: "UID already pending for the same agent", existing: nil, pending: selfPending, err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			mockPersistence := new(MockAgentPersistencePort)
+
+			if tt.existing != nil {
+				mockPersistence.On("GetAgent", ctx, newInstanceUID).Return(tt.existing, nil)
+			} else {
+				mockPersistence.On("GetAgent", ctx, newInstanceUID).Return(nil, model.ErrResourceNotExist)
+			}
+
+			if tt.pending != nil {
+				mockPersistence.On("GetAgentByNewInstanceUID", ctx, newInstanceUID).Return(tt.pending, nil)
+			} else {
+				mockPersistence.On("GetAgentByNewInstanceUID", ctx, newInstanceUID).
+					Return(nil, model.ErrResourceNotExist)
+			}
+
+			svc := newTestAgentService(mockPersistence, slog.Default())
+
+			err := svc.CheckNewInstanceUIDAvailable(ctx, instanceUID, newInstanceUID)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestAgentService_Shutdown(t *testing.T) {
 	t.Parallel()
 
@@ -796,3 +943,74 @@ func TestAgentService_Shutdown(t *testing.T) {
 	mockPersistence.AssertExpectations(t)
 	mockPersistence.AssertNumberOfCalls(t, "GetAgent", 2)
 }
+
+func TestAgentService_GetOrCreateAgent_FirstSeenAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("repeated reports keep the first-seen time", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+
+		mockPersistence := new(MockAgentPersistencePort)
+		mockPersistence.On("GetAgent", ctx, instanceUID).Return(nil, model.ErrResourceNotExist)
+		mockPersistence.On("PutAgent", ctx, mock.Anything).Return(nil)
+
+		svc := newTestAgentService(mockPersistence, slog.Default())
+
+		agent, err := svc.GetOrCreateAgent(ctx, instanceUID)
+		require.NoError(t, err)
+
+		firstSeenAt := agent.Status.FirstSeenAt
+		require.False(t, firstSeenAt.IsZero())
+
+		reportedAt := firstSeenAt
+		for range 3 {
+			reportedAt = reportedAt.Add(time.Minute)
+			agent.UpdateLastCommunicationInfo(reportedAt, nil)
+			require.NoError(t, svc.SaveAgent(ctx, agent))
+
+			agent, err = svc.GetOrCreateAgent(ctx, instanceUID)
+			require.NoError(t, err)
+
+			assert.Equal(t, firstSeenAt, agent.Status.FirstSeenAt)
+			assert.Equal(t, reportedAt, agent.Status.LastReportedAt)
+		}
+	})
+
+	t.Run("an agent registered ahead of time is first seen when it reports", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+
+		mockPersistence := new(MockAgentPersistencePort)
+		mockPersistence.On("GetAgent", ctx, instanceUID).Return(agentmodel.NewAgent(instanceUID), nil)
+
+		svc := newTestAgentService(mockPersistence, slog.Default())
+
+		agent, err := svc.GetOrCreateAgent(ctx, instanceUID)
+		require.NoError(t, err)
+		assert.False(t, agent.Status.FirstSeenAt.IsZero())
+	})
+
+	t.Run("an agent that reported before first-seen was recorded is left alone", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+
+		existing := agentmodel.NewAgent(instanceUID)
+		existing.UpdateLastCommunicationInfo(time.Now().Add(-time.Hour), nil)
+
+		mockPersistence := new(MockAgentPersistencePort)
+		mockPersistence.On("GetAgent", ctx, instanceUID).Return(existing, nil)
+
+		svc := newTestAgentService(mockPersistence, slog.Default())
+
+		agent, err := svc.GetOrCreateAgent(ctx, instanceUID)
+		require.NoError(t, err)
+		assert.True(t, agent.Status.FirstSeenAt.IsZero())
+	})
+}
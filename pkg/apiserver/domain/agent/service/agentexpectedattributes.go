@@ -0,0 +1,64 @@
+package agentservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var _ agentport.AgentExpectedAttributesUsecase = (*AgentExpectedAttributesService)(nil)
+
+// AgentExpectedAttributesService manages the attributes an agent is expected to keep
+// reporting. The AttributeDrift condition is re-evaluated whenever the expectations
+// change here, and whenever the agent reports its description or is reconciled.
+type AgentExpectedAttributesService struct {
+	agentUsecase agentport.AgentUsecase
+
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewAgentExpectedAttributesService creates a new AgentExpectedAttributesService.
+func NewAgentExpectedAttributesService(
+	agentUsecase agentport.AgentUsecase,
+	logger *slog.Logger,
+) *AgentExpectedAttributesService {
+	return &AgentExpectedAttributesService{
+		agentUsecase: agentUsecase,
+		clock:        clock.NewRealClock(),
+		logger:       logger,
+	}
+}
+
+// SetClock overrides the clock used for condition timestamps. Intended for tests.
+func (s *AgentExpectedAttributesService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetExpectedAttributes implements [agentport.AgentExpectedAttributesUsecase].
+func (s *AgentExpectedAttributesService) SetExpectedAttributes(
+	ctx context.Context,
+	agent *agentmodel.Agent,
+	expected map[string]string,
+	triggeredBy string,
+) error {
+	agent.SetExpectedAttributes(expected)
+
+	if agent.RecordAttributeDrift(s.clock.Now(), triggeredBy) &&
+		agent.IsConditionTrue(agentmodel.AgentConditionTypeAttributeDrift) {
+		s.logger.Info("agent does not report its expected attributes",
+			slog.String("agent", agent.Metadata.InstanceUID.String()),
+			slog.String("drift", agent.GetCondition(agentmodel.AgentConditionTypeAttributeDrift).Message))
+	}
+
+	err := s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("save agent %s with expected attributes: %w", agent.Metadata.InstanceUID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package agentservice
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+func TestAgentExpectedAttributesService_SetExpectedAttributes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newReportingAgent := func() *agentmodel.Agent {
+		return agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+			IdentifyingAttributes:    map[string]string{"service.name": "web", "env": "prod"},
+			NonIdentifyingAttributes: map[string]string{"host.arch": "amd64"},
+		}))
+	}
+
+	newService := func(t *testing.T, target *agentmodel.Agent) *AgentExpectedAttributesService {
+		t.Helper()
+
+		mockAgentUC := new(mockAgentUsecase)
+		mockAgentUC.On("SaveAgent", t.Context(), target).Return(nil)
+		t.Cleanup(func() { mockAgentUC.AssertExpectations(t) })
+
+		svc := NewAgentExpectedAttributesService(mockAgentUC, slog.Default())
+		svc.SetClock(fixedNowClock{Clock: clock.NewRealClock(), now: now})
+
+		return svc
+	}
+
+	t.Run("matching attributes clear the drift condition", func(t *testing.T) {
+		t.Parallel()
+
+		target := newReportingAgent()
+		svc := newService(t, target)
+
+		err := svc.SetExpectedAttributes(t.Context(), target,
+			map[string]string{"env": "prod", "host.arch": "amd64"}, "user:alice")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"env": "prod", "host.arch": "amd64"}, target.Spec.ExpectedAttributes)
+		assert.Empty(t, target.AttributeMismatches())
+
+		condition := target.GetCondition(agentmodel.AgentConditionTypeAttributeDrift)
+		require.NotNil(t, condition)
+		assert.Equal(t, agentmodel.AgentConditionStatusFalse, condition.Status)
+		assert.Equal(t, "user:alice", condition.Reason)
+	})
+
+	t.Run("drifted attributes raise the drift condition", func(t *testing.T) {
+		t.Parallel()
+
+		target := newReportingAgent()
+		svc := newService(t, target)
+
+		err := svc.SetExpectedAttributes(t.Context(), target,
+			map[string]string{"env": "staging", "region": "eu", "service.name": "web"}, "user:alice")
+		require.NoError(t, err)
+
+		assert.Equal(t, []agentmodel.AttributeMismatch{
+			{Key: "env", Expected: "staging", Reported: "prod", Missing: false},
+			{Key: "region", Expected: "eu", Reported: "", Missing: true},
+		}, target.AttributeMismatches())
+
+		condition := target.GetCondition(agentmodel.AgentConditionTypeAttributeDrift)
+		require.NotNil(t, condition)
+		assert.Equal(t, agentmodel.AgentConditionStatusTrue, condition.Status)
+		assert.Equal(t, now, condition.LastTransitionTime)
+		assert.Equal(t, `env: expected "staging", reported "prod"; region: expected "eu", not reported`,
+			condition.Message)
+	})
+
+	t.Run("the condition follows the attributes the agent reports", func(t *testing.T) {
+		t.Parallel()
+
+		target := newReportingAgent()
+		svc := newService(t, target)
+
+		require.NoError(t, svc.SetExpectedAttributes(t.Context(), target, map[string]string{"env": "prod"}, "user:alice"))
+		assert.False(t, target.IsConditionTrue(agentmodel.AgentConditionTypeAttributeDrift))
+
+		require.NoError(t, target.ReportDescription(&agent.Description{
+			IdentifyingAttributes: map[string]string{"service.name": "web", "env": "dev"},
+		}))
+		assert.True(t, target.RecordAttributeDrift(now, "agent"))
+		assert.True(t, target.IsConditionTrue(agentmodel.AgentConditionTypeAttributeDrift))
+		assert.False(t, target.RecordAttributeDrift(now, "agent"), "an unchanged drift is not a change")
+	})
+
+	t.Run("removing the expectations clears the drift condition", func(t *testing.T) {
+		t.Parallel()
+
+		target := newReportingAgent()
+		svc := newService(t, target)
+
+		require.NoError(t, svc.SetExpectedAttributes(t.Context(), target, map[string]string{"env": "dev"}, "user:alice"))
+		require.True(t, target.IsConditionTrue(agentmodel.AgentConditionTypeAttributeDrift))
+
+		require.NoError(t, svc.SetExpectedAttributes(t.Context(), target, nil, "user:alice"))
+		assert.Nil(t, target.Spec.ExpectedAttributes)
+		assert.Equal(t, agentmodel.AgentConditionStatusFalse,
+			target.GetCondition(agentmodel.AgentConditionTypeAttributeDrift).Status)
+	})
+
+	t.Run("an agent without expectations gets no condition", func(t *testing.T) {
+		t.Parallel()
+
+		target := newReportingAgent()
+
+		assert.False(t, target.RecordAttributeDrift(now, "agent"))
+		assert.Nil(t, target.GetCondition(agentmodel.AgentConditionTypeAttributeDrift))
+	})
+}
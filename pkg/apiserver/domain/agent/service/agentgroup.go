@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"strconv"
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
@@ -44,8 +47,17 @@ var ErrInvalidRemoteConfig = errors.New("invalid remote config: both spec and na
 // the same name, which would otherwise silently drop one of them.
 var ErrDuplicateRemoteConfigName = errors.New("duplicate remote config name within agent group")
 
+// ErrAgentOutsideAgentGroupNamespace is returned when an agent group is applied to an agent
+// in another namespace, which the group does not govern.
+var ErrAgentOutsideAgentGroupNamespace = errors.New("agent is outside the agent group's namespace")
+
+// ErrAgentQuarantined is returned when an agent group is applied to a quarantined agent,
+// which receives no new config until its quarantine is lifted.
+var ErrAgentQuarantined = errors.New("agent is quarantined")
+
 var _ agentport.AgentGroupUsecase = (*AgentGroupService)(nil)
 var _ agentport.AgentGroupRelatedUsecase = (*AgentGroupService)(nil)
+var _ agentport.AgentGroupNameUsecase = (*AgentGroupService)(nil)
 
 // AgentGroupService is a struct that implements the AgentGroupUsecase interface.
 type AgentGroupService struct {
@@ -62,6 +74,16 @@ type AgentGroupService struct {
 	// leaderElector gates the periodic reconcile loop so only one node runs it.
 	leaderElector agentport.LeaderElector
 
+	// remoteConfigKeyFormat namespaces inline config names under their group name.
+	remoteConfigKeyFormat agentmodel.RemoteConfigKeyFormat
+
+	// remoteConfigValidators enforce config policies on every config a group resolves to.
+	remoteConfigValidators []agentport.RemoteConfigValidator
+
+	// baseRemoteConfigs are merged, with the lowest priority, into the remote config of
+	// every agent an agent group governs unless one of its groups excludes them.
+	baseRemoteConfigs map[string]agentmodel.AgentConfigFile
+
 	// internalStatus
 	changedAgentGroupCh chan *agentmodel.AgentGroup
 
@@ -85,6 +107,9 @@ func NewAgentGroupService(
 		certificatePersistencePort:  certificatePersistencePort,
 		agentUsecase:                agentUsecase,
 		leaderElector:               leaderElector,
+		remoteConfigKeyFormat:       agentmodel.DefaultRemoteConfigKeyFormat(),
+		remoteConfigValidators:      nil,
+		baseRemoteConfigs:           nil,
 		clock:                       clock.NewRealClock(),
 		logger:                      logger,
 		changedAgentGroupCh:         make(chan *agentmodel.AgentGroup, ChangedAgentGroupBufferSize),
@@ -96,6 +121,23 @@ func (s *AgentGroupService) SetClock(c clock.Clock) {
 	s.clock = c
 }
 
+// SetRemoteConfigKeyFormat overrides how inline config names are namespaced under
+// their group name (see agentmodel.RemoteConfigKeyFormat).
+func (s *AgentGroupService) SetRemoteConfigKeyFormat(format agentmodel.RemoteConfigKeyFormat) {
+	s.remoteConfigKeyFormat = format
+}
+
+// SetRemoteConfigValidators replaces the validators every resolved remote config must pass.
+func (s *AgentGroupService) SetRemoteConfigValidators(validators ...agentport.RemoteConfigValidator) {
+	s.remoteConfigValidators = validators
+}
+
+// SetBaseRemoteConfigs replaces the base remote configs, keyed by the name they are
+// delivered under. A group config delivered under the same name overrides a base config.
+func (s *AgentGroupService) SetBaseRemoteConfigs(configs map[string]agentmodel.AgentConfigFile) {
+	s.baseRemoteConfigs = configs
+}
+
 // Name implements scheduler.Scheduler.
 func (s *AgentGroupService) Name() string {
 	return agentGroupServiceName
@@ -159,14 +201,152 @@ func (s *AgentGroupService) ReconcileAgentGroup(ctx context.Context, namespace,
 	return nil
 }
 
-// SaveAgentGroup saves the agent group.
+// PropagateAgentGroup re-applies the named agent group to its matching agents and reports
+// per agent whether it was updated, already up to date, or failed. Unlike
+// ReconcileAgentGroup a failing agent does not fail the call; it is listed in the result.
+func (s *AgentGroupService) PropagateAgentGroup(
+	ctx context.Context,
+	namespace, name string,
+) (*agentmodel.AgentGroupPropagation, error) {
+	agentGroup, err := s.persistencePort.GetAgentGroup(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent group: %w", err)
+	}
+
+	propagation, err := s.propagateAgentGroup(ctx, agentGroup)
+	if err != nil {
+		return nil, fmt.Errorf("propagate agent group %s/%s: %w", namespace, name, err)
+	}
+
+	return propagation, nil
+}
+
+// ApplyAgentGroupToAgents applies the named agent group's remote configs to exactly the
+// given agents, whether or not its selector matches them, and reports per agent whether it
+// was updated, already up to date, or failed. The configs are set on each agent as direct
+// remote configs under their resolved names, inline ones prefixed with the group name, so
+// the reconcile of the agent's matching groups keeps them. Later changes to the group do
+// not reach them; apply the group again for that.
+//
+// An agent in another namespace, a quarantined agent and an agent that does not accept
+// remote config are listed as failed. An agent the selector already matches receives the
+// configs from the group itself and is counted as unchanged.
+func (s *AgentGroupService) ApplyAgentGroupToAgents(
+	ctx context.Context,
+	namespace, name string,
+	instanceUIDs []uuid.UUID,
+) (*agentmodel.AgentGroupPropagation, error) {
+	agentGroup, err := s.persistencePort.GetAgentGroup(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get agent group: %w", err)
+	}
+
+	configs, err := s.collectGroupRemoteConfigs(ctx, agentGroup)
+	if err != nil {
+		return nil, fmt.Errorf("resolve remote configs of agent group %s/%s: %w", namespace, name, err)
+	}
+
+	//exhaustruct:ignore
+	propagation := &agentmodel.AgentGroupPropagation{}
+	seen := make(map[uuid.UUID]struct{}, len(instanceUIDs))
+
+	for _, instanceUID := range instanceUIDs {
+		if _, dup := seen[instanceUID]; dup {
+			continue
+		}
+
+		seen[instanceUID] = struct{}{}
+
+		updated, err := s.applyRemoteConfigsToAgent(ctx, agentGroup, configs, instanceUID)
+		switch {
+		case err != nil:
+			propagation.Failed = append(propagation.Failed, agentmodel.AgentPropagationFailure{
+				InstanceUID: instanceUID,
+				Err:         err,
+			})
+		case updated:
+			propagation.Updated++
+		default:
+			propagation.Unchanged++
+		}
+	}
+
+	return propagation, nil
+}
+
+// applyRemoteConfigsToAgent sets the agent group's resolved configs on the agent as direct
+// remote configs and saves the agent, reporting whether it changed.
+func (s *AgentGroupService) applyRemoteConfigsToAgent(
+	ctx context.Context,
+	agentGroup *agentmodel.AgentGroup,
+	configs map[string]agentmodel.AgentConfigFile,
+	instanceUID uuid.UUID,
+) (bool, error) {
+	agent, err := s.agentUsecase.GetAgent(ctx, instanceUID)
+	if err != nil {
+		return false, fmt.Errorf("get agent: %w", err)
+	}
+
+	switch {
+	case agent.Metadata.Namespace != agentGroup.Metadata.Namespace:
+		return false, fmt.Errorf("%w: agent is in namespace %q", ErrAgentOutsideAgentGroupNamespace,
+			agent.Metadata.Namespace)
+	case agent.IsQuarantined():
+		return false, ErrAgentQuarantined
+	case !agent.IsRemoteConfigSupported():
+		return false, fmt.Errorf("%w: agent does not accept remote config", agentmodel.ErrUnsupportedAgentOperation)
+	case agentGroup.Spec.Selector.Matches(agent):
+		return false, nil
+	}
+
+	before := agentSpecFingerprint(agent)
+
+	direct := agent.DirectRemoteConfigs()
+	maps.Copy(direct, configs)
+	agent.SetDirectRemoteConfigs(direct)
+
+	if agentSpecFingerprint(agent) == before {
+		return false, nil
+	}
+
+	err = s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return false, fmt.Errorf("save updated agent: %w", err)
+	}
+
+	return true, nil
+}
+
+// SaveAgentGroup saves the agent group. An invalid selector, rollout or priority, or a remote
+// config rejected by a validator, fails the save with a field error pointing at the offending
+// entry.
 func (s *AgentGroupService) SaveAgentGroup(
 	ctx context.Context,
 	namespace string,
 	name string,
 	agentGroup *agentmodel.AgentGroup,
 ) (*agentmodel.AgentGroup, error) {
-	agentGroup, err := s.persistencePort.PutAgentGroup(ctx, namespace, name, agentGroup)
+	err := agentGroup.Spec.Selector.Validate("spec.selector.")
+	if err != nil {
+		return nil, fmt.Errorf("validate selector: %w", err)
+	}
+
+	err = agentGroup.ValidateRollout()
+	if err != nil {
+		return nil, fmt.Errorf("validate rollout: %w", err)
+	}
+
+	err = agentGroup.ValidatePriority()
+	if err != nil {
+		return nil, fmt.Errorf("validate priority: %w", err)
+	}
+
+	err = s.validateGroupRemoteConfigs(ctx, agentGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	agentGroup, err = s.persistencePort.PutAgentGroup(ctx, namespace, name, agentGroup)
 	if err != nil {
 		return nil, fmt.Errorf("save agent group: %w", err)
 	}
@@ -192,6 +372,20 @@ func (s *AgentGroupService) ListAgentGroups(
 	return resp, nil
 }
 
+// ListAgentGroupNamesIgnoringCase implements agentport.AgentGroupNameUsecase.
+func (s *AgentGroupService) ListAgentGroupNamesIgnoringCase(
+	ctx context.Context,
+	namespace string,
+	name string,
+) ([]string, error) {
+	names, err := s.persistencePort.ListAgentGroupNamesIgnoringCase(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("list agent group names ignoring case: %w", err)
+	}
+
+	return names, nil
+}
+
 // DeleteAgentGroup marks an agent group as deleted.
 func (s *AgentGroupService) DeleteAgentGroup(
 	ctx context.Context,
@@ -280,7 +474,7 @@ func (s *AgentGroupService) GetAgentGroupsForAgent(
 			continue
 		}
 
-		if matchesSelector(agent, group.Spec.Selector) {
+		if group.Spec.Selector.Matches(agent) {
 			matchingGroups = append(matchingGroups, group)
 		}
 	}
@@ -288,27 +482,6 @@ func (s *AgentGroupService) GetAgentGroupsForAgent(
 	return matchingGroups, nil
 }
 
-// matchesSelector checks if an agent matches the given selector.
-func matchesSelector(agent *agentmodel.Agent, selector agentmodel.AgentSelector) bool {
-	// Check identifying attributes
-	for key, value := range selector.IdentifyingAttributes {
-		agentValue, ok := agent.Metadata.Description.IdentifyingAttributes[key]
-		if !ok || agentValue != value {
-			return false
-		}
-	}
-
-	// Check non-identifying attributes
-	for key, value := range selector.NonIdentifyingAttributes {
-		agentValue, ok := agent.Metadata.Description.NonIdentifyingAttributes[key]
-		if !ok || agentValue != value {
-			return false
-		}
-	}
-
-	return true
-}
-
 // PropagateAgentRemoteConfigChange queues propagation for every agent group in the
 // namespace that references the named AgentRemoteConfig via AgentRemoteConfigRef. Inline
 // configs are stored on the group itself and need no re-propagation when an external
@@ -354,19 +527,32 @@ func (s *AgentGroupService) PropagateAgentRemoteConfigChange(
 // state from the union of all matching, non-deleted agent groups and applies it to the
 // agent in place. RemoteConfigs are REPLACED (not merged) so entries left behind by
 // previously-matching groups are cleared. The caller is responsible for persisting.
+//
+// The base remote configs are laid down first so that group configs delivered under the
+// same name override them.
+//
+// A quarantined agent is left untouched so no new config is pushed to it; its groups are
+// applied again once the quarantine is lifted.
 func (s *AgentGroupService) ApplyMatchingAgentGroupsToAgent(
 	ctx context.Context,
 	agent *agentmodel.Agent,
 ) error {
+	if agent.IsQuarantined() {
+		s.logger.Debug("skip applying agent groups to quarantined agent",
+			slog.String("agent", agent.Metadata.InstanceUID.String()))
+
+		return nil
+	}
+
 	groups, err := s.GetAgentGroupsForAgent(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("get agent groups for agent: %w", err)
 	}
 
-	desired := make(map[string]agentmodel.AgentConfigFile)
+	desired := s.baseRemoteConfigsFor(groups)
 
 	for _, group := range groups {
-		configs, err := s.collectGroupRemoteConfigs(ctx, group)
+		configs, err := s.collectGroupRemoteConfigsForAgent(ctx, group, agent)
 		if err != nil {
 			// A single group with an invalid/unresolvable config must not block the
 			// other matching groups from applying. The failure is surfaced on that
@@ -384,6 +570,8 @@ func (s *AgentGroupService) ApplyMatchingAgentGroupsToAgent(
 		maps.Copy(desired, configs)
 	}
 
+	// Configs set on the agent directly are not the groups' to drop.
+	agent.KeepDirectRemoteConfigs(desired)
 	setAgentRemoteConfigs(agent, desired)
 
 	// Connection settings still follow per-group apply semantics (last group wins).
@@ -398,16 +586,48 @@ func (s *AgentGroupService) ApplyMatchingAgentGroupsToAgent(
 	return nil
 }
 
+// GetRemoteConfigSources implements [agentport.AgentGroupUsecase]. It resolves the matching
+// groups' remote configs the same way ApplyMatchingAgentGroupsToAgent does, so the group
+// it reports for a name is the one whose config wins there. Groups that fail to resolve
+// are skipped, as they are when applying.
+func (s *AgentGroupService) GetRemoteConfigSources(
+	ctx context.Context,
+	agent *agentmodel.Agent,
+) (map[string]*agentmodel.AgentGroup, error) {
+	groups, err := s.GetAgentGroupsForAgent(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("get agent groups for agent: %w", err)
+	}
+
+	sources := make(map[string]*agentmodel.AgentGroup)
+
+	for _, group := range groups {
+		configs, err := s.collectGroupRemoteConfigsForAgent(ctx, group, agent)
+		if err != nil {
+			continue
+		}
+
+		for name := range configs {
+			sources[name] = group
+		}
+	}
+
+	return sources, nil
+}
+
 // ReconcileAgent re-applies the matching agent groups to the agent and persists the result.
 // It mirrors the per-agent step of the background reconcile loop (apply then save), so an
 // on-demand reconcile of a single agent actually takes effect — ApplyMatchingAgentGroupsToAgent
-// alone only mutates the in-memory agent and leaves persistence to the caller.
+// alone only mutates the in-memory agent and leaves persistence to the caller. The agent's
+// AttributeDrift condition is re-evaluated on the way.
 func (s *AgentGroupService) ReconcileAgent(ctx context.Context, agent *agentmodel.Agent) error {
 	err := s.ApplyMatchingAgentGroupsToAgent(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("apply matching agent groups to agent %s: %w", agent.Metadata.InstanceUID, err)
 	}
 
+	agent.RecordAttributeDrift(s.clock.Now(), agentGroupServiceName)
+
 	err = s.agentUsecase.SaveAgent(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("save reconciled agent %s: %w", agent.Metadata.InstanceUID, err)
@@ -423,18 +643,41 @@ func (s *AgentGroupService) ReconcileAgent(ctx context.Context, agent *agentmode
 func (s *AgentGroupService) collectGroupRemoteConfigs(
 	ctx context.Context,
 	group *agentmodel.AgentGroup,
+) (map[string]agentmodel.AgentConfigFile, error) {
+	return s.collectRemoteConfigs(ctx, group, group.Spec.AgentRemoteConfigs)
+}
+
+// collectGroupRemoteConfigsForAgent is collectGroupRemoteConfigs for one agent: while the
+// group rolls out a change, an agent outside the rollout keeps the configs from before it.
+func (s *AgentGroupService) collectGroupRemoteConfigsForAgent(
+	ctx context.Context,
+	group *agentmodel.AgentGroup,
+	agent *agentmodel.Agent,
+) (map[string]agentmodel.AgentConfigFile, error) {
+	return s.collectRemoteConfigs(ctx, group, group.RemoteConfigsFor(agent.Metadata.InstanceUID))
+}
+
+func (s *AgentGroupService) collectRemoteConfigs(
+	ctx context.Context,
+	group *agentmodel.AgentGroup,
+	remoteConfigs []agentmodel.AgentGroupAgentRemoteConfig,
 ) (map[string]agentmodel.AgentConfigFile, error) {
 	out := make(map[string]agentmodel.AgentConfigFile)
 
 	agentGroupName := group.Metadata.Name
 	namespace := group.Metadata.Namespace
 
-	for _, cfg := range group.Spec.AgentRemoteConfigs {
+	for _, cfg := range remoteConfigs {
 		file, name, err := s.resolveRemoteConfig(ctx, namespace, agentGroupName, cfg)
 		if err != nil {
 			return nil, err
 		}
 
+		err = s.validateRemoteConfig(ctx, name, file)
+		if err != nil {
+			return nil, err
+		}
+
 		// Two entries resolving to the same name with different content would silently
 		// overwrite one another, dropping a config without any signal — surface that on
 		// the group's RemoteConfigApplied condition. Identical duplicates are idempotent,
@@ -453,6 +696,30 @@ func (s *AgentGroupService) collectGroupRemoteConfigs(
 	return out, nil
 }
 
+// baseRemoteConfigsFor returns a copy of the base remote configs for an agent matched by
+// the given groups. An agent no group governs gets none, and any one group excluding them
+// keeps them off the agent, so a group can opt its agents out even when a broader group
+// also matches them.
+func (s *AgentGroupService) baseRemoteConfigsFor(
+	groups []*agentmodel.AgentGroup,
+) map[string]agentmodel.AgentConfigFile {
+	desired := make(map[string]agentmodel.AgentConfigFile)
+
+	if len(groups) == 0 {
+		return desired
+	}
+
+	for _, group := range groups {
+		if group.Spec.ExcludeBaseRemoteConfig {
+			return desired
+		}
+	}
+
+	maps.Copy(desired, s.baseRemoteConfigs)
+
+	return desired
+}
+
 // sameConfigFile reports whether two resolved config files are byte-for-byte equivalent,
 // so idempotent duplicate entries can be collapsed rather than treated as a conflict.
 func sameConfigFile(a, b agentmodel.AgentConfigFile) bool {
@@ -619,7 +886,9 @@ func (s *AgentGroupService) reconcileAllAgents(ctx context.Context) {
 				continue
 			}
 
-			if before == agentSpecFingerprint(agent) {
+			condChanged := agent.RecordCapabilityMismatch(s.clock.Now(), agentGroupServiceName)
+
+			if before == agentSpecFingerprint(agent) && !condChanged {
 				continue
 			}
 
@@ -691,9 +960,15 @@ func agentSpecFingerprint(agent *agentmodel.Agent) string {
 var fingerprintErrSeq atomic.Int64
 
 // agentGroupReferencesRemoteConfig reports whether any of the group's remote configs
-// references the named AgentRemoteConfig resource (i.e. via AgentRemoteConfigRef).
+// references the named AgentRemoteConfig resource (i.e. via AgentRemoteConfigRef). The
+// configs agents outside a rollout stay on count too, as those agents still receive them.
 func agentGroupReferencesRemoteConfig(group *agentmodel.AgentGroup, name string) bool {
-	for _, cfg := range group.Spec.AgentRemoteConfigs {
+	remoteConfigs := group.Spec.AgentRemoteConfigs
+	if group.HasRollout() {
+		remoteConfigs = slices.Concat(remoteConfigs, group.Spec.Rollout.PreviousAgentRemoteConfigs)
+	}
+
+	for _, cfg := range remoteConfigs {
 		if cfg.AgentRemoteConfigRef != nil && *cfg.AgentRemoteConfigRef == name {
 			return true
 		}
@@ -782,12 +1057,13 @@ func (s *AgentGroupService) recordRemoteConfigCondition(
 // condition changed (so the caller folds it into the save decision). When a config is assigned
 // to an agent that lacks the AcceptsRemoteConfig capability the condition is set to False with
 // an explanatory message — that attempt is otherwise completely invisible because the config
-// is silently never delivered. When no config is assigned the condition is left untouched.
+// is silently never delivered. When no config is assigned, or the agent is quarantined and so
+// was not touched by the group, the condition is left untouched.
 func (s *AgentGroupService) recordAgentRemoteConfigCondition(
 	agent *agentmodel.Agent,
 	agentGroup *agentmodel.AgentGroup,
 ) bool {
-	if !agent.HasAssignedRemoteConfig() {
+	if !agent.HasAssignedRemoteConfig() || agent.IsQuarantined() {
 		return false
 	}
 
@@ -815,6 +1091,24 @@ func (s *AgentGroupService) updateAgentsByAgentGroup(
 	ctx context.Context,
 	agentGroup *agentmodel.AgentGroup,
 ) error {
+	propagation, err := s.propagateAgentGroup(ctx, agentGroup)
+	if err != nil {
+		return err
+	}
+
+	return propagation.Err()
+}
+
+// propagateAgentGroup applies the group to every matching agent. An agent that fails does
+// not stop the pass; it is recorded in the result so the remaining agents still converge.
+// Only a failure to list the agents aborts it.
+func (s *AgentGroupService) propagateAgentGroup(
+	ctx context.Context,
+	agentGroup *agentmodel.AgentGroup,
+) (*agentmodel.AgentGroupPropagation, error) {
+	//exhaustruct:ignore
+	propagation := &agentmodel.AgentGroupPropagation{}
+
 	// Resolve this group's config once up front and record the outcome on its condition.
 	// This is what makes an invalid config (e.g. an inline config missing its name, or a
 	// dangling AgentRemoteConfigRef) observable instead of failing silently per agent.
@@ -829,7 +1123,7 @@ func (s *AgentGroupService) updateAgentsByAgentGroup(
 			IncludeDeleted: false,
 		})
 		if err != nil {
-			return fmt.Errorf("list agents by agent group: %w", err)
+			return nil, fmt.Errorf("list agents by agent group: %w", err)
 		}
 
 		if len(agentsResp.Items) == 0 {
@@ -844,7 +1138,12 @@ func (s *AgentGroupService) updateAgentsByAgentGroup(
 			// ever dropping ones a group removed.
 			err := s.ApplyMatchingAgentGroupsToAgent(ctx, agent)
 			if err != nil {
-				return fmt.Errorf("apply matching groups to agent %s: %w", agent.Metadata.InstanceUID, err)
+				propagation.Failed = append(propagation.Failed, agentmodel.AgentPropagationFailure{
+					InstanceUID: agent.Metadata.InstanceUID,
+					Err:         fmt.Errorf("apply matching groups: %w", err),
+				})
+
+				continue
 			}
 
 			after := agentSpecFingerprint(agent)
@@ -855,15 +1154,27 @@ func (s *AgentGroupService) updateAgentsByAgentGroup(
 			// condition can change even when the spec did not (e.g. capability flip), so it
 			// participates in the save decision alongside the spec fingerprint.
 			condChanged := s.recordAgentRemoteConfigCondition(agent, agentGroup)
+			// Connection settings and packages can be withheld for lack of a capability too;
+			// CapabilityMismatch covers every offer, not just remote config.
+			mismatchChanged := agent.RecordCapabilityMismatch(s.clock.Now(), agentGroupServiceName)
+
+			if before == after && !condChanged && !mismatchChanged {
+				propagation.Unchanged++
 
-			if before == after && !condChanged {
 				continue
 			}
 
 			err = s.agentUsecase.SaveAgent(ctx, agent)
 			if err != nil {
-				return fmt.Errorf("save updated agent: %w", err)
+				propagation.Failed = append(propagation.Failed, agentmodel.AgentPropagationFailure{
+					InstanceUID: agent.Metadata.InstanceUID,
+					Err:         fmt.Errorf("save updated agent: %w", err),
+				})
+
+				continue
 			}
+
+			propagation.Updated++
 		}
 
 		// No more pages to fetch
@@ -874,7 +1185,7 @@ func (s *AgentGroupService) updateAgentsByAgentGroup(
 		continueToken = agentsResp.Continue
 	}
 
-	return nil
+	return propagation, nil
 }
 
 func (s *AgentGroupService) resolveRemoteConfig(
@@ -907,8 +1218,9 @@ func (s *AgentGroupService) resolveRemoteConfig(
 	}
 
 	// Prefix with AgentGroupName to avoid name collisions
-	// Format: {AgentGroupName}/{AgentRemoteConfigName}
-	prefixedName := fmt.Sprintf("%s/%s", agentGroupName, *remoteConfig.AgentRemoteConfigName)
+	// Format: {AgentGroupName}{delimiter}{AgentRemoteConfigName}, with any delimiter
+	// inside either name escaped so the key parses back unambiguously.
+	prefixedName := s.remoteConfigKeyFormat.Join(agentGroupName, *remoteConfig.AgentRemoteConfigName)
 
 	return agentmodel.AgentConfigFile{
 		Body:        remoteConfig.AgentRemoteConfigSpec.Value,
@@ -916,6 +1228,62 @@ func (s *AgentGroupService) resolveRemoteConfig(
 	}, prefixedName, nil
 }
 
+// validateGroupRemoteConfigs runs the validators on each of the group's remote configs and
+// reports the first rejected one as a field error. An entry that does not resolve is left
+// to the group's RemoteConfigApplied condition, as it is when the group is propagated.
+func (s *AgentGroupService) validateGroupRemoteConfigs(ctx context.Context, group *agentmodel.AgentGroup) error {
+	if len(s.remoteConfigValidators) == 0 {
+		return nil
+	}
+
+	for index, cfg := range group.Spec.AgentRemoteConfigs {
+		file, name, err := s.resolveRemoteConfig(ctx, group.Metadata.Namespace, group.Metadata.Name, cfg)
+		if err != nil {
+			continue
+		}
+
+		err = s.validateRemoteConfig(ctx, name, file)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, agentmodel.ErrRemoteConfigRejected) {
+			return err
+		}
+
+		field := fmt.Sprintf("spec.agentConfig.agentRemoteConfigs[%d].agentRemoteConfigSpec.value", index)
+		if cfg.AgentRemoteConfigRef != nil {
+			field = fmt.Sprintf("spec.agentConfig.agentRemoteConfigs[%d].agentRemoteConfigRef", index)
+		}
+
+		var violation *agentmodel.RemoteConfigViolation
+		if errors.As(err, &violation) {
+			return &model.FieldError{Field: field, Value: violation.Key, Reason: violation.Error()}
+		}
+
+		return &model.FieldError{Field: field, Value: name, Reason: err.Error()}
+	}
+
+	return nil
+}
+
+// validateRemoteConfig runs every validator on a resolved config, stopping at the first
+// that rejects it or fails.
+func (s *AgentGroupService) validateRemoteConfig(
+	ctx context.Context,
+	name string,
+	file agentmodel.AgentConfigFile,
+) error {
+	for _, validator := range s.remoteConfigValidators {
+		err := validator.ValidateRemoteConfig(ctx, name, file)
+		if err != nil {
+			return fmt.Errorf("validate remote config %q with %s: %w", name, validator.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func (s *AgentGroupService) applyConnectionSettings(
 	ctx context.Context,
 	agentGroup *agentmodel.AgentGroup,
@@ -3,6 +3,7 @@ package agentservice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"testing"
 	"time"
@@ -79,6 +80,17 @@ func (m *mockAgentGroupPersistence) ListAgentGroups(
 	return result, args.Error(1) //nolint:wrapcheck
 }
 
+func (m *mockAgentGroupPersistence) ListAgentGroupNamesIgnoringCase(
+	ctx context.Context,
+	namespace string,
+	name string,
+) ([]string, error) {
+	args := m.Called(ctx, namespace, name)
+	names, _ := args.Get(0).([]string)
+
+	return names, args.Error(1) //nolint:wrapcheck
+}
+
 // mockAgentUsecase is a mock for AgentUsecase.
 type mockAgentUsecase struct {
 	mock.Mock
@@ -179,6 +191,49 @@ func (m *mockAgentUsecase) SearchAgents(
 	return result, args.Error(1) //nolint:wrapcheck
 }
 
+func (m *mockAgentUsecase) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck
+	}
+
+	result, ok := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+	if !ok {
+		return nil, errUnexpectedType
+	}
+
+	return result, args.Error(1) //nolint:wrapcheck
+}
+
+func (m *mockAgentUsecase) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck
+}
+
+func (m *mockAgentUsecase) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck
+}
+
 // mockRemoteConfigPersistence is a mock for AgentRemoteConfigPersistencePort.
 type mockRemoteConfigPersistence struct {
 	mock.Mock
@@ -409,6 +464,38 @@ func TestResolveRemoteConfig_DirectMode(t *testing.T) {
 		assert.Equal(t, contentType, configFile.ContentType)
 	})
 
+	t.Run("Inline config name containing the delimiter round-trips", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockCertPort := new(mockCertPersistence)
+		svc := NewAgentGroupService(
+			new(mockAgentGroupPersistence), new(mockRemoteConfigPersistence), mockCertPort,
+			new(mockAgentUsecase), alwaysLeaderElector{}, slog.Default())
+
+		format, err := agentmodel.NewRemoteConfigKeyFormat("::")
+		require.NoError(t, err)
+		svc.SetRemoteConfigKeyFormat(format)
+
+		configName := "otel::collector/main"
+		remoteConfig := agentmodel.AgentGroupAgentRemoteConfig{
+			AgentRemoteConfigName: &configName,
+			AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+				Value:       []byte("receivers: {}"),
+				ContentType: "text/yaml",
+			},
+		}
+
+		_, resolvedName, err := svc.resolveRemoteConfig(ctx, "", "staging-group", remoteConfig)
+		require.NoError(t, err)
+		assert.Equal(t, `staging-group::otel\::collector/main`, resolvedName)
+
+		group, name, ok := format.Split(resolvedName)
+		require.True(t, ok)
+		assert.Equal(t, "staging-group", group)
+		assert.Equal(t, configName, name)
+	})
+
 	t.Run("Returns error when spec is nil", func(t *testing.T) {
 		t.Parallel()
 
@@ -1097,6 +1184,85 @@ func TestUpdateAgentsByAgentGroup(t *testing.T) {
 		require.NoError(t, err)
 		mockAgentUC.AssertExpectations(t)
 	})
+
+	t.Run("Agent without AcceptsRemoteConfig is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockPersistence := new(mockAgentGroupPersistence)
+		mockAgentUC := new(mockAgentUsecase)
+		mockRemoteConfigPort := new(mockRemoteConfigPersistence)
+		mockCertPort := new(mockCertPersistence)
+		svc := NewAgentGroupService(
+			mockPersistence, mockRemoteConfigPort, mockCertPort,
+			mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+		description := &agent.Description{
+			IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+		}
+		accepting := agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig)
+		acceptingAgent := agentmodel.NewAgent(uuid.New(),
+			agentmodel.WithDescription(description), agentmodel.WithCapabilities(&accepting))
+		reportOnly := agent.Capabilities(agent.AgentCapabilityReportsStatus)
+		reportOnlyAgent := agentmodel.NewAgent(uuid.New(),
+			agentmodel.WithDescription(description), agentmodel.WithCapabilities(&reportOnly))
+
+		inlineName := "inline-config"
+		agentGroup := &agentmodel.AgentGroup{
+			Metadata: agentmodel.AgentGroupMetadata{
+				Namespace: "default",
+				Name:      "mixed",
+			},
+			Spec: agentmodel.AgentGroupSpec{
+				Selector: agentmodel.AgentSelector{
+					IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+				},
+				AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{
+					{
+						AgentRemoteConfigName: &inlineName,
+						AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+							Value:       []byte("inline config content"),
+							ContentType: "text/plain",
+						},
+					},
+				},
+			},
+		}
+
+		mockAgentUC.On("ListAgentsBySelector", ctx, agentGroup.Spec.Selector, mock.Anything).
+			Return(&model.ListResponse[*agentmodel.Agent]{
+				Items: []*agentmodel.Agent{acceptingAgent, reportOnlyAgent},
+			}, nil)
+		mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+			Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: []*agentmodel.AgentGroup{agentGroup}}, nil)
+		mockPersistence.On("GetAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+		mockPersistence.On("PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+		mockAgentUC.On("SaveAgent", ctx, mock.Anything).Return(nil)
+
+		err := svc.updateAgentsByAgentGroup(ctx, agentGroup)
+		require.NoError(t, err)
+
+		builder := NewServerToAgentBuilder(nil, slog.Default())
+
+		// The capable agent is updated and receives the config.
+		applied := acceptingAgent.GetCondition(agentmodel.AgentConditionTypeRemoteConfigApplied)
+		require.NotNil(t, applied)
+		assert.Equal(t, agentmodel.AgentConditionStatusTrue, applied.Status)
+		assert.NotNil(t, builder.Build(ctx, acceptingAgent).GetRemoteConfig())
+
+		// The other is skipped: nothing is offered, and the reason is recorded.
+		skipped := reportOnlyAgent.GetCondition(agentmodel.AgentConditionTypeRemoteConfigApplied)
+		require.NotNil(t, skipped)
+		assert.Equal(t, agentmodel.AgentConditionStatusFalse, skipped.Status)
+		assert.Contains(t, skipped.Message, "AcceptsRemoteConfig")
+		assert.Nil(t, builder.Build(ctx, reportOnlyAgent).GetRemoteConfig())
+		assert.Equal(t, []agentmodel.OfferSkip{{
+			Offer:  agentmodel.OfferRemoteConfig,
+			Reason: "agent lacks the AcceptsRemoteConfig capability",
+		}}, reportOnlyAgent.SkippedOffers())
+	})
 }
 
 func TestReconcileAllAgents(t *testing.T) {
@@ -1180,6 +1346,389 @@ func TestReconcileAllAgents(t *testing.T) {
 	})
 }
 
+func TestUpdateAgentsByAgentGroup_CapabilityMismatch(t *testing.T) {
+	t.Parallel()
+
+	configName := "inline-config"
+	agentGroup := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "grp"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{
+				{
+					AgentRemoteConfigName: &configName,
+					AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+						Value:       []byte("inline config content"),
+						ContentType: "text/plain",
+					},
+				},
+			},
+		},
+	}
+
+	// reconcile runs the group propagation for a single agent with the given capabilities.
+	reconcile := func(t *testing.T, capabilities agent.Capabilities) *agentmodel.Agent {
+		t.Helper()
+
+		ctx := t.Context()
+		mockPersistence := new(mockAgentGroupPersistence)
+		mockAgentUC := new(mockAgentUsecase)
+		svc := NewAgentGroupService(
+			mockPersistence, new(mockRemoteConfigPersistence), new(mockCertPersistence),
+			mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+		a := agentmodel.NewAgent(uuid.New(),
+			agentmodel.WithDescription(&agent.Description{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			}),
+			agentmodel.WithCapabilities(&capabilities))
+
+		mockAgentUC.On("ListAgentsBySelector", ctx, agentGroup.Spec.Selector, mock.Anything).
+			Return(&model.ListResponse[*agentmodel.Agent]{Items: []*agentmodel.Agent{a}}, nil)
+		mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+			Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: []*agentmodel.AgentGroup{agentGroup}}, nil)
+		mockPersistence.On("GetAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+		mockPersistence.On("PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+		mockAgentUC.On("SaveAgent", ctx, a).Return(nil)
+
+		require.NoError(t, svc.updateAgentsByAgentGroup(ctx, agentGroup))
+		mockAgentUC.AssertCalled(t, "SaveAgent", ctx, a)
+
+		return a
+	}
+
+	t.Run("agent without AcceptsRemoteConfig is flagged", func(t *testing.T) {
+		t.Parallel()
+
+		a := reconcile(t, agent.Capabilities(agent.AgentCapabilityReportsStatus))
+
+		cond := a.GetCondition(agentmodel.AgentConditionTypeCapabilityMismatch)
+		require.NotNil(t, cond)
+		assert.Equal(t, agentmodel.AgentConditionStatusTrue, cond.Status)
+		assert.Contains(t, cond.Message, agentmodel.OfferRemoteConfig)
+		assert.Contains(t, cond.Message, "AcceptsRemoteConfig")
+	})
+
+	t.Run("agent accepting the config is not flagged", func(t *testing.T) {
+		t.Parallel()
+
+		a := reconcile(t, agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig))
+
+		assert.Nil(t, a.GetCondition(agentmodel.AgentConditionTypeCapabilityMismatch))
+	})
+}
+
+func TestPropagateAgentGroup_ReportsPerAgentResults(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	configName := "inline-config"
+	agentGroup := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "grp"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{
+				{
+					AgentRemoteConfigName: &configName,
+					AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+						Value:       []byte("inline config content"),
+						ContentType: "text/plain",
+					},
+				},
+			},
+		},
+	}
+
+	mockPersistence := new(mockAgentGroupPersistence)
+	mockAgentUC := new(mockAgentUsecase)
+	svc := NewAgentGroupService(
+		mockPersistence, new(mockRemoteConfigPersistence), new(mockCertPersistence),
+		mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+	capabilities := agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig)
+	agents := make([]*agentmodel.Agent, 3)
+
+	for index := range agents {
+		agents[index] = agentmodel.NewAgent(uuid.New(),
+			agentmodel.WithDescription(&agent.Description{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			}),
+			agentmodel.WithCapabilities(&capabilities))
+	}
+
+	mockPersistence.On("GetAgentGroup", mock.Anything, "default", "grp", (*model.GetOptions)(nil)).
+		Return(agentGroup, nil)
+	mockPersistence.On("PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(agentGroup, nil)
+	mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+		Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: []*agentmodel.AgentGroup{agentGroup}}, nil)
+	mockAgentUC.On("ListAgentsBySelector", ctx, agentGroup.Spec.Selector, mock.Anything).
+		Return(&model.ListResponse[*agentmodel.Agent]{Items: agents}, nil)
+	mockAgentUC.On("SaveAgent", ctx, agents[0]).Return(nil)
+	mockAgentUC.On("SaveAgent", ctx, agents[1]).Return(model.ErrConflict)
+	mockAgentUC.On("SaveAgent", ctx, agents[2]).Return(nil)
+
+	// The failing agent must not keep the agents after it from being updated.
+	propagation, err := svc.PropagateAgentGroup(ctx, "default", "grp")
+	require.NoError(t, err)
+	assert.Equal(t, 2, propagation.Updated)
+	assert.Equal(t, 0, propagation.Unchanged)
+	require.Len(t, propagation.Failed, 1)
+	assert.Equal(t, agents[1].Metadata.InstanceUID, propagation.Failed[0].InstanceUID)
+	require.ErrorIs(t, propagation.Err(), model.ErrConflict)
+	mockAgentUC.AssertCalled(t, "SaveAgent", ctx, agents[2])
+
+	// Propagating again changes nothing for the agents that already carry the group's config.
+	propagation, err = svc.PropagateAgentGroup(ctx, "default", "grp")
+	require.NoError(t, err)
+	assert.Equal(t, 0, propagation.Updated)
+	assert.Equal(t, 3, propagation.Unchanged)
+	assert.Empty(t, propagation.Failed)
+	require.NoError(t, propagation.Err())
+}
+
+func TestPropagateAgentGroup_RollsOutToDeterministicSubset(t *testing.T) {
+	t.Parallel()
+
+	const numAgents = 1000
+
+	ctx := t.Context()
+	configName := "inline-config"
+	groupConfig := func(body string) []agentmodel.AgentGroupAgentRemoteConfig {
+		return []agentmodel.AgentGroupAgentRemoteConfig{
+			{
+				AgentRemoteConfigName: &configName,
+				AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+					Value:       []byte(body),
+					ContentType: "text/plain",
+				},
+			},
+		}
+	}
+	agentGroup := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "grp"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: groupConfig("new"),
+			Rollout: &agentmodel.AgentGroupRollout{
+				Percentage:                 10,
+				PreviousAgentRemoteConfigs: groupConfig("old"),
+			},
+		},
+	}
+
+	mockPersistence := new(mockAgentGroupPersistence)
+	mockAgentUC := new(mockAgentUsecase)
+	svc := NewAgentGroupService(
+		mockPersistence, new(mockRemoteConfigPersistence), new(mockCertPersistence),
+		mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+	capabilities := agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig)
+	agents := make([]*agentmodel.Agent, numAgents)
+
+	// Fixed instance UIDs keep the size of the subset, and so the test, stable.
+	for index := range agents {
+		agents[index] = agentmodel.NewAgent(uuid.NewSHA1(uuid.NameSpaceOID, fmt.Appendf(nil, "agent-%d", index)),
+			agentmodel.WithDescription(&agent.Description{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			}),
+			agentmodel.WithCapabilities(&capabilities))
+	}
+
+	mockPersistence.On("GetAgentGroup", mock.Anything, "default", "grp", (*model.GetOptions)(nil)).
+		Return(agentGroup, nil)
+	mockPersistence.On("PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(agentGroup, nil)
+	mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+		Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: []*agentmodel.AgentGroup{agentGroup}}, nil)
+	mockAgentUC.On("ListAgentsBySelector", ctx, agentGroup.Spec.Selector, mock.Anything).
+		Return(&model.ListResponse[*agentmodel.Agent]{Items: agents}, nil)
+	mockAgentUC.On("SaveAgent", ctx, mock.Anything).Return(nil)
+
+	// agentsOnNewConfig returns the agents that received the group's new config; every
+	// other agent must have stayed on the config from before the rollout.
+	agentsOnNewConfig := func(t *testing.T) map[uuid.UUID]bool {
+		t.Helper()
+
+		onNewConfig := make(map[uuid.UUID]bool)
+
+		for _, a := range agents {
+			require.NotNil(t, a.Spec.RemoteConfig)
+			require.Len(t, a.Spec.RemoteConfig.ConfigMap.ConfigMap, 1)
+
+			for _, file := range a.Spec.RemoteConfig.ConfigMap.ConfigMap {
+				if string(file.Body) == "new" {
+					onNewConfig[a.Metadata.InstanceUID] = true
+				} else {
+					assert.Equal(t, "old", string(file.Body))
+				}
+			}
+		}
+
+		return onNewConfig
+	}
+
+	propagation, err := svc.PropagateAgentGroup(ctx, "default", "grp")
+	require.NoError(t, err)
+	require.NoError(t, propagation.Err())
+
+	canary := agentsOnNewConfig(t)
+	assert.InDelta(t, numAgents/10, len(canary), numAgents*0.03, "roughly 10% of the group gets the new config")
+
+	// The subset depends on the instance UIDs alone, so propagating again moves no agent.
+	propagation, err = svc.PropagateAgentGroup(ctx, "default", "grp")
+	require.NoError(t, err)
+	assert.Equal(t, 0, propagation.Updated)
+	assert.Equal(t, numAgents, propagation.Unchanged)
+	assert.Equal(t, canary, agentsOnNewConfig(t))
+
+	// Advancing the rollout only adds agents to the subset.
+	require.NoError(t, agentGroup.AdvanceRollout(50, 0))
+
+	_, err = svc.PropagateAgentGroup(ctx, "default", "grp")
+	require.NoError(t, err)
+
+	advanced := agentsOnNewConfig(t)
+	assert.InDelta(t, numAgents/2, len(advanced), numAgents*0.05, "roughly half the group gets the new config")
+
+	for instanceUID := range canary {
+		assert.True(t, advanced[instanceUID], "an agent of the canary keeps the new config")
+	}
+}
+
+func TestApplyAgentGroupToAgents_AppliesToExplicitAgents(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	inlineName := "inline-config"
+	refName := "shared-config"
+	agentGroup := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "grp"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{
+				{
+					AgentRemoteConfigName: &inlineName,
+					AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+						Value:       []byte("inline config content"),
+						ContentType: "text/plain",
+					},
+				},
+				{AgentRemoteConfigRef: &refName},
+			},
+		},
+	}
+
+	mockPersistence := new(mockAgentGroupPersistence)
+	mockRemoteConfig := new(mockRemoteConfigPersistence)
+	mockAgentUC := new(mockAgentUsecase)
+	svc := NewAgentGroupService(
+		mockPersistence, mockRemoteConfig, new(mockCertPersistence),
+		mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+	capabilities := agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig)
+	newAgent := func(serviceName, namespace string) *agentmodel.Agent {
+		return agentmodel.NewAgent(uuid.New(),
+			agentmodel.WithNamespace(namespace),
+			agentmodel.WithDescription(&agent.Description{
+				IdentifyingAttributes: map[string]string{"service.name": serviceName},
+			}),
+			agentmodel.WithCapabilities(&capabilities))
+	}
+
+	outsider := newAgent("other-service", "default")
+	member := newAgent("my-service", "default")
+	foreign := newAgent("other-service", "other")
+	quarantined := newAgent("other-service", "default")
+	quarantined.Quarantine(time.Now(), "test", "flapping", false)
+
+	mockPersistence.On("GetAgentGroup", mock.Anything, "default", "grp", (*model.GetOptions)(nil)).
+		Return(agentGroup, nil)
+	mockRemoteConfig.On("GetAgentRemoteConfig", mock.Anything, "default", refName, (*model.GetOptions)(nil)).
+		Return(&agentmodel.AgentRemoteConfig{
+			Metadata: agentmodel.AgentRemoteConfigMetadata{Namespace: "default", Name: refName},
+			Spec: agentmodel.AgentRemoteConfigSpec{
+				Value:       []byte("shared config content"),
+				ContentType: "text/yaml",
+			},
+		}, nil)
+
+	for _, a := range []*agentmodel.Agent{outsider, member, foreign, quarantined} {
+		mockAgentUC.On("GetAgent", ctx, a.Metadata.InstanceUID).Return(a, nil)
+	}
+
+	mockAgentUC.On("SaveAgent", ctx, outsider).Return(nil)
+
+	instanceUIDs := []uuid.UUID{
+		outsider.Metadata.InstanceUID,
+		member.Metadata.InstanceUID,
+		foreign.Metadata.InstanceUID,
+		quarantined.Metadata.InstanceUID,
+		outsider.Metadata.InstanceUID,
+	}
+
+	propagation, err := svc.ApplyAgentGroupToAgents(ctx, "default", "grp", instanceUIDs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, propagation.Updated)
+	assert.Equal(t, 1, propagation.Unchanged, "an agent the selector matches gets the configs from the group")
+	require.Len(t, propagation.Failed, 2)
+	assert.Equal(t, foreign.Metadata.InstanceUID, propagation.Failed[0].InstanceUID)
+	require.ErrorIs(t, propagation.Failed[0].Err, ErrAgentOutsideAgentGroupNamespace)
+	assert.Equal(t, quarantined.Metadata.InstanceUID, propagation.Failed[1].InstanceUID)
+	require.ErrorIs(t, propagation.Failed[1].Err, ErrAgentQuarantined)
+	mockAgentUC.AssertNumberOfCalls(t, "SaveAgent", 1)
+
+	// Inline configs are prefixed with the group name, referenced ones keep their own name,
+	// and both are kept as direct configs when the agent's matching groups are reconciled.
+	require.NotNil(t, outsider.Spec.RemoteConfig)
+	assert.Equal(t, map[string]agentmodel.AgentConfigFile{
+		"grp/inline-config": {Body: []byte("inline config content"), ContentType: "text/plain"},
+		refName:             {Body: []byte("shared config content"), ContentType: "text/yaml"},
+	}, outsider.DirectRemoteConfigs())
+	assert.Contains(t, outsider.Spec.RemoteConfig.ConfigMap.ConfigMap,
+		agentmodel.DirectRemoteConfigKey("grp/inline-config"))
+
+	// Applying again changes nothing.
+	propagation, err = svc.ApplyAgentGroupToAgents(ctx, "default", "grp", instanceUIDs[:1])
+	require.NoError(t, err)
+	assert.Equal(t, 0, propagation.Updated)
+	assert.Equal(t, 1, propagation.Unchanged)
+	mockAgentUC.AssertNumberOfCalls(t, "SaveAgent", 1)
+}
+
+func TestRecordCapabilityMismatch_ClearsOnceResolved(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := agentmodel.NewAgent(uuid.New())
+	a.Spec.RemoteConfig = &agentmodel.AgentSpecRemoteConfig{
+		ConfigMap: agentmodel.AgentConfigMap{
+			ConfigMap: map[string]agentmodel.AgentConfigFile{"grp/cfg": {}},
+		},
+	}
+
+	assert.True(t, a.RecordCapabilityMismatch(now, agentGroupServiceName))
+	assert.False(t, a.RecordCapabilityMismatch(now, agentGroupServiceName))
+
+	a.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityAcceptsRemoteConfig)
+
+	assert.True(t, a.RecordCapabilityMismatch(now, agentGroupServiceName))
+
+	cond := a.GetCondition(agentmodel.AgentConditionTypeCapabilityMismatch)
+	require.NotNil(t, cond)
+	assert.Equal(t, agentmodel.AgentConditionStatusFalse, cond.Status)
+}
+
 // alwaysLeaderElector is a test double that always reports leadership, so the
 // reconcile loop behaves as it did before leader election was introduced.
 type alwaysLeaderElector struct{}
@@ -82,6 +82,17 @@ func (m *MockAgentGroupPersistencePort) ListAgentGroups(
 	return result, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *MockAgentGroupPersistencePort) ListAgentGroupNamesIgnoringCase(
+	ctx context.Context,
+	namespace string,
+	name string,
+) ([]string, error) {
+	args := m.Called(ctx, namespace, name)
+	names, _ := args.Get(0).([]string)
+
+	return names, args.Error(1) //nolint:wrapcheck // mock error
+}
+
 // MockAgentUsecaseForGroup is a mock implementation of AgentUsecase for agent group tests.
 type MockAgentUsecaseForGroup struct {
 	mock.Mock
@@ -188,6 +199,49 @@ func (m *MockAgentUsecaseForGroup) SearchAgents(
 	return result, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *MockAgentUsecaseForGroup) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	result, ok := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+	if !ok {
+		return nil, errUnexpectedType
+	}
+
+	return result, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentUsecaseForGroup) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentUsecaseForGroup) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 // MockAgentRemoteConfigPersistencePort is a mock implementation of AgentRemoteConfigPersistencePort.
 type MockAgentRemoteConfigPersistencePort struct {
 	mock.Mock
@@ -630,6 +684,255 @@ func TestAgentGroupService_ReconcileAgent(t *testing.T) {
 	})
 }
 
+func TestAgentGroupService_ApplyMatchingAgentGroupsToAgentMergesBaseRemoteConfigs(t *testing.T) {
+	t.Parallel()
+
+	baseConfigs := map[string]agentmodel.AgentConfigFile{
+		"standard-processors": {Body: []byte("processors: {batch: {}}"), ContentType: "text/yaml"},
+		"standard-extensions": {Body: []byte("extensions: {health_check: {}}"), ContentType: "text/yaml"},
+	}
+
+	newGroup := func(
+		name string, excludeBase bool, remoteConfigs ...agentmodel.AgentGroupAgentRemoteConfig,
+	) *agentmodel.AgentGroup {
+		return &agentmodel.AgentGroup{
+			Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: name},
+			Spec: agentmodel.AgentGroupSpec{
+				Selector: agentmodel.AgentSelector{
+					IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+				},
+				AgentRemoteConfigs:      remoteConfigs,
+				ExcludeBaseRemoteConfig: excludeBase,
+			},
+		}
+	}
+
+	setup := func(t *testing.T, groups ...*agentmodel.AgentGroup) (
+		*agentservice.AgentGroupService, *MockAgentRemoteConfigPersistencePort, *agentmodel.Agent,
+	) {
+		t.Helper()
+
+		mockPersistence := new(MockAgentGroupPersistencePort)
+		mockRemoteConfigPort := new(MockAgentRemoteConfigPersistencePort)
+
+		svc := agentservice.NewAgentGroupService(
+			mockPersistence, mockRemoteConfigPort, new(MockCertificatePersistencePortForGroup),
+			new(MockAgentUsecaseForGroup), alwaysLeaderElector{}, slog.Default())
+		svc.SetBaseRemoteConfigs(baseConfigs)
+
+		mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+			Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: groups, Continue: "", RemainingItemCount: 0}, nil)
+
+		testAgent := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+			IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+		}))
+
+		return svc, mockRemoteConfigPort, testAgent
+	}
+
+	t.Run("base configs are delivered alongside group configs and lose name conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		inlineName := "exporters"
+		overrideName := "standard-processors"
+		group := newGroup("production", false,
+			agentmodel.AgentGroupAgentRemoteConfig{
+				AgentRemoteConfigName: &inlineName,
+				AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+					Value:       []byte("exporters: {otlp: {}}"),
+					ContentType: "text/yaml",
+				},
+			},
+			agentmodel.AgentGroupAgentRemoteConfig{AgentRemoteConfigRef: &overrideName},
+		)
+
+		svc, mockRemoteConfigPort, testAgent := setup(t, group)
+		mockRemoteConfigPort.On("GetAgentRemoteConfig", ctx, "default", overrideName, (*model.GetOptions)(nil)).
+			Return(&agentmodel.AgentRemoteConfig{
+				Metadata: agentmodel.AgentRemoteConfigMetadata{Namespace: "default", Name: overrideName},
+				Spec: agentmodel.AgentRemoteConfigSpec{
+					Value:       []byte("processors: {memory_limiter: {}}"),
+					ContentType: "text/yaml",
+				},
+			}, nil)
+
+		require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(ctx, testAgent))
+
+		require.NotNil(t, testAgent.Spec.RemoteConfig)
+		configs := testAgent.Spec.RemoteConfig.ConfigMap.ConfigMap
+		assert.Len(t, configs, 3)
+		assert.Equal(t, baseConfigs["standard-extensions"], configs["standard-extensions"])
+		assert.Equal(t, []byte("exporters: {otlp: {}}"), configs["production/exporters"].Body)
+		assert.Equal(t, []byte("processors: {memory_limiter: {}}"), configs["standard-processors"].Body,
+			"the group's config must override the base config delivered under the same name")
+	})
+
+	t.Run("a group excluding the base configs keeps them off its agents", func(t *testing.T) {
+		t.Parallel()
+
+		svc, _, testAgent := setup(t, newGroup("all", false), newGroup("legacy", true))
+
+		require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(t.Context(), testAgent))
+
+		assert.Nil(t, testAgent.Spec.RemoteConfig)
+	})
+
+	t.Run("an agent no group governs gets no base configs", func(t *testing.T) {
+		t.Parallel()
+
+		svc, _, testAgent := setup(t)
+
+		require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(t.Context(), testAgent))
+
+		assert.Nil(t, testAgent.Spec.RemoteConfig)
+	})
+}
+
+func TestAgentGroupService_ApplyMatchingAgentGroupsToAgentKeepsDirectRemoteConfigs(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockPersistence := new(MockAgentGroupPersistencePort)
+	svc := agentservice.NewAgentGroupService(
+		mockPersistence, new(MockAgentRemoteConfigPersistencePort), new(MockCertificatePersistencePortForGroup),
+		new(MockAgentUsecaseForGroup), alwaysLeaderElector{}, slog.Default())
+
+	configName := "collector"
+	group := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "production"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{{
+				AgentRemoteConfigName: &configName,
+				AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{Value: []byte("v1"), ContentType: "text/yaml"},
+			}},
+		},
+	}
+
+	mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+		Return(&model.ListResponse[*agentmodel.AgentGroup]{
+			Items: []*agentmodel.AgentGroup{group}, Continue: "", RemainingItemCount: 0,
+		}, nil)
+
+	testAgent := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+	}))
+
+	require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(ctx, testAgent))
+
+	override := agentmodel.AgentConfigFile{Body: []byte("log_level: debug"), ContentType: "text/yaml"}
+	testAgent.SetDirectRemoteConfigs(map[string]agentmodel.AgentConfigFile{"debug": override})
+
+	// The group changes and is propagated again.
+	group.Spec.AgentRemoteConfigs[0].AgentRemoteConfigSpec.Value = []byte("v2")
+
+	require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(ctx, testAgent))
+
+	require.NotNil(t, testAgent.Spec.RemoteConfig)
+	configs := testAgent.Spec.RemoteConfig.ConfigMap.ConfigMap
+	assert.Len(t, configs, 2)
+	assert.Equal(t, []byte("v2"), configs["production/collector"].Body)
+	assert.Equal(t, override, configs[agentmodel.DirectRemoteConfigKey("debug")],
+		"a direct config must survive the group's re-propagation")
+	assert.Equal(t, map[string]agentmodel.AgentConfigFile{"debug": override}, testAgent.DirectRemoteConfigs())
+}
+
+func TestAgentGroupService_RollbackDeliversPreviousRemoteConfigs(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockPersistence := new(MockAgentGroupPersistencePort)
+	svc := agentservice.NewAgentGroupService(
+		mockPersistence, new(MockAgentRemoteConfigPersistencePort), new(MockCertificatePersistencePortForGroup),
+		new(MockAgentUsecaseForGroup), alwaysLeaderElector{}, slog.Default())
+
+	configName := "collector"
+	withConfig := func(body string) []agentmodel.AgentGroupAgentRemoteConfig {
+		return []agentmodel.AgentGroupAgentRemoteConfig{{
+			AgentRemoteConfigName: &configName,
+			AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{Value: []byte(body), ContentType: "text/yaml"},
+		}}
+	}
+
+	stored := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "g-1"},
+		Spec: agentmodel.AgentGroupSpec{
+			Selector: agentmodel.AgentSelector{
+				IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+			},
+			AgentRemoteConfigs: withConfig("v1"),
+		},
+	}
+	mockPersistence.On("ListAgentGroups", mock.Anything, (*model.ListOptions)(nil)).
+		Return(&model.ListResponse[*agentmodel.AgentGroup]{Items: []*agentmodel.AgentGroup{stored}}, nil)
+
+	testAgent := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes: map[string]string{"service.name": "my-service"},
+	}))
+	deliveredBody := func() string {
+		t.Helper()
+		require.NoError(t, svc.ApplyMatchingAgentGroupsToAgent(ctx, testAgent))
+		require.NotNil(t, testAgent.Spec.RemoteConfig)
+
+		return string(testAgent.Spec.RemoteConfig.ConfigMap.ConfigMap["g-1/collector"].Body)
+	}
+
+	assert.Equal(t, "v1", deliveredBody())
+
+	updated := *stored
+	updated.Spec.AgentRemoteConfigs = withConfig("v2")
+	updated.RememberPreviousRemoteConfigs(stored)
+	*stored = updated
+
+	assert.Equal(t, "v2", deliveredBody())
+
+	require.NoError(t, stored.RollbackRemoteConfigs())
+
+	assert.Equal(t, "v1", deliveredBody())
+}
+
+func TestAgentGroupService_SaveAgentGroupRejectsForbiddenConfigKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mockPersistence := new(MockAgentGroupPersistencePort)
+	mockAgentUsecase := new(MockAgentUsecaseForGroup)
+	mockRemoteConfigPort := new(MockAgentRemoteConfigPersistencePort)
+	mockCertPersistence := new(MockCertificatePersistencePortForGroup)
+
+	svc := agentservice.NewAgentGroupService(
+		mockPersistence, mockRemoteConfigPort, mockCertPersistence, mockAgentUsecase, alwaysLeaderElector{}, slog.Default())
+	svc.SetRemoteConfigValidators(agentservice.NewForbiddenKeysValidator([]string{"exporters.debug"}))
+
+	configName := "collector"
+	group := &agentmodel.AgentGroup{
+		Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "test-group"},
+		Spec: agentmodel.AgentGroupSpec{
+			AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{{
+				AgentRemoteConfigName: &configName,
+				AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+					Value:       []byte("exporters:\n  otlp: {}\n  debug/verbose:\n    verbosity: detailed\n"),
+					ContentType: "text/yaml",
+				},
+			}},
+		},
+	}
+
+	_, err := svc.SaveAgentGroup(ctx, "default", "test-group", group)
+
+	var fieldErr *model.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.ErrorIs(t, err, model.ErrInvalidArgument)
+	assert.Equal(t, "spec.agentConfig.agentRemoteConfigs[0].agentRemoteConfigSpec.value", fieldErr.Field)
+	assert.Equal(t, "exporters.debug/verbose", fieldErr.Value)
+	assert.Contains(t, fieldErr.Reason, agentservice.ForbiddenKeysValidatorName)
+	// A rejected group is never persisted.
+	mockPersistence.AssertNotCalled(t, "PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // alwaysLeaderElector is a test double that always reports leadership.
 type alwaysLeaderElector struct{}
 
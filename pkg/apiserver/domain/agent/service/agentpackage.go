@@ -81,9 +81,14 @@ func (s *AgentPackageService) CreateAgentPackage(
 	agentPackage *agentmodel.AgentPackage,
 	actor string,
 ) (*agentmodel.AgentPackage, error) {
+	err := agentPackage.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	// Reject creating over an existing package instead of silently upserting it,
 	// which would overwrite it and rewind its optimistic-concurrency version.
-	_, err := s.persistence.GetAgentPackage(ctx, agentPackage.Metadata.Namespace, agentPackage.Metadata.Name, nil)
+	_, err = s.persistence.GetAgentPackage(ctx, agentPackage.Metadata.Namespace, agentPackage.Metadata.Name, nil)
 	switch {
 	case err == nil:
 		return nil, fmt.Errorf("%w: agent package %q in namespace %q",
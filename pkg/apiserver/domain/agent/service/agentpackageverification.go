@@ -0,0 +1,89 @@
+package agentservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var _ agentport.AgentPackageVerificationUsecase = (*AgentPackageVerificationService)(nil)
+
+// AgentPackageVerificationService downloads agent package artifacts and records whether
+// they match the package's content hash. Timeouts, retries and short-circuiting a failing
+// origin are left to the download port.
+type AgentPackageVerificationService struct {
+	persistence agentport.AgentPackagePersistencePort
+	download    agentport.AgentPackageDownloadPort
+	clock       clock.Clock
+	logger      *slog.Logger
+}
+
+// NewAgentPackageVerificationService creates a new AgentPackageVerificationService.
+func NewAgentPackageVerificationService(
+	persistence agentport.AgentPackagePersistencePort,
+	download agentport.AgentPackageDownloadPort,
+	logger *slog.Logger,
+) *AgentPackageVerificationService {
+	return &AgentPackageVerificationService{
+		persistence: persistence,
+		download:    download,
+		clock:       clock.NewRealClock(),
+		logger:      logger,
+	}
+}
+
+// SetClock overrides the clock used for condition timestamps. Intended for tests.
+func (s *AgentPackageVerificationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// VerifyAgentPackage implements [agentport.AgentPackageVerificationUsecase].
+func (s *AgentPackageVerificationService) VerifyAgentPackage(
+	ctx context.Context,
+	namespace string,
+	name string,
+	actor string,
+) (*agentmodel.AgentPackage, error) {
+	agentPackage, err := s.persistence.GetAgentPackage(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent package: %w", err)
+	}
+
+	if agentPackage.Spec.DownloadURL == "" {
+		return nil, &model.FieldError{
+			Field:  "spec.downloadUrl",
+			Value:  "",
+			Reason: "must be set to verify the package",
+		}
+	}
+
+	contentHash, downloadErr := s.download.FetchAgentPackageContentHash(
+		ctx, agentPackage.Spec.DownloadURL, agentPackage.Spec.Headers)
+	if downloadErr != nil {
+		// A caller that gave up says nothing about the origin, so it is not recorded.
+		if errors.Is(downloadErr, context.Canceled) && ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to download agent package: %w", downloadErr)
+		}
+
+		s.logger.Warn("failed to download agent package for verification",
+			slog.String("namespace", namespace),
+			slog.String("name", name),
+			slog.String("error", downloadErr.Error()),
+		)
+	}
+
+	agentPackage.RecordContentVerification(s.clock.Now(), actor, contentHash, downloadErr)
+
+	saved, err := s.persistence.PutAgentPackage(ctx, agentPackage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save agent package: %w", err)
+	}
+
+	return saved, nil
+}
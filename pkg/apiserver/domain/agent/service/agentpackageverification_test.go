@@ -0,0 +1,116 @@
+package agentservice_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// fakePackageDownload returns a fixed hash or error and records the URL it was asked for.
+type fakePackageDownload struct {
+	hash []byte
+	err  error
+	urls []string
+}
+
+func (f *fakePackageDownload) FetchAgentPackageContentHash(
+	_ context.Context, downloadURL string, _ map[string]string,
+) ([]byte, error) {
+	f.urls = append(f.urls, downloadURL)
+
+	return f.hash, f.err
+}
+
+var _ agentport.AgentPackageDownloadPort = (*fakePackageDownload)(nil)
+
+func TestAgentPackageVerificationService_VerifyAgentPackage(t *testing.T) {
+	t.Parallel()
+
+	content := sha256.Sum256([]byte("collector binary"))
+	other := sha256.Sum256([]byte("something else"))
+
+	newPackage := func(downloadURL string) *agentmodel.AgentPackage {
+		//exhaustruct:ignore
+		return &agentmodel.AgentPackage{
+			Metadata: agentmodel.AgentPackageMetadata{Name: "otelcol", Namespace: "default"},
+			Spec: agentmodel.AgentPackageSpec{
+				DownloadURL: downloadURL,
+				ContentHash: content[:],
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		download   *fakePackageDownload
+		wantStatus model.ConditionStatus
+		wantInMsg  string
+	}{
+		{
+			name:       "matching content",
+			download:   &fakePackageDownload{hash: content[:]},
+			wantStatus: model.ConditionStatusTrue,
+			wantInMsg:  "content hash matches",
+		},
+		{
+			name:       "mismatching content",
+			download:   &fakePackageDownload{hash: other[:]},
+			wantStatus: model.ConditionStatusFalse,
+			wantInMsg:  "content hash mismatch",
+		},
+		{
+			name: "unavailable origin",
+			download: &fakePackageDownload{
+				err: fmt.Errorf("%w: https://downloads.example.com", agentmodel.ErrPackageOriginUnavailable),
+			},
+			wantStatus: model.ConditionStatusFalse,
+			wantInMsg:  agentmodel.ErrPackageOriginUnavailable.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			persistence := &apFakePersistence{stored: newPackage("https://downloads.example.com/otelcol")}
+			svc := agentservice.NewAgentPackageVerificationService(
+				persistence, tt.download, slog.New(slog.DiscardHandler))
+
+			verified, err := svc.VerifyAgentPackage(t.Context(), "default", "otelcol", "tester")
+			require.NoError(t, err, "a failed download is reported on the condition")
+
+			assert.Equal(t, []string{"https://downloads.example.com/otelcol"}, tt.download.urls)
+			assert.Equal(t, 1, persistence.putCalls)
+
+			condition := verified.GetCondition(model.ConditionTypeContentVerified)
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.wantStatus, condition.Status)
+			assert.Equal(t, "tester", condition.Reason)
+			assert.Contains(t, condition.Message, tt.wantInMsg)
+		})
+	}
+
+	t.Run("package without download URL is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		persistence := &apFakePersistence{stored: newPackage("")}
+		download := &fakePackageDownload{}
+		svc := agentservice.NewAgentPackageVerificationService(
+			persistence, download, slog.New(slog.DiscardHandler))
+
+		_, err := svc.VerifyAgentPackage(t.Context(), "default", "otelcol", "tester")
+		require.ErrorIs(t, err, model.ErrInvalidArgument)
+		assert.Empty(t, download.urls)
+		assert.Zero(t, persistence.putCalls)
+	})
+}
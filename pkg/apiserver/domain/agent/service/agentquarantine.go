@@ -0,0 +1,215 @@
+package agentservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+const (
+	agentQuarantineServiceName = "AgentQuarantineService"
+	// DefaultQuarantineEvaluationInterval is how often the evaluator scans agents for
+	// health-based quarantine changes.
+	DefaultQuarantineEvaluationInterval = time.Minute
+	// quarantineEvaluatorActor is recorded as the trigger of automatic quarantine changes.
+	quarantineEvaluatorActor = "system:quarantine-evaluator"
+)
+
+var _ agentport.AgentQuarantineUsecase = (*AgentQuarantineService)(nil)
+
+// AgentQuarantineService quarantines agents so agent group propagation stops pushing
+// new config to them. Besides the manual operations, a background evaluator
+// quarantines agents that stay unhealthy for longer than the configured threshold
+// and lifts those automatic quarantines once the agent reports healthy again.
+type AgentQuarantineService struct {
+	agentUsecase      agentport.AgentUsecase
+	agentGroupUsecase agentport.AgentGroupUsecase
+
+	// leaderElector gates the evaluator so only one node runs it.
+	leaderElector agentport.LeaderElector
+
+	// unhealthyThreshold is how long an agent must stay unhealthy before it is
+	// quarantined automatically. Zero disables the evaluator.
+	unhealthyThreshold time.Duration
+	evaluationInterval time.Duration
+
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewAgentQuarantineService creates a new AgentQuarantineService. A zero
+// unhealthyThreshold disables automatic quarantine; manual quarantine still works.
+func NewAgentQuarantineService(
+	agentUsecase agentport.AgentUsecase,
+	agentGroupUsecase agentport.AgentGroupUsecase,
+	leaderElector agentport.LeaderElector,
+	unhealthyThreshold time.Duration,
+	evaluationInterval time.Duration,
+	logger *slog.Logger,
+) *AgentQuarantineService {
+	if evaluationInterval <= 0 {
+		evaluationInterval = DefaultQuarantineEvaluationInterval
+	}
+
+	return &AgentQuarantineService{
+		agentUsecase:       agentUsecase,
+		agentGroupUsecase:  agentGroupUsecase,
+		leaderElector:      leaderElector,
+		unhealthyThreshold: unhealthyThreshold,
+		evaluationInterval: evaluationInterval,
+		clock:              clock.NewRealClock(),
+		logger:             logger,
+	}
+}
+
+// SetClock overrides the clock used to measure unhealthy duration. Intended for tests.
+func (s *AgentQuarantineService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Name implements scheduler.Scheduler.
+func (s *AgentQuarantineService) Name() string {
+	return agentQuarantineServiceName
+}
+
+// Run implements scheduler.Scheduler. It evaluates every agent once per
+// evaluationInterval while this node is the leader.
+func (s *AgentQuarantineService) Run(ctx context.Context) error {
+	if s.unhealthyThreshold <= 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	ticker := time.NewTicker(s.evaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.evaluateAllIfLeader(ctx)
+		}
+	}
+}
+
+// QuarantineAgent implements [agentport.AgentQuarantineUsecase].
+func (s *AgentQuarantineService) QuarantineAgent(
+	ctx context.Context,
+	agent *agentmodel.Agent,
+	triggeredBy, reason string,
+) error {
+	agent.Quarantine(s.clock.Now(), triggeredBy, reason, false)
+
+	err := s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("save quarantined agent %s: %w", agent.Metadata.InstanceUID, err)
+	}
+
+	return nil
+}
+
+// UnquarantineAgent implements [agentport.AgentQuarantineUsecase].
+func (s *AgentQuarantineService) UnquarantineAgent(
+	ctx context.Context,
+	agent *agentmodel.Agent,
+	triggeredBy string,
+) error {
+	agent.Unquarantine(s.clock.Now(), triggeredBy)
+
+	// ReconcileAgent owns the save, so the lifted quarantine and the config the agent
+	// missed are persisted together.
+	err := s.agentGroupUsecase.ReconcileAgent(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("reconcile unquarantined agent %s: %w", agent.Metadata.InstanceUID, err)
+	}
+
+	return nil
+}
+
+// evaluateAllIfLeader runs the evaluation pass only on the leader. Like the agent group
+// reconcile loop it fails open when leadership cannot be determined.
+func (s *AgentQuarantineService) evaluateAllIfLeader(ctx context.Context) {
+	isLeader, err := s.leaderElector.IsLeader(ctx)
+	if err != nil {
+		s.logger.Warn("quarantine evaluator: leader election failed, evaluating anyway",
+			slog.String("error", err.Error()))
+
+		isLeader = true
+	}
+
+	if !isLeader {
+		return
+	}
+
+	s.evaluateAll(ctx)
+}
+
+// evaluateAll walks every agent and applies or lifts automatic quarantines.
+func (s *AgentQuarantineService) evaluateAll(ctx context.Context) {
+	var continueToken string
+
+	// An empty selector (no attribute constraints) matches every agent.
+	//exhaustruct:ignore
+	allAgents := agentmodel.AgentSelector{}
+
+	for {
+		agentsResp, err := s.agentUsecase.ListAgentsBySelector(ctx, allAgents, &model.ListOptions{
+			Limit:          PropagationChunkSize,
+			Continue:       continueToken,
+			IncludeDeleted: false,
+		})
+		if err != nil {
+			s.logger.Error("quarantine evaluator: failed to list agents",
+				slog.String("error", err.Error()))
+
+			return
+		}
+
+		for _, agent := range agentsResp.Items {
+			err := s.evaluateAgent(ctx, agent)
+			if err != nil {
+				s.logger.Warn("quarantine evaluator: failed to evaluate agent",
+					slog.String("agent", agent.Metadata.InstanceUID.String()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+
+		if agentsResp.Continue == "" {
+			return
+		}
+
+		continueToken = agentsResp.Continue
+	}
+}
+
+func (s *AgentQuarantineService) evaluateAgent(ctx context.Context, agent *agentmodel.Agent) error {
+	switch {
+	case agent.ShouldAutoQuarantine(s.clock.Now(), s.unhealthyThreshold):
+		agent.Quarantine(s.clock.Now(), quarantineEvaluatorActor,
+			agentmodel.AutoQuarantineReason(s.unhealthyThreshold), true)
+
+		s.logger.Info("quarantined long-unhealthy agent",
+			slog.String("agent", agent.Metadata.InstanceUID.String()))
+
+		err := s.agentUsecase.SaveAgent(ctx, agent)
+		if err != nil {
+			return fmt.Errorf("save quarantined agent: %w", err)
+		}
+	case agent.ShouldLiftAutoQuarantine():
+		s.logger.Info("lifting quarantine of recovered agent",
+			slog.String("agent", agent.Metadata.InstanceUID.String()))
+
+		return s.UnquarantineAgent(ctx, agent, quarantineEvaluatorActor)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package agentservice
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+// fixedNowClock is a real clock whose Now is pinned, so unhealthy durations are deterministic.
+type fixedNowClock struct {
+	clock.Clock
+
+	now time.Time
+}
+
+func (c fixedNowClock) Now() time.Time { return c.now }
+
+func newUnhealthyAgent(selectorAttrs map[string]string, since time.Time) *agentmodel.Agent {
+	a := agentmodel.NewAgent(uuid.New(), agentmodel.WithDescription(&agent.Description{
+		IdentifyingAttributes: selectorAttrs,
+	}))
+	a.SetConditionAt(agentmodel.AgentConditionTypeHealthy, agentmodel.AgentConditionStatusFalse,
+		since, "agent", "exporter failing")
+
+	return a
+}
+
+func TestAgentQuarantineService_evaluateAll(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	attrs := map[string]string{"service.name": "my-service"}
+
+	t.Run("quarantines only agents unhealthy past the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUC := new(mockAgentUsecase)
+		longUnhealthy := newUnhealthyAgent(attrs, now.Add(-2*time.Hour))
+		recentlyUnhealthy := newUnhealthyAgent(attrs, now.Add(-10*time.Minute))
+
+		mockAgentUC.On("ListAgentsBySelector", ctx, mock.Anything, mock.Anything).
+			Return(&model.ListResponse[*agentmodel.Agent]{
+				Items: []*agentmodel.Agent{longUnhealthy, recentlyUnhealthy},
+			}, nil)
+		mockAgentUC.On("SaveAgent", ctx, longUnhealthy).Return(nil).Once()
+
+		svc := NewAgentQuarantineService(mockAgentUC, nil, alwaysLeaderElector{}, time.Hour, 0, slog.Default())
+		svc.SetClock(fixedNowClock{Clock: clock.NewRealClock(), now: now})
+
+		svc.evaluateAll(ctx)
+
+		require.True(t, longUnhealthy.IsQuarantined())
+		assert.True(t, longUnhealthy.Spec.Quarantine.Automatic)
+		assert.True(t, longUnhealthy.IsConditionTrue(agentmodel.AgentConditionTypeQuarantined))
+		assert.False(t, recentlyUnhealthy.IsQuarantined())
+		mockAgentUC.AssertExpectations(t)
+	})
+
+	t.Run("long-unhealthy agent is skipped during propagation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockPersistence := new(mockAgentGroupPersistence)
+		mockAgentUC := new(mockAgentUsecase)
+		mockRemoteConfigPort := new(mockRemoteConfigPersistence)
+		mockCertPort := new(mockCertPersistence)
+
+		testAgent := newUnhealthyAgent(attrs, now.Add(-2*time.Hour))
+
+		inlineName := "new-config"
+		agentGroup := &agentmodel.AgentGroup{
+			Metadata: agentmodel.AgentGroupMetadata{Namespace: "default", Name: "production"},
+			Spec: agentmodel.AgentGroupSpec{
+				Selector: agentmodel.AgentSelector{IdentifyingAttributes: attrs},
+				AgentRemoteConfigs: []agentmodel.AgentGroupAgentRemoteConfig{
+					{
+						AgentRemoteConfigName: &inlineName,
+						AgentRemoteConfigSpec: &agentmodel.AgentRemoteConfigSpec{
+							Value:       []byte("new config content"),
+							ContentType: "text/plain",
+						},
+					},
+				},
+			},
+		}
+
+		agentsResponse := &model.ListResponse[*agentmodel.Agent]{Items: []*agentmodel.Agent{testAgent}}
+
+		// The evaluator quarantines the agent first.
+		mockAgentUC.On("ListAgentsBySelector", ctx, agentmodel.AgentSelector{}, mock.Anything).
+			Return(agentsResponse, nil)
+		mockAgentUC.On("SaveAgent", ctx, testAgent).Return(nil).Once()
+
+		quarantineSvc := NewAgentQuarantineService(mockAgentUC, nil, alwaysLeaderElector{}, time.Hour, 0, slog.Default())
+		quarantineSvc.SetClock(fixedNowClock{Clock: clock.NewRealClock(), now: now})
+		quarantineSvc.evaluateAll(ctx)
+		require.True(t, testAgent.IsQuarantined())
+
+		// Propagation then lists the agent but must neither resolve its groups nor save it.
+		mockAgentUC.On("ListAgentsBySelector", ctx, agentGroup.Spec.Selector, mock.Anything).
+			Return(agentsResponse, nil)
+		mockPersistence.On("GetAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+		mockPersistence.On("PutAgentGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(agentGroup, nil)
+
+		groupSvc := NewAgentGroupService(
+			mockPersistence, mockRemoteConfigPort, mockCertPort,
+			mockAgentUC, alwaysLeaderElector{}, slog.Default())
+
+		err := groupSvc.updateAgentsByAgentGroup(ctx, agentGroup)
+
+		require.NoError(t, err)
+		assert.False(t, testAgent.HasAssignedRemoteConfig())
+		mockPersistence.AssertNotCalled(t, "ListAgentGroups", mock.Anything, mock.Anything)
+		mockAgentUC.AssertNumberOfCalls(t, "SaveAgent", 1)
+	})
+}
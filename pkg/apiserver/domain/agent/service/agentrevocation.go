@@ -0,0 +1,99 @@
+package agentservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+var _ agentport.AgentRevocationUsecase = (*AgentRevocationService)(nil)
+
+// AgentRevocationService maintains the blacklist of revoked agent instance UIDs.
+//
+// Revoking closes the agent's connection only when this server holds it; an agent
+// connected to another server is refused the next time it sends a message, since every
+// server checks the persisted blacklist on each message.
+type AgentRevocationService struct {
+	persistencePort   agentport.AgentRevocationPersistencePort
+	connectionUsecase agentport.ConnectionUsecase
+
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewAgentRevocationService creates a new AgentRevocationService.
+func NewAgentRevocationService(
+	persistencePort agentport.AgentRevocationPersistencePort,
+	connectionUsecase agentport.ConnectionUsecase,
+	logger *slog.Logger,
+) *AgentRevocationService {
+	return &AgentRevocationService{
+		persistencePort:   persistencePort,
+		connectionUsecase: connectionUsecase,
+		clock:             clock.NewRealClock(),
+		logger:            logger,
+	}
+}
+
+// SetClock overrides the clock used to stamp revocations. Intended for tests.
+func (s *AgentRevocationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// RevokeAgent implements [agentport.AgentRevocationUsecase].
+func (s *AgentRevocationService) RevokeAgent(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	revokedBy, reason string,
+) (*agentmodel.AgentRevocation, error) {
+	revocation := agentmodel.NewAgentRevocation(instanceUID, revokedBy, reason, s.clock.Now())
+
+	err := s.persistencePort.PutAgentRevocation(ctx, revocation)
+	if err != nil {
+		return nil, fmt.Errorf("save revocation of agent %s: %w", instanceUID, err)
+	}
+
+	// The revocation is already in force, so a failed disconnect is only logged: the
+	// agent is refused on its next message regardless.
+	err = s.connectionUsecase.DisconnectAgent(ctx, instanceUID)
+	if err != nil && !errors.Is(err, agentport.ErrConnectionNotFound) {
+		s.logger.Warn("failed to disconnect revoked agent",
+			slog.String("instanceUID", instanceUID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return revocation, nil
+}
+
+// UnrevokeAgent implements [agentport.AgentRevocationUsecase].
+func (s *AgentRevocationService) UnrevokeAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	err := s.persistencePort.DeleteAgentRevocation(ctx, instanceUID)
+	if err != nil {
+		return fmt.Errorf("delete revocation of agent %s: %w", instanceUID, err)
+	}
+
+	return nil
+}
+
+// IsAgentRevoked implements [agentport.AgentRevocationUsecase].
+func (s *AgentRevocationService) IsAgentRevoked(ctx context.Context, instanceUID uuid.UUID) (bool, error) {
+	_, err := s.persistencePort.GetAgentRevocation(ctx, instanceUID)
+	if err != nil {
+		if errors.Is(err, model.ErrResourceNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("get revocation of agent %s: %w", instanceUID, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package agentservice_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+// fakeRevocationPersistence is a map-backed agentport.AgentRevocationPersistencePort.
+type fakeRevocationPersistence struct {
+	revocations map[uuid.UUID]*agentmodel.AgentRevocation
+}
+
+func newFakeRevocationPersistence() *fakeRevocationPersistence {
+	return &fakeRevocationPersistence{revocations: map[uuid.UUID]*agentmodel.AgentRevocation{}}
+}
+
+func (f *fakeRevocationPersistence) GetAgentRevocation(
+	_ context.Context, instanceUID uuid.UUID,
+) (*agentmodel.AgentRevocation, error) {
+	revocation, ok := f.revocations[instanceUID]
+	if !ok {
+		return nil, model.ErrResourceNotExist
+	}
+
+	return revocation, nil
+}
+
+func (f *fakeRevocationPersistence) PutAgentRevocation(
+	_ context.Context, revocation *agentmodel.AgentRevocation,
+) error {
+	f.revocations[revocation.InstanceUID] = revocation
+
+	return nil
+}
+
+func (f *fakeRevocationPersistence) DeleteAgentRevocation(_ context.Context, instanceUID uuid.UUID) error {
+	if _, ok := f.revocations[instanceUID]; !ok {
+		return model.ErrResourceNotExist
+	}
+
+	delete(f.revocations, instanceUID)
+
+	return nil
+}
+
+func TestAgentRevocationService(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("revoke persists the revocation and closes the live connection", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		connUC := new(MockConnectionUsecase)
+		connUC.On("DisconnectAgent", ctx, instanceUID).Return(nil).Once()
+
+		svc := agentservice.NewAgentRevocationService(newFakeRevocationPersistence(), connUC, slog.Default())
+		svc.SetClock(newTestFakeClock(now))
+
+		revocation, err := svc.RevokeAgent(ctx, instanceUID, "admin@example.com", "leaked credentials")
+		require.NoError(t, err)
+		assert.Equal(t, instanceUID, revocation.InstanceUID)
+		assert.Equal(t, "admin@example.com", revocation.RevokedBy)
+		assert.Equal(t, "leaked credentials", revocation.Reason)
+		assert.Equal(t, now, revocation.RevokedAt)
+
+		revoked, err := svc.IsAgentRevoked(ctx, instanceUID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		connUC.AssertExpectations(t)
+	})
+
+	t.Run("revoke succeeds when the agent is not connected here", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		connUC := new(MockConnectionUsecase)
+		connUC.On("DisconnectAgent", ctx, instanceUID).Return(agentport.ErrConnectionNotFound)
+
+		svc := agentservice.NewAgentRevocationService(newFakeRevocationPersistence(), connUC, slog.Default())
+
+		_, err := svc.RevokeAgent(ctx, instanceUID, "admin@example.com", "")
+		require.NoError(t, err)
+	})
+
+	t.Run("unrevoke lets the agent back in", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		connUC := new(MockConnectionUsecase)
+		connUC.On("DisconnectAgent", ctx, instanceUID).Return(agentport.ErrConnectionNotFound)
+
+		svc := agentservice.NewAgentRevocationService(newFakeRevocationPersistence(), connUC, slog.Default())
+
+		_, err := svc.RevokeAgent(ctx, instanceUID, "admin@example.com", "")
+		require.NoError(t, err)
+		require.NoError(t, svc.UnrevokeAgent(ctx, instanceUID))
+
+		revoked, err := svc.IsAgentRevoked(ctx, instanceUID)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		require.ErrorIs(t, svc.UnrevokeAgent(ctx, instanceUID), model.ErrResourceNotExist)
+	})
+
+	t.Run("unknown instance UID is not revoked", func(t *testing.T) {
+		t.Parallel()
+
+		svc := agentservice.NewAgentRevocationService(newFakeRevocationPersistence(), nil, slog.Default())
+
+		revoked, err := svc.IsAgentRevoked(t.Context(), uuid.New())
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
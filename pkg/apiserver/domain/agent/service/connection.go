@@ -286,6 +286,29 @@ func (s *Service) SendServerToAgent(
 	return nil
 }
 
+// DisconnectAgent closes the agent's connection held by this server. The connection
+// record itself is removed by the OnConnectionClose callback that follows.
+func (s *Service) DisconnectAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	connection, err := s.GetConnectionByInstanceUID(ctx, instanceUID)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for agent %s: %w", instanceUID, err)
+	}
+
+	conn, ok := connection.ID.(types.Connection)
+	if !ok {
+		return &ConnectionNotFoundError{InstanceUID: instanceUID}
+	}
+
+	err = conn.Disconnect()
+	if err != nil {
+		return fmt.Errorf("failed to disconnect agent %s: %w", instanceUID, err)
+	}
+
+	s.logger.Info("disconnected agent", slog.String("instanceUID", instanceUID.String()))
+
+	return nil
+}
+
 // detectConnectionType detects whether the connection is WebSocket or HTTP.
 // According to OpAMP spec:
 // - WebSocket: Bidirectional, persistent connection. OnConnected is called first.
@@ -0,0 +1,167 @@
+package agentservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+const (
+	effectiveConfigStalenessServiceName = "EffectiveConfigStalenessService"
+	// DefaultEffectiveConfigStalenessEvaluationInterval is how often the evaluator scans
+	// agents for stale effective configs.
+	DefaultEffectiveConfigStalenessEvaluationInterval = time.Minute
+	// effectiveConfigStalenessActor is recorded as the trigger of the StaleEffectiveConfig condition.
+	effectiveConfigStalenessActor = "system:effective-config-staleness"
+)
+
+// EffectiveConfigStalenessService asks connected agents that have not reported their
+// effective config within the configured window to report it, raises the
+// StaleEffectiveConfig condition on those that do not answer within another window, and
+// clears it on agents that are fresh again or no longer connected.
+type EffectiveConfigStalenessService struct {
+	agentUsecase agentport.AgentUsecase
+
+	// leaderElector gates the evaluator so only one node runs it.
+	leaderElector agentport.LeaderElector
+
+	// window is how long a connected agent may go without reporting its effective config.
+	// Zero disables the evaluator.
+	window             time.Duration
+	evaluationInterval time.Duration
+
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewEffectiveConfigStalenessService creates a new EffectiveConfigStalenessService. A zero
+// window disables the evaluator.
+func NewEffectiveConfigStalenessService(
+	agentUsecase agentport.AgentUsecase,
+	leaderElector agentport.LeaderElector,
+	window time.Duration,
+	evaluationInterval time.Duration,
+	logger *slog.Logger,
+) *EffectiveConfigStalenessService {
+	if evaluationInterval <= 0 {
+		evaluationInterval = DefaultEffectiveConfigStalenessEvaluationInterval
+	}
+
+	return &EffectiveConfigStalenessService{
+		agentUsecase:       agentUsecase,
+		leaderElector:      leaderElector,
+		window:             window,
+		evaluationInterval: evaluationInterval,
+		clock:              clock.NewRealClock(),
+		logger:             logger,
+	}
+}
+
+// SetClock overrides the clock used to measure staleness. Intended for tests.
+func (s *EffectiveConfigStalenessService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Name implements scheduler.Scheduler.
+func (s *EffectiveConfigStalenessService) Name() string {
+	return effectiveConfigStalenessServiceName
+}
+
+// Run implements scheduler.Scheduler. It evaluates every agent once per
+// evaluationInterval while this node is the leader.
+func (s *EffectiveConfigStalenessService) Run(ctx context.Context) error {
+	if s.window <= 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	ticker := time.NewTicker(s.evaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.evaluateAllIfLeader(ctx)
+		}
+	}
+}
+
+// evaluateAllIfLeader runs the evaluation pass only on the leader. Like the agent group
+// reconcile loop it fails open when leadership cannot be determined.
+func (s *EffectiveConfigStalenessService) evaluateAllIfLeader(ctx context.Context) {
+	isLeader, err := s.leaderElector.IsLeader(ctx)
+	if err != nil {
+		s.logger.Warn("effective config staleness evaluator: leader election failed, evaluating anyway",
+			slog.String("error", err.Error()))
+
+		isLeader = true
+	}
+
+	if !isLeader {
+		return
+	}
+
+	s.evaluateAll(ctx)
+}
+
+// evaluateAll walks every agent and records whether its effective config is stale.
+// Only agents whose condition changed are saved.
+func (s *EffectiveConfigStalenessService) evaluateAll(ctx context.Context) {
+	var continueToken string
+
+	// An empty selector (no attribute constraints) matches every agent.
+	//exhaustruct:ignore
+	allAgents := agentmodel.AgentSelector{}
+
+	for {
+		agentsResp, err := s.agentUsecase.ListAgentsBySelector(ctx, allAgents, &model.ListOptions{
+			Limit:          PropagationChunkSize,
+			Continue:       continueToken,
+			IncludeDeleted: false,
+		})
+		if err != nil {
+			s.logger.Error("effective config staleness evaluator: failed to list agents",
+				slog.String("error", err.Error()))
+
+			return
+		}
+
+		for _, agent := range agentsResp.Items {
+			err := s.evaluateAgent(ctx, agent)
+			if err != nil {
+				s.logger.Warn("effective config staleness evaluator: failed to evaluate agent",
+					slog.String("agent", agent.Metadata.InstanceUID.String()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+
+		if agentsResp.Continue == "" {
+			return
+		}
+
+		continueToken = agentsResp.Continue
+	}
+}
+
+func (s *EffectiveConfigStalenessService) evaluateAgent(ctx context.Context, agent *agentmodel.Agent) error {
+	if !agent.RecordEffectiveConfigStaleness(s.clock.Now(), s.window, effectiveConfigStalenessActor) {
+		return nil
+	}
+
+	err := s.agentUsecase.SaveAgent(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("save agent: %w", err)
+	}
+
+	return nil
+}
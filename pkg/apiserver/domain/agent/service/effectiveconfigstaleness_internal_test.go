@@ -0,0 +1,148 @@
+package agentservice
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
+)
+
+func newConnectedAgentReportedAt(reportedAt time.Time) *agentmodel.Agent {
+	a := agentmodel.NewAgent(uuid.New())
+	a.Metadata.Capabilities = agent.Capabilities(agent.AgentCapabilityReportsEffectiveConfig)
+	a.Status.Connected = true
+	_ = a.ReportEffectiveConfig(&agentmodel.AgentEffectiveConfig{}, reportedAt)
+
+	return a
+}
+
+func TestEffectiveConfigStalenessService_evaluateAll(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	evaluateAt := func(t *testing.T, agents []*agentmodel.Agent, at time.Time) *mockAgentUsecase {
+		t.Helper()
+
+		mockAgentUC := new(mockAgentUsecase)
+		mockAgentUC.On("ListAgentsBySelector", t.Context(), mock.Anything, mock.Anything).
+			Return(&model.ListResponse[*agentmodel.Agent]{Items: agents}, nil)
+		mockAgentUC.On("SaveAgent", t.Context(), mock.Anything).Return(nil).Maybe()
+
+		svc := NewEffectiveConfigStalenessService(mockAgentUC, alwaysLeaderElector{}, time.Hour, 0, slog.Default())
+		svc.SetClock(fixedNowClock{Clock: clock.NewRealClock(), now: at})
+
+		svc.evaluateAll(t.Context())
+
+		return mockAgentUC
+	}
+
+	t.Run("asks stale connected agents to report before flagging them", func(t *testing.T) {
+		t.Parallel()
+
+		fresh := newConnectedAgentReportedAt(now.Add(-10 * time.Minute))
+		stale := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		disconnected := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		disconnected.Status.Connected = false
+
+		mockAgentUC := evaluateAt(t, []*agentmodel.Agent{fresh, stale, disconnected}, now)
+
+		condition := stale.GetCondition(agentmodel.AgentConditionTypeStaleEffectiveConfig)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, agentmodel.AgentConditionStatusUnknown, condition.Status)
+		}
+
+		assert.True(t, stale.HasPendingReport(agentmodel.AgentReportKindEffectiveConfig),
+			"the agent is sent ReportFullState until it reports its effective config")
+		assert.Nil(t, fresh.GetCondition(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+		assert.Nil(t, disconnected.GetCondition(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+		mockAgentUC.AssertNumberOfCalls(t, "SaveAgent", 1)
+		mockAgentUC.AssertCalled(t, "SaveAgent", t.Context(), stale)
+	})
+
+	t.Run("a stable agent answering the request is not flagged", func(t *testing.T) {
+		t.Parallel()
+
+		stable := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		evaluateAt(t, []*agentmodel.Agent{stable}, now)
+
+		// The agent answers ReportFullState with its unchanged effective config.
+		_ = stable.ReportEffectiveConfig(&agentmodel.AgentEffectiveConfig{}, now.Add(time.Minute))
+
+		evaluateAt(t, []*agentmodel.Agent{stable}, now.Add(time.Hour))
+
+		condition := stable.GetCondition(agentmodel.AgentConditionTypeStaleEffectiveConfig)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, agentmodel.AgentConditionStatusFalse, condition.Status)
+		}
+
+		assert.False(t, stable.HasPendingReport(agentmodel.AgentReportKindEffectiveConfig))
+
+		// A window after its answer, the agent is asked again rather than flagged.
+		evaluateAt(t, []*agentmodel.Agent{stable}, now.Add(time.Hour+2*time.Minute))
+
+		assert.False(t, stable.IsConditionTrue(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+		assert.True(t, stable.HasPendingReport(agentmodel.AgentReportKindEffectiveConfig))
+	})
+
+	t.Run("flags agents that do not answer the request within the window", func(t *testing.T) {
+		t.Parallel()
+
+		silent := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		evaluateAt(t, []*agentmodel.Agent{silent}, now)
+
+		mockAgentUC := evaluateAt(t, []*agentmodel.Agent{silent}, now.Add(30*time.Minute))
+		mockAgentUC.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+		assert.False(t, silent.IsConditionTrue(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+
+		evaluateAt(t, []*agentmodel.Agent{silent}, now.Add(time.Hour+time.Minute))
+		assert.True(t, silent.IsConditionTrue(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+	})
+
+	t.Run("flags agents that can no longer be asked right away", func(t *testing.T) {
+		t.Parallel()
+
+		unaskable := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		unaskable.Metadata.Capabilities = 0
+
+		evaluateAt(t, []*agentmodel.Agent{unaskable}, now)
+
+		assert.True(t, unaskable.IsConditionTrue(agentmodel.AgentConditionTypeStaleEffectiveConfig))
+	})
+
+	t.Run("clears the condition once the agent reports again", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockAgentUC := new(mockAgentUsecase)
+		agent := newConnectedAgentReportedAt(now.Add(-2 * time.Hour))
+		agent.RecordEffectiveConfigStaleness(now, time.Hour, effectiveConfigStalenessActor)
+
+		_ = agent.ReportEffectiveConfig(&agentmodel.AgentEffectiveConfig{}, now)
+
+		mockAgentUC.On("ListAgentsBySelector", ctx, mock.Anything, mock.Anything).
+			Return(&model.ListResponse[*agentmodel.Agent]{Items: []*agentmodel.Agent{agent}}, nil)
+
+		svc := NewEffectiveConfigStalenessService(mockAgentUC, alwaysLeaderElector{}, time.Hour, 0, slog.Default())
+		svc.SetClock(fixedNowClock{Clock: clock.NewRealClock(), now: now.Add(time.Minute)})
+
+		svc.evaluateAll(ctx)
+
+		condition := agent.GetCondition(agentmodel.AgentConditionTypeStaleEffectiveConfig)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, agentmodel.AgentConditionStatusFalse, condition.Status)
+		}
+
+		assert.Equal(t, now, agent.Status.EffectiveConfigReportedAt)
+		// The report already cleared the condition, so the pass has nothing to save.
+		mockAgentUC.AssertNotCalled(t, "SaveAgent", mock.Anything, mock.Anything)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -125,6 +126,12 @@ func (f *nsFakeAgentGroupUsecase) ApplyMatchingAgentGroupsToAgent(
 	return nil
 }
 
+func (f *nsFakeAgentGroupUsecase) GetRemoteConfigSources(
+	context.Context, *agentmodel.Agent,
+) (map[string]*agentmodel.AgentGroup, error) {
+	return nil, nil
+}
+
 func (f *nsFakeAgentGroupUsecase) ReconcileAgent(
 	context.Context, *agentmodel.Agent,
 ) error {
@@ -137,6 +144,18 @@ func (f *nsFakeAgentGroupUsecase) ReconcileAgentGroup(
 	return nil
 }
 
+func (f *nsFakeAgentGroupUsecase) PropagateAgentGroup(
+	context.Context, string, string,
+) (*agentmodel.AgentGroupPropagation, error) {
+	return nil, errNotImplemented
+}
+
+func (f *nsFakeAgentGroupUsecase) ApplyAgentGroupToAgents(
+	context.Context, string, string, []uuid.UUID,
+) (*agentmodel.AgentGroupPropagation, error) {
+	return nil, errNotImplemented
+}
+
 type nsFakeCertificateUsecase struct{}
 
 func (f *nsFakeCertificateUsecase) GetCertificate(
@@ -0,0 +1,111 @@
+package agentservice
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+// ForbiddenKeysValidatorName is the name the forbidden-keys validator reports violations under.
+const ForbiddenKeysValidatorName = "forbidden-keys"
+
+var _ agentport.RemoteConfigValidator = (*ForbiddenKeysValidator)(nil)
+
+// ForbiddenKeysValidator rejects a remote config that sets any of a list of keys, e.g. to
+// forbid an exporter. A key is a dotted path into the YAML or JSON config such as
+// "exporters.debug". A segment also matches collector component IDs of its type, so
+// "exporters.debug" rejects "exporters: {debug/verbose: ...}" too.
+type ForbiddenKeysValidator struct {
+	keys [][]string
+}
+
+// NewForbiddenKeysValidator creates a validator rejecting the given dotted keys.
+// Blank keys are ignored.
+func NewForbiddenKeysValidator(keys []string) *ForbiddenKeysValidator {
+	paths := make([][]string, 0, len(keys))
+
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		paths = append(paths, strings.Split(key, "."))
+	}
+
+	return &ForbiddenKeysValidator{keys: paths}
+}
+
+// Name implements agentport.RemoteConfigValidator.
+func (v *ForbiddenKeysValidator) Name() string {
+	return ForbiddenKeysValidatorName
+}
+
+// ValidateRemoteConfig implements agentport.RemoteConfigValidator.
+// A config that is not a YAML or JSON mapping cannot be checked and is rejected.
+func (v *ForbiddenKeysValidator) ValidateRemoteConfig(
+	_ context.Context,
+	_ string,
+	file agentmodel.AgentConfigFile,
+) error {
+	if len(v.keys) == 0 || len(file.Body) == 0 {
+		return nil
+	}
+
+	var root map[string]any
+
+	err := yaml.Unmarshal(file.Body, &root)
+	if err != nil {
+		return &agentmodel.RemoteConfigViolation{
+			Validator: ForbiddenKeysValidatorName,
+			Key:       "",
+			Reason:    "not a YAML or JSON mapping, so its keys cannot be checked",
+		}
+	}
+
+	for _, key := range v.keys {
+		found, ok := findKey(root, key, nil)
+		if ok {
+			return &agentmodel.RemoteConfigViolation{
+				Validator: ForbiddenKeysValidatorName,
+				Key:       strings.Join(found, "."),
+				Reason:    "forbidden by policy",
+			}
+		}
+	}
+
+	return nil
+}
+
+// findKey returns the path of the first key under node that matches the dotted key path,
+// visiting sibling keys in sorted order so the reported key is deterministic.
+func findKey(node map[string]any, path []string, prefix []string) ([]string, bool) {
+	for _, name := range slices.Sorted(maps.Keys(node)) {
+		if name != path[0] && !strings.HasPrefix(name, path[0]+"/") {
+			continue
+		}
+
+		current := append(slices.Clone(prefix), name)
+		if len(path) == 1 {
+			return current, true
+		}
+
+		child, ok := node[name].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		found, ok := findKey(child, path[1:], current)
+		if ok {
+			return found, true
+		}
+	}
+
+	return nil, false
+}
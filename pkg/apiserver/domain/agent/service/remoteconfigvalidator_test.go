@@ -0,0 +1,57 @@
+package agentservice_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+)
+
+func TestForbiddenKeysValidator(t *testing.T) {
+	t.Parallel()
+
+	validator := agentservice.NewForbiddenKeysValidator([]string{"exporters.debug", " ", "extensions.pprof"})
+
+	tests := []struct {
+		name    string
+		body    string
+		wantKey string
+		wantErr bool
+	}{
+		{name: "allowed config", body: "exporters:\n  otlp: {}\n"},
+		{name: "empty config", body: ""},
+		{name: "forbidden key", body: "exporters:\n  debug: {}\n", wantKey: "exporters.debug", wantErr: true},
+		{
+			name: "forbidden component type", body: "exporters:\n  debug/b: {}\n  debug/a: {}\n",
+			wantKey: "exporters.debug/a", wantErr: true,
+		},
+		{name: "json config", body: `{"extensions":{"pprof":{}}}`, wantKey: "extensions.pprof", wantErr: true},
+		{name: "same name elsewhere", body: "receivers:\n  debug: {}\n"},
+		{name: "prefix of another name", body: "exporters:\n  debugging: {}\n"},
+		{name: "not a mapping", body: "- exporters", wantKey: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validator.ValidateRemoteConfig(t.Context(), "config", agentmodel.AgentConfigFile{
+				Body:        []byte(test.body),
+				ContentType: "text/yaml",
+			})
+			if !test.wantErr {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var violation *agentmodel.RemoteConfigViolation
+			require.ErrorAs(t, err, &violation)
+			require.ErrorIs(t, err, agentmodel.ErrRemoteConfigRejected)
+			assert.Equal(t, test.wantKey, violation.Key)
+		})
+	}
+}
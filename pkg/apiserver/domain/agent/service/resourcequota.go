@@ -0,0 +1,117 @@
+package agentservice
+
+import (
+	"context"
+	"fmt"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+var _ agentport.ResourceQuotaUsecase = (*ResourceQuotaService)(nil)
+
+// ResourceQuotaService counts resources against the configured quotas.
+//
+// The count and the create that follows it are not atomic, so concurrent creates can
+// overshoot a quota by the number of requests racing for its last slot. Quotas guard
+// against runaway clients, not against that.
+type ResourceQuotaService struct {
+	agentGroupPersistencePort  agentport.AgentGroupPersistencePort
+	certificatePersistencePort agentport.CertificatePersistencePort
+
+	quotas agentmodel.ResourceQuotas
+}
+
+// NewResourceQuotaService creates a new ResourceQuotaService with every resource unlimited.
+func NewResourceQuotaService(
+	agentGroupPersistencePort agentport.AgentGroupPersistencePort,
+	certificatePersistencePort agentport.CertificatePersistencePort,
+) *ResourceQuotaService {
+	return &ResourceQuotaService{
+		agentGroupPersistencePort:  agentGroupPersistencePort,
+		certificatePersistencePort: certificatePersistencePort,
+		quotas:                     agentmodel.ResourceQuotas{},
+	}
+}
+
+// SetResourceQuotas replaces the quotas enforced on create.
+func (s *ResourceQuotaService) SetResourceQuotas(quotas agentmodel.ResourceQuotas) {
+	s.quotas = quotas
+}
+
+// CheckResourceQuota implements [agentport.ResourceQuotaUsecase].
+func (s *ResourceQuotaService) CheckResourceQuota(ctx context.Context, resource agentmodel.QuotaResource) error {
+	if s.quotas[resource] <= 0 {
+		return nil
+	}
+
+	usage, err := s.usage(ctx, resource)
+	if err != nil {
+		return err
+	}
+
+	if !usage.AllowsCreate() {
+		return fmt.Errorf("%w: %s quota of %d is used up", model.ErrQuotaExceeded, resource, usage.Limit)
+	}
+
+	return nil
+}
+
+// ListResourceQuotaUsage implements [agentport.ResourceQuotaUsecase].
+func (s *ResourceQuotaService) ListResourceQuotaUsage(ctx context.Context) ([]agentmodel.ResourceQuotaUsage, error) {
+	resources := agentmodel.QuotaResources()
+	usages := make([]agentmodel.ResourceQuotaUsage, 0, len(resources))
+
+	for _, resource := range resources {
+		usage, err := s.usage(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+func (s *ResourceQuotaService) usage(
+	ctx context.Context,
+	resource agentmodel.QuotaResource,
+) (agentmodel.ResourceQuotaUsage, error) {
+	used, err := s.count(ctx, resource)
+	if err != nil {
+		return agentmodel.ResourceQuotaUsage{}, fmt.Errorf("failed to count %s resources: %w", resource, err)
+	}
+
+	return agentmodel.ResourceQuotaUsage{
+		Resource: resource,
+		Used:     used,
+		Limit:    max(s.quotas[resource], 0),
+	}, nil
+}
+
+// count asks for a single-item page; the page size plus the remaining count is the total.
+func (s *ResourceQuotaService) count(ctx context.Context, resource agentmodel.QuotaResource) (int64, error) {
+	//exhaustruct:ignore
+	options := &model.ListOptions{Limit: 1}
+
+	switch resource {
+	case agentmodel.QuotaResourceAgentGroup:
+		resp, err := s.agentGroupPersistencePort.ListAgentGroups(ctx, options)
+		if err != nil {
+			return 0, fmt.Errorf("list agent groups: %w", err)
+		}
+
+		return int64(len(resp.Items)) + resp.RemainingItemCount, nil
+	case agentmodel.QuotaResourceCertificate:
+		resp, err := s.certificatePersistencePort.ListCertificate(ctx, options)
+		if err != nil {
+			return 0, fmt.Errorf("list certificates: %w", err)
+		}
+
+		return int64(len(resp.Items)) + resp.RemainingItemCount, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown quota resource %q", model.ErrInvalidArgument, resource)
+	}
+}
@@ -0,0 +1,89 @@
+package agentservice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
+)
+
+func TestResourceQuotaService(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newService := func(quotas agentmodel.ResourceQuotas) (*agentservice.ResourceQuotaService,
+		*inmemory.AgentGroupRepository,
+	) {
+		groups := inmemory.NewAgentGroupRepository(inmemory.NewAgentRepository())
+		service := agentservice.NewResourceQuotaService(groups, inmemory.NewCertificateRepository())
+		service.SetResourceQuotas(quotas)
+
+		return service, groups
+	}
+
+	t.Run("creates up to the quota and rejects beyond it", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		service, groups := newService(agentmodel.ResourceQuotas{agentmodel.QuotaResourceAgentGroup: 3})
+
+		for i, name := range []string{"g-1", "g-2", "g-3"} {
+			require.NoError(t, service.CheckResourceQuota(ctx, agentmodel.QuotaResourceAgentGroup), "create #%d", i+1)
+
+			_, err := groups.PutAgentGroup(ctx, "default", name,
+				agentmodel.NewAgentGroup("default", name, nil, now, "tester"))
+			require.NoError(t, err)
+		}
+
+		err := service.CheckResourceQuota(ctx, agentmodel.QuotaResourceAgentGroup)
+		require.ErrorIs(t, err, model.ErrQuotaExceeded)
+		require.NoError(t, service.CheckResourceQuota(ctx, agentmodel.QuotaResourceCertificate),
+			"a quota only caps its own resource")
+	})
+
+	t.Run("deleted resources free their slot", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		service, groups := newService(agentmodel.ResourceQuotas{agentmodel.QuotaResourceAgentGroup: 1})
+
+		group := agentmodel.NewAgentGroup("default", "g-1", nil, now, "tester")
+		_, err := groups.PutAgentGroup(ctx, "default", "g-1", group)
+		require.NoError(t, err)
+		require.ErrorIs(t, service.CheckResourceQuota(ctx, agentmodel.QuotaResourceAgentGroup),
+			model.ErrQuotaExceeded)
+
+		group.MarkDeleted(now, "tester")
+		_, err = groups.PutAgentGroup(ctx, "default", "g-1", group)
+		require.NoError(t, err)
+		require.NoError(t, service.CheckResourceQuota(ctx, agentmodel.QuotaResourceAgentGroup))
+	})
+
+	t.Run("reports usage of every resource", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		service, groups := newService(agentmodel.ResourceQuotas{
+			agentmodel.QuotaResourceAgentGroup:  5,
+			agentmodel.QuotaResourceCertificate: -1,
+		})
+
+		_, err := groups.PutAgentGroup(ctx, "default", "g-1",
+			agentmodel.NewAgentGroup("default", "g-1", nil, now, "tester"))
+		require.NoError(t, err)
+
+		usages, err := service.ListResourceQuotaUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []agentmodel.ResourceQuotaUsage{
+			{Resource: agentmodel.QuotaResourceAgentGroup, Used: 1, Limit: 5},
+			{Resource: agentmodel.QuotaResourceCertificate, Used: 0, Limit: 0},
+		}, usages)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,9 +18,13 @@ import (
 )
 
 var (
-	_ agentport.ServerUsecase                   = (*ServerService)(nil)
-	_ agentport.LeaderElector                   = (*ServerService)(nil)
-	_ agentport.AgentCacheInvalidationPublisher = (*ServerService)(nil)
+	_ agentport.ServerUsecase                       = (*ServerService)(nil)
+	_ agentport.LeaderElector                       = (*ServerService)(nil)
+	_ agentport.AgentCacheInvalidationPublisher     = (*ServerService)(nil)
+	_ agentport.AgentGroupChangePublisher           = (*ServerService)(nil)
+	_ agentport.AgentDisconnectPublisher            = (*ServerService)(nil)
+	_ agentport.AgentEffectiveConfigChangePublisher = (*ServerService)(nil)
+	_ agentport.AgentEffectiveConfigWatcher         = (*ServerService)(nil)
 
 	// ErrNoCurrentServerID is returned by IsLeader when the current server has no
 	// identity, so leadership cannot be determined.
@@ -31,6 +36,11 @@ const (
 	DefaultServerCacheTTL = 30 * time.Second
 	// DefaultServerCacheCapacity is the default maximum number of server cache entries.
 	DefaultServerCacheCapacity = 100
+	// DefaultProcessedUpdateTTL is how long a consumed agent update is remembered for
+	// deduplication. Redeliveries arrive within seconds, so this only needs to outlast them.
+	DefaultProcessedUpdateTTL = 5 * time.Minute
+	// DefaultProcessedUpdateCapacity bounds the number of agents remembered for deduplication.
+	DefaultProcessedUpdateCapacity = 10000
 )
 
 // ServerService is a struct that implements the ServerUsecase interface.
@@ -40,6 +50,9 @@ type ServerService struct {
 	heartbeatTimeout time.Duration
 
 	serverCache *ttlcache.Cache[string, *agentmodel.Server]
+	// processedUpdates remembers the latest update sequence number consumed per agent, so
+	// a SendServerToAgent event delivered more than once is applied only once.
+	processedUpdates *ttlcache.Cache[uuid.UUID, int64]
 
 	serverPersistencePort   agentport.ServerPersistencePort
 	serverEventSenderPort   agentport.ServerEventSenderPort
@@ -49,6 +62,11 @@ type ServerService struct {
 	agentUsecase            agentport.AgentUsecase
 	agentCacheInvalidator   agentport.AgentCacheInvalidator
 	serverToAgentBuilder    *ServerToAgentBuilder
+
+	// effectiveConfigWatchers holds the channels of the effective-config watches open on
+	// this server, per agent.
+	effectiveConfigWatchersMu sync.Mutex
+	effectiveConfigWatchers   map[uuid.UUID]map[chan struct{}]struct{}
 }
 
 // NewServerService creates a new instance of the ServerService.
@@ -73,10 +91,16 @@ func NewServerService(
 		slog.Int64("maxCapacity", DefaultServerCacheCapacity),
 	)
 
+	processedUpdates := ttlcache.New[uuid.UUID, int64](
+		ttlcache.WithTTL[uuid.UUID, int64](DefaultProcessedUpdateTTL),
+		ttlcache.WithCapacity[uuid.UUID, int64](DefaultProcessedUpdateCapacity),
+	)
+
 	return &ServerService{
 		logger:                  logger,
 		clock:                   clock.NewRealClock(),
 		serverCache:             serverCache,
+		processedUpdates:        processedUpdates,
 		serverPersistencePort:   serverPersistencePort,
 		serverEventSenderPort:   serverEventSenderPort,
 		serverEventReceiverPort: serverEventReceiverPort,
@@ -86,6 +110,9 @@ func NewServerService(
 		agentUsecase:            agentUsecase,
 		agentCacheInvalidator:   agentCacheInvalidator,
 		serverToAgentBuilder:    serverToAgentBuilder,
+
+		effectiveConfigWatchersMu: sync.Mutex{},
+		effectiveConfigWatchers:   make(map[uuid.UUID]map[chan struct{}]struct{}),
 	}
 }
 
@@ -105,6 +132,8 @@ func (s *ServerService) Shutdown() {
 	s.logger.Info("shutting down server service, clearing cache")
 	s.serverCache.DeleteAll()
 	s.serverCache.Stop()
+	s.processedUpdates.DeleteAll()
+	s.processedUpdates.Stop()
 }
 
 // Run starts the server service.
@@ -289,6 +318,9 @@ func (s *ServerService) BroadcastAgentCacheInvalidation(
 				MessageForInvalidateAgentCache: &serverevent.MessageForInvalidateAgentCache{
 					AgentInstanceUIDs: instanceUIDs,
 				},
+				MessageForAgentGroupChanged:           nil,
+				MessageForDisconnectAgent:             nil,
+				MessageForAgentEffectiveConfigChanged: nil,
 			},
 		}
 
@@ -303,6 +335,186 @@ func (s *ServerService) BroadcastAgentCacheInvalidation(
 	return nil
 }
 
+// PublishAgentGroupChange implements agentport.AgentGroupChangePublisher.
+//
+// Unlike cache invalidation, the current server is included: the message reaches it
+// through the same local short-circuit, so every node handles the change the same way.
+func (s *ServerService) PublishAgentGroupChange(
+	ctx context.Context,
+	change *agentmodel.AgentGroupConfigChange,
+) error {
+	servers, err := s.ListServers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list servers for agent group change: %w", err)
+	}
+
+	currentID := ""
+	if s.serverIdentityProvider != nil {
+		currentID = s.serverIdentityProvider.CurrentServerID()
+	}
+
+	payload := &serverevent.MessageForAgentGroupChanged{
+		Namespace: change.Namespace,
+		Name:      change.Name,
+		Selector: serverevent.AgentGroupSelector{
+			IdentifyingAttributes:          change.Selector.IdentifyingAttributes,
+			NonIdentifyingAttributes:       change.Selector.NonIdentifyingAttributes,
+			AbsentIdentifyingAttributes:    change.Selector.AbsentIdentifyingAttributes,
+			AbsentNonIdentifyingAttributes: change.Selector.AbsentNonIdentifyingAttributes,
+		},
+		SelectorChanged: change.SelectorChanged,
+		AddedKeys:       change.AddedKeys,
+		RemovedKeys:     change.RemovedKeys,
+		ModifiedKeys:    change.ModifiedKeys,
+		ChangedAt:       change.ChangedAt,
+		ChangedBy:       change.ChangedBy,
+	}
+
+	for _, server := range servers {
+		message := serverevent.Message{
+			Source: currentID,
+			Target: server.ID,
+			Type:   serverevent.MessageTypeAgentGroupChanged,
+			Payload: serverevent.MessagePayload{
+				MessageForServerToAgent:               nil,
+				MessageForInvalidateAgentCache:        nil,
+				MessageForAgentGroupChanged:           payload,
+				MessageForDisconnectAgent:             nil,
+				MessageForAgentEffectiveConfigChanged: nil,
+			},
+		}
+
+		sendErr := s.SendMessageToServer(ctx, server, message)
+		if sendErr != nil {
+			s.logger.Warn("failed to publish agent group change to server",
+				slog.String("serverID", server.ID),
+				slog.String("error", sendErr.Error()))
+		}
+	}
+
+	return nil
+}
+
+// RequestAgentDisconnect implements agentport.AgentDisconnectPublisher.
+//
+// The request goes only to the server the agent last reported to; when that is the current
+// server it is handled in-process through the local short-circuit.
+func (s *ServerService) RequestAgentDisconnect(ctx context.Context, agent *agentmodel.Agent) error {
+	serverID, err := agent.ConnectedServerID()
+	if err != nil {
+		return fmt.Errorf("failed to get connected server ID: %w", err)
+	}
+
+	if serverID == "" {
+		return agentport.ErrAgentNotConnected
+	}
+
+	server, err := s.GetServer(ctx, serverID)
+	if err != nil {
+		return fmt.Errorf("failed to get connected server: %w", err)
+	}
+
+	currentID := ""
+	if s.serverIdentityProvider != nil {
+		currentID = s.serverIdentityProvider.CurrentServerID()
+	}
+
+	message := serverevent.Message{
+		Source: currentID,
+		Target: server.ID,
+		Type:   serverevent.MessageTypeDisconnectAgent,
+		Payload: serverevent.MessagePayload{
+			MessageForServerToAgent:        nil,
+			MessageForInvalidateAgentCache: nil,
+			MessageForAgentGroupChanged:    nil,
+			MessageForDisconnectAgent: &serverevent.MessageForDisconnectAgent{
+				AgentInstanceUID: agent.Metadata.InstanceUID,
+			},
+			MessageForAgentEffectiveConfigChanged: nil,
+		},
+	}
+
+	err = s.SendMessageToServer(ctx, server, message)
+	if err != nil {
+		return fmt.Errorf("failed to request agent disconnect: %w", err)
+	}
+
+	return nil
+}
+
+// PublishAgentEffectiveConfigChange implements agentport.AgentEffectiveConfigChangePublisher.
+//
+// Like agent group changes, the current server is included, so its own watchers are
+// notified through the local short-circuit.
+func (s *ServerService) PublishAgentEffectiveConfigChange(ctx context.Context, instanceUID uuid.UUID) error {
+	servers, err := s.ListServers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list servers for effective config change: %w", err)
+	}
+
+	currentID := ""
+	if s.serverIdentityProvider != nil {
+		currentID = s.serverIdentityProvider.CurrentServerID()
+	}
+
+	for _, server := range servers {
+		message := serverevent.Message{
+			Source: currentID,
+			Target: server.ID,
+			Type:   serverevent.MessageTypeAgentEffectiveConfigChanged,
+			Payload: serverevent.MessagePayload{
+				MessageForServerToAgent:        nil,
+				MessageForInvalidateAgentCache: nil,
+				MessageForAgentGroupChanged:    nil,
+				MessageForDisconnectAgent:      nil,
+				MessageForAgentEffectiveConfigChanged: &serverevent.MessageForAgentEffectiveConfigChanged{
+					ChangedAgentInstanceUID: instanceUID,
+				},
+			},
+		}
+
+		sendErr := s.SendMessageToServer(ctx, server, message)
+		if sendErr != nil {
+			s.logger.Warn("failed to publish agent effective config change to server",
+				slog.String("serverID", server.ID),
+				slog.String("error", sendErr.Error()))
+		}
+	}
+
+	return nil
+}
+
+// WatchAgentEffectiveConfig implements agentport.AgentEffectiveConfigWatcher.
+func (s *ServerService) WatchAgentEffectiveConfig(instanceUID uuid.UUID) (<-chan struct{}, func()) {
+	// A buffer of one keeps a change announced while the watcher is busy, and lets
+	// further ones coalesce into it without blocking the event loop.
+	changes := make(chan struct{}, 1)
+
+	s.effectiveConfigWatchersMu.Lock()
+	defer s.effectiveConfigWatchersMu.Unlock()
+
+	watchers, ok := s.effectiveConfigWatchers[instanceUID]
+	if !ok {
+		watchers = make(map[chan struct{}]struct{})
+		s.effectiveConfigWatchers[instanceUID] = watchers
+	}
+
+	watchers[changes] = struct{}{}
+
+	stop := sync.OnceFunc(func() {
+		s.effectiveConfigWatchersMu.Lock()
+		defer s.effectiveConfigWatchersMu.Unlock()
+
+		delete(watchers, changes)
+
+		if len(watchers) == 0 {
+			delete(s.effectiveConfigWatchers, instanceUID)
+		}
+	})
+
+	return changes, stop
+}
+
 func (s *ServerService) loopForReceivingMessages(ctx context.Context) error {
 	// StartReceiver is a blocking call.
 	// So, we don't need a loop here.
@@ -321,6 +533,12 @@ func (s *ServerService) handleServerEvent(ctx context.Context, event *servereven
 		return s.handleSendServerToAgentEvent(ctx, event)
 	case serverevent.MessageTypeInvalidateAgentCache:
 		return s.handleInvalidateAgentCacheEvent(event)
+	case serverevent.MessageTypeAgentGroupChanged:
+		return s.handleAgentGroupChangedEvent(event)
+	case serverevent.MessageTypeDisconnectAgent:
+		return s.handleDisconnectAgentEvent(ctx, event)
+	case serverevent.MessageTypeAgentEffectiveConfigChanged:
+		return s.handleAgentEffectiveConfigChangedEvent(event)
 	default:
 		s.logger.Warn("unknown server event type", slog.String("eventType", event.Type.String()))
 
@@ -353,6 +571,87 @@ func (s *ServerService) handleInvalidateAgentCacheEvent(event *serverevent.Messa
 	return nil
 }
 
+// handleAgentGroupChangedEvent records a received AgentGroup config diff. There is no
+// watch stream to forward it to yet, so the structured log line is its consumer.
+func (s *ServerService) handleAgentGroupChangedEvent(event *serverevent.Message) error {
+	change := event.Payload.MessageForAgentGroupChanged
+	if change == nil {
+		return ErrEventPayloadNil
+	}
+
+	s.logger.Info("agent group config changed",
+		slog.String("namespace", change.Namespace),
+		slog.String("name", change.Name),
+		slog.Any("addedKeys", change.AddedKeys),
+		slog.Any("removedKeys", change.RemovedKeys),
+		slog.Any("modifiedKeys", change.ModifiedKeys),
+		slog.Bool("selectorChanged", change.SelectorChanged),
+		slog.String("sourceServerID", event.Source),
+	)
+
+	return nil
+}
+
+// handleAgentEffectiveConfigChangedEvent notifies the watches of the agent open on this
+// server. A change reported through another server also drops the agent from the local
+// cache first, so the watchers read the new config rather than a stale copy.
+func (s *ServerService) handleAgentEffectiveConfigChangedEvent(event *serverevent.Message) error {
+	payload := event.Payload.MessageForAgentEffectiveConfigChanged
+	if payload == nil {
+		return ErrEventPayloadNil
+	}
+
+	instanceUID := payload.ChangedAgentInstanceUID
+
+	s.effectiveConfigWatchersMu.Lock()
+	defer s.effectiveConfigWatchersMu.Unlock()
+
+	watchers := s.effectiveConfigWatchers[instanceUID]
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	if s.serverIdentityProvider == nil || event.Source != s.serverIdentityProvider.CurrentServerID() {
+		s.agentCacheInvalidator.InvalidateCache(instanceUID)
+	}
+
+	for changes := range watchers {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// handleDisconnectAgentEvent closes the listed agent's connection on this server. An agent
+// that has already dropped its connection here is not an error: it reconnects regardless.
+func (s *ServerService) handleDisconnectAgentEvent(ctx context.Context, event *serverevent.Message) error {
+	payload := event.Payload.MessageForDisconnectAgent
+	if payload == nil {
+		return ErrEventPayloadNil
+	}
+
+	err := s.connectionUsecase.DisconnectAgent(ctx, payload.AgentInstanceUID)
+	if err != nil {
+		if errors.Is(err, agentport.ErrConnectionNotFound) {
+			s.logger.Debug("agent to disconnect has no connection on this server",
+				slog.String("instanceUID", payload.AgentInstanceUID.String()))
+
+			return nil
+		}
+
+		return fmt.Errorf("failed to disconnect agent: %w", err)
+	}
+
+	s.logger.Info("disconnected agent on request",
+		slog.String("instanceUID", payload.AgentInstanceUID.String()),
+		slog.String("sourceServerID", event.Source))
+
+	return nil
+}
+
 var (
 	// ErrEventPayloadNil is returned when the event payload is nil.
 	ErrEventPayloadNil = errors.New("event payload is nil")
@@ -376,6 +675,15 @@ func (s *ServerService) handleSendServerToAgentEvent(ctx context.Context, event
 		slog.Int("targetAgentCount", len(targetAgentUIDs)))
 
 	for _, instanceUID := range targetAgentUIDs {
+		sequenceNum := event.Payload.TargetAgentSequenceNums[instanceUID]
+		if s.isUpdateProcessed(instanceUID, sequenceNum) {
+			s.logger.Debug("skipping already processed agent update",
+				slog.String("instanceUID", instanceUID.String()),
+				slog.Int64("sequenceNum", sequenceNum))
+
+			continue
+		}
+
 		err := s.sendServerToAgentForInstance(ctx, instanceUID)
 		if err != nil {
 			s.logger.Error("failed to send ServerToAgent message",
@@ -385,6 +693,9 @@ func (s *ServerService) handleSendServerToAgentEvent(ctx context.Context, event
 			continue
 		}
 
+		// Only a successful send is recorded, so a redelivery can retry a failed one.
+		s.markUpdateProcessed(instanceUID, sequenceNum)
+
 		s.logger.Info("successfully sent ServerToAgent message",
 			slog.String("instanceUID", instanceUID.String()))
 	}
@@ -392,6 +703,39 @@ func (s *ServerService) handleSendServerToAgentEvent(ctx context.Context, event
 	return nil
 }
 
+// isUpdateProcessed reports whether an update with the given sequence number, or a later
+// one, has already been applied for the agent. A zero sequence number is never considered
+// processed, because senders that predate sequence numbers leave it unset.
+func (s *ServerService) isUpdateProcessed(instanceUID uuid.UUID, sequenceNum int64) bool {
+	if sequenceNum <= 0 {
+		return false
+	}
+
+	item := s.processedUpdates.Get(instanceUID)
+
+	return item != nil && item.Value() >= sequenceNum
+}
+
+func (s *ServerService) markUpdateProcessed(instanceUID uuid.UUID, sequenceNum int64) {
+	if sequenceNum <= 0 {
+		return
+	}
+
+	item := s.processedUpdates.Get(instanceUID)
+	if item != nil && item.Value() >= sequenceNum {
+		return
+	}
+
+	s.processedUpdates.Set(instanceUID, sequenceNum, ttlcache.DefaultTTL)
+}
+
+// ForgetAgentDeliveries implements agentport.AgentDeliveryTracker. An update is recorded as
+// processed once it is written to the agent's connection, not once the agent applies it, so
+// an agent that disconnects mid-delivery would otherwise have the redelivery skipped.
+func (s *ServerService) ForgetAgentDeliveries(instanceUID uuid.UUID) {
+	s.processedUpdates.Delete(instanceUID)
+}
+
 // sendServerToAgentForInstance sends a serverToAgent message to a specific agent instance.
 func (s *ServerService) sendServerToAgentForInstance(ctx context.Context, instanceUID uuid.UUID) error {
 	// Get the agent to fetch current state and build the ServerToAgent message
@@ -232,6 +232,12 @@ func (m *MockConnectionUsecase) SendServerToAgent(
 	return args.Error(0) //nolint:wrapcheck // mock error
 }
 
+func (m *MockConnectionUsecase) DisconnectAgent(ctx context.Context, instanceUID uuid.UUID) error {
+	args := m.Called(ctx, instanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 type MockAgentUsecase struct {
 	mock.Mock
 }
@@ -337,6 +343,49 @@ func (m *MockAgentUsecase) SearchAgents(
 	return resp, args.Error(1) //nolint:wrapcheck // mock error
 }
 
+func (m *MockAgentUsecase) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	options *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	args := m.Called(ctx, namespace, packageName, packageVersion, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	resp, ok := args.Get(0).(*model.ListResponse[*agentmodel.Agent])
+	if !ok {
+		return nil, errUnexpectedType
+	}
+
+	return resp, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentUsecase) ListAgentAttributes(
+	ctx context.Context, valueLimit int,
+) (*agentmodel.AttributeCatalog, error) {
+	args := m.Called(ctx, valueLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1) //nolint:wrapcheck // mock error
+	}
+
+	catalog, _ := args.Get(0).(*agentmodel.AttributeCatalog)
+
+	return catalog, args.Error(1) //nolint:wrapcheck // mock error
+}
+
+func (m *MockAgentUsecase) CheckNewInstanceUIDAvailable(
+	ctx context.Context,
+	instanceUID uuid.UUID,
+	newInstanceUID uuid.UUID,
+) error {
+	args := m.Called(ctx, instanceUID, newInstanceUID)
+
+	return args.Error(0) //nolint:wrapcheck // mock error
+}
+
 func TestServerService_GetServer_CacheHit(t *testing.T) {
 	t.Parallel()
 
@@ -581,6 +630,128 @@ func TestServerService_SendMessageToServer_LocalShortCircuit(t *testing.T) {
 		mock.Anything, mock.Anything, mock.Anything)
 }
 
+func TestServerService_ReceivedUpdateIsAppliedOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	instanceUID := uuid.New()
+
+	mockPersistence := new(MockServerPersistencePort)
+	mockEventSender := new(MockServerEventSenderPort)
+	mockEventReceiver := new(MockServerEventReceiverPort)
+	mockIdentity := new(MockServerIdentityProvider)
+	mockConnection := new(MockConnectionUsecase)
+	mockAgent := new(MockAgentUsecase)
+
+	agentEntity := &agentmodel.Agent{
+		Metadata: agentmodel.AgentMetadata{InstanceUID: instanceUID},
+	}
+	mockAgent.On("GetAgent", ctx, instanceUID).Return(agentEntity, nil).Once()
+	mockConnection.On("SendServerToAgent", ctx, instanceUID, mock.Anything).Return(nil).Once()
+
+	update := func(sequenceNum int64) *serverevent.Message {
+		return &serverevent.Message{
+			Source: "server-2",
+			Target: testServerID,
+			Type:   serverevent.MessageTypeSendServerToAgent,
+			Payload: serverevent.MessagePayload{
+				MessageForServerToAgent: &serverevent.MessageForServerToAgent{
+					TargetAgentInstanceUIDs: []uuid.UUID{instanceUID},
+					TargetAgentSequenceNums: map[uuid.UUID]int64{instanceUID: sequenceNum},
+				},
+			},
+		}
+	}
+
+	// The broker delivers the same update twice, followed by an older one.
+	mockEventReceiver.On("StartReceiver", ctx, mock.Anything).
+		Run(func(args mock.Arguments) {
+			handler, _ := args.Get(1).(agentport.ReceiveServerEventHandler)
+			assert.NoError(t, handler(ctx, update(7)))
+			assert.NoError(t, handler(ctx, update(7)))
+			assert.NoError(t, handler(ctx, update(6)))
+		}).
+		Return(nil)
+
+	svc := agentservice.NewServerService(
+		slog.Default(),
+		mockPersistence,
+		mockEventSender,
+		mockEventReceiver,
+		mockIdentity,
+		mockConnection,
+		mockAgent,
+		noopAgentCacheInvalidator{},
+		agentservice.NewServerToAgentBuilder(nil, slog.Default()),
+	)
+
+	require.NoError(t, svc.Run(ctx))
+
+	mockAgent.AssertNumberOfCalls(t, "GetAgent", 1)
+	mockConnection.AssertNumberOfCalls(t, "SendServerToAgent", 1)
+}
+
+func TestServerService_ForgottenDeliveryIsResentAfterReconnect(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	instanceUID := uuid.New()
+
+	mockPersistence := new(MockServerPersistencePort)
+	mockEventSender := new(MockServerEventSenderPort)
+	mockEventReceiver := new(MockServerEventReceiverPort)
+	mockIdentity := new(MockServerIdentityProvider)
+	mockConnection := new(MockConnectionUsecase)
+	mockAgent := new(MockAgentUsecase)
+
+	agentEntity := &agentmodel.Agent{
+		Metadata: agentmodel.AgentMetadata{InstanceUID: instanceUID},
+	}
+	mockAgent.On("GetAgent", ctx, instanceUID).Return(agentEntity, nil)
+	mockConnection.On("SendServerToAgent", ctx, instanceUID, mock.Anything).Return(nil)
+
+	update := &serverevent.Message{
+		Source: "server-2",
+		Target: testServerID,
+		Type:   serverevent.MessageTypeSendServerToAgent,
+		Payload: serverevent.MessagePayload{
+			MessageForServerToAgent: &serverevent.MessageForServerToAgent{
+				TargetAgentInstanceUIDs: []uuid.UUID{instanceUID},
+				TargetAgentSequenceNums: map[uuid.UUID]int64{instanceUID: 7},
+			},
+		},
+	}
+
+	var svc *agentservice.ServerService
+
+	mockEventReceiver.On("StartReceiver", ctx, mock.Anything).
+		Run(func(args mock.Arguments) {
+			handler, _ := args.Get(1).(agentport.ReceiveServerEventHandler)
+			// The offer is pushed, but the agent disconnects before applying it.
+			assert.NoError(t, handler(ctx, update))
+			svc.ForgetAgentDeliveries(instanceUID)
+			// After the agent reconnects, the same update is redelivered.
+			assert.NoError(t, handler(ctx, update))
+		}).
+		Return(nil)
+
+	svc = agentservice.NewServerService(
+		slog.Default(),
+		mockPersistence,
+		mockEventSender,
+		mockEventReceiver,
+		mockIdentity,
+		mockConnection,
+		mockAgent,
+		noopAgentCacheInvalidator{},
+		agentservice.NewServerToAgentBuilder(nil, slog.Default()),
+	)
+
+	require.NoError(t, svc.Run(ctx))
+
+	mockConnection.AssertNumberOfCalls(t, "SendServerToAgent", 2)
+}
+
 func TestServerService_SendMessageToServer_RemoteDispatch(t *testing.T) {
 	t.Parallel()
 
@@ -844,3 +1015,191 @@ func TestServerService_HandleInvalidateAgentCacheEvent_InvalidatesLocally(t *tes
 	assert.Equal(t, uid, spy.invalidated[0])
 	mockEventSender.AssertNotCalled(t, "SendMessageToServer", mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestServerService_PublishAgentEffectiveConfigChange_NotifiesWatchers(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	now := time.Now()
+	uid := uuid.New()
+
+	mockPersistence := new(MockServerPersistencePort)
+	mockEventSender := new(MockServerEventSenderPort)
+	mockIdentity := new(MockServerIdentityProvider)
+	spy := new(spyAgentCacheInvalidator)
+
+	mockIdentity.On("CurrentServerID").Return("server-1")
+	mockPersistence.On("ListServers", ctx).Return([]*agentmodel.Server{
+		{ID: "server-1", LastHeartbeatAt: now}, // self
+		{ID: "server-2", LastHeartbeatAt: now}, // peer
+	}, nil)
+	mockEventSender.On("SendMessageToServer", ctx, "server-2", mock.MatchedBy(
+		func(m serverevent.Message) bool {
+			return m.Type == serverevent.MessageTypeAgentEffectiveConfigChanged &&
+				m.Payload.MessageForAgentEffectiveConfigChanged != nil &&
+				m.Payload.ChangedAgentInstanceUID == uid
+		},
+	)).Return(nil)
+
+	svc := newServerServiceForInvalidation(mockPersistence, mockEventSender, mockIdentity, spy, now)
+
+	changes, stop := svc.WatchAgentEffectiveConfig(uid)
+	otherChanges, stopOther := svc.WatchAgentEffectiveConfig(uuid.New())
+
+	defer stopOther()
+
+	// A change published by this server reaches its own watchers and every peer.
+	err := svc.PublishAgentEffectiveConfigChange(ctx, uid)
+	require.NoError(t, err)
+
+	assert.Len(t, changes, 1)
+	assert.Empty(t, otherChanges)
+	assert.Empty(t, spy.invalidated, "the publishing server already holds the new config")
+	mockEventSender.AssertExpectations(t)
+
+	// A change announced by a peer also drops the agent from the local cache.
+	<-changes
+
+	err = svc.SendMessageToServer(ctx, &agentmodel.Server{ID: "server-1", LastHeartbeatAt: now}, serverevent.Message{
+		Source: "server-2",
+		Target: "server-1",
+		Type:   serverevent.MessageTypeAgentEffectiveConfigChanged,
+		Payload: serverevent.MessagePayload{
+			MessageForAgentEffectiveConfigChanged: &serverevent.MessageForAgentEffectiveConfigChanged{
+				ChangedAgentInstanceUID: uid,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, []uuid.UUID{uid}, spy.invalidated)
+
+	// Once stopped, the watch is no longer notified.
+	<-changes
+	stop()
+
+	err = svc.PublishAgentEffectiveConfigChange(ctx, uid)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func newServerServiceForDisconnect(
+	mockPersistence *MockServerPersistencePort,
+	mockEventSender *MockServerEventSenderPort,
+	mockConnection *MockConnectionUsecase,
+	now time.Time,
+) *agentservice.ServerService {
+	mockIdentity := new(MockServerIdentityProvider)
+	mockIdentity.On("CurrentServerID").Return("server-1")
+
+	svc := agentservice.NewServerService(
+		slog.Default(),
+		mockPersistence,
+		mockEventSender,
+		new(MockServerEventReceiverPort),
+		mockIdentity,
+		mockConnection,
+		new(MockAgentUsecase),
+		noopAgentCacheInvalidator{},
+		agentservice.NewServerToAgentBuilder(nil, slog.Default()),
+	)
+	svc.SetClock(newTestFakeClock(now))
+
+	return svc
+}
+
+func TestServerService_RequestAgentDisconnect(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	connectedTo := func(instanceUID uuid.UUID, serverID string) *agentmodel.Agent {
+		agent := agentmodel.NewAgent(instanceUID)
+		agent.Status.LastReportedTo = serverID
+
+		return agent
+	}
+
+	t.Run("closes the connection held by the current server", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		mockPersistence := new(MockServerPersistencePort)
+		mockEventSender := new(MockServerEventSenderPort)
+		mockConnection := new(MockConnectionUsecase)
+
+		mockPersistence.On("GetServer", ctx, "server-1").
+			Return(&agentmodel.Server{ID: "server-1", LastHeartbeatAt: now}, nil)
+		mockConnection.On("DisconnectAgent", ctx, instanceUID).Return(nil)
+
+		svc := newServerServiceForDisconnect(mockPersistence, mockEventSender, mockConnection, now)
+
+		err := svc.RequestAgentDisconnect(ctx, connectedTo(instanceUID, "server-1"))
+		require.NoError(t, err)
+
+		mockConnection.AssertCalled(t, "DisconnectAgent", ctx, instanceUID)
+		mockEventSender.AssertNotCalled(t, "SendMessageToServer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends the request to the server holding the connection", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		mockPersistence := new(MockServerPersistencePort)
+		mockEventSender := new(MockServerEventSenderPort)
+		mockConnection := new(MockConnectionUsecase)
+
+		mockPersistence.On("GetServer", ctx, "server-2").
+			Return(&agentmodel.Server{ID: "server-2", LastHeartbeatAt: now}, nil)
+		mockEventSender.On("SendMessageToServer", ctx, "server-2", mock.MatchedBy(
+			func(m serverevent.Message) bool {
+				return m.Type == serverevent.MessageTypeDisconnectAgent &&
+					m.Payload.MessageForDisconnectAgent != nil &&
+					m.Payload.AgentInstanceUID == instanceUID
+			},
+		)).Return(nil)
+
+		svc := newServerServiceForDisconnect(mockPersistence, mockEventSender, mockConnection, now)
+
+		err := svc.RequestAgentDisconnect(ctx, connectedTo(instanceUID, "server-2"))
+		require.NoError(t, err)
+
+		mockEventSender.AssertExpectations(t)
+		mockConnection.AssertNotCalled(t, "DisconnectAgent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignores a connection already gone from the current server", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		instanceUID := uuid.New()
+		mockPersistence := new(MockServerPersistencePort)
+		mockConnection := new(MockConnectionUsecase)
+
+		mockPersistence.On("GetServer", ctx, "server-1").
+			Return(&agentmodel.Server{ID: "server-1", LastHeartbeatAt: now}, nil)
+		mockConnection.On("DisconnectAgent", ctx, instanceUID).Return(agentport.ErrConnectionNotFound)
+
+		svc := newServerServiceForDisconnect(mockPersistence, new(MockServerEventSenderPort), mockConnection, now)
+
+		err := svc.RequestAgentDisconnect(ctx, connectedTo(instanceUID, "server-1"))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an agent that has not reported to any server", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		mockPersistence := new(MockServerPersistencePort)
+		mockConnection := new(MockConnectionUsecase)
+
+		svc := newServerServiceForDisconnect(mockPersistence, new(MockServerEventSenderPort), mockConnection, now)
+
+		err := svc.RequestAgentDisconnect(ctx, agentmodel.NewAgent(uuid.New()))
+		require.ErrorIs(t, err, agentport.ErrAgentNotConnected)
+		mockConnection.AssertNotCalled(t, "DisconnectAgent", mock.Anything, mock.Anything)
+	})
+}
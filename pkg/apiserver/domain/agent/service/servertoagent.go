@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/open-telemetry/opamp-go/protobufs"
@@ -11,7 +13,6 @@ import (
 
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
-	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model/vo"
 )
 
 // ServerToAgentBuilder builds the [protobufs.ServerToAgent] message that describes an
@@ -46,6 +47,8 @@ func (b *ServerToAgentBuilder) Build(
 ) *protobufs.ServerToAgent {
 	instanceUID := agentModel.Metadata.InstanceUID
 
+	b.logSkippedOffers(agentModel)
+
 	// Ask for a full-state report only while the agent's info is incomplete (self-terminating
 	// once it reports). Not NeedFullStateCommand(), which is ~always true and would loop.
 	var flags uint64
@@ -53,6 +56,8 @@ func (b *ServerToAgentBuilder) Build(
 		flags |= uint64(protobufs.ServerToAgentFlags_ServerToAgentFlags_ReportFullState)
 	}
 
+	flags |= pendingReportFlags(agentModel.Spec.PendingReports)
+
 	var remoteConfig *protobufs.AgentRemoteConfig
 
 	if agentModel.HasRemoteConfig() {
@@ -64,7 +69,7 @@ func (b *ServerToAgentBuilder) Build(
 			}
 		}
 
-		hash, err := vo.NewHashFromAny(configMap)
+		hash, err := agentModel.DesiredRemoteConfigHash()
 		if err != nil {
 			b.logger.Error("failed to compute hash for remote config", "instance_uid", instanceUID, "error", err)
 
@@ -74,7 +79,7 @@ func (b *ServerToAgentBuilder) Build(
 				Config: &protobufs.AgentConfigMap{
 					ConfigMap: configMap,
 				},
-				ConfigHash: hash.Bytes(),
+				ConfigHash: hash,
 			}
 		}
 	}
@@ -107,10 +112,7 @@ func (b *ServerToAgentBuilder) Build(
 	capabilities |= int32(protobufs.ServerCapabilities_ServerCapabilities_OffersPackages)
 	capabilities |= int32(protobufs.ServerCapabilities_ServerCapabilities_AcceptsPackagesStatus)
 
-	var connectionSettings *protobufs.ConnectionSettingsOffers
-	if agentModel.Spec.ConnectionInfo.HasConnectionSettings() {
-		connectionSettings = connectionInfoToProtobuf(agentModel.Spec.ConnectionInfo)
-	}
+	connectionSettings := b.buildConnectionSettings(agentModel)
 
 	return &protobufs.ServerToAgent{
 		InstanceUid:         instanceUID[:],
@@ -130,8 +132,30 @@ func (b *ServerToAgentBuilder) Build(
 	}
 }
 
-// buildPackagesAvailable resolves each package name advertised on the agent into a
-// protobuf PackageAvailable entry, or nil when the agent has no packages to offer.
+// pendingReportFlags translates the reports the agent was asked for into ServerToAgent
+// flags. OpAMP has no flag for the effective config or the health alone, so those are
+// requested with ReportFullState, which makes the agent re-send both; available
+// components have a flag of their own. The flags are sent until the agent reports the
+// requested field, so a request is not lost if the agent misses one message.
+func pendingReportFlags(kinds []agentmodel.AgentReportKind) uint64 {
+	var flags uint64
+
+	for _, kind := range kinds {
+		switch kind {
+		case agentmodel.AgentReportKindEffectiveConfig, agentmodel.AgentReportKindHealth:
+			flags |= uint64(protobufs.ServerToAgentFlags_ServerToAgentFlags_ReportFullState)
+		case agentmodel.AgentReportKindAvailableComponents:
+			flags |= uint64(protobufs.ServerToAgentFlags_ServerToAgentFlags_ReportAvailableComponents)
+		}
+	}
+
+	return flags
+}
+
+// buildPackagesAvailable resolves each package name advertised on the agent into the file
+// offered for it, or returns nil when the agent has no packages to offer. The offer's
+// all_packages_hash is computed over the resolved files, so it changes exactly when a
+// file in the offer does.
 //
 // A package that cannot be resolved is omitted from the offer (an undescribable download
 // cannot be advertised) but never silently: the unresolved names are aggregated and logged
@@ -146,71 +170,74 @@ func (b *ServerToAgentBuilder) buildPackagesAvailable(
 		return nil
 	}
 
-	instanceUID := agentModel.Metadata.InstanceUID
-
-	agentPackages := make(map[string]*protobufs.PackageAvailable)
+	files := make([]agentmodel.AgentPackageFile, 0, len(agentModel.Spec.PackagesAvailable.Packages))
 
 	var unresolved []string
 
 	for _, pkgName := range agentModel.Spec.PackagesAvailable.Packages {
-		pkg, err := b.resolveAvailablePackage(ctx, agentModel.Metadata.Namespace, pkgName)
+		file, err := b.resolvePackageFile(ctx, agentModel.Metadata.Namespace, pkgName)
 		if err != nil {
 			unresolved = append(unresolved, pkgName)
 
 			continue
 		}
 
-		agentPackages[pkgName] = pkg
+		files = append(files, file)
 	}
 
 	if len(unresolved) > 0 {
 		b.logger.Warn("some agent packages could not be resolved and were withheld from the offer",
-			slog.String("instance_uid", instanceUID.String()),
+			slog.String("instance_uid", agentModel.Metadata.InstanceUID.String()),
 			slog.String("namespace", agentModel.Metadata.Namespace),
 			slog.Any("unresolved_packages", unresolved),
 		)
 	}
 
-	hash, err := vo.NewHashFromAny(agentPackages)
-	if err != nil {
-		b.logger.Error("failed to compute hash for packages available", "instance_uid", instanceUID, "error", err)
-
-		return nil
+	agentPackages := make(map[string]*protobufs.PackageAvailable, len(files))
+	for _, file := range files {
+		agentPackages[file.Name] = b.packageFileToProtobuf(file)
 	}
 
 	return &protobufs.PackagesAvailable{
 		Packages:        agentPackages,
-		AllPackagesHash: hash.Bytes(),
+		AllPackagesHash: agentmodel.AllPackagesHash(files),
 	}
 }
 
-// resolveAvailablePackage looks up one advertised package by name and converts it into a
-// protobuf PackageAvailable. It returns the resolution error (rather than swallowing it) so
-// the caller can surface which packages were withheld.
-func (b *ServerToAgentBuilder) resolveAvailablePackage(
+// resolvePackageFile looks up one advertised package by name and returns the file offered
+// for it. It returns the resolution error (rather than swallowing it) so the caller can
+// surface which packages were withheld.
+func (b *ServerToAgentBuilder) resolvePackageFile(
 	ctx context.Context,
 	namespace, pkgName string,
-) (*protobufs.PackageAvailable, error) {
+) (agentmodel.AgentPackageFile, error) {
 	agentPackage, err := b.agentPackageUsecase.GetAgentPackage(ctx, namespace, pkgName, nil)
 	if err != nil {
-		return nil, fmt.Errorf("get agent package %q: %w", pkgName, err)
+		return agentmodel.AgentPackageFile{}, fmt.Errorf("get agent package %q: %w", pkgName, err)
+	}
+
+	return agentPackage.PackageFile(pkgName), nil
+}
+
+// packageFileToProtobuf converts an offered package file into a protobuf PackageAvailable.
+// Headers are emitted in key order so the message is stable across builds.
+func (b *ServerToAgentBuilder) packageFileToProtobuf(file agentmodel.AgentPackageFile) *protobufs.PackageAvailable {
+	headers := make([]*protobufs.Header, 0, len(file.Headers))
+	for _, key := range slices.Sorted(maps.Keys(file.Headers)) {
+		headers = append(headers, &protobufs.Header{Key: key, Value: file.Headers[key]})
 	}
 
 	return &protobufs.PackageAvailable{
-		Type:    b.packageType(agentPackage.Spec.PackageType, pkgName),
-		Version: agentPackage.Spec.Version,
+		Type:    b.packageType(file.PackageType, file.Name),
+		Version: file.Version,
 		File: &protobufs.DownloadableFile{
-			DownloadUrl: agentPackage.Spec.DownloadURL,
-			ContentHash: agentPackage.Spec.ContentHash,
-			Signature:   agentPackage.Spec.Signature,
-			Headers: &protobufs.Headers{
-				Headers: lo.MapToSlice(agentPackage.Spec.Headers, func(key, value string) *protobufs.Header {
-					return &protobufs.Header{Key: key, Value: value}
-				}),
-			},
+			DownloadUrl: file.DownloadURL,
+			ContentHash: file.ContentHash,
+			Signature:   file.Signature,
+			Headers:     &protobufs.Headers{Headers: headers},
 		},
-		Hash: agentPackage.Spec.Hash,
-	}, nil
+		Hash: file.Hash,
+	}
 }
 
 // packageType maps an AgentPackage's spec package type to the OpAMP protobuf enum. The match
@@ -232,6 +259,87 @@ func (b *ServerToAgentBuilder) packageType(specType, pkgName string) protobufs.P
 	}
 }
 
+// logSkippedOffers records which assigned offers are withheld because the agent lacks the
+// capability to accept them. It logs at debug level because Build runs for every message;
+// the durable record for remote config is the agent's RemoteConfigApplied condition.
+func (b *ServerToAgentBuilder) logSkippedOffers(agentModel *agentmodel.Agent) {
+	skipped := agentModel.SkippedOffers()
+	if len(skipped) == 0 {
+		return
+	}
+
+	attrs := make([]any, 0, len(skipped)+1)
+	attrs = append(attrs, slog.String("instance_uid", agentModel.Metadata.InstanceUID.String()))
+
+	for _, skip := range skipped {
+		attrs = append(attrs, slog.String(skip.Offer, skip.Reason))
+	}
+
+	b.logger.Debug("withholding offers the agent cannot accept", attrs...)
+}
+
+// buildConnectionSettings converts the agent's connection settings into an offer, keeping only
+// the settings the agent declared the capability to accept. When some are dropped the hash is
+// recomputed over what is offered, so the agent re-applies once it gains the capability.
+func (b *ServerToAgentBuilder) buildConnectionSettings(
+	agentModel *agentmodel.Agent,
+) *protobufs.ConnectionSettingsOffers {
+	offers := connectionInfoToProtobuf(agentModel.Spec.ConnectionInfo)
+	if offers == nil {
+		return nil
+	}
+
+	var withheld []string
+
+	if offers.GetOpamp() != nil && !agentModel.IsOpAMPConnectionSettingsSupported() {
+		offers.Opamp = nil
+		withheld = append(withheld, agentmodel.OfferOpAMPConnectionSettings)
+	}
+
+	if offers.GetOwnMetrics() != nil && !agentModel.IsOwnMetricsSupported() {
+		offers.OwnMetrics = nil
+		withheld = append(withheld, agentmodel.OfferOwnMetricsConnectionSettings)
+	}
+
+	if offers.GetOwnLogs() != nil && !agentModel.IsOwnLogsSupported() {
+		offers.OwnLogs = nil
+		withheld = append(withheld, agentmodel.OfferOwnLogsConnectionSettings)
+	}
+
+	if offers.GetOwnTraces() != nil && !agentModel.IsOwnTracesSupported() {
+		offers.OwnTraces = nil
+		withheld = append(withheld, agentmodel.OfferOwnTracesConnectionSettings)
+	}
+
+	if len(offers.GetOtherConnections()) > 0 && !agentModel.IsOtherConnectionSettingsSupported() {
+		offers.OtherConnections = nil
+		withheld = append(withheld, agentmodel.OfferOtherConnectionSettings)
+	}
+
+	if len(withheld) == 0 {
+		return offers
+	}
+
+	if offers.GetOpamp() == nil && offers.GetOwnMetrics() == nil && offers.GetOwnLogs() == nil &&
+		offers.GetOwnTraces() == nil && len(offers.GetOtherConnections()) == 0 {
+		return nil
+	}
+
+	// The hash must match OfferedConnectionSettingsHash, which the agent's reported hash is
+	// compared against to detect drift.
+	hash, err := agentModel.OfferedConnectionSettingsHash()
+	if err != nil {
+		b.logger.Error("failed to compute hash for connection settings",
+			"instance_uid", agentModel.Metadata.InstanceUID, "error", err)
+
+		return nil
+	}
+
+	offers.Hash = hash
+
+	return offers
+}
+
 // connectionInfoToProtobuf converts ConnectionInfo to protobuf ConnectionSettingsOffers.
 func connectionInfoToProtobuf(connectionInfo *agentmodel.ConnectionInfo) *protobufs.ConnectionSettingsOffers {
 	if connectionInfo == nil || !connectionInfo.HasConnectionSettings() {
@@ -159,6 +159,47 @@ func TestServerToAgentBuilder_Build_ReportFullState(t *testing.T) {
 	}
 }
 
+// TestServerToAgentBuilder_Build_PendingReports checks the flags each report request is
+// translated to. A complete agent is used so ReportFullState is only set by the request.
+func TestServerToAgentBuilder_Build_PendingReports(t *testing.T) {
+	t.Parallel()
+
+	fullState := uint64(protobufs.ServerToAgentFlags_ServerToAgentFlags_ReportFullState)
+	availableComponents := uint64(protobufs.ServerToAgentFlags_ServerToAgentFlags_ReportAvailableComponents)
+
+	tests := []struct {
+		name      string
+		kinds     []agentmodel.AgentReportKind
+		wantFlags uint64
+	}{
+		{"effective config", []agentmodel.AgentReportKind{agentmodel.AgentReportKindEffectiveConfig}, fullState},
+		{"health", []agentmodel.AgentReportKind{agentmodel.AgentReportKindHealth}, fullState},
+		{
+			"available components",
+			[]agentmodel.AgentReportKind{agentmodel.AgentReportKindAvailableComponents},
+			availableComponents,
+		},
+		{
+			"health and available components",
+			[]agentmodel.AgentReportKind{agentmodel.AgentReportKindHealth, agentmodel.AgentReportKindAvailableComponents},
+			fullState | availableComponents,
+		},
+		{"nothing pending", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agent := completeAgent(t)
+			agent.Spec.PendingReports = tt.kinds
+
+			msg := newTestBuilder().Build(t.Context(), agent)
+			assert.Equal(t, tt.wantFlags, msg.GetFlags())
+		})
+	}
+}
+
 // TestServerToAgentBuilder_Build_IncludesRemoteConfig is the core of the two-builders
 // unification: a config assigned to the agent must be delivered by the shared builder, so a
 // cross-server push carries the config instead of an empty message.
@@ -181,6 +222,11 @@ func TestServerToAgentBuilder_Build_IncludesRemoteConfig(t *testing.T) {
 	require.NotNil(t, msg.GetRemoteConfig())
 	assert.NotEmpty(t, msg.GetRemoteConfig().GetConfigHash())
 
+	desiredHash, err := agent.DesiredRemoteConfigHash()
+	require.NoError(t, err)
+	assert.Equal(t, desiredHash, msg.GetRemoteConfig().GetConfigHash(),
+		"the hash the agent reports back is compared with the desired one")
+
 	configFile, ok := msg.GetRemoteConfig().GetConfig().GetConfigMap()["collector.yaml"]
 	require.True(t, ok, "delivered config should contain the applied file")
 	assert.Equal(t, body, configFile.GetBody())
@@ -222,13 +268,17 @@ func TestServerToAgentBuilder_Build_IncludesConnectionSettings(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	agent := agentmodel.NewAgent(uuid.New())
+	capabilities := modelagent.Capabilities(modelagent.AgentCapabilityAcceptsOpAMPConnectionSettings |
+		modelagent.AgentCapabilityReportsOwnMetrics |
+		modelagent.AgentCapabilityAcceptsOtherConnectionSettings)
+	agent := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
 	agent.Spec.ConnectionInfo = connectionInfo
 
 	msg := newTestBuilder().Build(t.Context(), agent)
 
 	offers := msg.GetConnectionSettings()
 	require.NotNil(t, offers, "connection settings should be offered")
+	assert.Equal(t, connectionInfo.Hash.Bytes(), offers.GetHash(), "nothing withheld keeps the settings hash")
 	assert.NotEmpty(t, offers.GetHash())
 
 	opamp := offers.GetOpamp()
@@ -251,6 +301,142 @@ func TestServerToAgentBuilder_Build_IncludesConnectionSettings(t *testing.T) {
 	assert.Equal(t, "https://other.example", offers.GetOtherConnections()["custom"].GetDestinationEndpoint())
 }
 
+// TestServerToAgentBuilder_Build_ConnectionSettingsDrift checks the drift condition against
+// the hash the builder actually offers, with and without withheld settings.
+func TestServerToAgentBuilder_Build_ConnectionSettingsDrift(t *testing.T) {
+	t.Parallel()
+
+	connectionInfo, err := agentmodel.NewConnectionInfo(
+		&agentmodel.AgentOpAMPConnectionSettings{DestinationEndpoint: "wss://opamp.example/v1/opamp"},
+		nil,
+		&agentmodel.AgentTelemetryConnectionSettings{DestinationEndpoint: "https://logs.example"},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		capabilities modelagent.Capability
+		drifted      bool
+	}{
+		{"matching hash", modelagent.AgentCapabilityAcceptsOpAMPConnectionSettings |
+			modelagent.AgentCapabilityReportsOwnLogs, false},
+		{"matching hash with withheld settings", modelagent.AgentCapabilityReportsOwnLogs, false},
+		{"drifted hash", modelagent.AgentCapabilityAcceptsOpAMPConnectionSettings |
+			modelagent.AgentCapabilityReportsOwnLogs, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			capabilities := modelagent.Capabilities(tt.capabilities)
+			agent := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+			agent.Spec.ConnectionInfo = connectionInfo
+
+			offered := newTestBuilder().Build(t.Context(), agent).GetConnectionSettings().GetHash()
+			require.NotEmpty(t, offered)
+
+			reported := offered
+			if tt.drifted {
+				reported = []byte("settings the agent applied before")
+			}
+
+			agent.Status.ConnectionSettingsStatus = agentmodel.AgentConnectionSettingsStatus{
+				LastConnectionSettingsHash: reported,
+				Status:                     agentmodel.ConnectionSettingsStatusApplied,
+			}
+
+			changed, err := agent.RecordConnectionSettingsDrift(now, "agent")
+			require.NoError(t, err)
+			assert.True(t, changed)
+
+			condition := agent.GetCondition(agentmodel.AgentConditionTypeConnectionSettingsDrift)
+			require.NotNil(t, condition)
+
+			want := agentmodel.AgentConditionStatusFalse
+			if tt.drifted {
+				want = agentmodel.AgentConditionStatusTrue
+			}
+
+			assert.Equal(t, want, condition.Status)
+		})
+	}
+}
+
+// TestServerToAgentBuilder_Build_WithholdsUnsupportedConnectionSettings pins that only the
+// connection settings the agent declared a capability for are offered, under a hash that
+// differs from the full settings' so gaining the capability later triggers a re-apply.
+func TestServerToAgentBuilder_Build_WithholdsUnsupportedConnectionSettings(t *testing.T) {
+	t.Parallel()
+
+	connectionInfo, err := agentmodel.NewConnectionInfo(
+		&agentmodel.AgentOpAMPConnectionSettings{DestinationEndpoint: "wss://opamp.example/v1/opamp"},
+		&agentmodel.AgentTelemetryConnectionSettings{DestinationEndpoint: "https://metrics.example"},
+		&agentmodel.AgentTelemetryConnectionSettings{DestinationEndpoint: "https://logs.example"},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("partially supported", func(t *testing.T) {
+		t.Parallel()
+
+		capabilities := modelagent.Capabilities(modelagent.AgentCapabilityReportsOwnLogs)
+		agent := agentmodel.NewAgent(uuid.New(), agentmodel.WithCapabilities(&capabilities))
+		agent.Spec.ConnectionInfo = connectionInfo
+
+		offers := newTestBuilder().Build(t.Context(), agent).GetConnectionSettings()
+		require.NotNil(t, offers)
+		assert.Nil(t, offers.GetOpamp())
+		assert.Nil(t, offers.GetOwnMetrics())
+		assert.Equal(t, "https://logs.example", offers.GetOwnLogs().GetDestinationEndpoint())
+		assert.NotEmpty(t, offers.GetHash())
+		assert.NotEqual(t, connectionInfo.Hash.Bytes(), offers.GetHash())
+
+		assert.Equal(t, []agentmodel.OfferSkip{
+			{
+				Offer:  agentmodel.OfferOpAMPConnectionSettings,
+				Reason: "agent lacks the AcceptsOpAMPConnectionSettings capability",
+			},
+			{
+				Offer:  agentmodel.OfferOwnMetricsConnectionSettings,
+				Reason: "agent lacks the ReportsOwnMetrics capability",
+			},
+		}, agent.SkippedOffers())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		agent := agentmodel.NewAgent(uuid.New())
+		agent.Spec.ConnectionInfo = connectionInfo
+
+		assert.Nil(t, newTestBuilder().Build(t.Context(), agent).GetConnectionSettings())
+		assert.Len(t, agent.SkippedOffers(), 3)
+	})
+}
+
+// TestServerToAgentBuilder_Build_WithholdsPackagesWithoutCapability pins that packages are not
+// offered to an agent that does not accept packages.
+func TestServerToAgentBuilder_Build_WithholdsPackagesWithoutCapability(t *testing.T) {
+	t.Parallel()
+
+	agent := agentmodel.NewAgent(uuid.New())
+	agent.Spec.PackagesAvailable = &agentmodel.AgentSpecPackage{Packages: []string{"collector"}}
+
+	msg := newTestBuilder().Build(t.Context(), agent)
+
+	assert.Nil(t, msg.GetPackagesAvailable())
+	assert.Equal(t, []agentmodel.OfferSkip{{
+		Offer:  agentmodel.OfferPackagesAvailable,
+		Reason: "agent lacks the AcceptsPackages capability",
+	}}, agent.SkippedOffers())
+}
+
 // TestServerToAgentBuilder_Build_PackageType pins that the advertised PackageType derives
 // from the package spec (case-insensitive), instead of the previously hardcoded TopLevel.
 func TestServerToAgentBuilder_Build_PackageType(t *testing.T) {
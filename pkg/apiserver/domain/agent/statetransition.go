@@ -0,0 +1,135 @@
+package agentmodel
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// AgentStateTransitionKind is a kind of meaningful change of an agent's state, such as a
+// connect or a failed remote config.
+type AgentStateTransitionKind string
+
+const (
+	// AgentStateTransitionConnected means the agent became connected.
+	AgentStateTransitionConnected AgentStateTransitionKind = "Connected"
+	// AgentStateTransitionDisconnected means the agent became disconnected.
+	AgentStateTransitionDisconnected AgentStateTransitionKind = "Disconnected"
+	// AgentStateTransitionHealthChanged means the agent's reported health flipped.
+	AgentStateTransitionHealthChanged AgentStateTransitionKind = "HealthChanged"
+	// AgentStateTransitionConfigApplied means the agent reported a remote config as applied.
+	AgentStateTransitionConfigApplied AgentStateTransitionKind = "ConfigApplied"
+	// AgentStateTransitionConfigFailed means the agent reported failing to apply a remote config.
+	AgentStateTransitionConfigFailed AgentStateTransitionKind = "ConfigFailed"
+	// AgentStateTransitionCommandIssued means a command was queued for the agent.
+	AgentStateTransitionCommandIssued AgentStateTransitionKind = "CommandIssued"
+	// AgentStateTransitionCommandAcked means the agent acted on a queued command.
+	AgentStateTransitionCommandAcked AgentStateTransitionKind = "CommandAcked"
+)
+
+// remoteConfigTransitionKinds maps the remote config statuses that end an attempt to apply
+// a remote config to the transition reporting it.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var remoteConfigTransitionKinds = map[RemoteConfigStatus]AgentStateTransitionKind{
+	RemoteConfigStatusApplied: AgentStateTransitionConfigApplied,
+	RemoteConfigStatusFailed:  AgentStateTransitionConfigFailed,
+}
+
+// AgentStateSnapshot holds the parts of an agent's state whose changes are state
+// transitions. Take one before changing the agent and compare it with one taken after.
+type AgentStateSnapshot struct {
+	Connected          bool
+	Healthy            bool
+	RemoteConfigStatus RemoteConfigStatus
+	RemoteConfigHash   []byte
+	PendingCommands    int
+}
+
+// AgentStateTransition is one change between two AgentStateSnapshots. From and To
+// describe the state before and after it.
+type AgentStateTransition struct {
+	Kind AgentStateTransitionKind
+	From string
+	To   string
+}
+
+// StateSnapshot returns the agent's current AgentStateSnapshot.
+func (a *Agent) StateSnapshot() AgentStateSnapshot {
+	return AgentStateSnapshot{
+		Connected:          a.Status.Connected,
+		Healthy:            a.Status.ComponentHealth.Healthy,
+		RemoteConfigStatus: a.Status.RemoteConfigStatus.Status,
+		RemoteConfigHash:   bytes.Clone(a.Status.RemoteConfigStatus.LastRemoteConfigHash),
+		PendingCommands:    a.PendingCommandCount(),
+	}
+}
+
+// TransitionsTo returns the transitions from s to next, in a fixed order.
+//
+// A remote config counts as applied or failed when its status or hash changed and the
+// status is now APPLIED or FAILED, so reporting the same result again is no transition.
+func (s AgentStateSnapshot) TransitionsTo(next AgentStateSnapshot) []AgentStateTransition {
+	var transitions []AgentStateTransition
+
+	if s.Connected != next.Connected {
+		kind := AgentStateTransitionDisconnected
+		if next.Connected {
+			kind = AgentStateTransitionConnected
+		}
+
+		transitions = append(transitions, AgentStateTransition{
+			Kind: kind,
+			From: connectedString(s.Connected),
+			To:   connectedString(next.Connected),
+		})
+	}
+
+	if s.Healthy != next.Healthy {
+		transitions = append(transitions, AgentStateTransition{
+			Kind: AgentStateTransitionHealthChanged,
+			From: healthString(s.Healthy),
+			To:   healthString(next.Healthy),
+		})
+	}
+
+	remoteConfigChanged := s.RemoteConfigStatus != next.RemoteConfigStatus ||
+		!bytes.Equal(s.RemoteConfigHash, next.RemoteConfigHash)
+	if kind, ok := remoteConfigTransitionKinds[next.RemoteConfigStatus]; ok && remoteConfigChanged {
+		transitions = append(transitions, AgentStateTransition{
+			Kind: kind,
+			From: s.RemoteConfigStatus.String(),
+			To:   next.RemoteConfigStatus.String(),
+		})
+	}
+
+	if s.PendingCommands != next.PendingCommands {
+		kind := AgentStateTransitionCommandAcked
+		if next.PendingCommands > s.PendingCommands {
+			kind = AgentStateTransitionCommandIssued
+		}
+
+		transitions = append(transitions, AgentStateTransition{
+			Kind: kind,
+			From: strconv.Itoa(s.PendingCommands),
+			To:   strconv.Itoa(next.PendingCommands),
+		})
+	}
+
+	return transitions
+}
+
+func connectedString(connected bool) string {
+	if connected {
+		return "connected"
+	}
+
+	return "disconnected"
+}
+
+func healthString(healthy bool) string {
+	if healthy {
+		return "healthy"
+	}
+
+	return "unhealthy"
+}
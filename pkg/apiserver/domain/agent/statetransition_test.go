@@ -0,0 +1,101 @@
+package agentmodel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgentStateSnapshot_TransitionsTo(t *testing.T) {
+	t.Parallel()
+
+	applied := agentmodel.AgentStateSnapshot{
+		Connected:          true,
+		Healthy:            true,
+		RemoteConfigStatus: agentmodel.RemoteConfigStatusApplied,
+		RemoteConfigHash:   []byte("v1"),
+		PendingCommands:    1,
+	}
+
+	with := func(change func(*agentmodel.AgentStateSnapshot)) agentmodel.AgentStateSnapshot {
+		next := applied
+		change(&next)
+
+		return next
+	}
+
+	cases := []struct {
+		name string
+		next agentmodel.AgentStateSnapshot
+		want []agentmodel.AgentStateTransition
+	}{
+		{
+			name: "unchanged",
+			next: with(func(*agentmodel.AgentStateSnapshot) {}),
+			want: nil,
+		},
+		{
+			name: "disconnected",
+			next: with(func(s *agentmodel.AgentStateSnapshot) { s.Connected = false }),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionDisconnected, From: "connected", To: "disconnected"},
+			},
+		},
+		{
+			name: "unhealthy",
+			next: with(func(s *agentmodel.AgentStateSnapshot) { s.Healthy = false }),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionHealthChanged, From: "healthy", To: "unhealthy"},
+			},
+		},
+		{
+			name: "another config applied",
+			next: with(func(s *agentmodel.AgentStateSnapshot) { s.RemoteConfigHash = []byte("v2") }),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionConfigApplied, From: "APPLIED", To: "APPLIED"},
+			},
+		},
+		{
+			name: "config failed",
+			next: with(func(s *agentmodel.AgentStateSnapshot) {
+				s.RemoteConfigStatus = agentmodel.RemoteConfigStatusFailed
+				s.RemoteConfigHash = []byte("v2")
+			}),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionConfigFailed, From: "APPLIED", To: "FAILED"},
+			},
+		},
+		{
+			name: "config applying is no transition",
+			next: with(func(s *agentmodel.AgentStateSnapshot) {
+				s.RemoteConfigStatus = agentmodel.RemoteConfigStatusApplying
+				s.RemoteConfigHash = []byte("v2")
+			}),
+			want: nil,
+		},
+		{
+			name: "command issued",
+			next: with(func(s *agentmodel.AgentStateSnapshot) { s.PendingCommands = 2 }),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionCommandIssued, From: "1", To: "2"},
+			},
+		},
+		{
+			name: "command acked",
+			next: with(func(s *agentmodel.AgentStateSnapshot) { s.PendingCommands = 0 }),
+			want: []agentmodel.AgentStateTransition{
+				{Kind: agentmodel.AgentStateTransitionCommandAcked, From: "1", To: "0"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, applied.TransitionsTo(tc.next))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package agentmodel
+
+import (
+	"time"
+)
+
+const (
+	// UptimeWindow is the trailing window AgentUptime.WindowUptimeRatio covers.
+	UptimeWindow = 24 * time.Hour
+
+	// maxRecentConnectionSessions bounds RecentSessions so a flapping agent cannot
+	// grow its document without limit. When exceeded the oldest sessions are
+	// dropped, which can only under-report the window uptime.
+	maxRecentConnectionSessions = 512
+)
+
+// AgentConnectionStats aggregates an agent's connection history. It is updated on
+// connect/disconnect transitions only, not on every heartbeat.
+type AgentConnectionStats struct {
+	// TrackedSince is when the first connection was recorded.
+	TrackedSince time.Time
+	// ConnectedSince is when the current session started; zero while disconnected.
+	ConnectedSince time.Time
+	// LastDisconnectedAt is when the last session ended.
+	LastDisconnectedAt time.Time
+	// TotalConnectedDuration sums the length of every closed session.
+	TotalConnectedDuration time.Duration
+	// DisconnectCount is the number of closed sessions.
+	DisconnectCount int64
+	// RecentSessions are the closed sessions that overlap the last UptimeWindow,
+	// oldest first.
+	RecentSessions []ConnectionSession
+}
+
+// ConnectionSession is one closed connected period of an agent.
+type ConnectionSession struct {
+	ConnectedAt    time.Time
+	DisconnectedAt time.Time
+}
+
+// AgentUptime is a point-in-time view of an agent's connection statistics.
+type AgentUptime struct {
+	// Connected reports whether the agent is connected at the time of the view.
+	Connected bool
+	// ConnectedSince is when the current session started; zero while disconnected.
+	ConnectedSince time.Time
+	// LastDisconnectedAt is when the last session ended.
+	LastDisconnectedAt time.Time
+	// TotalConnectedDuration is the connected time since tracking started,
+	// including the current session.
+	TotalConnectedDuration time.Duration
+	// DisconnectCount is the number of disconnects since tracking started.
+	DisconnectCount int64
+	// Window is the trailing period the window fields cover. It is shorter than
+	// UptimeWindow when tracking started less than UptimeWindow ago.
+	Window time.Duration
+	// WindowConnectedDuration is the connected time within Window.
+	WindowConnectedDuration time.Duration
+	// WindowUptimeRatio is WindowConnectedDuration / Window in [0, 1]; 0 when
+	// Window is empty.
+	WindowUptimeRatio float64
+}
+
+// IsConnected reports whether a session is open.
+func (s *AgentConnectionStats) IsConnected() bool {
+	return !s.ConnectedSince.IsZero()
+}
+
+// RecordConnected opens a session at now. It is a no-op while a session is open.
+func (s *AgentConnectionStats) RecordConnected(now time.Time) {
+	if s.IsConnected() {
+		return
+	}
+
+	if s.TrackedSince.IsZero() {
+		s.TrackedSince = now
+	}
+
+	s.ConnectedSince = now
+}
+
+// RecordDisconnected closes the open session at now. It is a no-op while
+// disconnected.
+func (s *AgentConnectionStats) RecordDisconnected(now time.Time) {
+	if !s.IsConnected() {
+		return
+	}
+
+	// Guard against clock skew between servers ending a session before it began.
+	if now.Before(s.ConnectedSince) {
+		now = s.ConnectedSince
+	}
+
+	s.TotalConnectedDuration += now.Sub(s.ConnectedSince)
+	s.DisconnectCount++
+	s.LastDisconnectedAt = now
+	s.RecentSessions = append(s.RecentSessions, ConnectionSession{
+		ConnectedAt:    s.ConnectedSince,
+		DisconnectedAt: now,
+	})
+	s.ConnectedSince = time.Time{}
+
+	s.pruneRecentSessions(now)
+}
+
+// UptimeAt returns the statistics as of now.
+func (s *AgentConnectionStats) UptimeAt(now time.Time) AgentUptime {
+	uptime := AgentUptime{
+		Connected:               s.IsConnected(),
+		ConnectedSince:          s.ConnectedSince,
+		LastDisconnectedAt:      s.LastDisconnectedAt,
+		TotalConnectedDuration:  s.TotalConnectedDuration,
+		DisconnectCount:         s.DisconnectCount,
+		Window:                  0,
+		WindowConnectedDuration: 0,
+		WindowUptimeRatio:       0,
+	}
+
+	if s.TrackedSince.IsZero() {
+		return uptime
+	}
+
+	windowStart := now.Add(-UptimeWindow)
+	if s.TrackedSince.After(windowStart) {
+		windowStart = s.TrackedSince
+	}
+
+	for _, session := range s.RecentSessions {
+		uptime.WindowConnectedDuration += overlap(session.ConnectedAt, session.DisconnectedAt, windowStart, now)
+	}
+
+	if s.IsConnected() {
+		uptime.TotalConnectedDuration += max(now.Sub(s.ConnectedSince), 0)
+		uptime.WindowConnectedDuration += overlap(s.ConnectedSince, now, windowStart, now)
+	}
+
+	uptime.Window = max(now.Sub(windowStart), 0)
+	if uptime.Window > 0 {
+		uptime.WindowUptimeRatio = min(float64(uptime.WindowConnectedDuration)/float64(uptime.Window), 1)
+	}
+
+	return uptime
+}
+
+// Clone returns a deep copy of the stats.
+func (s *AgentConnectionStats) Clone() AgentConnectionStats {
+	clone := *s
+	if s.RecentSessions != nil {
+		clone.RecentSessions = make([]ConnectionSession, len(s.RecentSessions))
+		copy(clone.RecentSessions, s.RecentSessions)
+	}
+
+	return clone
+}
+
+func (s *AgentConnectionStats) pruneRecentSessions(now time.Time) {
+	windowStart := now.Add(-UptimeWindow)
+
+	keepFrom := 0
+	for keepFrom < len(s.RecentSessions) && !s.RecentSessions[keepFrom].DisconnectedAt.After(windowStart) {
+		keepFrom++
+	}
+
+	keepFrom = max(keepFrom, len(s.RecentSessions)-maxRecentConnectionSessions)
+	if keepFrom > 0 {
+		s.RecentSessions = append([]ConnectionSession(nil), s.RecentSessions[keepFrom:]...)
+	}
+}
+
+// overlap returns the length of the intersection of [start, end) and
+// [windowStart, windowEnd).
+func overlap(start, end, windowStart, windowEnd time.Time) time.Duration {
+	if start.Before(windowStart) {
+		start = windowStart
+	}
+
+	if end.After(windowEnd) {
+		end = windowEnd
+	}
+
+	return max(end.Sub(start), 0)
+}
@@ -0,0 +1,102 @@
+package agentmodel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+)
+
+func TestAgentConnectionStats_UptimeAt(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	stats := agentmodel.AgentConnectionStats{}
+	stats.RecordConnected(start)
+	stats.RecordConnected(start.Add(time.Hour)) // already connected: no-op
+	stats.RecordDisconnected(start.Add(4 * time.Hour))
+	stats.RecordDisconnected(start.Add(5 * time.Hour)) // already disconnected: no-op
+	stats.RecordConnected(start.Add(6 * time.Hour))
+
+	t.Run("window shorter than a day covers tracking so far", func(t *testing.T) {
+		t.Parallel()
+
+		uptime := stats.UptimeAt(start.Add(8 * time.Hour))
+
+		assert.True(t, uptime.Connected)
+		assert.Equal(t, int64(1), uptime.DisconnectCount)
+		assert.Equal(t, 6*time.Hour, uptime.TotalConnectedDuration)
+		assert.Equal(t, 8*time.Hour, uptime.Window)
+		assert.Equal(t, 6*time.Hour, uptime.WindowConnectedDuration)
+		assert.InDelta(t, 0.75, uptime.WindowUptimeRatio, 1e-9)
+	})
+
+	t.Run("window slides past old sessions", func(t *testing.T) {
+		t.Parallel()
+
+		// The window is [02:00, 26:00): 2h of the first session and 20h of the current.
+		uptime := stats.UptimeAt(start.Add(26 * time.Hour))
+
+		assert.Equal(t, agentmodel.UptimeWindow, uptime.Window)
+		assert.Equal(t, 22*time.Hour, uptime.WindowConnectedDuration)
+		assert.Equal(t, 24*time.Hour, uptime.TotalConnectedDuration)
+	})
+
+	t.Run("never connected", func(t *testing.T) {
+		t.Parallel()
+
+		empty := agentmodel.AgentConnectionStats{}
+		uptime := empty.UptimeAt(start)
+
+		assert.False(t, uptime.Connected)
+		assert.Zero(t, uptime.Window)
+		assert.Zero(t, uptime.WindowUptimeRatio)
+	})
+}
+
+func TestAgentConnectionStats_PrunesSessionsOutsideWindow(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	stats := agentmodel.AgentConnectionStats{}
+	stats.RecordConnected(start)
+	stats.RecordDisconnected(start.Add(time.Hour))
+	stats.RecordConnected(start.Add(30 * time.Hour))
+	stats.RecordDisconnected(start.Add(31 * time.Hour))
+
+	require.Len(t, stats.RecentSessions, 1)
+	assert.Equal(t, start.Add(30*time.Hour), stats.RecentSessions[0].ConnectedAt)
+	// Lifetime aggregates keep counting what the window forgot.
+	assert.Equal(t, int64(2), stats.DisconnectCount)
+	assert.Equal(t, 2*time.Hour, stats.TotalConnectedDuration)
+}
+
+func TestAgent_UptimeAt_StaleAgentSessionEndsAtLastReport(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	agent := agentmodel.NewAgent(uuid.New())
+
+	// An HTTP-polling agent polls for an hour and then silently stops.
+	for at := time.Duration(0); at <= time.Hour; at += 30 * time.Second {
+		agent.UpdateLastCommunicationInfo(start.Add(at), nil)
+	}
+
+	uptime := agent.UptimeAt(start.Add(2*time.Hour), agentmodel.DefaultConnectionStaleness)
+	assert.False(t, uptime.Connected)
+	assert.Equal(t, time.Hour, uptime.WindowConnectedDuration)
+
+	// When it polls again the stale session is closed at its last report.
+	agent.UpdateLastCommunicationInfo(start.Add(3*time.Hour), nil)
+
+	stats := agent.Status.ConnectionStats
+	assert.Equal(t, int64(1), stats.DisconnectCount)
+	assert.Equal(t, start.Add(time.Hour), stats.LastDisconnectedAt)
+	assert.Equal(t, start.Add(3*time.Hour), stats.ConnectedSince)
+}
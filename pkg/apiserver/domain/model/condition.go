@@ -16,6 +16,20 @@ type Condition struct {
 	Message string
 }
 
+// DeletedBy returns the identifier of the user or system that soft deleted a resource,
+// as recorded in the Reason of its latest true Deleted condition. It returns "" when the
+// resource is not deleted.
+func DeletedBy(conditions []Condition) string {
+	for i := len(conditions) - 1; i >= 0; i-- {
+		condition := conditions[i]
+		if condition.Type == ConditionTypeDeleted && condition.Status == ConditionStatusTrue {
+			return condition.Reason
+		}
+	}
+
+	return ""
+}
+
 // ConditionType represents the type of a condition.
 type ConditionType string
 
@@ -34,6 +48,10 @@ const (
 	// is invalid or a referenced resource cannot be fetched, so failures surface through
 	// the API instead of only the server log.
 	ConditionTypeRemoteConfigApplied ConditionType = "RemoteConfigApplied"
+	// ConditionTypeContentVerified represents whether an agent package's artifact could be
+	// downloaded from its download URL and matches its content hash. It is set when the
+	// package is verified, with the download or mismatch error in Message on False.
+	ConditionTypeContentVerified ConditionType = "ContentVerified"
 )
 
 // ConditionStatus represents the status of a condition.
@@ -1,6 +1,9 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
 	// ErrResourceNotExist is an error that indicates that the resource does not exist.
@@ -17,4 +20,62 @@ var (
 	// avoid clobbering that change. The caller should re-read and retry. It maps to
 	// HTTP 409.
 	ErrConflict = errors.New("resource version conflict")
+	// ErrUnprocessable indicates a well-formed request whose content is semantically
+	// invalid, e.g. a value outside its allowed range; it maps to HTTP 422. Wrap a
+	// FieldError or FieldErrors with it to name the offending fields.
+	ErrUnprocessable = errors.New("unprocessable request")
+	// ErrQuotaExceeded indicates a create was rejected because the resource's quota is
+	// already used up; it maps to HTTP 403.
+	ErrQuotaExceeded = errors.New("resource quota exceeded")
 )
+
+// FieldError is an ErrInvalidArgument pinned to a single field of a submitted resource,
+// so the caller learns exactly which field to fix.
+type FieldError struct {
+	// Field is the JSON path of the offending field within the resource,
+	// e.g. "spec.agentConfig.agentRemoteConfigs[0].agentRemoteConfigRef".
+	Field string
+	// Value is the rejected value.
+	Value any
+	// Reason explains why the value was rejected.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// Unwrap makes a FieldError match ErrInvalidArgument.
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// FieldErrors aggregates every FieldError found while validating a submitted resource, so
+// the caller can fix all of them at once instead of one per round trip. Like FieldError it
+// matches ErrInvalidArgument.
+type FieldErrors []*FieldError
+
+// Error implements the error interface.
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap makes FieldErrors match ErrInvalidArgument.
+func (e FieldErrors) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// Err returns e as an error, or nil when it holds no field errors.
+func (e FieldErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
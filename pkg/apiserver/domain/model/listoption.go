@@ -25,6 +25,16 @@ type ListOptions struct {
 	// (an AND of equality conditions). It is combined with IdentifyingAttributes
 	// via AND, and is a no-op for resources that have no non-identifying attributes.
 	NonIdentifyingAttributes map[string]string
+
+	// ConnectionType, when non-empty, restricts an agent listing to agents whose last
+	// connection has this type, named as agentmodel.ConnectionType.String does ("HTTP",
+	// "WebSocket" or "Unknown"). It is a no-op for resources that have no connection.
+	ConnectionType string
+
+	// Capabilities, when non-zero, restricts an agent listing to agents whose reported
+	// OpAMP capability bitmask has every bit of it set. It is a no-op for resources that
+	// have no capabilities.
+	Capabilities uint64
 }
 
 // GetOptions is a struct that holds options for getting a single resource.
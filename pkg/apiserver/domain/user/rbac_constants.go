@@ -25,10 +25,13 @@ const (
 
 // Global resource types (not namespace-scoped).
 const (
-	ResourceServer     = "server"
-	ResourceUser       = "user"
-	ResourceRole       = "role"
-	ResourcePermission = "permission"
+	ResourceServer          = "server"
+	ResourceUser            = "user"
+	ResourceRole            = "role"
+	ResourcePermission      = "permission"
+	ResourceAgentRevocation = "agentrevocation"
+	ResourceQuota           = "quota"
+	ResourceBackup          = "backup"
 )
 
 // DefaultNamespace is the namespace used for built-in default role assignments.
@@ -61,6 +64,19 @@ func NamespaceScopedResources() []string {
 	}
 }
 
+// GlobalResources returns all global (not namespace-scoped) resources controlled by RBAC.
+func GlobalResources() []string {
+	return []string{
+		ResourceServer,
+		ResourceUser,
+		ResourceRole,
+		ResourcePermission,
+		ResourceAgentRevocation,
+		ResourceQuota,
+		ResourceBackup,
+	}
+}
+
 // AllActions returns all RBAC actions.
 func AllActions() []string {
 	return []string{ActionGet, ActionList, ActionCreate, ActionUpdate, ActionDelete}
@@ -1,10 +1,14 @@
 package ginutil
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -100,6 +104,31 @@ func HandleDomainError(ctx *gin.Context, err error, fallbackMessage string) {
 		return
 	}
 
+	if errors.Is(err, model.ErrQuotaExceeded) {
+		ForbiddenError(ctx, err, "The resource quota is used up; delete unused resources or raise the quota.")
+
+		return
+	}
+
+	if errors.Is(err, model.ErrUnprocessable) {
+		UnprocessableEntityError(ctx, err)
+
+		return
+	}
+
+	if details, ok := fieldErrorDetails(err); ok {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorModel{
+			Type:     baseURL,
+			Title:    "Bad Request",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: ctx.Request.URL.String(),
+			Errors:   details,
+		})
+
+		return
+	}
+
 	if errors.Is(err, model.ErrInvalidArgument) {
 		ctx.JSON(http.StatusBadRequest, &api.ErrorModel{
 			Type:     baseURL,
@@ -123,6 +152,31 @@ func HandleDomainError(ctx *gin.Context, err error, fallbackMessage string) {
 	InternalServerError(ctx, err, fallbackMessage)
 }
 
+// fieldErrorDetails returns one error detail per model.FieldError in err, located in the
+// request body. ok is false when err holds no field errors.
+func fieldErrorDetails(err error) ([]*api.ErrorDetail, bool) {
+	var fieldErrs model.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		var fieldErr *model.FieldError
+		if !errors.As(err, &fieldErr) {
+			return nil, false
+		}
+
+		fieldErrs = model.FieldErrors{fieldErr}
+	}
+
+	details := make([]*api.ErrorDetail, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		details = append(details, &api.ErrorDetail{
+			Message:  fieldErr.Reason,
+			Location: "body." + fieldErr.Field,
+			Value:    fieldErr.Value,
+		})
+	}
+
+	return details, true
+}
+
 // InvalidQueryParamError creates an error response for invalid query parameters.
 func InvalidQueryParamError(ctx *gin.Context, paramName, value string, message string) {
 	ErrorResponse(ctx, &ErrorInfo{
@@ -157,7 +211,15 @@ func InvalidRequestBodyError(ctx *gin.Context, err error) {
 }
 
 // InternalServerError creates an error response for internal server errors.
+// An error caused by the request deadline is reported as 504 instead, since every
+// unexpected error, including a cancelled persistence call, ends up here.
 func InternalServerError(ctx *gin.Context, err error, detail string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		GatewayTimeoutError(ctx, err)
+
+		return
+	}
+
 	baseURL := GetErrorTypeURI(ctx)
 
 	ctx.JSON(http.StatusInternalServerError, &api.ErrorModel{
@@ -176,6 +238,49 @@ func InternalServerError(ctx *gin.Context, err error, detail string) {
 	})
 }
 
+// GatewayTimeoutError creates a standardized 504 error response for a request that ran
+// past its deadline.
+func GatewayTimeoutError(ctx *gin.Context, err error) {
+	baseURL := GetErrorTypeURI(ctx)
+
+	ctx.JSON(http.StatusGatewayTimeout, &api.ErrorModel{
+		Type:     baseURL,
+		Title:    "Gateway Timeout",
+		Status:   http.StatusGatewayTimeout,
+		Detail:   "The request did not complete before its deadline.",
+		Instance: ctx.Request.URL.String(),
+		Errors: []*api.ErrorDetail{
+			{
+				Message:  err.Error(),
+				Location: "server",
+				Value:    nil,
+			},
+		},
+	})
+}
+
+// NotAcceptableError creates a standardized 406 error response for a request whose Accept
+// header admits none of SupportedMediaTypes.
+func NotAcceptableError(ctx *gin.Context, accept string) {
+	baseURL := GetErrorTypeURI(ctx)
+
+	ctx.JSON(http.StatusNotAcceptable, &api.ErrorModel{
+		Type:   baseURL,
+		Title:  "Not Acceptable",
+		Status: http.StatusNotAcceptable,
+		Detail: "None of the accepted media types can be served. Supported media types: " +
+			strings.Join(SupportedMediaTypes, ", ") + ".",
+		Instance: ctx.Request.URL.String(),
+		Errors: []*api.ErrorDetail{
+			{
+				Message:  "unsupported media type " + accept,
+				Location: "header.Accept",
+				Value:    SupportedMediaTypes,
+			},
+		},
+	})
+}
+
 // ConflictError creates a standardized 409 Conflict error response.
 func ConflictError(ctx *gin.Context, err error, detail string) {
 	baseURL := GetErrorTypeURI(ctx)
@@ -196,6 +301,76 @@ func ConflictError(ctx *gin.Context, err error, detail string) {
 	})
 }
 
+// UnprocessableEntityError creates a standardized 422 Unprocessable Entity error response
+// for a well-formed request whose content is semantically invalid. Each field error err
+// holds is reported with its location in the body.
+func UnprocessableEntityError(ctx *gin.Context, err error) {
+	baseURL := GetErrorTypeURI(ctx)
+
+	details, ok := fieldErrorDetails(err)
+	if !ok {
+		details = []*api.ErrorDetail{
+			{
+				Message:  err.Error(),
+				Location: "body",
+				Value:    nil,
+			},
+		}
+	}
+
+	ctx.JSON(http.StatusUnprocessableEntity, &api.ErrorModel{
+		Type:     baseURL,
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   err.Error(),
+		Instance: ctx.Request.URL.String(),
+		Errors:   details,
+	})
+}
+
+// ForbiddenError creates a standardized 403 Forbidden error response.
+func ForbiddenError(ctx *gin.Context, err error, detail string) {
+	baseURL := GetErrorTypeURI(ctx)
+
+	ctx.JSON(http.StatusForbidden, &api.ErrorModel{
+		Type:     baseURL,
+		Title:    "Forbidden",
+		Status:   http.StatusForbidden,
+		Detail:   detail,
+		Instance: ctx.Request.URL.String(),
+		Errors: []*api.ErrorDetail{
+			{
+				Message:  err.Error(),
+				Location: "server",
+				Value:    nil,
+			},
+		},
+	})
+}
+
+// TooManyRequestsError creates a standardized 429 Too Many Requests error response, with a
+// Retry-After header telling the client how long to wait, rounded up to whole seconds.
+func TooManyRequestsError(ctx *gin.Context, err error, detail string, retryAfter time.Duration) {
+	baseURL := GetErrorTypeURI(ctx)
+
+	ctx.Header("Retry-After", strconv.FormatInt(int64((retryAfter+time.Second-1)/time.Second), 10))
+
+	ctx.JSON(http.StatusTooManyRequests, &api.ErrorModel{
+		Type:     baseURL,
+		Title:    "Too Many Requests",
+		Status:   http.StatusTooManyRequests,
+		Detail:   detail,
+		Instance: ctx.Request.URL.String(),
+		Errors: []*api.ErrorDetail{
+			{
+				Message:  err.Error(),
+				Location: "server",
+				Value:    nil,
+			},
+		},
+	})
+}
+
 // ResourceNotFoundError creates a standardized 404 error response.
 func ResourceNotFoundError(ctx *gin.Context, resourceType, identifier string) {
 	baseURL := GetErrorTypeURI(ctx)
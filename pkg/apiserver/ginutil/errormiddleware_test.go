@@ -1,14 +1,18 @@
 package ginutil_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/minuk-dev/opampcommander/api"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
 )
@@ -76,6 +80,65 @@ func TestHandleDomainError_ResourceNotExist(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestHandleDomainError_QuotaExceeded(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/agentgroups", nil)
+
+	ginutil.HandleDomainError(ctx, fmt.Errorf("%w: AgentGroup quota of 2 is used up", model.ErrQuotaExceeded),
+		"Failed to create agent group")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var body api.ErrorModel
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Forbidden", body.Title)
+	assert.Equal(t, http.StatusForbidden, body.Status)
+	require.Len(t, body.Errors, 1)
+	assert.Contains(t, body.Errors[0].Message, "AgentGroup quota of 2 is used up")
+}
+
+func TestHandleDomainError_Unprocessable(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/agentgroups", nil)
+
+	ginutil.HandleDomainError(ctx, fmt.Errorf("%w: %w", model.ErrUnprocessable, model.FieldErrors{
+		{Field: "spec.priority", Value: -1, Reason: "must not be negative"},
+		{Field: "spec.rollout.count", Value: -2, Reason: "must not be negative"},
+	}), "Failed to create agent group")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body api.ErrorModel
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Unprocessable Entity", body.Title)
+	assert.Equal(t, http.StatusUnprocessableEntity, body.Status)
+	require.Len(t, body.Errors, 2)
+	assert.Equal(t, "body.spec.priority", body.Errors[0].Location)
+	assert.Equal(t, "body.spec.rollout.count", body.Errors[1].Location)
+}
+
+func TestHandleDomainError_FieldErrorIsBadRequest(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/agentgroups", nil)
+
+	ginutil.HandleDomainError(ctx, &model.FieldError{Field: "spec.selector", Value: "", Reason: "must not be empty"},
+		"Failed to create agent group")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestHandleDomainError_InternalServerError(t *testing.T) {
 	t.Parallel()
 	gin.SetMode(gin.TestMode)
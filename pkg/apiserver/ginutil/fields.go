@@ -0,0 +1,188 @@
+package ginutil
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// knownFieldPathsCache memoizes the selectable field paths per response type.
+//
+//nolint:gochecknoglobals // cache keyed by type; computing it is pure reflection
+var knownFieldPathsCache sync.Map
+
+// typeMetaFields are the top-level fields every projection keeps.
+//
+//nolint:gochecknoglobals // read-only list of JSON keys
+var typeMetaFields = []string{"kind", "apiVersion"}
+
+// FieldSelector projects a response down to a set of dotted JSON field paths,
+// e.g. "metadata,status.componentHealth".
+type FieldSelector struct {
+	paths [][]string
+}
+
+// ParseFieldSelector parses the comma-separated field paths in the given query parameter
+// and validates each against the JSON shape of model.
+// It returns nil when the parameter is absent, meaning the full object should be returned.
+// Returns error if validation fails - caller must handle error response.
+func ParseFieldSelector(c *gin.Context, paramName string, model any) (*FieldSelector, error) {
+	value := c.Query(paramName)
+	if value == "" {
+		return nil, nil //nolint:nilnil // no selector means no projection
+	}
+
+	known := knownFieldPaths(reflect.TypeOf(model))
+
+	selector := &FieldSelector{paths: nil}
+
+	for raw := range strings.SplitSeq(value, ",") {
+		path := strings.TrimSpace(raw)
+		if path == "" {
+			return nil, ErrInvalidFormat
+		}
+
+		if _, ok := known[path]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidValue, path)
+		}
+
+		selector.paths = append(selector.paths, strings.Split(path, "."))
+	}
+
+	return selector, nil
+}
+
+// Project returns the JSON representation of value restricted to the selected paths.
+// Selected fields that are omitted from the full representation are omitted here too.
+// The top-level kind and apiVersion are always kept, so clients can still tell what a
+// projected object is.
+func (s *FieldSelector) Project(value any) (map[string]any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+
+	var full map[string]any
+
+	// Numbers are kept as their JSON literal, since a float64 cannot hold every uint64,
+	// e.g. a large SequenceNum.
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	err = decoder.Decode(&full)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal value: %w", err)
+	}
+
+	projected := make(map[string]any)
+
+	for _, key := range typeMetaFields {
+		copyFieldPath(full, projected, []string{key})
+	}
+
+	for _, path := range s.paths {
+		copyFieldPath(full, projected, path)
+	}
+
+	return projected, nil
+}
+
+func copyFieldPath(src, dst map[string]any, path []string) {
+	for i, key := range path {
+		value, ok := src[key]
+		if !ok {
+			return
+		}
+
+		if i == len(path)-1 {
+			dst[key] = value
+
+			return
+		}
+
+		child, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		next, ok := dst[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			dst[key] = next
+		}
+
+		src, dst = child, next
+	}
+}
+
+// knownFieldPaths lists every dotted JSON path that can be selected on t.
+// Structs are descended into; maps, slices and types with their own JSON encoding
+// (timestamps, UUIDs, ...) can only be selected as a whole.
+func knownFieldPaths(t reflect.Type) map[string]struct{} {
+	if cached, ok := knownFieldPathsCache.Load(t); ok {
+		paths, _ := cached.(map[string]struct{})
+
+		return paths
+	}
+
+	paths := make(map[string]struct{})
+	collectFieldPaths(t, "", paths, map[reflect.Type]bool{})
+	knownFieldPathsCache.Store(t, paths)
+
+	return paths
+}
+
+func collectFieldPaths(t reflect.Type, prefix string, paths map[string]struct{}, visiting map[reflect.Type]bool) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct || isJSONLeaf(t) || visiting[t] {
+		return
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous {
+			// Untagged embedded structs are flattened by encoding/json.
+			collectFieldPaths(field.Type, prefix, paths, visiting)
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		path := prefix + name
+		paths[path] = struct{}{}
+		collectFieldPaths(field.Type, path+".", paths, visiting)
+	}
+}
+
+func isJSONLeaf(t reflect.Type) bool {
+	jsonMarshaler := reflect.TypeFor[json.Marshaler]()
+	textMarshaler := reflect.TypeFor[encoding.TextMarshaler]()
+
+	return t.Implements(jsonMarshaler) || t.Implements(textMarshaler) ||
+		reflect.PointerTo(t).Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(textMarshaler)
+}
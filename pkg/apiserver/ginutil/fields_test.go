@@ -0,0 +1,154 @@
+package ginutil_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+type fieldsTestInner struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Note      string    `json:"note,omitempty"`
+}
+
+type fieldsTestModel struct {
+	Meta   fieldsTestInner   `json:"meta"`
+	Labels map[string]string `json:"labels"`
+	Hidden string            `json:"-"`
+}
+
+func newFieldsContext(t *testing.T, rawQuery string) *gin.Context {
+	t.Helper()
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/?"+rawQuery, nil)
+	require.NoError(t, err)
+
+	ctx.Request = req
+
+	return ctx
+}
+
+func TestParseFieldSelector(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		query     string
+		errorType error
+	}{
+		{name: "nested struct field", query: "fields=meta.name"},
+		{name: "whole struct and leaf", query: "fields=meta,labels"},
+		{name: "time is a leaf", query: "fields=meta.updatedAt.wall", errorType: ginutil.ErrInvalidValue},
+		{name: "map is a leaf", query: "fields=labels.team", errorType: ginutil.ErrInvalidValue},
+		{name: "ignored field", query: "fields=Hidden", errorType: ginutil.ErrInvalidValue},
+		{name: "empty path", query: "fields=meta,,labels", errorType: ginutil.ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := ginutil.ParseFieldSelector(newFieldsContext(t, tt.query), "fields", fieldsTestModel{})
+			if tt.errorType != nil {
+				require.ErrorIs(t, err, tt.errorType)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, selector)
+		})
+	}
+
+	t.Run("absent parameter selects nothing", func(t *testing.T) {
+		t.Parallel()
+
+		selector, err := ginutil.ParseFieldSelector(newFieldsContext(t, ""), "fields", fieldsTestModel{})
+		require.NoError(t, err)
+		assert.Nil(t, selector)
+	})
+}
+
+func TestFieldSelector_Project(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	selector, err := ginutil.ParseFieldSelector(
+		newFieldsContext(t, "fields=meta.name,meta.note,labels"), "fields", fieldsTestModel{})
+	require.NoError(t, err)
+
+	projected, err := selector.Project(fieldsTestModel{
+		Meta:   fieldsTestInner{Name: "agent", UpdatedAt: time.Now(), Note: ""},
+		Labels: map[string]string{"team": "infra"},
+		Hidden: "secret",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"meta":   map[string]any{"name": "agent"},
+		"labels": map[string]any{"team": "infra"},
+	}, projected)
+}
+
+func TestFieldSelector_Project_KeepsTypeMeta(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	type typedModel struct {
+		Kind       string            `json:"kind"`
+		APIVersion string            `json:"apiVersion"`
+		Labels     map[string]string `json:"labels"`
+	}
+
+	selector, err := ginutil.ParseFieldSelector(newFieldsContext(t, "fields=labels"), "fields", typedModel{})
+	require.NoError(t, err)
+
+	projected, err := selector.Project(typedModel{
+		Kind:       "Agent",
+		APIVersion: "v1",
+		Labels:     map[string]string{"team": "infra"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"kind":       "Agent",
+		"apiVersion": "v1",
+		"labels":     map[string]any{"team": "infra"},
+	}, projected)
+}
+
+func TestFieldSelector_Project_KeepsLargeIntegersExact(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	type statusModel struct {
+		SequenceNum uint64 `json:"sequenceNum"`
+	}
+
+	type agentModel struct {
+		Status statusModel `json:"status"`
+	}
+
+	selector, err := ginutil.ParseFieldSelector(
+		newFieldsContext(t, "fields=status.sequenceNum"), "fields", agentModel{})
+	require.NoError(t, err)
+
+	projected, err := selector.Project(agentModel{Status: statusModel{SequenceNum: math.MaxUint64}})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(projected)
+	require.NoError(t, err)
+	assert.Equal(t, `{"status":{"sequenceNum":18446744073709551615}}`, string(body))
+}
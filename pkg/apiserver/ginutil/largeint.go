@@ -0,0 +1,170 @@
+package ginutil
+
+import (
+	"bytes"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// LargeIntsAsStringsQueryParam is the query parameter that, set to true, asks for large
+	// integers to be serialized as strings.
+	LargeIntsAsStringsQueryParam = "largeIntsAsStrings"
+
+	// LargeIntsAsStringsProfile is the Accept profile that asks for large integers to be
+	// serialized as strings, e.g. `application/json; profile="large-ints-as-strings"`.
+	LargeIntsAsStringsProfile = "large-ints-as-strings"
+
+	// maxSafeInteger is the largest integer a JavaScript number represents exactly (2^53-1).
+	maxSafeInteger = 1<<53 - 1
+)
+
+// NewLargeIntsAsStringsMiddleware serializes every integer in a JSON response that a
+// JavaScript number cannot represent exactly, such as a large SequenceNum, as a string
+// holding its digits, when the client asks for it with LargeIntsAsStringsQueryParam or
+// LargeIntsAsStringsProfile. Integers within ±(2^53-1) stay numbers, so a client only has
+// to accept a string where it would otherwise lose precision.
+//
+// Each write of a JSON or NDJSON response is rewritten on its own, which holds for gin's
+// JSON rendering and NDJSONWriter as both write a whole value at once.
+func NewLargeIntsAsStringsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled, err := ParseBool(c, LargeIntsAsStringsQueryParam, false)
+		if err != nil {
+			HandleValidationError(c, LargeIntsAsStringsQueryParam, c.Query(LargeIntsAsStringsQueryParam), err, false)
+			c.Abort()
+
+			return
+		}
+
+		if !enabled && !acceptsLargeIntsAsStringsProfile(c.GetHeader("Accept")) {
+			c.Next()
+
+			return
+		}
+
+		c.Writer = &largeIntsAsStringsWriter{ResponseWriter: c.Writer}
+
+		c.Next()
+	}
+}
+
+func acceptsLargeIntsAsStringsProfile(accept string) bool {
+	for accepted := range strings.SplitSeq(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		for profile := range strings.FieldsSeq(params["profile"]) {
+			if profile == LargeIntsAsStringsProfile {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// largeIntsAsStringsWriter quotes the unsafe integers of each JSON write.
+type largeIntsAsStringsWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *largeIntsAsStringsWriter) Write(data []byte) (int, error) {
+	if !w.isJSON() {
+		return w.ResponseWriter.Write(data) //nolint:wrapcheck // transparent writer
+	}
+
+	_, err := w.ResponseWriter.Write(QuoteLargeInts(data))
+	if err != nil {
+		return 0, err //nolint:wrapcheck // transparent writer
+	}
+
+	return len(data), nil
+}
+
+func (w *largeIntsAsStringsWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *largeIntsAsStringsWriter) isJSON() bool {
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || mediaType == "application/problem+json" ||
+		mediaType == NDJSONContentType
+}
+
+// QuoteLargeInts returns the JSON in data with every integer outside ±(2^53-1) turned into
+// a string holding its digits. Strings, fractions and smaller integers are left as they are.
+func QuoteLargeInts(data []byte) []byte {
+	var out bytes.Buffer
+
+	out.Grow(len(data))
+
+	inString, escaped := false, false
+
+	for i := 0; i < len(data); i++ {
+		char := data[i]
+
+		switch {
+		case inString:
+			out.WriteByte(char)
+
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == '"':
+				inString = false
+			}
+		case char == '"':
+			inString = true
+
+			out.WriteByte(char)
+		case char == '-' || (char >= '0' && char <= '9'):
+			end := i + 1
+			for end < len(data) && strings.IndexByte("0123456789.eE+-", data[end]) >= 0 {
+				end++
+			}
+
+			number := data[i:end]
+			if isUnsafeInteger(number) {
+				out.WriteByte('"')
+				out.Write(number)
+				out.WriteByte('"')
+			} else {
+				out.Write(number)
+			}
+
+			i = end - 1
+		default:
+			out.WriteByte(char)
+		}
+	}
+
+	return out.Bytes()
+}
+
+func isUnsafeInteger(number []byte) bool {
+	if bytes.ContainsAny(number, ".eE") {
+		return false
+	}
+
+	digits := strings.TrimPrefix(string(number), "-")
+
+	value, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		// Too large even for a uint64, so certainly beyond a JavaScript number.
+		return digits != ""
+	}
+
+	return value > maxSafeInteger
+}
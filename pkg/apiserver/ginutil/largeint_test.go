@@ -0,0 +1,103 @@
+package ginutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+func TestQuoteLargeInts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "safe integer", in: `{"n":9007199254740991}`, want: `{"n":9007199254740991}`},
+		{name: "unsafe integer", in: `{"n":9007199254740993}`, want: `{"n":"9007199254740993"}`},
+		{name: "negative unsafe integer", in: `{"n":-9007199254740993}`, want: `{"n":"-9007199254740993"}`},
+		{name: "max uint64", in: `[18446744073709551615]`, want: `["18446744073709551615"]`},
+		{name: "beyond uint64", in: `[184467440737095516150]`, want: `["184467440737095516150"]`},
+		{name: "fraction", in: `{"n":9007199254740993.5}`, want: `{"n":9007199254740993.5}`},
+		{name: "exponent", in: `{"n":1e300}`, want: `{"n":1e300}`},
+		{
+			name: "digits inside strings",
+			in:   `{"s":"9007199254740993","e":"a\"9007199254740993"}`,
+			want: `{"s":"9007199254740993","e":"a\"9007199254740993"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.JSONEq(t, test.want, string(ginutil.QuoteLargeInts([]byte(test.in))))
+		})
+	}
+}
+
+func TestLargeIntsAsStringsMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ginutil.NewLargeIntsAsStringsMiddleware())
+	router.GET("/json", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"n": uint64(1<<63 + 1)}) })
+	router.GET("/text", func(c *gin.Context) { c.String(http.StatusOK, "9223372036854775809") })
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		status int
+		body   string
+	}{
+		{name: "not requested", path: "/json", status: http.StatusOK, body: `{"n":9223372036854775809}`},
+		{
+			name: "query param", path: "/json?largeIntsAsStrings=true",
+			status: http.StatusOK, body: `{"n":"9223372036854775809"}`,
+		},
+		{
+			name: "accept profile", path: "/json", accept: `application/json;profile="large-ints-as-strings"`,
+			status: http.StatusOK, body: `{"n":"9223372036854775809"}`,
+		},
+		{
+			name: "other profile", path: "/json", accept: `application/json;profile="other"`,
+			status: http.StatusOK, body: `{"n":9223372036854775809}`,
+		},
+		{
+			name: "non-JSON response", path: "/text?largeIntsAsStrings=true",
+			status: http.StatusOK, body: "9223372036854775809",
+		},
+		{name: "invalid query param", path: "/json?largeIntsAsStrings=maybe", status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, test.path, nil)
+			require.NoError(t, err)
+
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, test.status, recorder.Code)
+
+			if test.body != "" {
+				assert.Equal(t, test.body, recorder.Body.String())
+			}
+		})
+	}
+}
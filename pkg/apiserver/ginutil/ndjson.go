@@ -0,0 +1,73 @@
+package ginutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// NDJSONContentType is the media type of a newline-delimited JSON stream.
+	NDJSONContentType = "application/x-ndjson"
+
+	// StreamFormatNDJSON is the value of the "stream" query parameter that selects NDJSON.
+	StreamFormatNDJSON = "ndjson"
+)
+
+// WantsNDJSON reports whether the client asked for a newline-delimited JSON stream,
+// either with "?stream=ndjson" or an Accept header listing application/x-ndjson.
+// Returns error if the stream query parameter names an unsupported format.
+func WantsNDJSON(c *gin.Context) (bool, error) {
+	switch stream := c.Query("stream"); stream {
+	case StreamFormatNDJSON:
+		return true, nil
+	case "":
+	default:
+		return false, fmt.Errorf("%w: unsupported stream format %q", ErrInvalidValue, stream)
+	}
+
+	for accepted := range strings.SplitSeq(c.GetHeader("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), NDJSONContentType) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// NDJSONWriter writes one JSON value per line to the response, flushing after each so
+// the client receives items as they are produced rather than when the handler returns.
+type NDJSONWriter struct {
+	writer  gin.ResponseWriter
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter commits a 200 response with the NDJSON content type.
+// Once it is created the status can no longer change, so handlers should only create it
+// after the first read from their source succeeded.
+func NewNDJSONWriter(c *gin.Context) *NDJSONWriter {
+	c.Header("Content-Type", NDJSONContentType)
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+
+	return &NDJSONWriter{
+		writer:  c.Writer,
+		encoder: json.NewEncoder(c.Writer),
+	}
+}
+
+// Write encodes value as a single line and flushes it to the client.
+func (w *NDJSONWriter) Write(value any) error {
+	err := w.encoder.Encode(value)
+	if err != nil {
+		return fmt.Errorf("encode ndjson line: %w", err)
+	}
+
+	w.writer.Flush()
+
+	return nil
+}
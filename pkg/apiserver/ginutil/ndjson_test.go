@@ -0,0 +1,51 @@
+package ginutil_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+func TestWantsNDJSON(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		query    string
+		accept   string
+		expected bool
+		err      error
+	}{
+		{name: "plain request", query: "", accept: "application/json", expected: false, err: nil},
+		{name: "stream query", query: "stream=ndjson", accept: "", expected: true, err: nil},
+		{
+			name: "accept header among others", query: "",
+			accept: "application/json, application/x-ndjson;q=0.9", expected: true, err: nil,
+		},
+		{name: "unsupported stream format", query: "stream=csv", accept: "", expected: false, err: ginutil.ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := newFieldsContext(t, tt.query)
+			ctx.Request.Header.Set("Accept", tt.accept)
+
+			wants, err := ginutil.WantsNDJSON(ctx)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, wants)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package ginutil
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventStreamContentType is the media type of a server-sent event stream.
+const EventStreamContentType = "text/event-stream"
+
+// SupportedMediaTypes lists the media types the API can respond with.
+//
+//nolint:gochecknoglobals // read-only list shared by the middleware and its error response.
+var SupportedMediaTypes = []string{
+	"application/json",
+	"application/problem+json",
+	NDJSONContentType,
+}
+
+// NewContentNegotiationMiddleware rejects a request with 406 Not Acceptable when its Accept
+// header admits none of SupportedMediaTypes, instead of answering with JSON the client
+// did not ask for. A missing Accept header accepts anything.
+// Routes in eventStreamRoutes, keyed by route pattern, also accept EventStreamContentType,
+// as they stream server-sent events. Requests whose path starts with one of exemptPrefixes
+// are passed through, for routes that serve other media types, such as the OpAMP endpoint
+// or the swagger UI.
+func NewContentNegotiationMiddleware(eventStreamRoutes []string, exemptPrefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range exemptPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+
+				return
+			}
+		}
+
+		mediaTypes := SupportedMediaTypes
+		if slices.Contains(eventStreamRoutes, c.FullPath()) {
+			mediaTypes = append(slices.Clone(SupportedMediaTypes), EventStreamContentType)
+		}
+
+		if c.GetHeader("Accept") == "" || c.NegotiateFormat(mediaTypes...) != "" {
+			c.Next()
+
+			return
+		}
+
+		NotAcceptableError(c, c.GetHeader("Accept"))
+		c.Abort()
+	}
+}
@@ -0,0 +1,72 @@
+package ginutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ginutil.NewContentNegotiationMiddleware([]string{"/events"}, "/exempt"))
+	router.GET("/resource", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	router.GET("/exempt", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/events", func(c *gin.Context) { c.SSEvent("event", "data") })
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		status int
+	}{
+		{name: "no accept header", path: "/resource", accept: "", status: http.StatusOK},
+		{name: "json", path: "/resource", accept: "application/json", status: http.StatusOK},
+		{name: "any", path: "/resource", accept: "*/*", status: http.StatusOK},
+		{name: "application wildcard", path: "/resource", accept: "application/*", status: http.StatusOK},
+		{name: "ndjson", path: "/resource", accept: "application/x-ndjson", status: http.StatusOK},
+		{
+			name: "one supported among others", path: "/resource",
+			accept: "text/html, application/json;q=0.9", status: http.StatusOK,
+		},
+		{name: "xml", path: "/resource", accept: "application/xml", status: http.StatusNotAcceptable},
+		{name: "text", path: "/resource", accept: "text/*", status: http.StatusNotAcceptable},
+		{name: "event stream", path: "/events", accept: ginutil.EventStreamContentType, status: http.StatusOK},
+		{name: "event stream elsewhere", path: "/resource", accept: ginutil.EventStreamContentType, status: http.StatusNotAcceptable},
+		{name: "exempt path", path: "/exempt", accept: "text/html", status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, test.path, nil)
+			require.NoError(t, err)
+
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.status, recorder.Code)
+
+			if test.status == http.StatusNotAcceptable {
+				body := recorder.Body.String()
+				assert.Equal(t, "Not Acceptable", gjson.Get(body, "title").String())
+				assert.Equal(t, "header.Accept", gjson.Get(body, "errors.0.location").String())
+				assert.Equal(t, ginutil.SupportedMediaTypes[0], gjson.Get(body, "errors.0.value.0").String())
+			}
+		})
+	}
+}
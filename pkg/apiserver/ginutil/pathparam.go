@@ -0,0 +1,66 @@
+package ginutil
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// hyphenatedUUIDLength is the length of the canonical form, e.g. 550e8400-e29b-41d4-a716-446655440000.
+	hyphenatedUUIDLength = 36
+	// hexUUIDLength is the length of the 16-byte ID written as bare hex digits.
+	hexUUIDLength = 32
+
+	uuidPathParamKeyPrefix = "ginutil.uuidPathParam."
+)
+
+// WithUUIDPathParam wraps a handler so the named path parameter is validated before the
+// handler runs. A value that is neither a hyphenated UUID nor 32 hex digits is answered
+// with the standard 400 problem at path.<paramName>, and the handler is not called.
+// The handler reads the parsed value with UUIDPathParam.
+func WithUUIDPathParam(paramName string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.Param(paramName)
+
+		parsed, err := parseCanonicalUUID(value)
+		if err != nil {
+			HandleValidationError(c, paramName, value, err, true)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(uuidPathParamKeyPrefix+paramName, parsed)
+		handler(c)
+	}
+}
+
+// UUIDPathParam returns the path parameter WithUUIDPathParam validated. It panics when
+// the route was registered without WithUUIDPathParam for that parameter.
+func UUIDPathParam(c *gin.Context, paramName string) uuid.UUID {
+	value, ok := c.MustGet(uuidPathParamKeyPrefix + paramName).(uuid.UUID)
+	if !ok {
+		panic("ginutil: path parameter " + paramName + " was not validated as a UUID")
+	}
+
+	return value
+}
+
+// parseCanonicalUUID parses a UUID in its hyphenated form or as the 16-byte ID in hex.
+// uuid.Parse also accepts braced and urn:uuid: forms; those are rejected so an agent
+// has one spelling per encoding.
+func parseCanonicalUUID(value string) (uuid.UUID, error) {
+	switch len(value) {
+	case 0:
+		return uuid.Nil, ErrRequiredParam
+	case hyphenatedUUIDLength, hexUUIDLength:
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return uuid.Nil, ErrInvalidFormat
+		}
+
+		return parsed, nil
+	default:
+		return uuid.Nil, ErrInvalidFormat
+	}
+}
@@ -0,0 +1,67 @@
+package ginutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+func TestWithUUIDPathParam(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	want := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "hyphenated UUID", id: "550e8400-e29b-41d4-a716-446655440000", wantStatus: http.StatusOK},
+		{name: "uppercase hyphenated UUID", id: "550E8400-E29B-41D4-A716-446655440000", wantStatus: http.StatusOK},
+		{name: "16-byte ID in hex", id: "550e8400e29b41d4a716446655440000", wantStatus: http.StatusOK},
+		{name: "not a UUID", id: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{name: "non-hex digit", id: "550e8400e29b41d4a71644665544000z", wantStatus: http.StatusBadRequest},
+		{name: "braced UUID", id: "{550e8400-e29b-41d4-a716-446655440000}", wantStatus: http.StatusBadRequest},
+		{name: "urn UUID", id: "urn:uuid:550e8400-e29b-41d4-a716-446655440000", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got uuid.UUID
+
+			router := gin.New()
+			router.GET("/agents/:id", ginutil.WithUUIDPathParam("id", func(c *gin.Context) {
+				got = ginutil.UUIDPathParam(c, "id")
+				c.Status(http.StatusOK)
+			}))
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/agents/"+tt.id, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, want, got)
+
+				return
+			}
+
+			assert.Equal(t, uuid.Nil, got, "the handler must not run")
+			assert.Equal(t, "path.id", gjson.Get(recorder.Body.String(), "errors.0.location").String())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ginutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSelector is returned when a selector query parameter is malformed
+// (an entry without a "key=value" shape, or with an empty key). It wraps
+// ErrInvalidFormat so HandleValidationError maps it to a 400 Bad Request.
+var ErrInvalidSelector = fmt.Errorf(
+	"invalid selector: expected key=value pairs: %w", ErrInvalidFormat)
+
+// ParseSelector parses attribute selector values into an exact-match map.
+// Each query-param value is exactly one "key=value" pair; repeat the parameter
+// (?selector=a=b&selector=c=d) to match multiple attributes. Commas are NOT treated
+// as delimiters, so attribute values may safely contain commas. The value is split on
+// the first "=" only, so it may also contain "=". Whitespace around the whole entry and
+// the key is trimmed; the attribute value is kept verbatim. It returns an empty map
+// (no filter) when no pairs are present, and ErrInvalidSelector for a malformed entry.
+func ParseSelector(values []string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		key, val, found := strings.Cut(value, "=")
+
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, ErrInvalidSelector
+		}
+
+		result[key] = val
+	}
+
+	// A non-nil but empty map is treated as "no filter" by the persistence layer,
+	// so there is no need for a nil return here.
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package ginutil
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewRequestTimeoutMiddleware bounds each request with a deadline on its context, so a slow
+// persistence call is cancelled instead of holding the request open indefinitely.
+// routeTimeouts overrides defaultTimeout per route, keyed by the route pattern (e.g.
+// "/api/v1/export"); a non-positive timeout leaves the route unbounded.
+//
+// The deadline only takes effect where the handler passes c.Request.Context() downstream.
+// When it expires before the handler responded, the middleware answers 504.
+//
+// A request for an NDJSON stream only gets a route's own timeout, not defaultTimeout:
+// the stream lasts as long as the listing does, and a deadline expiring mid-stream would
+// truncate a response whose status was already sent.
+func NewRequestTimeoutMiddleware(defaultTimeout time.Duration, routeTimeouts map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routeTimeouts[c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+
+			// An invalid stream format is the handler's to report.
+			if streaming, _ := WantsNDJSON(c); streaming {
+				timeout = 0
+			}
+		}
+
+		if timeout <= 0 {
+			c.Next()
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			GatewayTimeoutError(c, ctx.Err())
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ginutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	// slow waits for the request context, like a persistence call that honours
+	// cancellation, and returns without writing a response.
+	slow := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+			c.Status(http.StatusOK)
+		}
+	}
+
+	router := gin.New()
+	router.Use(ginutil.NewRequestTimeoutMiddleware(10*time.Millisecond, map[string]time.Duration{
+		"/unbounded": 0,
+		"/override":  5 * time.Second,
+	}))
+	router.GET("/default", slow)
+	router.GET("/override", slow)
+	// stream writes NDJSON lines for longer than the default timeout.
+	router.GET("/stream", func(c *gin.Context) {
+		writer := ginutil.NewNDJSONWriter(c)
+		for line := range 3 {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			_ = writer.Write(gin.H{"line": line})
+		}
+	})
+	router.GET("/unbounded", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"hasDeadline": hasDeadline})
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	recorder := serve("/default")
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":504`)
+
+	assert.Equal(t, http.StatusOK, serve("/override").Code)
+
+	recorder = serve("/stream?stream=ndjson")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "{\"line\":0}\n{\"line\":1}\n{\"line\":2}\n", recorder.Body.String())
+
+	assert.JSONEq(t, `{"hasDeadline":false}`, serve("/unbounded").Body.String())
+}
@@ -57,8 +57,9 @@ func New(settings config.ServerSettings) *Server {
 func appOptions(settings *config.ServerSettings) []fx.Option {
 	return []fx.Option{
 		// Hexagonal architecture layers
-		adaptermodule.NewAdapterModules(settings.DatabaseSettings.Type), // Adapters: HTTP, DB, messaging, scheduler
-		infrastructuremodule.New(settings.DatabaseSettings.Type),        // Bootstrap: Casbin RBAC + default seed hooks
+		// Adapters: HTTP, DB, messaging, scheduler
+		adaptermodule.NewAdapterModules(settings.DatabaseSettings.Type, settings.EventSettings.ProtocolType),
+		infrastructuremodule.New(settings.DatabaseSettings.Type), // Bootstrap: Casbin RBAC + default seed hooks
 		applicationmodule.New(),   // Application services
 		domainmodule.New(),        // Domain services
 		NewConfigModule(settings), // Configuration
@@ -81,6 +82,25 @@ func appOptions(settings *config.ServerSettings) []fx.Option {
 	}
 }
 
+// bootstrapOptions returns the FX option set of the one-shot bootstrap command: the
+// persistence adapters and domain services the manifest appliers write through, without
+// the HTTP servers and background runners of a serving apiserver, so it can run next to
+// one.
+func bootstrapOptions(settings *config.ServerSettings, file string) []fx.Option {
+	return []fx.Option{
+		adaptermodule.NewPersistenceModules(settings.DatabaseSettings.Type),
+		infrastructuremodule.New(settings.DatabaseSettings.Type),
+		infrastructuremodule.NewManifestFileModule(file),
+		domainmodule.New(),
+		NewConfigModule(settings),
+		helper.NewModule(),
+		management.NewObservabilityModule(),
+		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
+			return &fxevent.SlogLogger{Logger: logger}
+		}),
+	}
+}
+
 // ValidateWiring checks that the apiserver's FX dependency graph resolves (no missing
 // dependencies or cycles) without constructing the application or running any lifecycle
 // hooks. It is exposed so a fast wiring test can guard against regressions without
@@ -90,6 +110,37 @@ func ValidateWiring(settings config.ServerSettings) error {
 	return fx.ValidateApp(appOptions(&settings)...)
 }
 
+// ValidateBootstrapWiring is ValidateWiring for the one-shot bootstrap command's graph.
+func ValidateBootstrapWiring(settings config.ServerSettings, file string) error {
+	//nolint:wrapcheck // thin pass-through to fx.ValidateApp for tests
+	return fx.ValidateApp(bootstrapOptions(&settings, file)...)
+}
+
+// Bootstrap applies the manifests in file to the configured store and returns: it
+// starts the persistence side of the application, which reconciles BootstrapSettings.Dir
+// and then file, and stops it again. It lets agents be registered before they connect.
+func Bootstrap(ctx context.Context, settings config.ServerSettings, file string) error {
+	app := fx.New(bootstrapOptions(&settings, file)...)
+
+	startCtx, startCancel := context.WithTimeout(ctx, DefaultServerStartTimeout)
+	defer startCancel()
+
+	err := app.Start(startCtx)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap from %q: %w", file, err)
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultServerStopTimeout)
+	defer stopCancel()
+
+	err = app.Stop(stopCtx)
+	if err != nil {
+		return fmt.Errorf("failed to stop after bootstrapping: %w", err)
+	}
+
+	return nil
+}
+
 // Run starts the server and blocks until the context is done.
 func (s *Server) Run(ctx context.Context) error {
 	startCtx, startCancel := context.WithTimeout(ctx, DefaultServerStartTimeout)
@@ -23,3 +23,19 @@ func TestWiringValidates(t *testing.T) {
 		t.Fatalf("fx app graph failed to validate: %v", err)
 	}
 }
+
+// TestBootstrapWiringValidates checks the one-shot bootstrap command's graph resolves
+// without the HTTP servers and runners of the serving apiserver.
+func TestBootstrapWiringValidates(t *testing.T) {
+	t.Parallel()
+
+	//exhaustruct:ignore
+	settings := config.ServerSettings{
+		DatabaseSettings: config.DatabaseSettings{Type: config.DatabaseTypeInMemory},
+	}
+
+	err := app.ValidateBootstrapWiring(settings, "agents.yaml")
+	if err != nil {
+		t.Fatalf("fx bootstrap graph failed to validate: %v", err)
+	}
+}
@@ -10,19 +10,39 @@ package adapter
 import (
 	"go.uber.org/fx"
 
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/inmemory"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/adapter/common"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/adapter/primary"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/adapter/secondary"
 )
 
 // NewAdapterModules creates the adapter layer module. The database type selects
-// the secondary persistence backend (MongoDB or in-memory standalone).
-func NewAdapterModules(databaseType config.DatabaseType) fx.Option {
+// the secondary persistence backend (MongoDB or in-memory standalone) and the event
+// protocol type the inbound messaging transport.
+func NewAdapterModules(databaseType config.DatabaseType, eventProtocolType config.EventProtocolType) fx.Option {
 	return fx.Module(
 		"adapter",
-		primary.New(),
+		primary.New(eventProtocolType),
 		secondary.New(databaseType),
 		common.New(),
 	)
 }
+
+// NewPersistenceModules creates the driven side of the adapter layer only: the
+// persistence backend selected by the database type and the outbound transports. It
+// serves one-shot commands that write to the store without serving any traffic, so
+// server events are received from the process-local hub instead of joining the
+// cluster's event stream.
+func NewPersistenceModules(databaseType config.DatabaseType) fx.Option {
+	return fx.Module(
+		"adapter",
+		secondary.New(databaseType),
+		common.New(),
+		fx.Provide(fx.Annotate(
+			func(hub *inmemory.EventSenderAdapter) *inmemory.EventSenderAdapter { return hub },
+			fx.As(new(agentport.ServerEventReceiverPort)),
+		)),
+	)
+}
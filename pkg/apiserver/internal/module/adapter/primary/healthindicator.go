@@ -0,0 +1,46 @@
+package primary
+
+import (
+	"context"
+
+	inkafka "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/kafka"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/management/healthcheck"
+)
+
+var _ healthcheck.HealthIndicator = (*KafkaConsumerLagHealthIndicator)(nil)
+
+// KafkaConsumerLagHealthIndicator reports the server not ready while its Kafka consumer
+// group is further behind than the configured maximum lag.
+type KafkaConsumerLagHealthIndicator struct {
+	monitor *inkafka.ConsumerLagMonitor
+}
+
+func newKafkaConsumerLagHealthIndicator(monitor *inkafka.ConsumerLagMonitor) *KafkaConsumerLagHealthIndicator {
+	return &KafkaConsumerLagHealthIndicator{
+		monitor: monitor,
+	}
+}
+
+// Name returns the name of the health indicator.
+func (k *KafkaConsumerLagHealthIndicator) Name() string {
+	return "KafkaConsumerLag"
+}
+
+// Readiness returns whether the latest measured consumer lag is within the maximum.
+func (k *KafkaConsumerLagHealthIndicator) Readiness(context.Context) healthcheck.Readiness {
+	ready, reason := k.monitor.Ready()
+
+	return healthcheck.Readiness{
+		Ready:  ready,
+		Reason: reason,
+	}
+}
+
+// Health returns the health status of the consumer. Lag is a readiness concern only: a
+// server that is behind still catches up on its own.
+func (k *KafkaConsumerLagHealthIndicator) Health(context.Context) healthcheck.Health {
+	return healthcheck.Health{
+		Healthy: true,
+		Reason:  "consumer lag only affects readiness",
+	}
+}
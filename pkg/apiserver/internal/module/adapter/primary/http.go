@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	sloggin "github.com/samber/slog-gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginswagger "github.com/swaggo/gin-swagger"
@@ -18,10 +20,15 @@ import (
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/auth/basic"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/auth/github"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/auth/jwks"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentexpectedattributes"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentgroup"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentpackage"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentquarantine"
 	agentremoteconfigcontroller "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentremoteconfig"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agentrevocation"
+	backupcontroller "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/backup"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/certificate"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/connection"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/container"
@@ -30,16 +37,21 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/host"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/namespace"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/opamp"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/openapi"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/ping"
 	reconcilecontroller "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/reconcile"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/resourcequota"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/role"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/rolebinding"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/server"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/summary"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/user"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/version"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/docs"
 	userport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user/port"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/management/observability"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
 )
@@ -56,6 +68,21 @@ const (
 	// surfaces as `Post ... : EOF` on the agent. When unset, net/http falls
 	// back to ReadTimeout, which produces that exact race.
 	DefaultHTTPIdleTimeout = 120 * time.Second
+
+	// DefaultRequestTimeout is the default deadline of an API request.
+	DefaultRequestTimeout = 30 * time.Second
+
+	// DefaultBackupRequestTimeout is the default deadline of export and import, which
+	// read or write every resource in one request.
+	DefaultBackupRequestTimeout = 10 * time.Minute
+
+	// DefaultPackageVerifyRequestTimeout is the default deadline of verifying an agent
+	// package, which may retry the download several times: it covers the default attempts,
+	// each with its own timeout, and the backoff between them.
+	DefaultPackageVerifyRequestTimeout = 2 * time.Minute
+
+	// effectiveConfigWatchRoute streams an agent's effective config as server-sent events.
+	effectiveConfigWatchRoute = "/api/v1/namespaces/:namespace/agents/:id/effective-config/watch"
 )
 
 var (
@@ -71,33 +98,11 @@ func NewHTTP() fx.Option {
 			NewHTTPServer,
 			fx.Annotate(NewEngine, fx.ParamTags(`group:"controllers"`)),
 
-			// Controllers — added to the "controllers" group consumed by NewEngine.
-			AsController(ping.NewController),
-			AsController(version.NewController),
-			AsController(connection.NewController),
-			AsController(agent.NewController),
-			AsController(agentgroup.NewController),
-			AsController(agentpackage.NewController),
-			AsController(agentremoteconfigcontroller.NewController),
-			AsController(reconcilecontroller.NewController),
-			AsController(endpoint.NewController),
-			AsController(endpointmetrics.NewController),
-			AsController(namespace.NewController),
-			AsController(certificate.NewController),
-			AsController(host.NewController),
-			AsController(container.NewController),
-			AsController(server.NewController),
-			AsController(user.NewController),
-			AsController(role.NewController),
-			AsController(rolebinding.NewController),
-			AsController(github.NewController),
-			AsController(basic.NewController),
-
 			// The OpAMP controller is also needed as its concrete type for the
 			// connection context, so it is provided plainly and then added to the
 			// group via a pass-through (fx.Self() can't be used here: ResultTags
 			// would also tag the concrete output, hiding it from connContext).
-			opamp.NewController,
+			newOpAMPController,
 			fx.Annotate(
 				func(c *opamp.Controller) Controller { return c },
 				fx.ResultTags(`group:"controllers"`),
@@ -108,9 +113,48 @@ func NewHTTP() fx.Option {
 				return opampController.ConnContext
 			},
 		),
+		// Controllers — added to the "controllers" group consumed by NewEngine.
+		fx.Provide(lo.Map(controllerConstructors(), func(constructor any, _ int) any {
+			return AsController(constructor)
+		})...),
 	)
 }
 
+// controllerConstructors returns the constructors of the controllers served by the
+// engine, other than the OpAMP controller.
+func controllerConstructors() []any {
+	return []any{
+		ping.NewController,
+		version.NewController,
+		openapi.NewController,
+		connection.NewController,
+		agent.NewController,
+		agentgroup.NewController,
+		agentpackage.NewController,
+		agentquarantine.NewController,
+		agentexpectedattributes.NewController,
+		agentrevocation.NewController,
+		agentremoteconfigcontroller.NewController,
+		reconcilecontroller.NewController,
+		backupcontroller.NewController,
+		endpoint.NewController,
+		endpointmetrics.NewController,
+		namespace.NewController,
+		certificate.NewController,
+		host.NewController,
+		container.NewController,
+		server.NewController,
+		resourcequota.NewController,
+		summary.NewController,
+		user.NewController,
+		role.NewController,
+		rolebinding.NewController,
+		github.NewController,
+		basic.NewController,
+		jwks.NewController,
+	}
+}
+
 // NewHTTPServer creates a new HTTP server instance.
 func NewHTTPServer(
 	lifecycle fx.Lifecycle,
@@ -170,10 +214,23 @@ func NewEngine(
 	settings *config.ServerSettings,
 	observabilityService *observability.Service,
 	logger *slog.Logger,
-) *gin.Engine {
+) (*gin.Engine, error) {
 	engine := gin.New()
+
+	err := configureTrustedProxies(engine, settings.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	engine.Use(sloggin.New(logger))
 	engine.Use(gin.Recovery())
+	engine.Use(version.NewHeaderMiddleware())
+	// OpAMP speaks protobuf, and swagger and the GitHub login serve HTML or redirects.
+	engine.Use(ginutil.NewContentNegotiationMiddleware(
+		[]string{effectiveConfigWatchRoute},
+		"/api/v1/opamp", "/swagger", "/docs", "/auth/"))
+	engine.Use(ginutil.NewLargeIntsAsStringsMiddleware())
+	engine.Use(ginutil.NewRequestTimeoutMiddleware(requestTimeouts(settings.RequestTimeoutSettings)))
 	engine.Use(security.NewAuthJWTMiddleware(securityService))
 	engine.Use(security.NewAuthorizationMiddleware(
 		rbacUsecase,
@@ -200,7 +257,58 @@ func NewEngine(
 		}
 	}
 
-	return engine
+	return engine, nil
+}
+
+// configureTrustedProxies restricts which peers may set the client IP through
+// X-Forwarded-For and X-Real-IP. Gin trusts every peer unless told otherwise, which
+// would let any client pick the IP that rate limiting and audit logs record; an empty
+// list trusts none, so ClientIP falls back to the connection's remote address.
+func configureTrustedProxies(engine *gin.Engine, trustedProxies []string) error {
+	err := engine.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies %v: %w", trustedProxies, err)
+	}
+
+	return nil
+}
+
+// requestTimeouts resolves the default request deadline and the per-route overrides,
+// layering the configured routes over the built-in ones.
+func requestTimeouts(settings config.RequestTimeoutSettings) (time.Duration, map[string]time.Duration) {
+	defaultTimeout := settings.Default
+	if defaultTimeout == 0 {
+		defaultTimeout = DefaultRequestTimeout
+	}
+
+	routes := map[string]time.Duration{
+		// An OpAMP WebSocket lives as long as the agent stays connected.
+		"/api/v1/opamp": 0,
+		// An effective config watch streams events until the client disconnects.
+		effectiveConfigWatchRoute: 0,
+		"/api/v1/export":          DefaultBackupRequestTimeout,
+		"/api/v1/import":          DefaultBackupRequestTimeout,
+
+		"/api/v1/namespaces/:namespace/agentpackages/:name/verify": DefaultPackageVerifyRequestTimeout,
+	}
+	maps.Copy(routes, settings.Routes)
+
+	return defaultTimeout, routes
+}
+
+// newOpAMPController creates the OpAMP controller with the configured message size and
+// per-IP connection limits, compression, and required headers and subprotocol.
+func newOpAMPController(
+	opampUsecase usecase.OpAMPUsecase,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) *opamp.Controller {
+	return opamp.NewController(opampUsecase, logger,
+		opamp.WithMaxMessageBytes(settings.OpAMPSettings.MaxMessageBytes),
+		opamp.WithMaxConnectionsPerIP(settings.OpAMPSettings.MaxConnectionsPerIP),
+		opamp.WithCompression(settings.OpAMPSettings.EnableCompression),
+		opamp.WithRequiredHeaders(settings.OpAMPSettings.RequiredHeaders),
+		opamp.WithRequiredSubprotocol(settings.OpAMPSettings.RequiredSubprotocol))
 }
 
 // Controller is an interface that defines the methods for handling HTTP requests.
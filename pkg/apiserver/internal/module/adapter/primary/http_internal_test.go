@@ -0,0 +1,209 @@
+package primary
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/http/v1/agent/usecasemock"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/docs"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/ginutil"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/management/observability"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+)
+
+func TestConfigureTrustedProxies(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	const forwardedIP = "203.0.113.7"
+
+	engine := gin.New()
+	require.NoError(t, configureTrustedProxies(engine, []string{"10.0.0.0/8"}))
+	engine.GET("/ip", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.ClientIP())
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expectedIP string
+	}{
+		{name: "trusted proxy forwards the client IP", remoteAddr: "10.1.2.3:40000", expectedIP: forwardedIP},
+		{name: "untrusted peer cannot spoof the client IP", remoteAddr: "192.0.2.5:40000", expectedIP: "192.0.2.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", forwardedIP)
+
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.expectedIP, recorder.Body.String())
+		})
+	}
+
+	t.Run("invalid CIDR is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, configureTrustedProxies(gin.New(), []string{"not-a-cidr"}))
+	})
+}
+
+func TestNewEngineStreamsEffectiveConfigWatch(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	const (
+		adminUsername = "admin"
+		adminPassword = "admin-password"
+		adminEmail    = "admin@example.com"
+	)
+
+	//exhaustruct:ignore
+	securityConfig := security.Config{
+		//exhaustruct:ignore
+		JWTSettings: security.JWTSettings{
+			SigningKey: "test-signing-key",
+			Issuer:     "test",
+			Expiration: time.Minute,
+		},
+		AdminSettings: security.AdminSettings{
+			Username: adminUsername,
+			Password: adminPassword,
+			Email:    adminEmail,
+		},
+	}
+
+	securityService, err := security.New(slog.Default(), &securityConfig, http.DefaultClient,
+		security.NewPasswordHasher(&securityConfig), inmemory.NewUserRepository())
+	require.NoError(t, err)
+
+	login, err := securityService.BasicAuth(t.Context(), adminUsername, adminPassword)
+	require.NoError(t, err)
+
+	instanceUID := uuid.New()
+	agentUsecase := usecasemock.NewMockManageUsecase(t)
+	agentUsecase.EXPECT().
+		WatchAgentEffectiveConfig(mock.Anything, "default", instanceUID).
+		RunAndReturn(func(ctx context.Context, _ string, _ uuid.UUID) (<-chan *v1.AgentEffectiveConfig, error) {
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline, "the watch must not inherit the default request deadline")
+
+			configs := make(chan *v1.AgentEffectiveConfig)
+
+			// The change arrives after the default deadline would have expired.
+			go func() {
+				defer close(configs)
+
+				time.Sleep(100 * time.Millisecond)
+
+				configs <- &v1.AgentEffectiveConfig{}
+			}()
+
+			return configs, nil
+		})
+
+	//exhaustruct:ignore
+	observabilityService := &observability.Service{}
+
+	//exhaustruct:ignore
+	settings := &config.ServerSettings{
+		RequestTimeoutSettings: config.RequestTimeoutSettings{Default: 10 * time.Millisecond},
+		Security:               securityConfig,
+	}
+
+	engine, err := NewEngine(
+		[]Controller{agent.NewController(agentUsecase, slog.Default())},
+		securityService, nil, nil, settings, observabilityService, slog.Default())
+	require.NoError(t, err)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/api/v1/namespaces/default/agents/"+instanceUID.String()+"/effective-config/watch", nil)
+	req.Header.Set("Accept", ginutil.EventStreamContentType)
+	req.Header.Set("Authorization", "Bearer "+login.Token)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	assert.Contains(t, recorder.Header().Get("Content-Type"), ginutil.EventStreamContentType)
+	assert.Contains(t, recorder.Body.String(), "event:"+agent.EffectiveConfigEventName+"\n")
+}
+
+func TestOpenAPIDocumentDescribesEveryRoute(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	controllers := make([]Controller, 0, len(controllerConstructors()))
+
+	// The routes don't depend on the controllers' dependencies, so every
+	// constructor is given zero values.
+	for _, constructor := range controllerConstructors() {
+		constructorValue := reflect.ValueOf(constructor)
+		args := make([]reflect.Value, constructorValue.Type().NumIn())
+
+		for i := range args {
+			args[i] = reflect.Zero(constructorValue.Type().In(i))
+		}
+
+		controller, ok := constructorValue.Call(args)[0].Interface().(Controller)
+		require.True(t, ok, "%T must construct a Controller", constructor)
+
+		controllers = append(controllers, controller)
+	}
+
+	//exhaustruct:ignore
+	securityConfig := security.Config{
+		//exhaustruct:ignore
+		JWTSettings: security.JWTSettings{SigningKey: "test-signing-key", Issuer: "test"},
+	}
+
+	securityService, err := security.New(slog.Default(), &securityConfig, http.DefaultClient,
+		security.NewPasswordHasher(&securityConfig), inmemory.NewUserRepository())
+	require.NoError(t, err)
+
+	//exhaustruct:ignore
+	settings := &config.ServerSettings{Security: securityConfig}
+
+	//exhaustruct:ignore
+	engine, err := NewEngine(controllers, securityService, nil, nil, settings,
+		&observability.Service{}, slog.Default())
+	require.NoError(t, err)
+
+	document := docs.SwaggerInfo.ReadDoc()
+	pathParam := regexp.MustCompile(`[:*]([^/]+)`)
+
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		operation := gjson.Get(document, "paths."+gjson.Escape(path)+"."+strings.ToLower(route.Method))
+		assert.True(t, operation.Exists(), "%s %s is missing from the OpenAPI document", route.Method, path)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/IBM/sarama"
 	cekafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	metricapi "go.opentelemetry.io/otel/metric"
 	"go.uber.org/fx"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/inmemory"
@@ -17,6 +18,7 @@ import (
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/adapter/common"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/helper"
 )
 
 // ErrServerIDRequired is returned when Kafka messaging is configured without a server ID.
@@ -33,31 +35,33 @@ const (
 	defaultKafkaRetryBackoff = 2 * time.Second
 )
 
-// newEventReceiver provides the inbound server-event receiver, selecting the transport
-// based on the configured event protocol. In standalone (in-memory) mode it returns
-// the shared hub so events sent by the sender in the secondary adapter are observed.
+// newMessaging provides the inbound server-event receiver, selecting the transport
+// from the configured event protocol. In distributed (Kafka) mode it also monitors the
+// consumer group's lag, exporting it as a metric and reporting it on /readyz.
+func newMessaging(protocolType config.EventProtocolType) fx.Option {
+	if protocolType == config.EventProtocolTypeKafka {
+		return fx.Provide(
+			newKafkaConsumer,
+			newKafkaEventReceiver,
+			newKafkaConsumerLagMonitor,
+			helper.AsRunner(func(monitor *inkafka.ConsumerLagMonitor) *inkafka.ConsumerLagMonitor {
+				return monitor
+			}),
+			helper.AsHealthIndicator(newKafkaConsumerLagHealthIndicator),
+		)
+	}
+
+	return fx.Provide(newEventReceiver)
+}
+
+// newEventReceiver provides the inbound server-event receiver for the non-Kafka
+// protocols. In standalone (in-memory) mode it returns the shared hub so events sent
+// by the sender in the secondary adapter are observed.
 func newEventReceiver(
 	settings *config.EventSettings,
-	serverID agentmodel.ServerID,
-	logger *slog.Logger,
-	lifecycle fx.Lifecycle,
-	serverIdentityProvider agentport.ServerIdentityProvider,
 	hub *inmemory.EventSenderAdapter,
 ) (agentport.ServerEventReceiverPort, error) {
-	switch settings.ProtocolType {
-	case config.EventProtocolTypeKafka:
-		receiver, err := createKafkaReceiver(settings, serverID, logger, lifecycle)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Kafka receiver: %w", err)
-		}
-
-		adapter, err := inkafka.NewEventReceiverAdapter(serverIdentityProvider, receiver, logger)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Kafka event receiver adapter: %w", err)
-		}
-
-		return adapter, nil
-	case config.EventProtocolTypeInMemory:
+	if settings.ProtocolType == config.EventProtocolTypeInMemory {
 		return hub, nil
 	}
 
@@ -66,19 +70,63 @@ func newEventReceiver(
 	}
 }
 
-// createKafkaReceiver creates a Kafka receiver with lifecycle management.
+// newKafkaEventReceiver provides the inbound server-event receiver backed by Kafka.
+func newKafkaEventReceiver(
+	consumer *kafkaConsumer,
+	serverIdentityProvider agentport.ServerIdentityProvider,
+	logger *slog.Logger,
+) (agentport.ServerEventReceiverPort, error) {
+	adapter, err := inkafka.NewEventReceiverAdapter(serverIdentityProvider, consumer.protocol, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka event receiver adapter: %w", err)
+	}
+
+	return adapter, nil
+}
+
+// newKafkaConsumerLagMonitor provides the monitor measuring how far this server's
+// consumer group is behind on the event topic.
+func newKafkaConsumerLagMonitor(
+	settings *config.EventSettings,
+	consumer *kafkaConsumer,
+	meterProvider metricapi.MeterProvider,
+	logger *slog.Logger,
+) *inkafka.ConsumerLagMonitor {
+	return inkafka.NewConsumerLagMonitor(
+		inkafka.NewSaramaOffsetReader(consumer.client),
+		consumer.groupID,
+		consumer.topic,
+		inkafka.LagSettings{
+			CheckInterval: settings.KafkaSettings.LagCheckInterval,
+			MaxLag:        settings.KafkaSettings.MaxConsumerLag,
+		},
+		meterProvider,
+		logger,
+	)
+}
+
+// kafkaConsumer is the server's Kafka consumer together with the client it shares
+// with the consumer lag monitor.
+type kafkaConsumer struct {
+	client   sarama.Client
+	protocol *cekafka.Consumer
+	groupID  string
+	topic    string
+}
+
+// newKafkaConsumer creates the Kafka consumer with lifecycle management.
 //
 // Each server uses its own consumer group so that every server receives every
 // inter-server event (broadcast). The receiver then filters by event.Subject
 // to keep only those targeting this server. A single shared consumer group
 // would route each event to exactly one consumer, silently dropping events
 // addressed to other servers.
-func createKafkaReceiver(
+func newKafkaConsumer(
 	settings *config.EventSettings,
 	serverID agentmodel.ServerID,
 	logger *slog.Logger,
 	lifecycle fx.Lifecycle,
-) (*cekafka.Consumer, error) {
+) (*kafkaConsumer, error) {
 	if serverID.String() == "" {
 		return nil, ErrServerIDRequired
 	}
@@ -94,11 +142,13 @@ func createKafkaReceiver(
 	topic := settings.KafkaSettings.Topic
 	groupID := "opampcommander-consumer-group-" + serverID.String()
 
-	consumer, err := cekafka.NewConsumer(brokers, saramaConfig, groupID, topic)
+	client, err := sarama.NewClient(brokers, saramaConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Kafka receiver: %w", err)
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
 	}
 
+	consumer := cekafka.NewConsumerFromClient(client, groupID, topic)
+
 	lifecycleCtx, lifecycleCancel := context.WithCancel(context.Background())
 
 	lifecycle.Append(fx.Hook{
@@ -116,7 +166,7 @@ func createKafkaReceiver(
 		},
 		OnStop: func(ctx context.Context) error {
 			// Cancel the lifecycle context first so OpenInbound stops receiving,
-			// then close the underlying consumer to release resources.
+			// then close the underlying consumer and the client it was built on.
 			lifecycleCancel()
 
 			err := consumer.Close(ctx)
@@ -124,9 +174,19 @@ func createKafkaReceiver(
 				return fmt.Errorf("failed to close Kafka receiver: %w", err)
 			}
 
+			err = client.Close()
+			if err != nil {
+				return fmt.Errorf("failed to close Kafka client: %w", err)
+			}
+
 			return nil
 		},
 	})
 
-	return consumer, nil
+	return &kafkaConsumer{
+		client:   client,
+		protocol: consumer,
+		groupID:  groupID,
+		topic:    topic,
+	}, nil
 }
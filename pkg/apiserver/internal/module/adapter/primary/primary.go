@@ -5,17 +5,20 @@ package primary
 
 import (
 	"go.uber.org/fx"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
 )
 
-// New creates the primary adapter module.
-func New() fx.Option {
+// New creates the primary adapter module. The event protocol type selects the inbound
+// messaging transport.
+func New(eventProtocolType config.EventProtocolType) fx.Option {
 	return fx.Options(
 		NewHTTP(),
 		fx.Provide(
 			fx.Annotate(NewExecutor, fx.ParamTags(``, `group:"runners"`)),
-			// Inbound messaging: server-event receiver.
-			newEventReceiver,
 		),
+		// Inbound messaging: server-event receiver.
+		newMessaging(eventProtocolType),
 		fx.Invoke(func(*Executor) {}),
 	)
 }
@@ -0,0 +1,31 @@
+package secondary
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/enrichment"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+// errAgentEnrichmentAttributeEmpty is returned when static enrichment is selected but no
+// attribute is configured to look agents up by.
+var errAgentEnrichmentAttributeEmpty = errors.New("agent enrichment attribute is empty")
+
+// newAgentEnrichmentAdapter selects the agent enrichment adapter from the configured
+// enrichment type: a static label table when configured, otherwise a no-op so the port is
+// always satisfiable.
+func newAgentEnrichmentAdapter(settings *config.ServerSettings) (agentport.AgentEnrichmentPort, error) {
+	enrichmentSettings := settings.AgentEnrichment
+
+	if enrichmentSettings.Type == config.AgentEnrichmentTypeStatic {
+		if enrichmentSettings.StaticAttribute == "" {
+			return nil, fmt.Errorf("%w (type %q)", errAgentEnrichmentAttributeEmpty, enrichmentSettings.Type)
+		}
+
+		return enrichment.NewStaticAdapter(enrichmentSettings.StaticAttribute, enrichmentSettings.StaticLabels), nil
+	}
+
+	return enrichment.NewNoopAdapter(), nil
+}
@@ -51,6 +51,7 @@ func NewInMemory() fx.Option {
 			fx.Annotate(inmemory.NewAgentPackageRepository, fx.As(new(agentport.AgentPackagePersistencePort))),
 			fx.Annotate(inmemory.NewNamespaceRepository, fx.As(new(agentport.NamespacePersistencePort))),
 			fx.Annotate(inmemory.NewAgentRemoteConfigRepository, fx.As(new(agentport.AgentRemoteConfigPersistencePort))),
+			fx.Annotate(inmemory.NewAgentRevocationRepository, fx.As(new(agentport.AgentRevocationPersistencePort))),
 			fx.Annotate(inmemory.NewEndpointRepository, fx.As(new(agentport.EndpointPersistencePort))),
 			fx.Annotate(inmemory.NewCertificateRepository, fx.As(new(agentport.CertificatePersistencePort))),
 			fx.Annotate(inmemory.NewHostRepository, fx.As(new(agentport.HostPersistencePort))),
@@ -10,6 +10,7 @@ import (
 	cekafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	"go.uber.org/fx"
 
+	kafkamodel "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/common/kafka"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/primary/messaging/inmemory"
 	outkafka "github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/messaging/kafka"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
@@ -42,6 +43,11 @@ func newEventSender(
 ) (agentport.ServerEventSenderPort, error) {
 	switch settings.ProtocolType {
 	case config.EventProtocolTypeKafka:
+		keyStrategy, err := kafkamodel.ParseKeyStrategy(settings.KafkaSettings.KeyStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kafka settings: %w", err)
+		}
+
 		sender, err := createKafkaSender(settings, lifecycle)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Kafka sender: %w", err)
@@ -52,6 +58,8 @@ func newEventSender(
 			return nil, fmt.Errorf("failed to create Kafka event sender adapter: %w", err)
 		}
 
+		adapter.SetKeyStrategy(keyStrategy)
+
 		return adapter, nil
 	case config.EventProtocolTypeInMemory:
 		return hub, nil
@@ -3,6 +3,7 @@ package secondary
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -32,13 +33,15 @@ func NewMongoDB() fx.Option {
 				fx.As(new(applicationport.TransactionRunner)),
 				fx.As(new(agentport.TransactionPort)),
 			),
-			fx.Annotate(mongodb.NewAgentRepository, fx.As(new(agentport.AgentPersistencePort))),
+			NewAgentPersistence,
+			NewFieldCipher,
 			fx.Annotate(mongodb.NewAgentGroupRepository, fx.As(new(agentport.AgentGroupPersistencePort))),
 			fx.Annotate(mongodb.NewServerAdapter, fx.As(new(agentport.ServerPersistencePort))),
 			fx.Annotate(mongodb.NewServerConnectionAdapter, fx.As(new(agentport.ServerConnectionPersistencePort))),
 			fx.Annotate(mongodb.NewAgentPackageRepository, fx.As(new(agentport.AgentPackagePersistencePort))),
 			fx.Annotate(mongodb.NewNamespaceRepository, fx.As(new(agentport.NamespacePersistencePort))),
 			fx.Annotate(mongodb.NewAgentRemoteConfigRepository, fx.As(new(agentport.AgentRemoteConfigPersistencePort))),
+			fx.Annotate(mongodb.NewAgentRevocationRepository, fx.As(new(agentport.AgentRevocationPersistencePort))),
 			fx.Annotate(mongodb.NewEndpointRepository, fx.As(new(agentport.EndpointPersistencePort))),
 			fx.Annotate(mongodb.NewCertificateRepository, fx.As(new(agentport.CertificatePersistencePort))),
 			fx.Annotate(mongodb.NewHostRepository, fx.As(new(agentport.HostPersistencePort))),
@@ -53,6 +56,45 @@ func NewMongoDB() fx.Option {
 	)
 }
 
+// NewAgentPersistence provides the agent repository. When an agent write flush interval is
+// configured, agent writes are batched, and the buffer is flushed when the server stops.
+func NewAgentPersistence(
+	database *mongo.Database,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+	lifecycle fx.Lifecycle,
+) agentport.AgentPersistencePort {
+	repository := mongodb.NewAgentRepository(database, logger)
+
+	batchSettings := settings.DatabaseSettings.AgentWriteBatch
+	if batchSettings.FlushInterval <= 0 {
+		return repository
+	}
+
+	batching := mongodb.NewBatchingAgentRepository(repository, batchSettings.FlushInterval, batchSettings.MaxSize)
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			batching.Start()
+
+			return nil
+		},
+		OnStop: batching.Stop,
+	})
+
+	return batching
+}
+
+// NewFieldCipher provides the cipher that encrypts secret fields at rest. It is nil when no
+// field encryption key is configured.
+func NewFieldCipher(settings *config.ServerSettings) (*mongodb.FieldCipher, error) {
+	fieldCipher, err := mongodb.NewFieldCipher(settings.DatabaseSettings.FieldEncryption.KeyURI)
+	if err != nil {
+		return nil, fmt.Errorf("database field encryption: %w", err)
+	}
+
+	return fieldCipher, nil
+}
+
 // NewMongoDBClient creates a new MongoDB client with OpenTelemetry instrumentation.
 func NewMongoDBClient(
 	settings *config.ServerSettings,
@@ -0,0 +1,26 @@
+package secondary
+
+import (
+	"log/slog"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/packagedownload"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+)
+
+// newAgentPackageDownloadAdapter creates the HTTP adapter that downloads agent package
+// artifacts, with the configured timeout, retries and circuit breaker.
+func newAgentPackageDownloadAdapter(
+	settings *config.ServerSettings,
+	logger *slog.Logger,
+) agentport.AgentPackageDownloadPort {
+	download := settings.PackageDownloadSettings
+
+	return packagedownload.NewAdapter(packagedownload.Settings{
+		Timeout:          download.Timeout,
+		MaxAttempts:      download.MaxAttempts,
+		RetryBackoff:     download.RetryBackoff,
+		FailureThreshold: download.FailureThreshold,
+		OpenDuration:     download.OpenDuration,
+	}, logger)
+}
@@ -24,5 +24,9 @@ func New(databaseType config.DatabaseType) fx.Option {
 		fx.Provide(newEventSender),
 		// Outbound metrics: endpoint-throughput query port (Prometheus or no-op).
 		fx.Provide(newEndpointMetricsQueryAdapter),
+		// Outbound enrichment: server-owned agent labels (static table or no-op).
+		fx.Provide(newAgentEnrichmentAdapter),
+		// Outbound downloads: agent package artifacts (HTTP with retries and a circuit breaker).
+		fx.Provide(newAgentPackageDownloadAdapter),
 	)
 }
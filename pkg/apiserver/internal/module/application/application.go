@@ -2,16 +2,24 @@
 package application
 
 import (
+	"fmt"
 	"log/slog"
 
+	metricapi "go.opentelemetry.io/otel/metric"
+	traceapi "go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
+	applicationhelper "github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	adminApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/admin"
 	agentApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agent"
+	agentexpectedattributesApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentexpectedattributes"
 	agentgroupApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentgroup"
 	agentpackageApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentpackage"
+	agentquarantineApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentquarantine"
 	agentremoteconfigApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentremoteconfig"
+	agentrevocationApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/agentrevocation"
 	authApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/auth"
+	backupApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/backup"
 	certificateApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/certificate"
 	containerApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/container"
 	endpointApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/endpoint"
@@ -20,14 +28,20 @@ import (
 	namespaceApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/namespace"
 	opampApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/opamp"
 	reconcileApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/reconcile"
+	resourcequotaApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/resourcequota"
 	roleApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/role"
 	rolebindingApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/rolebinding"
 	serverApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/server"
+	summaryApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/summary"
 	userApplicationService "github.com/minuk-dev/opampcommander/pkg/apiserver/application/service/user"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/usecase"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
+	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/internal/module/helper"
+	"github.com/minuk-dev/opampcommander/pkg/utils/clock"
 )
 
 // New creates a new module for application services.
@@ -38,7 +52,8 @@ func New() fx.Option {
 		"application",
 		// application
 		fx.Provide(
-			opampApplicationService.New,
+			provideAgentTransitionLogger,
+			provideOpAMPService,
 			fx.Annotate(Identity[*opampApplicationService.Service], fx.As(new(usecase.OpAMPUsecase))),
 			helper.AsRunner(Identity[*opampApplicationService.Service]), // for background processing
 
@@ -46,19 +61,44 @@ func New() fx.Option {
 			fx.Annotate(Identity[*adminApplicationService.Service], fx.As(new(usecase.AdminUsecase))),
 			serverApplicationService.New,
 			fx.Annotate(Identity[*serverApplicationService.Service], fx.As(new(usecase.ServerManageUsecase))),
+			resourcequotaApplicationService.New,
+			fx.Annotate(
+				Identity[*resourcequotaApplicationService.Service],
+				fx.As(new(usecase.ResourceQuotaManageUsecase)),
+			),
+			summaryApplicationService.New,
+			fx.Annotate(Identity[*summaryApplicationService.Service], fx.As(new(usecase.SummaryManageUsecase))),
 
-			agentApplicationService.New,
+			provideAgentService,
 			fx.Annotate(Identity[*agentApplicationService.Service], fx.As(new(usecase.AgentManageUsecase))),
+			agentquarantineApplicationService.New,
+			fx.Annotate(
+				Identity[*agentquarantineApplicationService.Service],
+				fx.As(new(usecase.AgentQuarantineManageUsecase)),
+			),
+			agentexpectedattributesApplicationService.New,
+			fx.Annotate(
+				Identity[*agentexpectedattributesApplicationService.Service],
+				fx.As(new(usecase.AgentExpectedAttributesManageUsecase)),
+			),
+			agentrevocationApplicationService.New,
+			fx.Annotate(
+				Identity[*agentrevocationApplicationService.Service],
+				fx.As(new(usecase.AgentRevocationManageUsecase)),
+			),
 
 			reconcileApplicationService.New,
 			fx.Annotate(Identity[*reconcileApplicationService.Service], fx.As(new(usecase.ReconcileManageUsecase))),
 
-			agentgroupApplicationService.NewManageService,
+			provideAgentGroupManageService,
 			fx.Annotate(Identity[*agentgroupApplicationService.ManageService], fx.As(new(usecase.AgentGroupManageUsecase))),
 
 			agentpackageApplicationService.NewAgentPackageService,
 			fx.Annotate(Identity[*agentpackageApplicationService.Service], fx.As(new(usecase.AgentPackageManageUsecase))),
 
+			backupApplicationService.New,
+			fx.Annotate(Identity[*backupApplicationService.Service], fx.As(new(usecase.BackupUsecase))),
+
 			namespaceApplicationService.NewNamespaceService,
 			fx.Annotate(Identity[*namespaceApplicationService.Service], fx.As(new(usecase.NamespaceManageUsecase))),
 
@@ -107,6 +147,192 @@ func New() fx.Option {
 	)
 }
 
+// provideAgentTransitionLogger builds the agent state transition logger shared by the
+// services changing agents, so its rate limit applies to all of them. It is nil when the
+// transitions are not logged.
+func provideAgentTransitionLogger(
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) *applicationhelper.AgentTransitionLogger {
+	if !settings.AgentTransitionLogSettings.Enabled {
+		return nil
+	}
+
+	return applicationhelper.NewAgentTransitionLogger(logger, clock.NewRealClock(),
+		settings.AgentTransitionLogSettings.MaxPerSecond)
+}
+
+// provideOpAMPService builds the OpAMP service with the configured attribute aliases,
+// limits and default config content type, falling back to the built-in ones when none
+// are configured.
+func provideOpAMPService(
+	agentUsecase agentport.AgentUsecase,
+	connectionUsecase agentport.ConnectionUsecase,
+	serverIdentityProvider agentport.ServerIdentityProvider,
+	agentGroupUsecase agentport.AgentGroupUsecase,
+	agentNotificationUsecase agentport.AgentNotificationUsecase,
+	serverToAgentBuilder *agentservice.ServerToAgentBuilder,
+	agentRemoteConfigUsecase agentport.AgentRemoteConfigUsecase,
+	hostUsecase agentport.HostUsecase,
+	containerUsecase agentport.ContainerUsecase,
+	agentRevocationUsecase agentport.AgentRevocationUsecase,
+	deliveryTracker agentport.AgentDeliveryTracker,
+	disconnectGraceUsecase agentport.AgentDisconnectGraceUsecase,
+	effectiveConfigChangePublisher agentport.AgentEffectiveConfigChangePublisher,
+	transitionLogger *applicationhelper.AgentTransitionLogger,
+	enricher agentport.AgentEnrichmentPort,
+	traceProvider traceapi.TracerProvider,
+	meterProvider metricapi.MeterProvider,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) (*opampApplicationService.Service, error) {
+	defaultConfigContentType, err := applicationhelper.ConfigContentTypeForFormat(
+		settings.AgentConfigFileSettings.DefaultFormat)
+	if err != nil {
+		return nil, fmt.Errorf("agent config file settings: %w", err)
+	}
+
+	service := opampApplicationService.New(
+		agentUsecase,
+		connectionUsecase,
+		serverIdentityProvider,
+		agentGroupUsecase,
+		agentNotificationUsecase,
+		serverToAgentBuilder,
+		agentRemoteConfigUsecase,
+		hostUsecase,
+		containerUsecase,
+		agentRevocationUsecase,
+		traceProvider,
+		logger,
+	)
+
+	if denylist := modelagent.AttributeDenylist(settings.AgentAttributeSettings.Denylist); len(denylist) > 0 {
+		err := denylist.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid agent attribute denylist: %w", err)
+		}
+
+		service.SetAttributeDenylist(denylist)
+	}
+
+	if aliases := settings.AgentAttributeSettings.Aliases; len(aliases) > 0 {
+		service.SetAttributeAliases(aliases)
+	}
+
+	limits := modelagent.DefaultAttributeLimits()
+	if maxCount := settings.AgentAttributeSettings.MaxCount; maxCount != 0 {
+		limits.MaxCount = maxCount
+	}
+
+	if maxTotalBytes := settings.AgentAttributeSettings.MaxTotalBytes; maxTotalBytes != 0 {
+		limits.MaxTotalBytes = maxTotalBytes
+	}
+
+	service.SetAttributeLimits(limits)
+
+	historyLimits := agentmodel.DefaultEffectiveConfigHistoryLimits()
+	if maxEntries := settings.AgentEffectiveConfigHistorySettings.MaxEntries; maxEntries != 0 {
+		historyLimits.MaxEntries = maxEntries
+	}
+
+	if maxTotalBytes := settings.AgentEffectiveConfigHistorySettings.MaxTotalBytes; maxTotalBytes != 0 {
+		historyLimits.MaxTotalBytes = maxTotalBytes
+	}
+
+	service.SetEffectiveConfigHistoryLimits(historyLimits)
+
+	componentsLimits := agentmodel.DefaultAvailableComponentsLimits()
+	if maxDepth := settings.AgentAvailableComponentsSettings.MaxDepth; maxDepth != 0 {
+		componentsLimits.MaxDepth = maxDepth
+	}
+
+	if maxNodes := settings.AgentAvailableComponentsSettings.MaxNodes; maxNodes != 0 {
+		componentsLimits.MaxNodes = maxNodes
+	}
+
+	service.SetAvailableComponentsLimits(componentsLimits)
+	service.SetDefaultConfigContentType(defaultConfigContentType)
+	service.SetMeterProvider(meterProvider)
+	service.SetMinReportInterval(settings.OpAMPSettings.MinReportInterval)
+	service.SetEffectiveConfigPersistence(opampApplicationService.EffectiveConfigPersistence{
+		OnChangeOnly:   settings.OpAMPSettings.EffectiveConfigOnChangeOnly,
+		SampleInterval: settings.OpAMPSettings.EffectiveConfigSampleInterval,
+	})
+	service.SetDisconnectGracePeriod(settings.OpAMPSettings.DisconnectGracePeriod, disconnectGraceUsecase)
+	service.SetAgentDeliveryTracker(deliveryTracker)
+	service.SetAgentEffectiveConfigChangePublisher(effectiveConfigChangePublisher)
+	service.SetAgentTransitionLogger(transitionLogger)
+	service.SetAgentEnricher(enricher)
+
+	return service, nil
+}
+
+// provideAgentService builds the agent management service with the configured default
+// content type for config files stored without one.
+func provideAgentService(
+	agentUsecase agentport.AgentUsecase,
+	agentNotificationUsecase agentport.AgentNotificationUsecase,
+	endpointDetectionUsecase agentport.EndpointDetectionUsecase,
+	cacheInvalidationPublisher agentport.AgentCacheInvalidationPublisher,
+	agentGroupUsecase agentport.AgentGroupUsecase,
+	disconnectPublisher agentport.AgentDisconnectPublisher,
+	effectiveConfigWatcher agentport.AgentEffectiveConfigWatcher,
+	transitionLogger *applicationhelper.AgentTransitionLogger,
+	commandQueueSummaryUsecase agentport.AgentCommandQueueSummaryUsecase,
+	meterProvider metricapi.MeterProvider,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) (*agentApplicationService.Service, error) {
+	defaultConfigContentType, err := applicationhelper.ConfigContentTypeForFormat(
+		settings.AgentConfigFileSettings.DefaultFormat)
+	if err != nil {
+		return nil, fmt.Errorf("agent config file settings: %w", err)
+	}
+
+	service := agentApplicationService.New(
+		agentUsecase,
+		agentNotificationUsecase,
+		endpointDetectionUsecase,
+		cacheInvalidationPublisher,
+		logger,
+	)
+	service.SetDefaultConfigContentType(defaultConfigContentType)
+	service.SetMaxPendingCommands(settings.AgentCommandSettings.MaxPending)
+	service.SetMeterProvider(meterProvider, commandQueueSummaryUsecase)
+	service.SetAgentGroupUsecase(agentGroupUsecase)
+	service.SetAgentDisconnectPublisher(disconnectPublisher)
+	service.SetAgentEffectiveConfigWatcher(effectiveConfigWatcher)
+	service.SetAgentTransitionLogger(transitionLogger)
+
+	return service, nil
+}
+
+// provideAgentGroupManageService builds the agent group application service, applying the
+// configured case-insensitive name uniqueness.
+func provideAgentGroupManageService(
+	agentgroupUsecase agentport.AgentGroupUsecase,
+	agentUsecase agentport.AgentUsecase,
+	remoteConfigUsecase agentport.AgentRemoteConfigUsecase,
+	changePublisher agentport.AgentGroupChangePublisher,
+	quotaUsecase agentport.ResourceQuotaUsecase,
+	nameUsecase agentport.AgentGroupNameUsecase,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) *agentgroupApplicationService.ManageService {
+	service := agentgroupApplicationService.NewManageService(
+		agentgroupUsecase,
+		agentUsecase,
+		remoteConfigUsecase,
+		changePublisher,
+		quotaUsecase,
+		logger,
+	)
+	service.SetCaseInsensitiveNames(settings.AgentGroupSettings.CaseInsensitiveNames, nameUsecase)
+
+	return service
+}
+
 // provideEndpointMetricsService builds the endpoint-throughput service, sourcing
 // the default rate window from configuration.
 func provideEndpointMetricsService(
@@ -3,12 +3,17 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"go.uber.org/fx"
 	"k8s.io/utils/clock"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
+	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	agentservice "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/service"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/reconcile"
@@ -32,15 +37,34 @@ func New() fx.Option {
 		fx.Annotate(
 			Identity[*agentservice.AgentService],
 			fx.As(new(agentport.AgentUsecase)),
+			fx.As(new(agentport.AgentFleetSummaryUsecase)),
+			fx.As(new(agentport.AgentCommandQueueSummaryUsecase)),
+			fx.As(new(agentport.AgentDisconnectGraceUsecase)),
 			fx.As(new(agentport.AgentCacheInvalidator)),
 		),
-		agentservice.NewAgentGroupService,
+		provideAgentGroupService,
 		fx.Annotate(
 			Identity[*agentservice.AgentGroupService],
 			fx.As(new(agentport.AgentGroupUsecase)),
 			fx.As(new(agentport.AgentGroupRelatedUsecase)),
+			fx.As(new(agentport.AgentGroupNameUsecase)),
 		),
+		provideAgentQuarantineService,
+		fx.Annotate(
+			Identity[*agentservice.AgentQuarantineService],
+			fx.As(new(agentport.AgentQuarantineUsecase)),
+		),
+		provideEffectiveConfigStalenessService,
+		fx.Annotate(
+			agentservice.NewAgentExpectedAttributesService,
+			fx.As(new(agentport.AgentExpectedAttributesUsecase)),
+		),
+		fx.Annotate(agentservice.NewAgentRevocationService, fx.As(new(agentport.AgentRevocationUsecase))),
 		fx.Annotate(agentservice.NewAgentPackageService, fx.As(new(agentport.AgentPackageUsecase))),
+		fx.Annotate(
+			agentservice.NewAgentPackageVerificationService,
+			fx.As(new(agentport.AgentPackageVerificationUsecase)),
+		),
 		fx.Annotate(provideNamespaceService, fx.As(new(agentport.NamespaceUsecase))),
 		fx.Annotate(provideHostService, fx.As(new(agentport.HostUsecase))),
 		fx.Annotate(provideContainerService, fx.As(new(agentport.ContainerUsecase))),
@@ -49,6 +73,7 @@ func New() fx.Option {
 		fx.Annotate(agentservice.NewEndpointMetricsService, fx.As(new(agentport.EndpointMetricsUsecase))),
 		fx.Annotate(agentservice.NewEndpointDetectionService, fx.As(new(agentport.EndpointDetectionUsecase))),
 		fx.Annotate(agentservice.NewCertificateService, fx.As(new(agentport.CertificateUsecase))),
+		fx.Annotate(provideResourceQuotaService, fx.As(new(agentport.ResourceQuotaUsecase))),
 		agentservice.NewServerToAgentBuilder,
 		agentservice.NewServerService,
 		fx.Annotate(
@@ -57,6 +82,11 @@ func New() fx.Option {
 			fx.As(new(agentport.ServerMessageUsecase)),
 			fx.As(new(agentport.LeaderElector)),
 			fx.As(new(agentport.AgentCacheInvalidationPublisher)),
+			fx.As(new(agentport.AgentGroupChangePublisher)),
+			fx.As(new(agentport.AgentDisconnectPublisher)),
+			fx.As(new(agentport.AgentEffectiveConfigChangePublisher)),
+			fx.As(new(agentport.AgentEffectiveConfigWatcher)),
+			fx.As(new(agentport.AgentDeliveryTracker)),
 		),
 		agentservice.NewServerIdentityService,
 		fx.Annotate(
@@ -81,6 +111,8 @@ func New() fx.Option {
 		),
 		helper.AsRunner(Identity[*agentservice.Service]),
 		helper.AsRunner(Identity[*agentservice.AgentGroupService]),
+		helper.AsRunner(Identity[*agentservice.AgentQuarantineService]),
+		helper.AsRunner(Identity[*agentservice.EffectiveConfigStalenessService]),
 		helper.AsRunner(Identity[*agentservice.ServerService]),
 		helper.AsRunner(Identity[*agentservice.ServerIdentityService]),
 		helper.AsRunner(Identity[*agentservice.AgentNotificationService]),
@@ -130,6 +162,137 @@ func provideAgentService(
 	)
 }
 
+// provideAgentGroupService builds the agent group domain service, applying the
+// configured delimiter used to namespace inline config names under their group,
+// the configured remote config validators and the base remote configs.
+func provideAgentGroupService(
+	persistencePort agentport.AgentGroupPersistencePort,
+	agentRemoteConfigPersistencePort agentport.AgentRemoteConfigPersistencePort,
+	certificatePersistencePort agentport.CertificatePersistencePort,
+	agentUsecase agentport.AgentUsecase,
+	leaderElector agentport.LeaderElector,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) (*agentservice.AgentGroupService, error) {
+	keyFormat, err := agentmodel.NewRemoteConfigKeyFormat(settings.AgentGroupSettings.RemoteConfigKeyDelimiter)
+	if err != nil {
+		return nil, fmt.Errorf("agent group settings: %w", err)
+	}
+
+	service := agentservice.NewAgentGroupService(
+		persistencePort,
+		agentRemoteConfigPersistencePort,
+		certificatePersistencePort,
+		agentUsecase,
+		leaderElector,
+		logger,
+	)
+	service.SetRemoteConfigKeyFormat(keyFormat)
+
+	var validators []agentport.RemoteConfigValidator
+	if len(settings.AgentGroupSettings.ForbiddenRemoteConfigKeys) > 0 {
+		validators = append(validators,
+			agentservice.NewForbiddenKeysValidator(settings.AgentGroupSettings.ForbiddenRemoteConfigKeys))
+	}
+
+	service.SetRemoteConfigValidators(validators...)
+
+	baseRemoteConfigs, err := loadBaseRemoteConfigs(settings.AgentGroupSettings.BaseRemoteConfigFiles)
+	if err != nil {
+		return nil, fmt.Errorf("agent group settings: %w", err)
+	}
+
+	// Base configs reach every governed agent, so they must pass the same policies a
+	// group's configs do; check them once here rather than on every propagation.
+	for name, file := range baseRemoteConfigs {
+		for _, validator := range validators {
+			err := validator.ValidateRemoteConfig(context.Background(), name, file)
+			if err != nil {
+				return nil, fmt.Errorf("agent group settings: base remote config %q: %w", name, err)
+			}
+		}
+	}
+
+	service.SetBaseRemoteConfigs(baseRemoteConfigs)
+
+	return service, nil
+}
+
+// loadBaseRemoteConfigs reads the base remote config files, keyed by their file name
+// without the extension.
+func loadBaseRemoteConfigs(paths []string) (map[string]agentmodel.AgentConfigFile, error) {
+	configs := make(map[string]agentmodel.AgentConfigFile, len(paths))
+
+	for _, path := range paths {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read base remote config: %w", err)
+		}
+
+		ext := filepath.Ext(path)
+		name := strings.TrimSuffix(filepath.Base(path), ext)
+
+		if _, dup := configs[name]; dup {
+			return nil, fmt.Errorf("%w: base remote config %q", agentservice.ErrDuplicateRemoteConfigName, name)
+		}
+
+		configs[name] = agentmodel.AgentConfigFile{
+			Body:        body,
+			ContentType: baseRemoteConfigContentType(ext),
+		}
+	}
+
+	return configs, nil
+}
+
+func baseRemoteConfigContentType(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		return "text/yaml"
+	case ".json":
+		return "application/json"
+	default:
+		return "text/plain"
+	}
+}
+
+// provideAgentQuarantineService builds the agent quarantine domain service with the
+// configured unhealthy threshold; a zero threshold leaves only manual quarantine.
+func provideAgentQuarantineService(
+	agentUsecase agentport.AgentUsecase,
+	agentGroupUsecase agentport.AgentGroupUsecase,
+	leaderElector agentport.LeaderElector,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) *agentservice.AgentQuarantineService {
+	return agentservice.NewAgentQuarantineService(
+		agentUsecase,
+		agentGroupUsecase,
+		leaderElector,
+		settings.AgentQuarantineSettings.UnhealthyThreshold,
+		settings.AgentQuarantineSettings.EvaluationInterval,
+		logger,
+	)
+}
+
+// provideEffectiveConfigStalenessService builds the evaluator that flags connected agents
+// whose effective config went unreported for longer than the configured window; a zero
+// window disables it.
+func provideEffectiveConfigStalenessService(
+	agentUsecase agentport.AgentUsecase,
+	leaderElector agentport.LeaderElector,
+	logger *slog.Logger,
+	settings *config.ServerSettings,
+) *agentservice.EffectiveConfigStalenessService {
+	return agentservice.NewEffectiveConfigStalenessService(
+		agentUsecase,
+		leaderElector,
+		settings.AgentEffectiveConfigStalenessSettings.Window,
+		settings.AgentEffectiveConfigStalenessSettings.EvaluationInterval,
+		logger,
+	)
+}
+
 // provideNamespaceService builds the namespace domain service, sourcing the
 // undeletable default namespace name from configuration. The service owns the
 // namespace lifecycle rules and the cascade delete of a namespace's children.
@@ -153,6 +316,22 @@ func provideNamespaceService(
 	)
 }
 
+// provideResourceQuotaService builds the resource quota domain service with the
+// configured per-resource caps.
+func provideResourceQuotaService(
+	agentGroupPersistencePort agentport.AgentGroupPersistencePort,
+	certificatePersistencePort agentport.CertificatePersistencePort,
+	settings *config.ServerSettings,
+) *agentservice.ResourceQuotaService {
+	service := agentservice.NewResourceQuotaService(agentGroupPersistencePort, certificatePersistencePort)
+	service.SetResourceQuotas(agentmodel.ResourceQuotas{
+		agentmodel.QuotaResourceAgentGroup:  settings.ResourceQuotaSettings.MaxAgentGroups,
+		agentmodel.QuotaResourceCertificate: settings.ResourceQuotaSettings.MaxCertificates,
+	})
+
+	return service
+}
+
 // provideHostService builds the host domain service with the real clock so
 // discovery timestamps (FirstSeenAt/LastSeenAt) use wall-clock time.
 func provideHostService(
@@ -25,14 +25,11 @@ func registerObservabilityShutdown(lifecycle fx.Lifecycle, service *observabilit
 func NewModule() fx.Option {
 	return fx.Module(
 		"management",
+		observabilityComponents(),
 		fx.Provide(
 			// Management HTTP handlers
-			observability.New, AsManagementHTTPHandler(Identity[*observability.Service]),
+			AsManagementHTTPHandler(Identity[*observability.Service]),
 			pprof.NewHandler, AsManagementHTTPHandler(Identity[*pprof.Handler]),
-			ExposeObservabilityComponents,
-
-			// HTTP Client with tracing - must be provided after observability
-			NewTracedHTTPClient,
 
 			// Health checks
 			fx.Annotate(healthcheck.NewHealthHelper, fx.ParamTags(`group:"health_indicators"`)),
@@ -41,11 +38,35 @@ func NewModule() fx.Option {
 		),
 		// Management HTTP server
 		fx.Invoke(func(*HTTPServer) {}),
-		// Flush observability pipelines on shutdown
-		fx.Invoke(registerObservabilityShutdown),
 		// Logger
 		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
 			return &fxevent.SlogLogger{Logger: logger}
 		}),
 	)
 }
+
+// NewObservabilityModule creates a module with only the observability components
+// (logger, meter/trace providers, traced HTTP client), without the management HTTP
+// server, for one-shot commands that serve no traffic.
+func NewObservabilityModule() fx.Option {
+	return fx.Module(
+		"management",
+		observabilityComponents(),
+	)
+}
+
+// observabilityComponents provides the observability Service and the components it
+// exposes, and flushes its pipelines on shutdown.
+func observabilityComponents() fx.Option {
+	return fx.Options(
+		fx.Provide(
+			observability.New,
+			ExposeObservabilityComponents,
+
+			// HTTP Client with tracing - must be provided after observability
+			NewTracedHTTPClient,
+		),
+		// Flush observability pipelines on shutdown
+		fx.Invoke(registerObservabilityShutdown),
+	)
+}
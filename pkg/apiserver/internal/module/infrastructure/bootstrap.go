@@ -24,6 +24,7 @@ import (
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/config"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	agentport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/domain/model"
 	usermodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user"
@@ -53,6 +54,7 @@ var (
 	errEmptyUserEmail         = errors.New("user manifest has empty spec.email")
 	errEmptyEndpointName      = errors.New("endpoint manifest has empty metadata.name")
 	errEmptyEndpointNamespace = errors.New("endpoint manifest has empty metadata.namespace")
+	errEmptyAgentInstanceUID  = errors.New("agent manifest has empty metadata.instanceUid")
 )
 
 // bootstrapUIDNamespace is a fixed UUID namespace used to derive deterministic UIDs
@@ -84,12 +86,16 @@ type bootstrapDeps struct {
 	fs                        afero.Fs
 	namespaceUsecase          agentport.NamespaceUsecase
 	endpointUsecase           agentport.EndpointUsecase
+	agentUsecase              agentport.AgentUsecase
 	rolePersistencePort       userport.RolePersistencePort
 	permissionPersistencePort userport.PermissionPersistencePort
 	userPersistencePort       userport.UserPersistencePort
 	passwordHasher            *security.PasswordHasher
-	clk                       clock.PassiveClock
-	logger                    *slog.Logger
+	// defaultNamespace is the namespace a bootstrapped agent is placed in when its
+	// manifest names none, mirroring how a newly-connected agent is defaulted.
+	defaultNamespace string
+	clk              clock.PassiveClock
+	logger           *slog.Logger
 }
 
 // manifestDoc is a single decoded YAML document together with its type meta and
@@ -217,6 +223,8 @@ func applyManifests(ctx context.Context, docs []manifestDoc, deps bootstrapDeps)
 			err = applyUser(ctx, doc, deps)
 		case v1.EndpointKind:
 			err = applyEndpoint(ctx, doc, deps)
+		case v1.AgentKind:
+			err = applyAgent(ctx, doc, deps)
 		default:
 			return fmt.Errorf("%w: kind %q in %q", errUnsupportedKind, doc.kind, doc.source)
 		}
@@ -372,6 +380,76 @@ func reconcileBootstrapEndpoint(
 	return nil
 }
 
+// applyAgent pre-registers an agent from a manifest so it is listed, and matched by
+// agent groups, before it first connects. Unlike the other resources an agent is not
+// overwritten once it exists: its description is owned by what the agent reports, so
+// the manifest only seeds the record.
+func applyAgent(ctx context.Context, doc manifestDoc, deps bootstrapDeps) error {
+	var apiAgent v1.Agent
+
+	err := json.Unmarshal(doc.json, &apiAgent)
+	if err != nil {
+		return fmt.Errorf("decode Agent from %q: %w", doc.source, err)
+	}
+
+	instanceUID := apiAgent.Metadata.InstanceUID
+	if instanceUID == uuid.Nil {
+		return fmt.Errorf("%w: %q", errEmptyAgentInstanceUID, doc.source)
+	}
+
+	_, err = deps.agentUsecase.GetAgent(ctx, instanceUID)
+	if err == nil {
+		deps.logger.Info("bootstrap: agent already exists, skipping",
+			slog.String("instanceUid", instanceUID.String()))
+
+		return nil
+	}
+
+	if !errors.Is(err, model.ErrResourceNotExist) {
+		return fmt.Errorf("check agent %q: %w", instanceUID, err)
+	}
+
+	description := agent.Description{
+		IdentifyingAttributes:       apiAgent.Metadata.Description.IdentifyingAttributes,
+		NonIdentifyingAttributes:    apiAgent.Metadata.Description.NonIdentifyingAttributes,
+		RawIdentifyingAttributes:    nil,
+		RawNonIdentifyingAttributes: nil,
+		AttributesTruncated:         false,
+	}
+
+	// Place the agent where it will land once it reports the same description: an
+	// explicit namespace wins, then service.namespace, then the default namespace.
+	namespace := apiAgent.Metadata.Namespace
+	if namespace == "" {
+		namespace = description.Service().Namespace
+	}
+
+	if namespace == "" {
+		namespace = deps.defaultNamespace
+	}
+
+	deps.logger.Info("bootstrap: creating agent",
+		slog.String("instanceUid", instanceUID.String()), slog.String("namespace", namespace))
+
+	newAgent := agentmodel.NewAgent(instanceUID,
+		agentmodel.WithNamespace(namespace),
+		agentmodel.WithDescription(&description),
+	)
+
+	err = deps.agentUsecase.SaveAgent(ctx, newAgent)
+	if errors.Is(err, model.ErrConflict) {
+		// The agent connected (or another apiserver seeded it) since the check above;
+		// the record it now has takes precedence over the manifest.
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("save agent %q: %w", instanceUID, err)
+	}
+
+	return nil
+}
+
 // applyRole upserts a role, setting its permission list to exactly the manifest's
 // (full overwrite). Permission objects referenced by name are auto-created from the
 // "resource:action" encoding so SyncPolicies can resolve them.
@@ -620,31 +698,42 @@ func ensurePermission(ctx context.Context, name string, deps bootstrapDeps) erro
 	return nil
 }
 
-// registerBootstrapHook reconciles the initial manifests under BootstrapSettings.Dir
-// into persistence on startup. When Dir is empty, reconciliation is skipped.
-func registerBootstrapHook(
-	lifecycle fx.Lifecycle,
+// newBootstrapDeps bundles the ports the manifest appliers write through, for both the
+// startup reconciliation and the one-shot bootstrap of a manifest file.
+func newBootstrapDeps(
 	namespaceUsecase agentport.NamespaceUsecase,
 	endpointUsecase agentport.EndpointUsecase,
+	agentUsecase agentport.AgentUsecase,
 	rolePersistencePort userport.RolePersistencePort,
 	permissionPersistencePort userport.PermissionPersistencePort,
 	userPersistencePort userport.UserPersistencePort,
 	passwordHasher *security.PasswordHasher,
 	settings *config.ServerSettings,
 	logger *slog.Logger,
-) {
-	deps := bootstrapDeps{
+) bootstrapDeps {
+	defaultNamespace := settings.BootstrapSettings.DefaultNamespace
+	if defaultNamespace == "" {
+		defaultNamespace = agentmodel.DefaultNamespaceName
+	}
+
+	return bootstrapDeps{
 		fs:                        afero.NewOsFs(),
 		namespaceUsecase:          namespaceUsecase,
 		endpointUsecase:           endpointUsecase,
+		agentUsecase:              agentUsecase,
 		rolePersistencePort:       rolePersistencePort,
 		permissionPersistencePort: permissionPersistencePort,
 		userPersistencePort:       userPersistencePort,
 		passwordHasher:            passwordHasher,
+		defaultNamespace:          defaultNamespace,
 		clk:                       clock.NewRealClock(),
 		logger:                    logger,
 	}
+}
 
+// registerBootstrapHook reconciles the initial manifests under BootstrapSettings.Dir
+// into persistence on startup. When Dir is empty, reconciliation is skipped.
+func registerBootstrapHook(lifecycle fx.Lifecycle, deps bootstrapDeps, settings *config.ServerSettings) {
 	lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			return reconcileManifests(ctx, settings.BootstrapSettings.Dir, deps)
@@ -653,6 +742,44 @@ func registerBootstrapHook(
 	})
 }
 
+// NewManifestFileModule applies the manifests in file once the application starts,
+// after the manifests under BootstrapSettings.Dir. It backs the one-shot
+// "apiserver bootstrap --file" command, e.g. to pre-register agents before they connect.
+func NewManifestFileModule(file string) fx.Option {
+	return fx.Module(
+		"bootstrap-file",
+		fx.Invoke(func(lifecycle fx.Lifecycle, deps bootstrapDeps) {
+			lifecycle.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					return applyManifestFile(ctx, file, deps)
+				},
+				OnStop: nil,
+			})
+		}),
+	)
+}
+
+// applyManifestFile applies every manifest document in file. Unlike the startup
+// directory, the file was asked for explicitly, so a missing file is an error.
+func applyManifestFile(ctx context.Context, file string, deps bootstrapDeps) error {
+	data, err := afero.ReadFile(deps.fs, file)
+	if err != nil {
+		return fmt.Errorf("read manifest %q: %w", file, err)
+	}
+
+	docs, err := decodeManifestBytes(data, filepath.Base(file))
+	if err != nil {
+		return err
+	}
+
+	deps.logger.Info("bootstrap: applying manifest file",
+		slog.String("file", file),
+		slog.Int("documents", len(docs)),
+	)
+
+	return applyManifests(ctx, docs, deps)
+}
+
 // reconcileManifests applies the initial manifests from dir. dir is the directory of
 // default manifests an operator can inspect, edit, or point elsewhere via
 // bootstrap.dir. An empty dir disables seeding; a dir that does not exist or is not a
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -51,6 +52,26 @@ spec:
     - agent:GET
 `
 
+const agentsManifest = `kind: Agent
+apiVersion: v1
+metadata:
+  instanceUid: 01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a90
+  description:
+    identifyingAttributes:
+      service.name: otelcol-contrib
+      service.namespace: production
+    nonIdentifyingAttributes:
+      host.name: web-1
+---
+kind: Agent
+apiVersion: v1
+metadata:
+  instanceUid: 01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a91
+  description:
+    identifyingAttributes:
+      service.name: otelcol
+`
+
 // fixedClock is a clock.PassiveClock that always reports a fixed time, used to make
 // a spurious write detectable via the timestamp it would stamp.
 type fixedClock struct{ now time.Time }
@@ -79,14 +100,23 @@ func newTestDeps() (bootstrapDeps, *inmemory.RoleRepository, *inmemory.Permissio
 		BasicAuthSettings: security.BasicAuthSettings{Pepper: testPepper},
 	})
 
+	agentService := agentservice.NewAgentService(
+		inmemory.NewAgentRepository(),
+		slog.Default(),
+		agentservice.AgentCacheConfig{Enabled: false, TTL: 0, MaxCapacity: 0},
+		agentmodel.DefaultNamespaceName,
+	)
+
 	return bootstrapDeps{
 		fs:                        afero.NewMemMapFs(),
 		namespaceUsecase:          nsService,
 		endpointUsecase:           agentservice.NewEndpointService(inmemory.NewEndpointRepository()),
+		agentUsecase:              agentService,
 		rolePersistencePort:       roleRepo,
 		permissionPersistencePort: permRepo,
 		userPersistencePort:       userRepo,
 		passwordHasher:            hasher,
+		defaultNamespace:          agentmodel.DefaultNamespaceName,
 		clk:                       clock.NewRealClock(),
 		logger:                    slog.Default(),
 	}, roleRepo, permRepo
@@ -553,3 +583,69 @@ func TestReconcileManifests_DirIsAFileSkipsWithoutError(t *testing.T) {
 	_, err := roleRepo.GetRoleByName(t.Context(), "default")
 	require.Error(t, err, "nothing must be seeded when dir is a file")
 }
+
+func TestApplyManifestFile_RegistersAgentsBeforeTheyConnect(t *testing.T) {
+	t.Parallel()
+
+	deps, _, _ := newTestDeps()
+	require.NoError(t, afero.WriteFile(deps.fs, "/agents.yaml", []byte(agentsManifest), 0o600))
+
+	require.NoError(t, applyManifestFile(t.Context(), "/agents.yaml", deps))
+
+	// Neither agent has connected, yet both are listed in the namespace it will report.
+	production, err := deps.agentUsecase.ListAgents(t.Context(), "production", nil)
+	require.NoError(t, err)
+	require.Len(t, production.Items, 1)
+
+	web := production.Items[0]
+	assert.Equal(t, "01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a90", web.Metadata.InstanceUID.String())
+	assert.Equal(t, "otelcol-contrib", web.Metadata.Description.IdentifyingAttributes["service.name"])
+	assert.Equal(t, "web-1", web.Metadata.Description.NonIdentifyingAttributes["host.name"])
+	assert.False(t, web.IsConnected(t.Context()))
+
+	defaultNamespace, err := deps.agentUsecase.ListAgents(t.Context(), agentmodel.DefaultNamespaceName, nil)
+	require.NoError(t, err)
+	require.Len(t, defaultNamespace.Items, 1, "an agent without service.namespace lands in the default namespace")
+	assert.Equal(t, "01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a91", defaultNamespace.Items[0].Metadata.InstanceUID.String())
+}
+
+func TestApplyManifests_ExistingAgentIsNotOverwritten(t *testing.T) {
+	t.Parallel()
+
+	deps, _, _ := newTestDeps()
+	docs, err := decodeManifestBytes([]byte(agentsManifest), "agents.yaml")
+	require.NoError(t, err)
+	require.NoError(t, applyManifests(t.Context(), docs, deps))
+
+	// The agent connects and reports a different description, which it owns from then on.
+	uid := uuid.MustParse("01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a90")
+	reported, err := deps.agentUsecase.GetAgent(t.Context(), uid)
+	require.NoError(t, err)
+	reported.Metadata.Description.NonIdentifyingAttributes = map[string]string{"host.name": "web-2"}
+	require.NoError(t, deps.agentUsecase.SaveAgent(t.Context(), reported))
+
+	require.NoError(t, applyManifests(t.Context(), docs, deps))
+
+	got, err := deps.agentUsecase.GetAgent(t.Context(), uid)
+	require.NoError(t, err)
+	assert.Equal(t, "web-2", got.Metadata.Description.NonIdentifyingAttributes["host.name"])
+}
+
+func TestApplyManifests_AgentRequiresInstanceUID(t *testing.T) {
+	t.Parallel()
+
+	deps, _, _ := newTestDeps()
+	docs, err := decodeManifestBytes([]byte("kind: Agent\napiVersion: v1\nmetadata:\n  namespace: default\n"), "a.yaml")
+	require.NoError(t, err)
+
+	err = applyManifests(t.Context(), docs, deps)
+	require.ErrorIs(t, err, errEmptyAgentInstanceUID)
+}
+
+func TestApplyManifestFile_MissingFileFails(t *testing.T) {
+	t.Parallel()
+
+	deps, _, _ := newTestDeps()
+
+	require.Error(t, applyManifestFile(t.Context(), "/does-not-exist.yaml", deps))
+}
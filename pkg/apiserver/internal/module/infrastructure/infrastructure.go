@@ -34,6 +34,7 @@ func New(databaseType config.DatabaseType) fx.Option {
 
 		// Initial manifest reconciliation — seeds the default namespace, built-in
 		// roles, and their permissions declaratively from BootstrapSettings.Dir.
+		fx.Provide(newBootstrapDeps),
 		fx.Invoke(registerBootstrapHook),
 		// RBAC policy sync — must run after the bootstrap manifests are applied.
 		fx.Invoke(registerSyncPoliciesHook),
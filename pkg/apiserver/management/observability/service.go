@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
 	metricapi "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	traceapi "go.opentelemetry.io/otel/trace"
@@ -105,7 +106,14 @@ func New(
 			service.shutdownFuncs = append(service.shutdownFuncs, shutdown)
 		}
 
-		service.TextMapPropagator = propagation.TraceContext{}
+		// Baggage rides along with the W3C trace context so both are continued from
+		// incoming request headers. The global is set for libraries that only read it
+		// (e.g. cloudevents, see newTraceProvider).
+		service.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		)
+		otel.SetTextMapPropagator(service.TextMapPropagator)
 	}
 
 	return service, nil
@@ -132,8 +140,10 @@ func (service *Service) RoutesInfos() management.RoutesInfo {
 }
 
 // Middleware returns a Gin middleware function that applies OpenTelemetry instrumentation.
+// With tracing enabled every request gets a server span named after its route, carrying
+// the response status and continuing the trace context of the incoming headers.
 func (service *Service) Middleware() gin.HandlerFunc {
-	if service.MeterProvider == nil {
+	if service.MeterProvider == nil && service.TraceProvider == nil {
 		return func(ctx *gin.Context) {
 			ctx.Next()
 		}
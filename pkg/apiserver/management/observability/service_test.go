@@ -0,0 +1,72 @@
+package observability_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/management/observability"
+)
+
+func TestService_Middleware_ProducesNestedSpans(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	exporter := tracetest.NewInMemoryExporter()
+	traceProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	t.Cleanup(func() { _ = traceProvider.Shutdown(t.Context()) })
+
+	//exhaustruct:ignore
+	service := &observability.Service{
+		TraceProvider:     traceProvider,
+		TextMapPropagator: propagation.TraceContext{},
+	}
+
+	router := gin.New()
+	router.Use(service.Middleware())
+	router.GET("/api/v1/agents/:id", func(ctx *gin.Context) {
+		// Stands in for a persistence call, which is traced from the request context.
+		_, span := traceProvider.Tracer("test").Start(ctx.Request.Context(), "mongodb.find")
+		span.End()
+
+		ctx.Status(http.StatusOK)
+	})
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/agents/abc", nil)
+	req.Header.Set("Traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	// The child ends first, so it is exported first.
+	child, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /api/v1/agents/:id", server.Name)
+	assert.Contains(t, server.Attributes, attribute.String("http.route", "/api/v1/agents/:id"))
+	assert.Contains(t, server.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+
+	// The server span continues the incoming trace and parents the child span.
+	assert.Equal(t, traceID, server.SpanContext.TraceID().String())
+	assert.Equal(t, parentSpanID, server.Parent.SpanID().String())
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), child.SpanContext.TraceID())
+}
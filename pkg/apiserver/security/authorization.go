@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	usermodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user"
 	userport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user/port"
 )
 
@@ -20,7 +21,7 @@ const (
 
 // NewAuthorizationMiddleware creates a Gin middleware that enforces RBAC for
 // both namespace-scoped (/api/v1/namespaces/:namespace/*) and global
-// (/api/v1/users, /api/v1/roles, /api/v1/servers) resources.
+// (/api/v1/users, /api/v1/roles, /api/v1/servers, /api/v1/agents, /api/v1/export, /api/v1/import) resources.
 // The adminEmail user bypasses all RBAC checks.
 func NewAuthorizationMiddleware(
 	rbacUsecase userport.RBACUsecase,
@@ -75,6 +76,7 @@ func NewAuthorizationMiddleware(
 func isExemptFromRBAC(fullPath string) bool {
 	if strings.HasPrefix(fullPath, "/auth/") ||
 		strings.HasPrefix(fullPath, "/api/v1/auth/") ||
+		strings.HasPrefix(fullPath, "/.well-known/") ||
 		strings.HasPrefix(fullPath, "/swagger") ||
 		strings.HasPrefix(fullPath, "/docs") {
 		return true
@@ -84,6 +86,7 @@ func isExemptFromRBAC(fullPath string) bool {
 	case "/api/v1/ping",
 		"/api/v1/version",
 		"/api/v1/opamp",
+		"/api/v1/openapi.json",
 		"/api/v1/namespaces",
 		"/api/v1/namespaces/:namespace":
 		return true
@@ -208,8 +211,35 @@ func extractNamespacedResourceAndAction(fullPath, method string) (string, string
 		return "", ""
 	}
 
+	// Setting or lifting an agent's quarantine (/agents/:id/quarantine), replacing its
+	// expected attributes (/agents/:id/expectedattributes), resending its remote config
+	// (/agents/:id/resend-config), forcing it to reconnect (/agents/:id/reconnect), setting
+	// its direct config (/agents/:id/config), re-propagating an agent group
+	// (/agentgroups/:name/propagate), applying it to given agents (/agentgroups/:name/apply),
+	// advancing its rollout (/agentgroups/:name/rollout), rolling it back
+	// (/agentgroups/:name/rollback) or verifying an agent package
+	// (/agentpackages/:name/verify) modifies the resource, so every verb requires UPDATE
+	// rather than CREATE/DELETE.
+	if len(parts) == minParts+2 && method != http.MethodGet &&
+		(parts[minParts+1] == "quarantine" || parts[minParts+1] == "expectedattributes" ||
+			parts[minParts+1] == "resend-config" || parts[minParts+1] == "reconnect" ||
+			parts[minParts+1] == "config" ||
+			parts[minParts+1] == "propagate" || parts[minParts+1] == "apply" ||
+			parts[minParts+1] == "rollout" || parts[minParts+1] == "rollback" ||
+			parts[minParts+1] == "verify") {
+		return resource, "UPDATE"
+	}
+
+	// Requesting a report from an agent (/agents/:id/report/<kind>) queues a command on the
+	// existing agent, so it requires UPDATE like quarantining it rather than CREATE.
+	if len(parts) == minParts+3 && method != http.MethodGet && parts[minParts+1] == "report" {
+		return resource, "UPDATE"
+	}
+
+	// Searching agents (/agents/search) and listing them by package (/agents/by-package)
+	// read the collection, so they take LIST like the plain listing.
 	isCollection := len(parts) == minParts ||
-		(len(parts) == minParts+1 && parts[minParts] == "search")
+		(len(parts) == minParts+1 && (parts[minParts] == "search" || parts[minParts] == "by-package"))
 
 	return resource, methodToAction(method, isCollection)
 }
@@ -226,6 +256,32 @@ func extractGlobalResourceAndAction(fullPath, method string) (string, string) {
 		return "", ""
 	}
 
+	// Revoking an agent (/agents/:id/revoke) and lifting the revocation edit a blacklist
+	// shared by every namespace, so they are checked against the agentrevocation resource.
+	if len(parts) == minParts+2 && parts[3] == "agents" && parts[minParts+1] == "revoke" {
+		return usermodel.ResourceAgentRevocation, methodToAction(method, false)
+	}
+
+	// The capability matrix (/agents/capabilities) and the attribute catalog
+	// (/agents/attributes) list agents of every namespace, so they take agent:LIST across
+	// every namespace like the namespaced listing.
+	if len(parts) == minParts+1 && parts[3] == "agents" &&
+		(parts[minParts] == "capabilities" || parts[minParts] == "attributes") {
+		return "agent", methodToAction(method, true)
+	}
+
+	// The fleet summary (/summary) counts the agents of every namespace, so it takes
+	// agent:LIST across every namespace like the attribute catalog.
+	if len(parts) == minParts && parts[3] == "summary" {
+		return "agent", "LIST"
+	}
+
+	// Previewing a selector (/selectors/preview) only lists the agents it matches across
+	// every namespace, so it takes agent:LIST although it is a POST.
+	if len(parts) == minParts+1 && parts[3] == "selectors" && parts[minParts] == "preview" {
+		return "agent", "LIST"
+	}
+
 	resource, ok := globalResourceSingular(parts[3])
 	if !ok {
 		return "", ""
@@ -267,6 +323,16 @@ func globalResourceSingular(plural string) (string, bool) {
 		return "server", true
 	case "roles":
 		return "role", true
+	case "quotas":
+		return usermodel.ResourceQuota, true
+	case "agents":
+		// The agent routes that are not namespaced are matched above; any other one is
+		// checked against the agent resource across every namespace.
+		return "agent", true
+	case "export", "import":
+		// The backup bundle spans every namespace and carries certificate private
+		// keys, so it is a global resource: export needs backup:LIST, import backup:CREATE.
+		return usermodel.ResourceBackup, true
 	default:
 		return "", false
 	}
@@ -1,16 +1,20 @@
 package security_test
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	usermodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user"
 	userport "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user/port"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
 )
@@ -94,3 +98,292 @@ func TestAuthorizationMiddleware_UsersMe_RequiresAuthentication(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+type recordingRBACUsecase struct {
+	userport.RBACUsecase
+
+	namespace, resource, action string
+}
+
+func (r *recordingRBACUsecase) CheckPermission(
+	_ context.Context, _ uuid.UUID, namespace, resource, action string,
+) (bool, error) {
+	r.namespace, r.resource, r.action = namespace, resource, action
+
+	return true, nil
+}
+
+type stubUserUsecase struct {
+	userport.UserUsecase
+}
+
+func (stubUserUsecase) GetUserByEmail(_ context.Context, email string) (*usermodel.User, error) {
+	return usermodel.NewUser(email, email), nil
+}
+
+func TestAuthorizationMiddleware_GlobalAgentRoutes(t *testing.T) {
+	t.Parallel()
+
+	for path, route := range map[string]struct {
+		method string
+		want   [2]string
+	}{
+		"/api/v1/agents/:id/revoke":   {http.MethodPost, [2]string{"agentrevocation", "CREATE"}},
+		"/api/v1/agents/capabilities": {http.MethodGet, [2]string{"agent", "LIST"}},
+		"/api/v1/agents/attributes":   {http.MethodGet, [2]string{"agent", "LIST"}},
+		"/api/v1/selectors/preview":   {http.MethodPost, [2]string{"agent", "LIST"}},
+		"/api/v1/summary":             {http.MethodGet, [2]string{"agent", "LIST"}},
+		// A route added under /api/v1/agents without its own rule falls back to the agent.
+		"/api/v1/agents/:id/unmatched": {http.MethodGet, [2]string{"agent", "GET"}},
+	} {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			email := "user@example.com"
+			rbac := &recordingRBACUsecase{}
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+				ctx.Next()
+			})
+			router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+			router.Handle(route.method, path, func(ctx *gin.Context) {
+				ctx.Status(http.StatusAccepted)
+			})
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), route.method,
+				strings.Replace(path, ":id", uuid.NewString(), 1), nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, "*", rbac.namespace)
+			assert.Equal(t, route.want[0], rbac.resource)
+			assert.Equal(t, route.want[1], rbac.action)
+		})
+	}
+}
+
+func TestAuthorizationMiddleware_ResourceQuotaRoute(t *testing.T) {
+	t.Parallel()
+
+	email := "user@example.com"
+	rbac := &recordingRBACUsecase{}
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+		ctx.Next()
+	})
+	router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+	router.GET("/api/v1/quotas", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/api/v1/quotas", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", rbac.namespace)
+	assert.Equal(t, "quota", rbac.resource)
+	assert.Equal(t, "LIST", rbac.action)
+}
+
+func TestAuthorizationMiddleware_AgentExpectedAttributesRoute(t *testing.T) {
+	t.Parallel()
+
+	const path = "/api/v1/namespaces/:namespace/agents/:id/expectedattributes"
+
+	email := "user@example.com"
+	rbac := &recordingRBACUsecase{}
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+		ctx.Next()
+	})
+	router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+	router.PUT(path, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPut,
+		"/api/v1/namespaces/prod/agents/"+uuid.NewString()+"/expectedattributes", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "prod", rbac.namespace)
+	assert.Equal(t, "agent", rbac.resource)
+	assert.Equal(t, "UPDATE", rbac.action)
+}
+
+func TestAuthorizationMiddleware_NamespacedAgentRoutes(t *testing.T) {
+	t.Parallel()
+
+	for path, route := range map[string]struct {
+		method string
+		want   string
+	}{
+		"/api/v1/namespaces/:namespace/agents/:id/resend-config":          {http.MethodPost, "UPDATE"},
+		"/api/v1/namespaces/:namespace/agents/:id/reconnect":              {http.MethodPost, "UPDATE"},
+		"/api/v1/namespaces/:namespace/agents/:id/config":                 {http.MethodPut, "UPDATE"},
+		"/api/v1/namespaces/:namespace/agents/:id/desired-config":         {http.MethodGet, "GET"},
+		"/api/v1/namespaces/:namespace/agents/:id/effective-config/watch": {http.MethodGet, "GET"},
+	} {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			email := "user@example.com"
+			rbac := &recordingRBACUsecase{}
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+				ctx.Next()
+			})
+			router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+			router.Handle(route.method, path, func(ctx *gin.Context) {
+				ctx.Status(http.StatusAccepted)
+			})
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), route.method,
+				strings.NewReplacer(":namespace", "prod", ":id", uuid.NewString()).Replace(path), nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, "prod", rbac.namespace)
+			assert.Equal(t, "agent", rbac.resource)
+			assert.Equal(t, route.want, rbac.action)
+		})
+	}
+}
+
+func TestAuthorizationMiddleware_AgentReportRoute(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range []string{"effective-config", "health", "available-components"} {
+		t.Run(kind, func(t *testing.T) {
+			t.Parallel()
+
+			email := "user@example.com"
+			rbac := &recordingRBACUsecase{}
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+				ctx.Next()
+			})
+			router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+			router.POST("/api/v1/namespaces/:namespace/agents/:id/report/"+kind, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+				"/api/v1/namespaces/prod/agents/"+uuid.NewString()+"/report/"+kind, nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "prod", rbac.namespace)
+			assert.Equal(t, "agent", rbac.resource)
+			assert.Equal(t, "UPDATE", rbac.action)
+		})
+	}
+}
+
+func TestAuthorizationMiddleware_AgentGroupPropagateRoute(t *testing.T) {
+	t.Parallel()
+
+	// Advancing a rollout (/agentgroups/:name/rollout) or rolling back the group's configs
+	// (/agentgroups/:name/rollback) modifies the group like propagating it.
+	for _, subresource := range []string{"propagate", "apply", "rollout", "rollback"} {
+		t.Run(subresource, func(t *testing.T) {
+			t.Parallel()
+
+			email := "user@example.com"
+			rbac := &recordingRBACUsecase{}
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+				ctx.Next()
+			})
+			router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+			router.POST("/api/v1/namespaces/:namespace/agentgroups/:name/"+subresource, func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+				"/api/v1/namespaces/prod/agentgroups/web/"+subresource, nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "prod", rbac.namespace)
+			assert.Equal(t, "agentgroup", rbac.resource)
+			assert.Equal(t, "UPDATE", rbac.action)
+		})
+	}
+}
+
+func TestAuthorizationMiddleware_AgentPackageVerifyRoute(t *testing.T) {
+	t.Parallel()
+
+	const path = "/api/v1/namespaces/:namespace/agentpackages/:name/verify"
+
+	email := "user@example.com"
+	rbac := &recordingRBACUsecase{}
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+		ctx.Next()
+	})
+	router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+	router.POST(path, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+		"/api/v1/namespaces/prod/agentpackages/otelcol/verify", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "prod", rbac.namespace)
+	assert.Equal(t, "agentpackage", rbac.resource)
+	assert.Equal(t, "UPDATE", rbac.action)
+}
+
+func TestAuthorizationMiddleware_AgentsByPackageRoute(t *testing.T) {
+	t.Parallel()
+
+	const path = "/api/v1/namespaces/:namespace/agents/by-package"
+
+	email := "user@example.com"
+	rbac := &recordingRBACUsecase{}
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		security.SetUser(ctx, &security.User{Authenticated: true, Email: &email})
+		ctx.Next()
+	})
+	router.Use(security.NewAuthorizationMiddleware(rbac, stubUserUsecase{}, adminEmail, slog.Default()))
+	router.GET(path, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/api/v1/namespaces/prod/agents/by-package?name=otelcol", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "prod", rbac.namespace)
+	assert.Equal(t, "agent", rbac.resource)
+	assert.Equal(t, "LIST", rbac.action)
+}
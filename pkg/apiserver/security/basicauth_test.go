@@ -30,7 +30,10 @@ func newBasicAuthService(t *testing.T, pepper string, repo *inmemory.UserReposit
 		AdminSettings: security.AdminSettings{Username: "admin", Password: "adminpass", Email: "admin@x"},
 	}
 
-	return security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg), repo)
+	svc, err := security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg), repo)
+	require.NoError(t, err)
+
+	return svc
 }
 
 // Regression: when no pepper is configured, a failed/non-admin basic login must surface as
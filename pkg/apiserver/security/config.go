@@ -57,9 +57,15 @@ type OAuthSettings struct {
 
 // JWTSettings holds the configuration settings for JSON Web Tokens (JWT).
 type JWTSettings struct {
+	// SigningAlgorithm is SigningAlgorithmHS256 (the default when empty) or SigningAlgorithmRS256.
+	SigningAlgorithm string
+	// SigningKey is the shared HMAC secret used with HS256.
 	SigningKey string
-	Issuer     string
-	Expiration time.Duration
+	// PrivateKeyFile is the path of the PEM-encoded RSA private key used with RS256.
+	// Its public key is published at /.well-known/jwks.json.
+	PrivateKeyFile string
+	Issuer         string
+	Expiration     time.Duration
 	// RefreshExpiration is the lifetime of refresh tokens issued alongside access tokens.
 	// Zero means refresh tokens are disabled.
 	RefreshExpiration time.Duration
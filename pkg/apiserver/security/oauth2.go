@@ -183,12 +183,8 @@ func (s *Service) validateState(state string) error {
 }
 
 func (s *Service) parseStateClaims(state string) (*OAuthStateClaims, error) {
-	token, err := jwt.ParseWithClaims(state,
-		//exhaustruct:ignore
-		&OAuthStateClaims{},
-		func(_ *jwt.Token) (any, error) {
-			return []byte(s.oauthStateSettings.SigningKey), nil
-		})
+	//exhaustruct:ignore
+	token, err := s.tokenSigner.parse(state, &OAuthStateClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JWT token for state: %w", err)
 	}
@@ -247,9 +243,7 @@ func (s *Service) createState(cliRedirect string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	ss, err := token.SignedString([]byte(s.oauthStateSettings.SigningKey))
+	ss, err := s.tokenSigner.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT token for state: %w", err)
 	}
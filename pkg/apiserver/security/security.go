@@ -77,6 +77,7 @@ type Service struct {
 	oauthStateSettings   JWTSettings
 	adminSettings        AdminSettings
 	tokenSettings        JWTSettings
+	tokenSigner          *tokenSigner
 	httpClient           *http.Client
 	allowedRedirectHosts []string
 	passwordHasher       *PasswordHasher
@@ -101,13 +102,19 @@ type OPAMPClaims struct {
 }
 
 // New creates a new instance of the Service struct with the provided logger and OAuth settings.
+// It returns an error when the JWT signing algorithm is unsupported or its key cannot be loaded.
 func New(
 	logger *slog.Logger,
 	settings *Config,
 	httpClient *http.Client,
 	passwordHasher *PasswordHasher,
 	userPort userport.UserPersistencePort,
-) *Service {
+) (*Service, error) {
+	tokenSigner, err := newTokenSigner(settings.JWTSettings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT settings: %w", err)
+	}
+
 	var oauth2Cfg *oauth2.Config
 
 	var allowedRedirectHosts []string
@@ -129,11 +136,22 @@ func New(
 		oauthStateSettings:   settings.JWTSettings,
 		adminSettings:        settings.AdminSettings,
 		tokenSettings:        settings.JWTSettings,
+		tokenSigner:          tokenSigner,
 		httpClient:           httpClient,
 		allowedRedirectHosts: allowedRedirectHosts,
 		passwordHasher:       passwordHasher,
 		userPort:             userPort,
+	}, nil
+}
+
+// VerificationKeys returns the public keys that verify the tokens the server signs, for
+// publishing as a JWKS. It is empty with HS256, whose shared secret must never be published.
+func (s *Service) VerificationKeys() []VerificationKey {
+	if s.tokenSigner.publicKey == nil {
+		return []VerificationKey{}
 	}
+
+	return []VerificationKey{*s.tokenSigner.publicKey}
 }
 
 // ValidateToken validates the provided JWT token string and returns the claims if valid.
@@ -262,12 +280,8 @@ func (s *Service) issueBasicAuthLogin(username, email string) (LoginResult, erro
 }
 
 func (s *Service) parseClaims(tokenString string) (*OPAMPClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString,
-		//exhaustruct:ignore
-		&OPAMPClaims{},
-		func(_ *jwt.Token) (any, error) {
-			return []byte(s.tokenSettings.SigningKey), nil
-		})
+	//exhaustruct:ignore
+	token, err := s.tokenSigner.parse(tokenString, &OPAMPClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JWT token: %w", err)
 	}
@@ -341,9 +355,7 @@ func (s *Service) newOPAMPClaims(email, tokenType string, expiration time.Durati
 }
 
 func (s *Service) createToken(claims *OPAMPClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString([]byte(s.tokenSettings.SigningKey))
+	tokenString, err := s.tokenSigner.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT token: %w", err)
 	}
@@ -0,0 +1,133 @@
+package security
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms accepted in JWTSettings.SigningAlgorithm.
+const (
+	// SigningAlgorithmHS256 signs tokens with HMAC-SHA256 and the shared JWTSettings.SigningKey.
+	SigningAlgorithmHS256 = "HS256"
+	// SigningAlgorithmRS256 signs tokens with RSA-SHA256 and the private key in
+	// JWTSettings.PrivateKeyFile, so that other services can verify them with the public key.
+	SigningAlgorithmRS256 = "RS256"
+)
+
+var (
+	// ErrUnsupportedSigningAlgorithm is returned when JWTSettings.SigningAlgorithm is neither HS256 nor RS256.
+	ErrUnsupportedSigningAlgorithm = errors.New("unsupported JWT signing algorithm")
+	// ErrMissingPrivateKey is returned when RS256 is chosen without a private key file.
+	ErrMissingPrivateKey = errors.New("RS256 requires a PEM private key file")
+)
+
+// VerificationKey is a public key that verifies the tokens the server signs.
+type VerificationKey struct {
+	// KeyID is the RFC 7638 thumbprint of the key, set as the "kid" header of every token it verifies.
+	KeyID string
+	// Algorithm is the JWS algorithm the key is used with.
+	Algorithm string
+	// PublicKey is the RSA public key.
+	PublicKey *rsa.PublicKey
+}
+
+// tokenSigner signs and verifies JWTs with the algorithm and key of one JWTSettings.
+type tokenSigner struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	// publicKey is nil for symmetric algorithms, which have nothing to publish.
+	publicKey *VerificationKey
+}
+
+func newTokenSigner(settings JWTSettings) (*tokenSigner, error) {
+	switch settings.SigningAlgorithm {
+	case "", SigningAlgorithmHS256:
+		return &tokenSigner{
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(settings.SigningKey),
+			verifyKey: []byte(settings.SigningKey),
+			publicKey: nil,
+		}, nil
+	case SigningAlgorithmRS256:
+		if settings.PrivateKeyFile == "" {
+			return nil, ErrMissingPrivateKey
+		}
+
+		pemBytes, err := os.ReadFile(settings.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+		}
+
+		return &tokenSigner{
+			method:    jwt.SigningMethodRS256,
+			signKey:   privateKey,
+			verifyKey: &privateKey.PublicKey,
+			publicKey: &VerificationKey{
+				KeyID:     rsaThumbprint(&privateKey.PublicKey),
+				Algorithm: SigningAlgorithmRS256,
+				PublicKey: &privateKey.PublicKey,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSigningAlgorithm, settings.SigningAlgorithm)
+	}
+}
+
+// sign signs claims, setting the "kid" header when the key is published.
+func (s *tokenSigner) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.publicKey != nil {
+		token.Header["kid"] = s.publicKey.KeyID
+	}
+
+	signed, err := token.SignedString(s.signKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// parse parses and verifies tokenString into claims. Tokens signed with any other
+// algorithm are rejected, so an RS256 public key can never be used as an HMAC secret.
+func (s *tokenSigner) parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(tokenString, claims,
+		func(_ *jwt.Token) (any, error) {
+			return s.verifyKey, nil
+		},
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	return token, nil
+}
+
+// rsaThumbprint returns the base64url-encoded RFC 7638 SHA-256 thumbprint of key.
+func rsaThumbprint(key *rsa.PublicKey) string {
+	// RFC 7638 hashes the required members in lexicographic order without whitespace.
+	canonical := `{"e":"` + encodeJWKInt(big.NewInt(int64(key.E))) +
+		`","kty":"RSA","n":"` + encodeJWKInt(key.N) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// encodeJWKInt encodes an integer as the unpadded base64url big-endian value used by JWK "n" and "e".
+func encodeJWKInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
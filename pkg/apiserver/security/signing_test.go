@@ -0,0 +1,174 @@
+package security_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/adapter/secondary/persistence/inmemory"
+	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
+)
+
+const (
+	signingAdminUsername = "admin"
+	signingAdminPassword = "adminpass"
+	signingAdminEmail    = "admin@x"
+)
+
+// writePrivateKey writes a fresh PKCS#1 PEM RSA private key into a temp file and returns
+// the key and the path.
+func writePrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, pemBytes, 0o600))
+
+	return key, path
+}
+
+func newSigningService(t *testing.T, settings security.JWTSettings) (*security.Service, error) {
+	t.Helper()
+
+	settings.Issuer = "test"
+	settings.Expiration = time.Minute
+
+	//exhaustruct:ignore
+	cfg := &security.Config{
+		JWTSettings: settings,
+		AdminSettings: security.AdminSettings{
+			Username: signingAdminUsername,
+			Password: signingAdminPassword,
+			Email:    signingAdminEmail,
+		},
+	}
+
+	return security.New(slog.Default(), cfg, http.DefaultClient, security.NewPasswordHasher(cfg),
+		inmemory.NewUserRepository())
+}
+
+func TestService_HS256SignsAndVerifies(t *testing.T) {
+	t.Parallel()
+
+	//exhaustruct:ignore
+	svc, err := newSigningService(t, security.JWTSettings{SigningKey: "test-signing-key"})
+	require.NoError(t, err)
+
+	result, err := svc.BasicAuth(t.Context(), signingAdminUsername, signingAdminPassword)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateToken(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, signingAdminEmail, claims.Email)
+
+	token, _, err := jwt.NewParser().ParseUnverified(result.Token, &security.OPAMPClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", token.Method.Alg())
+	assert.Empty(t, svc.VerificationKeys(), "a shared secret is never published")
+}
+
+func TestService_RS256SignsAndVerifies(t *testing.T) {
+	t.Parallel()
+
+	key, path := writePrivateKey(t)
+
+	//exhaustruct:ignore
+	svc, err := newSigningService(t, security.JWTSettings{
+		SigningAlgorithm: security.SigningAlgorithmRS256,
+		PrivateKeyFile:   path,
+	})
+	require.NoError(t, err)
+
+	result, err := svc.BasicAuth(t.Context(), signingAdminUsername, signingAdminPassword)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateToken(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, signingAdminEmail, claims.Email)
+
+	keys := svc.VerificationKeys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, security.SigningAlgorithmRS256, keys[0].Algorithm)
+	assert.True(t, key.PublicKey.Equal(keys[0].PublicKey))
+
+	// A third party holding only the published public key can verify the token.
+	//exhaustruct:ignore
+	token, err := jwt.ParseWithClaims(result.Token, &security.OPAMPClaims{}, func(token *jwt.Token) (any, error) {
+		assert.Equal(t, keys[0].KeyID, token.Header["kid"])
+
+		return keys[0].PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+}
+
+func TestService_RS256RejectsHS256Tokens(t *testing.T) {
+	t.Parallel()
+
+	_, path := writePrivateKey(t)
+
+	//exhaustruct:ignore
+	rsService, err := newSigningService(t, security.JWTSettings{
+		SigningAlgorithm: security.SigningAlgorithmRS256,
+		PrivateKeyFile:   path,
+	})
+	require.NoError(t, err)
+
+	//exhaustruct:ignore
+	hsService, err := newSigningService(t, security.JWTSettings{SigningKey: "test-signing-key"})
+	require.NoError(t, err)
+
+	result, err := hsService.BasicAuth(t.Context(), signingAdminUsername, signingAdminPassword)
+	require.NoError(t, err)
+
+	_, err = rsService.ValidateToken(result.Token)
+	require.Error(t, err)
+}
+
+func TestNew_InvalidSigningSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		//exhaustruct:ignore
+		_, err := newSigningService(t, security.JWTSettings{SigningAlgorithm: "ES256"})
+		require.ErrorIs(t, err, security.ErrUnsupportedSigningAlgorithm)
+	})
+
+	t.Run("RS256 without a private key", func(t *testing.T) {
+		t.Parallel()
+
+		//exhaustruct:ignore
+		_, err := newSigningService(t, security.JWTSettings{SigningAlgorithm: security.SigningAlgorithmRS256})
+		require.ErrorIs(t, err, security.ErrMissingPrivateKey)
+	})
+
+	t.Run("RS256 with a malformed private key", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "jwt.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+
+		//exhaustruct:ignore
+		_, err := newSigningService(t, security.JWTSettings{
+			SigningAlgorithm: security.SigningAlgorithmRS256,
+			PrivateKeyFile:   path,
+		})
+		require.Error(t, err)
+	})
+}
@@ -63,11 +63,13 @@ func NewAnonymousUser() *User {
 
 //nolint:gochecknoglobals
 var jwtBypassPrefixes = []string{
+	"/.well-known",
 	"/auth",
 	"/api/v1/auth/basic",
 	"/api/v1/auth/github",
 	"/api/v1/ping",
 	"/api/v1/opamp",
+	"/api/v1/openapi.json",
 	"/api/v1/version",
 	"/healthz",
 	"/readyz",
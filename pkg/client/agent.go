@@ -20,12 +20,41 @@ const (
 	ListAgentURL = "/api/v1/namespaces/{namespace}/agents"
 	// SearchAgentURL is the path to search agents in a namespace.
 	SearchAgentURL = "/api/v1/namespaces/{namespace}/agents/search"
+	// ListAgentsByPackageURL is the path to list the agents reporting a package in a namespace.
+	ListAgentsByPackageURL = "/api/v1/namespaces/{namespace}/agents/by-package"
 	// GetAgentURL is the path to get an agent by ID in a namespace.
 	GetAgentURL = agentByIDURL
 	// UpdateAgentURL is the path to update an agent in a namespace.
 	UpdateAgentURL = agentByIDURL
 	// DeleteAgentURL is the path to delete an agent by ID in a namespace.
 	DeleteAgentURL = agentByIDURL
+	// AgentQuarantineURL is the path to quarantine or unquarantine an agent in a namespace.
+	AgentQuarantineURL = agentByIDURL + "/quarantine"
+	// AgentExpectedAttributesURL is the path to set the expected attributes of an agent in a namespace.
+	AgentExpectedAttributesURL = agentByIDURL + "/expectedattributes"
+	// AgentUptimeURL is the path to get an agent's connection statistics in a namespace.
+	AgentUptimeURL = agentByIDURL + "/uptime"
+	// AgentEffectiveConfigHistoryURL is the path to get an agent's effective-config history in a namespace.
+	AgentEffectiveConfigHistoryURL = agentByIDURL + "/effective-config/history"
+	// AgentEffectiveConfigReportURL is the path to ask an agent to report its effective config again.
+	AgentEffectiveConfigReportURL = agentByIDURL + "/report/effective-config"
+	// AgentHealthReportURL is the path to ask an agent to report its health again.
+	AgentHealthReportURL = agentByIDURL + "/report/health"
+	// AgentAvailableComponentsReportURL is the path to ask an agent to report its available components again.
+	AgentAvailableComponentsReportURL = agentByIDURL + "/report/available-components"
+	// AgentRevocationURL is the path to revoke or unrevoke an agent instance UID. It is not
+	// namespaced because revocation applies wherever the agent connects.
+	AgentRevocationURL = "/api/v1/agents/{id}/revoke"
+	// AgentResendConfigURL is the path to offer an agent in a namespace its current remote config again.
+	AgentResendConfigURL = agentByIDURL + "/resend-config"
+	// AgentReconnectURL is the path to close the connection of an agent in a namespace so that it reconnects.
+	AgentReconnectURL = agentByIDURL + "/reconnect"
+	// AgentConfigURL is the path to set remote configs on an agent in a namespace directly, outside of
+	// any agent group.
+	AgentConfigURL = agentByIDURL + "/config"
+	// AgentDesiredConfigURL is the path to get the remote config the server intends to offer an agent
+	// in a namespace.
+	AgentDesiredConfigURL = agentByIDURL + "/desired-config"
 )
 
 // AgentService provides methods to interact with agents.
@@ -102,6 +131,15 @@ func (s *AgentService) ListAgents(
 	return &result, nil
 }
 
+// ListAllAgents lists every agents in a namespace, following continue tokens across pages.
+func (s *AgentService) ListAllAgents(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.Agent, error) {
+	return ListAll(ctx, namespaced(s.ListAgents, namespace), opts...)
+}
+
 // SearchAgents searches agents by query in a namespace.
 func (s *AgentService) SearchAgents(
 	ctx context.Context,
@@ -135,6 +173,45 @@ func (s *AgentService) SearchAgents(
 	return &result, nil
 }
 
+// ListAgentsByPackage lists the agents in a namespace that report the named package at
+// packageVersion, or at any version when packageVersion is empty.
+func (s *AgentService) ListAgentsByPackage(
+	ctx context.Context,
+	namespace string,
+	packageName string,
+	packageVersion string,
+	opts ...ListOption,
+) (*AgentListResponse, error) {
+	listSettings := newListSettings(opts)
+
+	var result AgentListResponse
+
+	req := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetQueryParam("name", packageName).
+		SetResult(&result)
+	if packageVersion != "" {
+		req.SetQueryParam("version", packageVersion)
+	}
+
+	listSettings.applyTo(req)
+
+	response, err := req.Get(ListAgentsByPackageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents by package: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
 // DeleteAgent deletes a disconnected agent by its namespace and ID.
 // The server rejects deletion of connected agents with a 409 Conflict.
 func (s *AgentService) DeleteAgent(
@@ -229,3 +306,366 @@ func (s *AgentService) RestartAgent(
 
 	return s.UpdateAgent(ctx, namespace, id, agent)
 }
+
+// QuarantineAgent quarantines an agent so its agent groups stop pushing new config to it.
+func (s *AgentService) QuarantineAgent(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+	reason string,
+) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetBody(&v1.AgentQuarantineRequest{Reason: reason}).
+		SetResult(&result).
+		Post(AgentQuarantineURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quarantine agent: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// SetAgentExpectedAttributes replaces the attributes an agent is expected to keep reporting.
+// An empty map removes every expectation.
+func (s *AgentService) SetAgentExpectedAttributes(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+	attributes map[string]string,
+) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetBody(&v1.AgentExpectedAttributesRequest{Attributes: attributes}).
+		SetResult(&result).
+		Put(AgentExpectedAttributesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set agent expected attributes: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// UnquarantineAgent lifts an agent's quarantine.
+func (s *AgentService) UnquarantineAgent(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Delete(AgentQuarantineURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unquarantine agent: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// RequestEffectiveConfigReport asks an agent to report its effective config again.
+func (s *AgentService) RequestEffectiveConfigReport(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.Agent, error) {
+	return s.requestReport(ctx, namespace, id, AgentEffectiveConfigReportURL)
+}
+
+// RequestHealthReport asks an agent to report its health again.
+func (s *AgentService) RequestHealthReport(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.Agent, error) {
+	return s.requestReport(ctx, namespace, id, AgentHealthReportURL)
+}
+
+// RequestAvailableComponentsReport asks an agent to report its available components again.
+func (s *AgentService) RequestAvailableComponentsReport(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.Agent, error) {
+	return s.requestReport(ctx, namespace, id, AgentAvailableComponentsReportURL)
+}
+
+func (s *AgentService) requestReport(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+	url string,
+) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Post(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request agent report: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// ResendAgentRemoteConfig offers an agent its current desired remote config again.
+func (s *AgentService) ResendAgentRemoteConfig(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Post(AgentResendConfigURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resend agent remote config: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// ReconnectAgent closes an agent's connection so that it reconnects.
+func (s *AgentService) ReconnectAgent(ctx context.Context, namespace string, id uuid.UUID) (*v1.Agent, error) {
+	var result v1.Agent
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Post(AgentReconnectURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconnect agent: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// GetAgentDesiredConfig retrieves the remote config the server intends to offer an agent,
+// with the source of each entry.
+func (s *AgentService) GetAgentDesiredConfig(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.AgentDesiredConfig, error) {
+	var result v1.AgentDesiredConfig
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Get(AgentDesiredConfigURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent desired config: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// SetAgentConfig replaces the remote configs set on an agent directly, outside of any agent
+// group, and returns the agent's resulting desired config. An empty config map removes them.
+func (s *AgentService) SetAgentConfig(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+	configMap *v1.AgentConfigMap,
+) (*v1.AgentDesiredConfig, error) {
+	var result v1.AgentDesiredConfig
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetBody(configMap).
+		SetResult(&result).
+		Put(AgentConfigURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set agent config: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// RevokeAgent revokes an agent instance UID so the server refuses and closes its connections.
+func (s *AgentService) RevokeAgent(
+	ctx context.Context,
+	id uuid.UUID,
+	reason string,
+) (*v1.AgentRevocation, error) {
+	var result v1.AgentRevocation
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("id", id.String()).
+		SetBody(&v1.AgentRevocationRequest{Reason: reason}).
+		SetResult(&result).
+		Post(AgentRevocationURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke agent: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// UnrevokeAgent removes the revocation of an agent instance UID.
+func (s *AgentService) UnrevokeAgent(
+	ctx context.Context,
+	id uuid.UUID,
+) error {
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("id", id.String()).
+		Delete(AgentRevocationURL)
+	if err != nil {
+		return fmt.Errorf("failed to unrevoke agent: %w", err)
+	}
+
+	if response.IsError() {
+		return &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return nil
+}
+
+// GetAgentUptime retrieves an agent's connection statistics over time.
+func (s *AgentService) GetAgentUptime(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.AgentUptime, error) {
+	var result v1.AgentUptime
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Get(AgentUptimeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent uptime: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
+
+// GetAgentEffectiveConfigHistory retrieves the effective configs an agent reported over time.
+func (s *AgentService) GetAgentEffectiveConfigHistory(
+	ctx context.Context,
+	namespace string,
+	id uuid.UUID,
+) (*v1.AgentEffectiveConfigHistory, error) {
+	var result v1.AgentEffectiveConfigHistory
+
+	response, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetPathParam("namespace", namespace).
+		SetPathParam("id", id.String()).
+		SetResult(&result).
+		Get(AgentEffectiveConfigHistoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent effective config history: %w", err)
+	}
+
+	if response.IsError() {
+		return nil, &ResponseError{
+			StatusCode:   response.StatusCode(),
+			ErrorMessage: response.String(),
+		}
+	}
+
+	return &result, nil
+}
@@ -103,6 +103,15 @@ func (s *AgentGroupService) ListAgentGroups(
 	return &listResponse, nil
 }
 
+// ListAllAgentGroups lists every agent groups in a namespace, following continue tokens across pages.
+func (s *AgentGroupService) ListAllAgentGroups(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.AgentGroup, error) {
+	return ListAll(ctx, namespaced(s.ListAgentGroups, namespace), opts...)
+}
+
 // ListAgentsByAgentGroup lists agents belonging to a specific agent group.
 func (s *AgentGroupService) ListAgentsByAgentGroup(
 	ctx context.Context,
@@ -4,6 +4,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 )
@@ -19,6 +20,8 @@ const (
 	UpdateAgentPackageURL = "/api/v1/namespaces/{namespace}/agentpackages/{id}"
 	// DeleteAgentPackageURL is the path to delete an agent package.
 	DeleteAgentPackageURL = "/api/v1/namespaces/{namespace}/agentpackages/{id}"
+	// DeleteAgentPackagesURL is the path to delete the agent packages matching a selector.
+	DeleteAgentPackagesURL = "/api/v1/namespaces/{namespace}/agentpackages"
 )
 
 // AgentPackageService provides methods to interact with agent packages.
@@ -100,6 +103,15 @@ func (s *AgentPackageService) ListAgentPackages(
 	return &listResponse, nil
 }
 
+// ListAllAgentPackages lists every agent packages in a namespace, following continue tokens across pages.
+func (s *AgentPackageService) ListAllAgentPackages(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.AgentPackage, error) {
+	return ListAll(ctx, namespaced(s.ListAgentPackages, namespace), opts...)
+}
+
 // CreateAgentPackage creates a new agent package.
 func (s *AgentPackageService) CreateAgentPackage(
 	ctx context.Context,
@@ -180,3 +192,35 @@ func (s *AgentPackageService) DeleteAgentPackage(
 
 	return nil
 }
+
+// DeleteAgentPackagesBySelector deletes every agent package in the namespace whose attributes match
+// selector, and returns how many were deleted. The server rejects an empty selector.
+func (s *AgentPackageService) DeleteAgentPackagesBySelector(
+	ctx context.Context,
+	namespace string,
+	selector map[string]string,
+) (*v1.DeleteCollectionResponse, error) {
+	var result v1.DeleteCollectionResponse
+
+	values := url.Values{}
+	addSelectorParams(values, "selector", selector)
+
+	res, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetPathParam("namespace", namespace).
+		SetQueryParamsFromValues(values).
+		Delete(DeleteAgentPackagesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete agent packages by selector(restyError): %w", err)
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to delete agent packages by selector(responseError): %w", &ResponseError{
+			StatusCode:   res.StatusCode(),
+			ErrorMessage: res.String(),
+		})
+	}
+
+	return &result, nil
+}
@@ -110,6 +110,15 @@ func (s *AgentRemoteConfigService) ListAgentRemoteConfigs(
 	return &listResponse, nil
 }
 
+// ListAllAgentRemoteConfigs lists every agent remote configs in a namespace, following continue tokens across pages.
+func (s *AgentRemoteConfigService) ListAllAgentRemoteConfigs(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.AgentRemoteConfig, error) {
+	return ListAll(ctx, namespaced(s.ListAgentRemoteConfigs, namespace), opts...)
+}
+
 // CreateAgentRemoteConfig creates a new agent remote config.
 func (s *AgentRemoteConfigService) CreateAgentRemoteConfig(
 	ctx context.Context,
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+const (
+	// ExportURL is the path to export the managed resources as a backup bundle.
+	ExportURL = "/api/v1/export"
+	// ImportURL is the path to restore the managed resources from a backup bundle.
+	ImportURL = "/api/v1/import"
+)
+
+// BackupService exports and imports backup bundles of the managed resources.
+type BackupService struct {
+	service *service
+}
+
+// NewBackupService creates a new BackupService.
+func NewBackupService(service *service) *BackupService {
+	return &BackupService{service: service}
+}
+
+// Export downloads a bundle of every agent group, agent remote config, certificate and
+// agent package.
+func (s *BackupService) Export(ctx context.Context) (*v1.BackupBundle, error) {
+	var bundle v1.BackupBundle
+
+	// Like reconcile, a large fleet can outlast the shared client's timeout; the
+	// context deadline is the only limit.
+	res, err := s.service.Resty.Clone().SetTimeout(0).R().
+		SetContext(ctx).
+		SetResult(&bundle).
+		Get(ExportURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export resources: %w", err)
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to export resources: %w", &ResponseError{
+			StatusCode:   res.StatusCode(),
+			ErrorMessage: res.String(),
+		})
+	}
+
+	return &bundle, nil
+}
+
+// Import restores the resources in bundle, creating missing ones and updating
+// ones that differ.
+func (s *BackupService) Import(ctx context.Context, bundle *v1.BackupBundle) (*v1.BackupImportResult, error) {
+	var result v1.BackupImportResult
+
+	res, err := s.service.Resty.Clone().SetTimeout(0).R().
+		SetContext(ctx).
+		SetBody(bundle).
+		SetResult(&result).
+		Post(ImportURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import resources: %w", err)
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to import resources: %w", &ResponseError{
+			StatusCode:   res.StatusCode(),
+			ErrorMessage: res.String(),
+		})
+	}
+
+	return &result, nil
+}
@@ -4,6 +4,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	v1 "github.com/minuk-dev/opampcommander/api/v1"
 )
@@ -19,6 +20,8 @@ const (
 	UpdateCertificateURL = "/api/v1/namespaces/{namespace}/certificates/{id}"
 	// DeleteCertificateURL is the path to delete a certificate.
 	DeleteCertificateURL = "/api/v1/namespaces/{namespace}/certificates/{id}"
+	// DeleteCertificatesURL is the path to delete the certificates matching a selector.
+	DeleteCertificatesURL = "/api/v1/namespaces/{namespace}/certificates"
 )
 
 // CertificateService provides methods to interact with certificates.
@@ -100,6 +103,15 @@ func (s *CertificateService) ListCertificates(
 	return &listResponse, nil
 }
 
+// ListAllCertificates lists every certificates in a namespace, following continue tokens across pages.
+func (s *CertificateService) ListAllCertificates(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.Certificate, error) {
+	return ListAll(ctx, namespaced(s.ListCertificates, namespace), opts...)
+}
+
 // CreateCertificate creates a new certificate.
 func (s *CertificateService) CreateCertificate(
 	ctx context.Context,
@@ -180,3 +192,35 @@ func (s *CertificateService) DeleteCertificate(
 
 	return nil
 }
+
+// DeleteCertificatesBySelector deletes every certificate in the namespace whose attributes match
+// selector, and returns how many were deleted. The server rejects an empty selector.
+func (s *CertificateService) DeleteCertificatesBySelector(
+	ctx context.Context,
+	namespace string,
+	selector map[string]string,
+) (*v1.DeleteCollectionResponse, error) {
+	var result v1.DeleteCollectionResponse
+
+	values := url.Values{}
+	addSelectorParams(values, "selector", selector)
+
+	res, err := s.service.Resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetPathParam("namespace", namespace).
+		SetQueryParamsFromValues(values).
+		Delete(DeleteCertificatesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete certificates by selector(restyError): %w", err)
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to delete certificates by selector(responseError): %w", &ResponseError{
+			StatusCode:   res.StatusCode(),
+			ErrorMessage: res.String(),
+		})
+	}
+
+	return &result, nil
+}
@@ -35,6 +35,7 @@ type Client struct {
 	RoleService              *RoleService
 	RoleBindingService       *RoleBindingService
 	ReconcileService         *ReconcileService
+	BackupService            *BackupService
 }
 
 type service struct {
@@ -46,6 +47,8 @@ func New(endpoint string, opt ...Option) *Client {
 	service := service{
 		Resty: resty.New().SetBaseURL(endpoint).SetTimeout(defaultHTTPTimeout),
 	}
+	setRetry(service.Resty, defaultMaxRetries, defaultRetryWaitTime, defaultRetryMaxWaitTime)
+
 	client := &Client{
 		Endpoint: endpoint,
 		common:   service,
@@ -66,6 +69,7 @@ func New(endpoint string, opt ...Option) *Client {
 		RoleService:              nil,
 		RoleBindingService:       nil,
 		ReconcileService:         nil,
+		BackupService:            nil,
 	}
 
 	for _, o := range opt {
@@ -87,6 +91,7 @@ func New(endpoint string, opt ...Option) *Client {
 	client.RoleService = NewRoleService(&service)
 	client.RoleBindingService = NewRoleBindingService(&service)
 	client.ReconcileService = NewReconcileService(&service)
+	client.BackupService = NewBackupService(&service)
 
 	return client
 }
@@ -134,6 +139,16 @@ func (c *Client) GetServerVersion(ctx context.Context) (*v1version.Info, error)
 	return &versionInfo, nil
 }
 
+// ListResourceQuotas retrieves the usage of every resource quota.
+func (c *Client) ListResourceQuotas(ctx context.Context) (*apiv1.ResourceQuotaListResponse, error) {
+	return listResources[apiv1.ResourceQuota](ctx, &c.common, "/api/v1/quotas", newListSettings(nil))
+}
+
+// GetSummary retrieves the summary of the agent fleet across all namespaces.
+func (c *Client) GetSummary(ctx context.Context) (*apiv1.Summary, error) {
+	return getResource[apiv1.Summary](ctx, &c.common, "/api/v1/summary", "")
+}
+
 // SetAuthToken sets the authentication token for the client.
 func (c *Client) SetAuthToken(bearerToken string) {
 	c.common.Resty.SetAuthToken(bearerToken)
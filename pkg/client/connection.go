@@ -94,3 +94,12 @@ func (s *ConnectionService) ListConnections(
 
 	return &result, nil
 }
+
+// ListAllConnections lists every connections in a namespace, following continue tokens across pages.
+func (s *ConnectionService) ListAllConnections(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.Connection, error) {
+	return ListAll(ctx, namespaced(s.ListConnections, namespace), opts...)
+}
@@ -42,6 +42,11 @@ func (s *ContainerService) ListContainers(ctx context.Context, opts ...ListOptio
 	return listResources[v1.Container](ctx, s.service, ListContainerURL, newListSettings(opts))
 }
 
+// ListAllContainers lists every containers, following continue tokens across pages.
+func (s *ContainerService) ListAllContainers(ctx context.Context, opts ...ListOption) ([]v1.Container, error) {
+	return ListAll(ctx, s.ListContainers, opts...)
+}
+
 // ListAgentsByContainer lists the agents running in a container.
 func (s *ContainerService) ListAgentsByContainer(
 	ctx context.Context,
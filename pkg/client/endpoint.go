@@ -104,6 +104,15 @@ func (s *EndpointService) ListEndpoints(
 	return &listResponse, nil
 }
 
+// ListAllEndpoints lists every endpoints in a namespace, following continue tokens across pages.
+func (s *EndpointService) ListAllEndpoints(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.Endpoint, error) {
+	return ListAll(ctx, namespaced(s.ListEndpoints, namespace), opts...)
+}
+
 // CreateEndpoint creates a new endpoint.
 func (s *EndpointService) CreateEndpoint(
 	ctx context.Context,
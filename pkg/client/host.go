@@ -42,6 +42,11 @@ func (s *HostService) ListHosts(ctx context.Context, opts ...ListOption) (*HostL
 	return listResources[v1.Host](ctx, s.service, ListHostURL, newListSettings(opts))
 }
 
+// ListAllHosts lists every hosts, following continue tokens across pages.
+func (s *HostService) ListAllHosts(ctx context.Context, opts ...ListOption) ([]v1.Host, error) {
+	return ListAll(ctx, s.ListHosts, opts...)
+}
+
 // ListAgentsByHost lists the agents running on a host.
 func (s *HostService) ListAgentsByHost(
 	ctx context.Context,
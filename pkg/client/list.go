@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// ListPageFunc fetches one page of a listing. Every ListXxx method of a resource service
+// fits it once its path arguments, such as the namespace, are bound.
+type ListPageFunc[T any] func(ctx context.Context, opts ...ListOption) (*v1.ListResponse[T], error)
+
+// Iterate returns an iterator over every item of a listing, following continue tokens so
+// the caller sees all pages as one sequence. Pages are fetched lazily, as the caller
+// consumes items, using opts (e.g. WithLimit to size pages) for every request.
+//
+// The iterator yields a non-nil error, with a zero item, at most once and then stops. It
+// stops with ctx's error when ctx is cancelled between pages, and with
+// ErrUnexpectedBehavior when the server hands back the continue token it was just given.
+func Iterate[T any](ctx context.Context, list ListPageFunc[T], opts ...ListOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var (
+			zero          T
+			continueToken string
+		)
+
+		for {
+			err := ctx.Err()
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to list all resources: %w", err))
+
+				return
+			}
+
+			pageOpts := opts
+			if continueToken != "" {
+				pageOpts = append(opts[:len(opts):len(opts)], WithContinueToken(continueToken))
+			}
+
+			page, err := list(ctx, pageOpts...)
+			if err != nil {
+				yield(zero, err)
+
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next := page.Metadata.Continue
+			if next == "" {
+				return
+			}
+
+			if next == continueToken {
+				yield(zero, fmt.Errorf("failed to list all resources: %w: repeated continue token %q",
+					ErrUnexpectedBehavior, next))
+
+				return
+			}
+
+			continueToken = next
+		}
+	}
+}
+
+// ListAll collects every item of a listing into one slice, following continue tokens.
+// See Iterate for how pages are fetched and when it fails.
+func ListAll[T any](ctx context.Context, list ListPageFunc[T], opts ...ListOption) ([]T, error) {
+	var items []T
+
+	for item, err := range Iterate(ctx, list, opts...) {
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// namespaced binds the namespace of a namespaced ListXxx method, giving a ListPageFunc.
+func namespaced[T any](
+	list func(ctx context.Context, namespace string, opts ...ListOption) (*v1.ListResponse[T], error),
+	namespace string,
+) ListPageFunc[T] {
+	return func(ctx context.Context, opts ...ListOption) (*v1.ListResponse[T], error) {
+		return list(ctx, namespace, opts...)
+	}
+}
@@ -0,0 +1,166 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/client"
+)
+
+// certificatePager serves the certificates of the "default" namespace in pages of pageSize,
+// using the index of a page's first item as its continue token, and records the query of
+// every request.
+type certificatePager struct {
+	mu       sync.Mutex
+	names    []string
+	pageSize int
+	queries  []string
+}
+
+func (p *certificatePager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r.URL.Path != "/api/v1/namespaces/default/certificates" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	p.queries = append(p.queries, r.URL.RawQuery)
+
+	start := 0
+	if token := r.URL.Query().Get("continue"); token != "" {
+		start, _ = strconv.Atoi(token)
+	}
+
+	end := min(start+p.pageSize, len(p.names))
+
+	response := client.CertificateListResponse{
+		Kind:       v1.CertificateKind,
+		APIVersion: v1.APIVersion,
+		Metadata:   v1.ListMeta{Continue: "", RemainingItemCount: int64(len(p.names) - end)},
+		Items:      nil,
+	}
+	if end < len(p.names) {
+		response.Metadata.Continue = strconv.Itoa(end)
+	}
+
+	for _, name := range p.names[start:end] {
+		var certificate v1.Certificate
+
+		certificate.Metadata.Name = name
+		certificate.Metadata.Namespace = "default"
+		response.Items = append(response.Items, certificate)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (p *certificatePager) recorded() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.queries
+}
+
+func certificateNames(certificates []v1.Certificate) []string {
+	names := make([]string, 0, len(certificates))
+	for _, certificate := range certificates {
+		names = append(names, certificate.Metadata.Name)
+	}
+
+	return names
+}
+
+func TestCertificateService_ListAllCertificates(t *testing.T) {
+	t.Parallel()
+
+	pager := &certificatePager{names: []string{"a", "b", "c", "d", "e", "f", "g"}, pageSize: 3}
+	server := httptest.NewServer(pager)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL)
+
+	certificates, err := cli.CertificateService.ListAllCertificates(t.Context(), "default", client.WithLimit(3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, certificateNames(certificates))
+	assert.Equal(t, []string{"limit=3", "continue=3&limit=3", "continue=6&limit=3"}, pager.recorded(),
+		"every page keeps the caller's options and follows the previous page's continue token")
+}
+
+func TestIterate_StopsEarly(t *testing.T) {
+	t.Parallel()
+
+	pager := &certificatePager{names: []string{"a", "b", "c", "d", "e", "f", "g"}, pageSize: 3}
+	server := httptest.NewServer(pager)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL)
+	list := func(ctx context.Context, opts ...client.ListOption) (*client.CertificateListResponse, error) {
+		return cli.CertificateService.ListCertificates(ctx, "default", opts...)
+	}
+
+	var names []string
+
+	for certificate, err := range client.Iterate(t.Context(), list) {
+		require.NoError(t, err)
+
+		names = append(names, certificate.Metadata.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Len(t, pager.recorded(), 1, "pages after the one being consumed are not fetched")
+}
+
+func TestIterate_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	pager := &certificatePager{names: []string{"a", "b", "c", "d", "e", "f", "g"}, pageSize: 3}
+	server := httptest.NewServer(pager)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL)
+	ctx, cancel := context.WithCancel(t.Context())
+
+	list := func(ctx context.Context, opts ...client.ListOption) (*client.CertificateListResponse, error) {
+		page, err := cli.CertificateService.ListCertificates(ctx, "default", opts...)
+
+		cancel()
+
+		return page, err
+	}
+
+	certificates, err := client.ListAll(ctx, list)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, certificates)
+	assert.Len(t, pager.recorded(), 1, "no page is fetched once the context is cancelled")
+}
+
+func TestIterate_RepeatedContinueToken(t *testing.T) {
+	t.Parallel()
+
+	list := func(context.Context, ...client.ListOption) (*client.CertificateListResponse, error) {
+		var page client.CertificateListResponse
+
+		page.Metadata.Continue = "stuck"
+
+		return &page, nil
+	}
+
+	_, err := client.ListAll(t.Context(), list)
+	require.ErrorIs(t, err, client.ErrUnexpectedBehavior)
+}
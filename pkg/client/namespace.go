@@ -57,6 +57,11 @@ func (s *NamespaceService) ListNamespaces(
 	)
 }
 
+// ListAllNamespaces lists every namespaces, following continue tokens across pages.
+func (s *NamespaceService) ListAllNamespaces(ctx context.Context, opts ...ListOption) ([]v1.Namespace, error) {
+	return ListAll(ctx, s.ListNamespaces, opts...)
+}
+
 // CreateNamespace creates a new namespace.
 func (s *NamespaceService) CreateNamespace(
 	ctx context.Context,
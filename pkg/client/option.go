@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/samber/mo"
@@ -65,6 +66,16 @@ func WithLogger(logger *slog.Logger) OptionFunc {
 	}
 }
 
+// WithRetry configures how requests the server answers with 429 Too Many Requests or
+// 503 Service Unavailable are retried: at most maxRetries times, waiting as long as the
+// server's Retry-After header asks, but at least waitTime and at most maxWaitTime.
+// maxRetries of 0 disables retries.
+func WithRetry(maxRetries int, waitTime, maxWaitTime time.Duration) OptionFunc {
+	return func(c *Client) {
+		setRetry(c.common.Resty, maxRetries, waitTime, maxWaitTime)
+	}
+}
+
 // ListOption is an interface for options that can be applied to list operations.
 type ListOption interface {
 	Apply(settings *ListSettings)
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// defaultMaxRetries caps how many times a request is retried after the server answers
+	// it with 429 Too Many Requests or 503 Service Unavailable.
+	defaultMaxRetries = 3
+	// defaultRetryWaitTime is the shortest wait before a retry, and the base of the
+	// exponential backoff used when the server sends no Retry-After header.
+	defaultRetryWaitTime = 500 * time.Millisecond
+	// defaultRetryMaxWaitTime is the longest wait before a retry, however long the server
+	// asks the client to wait.
+	defaultRetryMaxWaitTime = 30 * time.Second
+)
+
+// setRetry makes the resty client retry requests the server turned away with 429 or 503,
+// waiting for as long as the server's Retry-After header asks, within
+// [waitTime, maxWaitTime], and backing off exponentially when it sends none.
+// maxRetries of 0 disables retries.
+func setRetry(restyClient *resty.Client, maxRetries int, waitTime, maxWaitTime time.Duration) {
+	restyClient.
+		SetRetryCount(maxRetries).
+		SetRetryWaitTime(waitTime).
+		SetRetryMaxWaitTime(maxWaitTime).
+		SetRetryAfter(retryAfter)
+
+	restyClient.RetryConditions = []resty.RetryConditionFunc{isRetryableResponse}
+}
+
+// isRetryableResponse reports whether the server asked the client to come back later.
+// Transport errors are not retried, as the request may have reached the server.
+func isRetryableResponse(res *resty.Response, _ error) bool {
+	if res == nil || res.RawResponse == nil {
+		return false
+	}
+
+	return res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() == http.StatusServiceUnavailable
+}
+
+// retryAfter returns the wait the response's Retry-After header asks for, given either as
+// seconds or as an HTTP date. It returns 0, falling back to the exponential backoff, when
+// the header is absent, malformed or already past.
+func retryAfter(_ *resty.Client, res *resty.Response) (time.Duration, error) {
+	value := strings.TrimSpace(res.Header().Get("Retry-After"))
+	if value == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), nil
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,109 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1version "github.com/minuk-dev/opampcommander/api/v1/version"
+	"github.com/minuk-dev/opampcommander/pkg/client"
+)
+
+// attemptRecorder serves the queued status codes in order, then 200 with a version body,
+// and records when each request arrived.
+type attemptRecorder struct {
+	mu         sync.Mutex
+	statuses   []int
+	retryAfter string
+	attempts   []time.Time
+}
+
+func (a *attemptRecorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.attempts = append(a.attempts, time.Now())
+
+	if len(a.statuses) > 0 {
+		status := a.statuses[0]
+		a.statuses = a.statuses[1:]
+
+		w.Header().Set("Retry-After", a.retryAfter)
+		w.WriteHeader(status)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v1version.Info{GitVersion: "v1.0.0"})
+}
+
+func (a *attemptRecorder) recorded() []time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.attempts
+}
+
+func TestClient_RetriesAfterRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	recorder := &attemptRecorder{statuses: []int{http.StatusTooManyRequests}, retryAfter: "1"}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL, client.WithRetry(3, 10*time.Millisecond, 5*time.Second))
+
+	info, err := cli.GetServerVersion(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", info.GitVersion)
+
+	attempts := recorder.recorded()
+	require.Len(t, attempts, 2)
+	assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), time.Second,
+		"the retry waits for as long as Retry-After asks")
+}
+
+func TestClient_RetriesAreCapped(t *testing.T) {
+	t.Parallel()
+
+	recorder := &attemptRecorder{
+		statuses: []int{
+			http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+		},
+		retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+	}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL, client.WithRetry(2, time.Millisecond, 10*time.Millisecond))
+
+	_, err := cli.GetServerVersion(t.Context())
+
+	var responseErr *client.ResponseError
+
+	require.ErrorAs(t, err, &responseErr)
+	assert.Equal(t, http.StatusServiceUnavailable, responseErr.StatusCode)
+	assert.Len(t, recorder.recorded(), 3, "the request is sent once and retried twice")
+}
+
+func TestClient_DoesNotRetryOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	recorder := &attemptRecorder{statuses: []int{http.StatusInternalServerError}, retryAfter: "0"}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	cli := client.New(server.URL, client.WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+	_, err := cli.GetServerVersion(t.Context())
+	require.Error(t, err)
+	assert.Len(t, recorder.recorded(), 1)
+}
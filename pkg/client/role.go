@@ -46,6 +46,11 @@ func (s *RoleService) ListRoles(
 	)
 }
 
+// ListAllRoles lists every roles, following continue tokens across pages.
+func (s *RoleService) ListAllRoles(ctx context.Context, opts ...ListOption) ([]v1.Role, error) {
+	return ListAll(ctx, s.ListRoles, opts...)
+}
+
 // GetRole retrieves a role by its UID.
 func (s *RoleService) GetRole(ctx context.Context, uid string, opts ...GetOption) (*v1.Role, error) {
 	return getResource[v1.Role](ctx, s.service, GetRoleURL, uid, opts...)
@@ -100,6 +100,15 @@ func (s *RoleBindingService) ListRoleBindings(
 	return &listResponse, nil
 }
 
+// ListAllRoleBindings lists every role bindings in a namespace, following continue tokens across pages.
+func (s *RoleBindingService) ListAllRoleBindings(
+	ctx context.Context,
+	namespace string,
+	opts ...ListOption,
+) ([]v1.RoleBinding, error) {
+	return ListAll(ctx, namespaced(s.ListRoleBindings, namespace), opts...)
+}
+
 // CreateRoleBinding creates a new role binding.
 func (s *RoleBindingService) CreateRoleBinding(
 	ctx context.Context,
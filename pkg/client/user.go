@@ -47,6 +47,11 @@ func (s *UserService) ListUsers(
 	)
 }
 
+// ListAllUsers lists every users, following continue tokens across pages.
+func (s *UserService) ListAllUsers(ctx context.Context, opts ...ListOption) ([]v1.User, error) {
+	return ListAll(ctx, s.ListUsers, opts...)
+}
+
 // GetUser retrieves a user by its UID.
 func (s *UserService) GetUser(ctx context.Context, uid string, opts ...GetOption) (*v1.User, error) {
 	return getResource[v1.User](ctx, s.service, GetUserURL, uid, opts...)
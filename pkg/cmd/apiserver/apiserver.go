@@ -14,8 +14,10 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/minuk-dev/opampcommander/pkg/apiserver"
+	applicationhelper "github.com/minuk-dev/opampcommander/pkg/apiserver/application/helper"
 	appconfig "github.com/minuk-dev/opampcommander/pkg/apiserver/config"
 	agentmodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent"
+	modelagent "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/agent/agent"
 	usermodel "github.com/minuk-dev/opampcommander/pkg/apiserver/domain/user"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/management/observability"
 	"github.com/minuk-dev/opampcommander/pkg/apiserver/security"
@@ -26,21 +28,48 @@ type CommandOption struct {
 	configFilename string
 
 	// flags
-	Address  string `mapstructure:"address"`
+	Address        string   `mapstructure:"address"`
+	TrustedProxies []string `mapstructure:"trustedProxies"`
+	RequestTimeout struct {
+		Default time.Duration            `mapstructure:"default"`
+		Routes  map[string]time.Duration `mapstructure:"routes"`
+	} `mapstructure:"requestTimeout"`
+	OpAMP struct {
+		MaxMessageBytes       int64             `mapstructure:"maxMessageBytes"`
+		MaxConnectionsPerIP   int               `mapstructure:"maxConnectionsPerIP"`
+		MinReportInterval     time.Duration     `mapstructure:"minReportInterval"`
+		DisconnectGracePeriod time.Duration     `mapstructure:"disconnectGracePeriod"`
+		EnableCompression     bool              `mapstructure:"enableCompression"`
+		RequiredHeaders       map[string]string `mapstructure:"requiredHeaders"`
+		RequiredSubprotocol   string            `mapstructure:"requiredSubprotocol"`
+
+		EffectiveConfigOnChangeOnly   bool          `mapstructure:"effectiveConfigOnChangeOnly"`
+		EffectiveConfigSampleInterval time.Duration `mapstructure:"effectiveConfigSampleInterval"`
+	} `mapstructure:"opamp"`
 	ServerID string `mapstructure:"serverId"`
 	Database struct {
-		Type           string        `mapstructure:"type"`
-		Endpoints      []string      `mapstructure:"endpoints"`
-		ConnectTimeout time.Duration `mapstructure:"connectTimeout"`
-		DatabaseName   string        `mapstructure:"databaseName"`
-		DDLAuto        bool          `mapstructure:"ddlAuto"`
+		Type            string        `mapstructure:"type"`
+		Endpoints       []string      `mapstructure:"endpoints"`
+		ConnectTimeout  time.Duration `mapstructure:"connectTimeout"`
+		DatabaseName    string        `mapstructure:"databaseName"`
+		DDLAuto         bool          `mapstructure:"ddlAuto"`
+		AgentWriteBatch struct {
+			FlushInterval time.Duration `mapstructure:"flushInterval"`
+			MaxSize       int           `mapstructure:"maxSize"`
+		} `mapstructure:"agentWriteBatch"`
+		FieldEncryption struct {
+			KeyURI string `mapstructure:"keyUri"`
+		} `mapstructure:"fieldEncryption"`
 	} `mapstructure:"database"`
 	ServiceName string `mapstructure:"serviceName"`
 	Event       struct {
 		Type  string `mapstructure:"type"`
 		Kafka struct {
-			Brokers []string `mapstructure:"brokers"`
-			Topic   string   `mapstructure:"topic"`
+			Brokers          []string      `mapstructure:"brokers"`
+			Topic            string        `mapstructure:"topic"`
+			KeyStrategy      string        `mapstructure:"keyStrategy"`
+			MaxConsumerLag   int64         `mapstructure:"maxConsumerLag"`
+			LagCheckInterval time.Duration `mapstructure:"lagCheckInterval"`
 		}
 	} `mapstructure:"event"`
 	Management struct {
@@ -83,11 +112,13 @@ type CommandOption struct {
 			Pepper string `mapstructure:"pepper"`
 		} `mapstructure:"basic"`
 		JWT struct {
-			Issuer        string        `mapstructure:"issuer"`
-			Expire        time.Duration `mapstructure:"expire"`
-			RefreshExpire time.Duration `mapstructure:"refreshExpire"`
-			Secret        string        `mapstructure:"secret"`
-			Audience      []string      `mapstructure:"audience"`
+			Issuer           string        `mapstructure:"issuer"`
+			Expire           time.Duration `mapstructure:"expire"`
+			RefreshExpire    time.Duration `mapstructure:"refreshExpire"`
+			SigningAlgorithm string        `mapstructure:"signingAlgorithm"`
+			Secret           string        `mapstructure:"secret"`
+			PrivateKeyFile   string        `mapstructure:"privateKeyFile"`
+			Audience         []string      `mapstructure:"audience"`
 		}
 		Type   string `mapstructure:"type"`
 		OAuth2 struct {
@@ -113,12 +144,80 @@ type CommandOption struct {
 		DefaultRole      string `mapstructure:"defaultRole"`
 	} `mapstructure:"bootstrap"`
 
+	AgentGroup struct {
+		RemoteConfigKeyDelimiter  string   `mapstructure:"remoteConfigKeyDelimiter"`
+		ForbiddenRemoteConfigKeys []string `mapstructure:"forbiddenRemoteConfigKeys"`
+		BaseRemoteConfigFiles     []string `mapstructure:"baseRemoteConfigFiles"`
+		CaseInsensitiveNames      bool     `mapstructure:"caseInsensitiveNames"`
+	} `mapstructure:"agentGroup"`
+
+	AgentQuarantine struct {
+		UnhealthyThreshold time.Duration `mapstructure:"unhealthyThreshold"`
+		EvaluationInterval time.Duration `mapstructure:"evaluationInterval"`
+	} `mapstructure:"agentQuarantine"`
+
+	AgentAttribute struct {
+		Aliases       map[string]string `mapstructure:"aliases"`
+		MaxCount      int               `mapstructure:"maxCount"`
+		MaxTotalBytes int               `mapstructure:"maxTotalBytes"`
+		Denylist      []string          `mapstructure:"denylist"`
+	} `mapstructure:"agentAttribute"`
+
+	AgentEffectiveConfigHistory struct {
+		MaxEntries    int `mapstructure:"maxEntries"`
+		MaxTotalBytes int `mapstructure:"maxTotalBytes"`
+	} `mapstructure:"agentEffectiveConfigHistory"`
+
+	AgentAvailableComponents struct {
+		MaxDepth int `mapstructure:"maxDepth"`
+		MaxNodes int `mapstructure:"maxNodes"`
+	} `mapstructure:"agentAvailableComponents"`
+
+	AgentEffectiveConfigStaleness struct {
+		Window             time.Duration `mapstructure:"window"`
+		EvaluationInterval time.Duration `mapstructure:"evaluationInterval"`
+	} `mapstructure:"agentEffectiveConfigStaleness"`
+
+	AgentCommand struct {
+		MaxPending int `mapstructure:"maxPending"`
+	} `mapstructure:"agentCommand"`
+
+	AgentTransitionLog struct {
+		Enabled      bool `mapstructure:"enabled"`
+		MaxPerSecond int  `mapstructure:"maxPerSecond"`
+	} `mapstructure:"agentTransitionLog"`
+
+	AgentConfigFile struct {
+		DefaultFormat string `mapstructure:"defaultFormat"`
+	} `mapstructure:"agentConfigFile"`
+
+	PackageDownload struct {
+		Timeout          time.Duration `mapstructure:"timeout"`
+		MaxAttempts      int           `mapstructure:"maxAttempts"`
+		RetryBackoff     time.Duration `mapstructure:"retryBackoff"`
+		FailureThreshold int           `mapstructure:"failureThreshold"`
+		OpenDuration     time.Duration `mapstructure:"openDuration"`
+	} `mapstructure:"packageDownload"`
+
+	ResourceQuota struct {
+		MaxAgentGroups  int64 `mapstructure:"maxAgentGroups"`
+		MaxCertificates int64 `mapstructure:"maxCertificates"`
+	} `mapstructure:"resourceQuota"`
+
 	MetricsBackend struct {
 		Type          string        `mapstructure:"type"`
 		Address       string        `mapstructure:"address"`
 		DefaultWindow time.Duration `mapstructure:"defaultWindow"`
 	} `mapstructure:"metricsBackend"`
 
+	AgentEnrichment struct {
+		Type   string `mapstructure:"type"`
+		Static struct {
+			Attribute string                       `mapstructure:"attribute"`
+			Labels    map[string]map[string]string `mapstructure:"labels"`
+		} `mapstructure:"static"`
+	} `mapstructure:"agentEnrichment"`
+
 	// viper
 	viper *viper.Viper
 
@@ -163,17 +262,54 @@ func NewCommand(opt CommandOption) *cobra.Command {
 	cmd.PersistentFlags().StringVar(&opt.configFilename, "config", "",
 		"config file (default is $HOME/.config/opampcommander/apiserver/config.yaml)")
 	cmd.Flags().String("address", "localhost:8080", "server address")
+	cmd.Flags().StringSlice("trustedProxies", nil,
+		"IPs or CIDRs of reverse proxies whose X-Forwarded-For header is honored for the client IP "+
+			"(empty trusts none)")
+	cmd.Flags().Duration("requestTimeout.default", 30*time.Second,
+		"deadline of an API request; per-route overrides are set in the config file (negative disables)")
+	//nolint:mnd
+	cmd.Flags().Int64("opamp.maxMessageBytes", 16<<20,
+		"largest OpAMP message an agent may send; a connection sending a larger one is closed (negative disables)")
+	cmd.Flags().Int("opamp.maxConnectionsPerIP", 0,
+		"OpAMP connections one client IP may hold open at once; further ones get 429 (0 for unlimited)")
+	cmd.Flags().Duration("opamp.minReportInterval", 0,
+		"minimum interval between persisting an agent's reports; sooner reports are coalesced (0 disables)")
+	cmd.Flags().Bool("opamp.effectiveConfigOnChangeOnly", false,
+		"do not persist an agent only because it reported the effective config it already has")
+	cmd.Flags().Duration("opamp.effectiveConfigSampleInterval", 0,
+		"minimum interval between persisting an agent's changed effective configs; sooner changes are held back "+
+			"(0 disables)")
+	cmd.Flags().Duration("opamp.disconnectGracePeriod", 0,
+		"how long an agent whose connection closed still counts as connected before it is marked disconnected")
+	cmd.Flags().Bool("opamp.enableCompression", false,
+		"compress WebSocket messages and HTTP responses sent to agents that advertise support")
+	cmd.Flags().StringToString("opamp.requiredHeaders", nil,
+		"headers an OpAMP connection must present, e.g. X-Agent-Secret=value; others get 403 (empty value: any)")
+	cmd.Flags().String("opamp.requiredSubprotocol", "",
+		"subprotocol a WebSocket OpAMP connection must offer; others get 400 (empty disables)")
 	cmd.Flags().String("serverId", "", "server ID (default is hostname, can be overridden by SERVER_ID env var)")
 	cmd.Flags().String("database.type", "inmemory", "database type (inmemory, mongodb)")
 	cmd.Flags().StringSlice("database.endpoints", []string{"mongodb://localhost:27017"}, "database endpoints")
 	cmd.Flags().Duration("database.connectTimeout", 10*time.Second, "database connection timeout")
 	cmd.Flags().String("database.databaseName", "opampcommander", "database name")
 	cmd.Flags().Bool("database.ddlAuto", false, "automatically create database schema")
+	cmd.Flags().Duration("database.agentWriteBatch.flushInterval", 0,
+		"buffer agent writes and bulk write them at this interval (mongodb only, 0 to disable)")
+	//nolint:mnd
+	cmd.Flags().Int("database.agentWriteBatch.maxSize", 500,
+		"number of buffered agent writes that triggers an early flush")
+	cmd.Flags().String("database.fieldEncryption.keyUri", "",
+		"key encrypting certificate private keys at rest (base64key://<key> or file:///path, mongodb only)")
 	cmd.Flags().String("serviceName", "opampcommander", "service name for observability")
 	cmd.Flags().String("event.type", "inmemory", "event protocol type (inmemory, kafka)")
 	cmd.Flags().Bool("event.enabled", false, "enable event communication")
 	cmd.Flags().StringSlice("event.kafka.brokers", []string{"localhost:9092"}, "Kafka broker addresses")
 	cmd.Flags().String("event.kafka.topic", "opampcommander.events", "Kafka topic name")
+	cmd.Flags().String("event.kafka.keyStrategy", "instanceUID",
+		"how Kafka events are keyed for partitioning (instanceUID, none)")
+	cmd.Flags().Int64("event.kafka.maxConsumerLag", 0,
+		"unconsumed Kafka events above which the server reports not ready (0 disables)")
+	cmd.Flags().Duration("event.kafka.lagCheckInterval", 30*time.Second, "how often Kafka consumer lag is measured")
 	cmd.Flags().String("management.address", "localhost:9090", "management server address")
 	cmd.Flags().Bool("management.metric.enabled", false, "enable metrics")
 	cmd.Flags().String("management.metric.type", "prometheus", "metric type (prometheus, opentelemetry)")
@@ -212,7 +348,11 @@ func NewCommand(opt CommandOption) *cobra.Command {
 	//nolint:mnd
 	cmd.Flags().Duration("auth.jwt.refreshExpire", 7*24*time.Hour,
 		"JWT refresh token expiration duration (0 disables refresh tokens)")
-	cmd.Flags().String("auth.jwt.secret", "", "JWT signing secret")
+	cmd.Flags().String("auth.jwt.signingAlgorithm", security.SigningAlgorithmHS256,
+		"JWT signing algorithm: HS256 (shared secret) or RS256 "+
+			"(RSA key pair, public key served at /.well-known/jwks.json)")
+	cmd.Flags().String("auth.jwt.secret", "", "JWT signing secret (HS256)")
+	cmd.Flags().String("auth.jwt.privateKeyFile", "", "path of the PEM-encoded RSA private key used to sign JWTs (RS256)")
 	cmd.Flags().StringSlice("auth.jwt.audience", []string{"opampcommander"}, "JWT audience")
 	cmd.Flags().String("auth.type", "oauth2", "authentication type")
 	cmd.Flags().String("auth.oauth2.provider", "", "OAuth2 provider URL")
@@ -235,12 +375,75 @@ func NewCommand(opt CommandOption) *cobra.Command {
 		"namespace agents without a service.namespace are placed in, and where the default role is granted")
 	cmd.Flags().String("bootstrap.defaultRole", "default",
 		"name of the built-in role auto-granted to every user")
+	cmd.Flags().String("agentGroup.remoteConfigKeyDelimiter", agentmodel.DefaultRemoteConfigKeyDelimiter,
+		"delimiter between an agent group name and an inline config name in delivered config keys "+
+			"(must not contain a backslash, which escapes delimiters inside names)")
+	cmd.Flags().StringSlice("agentGroup.forbiddenRemoteConfigKeys", nil,
+		"dotted config keys (e.g. exporters.debug) an agent group's remote configs must not set")
+	cmd.Flags().StringSlice("agentGroup.baseRemoteConfigFiles", nil,
+		"remote config files merged, with the lowest priority, into every agent group's agents' config")
+	cmd.Flags().Bool("agentGroup.caseInsensitiveNames", false,
+		"reject creating an agent group whose name differs from an existing one in its namespace only by case")
+	cmd.Flags().Duration("agentQuarantine.unhealthyThreshold", 0,
+		"how long an agent must stay unhealthy before it is quarantined automatically (0 disables)")
+	cmd.Flags().Duration("agentQuarantine.evaluationInterval", time.Minute,
+		"how often agents are evaluated for automatic quarantine")
+	cmd.Flags().StringToString("agentAttribute.aliases", map[string]string(modelagent.DefaultAttributeAliases()),
+		"reported agent attribute keys to rename to a canonical key (alias=canonical)")
+	cmd.Flags().Int("agentAttribute.maxCount", modelagent.DefaultMaxAttributeCount,
+		"maximum number of identifying (and of non-identifying) attributes stored per agent (negative disables)")
+	cmd.Flags().Int("agentAttribute.maxTotalBytes", modelagent.DefaultMaxAttributeTotalBytes,
+		"maximum summed key+value bytes of each reported attribute map stored per agent (negative disables)")
+	cmd.Flags().StringSlice("agentAttribute.denylist", nil,
+		"reported agent attribute key patterns (e.g. *.token) stripped before storage")
+	cmd.Flags().Int("agentEffectiveConfigHistory.maxEntries", agentmodel.DefaultEffectiveConfigHistoryMaxEntries,
+		"maximum number of past effective configs kept per agent (negative disables the history)")
+	cmd.Flags().Int("agentEffectiveConfigHistory.maxTotalBytes", agentmodel.DefaultEffectiveConfigHistoryMaxTotalBytes,
+		"maximum summed size of the effective-config history kept per agent (negative disables)")
+	cmd.Flags().Int("agentAvailableComponents.maxDepth", agentmodel.DefaultAvailableComponentsMaxDepth,
+		"deepest level of an agent's available components tree that is stored (negative disables)")
+	cmd.Flags().Int("agentAvailableComponents.maxNodes", agentmodel.DefaultAvailableComponentsMaxNodes,
+		"maximum number of available components stored per agent across all levels (negative disables)")
+	cmd.Flags().Duration("agentEffectiveConfigStaleness.window", 0,
+		"how long a connected agent may go without reporting its effective config before it is "+
+			"asked for it, and then has to answer before it is flagged StaleEffectiveConfig (0 disables)")
+	cmd.Flags().Duration("agentEffectiveConfigStaleness.evaluationInterval", time.Minute,
+		"how often agents are evaluated for a stale effective config")
+	cmd.Flags().Int("agentCommand.maxPending", 0,
+		"maximum number of commands queued for a single agent; further commands get 429 (0 for unlimited)")
+	cmd.Flags().Bool("agentTransitionLog.enabled", false,
+		"log every agent state transition (connect, health, remote config, command) at info level")
+	cmd.Flags().Int("agentTransitionLog.maxPerSecond", applicationhelper.DefaultAgentTransitionLogMaxPerSecond,
+		"maximum number of agent state transitions logged per second; the excess is dropped")
+	cmd.Flags().String("agentConfigFile.defaultFormat", "yaml",
+		"format (yaml, json) assumed for agent config files reported without a content type")
+	cmd.Flags().Duration("packageDownload.timeout", 30*time.Second,
+		"deadline of a single agent package download attempt")
+	cmd.Flags().Int("packageDownload.maxAttempts", 3,
+		"number of times a failing agent package download is tried")
+	cmd.Flags().Duration("packageDownload.retryBackoff", time.Second,
+		"wait before retrying a failed agent package download; doubles on each further retry")
+	cmd.Flags().Int("packageDownload.failureThreshold", 5,
+		"consecutive failed downloads after which an origin's downloads fail immediately")
+	cmd.Flags().Duration("packageDownload.openDuration", time.Minute,
+		"how long downloads from a failing origin fail immediately before it is probed again")
+	cmd.Flags().Int64("resourceQuota.maxAgentGroups", 0,
+		"maximum number of agent groups across all namespaces (0 for unlimited)")
+	cmd.Flags().Int64("resourceQuota.maxCertificates", 0,
+		"maximum number of certificates across all namespaces (0 for unlimited)")
 	cmd.Flags().String("metricsBackend.type", "none",
 		"metrics backend for endpoint-throughput queries (none, prometheus)")
 	cmd.Flags().String("metricsBackend.address", "",
 		"base URL of the Prometheus-compatible HTTP API (required when metricsBackend.type=prometheus)")
 	cmd.Flags().Duration("metricsBackend.defaultWindow", 5*time.Minute,
 		"default rate window for endpoint-throughput queries")
+	cmd.Flags().String("agentEnrichment.type", "none",
+		"enrichment deriving server-owned agent labels (none, static)")
+	cmd.Flags().String("agentEnrichment.static.attribute", "",
+		"agent attribute whose value selects the agent's labels in agentEnrichment.static.labels "+
+			"(required when agentEnrichment.type=static)")
+
+	cmd.AddCommand(newBootstrapCommand(&opt, cmd.Flags()))
 
 	return cmd
 }
@@ -303,11 +506,35 @@ func (opt *CommandOption) Init(cmd *cobra.Command, _ []string) error {
 }
 
 // Prepare prepares the command.
+func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
+	opt.app = apiserver.New(opt.serverSettings())
+
+	return nil
+}
+
+// serverSettings builds the apiserver settings from the parsed flags and config file.
 //
 //nolint:funlen // Configuration parsing requires many steps
-func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
-	opt.app = apiserver.New(appconfig.ServerSettings{
-		Address:  opt.Address,
+func (opt *CommandOption) serverSettings() appconfig.ServerSettings {
+	return appconfig.ServerSettings{
+		Address:        opt.Address,
+		TrustedProxies: opt.TrustedProxies,
+		RequestTimeoutSettings: appconfig.RequestTimeoutSettings{
+			Default: opt.RequestTimeout.Default,
+			Routes:  opt.RequestTimeout.Routes,
+		},
+		OpAMPSettings: appconfig.OpAMPSettings{
+			MaxMessageBytes:       opt.OpAMP.MaxMessageBytes,
+			MaxConnectionsPerIP:   opt.OpAMP.MaxConnectionsPerIP,
+			MinReportInterval:     opt.OpAMP.MinReportInterval,
+			DisconnectGracePeriod: opt.OpAMP.DisconnectGracePeriod,
+			EnableCompression:     opt.OpAMP.EnableCompression,
+			RequiredHeaders:       opt.OpAMP.RequiredHeaders,
+			RequiredSubprotocol:   opt.OpAMP.RequiredSubprotocol,
+
+			EffectiveConfigOnChangeOnly:   opt.OpAMP.EffectiveConfigOnChangeOnly,
+			EffectiveConfigSampleInterval: opt.OpAMP.EffectiveConfigSampleInterval,
+		},
 		ServerID: agentmodel.ServerID(opt.ServerID),
 		DatabaseSettings: appconfig.DatabaseSettings{
 			Type:           appconfig.DatabaseType(opt.Database.Type),
@@ -315,6 +542,13 @@ func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
 			ConnectTimeout: opt.Database.ConnectTimeout,
 			DatabaseName:   opt.Database.DatabaseName,
 			DDLAuto:        opt.Database.DDLAuto,
+			AgentWriteBatch: appconfig.AgentWriteBatchSettings{
+				FlushInterval: opt.Database.AgentWriteBatch.FlushInterval,
+				MaxSize:       opt.Database.AgentWriteBatch.MaxSize,
+			},
+			FieldEncryption: appconfig.FieldEncryptionSettings{
+				KeyURI: opt.Database.FieldEncryption.KeyURI,
+			},
 		},
 		Security: security.Config{
 			AdminSettings: security.AdminSettings{
@@ -329,7 +563,9 @@ func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
 				Issuer:            opt.Auth.JWT.Issuer,
 				Expiration:        opt.Auth.JWT.Expire,
 				RefreshExpiration: opt.Auth.JWT.RefreshExpire,
+				SigningAlgorithm:  opt.Auth.JWT.SigningAlgorithm,
 				SigningKey:        opt.Auth.JWT.Secret,
+				PrivateKeyFile:    opt.Auth.JWT.PrivateKeyFile,
 				Audience:          opt.Auth.JWT.Audience,
 			},
 			OAuthSettings: &security.OAuthSettings{
@@ -341,7 +577,9 @@ func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
 					Issuer:            opt.Auth.OAuth2.State.JWT.Issuer,
 					Expiration:        opt.Auth.OAuth2.State.JWT.Expire,
 					RefreshExpiration: 0,
+					SigningAlgorithm:  security.SigningAlgorithmHS256,
 					SigningKey:        opt.Auth.OAuth2.State.JWT.Secret,
+					PrivateKeyFile:    "",
 					Audience:          opt.Auth.OAuth2.State.JWT.Audience,
 				},
 			},
@@ -349,8 +587,11 @@ func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
 		EventSettings: appconfig.EventSettings{
 			ProtocolType: appconfig.EventProtocolType(opt.Event.Type),
 			KafkaSettings: appconfig.KafkaSettings{
-				Brokers: opt.Event.Kafka.Brokers,
-				Topic:   opt.Event.Kafka.Topic,
+				Brokers:          opt.Event.Kafka.Brokers,
+				Topic:            opt.Event.Kafka.Topic,
+				KeyStrategy:      opt.Event.Kafka.KeyStrategy,
+				MaxConsumerLag:   opt.Event.Kafka.MaxConsumerLag,
+				LagCheckInterval: opt.Event.Kafka.LagCheckInterval,
 			},
 		},
 		ManagementSettings: appconfig.ManagementSettings{
@@ -391,15 +632,67 @@ func (opt *CommandOption) Prepare(_ *cobra.Command, _ []string) error {
 			DefaultNamespace: defaultString(opt.Bootstrap.DefaultNamespace, agentmodel.DefaultNamespaceName),
 			DefaultRole:      defaultString(opt.Bootstrap.DefaultRole, usermodel.RoleDefault),
 		},
+		AgentGroupSettings: appconfig.AgentGroupSettings{
+			RemoteConfigKeyDelimiter:  opt.AgentGroup.RemoteConfigKeyDelimiter,
+			ForbiddenRemoteConfigKeys: opt.AgentGroup.ForbiddenRemoteConfigKeys,
+			BaseRemoteConfigFiles:     opt.AgentGroup.BaseRemoteConfigFiles,
+			CaseInsensitiveNames:      opt.AgentGroup.CaseInsensitiveNames,
+		},
+		AgentAttributeSettings: appconfig.AgentAttributeSettings{
+			Aliases:       opt.AgentAttribute.Aliases,
+			MaxCount:      opt.AgentAttribute.MaxCount,
+			MaxTotalBytes: opt.AgentAttribute.MaxTotalBytes,
+			Denylist:      opt.AgentAttribute.Denylist,
+		},
+		AgentQuarantineSettings: appconfig.AgentQuarantineSettings{
+			UnhealthyThreshold: opt.AgentQuarantine.UnhealthyThreshold,
+			EvaluationInterval: opt.AgentQuarantine.EvaluationInterval,
+		},
+		AgentEffectiveConfigHistorySettings: appconfig.AgentEffectiveConfigHistorySettings{
+			MaxEntries:    opt.AgentEffectiveConfigHistory.MaxEntries,
+			MaxTotalBytes: opt.AgentEffectiveConfigHistory.MaxTotalBytes,
+		},
+		AgentAvailableComponentsSettings: appconfig.AgentAvailableComponentsSettings{
+			MaxDepth: opt.AgentAvailableComponents.MaxDepth,
+			MaxNodes: opt.AgentAvailableComponents.MaxNodes,
+		},
+		AgentEffectiveConfigStalenessSettings: appconfig.AgentEffectiveConfigStalenessSettings{
+			Window:             opt.AgentEffectiveConfigStaleness.Window,
+			EvaluationInterval: opt.AgentEffectiveConfigStaleness.EvaluationInterval,
+		},
+		AgentCommandSettings: appconfig.AgentCommandSettings{
+			MaxPending: opt.AgentCommand.MaxPending,
+		},
+		AgentTransitionLogSettings: appconfig.AgentTransitionLogSettings{
+			Enabled:      opt.AgentTransitionLog.Enabled,
+			MaxPerSecond: opt.AgentTransitionLog.MaxPerSecond,
+		},
+		AgentConfigFileSettings: appconfig.AgentConfigFileSettings{
+			DefaultFormat: opt.AgentConfigFile.DefaultFormat,
+		},
+		PackageDownloadSettings: appconfig.PackageDownloadSettings{
+			Timeout:          opt.PackageDownload.Timeout,
+			MaxAttempts:      opt.PackageDownload.MaxAttempts,
+			RetryBackoff:     opt.PackageDownload.RetryBackoff,
+			FailureThreshold: opt.PackageDownload.FailureThreshold,
+			OpenDuration:     opt.PackageDownload.OpenDuration,
+		},
+		ResourceQuotaSettings: appconfig.ResourceQuotaSettings{
+			MaxAgentGroups:  opt.ResourceQuota.MaxAgentGroups,
+			MaxCertificates: opt.ResourceQuota.MaxCertificates,
+		},
 		MetricsBackend: appconfig.MetricsBackendSettings{
 			Type:          appconfig.MetricsBackendType(opt.MetricsBackend.Type),
 			Address:       opt.MetricsBackend.Address,
 			DefaultWindow: opt.MetricsBackend.DefaultWindow,
 		},
+		AgentEnrichment: appconfig.AgentEnrichmentSettings{
+			Type:            appconfig.AgentEnrichmentType(opt.AgentEnrichment.Type),
+			StaticAttribute: opt.AgentEnrichment.Static.Attribute,
+			StaticLabels:    opt.AgentEnrichment.Static.Labels,
+		},
 		RBACModelPath: "",
-	})
-
-	return nil
+	}
 }
 
 // defaultString returns value when non-empty, otherwise fallback.
@@ -0,0 +1,63 @@
+package apiserver
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/minuk-dev/opampcommander/pkg/apiserver"
+)
+
+// newBootstrapCommand creates the "apiserver bootstrap" command, which applies a
+// manifest file to the configured store and exits. It shares the apiserver's flags so
+// it reaches the same database as the server it prepares.
+func newBootstrapCommand(opt *CommandOption, serverFlags *pflag.FlagSet) *cobra.Command {
+	var file string
+
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "apply a manifest file, e.g. to register agents before they connect",
+		Long: `Apply the manifests in a YAML file to the configured database and exit.
+
+Besides the kinds accepted in bootstrap.dir, the file may contain Agent documents.
+Each one registers an agent by its metadata.instanceUid with the given identifying
+and non-identifying attributes, so the agent is listed, and matched by agent groups,
+before it first connects. An agent that already exists is left untouched.
+
+  kind: Agent
+  apiVersion: v1
+  metadata:
+    instanceUid: 01966b1d-9f6c-7a4e-8a3b-5d2f7c1e4a90
+    description:
+      identifyingAttributes:
+        service.name: otelcol-contrib
+        service.namespace: production
+      nonIdentifyingAttributes:
+        host.name: web-1`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			err := opt.Init(cmd, args)
+			if err != nil {
+				return fmt.Errorf("failed to initialize command: %w", err)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := apiserver.Bootstrap(cmd.Context(), opt.serverSettings(), file)
+			if err != nil {
+				return fmt.Errorf("failed to bootstrap: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(serverFlags)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "path of the manifest YAML file to apply (required)")
+
+	cmd.MarkFlagRequired("file") //nolint:errcheck,gosec
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+// Package agent provides the agent command for opampctl.
+package agent
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agent/watchconfig"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+// CommandOptions contains the options for the agent command.
+type CommandOptions struct {
+	*config.GlobalConfig
+}
+
+// NewCommand creates a new agent command.
+// It contains subcommands that follow a single agent rather than list or edit the resource.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "inspect agents",
+	}
+
+	cmd.AddCommand(watchconfig.NewCommand(watchconfig.CommandOptions{
+		GlobalConfig: options.GlobalConfig,
+	}))
+
+	return cmd
+}
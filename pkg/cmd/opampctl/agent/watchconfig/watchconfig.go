@@ -0,0 +1,158 @@
+// Package watchconfig provides the agent watch-config command for opampctl.
+package watchconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/clientutil"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+const (
+	defaultTimeout  = 5 * time.Minute
+	defaultInterval = 2 * time.Second
+)
+
+var (
+	// ErrWatchTimeout is returned when the agent does not report its remote config applied
+	// before the timeout.
+	ErrWatchTimeout = errors.New("timed out waiting for the agent to apply its remote config")
+	// ErrConfigNotDeliverable is returned when the server reports that the remote config
+	// assigned to the agent can never be delivered to it.
+	ErrConfigNotDeliverable = errors.New("remote config cannot be delivered to the agent")
+)
+
+// agentGetter is the part of the API client watch-config needs.
+type agentGetter interface {
+	GetAgent(ctx context.Context, namespace string, id uuid.UUID) (*v1.Agent, error)
+}
+
+// CommandOptions contains the options for the agent watch-config command.
+type CommandOptions struct {
+	*config.GlobalConfig
+
+	// flags
+	namespace  string
+	configName string
+	timeout    time.Duration
+	interval   time.Duration
+
+	// internal
+	client agentGetter
+}
+
+// NewCommand creates a new agent watch-config command.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "watch-config UID",
+		Short: "wait until an agent reports its remote config applied",
+		Long: `watch-config polls the agent until it reports having applied the remote config the
+server offers it, and exits non-zero if that does not happen before --timeout or the
+server reports that the config can never be delivered to the agent.`,
+		Example: `  # wait up to 5 minutes for the agent to apply its remote config
+  opampctl agent watch-config 0190c4f2-8d7e-7b3a-9a51-2f1c6f1e5d42 -n default
+
+  # also require the remote config "otel-base" to be among the configs it was offered
+  opampctl agent watch-config 0190c4f2-8d7e-7b3a-9a51-2f1c6f1e5d42 --config otel-base --timeout 10m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := options.Prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = options.Run(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.namespace, "namespace", "n", "default", "Namespace of the agent")
+	cmd.Flags().StringVar(&options.configName, "config", "",
+		"Wait until this remote config is among the configs offered to the agent as well")
+	cmd.Flags().DurationVar(&options.timeout, "timeout", defaultTimeout, "How long to wait before giving up")
+	cmd.Flags().DurationVar(&options.interval, "interval", defaultInterval, "How often to poll the agent")
+
+	return cmd
+}
+
+// Prepare creates the API client.
+func (opt *CommandOptions) Prepare(*cobra.Command, []string) error {
+	client, err := clientutil.NewClient(opt.GlobalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create authenticated client: %w", err)
+	}
+
+	opt.client = client.AgentService
+
+	return nil
+}
+
+// Run polls the agent named by the first argument until its config is in sync.
+func (opt *CommandOptions) Run(cmd *cobra.Command, args []string) error {
+	instanceUID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid agent instance UID %q: %w", args[0], err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), opt.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(opt.interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := opt.check(ctx, instanceUID)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			cmd.Printf("agent %s applied its remote config\n", instanceUID)
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: agent %s after %s", ErrWatchTimeout, instanceUID, opt.timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// check fetches the agent once and reports whether its remote config is applied.
+func (opt *CommandOptions) check(ctx context.Context, instanceUID uuid.UUID) (bool, error) {
+	agent, err := opt.client.GetAgent(ctx, opt.namespace, instanceUID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("%w: agent %s after %s", ErrWatchTimeout, instanceUID, opt.timeout)
+		}
+
+		return false, fmt.Errorf("failed to get agent %s in namespace %q: %w", instanceUID, opt.namespace, err)
+	}
+
+	for _, condition := range agent.Status.Conditions {
+		if condition.Type == v1.ConditionTypeRemoteConfigApplied && condition.Status == v1.ConditionStatusFalse {
+			return false, fmt.Errorf("%w: %s", ErrConfigNotDeliverable, condition.Message)
+		}
+	}
+
+	if opt.configName != "" && !slices.Contains(agent.Spec.RemoteConfig.RemoteConfigNames, opt.configName) {
+		return false, nil
+	}
+
+	return agent.Status.ConfigInSync, nil
+}
@@ -0,0 +1,135 @@
+package watchconfig
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// fakeAgentGetter returns the agents in order, repeating the last one once they run out.
+type fakeAgentGetter struct {
+	agents []*v1.Agent
+	calls  int
+}
+
+func (f *fakeAgentGetter) GetAgent(context.Context, string, uuid.UUID) (*v1.Agent, error) {
+	agent := f.agents[min(f.calls, len(f.agents)-1)]
+	f.calls++
+
+	return agent, nil
+}
+
+func newAgent(inSync bool, remoteConfigNames ...string) *v1.Agent {
+	//exhaustruct:ignore
+	return &v1.Agent{
+		Spec: v1.AgentSpec{
+			RemoteConfig: v1.AgentSpecRemoteConfig{RemoteConfigNames: remoteConfigNames},
+		},
+		Status: v1.AgentStatus{ConfigInSync: inSync},
+	}
+}
+
+func newCommand(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetContext(t.Context())
+
+	return cmd, &out
+}
+
+func TestRun_WaitsUntilConfigInSync(t *testing.T) {
+	t.Parallel()
+
+	getter := &fakeAgentGetter{agents: []*v1.Agent{newAgent(false), newAgent(false), newAgent(true)}}
+	cmd, out := newCommand(t)
+	instanceUID := uuid.New()
+
+	//exhaustruct:ignore
+	options := &CommandOptions{timeout: time.Minute, interval: time.Millisecond, client: getter}
+
+	require.NoError(t, options.Run(cmd, []string{instanceUID.String()}))
+	assert.Equal(t, 3, getter.calls)
+	assert.Contains(t, out.String(), instanceUID.String()+" applied its remote config")
+}
+
+func TestRun_TimesOutWhileOutOfSync(t *testing.T) {
+	t.Parallel()
+
+	getter := &fakeAgentGetter{agents: []*v1.Agent{newAgent(false)}}
+	cmd, _ := newCommand(t)
+
+	//exhaustruct:ignore
+	options := &CommandOptions{timeout: 20 * time.Millisecond, interval: time.Millisecond, client: getter}
+
+	err := options.Run(cmd, []string{uuid.NewString()})
+	require.ErrorIs(t, err, ErrWatchTimeout)
+	assert.Greater(t, getter.calls, 1)
+}
+
+func TestRun_FailsWhenConfigCannotBeDelivered(t *testing.T) {
+	t.Parallel()
+
+	undeliverable := newAgent(false)
+	//exhaustruct:ignore
+	undeliverable.Status.Conditions = []v1.Condition{{
+		Type:    v1.ConditionTypeRemoteConfigApplied,
+		Status:  v1.ConditionStatusFalse,
+		Message: "agent does not accept remote config",
+	}}
+
+	getter := &fakeAgentGetter{agents: []*v1.Agent{newAgent(false), undeliverable}}
+	cmd, _ := newCommand(t)
+
+	//exhaustruct:ignore
+	options := &CommandOptions{timeout: time.Minute, interval: time.Millisecond, client: getter}
+
+	err := options.Run(cmd, []string{uuid.NewString()})
+	require.ErrorIs(t, err, ErrConfigNotDeliverable)
+	assert.ErrorContains(t, err, "agent does not accept remote config")
+}
+
+func TestRun_WaitsForTheNamedConfig(t *testing.T) {
+	t.Parallel()
+
+	// The agent is in sync with what it was offered before the new config reached it.
+	getter := &fakeAgentGetter{agents: []*v1.Agent{
+		newAgent(true, "base"),
+		newAgent(false, "base", "otel"),
+		newAgent(true, "base", "otel"),
+	}}
+	cmd, _ := newCommand(t)
+
+	//exhaustruct:ignore
+	options := &CommandOptions{
+		configName: "otel",
+		timeout:    time.Minute,
+		interval:   time.Millisecond,
+		client:     getter,
+	}
+
+	require.NoError(t, options.Run(cmd, []string{uuid.NewString()}))
+	assert.Equal(t, 3, getter.calls)
+}
+
+func TestRun_RejectsInvalidInstanceUID(t *testing.T) {
+	t.Parallel()
+
+	cmd, _ := newCommand(t)
+
+	//exhaustruct:ignore
+	options := &CommandOptions{timeout: time.Minute, interval: time.Millisecond, client: &fakeAgentGetter{}}
+
+	require.Error(t, options.Run(cmd, []string{"not-a-uuid"}))
+}
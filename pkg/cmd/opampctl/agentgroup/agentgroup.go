@@ -0,0 +1,30 @@
+// Package agentgroup provides the agentgroup command for opampctl.
+package agentgroup
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agentgroup/members"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+// CommandOptions contains the options for the agentgroup command.
+type CommandOptions struct {
+	*config.GlobalConfig
+}
+
+// NewCommand creates a new agentgroup command.
+// It contains subcommands that look into what an agent group governs rather than at the resource alone.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "agentgroup",
+		Short: "inspect agent groups",
+	}
+
+	cmd.AddCommand(members.NewCommand(members.CommandOptions{
+		GlobalConfig: options.GlobalConfig,
+	}))
+
+	return cmd
+}
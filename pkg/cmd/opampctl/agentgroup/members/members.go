@@ -0,0 +1,164 @@
+// Package members provides the agentgroup members command for opampctl.
+package members
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/client"
+	"github.com/minuk-dev/opampcommander/pkg/clientutil"
+	"github.com/minuk-dev/opampcommander/pkg/formatter"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+// agentGroupMemberLister is the part of the API client members needs.
+type agentGroupMemberLister interface {
+	ListAgentsByAgentGroup(
+		ctx context.Context,
+		namespace string,
+		name string,
+		opts ...client.ListOption,
+	) (*client.AgentListResponse, error)
+}
+
+// CommandOptions contains the options for the agentgroup members command.
+type CommandOptions struct {
+	*config.GlobalConfig
+
+	// flags
+	namespace     string
+	connectedOnly bool
+	formatType    string
+
+	// internal
+	client agentGroupMemberLister
+}
+
+// NewCommand creates a new agentgroup members command.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "members NAME",
+		Short: "list the agents an agent group currently governs",
+		Example: `  # list the agents of the agent group "production"
+  opampctl agentgroup members production -n default
+
+  # list only its connected agents
+  opampctl agentgroup members production --connected`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := options.Prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = options.Run(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.namespace, "namespace", "n", "default", "Namespace of the agent group")
+	cmd.Flags().BoolVar(&options.connectedOnly, "connected", false, "List only currently-connected agents")
+	cmd.Flags().StringVarP(&options.formatType, "output", "o", "short", "Output format (short, text, json, yaml)")
+
+	return cmd
+}
+
+// Prepare creates the API client.
+func (opt *CommandOptions) Prepare(*cobra.Command, []string) error {
+	client, err := clientutil.NewClient(opt.GlobalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create authenticated client: %w", err)
+	}
+
+	opt.client = client.AgentGroupService
+
+	return nil
+}
+
+// Run lists every agent of the agent group named by the first argument.
+func (opt *CommandOptions) Run(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	agents, err := opt.listMembers(cmd.Context(), name)
+	if err != nil {
+		return fmt.Errorf("failed to list members of agent group %q in namespace %q: %w", name, opt.namespace, err)
+	}
+
+	members := make([]formattedMember, len(agents))
+	for idx, agent := range agents {
+		members[idx] = toFormattedMember(agent)
+	}
+
+	err = formatter.Format(cmd.OutOrStdout(), members, formatter.FormatType(opt.formatType))
+	if err != nil {
+		return fmt.Errorf("failed to format members: %w", err)
+	}
+
+	return nil
+}
+
+// listMembers walks every page of the agent group's agents.
+func (opt *CommandOptions) listMembers(ctx context.Context, name string) ([]v1.Agent, error) {
+	var (
+		agents        []v1.Agent
+		continueToken string
+	)
+
+	for {
+		opts := []client.ListOption{
+			client.WithLimit(clientutil.ChunkSize),
+			client.WithConnectedOnly(opt.connectedOnly),
+		}
+		if continueToken != "" {
+			opts = append(opts, client.WithContinueToken(continueToken))
+		}
+
+		resp, err := opt.client.ListAgentsByAgentGroup(ctx, opt.namespace, name, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list agents by agent group: %w", err)
+		}
+
+		agents = append(agents, resp.Items...)
+
+		continueToken = resp.Metadata.Continue
+		if continueToken == "" || len(resp.Items) == 0 {
+			return agents, nil
+		}
+	}
+}
+
+//nolint:lll
+type formattedMember struct {
+	InstanceUID    uuid.UUID `json:"instanceUid"    short:"Instance UID"     text:"Instance UID"     yaml:"instanceUid"`
+	ConnectionType string    `json:"connectionType" short:"Connection Type"  text:"Connection Type"  yaml:"connectionType"`
+	Connected      bool      `json:"connected"      short:"Connected"        text:"Connected"        yaml:"connected"`
+	Healthy        bool      `json:"healthy"        short:"Healthy"          text:"Healthy"          yaml:"healthy"`
+	StartedAt      string    `json:"startedAt"      short:"-"                text:"Started At"       yaml:"startedAt"`
+	LastReportedAt string    `json:"lastReportedAt" short:"Last Reported At" text:"Last Reported At" yaml:"lastReportedAt"`
+}
+
+func toFormattedMember(agent v1.Agent) formattedMember {
+	var startedAt string
+	if !agent.Status.ComponentHealth.StartTime.IsZero() {
+		startedAt = agent.Status.ComponentHealth.StartTime.Format(time.DateTime)
+	}
+
+	return formattedMember{
+		InstanceUID:    agent.Metadata.InstanceUID,
+		ConnectionType: agent.Status.ConnectionType,
+		Connected:      agent.Status.Connected,
+		Healthy:        agent.Status.ComponentHealth.Healthy,
+		StartedAt:      startedAt,
+		LastReportedAt: agent.Status.LastReportedAt,
+	}
+}
@@ -0,0 +1,115 @@
+package members
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/client"
+)
+
+// fakeAgentGroupMemberLister serves pages in order, recording the requests it got.
+type fakeAgentGroupMemberLister struct {
+	pages      []*client.AgentListResponse
+	namespaces []string
+	names      []string
+}
+
+func (f *fakeAgentGroupMemberLister) ListAgentsByAgentGroup(
+	_ context.Context,
+	namespace string,
+	name string,
+	_ ...client.ListOption,
+) (*client.AgentListResponse, error) {
+	f.namespaces = append(f.namespaces, namespace)
+	f.names = append(f.names, name)
+
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+
+	return page, nil
+}
+
+func newAgent(connectionType string, connected bool) v1.Agent {
+	//exhaustruct:ignore
+	return v1.Agent{
+		Metadata: v1.AgentMetadata{InstanceUID: uuid.New()},
+		Status:   v1.AgentStatus{ConnectionType: connectionType, Connected: connected},
+	}
+}
+
+func newPage(continueToken string, agents ...v1.Agent) *client.AgentListResponse {
+	return &client.AgentListResponse{
+		APIVersion: "v1",
+		Kind:       v1.AgentKind,
+		Items:      agents,
+		Metadata:   v1.ListMeta{RemainingItemCount: 0, Continue: continueToken},
+	}
+}
+
+func TestRun_ListsEveryMemberAcrossPages(t *testing.T) {
+	t.Parallel()
+
+	first := newAgent(v1.ConnectionTypeWebSocket, true)
+	second := newAgent(v1.ConnectionTypeHTTP, true)
+	third := newAgent(v1.ConnectionTypeUnknown, false)
+
+	lister := &fakeAgentGroupMemberLister{
+		pages: []*client.AgentListResponse{
+			newPage("next", first, second),
+			newPage("", third),
+		},
+	}
+
+	var out bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetContext(t.Context())
+
+	//exhaustruct:ignore
+	options := &CommandOptions{namespace: "production", formatType: "json", client: lister}
+
+	require.NoError(t, options.Run(cmd, []string{"collectors"}))
+
+	assert.Equal(t, []string{"production", "production"}, lister.namespaces)
+	assert.Equal(t, []string{"collectors", "collectors"}, lister.names)
+	assert.Empty(t, lister.pages, "every page is fetched")
+
+	members := gjson.Parse(out.String()).Array()
+	require.Len(t, members, 3)
+	assert.Equal(t, first.Metadata.InstanceUID.String(), members[0].Get("instanceUid").String())
+	assert.Equal(t, v1.ConnectionTypeWebSocket, members[0].Get("connectionType").String())
+	assert.Equal(t, second.Metadata.InstanceUID.String(), members[1].Get("instanceUid").String())
+	assert.Equal(t, third.Metadata.InstanceUID.String(), members[2].Get("instanceUid").String())
+	assert.False(t, members[2].Get("connected").Bool())
+}
+
+func TestRun_PrintsTable(t *testing.T) {
+	t.Parallel()
+
+	member := newAgent(v1.ConnectionTypeWebSocket, true)
+	lister := &fakeAgentGroupMemberLister{pages: []*client.AgentListResponse{newPage("", member)}}
+
+	var out bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetContext(t.Context())
+
+	//exhaustruct:ignore
+	options := &CommandOptions{namespace: "default", formatType: "short", client: lister}
+
+	require.NoError(t, options.Run(cmd, []string{"collectors"}))
+
+	assert.Contains(t, out.String(), "Instance UID")
+	assert.Contains(t, out.String(), member.Metadata.InstanceUID.String())
+	assert.Contains(t, out.String(), v1.ConnectionTypeWebSocket)
+}
@@ -0,0 +1,30 @@
+// Package agentpackage provides the agentpackage command for opampctl.
+package agentpackage
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agentpackage/push"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+// CommandOptions contains the options for the agentpackage command.
+type CommandOptions struct {
+	*config.GlobalConfig
+}
+
+// NewCommand creates a new agentpackage command.
+// It contains subcommands that act on agent package artifacts rather than on the resource alone.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "agentpackage",
+		Short: "manage agent package artifacts",
+	}
+
+	cmd.AddCommand(push.NewCommand(push.CommandOptions{
+		GlobalConfig: options.GlobalConfig,
+	}))
+
+	return cmd
+}
@@ -0,0 +1,298 @@
+// Package push provides the agentpackage push command for opampctl.
+package push
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/clientutil"
+	"github.com/minuk-dev/opampcommander/pkg/formatter"
+	"github.com/minuk-dev/opampcommander/pkg/opampctl/config"
+)
+
+// maxUploadErrorBodyBytes bounds how much of an upload error response is echoed back.
+const maxUploadErrorBodyBytes = 1024
+
+var (
+	// ErrFileRequired is returned when --file is missing.
+	ErrFileRequired = errors.New("--file is required")
+	// ErrNameRequired is returned when --name is missing.
+	ErrNameRequired = errors.New("--name is required")
+	// ErrVersionRequired is returned when --version is missing.
+	ErrVersionRequired = errors.New("--version is required")
+	// ErrDownloadURLRequired is returned when neither --download-url nor --upload-url is set,
+	// so there is no location agents could fetch the artifact from.
+	ErrDownloadURLRequired = errors.New("--download-url or --upload-url is required")
+	// ErrUploadFailed is returned when the object store rejects the upload.
+	ErrUploadFailed = errors.New("upload failed")
+)
+
+// agentPackageCreator is the part of the API client push needs.
+type agentPackageCreator interface {
+	CreateAgentPackage(ctx context.Context, namespace string, agentPackage *v1.AgentPackage) (*v1.AgentPackage, error)
+}
+
+// CommandOptions contains the options for the agentpackage push command.
+type CommandOptions struct {
+	*config.GlobalConfig
+
+	// Flags
+	file          string
+	name          string
+	namespace     string
+	version       string
+	packageType   string
+	attributes    map[string]string
+	downloadURL   string
+	headers       map[string]string
+	uploadURL     string
+	uploadHeaders map[string]string
+	formatType    string
+
+	// internal state
+	client     agentPackageCreator
+	httpClient *http.Client
+}
+
+// NewCommand creates a new agentpackage push command.
+func NewCommand(options CommandOptions) *cobra.Command {
+	//exhaustruct:ignore
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "upload a local artifact and register it as an agent package",
+		Long: `Compute the SHA-256 content hash of a local artifact, optionally upload it to an
+object store, and create an AgentPackage pointing at it.
+
+The upload is a single HTTP PUT to --upload-url, which fits pre-signed object store URLs
+(S3, GCS, Azure Blob). Unless --download-url is set, agents download from --upload-url
+with its query string (the pre-signed credentials) removed.`,
+		Example: `  # register an artifact that is already hosted
+  opampctl agentpackage push --file pkg.tar.gz --name foo --version 1.0.0 \
+    --download-url https://artifacts.example.com/foo-1.0.0.tar.gz
+
+  # upload to a pre-signed URL and register it
+  opampctl agentpackage push --file pkg.tar.gz --name foo --version 1.0.0 \
+    --upload-url "https://bucket.s3.amazonaws.com/foo-1.0.0.tar.gz?X-Amz-Signature=..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := options.Prepare(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = options.Run(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.file, "file", "f", "", "Path to the package artifact (required)")
+	cmd.Flags().StringVar(&options.name, "name", "", "Name of the agent package (required)")
+	cmd.Flags().StringVarP(&options.namespace, "namespace", "n", "default", "Namespace of the agent package")
+	cmd.Flags().StringVar(&options.version, "version", "", "Version of the package (required)")
+	cmd.Flags().StringVar(&options.packageType, "package-type", "", "Type of the package (e.g., TopLevel, Addon)")
+	cmd.Flags().StringToStringVar(&options.attributes, "attributes", nil, "Attributes of the agent package (key=value)")
+	cmd.Flags().StringVar(&options.downloadURL, "download-url", "",
+		"URL agents download the package from (defaults to --upload-url without its query string)")
+	cmd.Flags().StringToStringVar(&options.headers, "headers", nil,
+		"HTTP headers agents send when downloading (key=value)")
+	cmd.Flags().StringVar(&options.uploadURL, "upload-url", "", "URL to upload the artifact to with HTTP PUT")
+	cmd.Flags().StringToStringVar(&options.uploadHeaders, "upload-headers", nil,
+		"HTTP headers sent with the upload (key=value)")
+	cmd.Flags().StringVarP(&options.formatType, "output", "o", "text", "Output format (text, json, yaml)")
+
+	return cmd
+}
+
+// Prepare validates the flags and creates the API client.
+func (opt *CommandOptions) Prepare(*cobra.Command, []string) error {
+	err := opt.validate()
+	if err != nil {
+		return err
+	}
+
+	client, err := clientutil.NewClient(opt.GlobalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create authenticated client: %w", err)
+	}
+
+	opt.client = client.AgentPackageService
+	opt.httpClient = http.DefaultClient
+
+	return nil
+}
+
+// Run executes the agentpackage push command.
+func (opt *CommandOptions) Run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	contentHash, err := ComputeFileContentHash(opt.file)
+	if err != nil {
+		return err
+	}
+
+	downloadURL := opt.downloadURL
+
+	if opt.uploadURL != "" {
+		err = opt.upload(ctx)
+		if err != nil {
+			return err
+		}
+
+		if downloadURL == "" {
+			downloadURL, err = stripQuery(opt.uploadURL)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	//exhaustruct:ignore
+	agentPackage, err := opt.client.CreateAgentPackage(ctx, opt.namespace, &v1.AgentPackage{
+		Metadata: v1.AgentPackageMetadata{
+			Name:       opt.name,
+			Namespace:  opt.namespace,
+			Attributes: opt.attributes,
+		},
+		Spec: v1.AgentPackageSpec{
+			PackageType: opt.packageType,
+			Version:     opt.version,
+			DownloadURL: downloadURL,
+			ContentHash: contentHash,
+			Headers:     opt.headers,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create agent package: %w", err)
+	}
+
+	err = formatter.Format(cmd.OutOrStdout(), toFormattedAgentPackage(agentPackage), formatter.FormatType(opt.formatType))
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	return nil
+}
+
+// ComputeContentHash returns the SHA-256 digest of the content read from r, in the raw form
+// OpAMP's DownloadableFile.content_hash carries.
+func ComputeContentHash(r io.Reader) ([]byte, error) {
+	hash := sha256.New()
+
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash content: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// ComputeFileContentHash returns the SHA-256 digest of the file at path.
+func ComputeFileContentHash(path string) ([]byte, error) {
+	file, err := os.Open(path) //nolint:gosec // The path is the artifact the operator asked to push.
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close() //nolint:errcheck // Read-only file.
+
+	return ComputeContentHash(file)
+}
+
+func (opt *CommandOptions) validate() error {
+	switch {
+	case opt.file == "":
+		return ErrFileRequired
+	case opt.name == "":
+		return ErrNameRequired
+	case opt.version == "":
+		return ErrVersionRequired
+	case opt.downloadURL == "" && opt.uploadURL == "":
+		return ErrDownloadURLRequired
+	default:
+		return nil
+	}
+}
+
+// upload streams the artifact to the upload URL. The file is re-opened rather than buffered
+// so that large artifacts are not held in memory.
+func (opt *CommandOptions) upload(ctx context.Context) error {
+	file, err := os.Open(opt.file) //nolint:gosec // The path is the artifact the operator asked to push.
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", opt.file, err)
+	}
+	defer file.Close() //nolint:errcheck // Read-only file.
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", opt.file, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, opt.uploadURL, file)
+	if err != nil {
+		return fmt.Errorf("failed to build upload request: %w", err)
+	}
+
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	for key, value := range opt.uploadHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := opt.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", opt.file, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Nothing to do on close failure.
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxUploadErrorBodyBytes))
+
+		return fmt.Errorf("%w: %s: %s", ErrUploadFailed, resp.Status, body)
+	}
+
+	return nil
+}
+
+func stripQuery(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse upload URL: %w", err)
+	}
+
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+
+	return parsed.String(), nil
+}
+
+//nolint:lll
+type formattedAgentPackage struct {
+	Name        string `json:"name"        short:"name"        text:"name"        yaml:"name"`
+	Namespace   string `json:"namespace"   short:"namespace"   text:"namespace"   yaml:"namespace"`
+	Version     string `json:"version"     short:"version"     text:"version"     yaml:"version"`
+	DownloadURL string `json:"downloadUrl" short:"-"           text:"downloadUrl" yaml:"downloadUrl"`
+	ContentHash string `json:"contentHash" short:"contentHash" text:"contentHash" yaml:"contentHash"`
+}
+
+func toFormattedAgentPackage(agentPackage *v1.AgentPackage) *formattedAgentPackage {
+	return &formattedAgentPackage{
+		Name:        agentPackage.Metadata.Name,
+		Namespace:   agentPackage.Metadata.Namespace,
+		Version:     agentPackage.Spec.Version,
+		DownloadURL: agentPackage.Spec.DownloadURL,
+		ContentHash: hex.EncodeToString(agentPackage.Spec.ContentHash),
+	}
+}
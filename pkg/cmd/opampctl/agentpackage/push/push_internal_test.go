@@ -0,0 +1,224 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+)
+
+// sha256("hello world").
+const helloWorldSHA256 = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+type fakeAgentPackageCreator struct {
+	namespace string
+	created   *v1.AgentPackage
+}
+
+func (f *fakeAgentPackageCreator) CreateAgentPackage(
+	_ context.Context,
+	namespace string,
+	agentPackage *v1.AgentPackage,
+) (*v1.AgentPackage, error) {
+	f.namespace = namespace
+	f.created = agentPackage
+
+	return agentPackage, nil
+}
+
+func writeArtifact(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pkg.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func newTestCommand(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetContext(t.Context())
+
+	return cmd, &out
+}
+
+func TestComputeContentHash(t *testing.T) {
+	t.Parallel()
+
+	hash, err := ComputeContentHash(strings.NewReader("hello world"))
+	require.NoError(t, err)
+	assert.Equal(t, helloWorldSHA256, hex.EncodeToString(hash))
+
+	fileHash, err := ComputeFileContentHash(writeArtifact(t, "hello world"))
+	require.NoError(t, err)
+	assert.Equal(t, hash, fileHash)
+
+	_, err = ComputeFileContentHash(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestRun_CreatesAgentPackageWithDownloadURL(t *testing.T) {
+	t.Parallel()
+
+	creator := &fakeAgentPackageCreator{}
+	opt := &CommandOptions{
+		file:        writeArtifact(t, "hello world"),
+		name:        "foo",
+		namespace:   "team-a",
+		version:     "1.0.0",
+		downloadURL: "https://artifacts.example.com/foo-1.0.0.tar.gz",
+		headers:     map[string]string{"Authorization": "Bearer token"},
+		formatType:  "json",
+		client:      creator,
+		httpClient:  http.DefaultClient,
+	}
+	require.NoError(t, opt.validate())
+
+	cmd, out := newTestCommand(t)
+	require.NoError(t, opt.Run(cmd, nil))
+
+	require.NotNil(t, creator.created)
+	assert.Equal(t, "team-a", creator.namespace)
+	assert.Equal(t, "foo", creator.created.Metadata.Name)
+	assert.Equal(t, "1.0.0", creator.created.Spec.Version)
+	assert.Equal(t, "https://artifacts.example.com/foo-1.0.0.tar.gz", creator.created.Spec.DownloadURL)
+	assert.Equal(t, helloWorldSHA256, hex.EncodeToString(creator.created.Spec.ContentHash))
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, creator.created.Spec.Headers)
+	assert.Contains(t, out.String(), helloWorldSHA256)
+}
+
+func TestRun_UploadsBeforeCreating(t *testing.T) {
+	t.Parallel()
+
+	var (
+		uploaded    []byte
+		uploadToken string
+	)
+
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		uploadToken = r.Header.Get("X-Upload-Token")
+		uploaded, _ = io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(store.Close)
+
+	creator := &fakeAgentPackageCreator{}
+	opt := &CommandOptions{
+		file:          writeArtifact(t, "hello world"),
+		name:          "foo",
+		namespace:     "default",
+		version:       "1.0.0",
+		uploadURL:     store.URL + "/bucket/foo-1.0.0.tar.gz?signature=secret",
+		uploadHeaders: map[string]string{"X-Upload-Token": "token"},
+		formatType:    "text",
+		client:        creator,
+		httpClient:    store.Client(),
+	}
+	require.NoError(t, opt.validate())
+
+	cmd, _ := newTestCommand(t)
+	require.NoError(t, opt.Run(cmd, nil))
+
+	assert.Equal(t, "hello world", string(uploaded))
+	assert.Equal(t, "token", uploadToken)
+	require.NotNil(t, creator.created)
+	// The pre-signed query string must not leak into the URL agents are given.
+	assert.Equal(t, store.URL+"/bucket/foo-1.0.0.tar.gz", creator.created.Spec.DownloadURL)
+}
+
+func TestRun_UploadFailureDoesNotCreate(t *testing.T) {
+	t.Parallel()
+
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "access denied", http.StatusForbidden)
+	}))
+	t.Cleanup(store.Close)
+
+	creator := &fakeAgentPackageCreator{}
+	opt := &CommandOptions{
+		file:       writeArtifact(t, "hello world"),
+		name:       "foo",
+		namespace:  "default",
+		version:    "1.0.0",
+		uploadURL:  store.URL + "/bucket/foo.tar.gz",
+		formatType: "text",
+		client:     creator,
+		httpClient: store.Client(),
+	}
+
+	cmd, _ := newTestCommand(t)
+	err := opt.Run(cmd, nil)
+
+	require.ErrorIs(t, err, ErrUploadFailed)
+	assert.Contains(t, err.Error(), "access denied")
+	assert.Nil(t, creator.created)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opt     CommandOptions
+		wantErr error
+	}{
+		{
+			name:    "missing file",
+			opt:     CommandOptions{name: "foo", version: "1.0.0", downloadURL: "https://example.com"},
+			wantErr: ErrFileRequired,
+		},
+		{
+			name:    "missing name",
+			opt:     CommandOptions{file: "pkg.tar.gz", version: "1.0.0", downloadURL: "https://example.com"},
+			wantErr: ErrNameRequired,
+		},
+		{
+			name:    "missing version",
+			opt:     CommandOptions{file: "pkg.tar.gz", name: "foo", downloadURL: "https://example.com"},
+			wantErr: ErrVersionRequired,
+		},
+		{
+			name:    "no location",
+			opt:     CommandOptions{file: "pkg.tar.gz", name: "foo", version: "1.0.0"},
+			wantErr: ErrDownloadURLRequired,
+		},
+		{
+			name:    "upload only",
+			opt:     CommandOptions{file: "pkg.tar.gz", name: "foo", version: "1.0.0", uploadURL: "https://example.com"},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.opt.validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
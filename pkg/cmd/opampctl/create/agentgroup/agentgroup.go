@@ -33,6 +33,9 @@ type CommandOptions struct {
 	priority                        int
 	identifyingAttributesSelector   map[string]string
 	nonIdentifyingAttributeSelector map[string]string
+	absentIdentifyingAttributes     []string
+	absentNonIdentifyingAttributes  []string
+	caseInsensitiveSelector         bool
 	formatType                      string
 	agentConfigFile                 string
 	file                            string
@@ -75,6 +78,12 @@ func NewCommand(options CommandOptions) *cobra.Command {
 		nil, "NonIdentifying attributes selector for the agent group (key=value)")
 	cmd.Flags().StringToStringVar(&options.nonIdentifyingAttributeSelector, "ns",
 		nil, "same as --non-identifying-attributes-selector")
+	cmd.Flags().StringSliceVar(&options.absentIdentifyingAttributes, "absent-identifying-attributes",
+		nil, "Identifying attribute keys agents in the group must not report")
+	cmd.Flags().StringSliceVar(&options.absentNonIdentifyingAttributes, "absent-non-identifying-attributes",
+		nil, "NonIdentifying attribute keys agents in the group must not report")
+	cmd.Flags().BoolVar(&options.caseInsensitiveSelector, "case-insensitive-selector", false,
+		"Match selector values ignoring case and surrounding whitespace")
 	cmd.Flags().StringVarP(&options.formatType, "output", "o", "text", "Output format (text, json, yaml)")
 	cmd.Flags().StringVar(&options.agentConfigFile, "agent-config", "", "Path to agent config file")
 	cmd.Flags().StringVarP(&options.file, "file", "f", "",
@@ -151,8 +160,11 @@ func (opt *CommandOptions) buildRequest() (*v1.AgentGroup, string, error) {
 		Spec: v1.Spec{
 			Priority: opt.priority,
 			Selector: v1.AgentSelector{
-				IdentifyingAttributes:    opt.identifyingAttributesSelector,
-				NonIdentifyingAttributes: opt.nonIdentifyingAttributeSelector,
+				IdentifyingAttributes:          opt.identifyingAttributesSelector,
+				NonIdentifyingAttributes:       opt.nonIdentifyingAttributeSelector,
+				AbsentIdentifyingAttributes:    opt.absentIdentifyingAttributes,
+				AbsentNonIdentifyingAttributes: opt.absentNonIdentifyingAttributes,
+				CaseInsensitive:                opt.caseInsensitiveSelector,
 			},
 			AgentConfig: agentConfig,
 		},
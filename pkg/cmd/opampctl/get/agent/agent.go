@@ -137,6 +137,7 @@ type ItemForCLI struct {
 	Healthy        bool      `short:"Healthy"          text:"Healthy"`
 	SequenceNum    uint64    `short:"Sequence Num"     text:"Sequence Num"`
 	StartedAt      string    `short:"Started At"       text:"Started At"`
+	FirstSeenAt    string    `short:"First Seen At"    text:"First Seen At"`
 	LastReportedAt string    `short:"Last Reported At" text:"Last Reported At"`
 }
 
@@ -403,6 +404,7 @@ func toShortItemForCLI(agent v1.Agent) ItemForCLI {
 		Healthy:        agent.Status.ComponentHealth.Healthy,
 		SequenceNum:    agent.Status.SequenceNum,
 		StartedAt:      startedAt,
+		FirstSeenAt:    agent.Status.FirstSeenAt,
 		LastReportedAt: agent.Status.LastReportedAt,
 	}
 }
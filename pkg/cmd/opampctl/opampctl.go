@@ -10,6 +10,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agent"
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agentgroup"
+	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/agentpackage"
 	configCmd "github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/config"
 	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/context"
 	"github.com/minuk-dev/opampcommander/pkg/cmd/opampctl/create"
@@ -51,6 +54,9 @@ func NewCommand(options CommandOption) *cobra.Command {
 	cmd.AddCommand(deletecmd.NewCommand(deletecmd.CommandOptions{GlobalConfig: options.globalConfig}))
 	cmd.AddCommand(create.NewCommand(create.CommandOptions{GlobalConfig: options.globalConfig}))
 	cmd.AddCommand(template.NewCommand(template.CommandOptions{GlobalConfig: options.globalConfig}))
+	cmd.AddCommand(agent.NewCommand(agent.CommandOptions{GlobalConfig: options.globalConfig}))
+	cmd.AddCommand(agentgroup.NewCommand(agentgroup.CommandOptions{GlobalConfig: options.globalConfig}))
+	cmd.AddCommand(agentpackage.NewCommand(agentpackage.CommandOptions{GlobalConfig: options.globalConfig}))
 	cmd.AddCommand(restart.NewCommand(restart.CommandOptions{GlobalConfig: options.globalConfig}))
 	cmd.AddCommand(reconcile.NewCommand(reconcile.CommandOptions{GlobalConfig: options.globalConfig}))
 	cmd.AddCommand(configCmd.NewCommand(configCmd.CommandOptions{GlobalConfig: options.globalConfig}))
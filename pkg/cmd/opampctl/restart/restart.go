@@ -2,8 +2,14 @@
 package restart
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -20,6 +26,14 @@ const (
 	MaxCompletionResults = 20
 )
 
+var (
+	// ErrRestartFailed is returned when at least one of the selected agents could not be restarted.
+	ErrRestartFailed = errors.New("failed to restart some agents")
+	// ErrEmptySelector is returned when --selector is given without any key=value pair,
+	// which would otherwise select every agent in the namespace.
+	ErrEmptySelector = errors.New("--selector must have at least one key=value pair")
+)
+
 // CommandOptions contains the options for the restart command.
 type CommandOptions struct {
 	GlobalConfig *config.GlobalConfig
@@ -38,49 +52,224 @@ func NewCommand(options CommandOptions) *cobra.Command {
 	return cmd
 }
 
+// agentRestarter is the part of the API client the restart agent command needs.
+type agentRestarter interface {
+	ListAgents(ctx context.Context, namespace string, opts ...client.ListOption) (*client.AgentListResponse, error)
+	RestartAgent(ctx context.Context, namespace string, id uuid.UUID) (*v1.Agent, error)
+}
+
+// restartAgentOptions contains the options for the restart agent command.
+type restartAgentOptions struct {
+	*config.GlobalConfig
+
+	// flags
+	agentID   string
+	namespace string
+	selector  map[string]string
+	yes       bool
+
+	// internal
+	client agentRestarter
+}
+
 // newRestartAgentCommand creates a new restart agent command.
 func newRestartAgentCommand(options CommandOptions) *cobra.Command {
-	var (
-		agentID   string
-		namespace string
-	)
+	//exhaustruct:ignore
+	opt := &restartAgentOptions{GlobalConfig: options.GlobalConfig}
 
 	//exhaustruct:ignore
 	cmd := &cobra.Command{
 		Use:   "agent",
 		Short: "restart agent",
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			ctx := context.Background()
-
-			client, err := clientutil.NewClient(options.GlobalConfig)
+		Long: "Restart a single agent by --id, or every agent matching --selector.\n" +
+			"With --selector the matching agents are listed and a confirmation is asked first, unless --yes is set.",
+		Example: "  opampctl restart agent --id 0196e2d5-4e62-7c3c-b4e5-9b3f3b0c7a1e\n" +
+			"  opampctl restart agent --selector service.name=web --yes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := opt.Prepare(cmd, args)
 			if err != nil {
-				return fmt.Errorf("failed to create API client: %w", err)
+				return err
 			}
 
-			agentUUID, err := uuid.Parse(agentID)
-			if err != nil {
-				return fmt.Errorf("invalid agent ID format: %w", err)
-			}
+			return opt.Run(cmd, args)
+		},
+	}
 
-			_, err = client.AgentService.RestartAgent(ctx, namespace, agentUUID)
-			if err != nil {
-				return fmt.Errorf("failed to restart agent: %w", err)
-			}
+	cmd.Flags().StringVar(&opt.agentID, "id", "", "agent ID to restart")
+	cmd.Flags().StringVarP(&opt.namespace, "namespace", "n", "default", "Namespace of the agent")
+	cmd.Flags().StringToStringVarP(&opt.selector, "selector", "l", nil,
+		"Restart every agent whose identifying attributes match (exact match), e.g. -l service.name=web")
+	cmd.Flags().BoolVarP(&opt.yes, "yes", "y", false, "Restart the agents matching --selector without asking")
+	cmd.MarkFlagsOneRequired("id", "selector")
+	cmd.MarkFlagsMutuallyExclusive("id", "selector")
+
+	_ = cmd.RegisterFlagCompletionFunc("id",
+		restartAgentIDCompletion(options, &opt.namespace))
+
+	return cmd
+}
+
+// Prepare creates the API client.
+func (opt *restartAgentOptions) Prepare(*cobra.Command, []string) error {
+	cli, err := clientutil.NewClient(opt.GlobalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	opt.client = cli.AgentService
+
+	return nil
+}
+
+// Run restarts the agent given by --id, or the agents matching --selector.
+func (opt *restartAgentOptions) Run(cmd *cobra.Command, _ []string) error {
+	if opt.agentID != "" {
+		return opt.restartByID(cmd)
+	}
+
+	if len(opt.selector) == 0 {
+		return ErrEmptySelector
+	}
+
+	return opt.restartBySelector(cmd)
+}
+
+func (opt *restartAgentOptions) restartByID(cmd *cobra.Command) error {
+	agentUUID, err := uuid.Parse(opt.agentID)
+	if err != nil {
+		return fmt.Errorf("invalid agent ID format: %w", err)
+	}
+
+	_, err = opt.client.RestartAgent(cmd.Context(), opt.namespace, agentUUID)
+	if err != nil {
+		return fmt.Errorf("failed to restart agent: %w", err)
+	}
+
+	cmd.Printf("Agent %s restarted successfully\n", opt.agentID)
+
+	return nil
+}
+
+func (opt *restartAgentOptions) restartBySelector(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+	selector := formatSelector(opt.selector)
+
+	agents, err := opt.listMatchingAgents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(agents) == 0 {
+		cmd.Printf("No agents in namespace %s match selector %s\n", opt.namespace, selector)
+
+		return nil
+	}
+
+	cmd.Printf("%d agent(s) in namespace %s match selector %s:\n", len(agents), opt.namespace, selector)
+
+	for _, agent := range agents {
+		cmd.Printf("  %s\n", agent.Metadata.InstanceUID)
+	}
+
+	if !opt.yes {
+		confirmed, err := confirm(cmd, fmt.Sprintf("Restart %d agent(s)? [y/N]: ", len(agents)))
+		if err != nil {
+			return err
+		}
 
-			cmd.Printf("Agent %s restarted successfully\n", agentID)
+		if !confirmed {
+			cmd.Println("Restart cancelled")
 
 			return nil
-		},
+		}
 	}
 
-	cmd.Flags().StringVar(&agentID, "id", "", "agent ID to restart")
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the agent")
-	_ = cmd.MarkFlagRequired("id")
+	failed := 0
 
-	_ = cmd.RegisterFlagCompletionFunc("id",
-		restartAgentIDCompletion(options, &namespace))
+	for _, agent := range agents {
+		instanceUID := agent.Metadata.InstanceUID
 
-	return cmd
+		_, err := opt.client.RestartAgent(ctx, opt.namespace, instanceUID)
+		if err != nil {
+			failed++
+
+			cmd.PrintErrf("  %s: failed: %v\n", instanceUID, err)
+
+			continue
+		}
+
+		cmd.Printf("  %s: restarted\n", instanceUID)
+	}
+
+	cmd.Printf("Restarted %d of %d agent(s)\n", len(agents)-failed, len(agents))
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d failed", ErrRestartFailed, failed, len(agents))
+	}
+
+	return nil
+}
+
+// listMatchingAgents lists every agent matching the selector, page by page. Disconnected
+// agents are included: the restart is recorded on the agent and sent once it reconnects.
+func (opt *restartAgentOptions) listMatchingAgents(ctx context.Context) ([]v1.Agent, error) {
+	var (
+		agents        []v1.Agent
+		continueToken string
+	)
+
+	for {
+		opts := []client.ListOption{
+			client.WithLimit(clientutil.ChunkSize),
+			client.WithSelector(opt.selector),
+		}
+		if continueToken != "" {
+			opts = append(opts, client.WithContinueToken(continueToken))
+		}
+
+		resp, err := opt.client.ListAgents(ctx, opt.namespace, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list agents: %w", err)
+		}
+
+		agents = append(agents, resp.Items...)
+
+		continueToken = resp.Metadata.Continue
+		if continueToken == "" || len(resp.Items) == 0 {
+			return agents, nil
+		}
+	}
+}
+
+// confirm prints prompt and reports whether the answer read from the command's input is yes.
+// An empty answer or end of input counts as no.
+func confirm(cmd *cobra.Command, prompt string) (bool, error) {
+	cmd.Print(prompt)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		if errors.Is(err, io.EOF) {
+			cmd.Println()
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// formatSelector renders the selector as sorted key=value pairs, as it was given on the command line.
+func formatSelector(selector map[string]string) string {
+	return strings.Join(lo.Map(slices.Sorted(maps.Keys(selector)), func(key string, _ int) string {
+		return key + "=" + selector[key]
+	}), ",")
 }
 
 func restartAgentIDCompletion(
@@ -0,0 +1,167 @@
+package restart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minuk-dev/opampcommander/api/v1"
+	"github.com/minuk-dev/opampcommander/pkg/client"
+)
+
+var errRestartRejected = errors.New("restart rejected")
+
+type fakeAgentRestarter struct {
+	agents    []v1.Agent
+	failing   map[uuid.UUID]bool
+	restarted []uuid.UUID
+}
+
+func (f *fakeAgentRestarter) ListAgents(
+	_ context.Context, _ string, _ ...client.ListOption,
+) (*client.AgentListResponse, error) {
+	//exhaustruct:ignore
+	return &client.AgentListResponse{Items: f.agents}, nil
+}
+
+func (f *fakeAgentRestarter) RestartAgent(_ context.Context, _ string, id uuid.UUID) (*v1.Agent, error) {
+	if f.failing[id] {
+		return nil, errRestartRejected
+	}
+
+	f.restarted = append(f.restarted, id)
+
+	return &v1.Agent{}, nil
+}
+
+func newFakeAgentRestarter(count int) *fakeAgentRestarter {
+	fake := &fakeAgentRestarter{failing: map[uuid.UUID]bool{}}
+
+	for range count {
+		//exhaustruct:ignore
+		fake.agents = append(fake.agents, v1.Agent{Metadata: v1.AgentMetadata{InstanceUID: uuid.New()}})
+	}
+
+	return fake
+}
+
+func newTestCommand(t *testing.T, stdin string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetContext(t.Context())
+
+	return cmd, &out
+}
+
+func TestRestartAgentBySelector_Confirmation(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		stdin     string
+		restarted bool
+	}{
+		{name: "yes restarts", stdin: "y\n", restarted: true},
+		{name: "full yes restarts", stdin: "YES\n", restarted: true},
+		{name: "no cancels", stdin: "n\n", restarted: false},
+		{name: "empty answer cancels", stdin: "\n", restarted: false},
+		{name: "closed stdin cancels", stdin: "", restarted: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := newFakeAgentRestarter(2)
+			//exhaustruct:ignore
+			opt := &restartAgentOptions{
+				namespace: "default",
+				selector:  map[string]string{"service.name": "web"},
+				client:    fake,
+			}
+
+			cmd, out := newTestCommand(t, tc.stdin)
+			require.NoError(t, opt.Run(cmd, nil))
+
+			assert.Contains(t, out.String(), "2 agent(s) in namespace default match selector service.name=web")
+			assert.Contains(t, out.String(), "Restart 2 agent(s)? [y/N]: ")
+
+			if tc.restarted {
+				assert.Len(t, fake.restarted, 2)
+				assert.Contains(t, out.String(), "Restarted 2 of 2 agent(s)")
+			} else {
+				assert.Empty(t, fake.restarted)
+				assert.Contains(t, out.String(), "Restart cancelled")
+			}
+		})
+	}
+}
+
+func TestRestartAgentBySelector_Yes(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeAgentRestarter(3)
+	fake.failing[fake.agents[1].Metadata.InstanceUID] = true
+	//exhaustruct:ignore
+	opt := &restartAgentOptions{
+		namespace: "default",
+		selector:  map[string]string{"service.name": "web"},
+		yes:       true,
+		client:    fake,
+	}
+
+	cmd, out := newTestCommand(t, "")
+	err := opt.Run(cmd, nil)
+
+	require.ErrorIs(t, err, ErrRestartFailed)
+	assert.NotContains(t, out.String(), "[y/N]", "--yes skips the prompt")
+	assert.Equal(t, []uuid.UUID{fake.agents[0].Metadata.InstanceUID, fake.agents[2].Metadata.InstanceUID},
+		fake.restarted)
+	assert.Contains(t, out.String(), fake.agents[0].Metadata.InstanceUID.String()+": restarted")
+	assert.Contains(t, out.String(), fake.agents[1].Metadata.InstanceUID.String()+": failed: restart rejected")
+	assert.Contains(t, out.String(), "Restarted 2 of 3 agent(s)")
+}
+
+func TestRestartAgentBySelector_NoMatches(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeAgentRestarter(0)
+	//exhaustruct:ignore
+	opt := &restartAgentOptions{
+		namespace: "default",
+		selector:  map[string]string{"service.name": "web"},
+		client:    fake,
+	}
+
+	cmd, out := newTestCommand(t, "y\n")
+	require.NoError(t, opt.Run(cmd, nil))
+
+	assert.Contains(t, out.String(), "No agents in namespace default match selector service.name=web")
+	assert.NotContains(t, out.String(), "[y/N]")
+}
+
+func TestRestartAgent_EmptySelector(t *testing.T) {
+	t.Parallel()
+
+	//exhaustruct:ignore
+	opt := &restartAgentOptions{
+		namespace: "default",
+		selector:  map[string]string{},
+		yes:       true,
+		client:    newFakeAgentRestarter(1),
+	}
+
+	cmd, _ := newTestCommand(t, "")
+	require.ErrorIs(t, opt.Run(cmd, nil), ErrEmptySelector)
+}
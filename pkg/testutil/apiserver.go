@@ -188,6 +188,19 @@ func buildServerSettings(
 			DefaultNamespace: agentmodel.DefaultNamespaceName,
 			DefaultRole:      usermodel.RoleDefault,
 		},
+		AgentGroupSettings: config.AgentGroupSettings{
+			RemoteConfigKeyDelimiter: agentmodel.DefaultRemoteConfigKeyDelimiter,
+		},
+		AgentAttributeSettings: config.AgentAttributeSettings{
+			Aliases:       nil,
+			MaxCount:      0,
+			MaxTotalBytes: 0,
+			Denylist:      nil,
+		},
+		AgentQuarantineSettings: config.AgentQuarantineSettings{
+			UnhealthyThreshold: 0,
+			EvaluationInterval: 0,
+		},
 		RBACModelPath: "",
 	}
 }
@@ -229,8 +242,9 @@ func (b *Base) StartAPIServerWithKafka(mongoURI, kafkaBroker, databaseName strin
 	settings.EventSettings = config.EventSettings{
 		ProtocolType: config.EventProtocolTypeKafka,
 		KafkaSettings: config.KafkaSettings{
-			Brokers: []string{kafkaBroker},
-			Topic:   kafkaEventTopic,
+			Brokers:     []string{kafkaBroker},
+			Topic:       kafkaEventTopic,
+			KeyStrategy: "instanceUID",
 		},
 	}
 
@@ -0,0 +1,34 @@
+//nolint:testpackage // white-box test that sets the variables goreleaser injects through ldflags
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:paralleltest // swaps the package-level ldflag variables
+func TestGet_ReportsTheInjectedBuildInfo(t *testing.T) {
+	saved := []string{gitMajor, gitMinor, gitVersion, gitCommit, gitTreeState, buildDate}
+
+	t.Cleanup(func() {
+		gitMajor, gitMinor, gitVersion, gitCommit, gitTreeState, buildDate =
+			saved[0], saved[1], saved[2], saved[3], saved[4], saved[5]
+	})
+
+	gitMajor = "1"
+	gitMinor = "2"
+	gitVersion = "v1.2.3"
+	gitCommit = "0123456789abcdef0123456789abcdef01234567"
+	gitTreeState = "clean"
+	buildDate = "2026-01-02T03:04:05Z"
+
+	info := Get()
+
+	assert.Equal(t, "1", info.Major)
+	assert.Equal(t, "2", info.Minor)
+	assert.Equal(t, "v1.2.3", info.GitVersion)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", info.GitCommit)
+	assert.Equal(t, "clean", info.GitTreeState)
+	assert.Equal(t, "2026-01-02T03:04:05Z", info.BuildDate)
+}
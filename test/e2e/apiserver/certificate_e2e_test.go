@@ -270,8 +270,26 @@ func TestE2E_Certificate_MultipleCertificates(t *testing.T) {
 	}
 	assert.Equal(t, len(certNames), foundCount, "All created certificates should be listed")
 
+	// Deleting by selector removes only the matching certificate
+	deleted, err := opampClient.CertificateService.DeleteCertificatesBySelector(ctx, "default",
+		map[string]string{"environment": "dev"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted.DeletedCount)
+
+	certs, err = opampClient.CertificateService.ListCertificates(ctx, "default")
+	require.NoError(t, err)
+
+	remaining := make([]string, 0, len(certs.Items))
+	for _, c := range certs.Items {
+		remaining = append(remaining, c.Metadata.Name)
+	}
+
+	assert.Contains(t, remaining, "cert-staging")
+	assert.Contains(t, remaining, "cert-prod")
+	assert.NotContains(t, remaining, "cert-dev")
+
 	// Cleanup
-	for _, name := range certNames {
+	for _, name := range []string{"cert-staging", "cert-prod"} {
 		err := opampClient.CertificateService.DeleteCertificate(ctx, "default", name)
 		require.NoError(t, err, "Failed to delete certificate: %s", name)
 	}
@@ -189,6 +189,24 @@ func (m *mockAgentUsecase) SearchAgents(
 	return nil, errNotImplemented
 }
 
+func (m *mockAgentUsecase) ListAgentsByPackage(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ string,
+	_ *model.ListOptions,
+) (*model.ListResponse[*agentmodel.Agent], error) {
+	return nil, errNotImplemented
+}
+
+func (m *mockAgentUsecase) ListAgentAttributes(_ context.Context, _ int) (*agentmodel.AttributeCatalog, error) {
+	return nil, errNotImplemented
+}
+
+func (m *mockAgentUsecase) CheckNewInstanceUIDAvailable(_ context.Context, _ uuid.UUID, _ uuid.UUID) error {
+	return nil
+}
+
 type mockAgentNotificationUsecase struct {
 	notificationCalled bool
 }